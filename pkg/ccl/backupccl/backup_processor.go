@@ -79,6 +79,24 @@ var (
 		"split backup data on timestamps when writing revision history",
 		true,
 	)
+
+	exportRequestTargetBytes = settings.RegisterByteSizeSetting(
+		settings.TenantWritable,
+		"bulkio.backup.export_request_target_bytes",
+		"target size for the data returned by each export request issued by BACKUP; "+
+			"a request returns at least one SST, and returns less data if it yields to "+
+			"foreground traffic in admission control",
+		1,
+		settings.PositiveInt,
+	)
+
+	skipUnchangedRanges = settings.RegisterBoolSetting(
+		settings.TenantWritable,
+		"bulkio.backup.skip_unchanged_ranges.enabled",
+		"allow incremental backups to skip exporting ranges which have not been "+
+			"written to since the previous backup",
+		false,
+	)
 )
 
 const backupProcessorName = "backupDataProcessor"
@@ -351,6 +369,7 @@ func runBackupProcessor(
 							TargetFileSize:                      batcheval.ExportRequestTargetFileSize.Get(&clusterSettings.SV),
 							ReturnSST:                           true,
 							SplitMidKey:                         splitMidKey,
+							SkipIfUnchanged:                     skipUnchangedRanges.Get(&clusterSettings.SV),
 						}
 
 						// If we're doing re-attempts but are not yet in the priority regime,
@@ -389,10 +408,13 @@ func runBackupProcessor(
 							header.WaitPolicy = lock.WaitPolicy_Error
 						}
 
-						// We set the DistSender response target bytes field to a sentinel
-						// value. The sentinel value of 1 forces the ExportRequest to paginate
-						// after creating a single SST.
-						header.TargetBytes = 1
+						// We set the DistSender response target bytes field so that the
+						// ExportRequest paginates once it has created SSTs of at least this
+						// size. The default of 1 forces it to paginate after creating a
+						// single SST. Since the request is paginated, it may also yield
+						// early if it runs for longer than admission control allows, which
+						// is signaled by RESUME_ELASTIC_LIMIT.
+						header.TargetBytes = exportRequestTargetBytes.Get(&clusterSettings.SV)
 						admissionHeader := roachpb.AdmissionHeader{
 							// Export requests are currently assigned NormalPri.
 							//
@@ -484,7 +506,7 @@ func runBackupProcessor(
 							completedSpans = 1
 						}
 
-						if len(resp.Files) > 1 {
+						if len(resp.Files) > 1 && header.TargetBytes == 1 {
 							log.Warning(ctx, "unexpected multi-file response using header.TargetBytes = 1")
 						}
 
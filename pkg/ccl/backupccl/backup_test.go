@@ -4960,6 +4960,38 @@ func TestBackupRestoreIncrementalTrucateTable(t *testing.T) {
 	sqlDB.Exec(t, "BACKUP DATABASE data TO $1 INCREMENTAL FROM $2", inc, full)
 }
 
+// TestBackupRestoreIncrementalSkipUnchangedAfterPush verifies that incremental
+// backups that skip unchanged ranges still export a write whose transaction was
+// pushed above the timestamp it first wrote at.
+func TestBackupRestoreIncrementalSkipUnchangedAfterPush(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 1
+	tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+	sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.backup.skip_unchanged_ranges.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE data.t (k INT PRIMARY KEY, v INT)`)
+	sqlDB.Exec(t, `INSERT INTO data.t VALUES (1, 1)`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+
+	// Lay down an intent with a low priority transaction, then push it above
+	// the timestamp it was written at with a high priority read.
+	txn, err := tc.Conns[0].Begin()
+	require.NoError(t, err)
+	_, err = txn.Exec(`SET TRANSACTION PRIORITY LOW`)
+	require.NoError(t, err)
+	_, err = txn.Exec(`INSERT INTO data.t VALUES (2, 2)`)
+	require.NoError(t, err)
+	sqlDB.Exec(t, `BEGIN PRIORITY HIGH; SELECT * FROM data.t WHERE k = 2; COMMIT`)
+	require.NoError(t, txn.Commit())
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	sqlDB.Exec(t, `CREATE DATABASE restored`)
+	sqlDB.Exec(t, `RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'restored'`, localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT k, v FROM restored.t ORDER BY k`, [][]string{{"1", "1"}, {"2", "2"}})
+}
+
 func TestBackupRestoreIncrementalDropTable(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -643,6 +643,7 @@ func TestClusterRestoreFailCleanup(t *testing.T) {
 				{"database_role_settings"},
 				{"external_connections"},
 				{"locations"},
+				{"plan_pins"},
 				{"privileges"},
 				{"role_id_seq"},
 				{"role_members"},
@@ -736,6 +737,7 @@ func TestClusterRestoreFailCleanup(t *testing.T) {
 				{"database_role_settings"},
 				{"external_connections"},
 				{"locations"},
+				{"plan_pins"},
 				{"privileges"},
 				{"role_id_seq"},
 				{"role_members"},
@@ -177,9 +177,16 @@ func allocateDescriptorRewrites(
 			fk := &table.OutboundFKs[i]
 			if _, ok := tablesByID[fk.ReferencedTableID]; !ok {
 				if !opts.SkipMissingFKs {
-					return nil, errors.Errorf(
-						"cannot restore table %q without referenced table %d (or %q option)",
-						table.Name, fk.ReferencedTableID, restoreOptSkipMissingFKs,
+					return nil, errors.WithHintf(
+						errors.Errorf(
+							"cannot restore table %q without referenced table %d (or %q option)",
+							table.Name, fk.ReferencedTableID, restoreOptSkipMissingFKs,
+						),
+						"foreign key constraint %q references a table that is not being restored; "+
+							"include the referenced table in the RESTORE, or use the %q option to "+
+							"restore the table without the constraint and add it back with "+
+							"ALTER TABLE ... ADD CONSTRAINT ... NOT VALID once the referenced table exists",
+						fk.Name, restoreOptSkipMissingFKs,
 					)
 				}
 			}
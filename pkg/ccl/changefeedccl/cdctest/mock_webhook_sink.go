@@ -9,6 +9,7 @@
 package cdctest
 
 import (
+	"compress/gzip"
 	"crypto/tls"
 	"io"
 	"net/http"
@@ -29,6 +30,7 @@ type MockWebhookSink struct {
 		statusCodes      []int
 		statusCodesIndex int
 		rows             []string
+		headers          []http.Header
 		notify           chan struct{}
 	}
 }
@@ -133,6 +135,17 @@ func (s *MockWebhookSink) Latest() string {
 	return latest
 }
 
+// LatestHeaders returns the headers of the most recent message received by the
+// MockWebhookSink.
+func (s *MockWebhookSink) LatestHeaders() http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.mu.headers) == 0 {
+		return nil
+	}
+	return s.mu.headers[len(s.mu.headers)-1]
+}
+
 // Pop deletes and returns the oldest message from MockWebhookSink
 func (s *MockWebhookSink) Pop() string {
 	s.mu.Lock()
@@ -140,6 +153,7 @@ func (s *MockWebhookSink) Pop() string {
 	if len(s.mu.rows) > 0 {
 		oldest := s.mu.rows[0]
 		s.mu.rows = s.mu.rows[1:]
+		s.mu.headers = s.mu.headers[1:]
 		return oldest
 	}
 	return ""
@@ -183,7 +197,16 @@ func (s *MockWebhookSink) requestHandler(hw http.ResponseWriter, hr *http.Reques
 
 func (s *MockWebhookSink) publish(hw http.ResponseWriter, hr *http.Request) error {
 	defer hr.Body.Close()
-	row, err := io.ReadAll(hr.Body)
+	body := io.Reader(hr.Body)
+	if hr.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(hr.Body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	row, err := io.ReadAll(body)
 	if err != nil {
 		return err
 	}
@@ -191,6 +214,7 @@ func (s *MockWebhookSink) publish(hw http.ResponseWriter, hr *http.Request) erro
 	s.mu.numCalls++
 	if s.mu.statusCodes[s.mu.statusCodesIndex] >= http.StatusOK && s.mu.statusCodes[s.mu.statusCodesIndex] < http.StatusMultipleChoices {
 		s.mu.rows = append(s.mu.rows, string(row))
+		s.mu.headers = append(s.mu.headers, hr.Header.Clone())
 		if s.mu.notify != nil {
 			close(s.mu.notify)
 			s.mu.notify = nil
@@ -234,6 +234,13 @@ func startDistChangefeed(
 	if err != nil {
 		return err
 	}
+	var partitionSpans []execinfrapb.ChangeFrontierSpec_PartitionSpans
+	if changefeedbase.MakeStatementOptions(details.Opts).ResolvedPerPartition() {
+		partitionSpans, err = fetchPartitionSpans(execCfg.Codec, tableDescs)
+		if err != nil {
+			return err
+		}
+	}
 	cfKnobs := execCfg.DistSQLSrv.TestingKnobs.Changefeed
 
 	// Changefeed flows handle transactional consistency themselves.
@@ -242,7 +249,7 @@ func startDistChangefeed(
 	dsp := execCtx.DistSQLPlanner()
 	evalCtx := execCtx.ExtendedEvalContext()
 
-	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans, selectClause)(ctx, dsp)
+	p, planCtx, err := makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans, partitionSpans, selectClause)(ctx, dsp)
 	if err != nil {
 		return err
 	}
@@ -258,7 +265,7 @@ func startDistChangefeed(
 
 	replanner, stopReplanner := sql.PhysicalPlanChangeChecker(ctx,
 		p,
-		makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans, selectClause),
+		makePlan(execCtx, jobID, details, initialHighWater, checkpoint, trackedSpans, partitionSpans, selectClause),
 		execCtx,
 		replanOracle,
 		func() time.Duration { return replanChangefeedFrequency.Get(execCtx.ExecCfg().SV()) },
@@ -314,6 +321,7 @@ func makePlan(
 	initialHighWater hlc.Timestamp,
 	checkpoint jobspb.ChangefeedProgress_Checkpoint,
 	trackedSpans []roachpb.Span,
+	partitionSpans []execinfrapb.ChangeFrontierSpec_PartitionSpans,
 	selectClause string,
 ) func(context.Context, *sql.DistSQLPlanner) (*sql.PhysicalPlan, *sql.PlanningCtx, error) {
 
@@ -376,10 +384,11 @@ func makePlan(
 		// is created, even if it is paused and unpaused, but #28982 describes some
 		// ways that this might happen in the future.
 		changeFrontierSpec := execinfrapb.ChangeFrontierSpec{
-			TrackedSpans: trackedSpans,
-			Feed:         details,
-			JobID:        jobID,
-			UserProto:    execCtx.User().EncodeProto(),
+			TrackedSpans:   trackedSpans,
+			Feed:           details,
+			JobID:          jobID,
+			UserProto:      execCtx.User().EncodeProto(),
+			PartitionSpans: partitionSpans,
 		}
 
 		cfKnobs := execCtx.ExecCfg().DistSQLSrv.TestingKnobs.Changefeed
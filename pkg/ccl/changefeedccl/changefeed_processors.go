@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -383,6 +384,11 @@ func (ca *changeAggregator) makeKVFeedCfg(
 			initialHighWater, &ca.metrics.SchemaFeedMetrics, opts.GetCanHandle())
 	}
 
+	familyIDs, err := targetFamilyIDs(ctx, cfg, AllTargets(ca.spec.Feed), initialHighWater)
+	if err != nil {
+		return kvfeed.Config{}, err
+	}
+
 	return kvfeed.Config{
 		Writer:                  buf,
 		Settings:                cfg.Settings,
@@ -407,9 +413,68 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		SchemaFeed:              sf,
 		Knobs:                   ca.knobs.FeedKnobs,
 		UseMux:                  changefeedbase.UseMuxRangeFeed.Get(&cfg.Settings.SV),
+		FamilyIDs:               familyIDs,
 	}, nil
 }
 
+// targetFamilyIDs returns the IDs of the column families watched by a
+// changefeed whose targets are all column families, as of the given timestamp.
+// It returns nil if any target watches all the families of its table, since
+// the rangefeeds then cannot be restricted to the watched families.
+func targetFamilyIDs(
+	ctx context.Context,
+	cfg *execinfra.ServerConfig,
+	targets changefeedbase.Targets,
+	ts hlc.Timestamp,
+) ([]uint32, error) {
+	allFamilyTargets := true
+	_ = targets.EachTarget(func(t changefeedbase.Target) error {
+		if t.Type != jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY {
+			allFamilyTargets = false
+		}
+		return nil
+	})
+	if !allFamilyTargets {
+		return nil, nil
+	}
+
+	var familyIDs []uint32
+	if err := cfg.CollectionFactory.Txn(ctx, cfg.DB, func(
+		ctx context.Context, txn *kv.Txn, descriptors *descs.Collection,
+	) error {
+		familyIDs = familyIDs[:0]
+		if err := txn.SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		seen := make(map[uint32]struct{})
+		return targets.EachTarget(func(t changefeedbase.Target) error {
+			flags := tree.ObjectLookupFlagsWithRequired()
+			flags.AvoidLeased = true
+			desc, err := descriptors.GetImmutableTableByID(ctx, txn, t.TableID, flags)
+			if err != nil {
+				return err
+			}
+			for _, family := range desc.GetFamilies() {
+				if family.Name != t.FamilyName {
+					continue
+				}
+				// Tables can share family IDs, so the union of the families may
+				// let through families that aren't watched. They are filtered out
+				// when the events are decoded.
+				if _, ok := seen[uint32(family.ID)]; !ok {
+					seen[uint32(family.ID)] = struct{}{}
+					familyIDs = append(familyIDs, uint32(family.ID))
+				}
+				return nil
+			}
+			return errors.Errorf("column family %s not found in table %s", t.FamilyName, desc.GetName())
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return familyIDs, nil
+}
+
 // setupSpans is called on start to extract the spans for this changefeed as a
 // slice and creates a span frontier with the initial resolved timestamps. This
 // SpanFrontier only tracks the spans being watched on this node. There is a
@@ -669,6 +734,9 @@ type changeFrontier struct {
 	freqEmitResolved time.Duration
 	// lastEmitResolved is the last time a resolved timestamp was emitted.
 	lastEmitResolved time.Time
+	// partitions, if non-empty, are the partitions of the watched tables for
+	// which resolved timestamps are emitted separately.
+	partitions []partitionResolved
 
 	// slowLogEveryN rate-limits the logging of slow spans
 	slowLogEveryN log.EveryN
@@ -886,6 +954,15 @@ func newChangeFrontierProcessor(
 	} else {
 		cf.freqEmitResolved = emitNoResolved
 	}
+	if emitResolved && opts.ResolvedPerPartition() {
+		for _, p := range spec.PartitionSpans {
+			pr, err := makePartitionResolved(p)
+			if err != nil {
+				return nil, err
+			}
+			cf.partitions = append(cf.partitions, pr)
+		}
+	}
 
 	encodingOpts, err := opts.GetEncodingOptions()
 	if err != nil {
@@ -974,6 +1051,14 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 					return
 				}
 			}
+			for i := range cf.partitions {
+				for _, span := range cf.spec.TrackedSpans {
+					if _, err := cf.partitions[i].forward(span, *ts); err != nil {
+						cf.MoveToDraining(err)
+						return
+					}
+				}
+			}
 		}
 
 		if p.RunningStatus != "" {
@@ -1142,6 +1227,10 @@ func (cf *changeFrontier) forwardFrontier(resolved jobspb.ResolvedSpan) error {
 
 	cf.maybeLogBehindSpan(frontierChanged)
 
+	if err := cf.maybeEmitPartitionResolved(resolved.Span, resolved.Timestamp); err != nil {
+		return err
+	}
+
 	// If frontier changed, we emit resolved timestamp.
 	emitResolved := frontierChanged
 
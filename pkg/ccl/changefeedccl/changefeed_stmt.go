@@ -540,6 +540,12 @@ func createChangefeedJobRecord(
 					"Otherwise, please re-run with a different %[1]q value.",
 				changefeedbase.OptMetricsScope, defaultSLIScope)
 		}
+
+		if !metric.ChildMetricsEnabled.Get(&p.ExecCfg().Settings.SV) {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"%s is set to false, metrics will only be published to the '%s' label when it is set to true",
+				metric.ChildMetricsEnabled.Key(), scope))
+		}
 	}
 
 	details.Opts = opts.AsMap()
@@ -785,6 +791,7 @@ func changefeedJobDescription(
 		changefeedbase.SinkParamSASLPassword,
 		changefeedbase.SinkParamCACert,
 		changefeedbase.SinkParamClientCert,
+		changefeedbase.SinkParamWebhookHeaders,
 	})
 
 	if err != nil {
@@ -929,32 +936,46 @@ func (b *changefeedResumer) handleChangefeedError(
 	switch onError {
 	// default behavior
 	case changefeedbase.OptOnErrorFail:
+		if changefeedbase.IsIncompatibleSchemaError(changefeedErr) {
+			// The schema registry rejected a schema change. Pause instead of
+			// failing, so that the changefeed can be resumed once the
+			// compatibility settings of the subject are adjusted.
+			return b.pauseOnError(ctx, changefeedErr, jobExec, "of an incompatible schema change")
+		}
 		return changefeedErr
 	// pause instead of failing
 	case changefeedbase.OptOnErrorPause:
-		// note: we only want the job to pause here if a failure happens, not a
-		// user-initiated cancellation. if the job has been canceled, the ctx
-		// will handle it and the pause will return an error.
-		const errorFmt = "job failed (%v) but is being paused because of %s=%s"
-		errorMessage := fmt.Sprintf(errorFmt, changefeedErr,
-			changefeedbase.OptOnError, changefeedbase.OptOnErrorPause)
-		return b.job.PauseRequested(ctx, jobExec.Txn(), func(ctx context.Context,
-			planHookState interface{}, txn *kv.Txn, progress *jobspb.Progress) error {
-			err := b.OnPauseRequest(ctx, jobExec, txn, progress)
-			if err != nil {
-				return err
-			}
-			// directly update running status to avoid the running/reverted job status check
-			progress.RunningStatus = errorMessage
-			log.Warningf(ctx, errorFmt, changefeedErr, changefeedbase.OptOnError, changefeedbase.OptOnErrorPause)
-			return nil
-		}, errorMessage)
+		return b.pauseOnError(ctx, changefeedErr, jobExec,
+			fmt.Sprintf("of %s=%s", changefeedbase.OptOnError, changefeedbase.OptOnErrorPause))
 	default:
 		return errors.Wrapf(changefeedErr, "unrecognized option value: %s=%s for handling error",
 			changefeedbase.OptOnError, details.Opts[changefeedbase.OptOnError])
 	}
 }
 
+// pauseOnError pauses the changefeed after it failed with the given error. The
+// reason is reported, along with the error, in the running status of the job.
+func (b *changefeedResumer) pauseOnError(
+	ctx context.Context, changefeedErr error, jobExec sql.JobExecContext, reason string,
+) error {
+	// note: we only want the job to pause here if a failure happens, not a
+	// user-initiated cancellation. if the job has been canceled, the ctx
+	// will handle it and the pause will return an error.
+	const errorFmt = "job failed (%v) but is being paused because %s"
+	errorMessage := fmt.Sprintf(errorFmt, changefeedErr, reason)
+	return b.job.PauseRequested(ctx, jobExec.Txn(), func(ctx context.Context,
+		planHookState interface{}, txn *kv.Txn, progress *jobspb.Progress) error {
+		err := b.OnPauseRequest(ctx, jobExec, txn, progress)
+		if err != nil {
+			return err
+		}
+		// directly update running status to avoid the running/reverted job status check
+		progress.RunningStatus = errorMessage
+		log.Warningf(ctx, errorFmt, changefeedErr, reason)
+		return nil
+	}, errorMessage)
+}
+
 func (b *changefeedResumer) resumeWithRetries(
 	ctx context.Context,
 	jobExec sql.JobExecContext,
@@ -3261,26 +3261,32 @@ func TestChangefeedMonitoring(t *testing.T) {
 			t.Errorf(`expected 0 got %d`, c)
 		}
 
-		enableSLIMetrics = false
 		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH metrics_label='tier0'`)
-		_, err := foo.Next()
-		require.Regexp(t, "cannot create metrics scope", err)
-		require.NoError(t, foo.Close())
+		msg, err := foo.Next()
+		require.NoError(t, err)
+		// The emitted bytes of a message are the bytes of its key and value.
+		emittedBytes := int64(len(msg.Key) + len(msg.Value))
 
-		enableSLIMetrics = true
-		foo = feed(t, f, `CREATE CHANGEFEED FOR foo WITH metrics_label='tier0'`)
-		_, err = foo.Next()
+		// The changefeed's metrics are also exported under its label.
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		tier0, err := registry.MetricsStruct().Changefeed.(*Metrics).getSLIMetrics(`tier0`)
 		require.NoError(t, err)
 
 		testutils.SucceedsSoon(t, func() error {
+			if c := tier0.EmittedMessages.Value(); c != 1 {
+				return errors.Errorf(`expected 1 got %d`, c)
+			}
+			if c := tier0.EmittedBytes.Value(); c != emittedBytes {
+				return errors.Errorf(`expected %d got %d`, emittedBytes, c)
+			}
 			if c := s.Server.MustGetSQLCounter(`changefeed.emitted_messages`); c != 1 {
 				return errors.Errorf(`expected 1 got %d`, c)
 			}
-			if c := s.Server.MustGetSQLCounter(`changefeed.emitted_bytes`); c != 22 {
-				return errors.Errorf(`expected 22 got %d`, c)
+			if c := s.Server.MustGetSQLCounter(`changefeed.emitted_bytes`); c != emittedBytes {
+				return errors.Errorf(`expected %d got %d`, emittedBytes, c)
 			}
-			if c := s.Server.MustGetSQLCounter(`changefeed.flushed_bytes`); c != 22 {
-				return errors.Errorf(`expected 22 got %d`, c)
+			if c := s.Server.MustGetSQLCounter(`changefeed.flushed_bytes`); c != emittedBytes {
+				return errors.Errorf(`expected %d got %d`, emittedBytes, c)
 			}
 			if c := s.Server.MustGetSQLCounter(`changefeed.flushes`); c <= 0 {
 				return errors.Errorf(`expected > 0 got %d`, c)
@@ -4076,6 +4082,23 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH topic_in_value, envelope='row'`, `kafka://nope`,
 	)
 
+	// WITH resolved_per_partition requires resolved and format=json, and is
+	// not supported by the cloud storage sink.
+	sqlDB.ExpectErr(
+		t, `resolved_per_partition is only usable with resolved`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH resolved_per_partition`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `resolved_per_partition is only usable with format=json`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH resolved, resolved_per_partition, format='avro', confluent_schema_registry=$2`,
+		`kafka://nope`, schemaReg.URL(),
+	)
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option resolved_per_partition`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH resolved, resolved_per_partition`,
+		`experimental-nodelocal://0/bar`,
+	)
+
 	// WITH initial_scan and no_initial_scan disallowed
 	sqlDB.ExpectErr(
 		t, `cannot specify both initial_scan and no_initial_scan`,
@@ -4258,10 +4281,20 @@ func TestChangefeedErrors(t *testing.T) {
 		`webhook-https://fake-host`,
 	)
 	sqlDB.ExpectErr(
-		t, `this sink is incompatible with option compression`,
-		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='gzip'`,
+		t, `invalid option value webhook_sink_config, retry multiplier must be at least 1`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH webhook_sink_config='{"Retry": {"Multiplier": 0.5}}'`,
 		`webhook-https://fake-host`,
 	)
+	sqlDB.ExpectErr(
+		t, `invalid option value webhook_sink_config, max retry backoff must not be less than the initial backoff`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH webhook_sink_config='{"Retry": {"Backoff": "10s", "MaxBackoff": "1s"}}'`,
+		`webhook-https://fake-host`,
+	)
+	sqlDB.ExpectErr(
+		t, `invalid sink parameter custom_headers: headers must be a JSON object with string values`,
+		`CREATE CHANGEFEED FOR foo INTO $1`,
+		`webhook-https://fake-host?custom_headers=not_json`,
+	)
 	sqlDB.ExpectErr(
 		t, `max retries must be either a positive int or 'inf' for infinite retries.`,
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH webhook_sink_config='{"Retry": {"Max": "not valid"}}'`,
@@ -6226,6 +6259,135 @@ func TestChangefeedOnlyInitialScan(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks)
 }
 
+// TestChangefeedOnlyInitialScanResumesFromCheckpoint verifies that an
+// initial_scan='only' changefeed which is paused in the middle of its scan
+// does not rescan the spans it had checkpointed once resumed, and still emits
+// every row before completing.
+func TestChangefeedOnlyInitialScanResumesFromCheckpoint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t)
+	skip.UnderShort(t)
+
+	rnd, _ := randutil.NewTestRand()
+
+	testFn := func(t *testing.T, s TestServerWithSystem, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		const numRows = 1000
+		sqlDB.Exec(t, `CREATE TABLE foo(a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, fmt.Sprintf(`INSERT INTO foo (a) SELECT * FROM generate_series(1, %d)`, numRows))
+
+		fooDesc := desctestutils.TestingGetPublicTableDescriptor(
+			s.SystemServer.DB(), s.Codec, "d", "foo")
+		tableSpan := fooDesc.PrimaryIndexSpan(s.Codec)
+
+		knobs := s.TestingKnobs.
+			DistSQL.(*execinfra.TestingKnobs).
+			Changefeed.(*TestingKnobs)
+
+		// Scan in small batches so that the scan resolves many sub-spans.
+		knobs.FeedKnobs.BeforeScanRequest = func(b *kv.Batch) error {
+			b.Header.MaxSpanRequestKeys = 1 + rnd.Int63n(100)
+			return nil
+		}
+
+		// Hold back the resolved events of some of the sub-spans so that the scan
+		// cannot complete and its progress has to be checkpointed.
+		haveGaps := false
+		knobs.FilterSpanWithMutation = func(r *jobspb.ResolvedSpan) bool {
+			if r.Span.Equal(tableSpan) {
+				r.Span.EndKey = tableSpan.Key.Next()
+				return false
+			}
+			if haveGaps {
+				return rnd.Intn(10) > 7
+			}
+			haveGaps = true
+			return true
+		}
+
+		changefeedbase.FrontierCheckpointFrequency.Override(
+			context.Background(), &s.Server.ClusterSettings().SV, 1)
+		changefeedbase.FrontierCheckpointMaxBytes.Override(
+			context.Background(), &s.Server.ClusterSettings().SV, 100<<20)
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH initial_scan = 'only'`)
+
+		// Some test feeds are not buffered, so we have to consume messages.
+		var mu syncutil.Mutex
+		seen := make(map[string]struct{})
+		g := ctxgroup.WithContext(context.Background())
+		g.Go(func() error {
+			for {
+				m, err := foo.Next()
+				if err != nil {
+					return err
+				}
+				if m.Resolved == nil {
+					mu.Lock()
+					seen[string(m.Key)] = struct{}{}
+					mu.Unlock()
+				}
+			}
+		})
+		defer func() {
+			closeFeed(t, foo)
+			_ = g.Wait()
+		}()
+
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		loadProgress := func() jobspb.Progress {
+			job, err := registry.LoadJob(context.Background(), jobFeed.JobID())
+			require.NoError(t, err)
+			return job.Progress()
+		}
+
+		testutils.SucceedsSoon(t, func() error {
+			progress := loadProgress()
+			if p := progress.GetChangefeed(); p != nil && p.Checkpoint != nil && len(p.Checkpoint.Spans) > 0 {
+				return nil
+			}
+			return errors.New("waiting for checkpoint")
+		})
+
+		require.NoError(t, jobFeed.Pause())
+		var checkpoint roachpb.SpanGroup
+		checkpoint.Add(loadProgress().GetChangefeed().Checkpoint.Spans...)
+
+		// Let every span resolve after the resumption, and record them.
+		var resolved []roachpb.Span
+		knobs.FilterSpanWithMutation = func(r *jobspb.ResolvedSpan) bool {
+			if !r.Span.Equal(tableSpan) {
+				resolved = append(resolved, r.Span)
+			}
+			return false
+		}
+
+		require.NoError(t, jobFeed.Resume())
+		require.NoError(t, jobFeed.WaitForStatus(func(s jobs.Status) bool {
+			return s == jobs.StatusSucceeded
+		}))
+
+		// None of the checkpointed spans should have been scanned again.
+		for _, sp := range resolved {
+			require.Falsef(t, checkpoint.Contains(sp.Key), "span should not have been resolved: %s", sp)
+		}
+
+		testutils.SucceedsSoon(t, func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			if len(seen) != numRows {
+				return errors.Newf("saw %d of %d rows", len(seen), numRows)
+			}
+			return nil
+		})
+	}
+
+	cdcTestWithSystem(t, testFn, feedTestForceSink("webhook"))
+}
+
 func TestChangefeedOnlyInitialScanCSV(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -142,3 +142,22 @@ func MaybeStripRetryableErrorMarker(err error) error {
 }
 
 var retryableErrorType = reflect.TypeOf((*retryableError)(nil))
+
+// errIncompatibleSchema marks errors returned when the schema registry rejects
+// a schema because it is incompatible with the versions previously registered
+// for the same subject.
+var errIncompatibleSchema = errors.New("incompatible schema")
+
+// MarkIncompatibleSchemaError marks the given error as being caused by a
+// schema that the schema registry considers incompatible. Such errors are not
+// retried, since retrying cannot succeed until the compatibility settings of
+// the subject are changed.
+func MarkIncompatibleSchemaError(e error) error {
+	return errors.Mark(e, errIncompatibleSchema)
+}
+
+// IsIncompatibleSchemaError returns true if the supplied error, or any of its
+// parent causes, was marked with MarkIncompatibleSchemaError.
+func IsIncompatibleSchemaError(err error) bool {
+	return errors.Is(err, errIncompatibleSchema)
+}
@@ -80,6 +80,7 @@ const (
 	OptKeyInValue               = `key_in_value`
 	OptTopicInValue             = `topic_in_value`
 	OptResolvedTimestamps       = `resolved`
+	OptResolvedPerPartition     = `resolved_per_partition`
 	OptMinCheckpointFrequency   = `min_checkpoint_frequency`
 	OptUpdatedTimestamps        = `updated`
 	OptMVCCTimestamps           = `mvcc_timestamp`
@@ -157,6 +158,9 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	// OptPubsubSinkConfig is a JSON configuration for pubsub sink
+	// (pubsubSinkConfig).
+	OptPubsubSinkConfig = `pubsub_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
 	// Note that this option is only allowed for alter changefeed statements.
@@ -172,6 +176,7 @@ const (
 	SinkParamSkipTLSVerify          = `insecure_tls_skip_verify`
 	SinkParamTopicPrefix            = `topic_prefix`
 	SinkParamTopicName              = `topic_name`
+	SinkParamWebhookHeaders         = `custom_headers`
 	SinkSchemeCloudStorageAzure     = `azure`
 	SinkSchemeCloudStorageGCS       = `gs`
 	SinkSchemeCloudStorageHTTP      = `http`
@@ -292,6 +297,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptKeyInValue:               flagOption,
 	OptTopicInValue:             flagOption,
 	OptResolvedTimestamps:       durationOption.thatCanBeZero().orEmptyMeans("0"),
+	OptResolvedPerPartition:     flagOption,
 	OptMinCheckpointFrequency:   durationOption.thatCanBeZero(),
 	OptUpdatedTimestamps:        flagOption,
 	OptMVCCTimestamps:           flagOption,
@@ -306,6 +312,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptProtectDataFromGCOnPause: flagOption,
 	OptKafkaSinkConfig:          jsonOption,
 	OptWebhookSinkConfig:        jsonOption,
+	OptPubsubSinkConfig:         jsonOption,
 	OptWebhookAuthHeader:        stringOption,
 	OptWebhookClientTimeout:     durationOption,
 	OptOnError:                  enum("pause", "fail"),
@@ -328,16 +335,18 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 var SQLValidOptions map[string]struct{} = nil
 
 // KafkaValidOptions is options exclusive to Kafka sink
-var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig)
+var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig,
+	OptResolvedPerPartition)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
 var CloudStorageValidOptions = makeStringSet(OptCompression)
 
 // WebhookValidOptions is options exclusive to webhook sink
-var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
+var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig,
+	OptResolvedPerPartition, OptCompression)
 
 // PubsubValidOptions is options exclusive to pubsub sink
-var PubsubValidOptions = makeStringSet()
+var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
 
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
@@ -350,6 +359,13 @@ var ExternalConnectionValidOptions = makeStringSet(
 	OptAvroSchemaPrefix,
 	OptConfluentSchemaRegistry,
 	OptKafkaSinkConfig,
+	OptResolvedPerPartition,
+
+	// Options valid for a webhook sink.
+	OptWebhookAuthHeader,
+	OptWebhookClientTimeout,
+	OptWebhookSinkConfig,
+	OptCompression,
 )
 
 // CaseInsensitiveOpts options which supports case Insensitive value
@@ -373,7 +389,7 @@ var NoLongerExperimental = map[string]string{
 // InitialScanOnlyUnsupportedOptions is options that are not supported with the
 // initial scan only option
 var InitialScanOnlyUnsupportedOptions = makeStringSet(OptEndTime, OptResolvedTimestamps, OptDiff,
-	OptMVCCTimestamps, OptUpdatedTimestamps)
+	OptMVCCTimestamps, OptUpdatedTimestamps, OptResolvedPerPartition)
 
 // AlterChangefeedUnsupportedOptions are changefeed options that we do not allow
 // users to alter.
@@ -775,6 +791,12 @@ func (s StatementOptions) GetKafkaConfigJSON() SinkSpecificJSONConfig {
 	return s.getJSONValue(OptKafkaSinkConfig)
 }
 
+// GetPubsubConfigJSON returns arbitrary json to be interpreted
+// by the pubsub sink.
+func (s StatementOptions) GetPubsubConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptPubsubSinkConfig)
+}
+
 // GetResolvedTimestampInterval gets the best-effort interval at which resolved timestamps
 // should be emitted. Nil or 0 means emit as often as possible. False means do not emit at all.
 // Returns an error for negative or invalid duration value.
@@ -787,6 +809,13 @@ func (s StatementOptions) GetResolvedTimestampInterval() (*time.Duration, bool,
 	return d, d != nil, err
 }
 
+// ResolvedPerPartition returns true if resolved timestamps should also be
+// emitted for each partition of the watched tables.
+func (s StatementOptions) ResolvedPerPartition() bool {
+	_, ok := s.m[OptResolvedPerPartition]
+	return ok
+}
+
 // GetMetricScope returns a namespace for metrics affected by this changefeed, or
 // false if none has been provided.
 func (s StatementOptions) GetMetricScope() (string, bool) {
@@ -890,6 +919,14 @@ func (s StatementOptions) ValidateForCreateChangefeed() error {
 			return errors.Newf(`%s=%s is only usable with %s`, OptFormat, OptFormatCSV, OptInitialScanOnly)
 		}
 	}
+	if s.ResolvedPerPartition() {
+		if _, ok := s.m[OptResolvedTimestamps]; !ok {
+			return errors.Newf(`%s is only usable with %s`, OptResolvedPerPartition, OptResolvedTimestamps)
+		}
+		if f, ok := s.m[OptFormat]; ok && f != string(OptFormatJSON) {
+			return errors.Newf(`%s is only usable with %s=%s`, OptResolvedPerPartition, OptFormat, OptFormatJSON)
+		}
+	}
 	return nil
 }
 
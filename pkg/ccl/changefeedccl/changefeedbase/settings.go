@@ -10,6 +10,7 @@ package changefeedbase
 
 import (
 	"encoding/json"
+	"net/http"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -166,6 +167,67 @@ func validateSinkThrottleConfig(values *settings.Values, configStr string) error
 	return json.Unmarshal([]byte(configStr), config)
 }
 
+// WebhookSinkHeaders specifies HTTP headers added to the requests sent by all
+// webhook sinks. The headers may carry credentials, so the setting is not
+// reportable.
+var WebhookSinkHeaders = func() *settings.StringSetting {
+	s := settings.RegisterValidatedStringSetting(
+		settings.TenantWritable,
+		"changefeed.webhook.headers",
+		"JSON object of HTTP headers added to the requests sent by webhook sinks; "+
+			"headers set with the custom_headers parameter of a sink URI take precedence",
+		"",
+		validateWebhookSinkHeaders,
+	)
+	// Even though string settings are non-reportable by default, we
+	// still mark them explicitly in case a future code change flips the
+	// default.
+	s.SetReportable(false)
+	return s
+}()
+
+func validateWebhookSinkHeaders(values *settings.Values, headers string) error {
+	_, err := ParseWebhookSinkHeaders(headers)
+	return err
+}
+
+// reservedWebhookSinkHeaders are the headers which can't be set for webhook
+// sinks: the Host header, which would redirect the requests to another virtual
+// host, and the hop-by-hop headers, which are managed by the HTTP client.
+var reservedWebhookSinkHeaders = map[string]struct{}{
+	"Host":                {},
+	"Connection":          {},
+	"Keep-Alive":          {},
+	"Proxy-Authenticate":  {},
+	"Proxy-Authorization": {},
+	"Proxy-Connection":    {},
+	"Te":                  {},
+	"Trailer":             {},
+	"Transfer-Encoding":   {},
+	"Upgrade":             {},
+}
+
+// ParseWebhookSinkHeaders parses a JSON object mapping HTTP header names to
+// values. An empty string yields no headers.
+func ParseWebhookSinkHeaders(headers string) (map[string]string, error) {
+	if headers == "" {
+		return nil, nil
+	}
+	var res map[string]string
+	if err := json.Unmarshal([]byte(headers), &res); err != nil {
+		return nil, errors.Wrap(err, "headers must be a JSON object with string values")
+	}
+	for k := range res {
+		if k == "" {
+			return nil, errors.New("header names must not be empty")
+		}
+		if _, ok := reservedWebhookSinkHeaders[http.CanonicalHeaderKey(k)]; ok {
+			return nil, errors.Newf("header %q can't be set", k)
+		}
+	}
+	return res, nil
+}
+
 // MinHighWaterMarkCheckpointAdvance specifies the minimum amount of time the
 // changefeed high water mark must advance for it to be eligible for checkpointing.
 var MinHighWaterMarkCheckpointAdvance = settings.RegisterDurationSetting(
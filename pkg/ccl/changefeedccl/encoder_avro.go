@@ -307,6 +307,10 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 	return registered.schema.BinaryFromRow(header, meta, nilRow, nilRow)
 }
 
+// register registers the given schema for the given subject. A new version of
+// the schema is registered every time the table descriptor changes; the schema
+// registry rejects the versions which are incompatible with the previous ones,
+// which is reported as an incompatible schema error.
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
 ) (int32, error) {
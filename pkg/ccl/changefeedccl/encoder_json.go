@@ -181,10 +181,22 @@ func (e *jsonEncoder) EncodeValue(
 // EncodeResolvedTimestamp implements the Encoder interface.
 func (e *jsonEncoder) EncodeResolvedTimestamp(
 	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return e.encodeResolvedTimestamp(resolved, "" /* partition */)
+}
+
+// encodeResolvedTimestamp encodes a resolved timestamp message. If partition is
+// non-empty, the message is for the named partition of the watched tables
+// rather than for the whole changefeed.
+func (e *jsonEncoder) encodeResolvedTimestamp(
+	resolved hlc.Timestamp, partition string,
 ) ([]byte, error) {
 	meta := map[string]interface{}{
 		`resolved`: eval.TimestampToDecimalDatum(resolved).Decimal.String(),
 	}
+	if partition != "" {
+		meta[`partition`] = partition
+	}
 	var jsonEntries interface{}
 	if e.wrapped {
 		jsonEntries = meta
@@ -75,6 +75,10 @@ type Config struct {
 
 	// UseMux enables MuxRangeFeed rpc
 	UseMux bool
+
+	// FamilyIDs, if non-empty, are the IDs of the column families of the
+	// targets. The rangefeeds only emit the values of these families.
+	FamilyIDs []uint32
 }
 
 // Run will run the kvfeed. The feed runs synchronously and returns an
@@ -111,6 +115,7 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.UseMux, cfg.Knobs)
 	f.onBackfillCallback = cfg.OnBackfillCallback
+	f.familyIDs = cfg.FamilyIDs
 
 	g := ctxgroup.WithContext(ctx)
 	g.GoCtx(cfg.SchemaFeed.Run)
@@ -179,7 +184,8 @@ type kvFeed struct {
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass
 	schemaChangePolicy changefeedbase.SchemaChangePolicy
 
-	useMux bool
+	useMux    bool
+	familyIDs []uint32
 
 	// These dependencies are made available for test injection.
 	bufferFactory func() kvevent.Buffer
@@ -466,11 +472,12 @@ func (f *kvFeed) runUntilTableEvent(
 
 	g := ctxgroup.WithContext(ctx)
 	physicalCfg := rangeFeedConfig{
-		Spans:    stps,
-		Frontier: resumeFrontier.Frontier(),
-		WithDiff: f.withDiff,
-		Knobs:    f.knobs,
-		UseMux:   f.useMux,
+		Spans:     stps,
+		Frontier:  resumeFrontier.Frontier(),
+		WithDiff:  f.withDiff,
+		Knobs:     f.knobs,
+		UseMux:    f.useMux,
+		FamilyIDs: f.familyIDs,
 	}
 
 	g.GoCtx(func(ctx context.Context) error {
@@ -32,6 +32,9 @@ type rangeFeedConfig struct {
 	WithDiff bool
 	Knobs    TestingKnobs
 	UseMux   bool
+	// FamilyIDs, if non-empty, restricts the values emitted by the rangefeed to
+	// those of the given column families.
+	FamilyIDs []uint32
 }
 
 type rangefeedFactory func(
@@ -79,6 +82,9 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	if cfg.UseMux {
 		rfOpts = append(rfOpts, kvcoord.WithMuxRangeFeed())
 	}
+	if len(cfg.FamilyIDs) > 0 {
+		rfOpts = append(rfOpts, kvcoord.WithFamilyIDs(cfg.FamilyIDs))
+	}
 
 	g.GoCtx(func(ctx context.Context) error {
 		return p(ctx, cfg.Spans, cfg.WithDiff, feed.eventC, rfOpts...)
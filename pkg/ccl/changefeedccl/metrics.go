@@ -21,19 +21,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
-	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
-	"github.com/cockroachdb/errors"
 )
 
-// allow creation of per changefeed SLI metrics.
-var enableSLIMetrics = envutil.EnvOrDefaultBool(
-	"COCKROACH_EXPERIMENTAL_ENABLE_PER_CHANGEFEED_METRICS", false)
-
 // max length for the scope name.
 const maxSLIScopeNameLen = 128
 
@@ -498,13 +492,11 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		return s, nil
 	}
 
+	// Metrics scopes used to require an environment variable, since every scope
+	// adds a set of child metrics. They are now always allowed: the number of
+	// scopes is capped below, and child metrics are only exported when
+	// server.child_metrics.enabled is set.
 	if scope != defaultSLIScope {
-		if !enableSLIMetrics {
-			return nil, errors.WithHint(
-				pgerror.Newf(pgcode.ConfigurationLimitExceeded, "cannot create metrics scope %q", scope),
-				"try restarting with COCKROACH_EXPERIMENTAL_ENABLE_PER_CHANGEFEED_METRICS=true",
-			)
-		}
 		const failSafeMax = 1024
 		if len(a.mu.sliMetrics) == failSafeMax {
 			return nil, pgerror.Newf(pgcode.ConfigurationLimitExceeded,
@@ -19,6 +19,7 @@ import (
 var escapeRE = regexp.MustCompile(`_u[0-9a-fA-F]{2,8}_`)
 var kafkaDisallowedRE = regexp.MustCompile(`[^a-zA-Z0-9\._\-]`)
 var avroDisallowedRE = regexp.MustCompile(`[^A-Za-z0-9_]`)
+var pubsubDisallowedRE = regexp.MustCompile(`[^a-zA-Z0-9\._\-~+%]`)
 
 func escapeRune(r rune) string {
 	if r <= 1<<16 {
@@ -55,6 +56,31 @@ func KafkaNameToSQLName(s string) string {
 	return unescapeSQLName(s)
 }
 
+// SQLNameToPubsubName escapes a sql table name into a valid pubsub topic name.
+// This is reversible by PubsubNameToSQLName except when the escaped string is
+// longer than pubsub's length limit.
+//
+// Pubsub allows names matching `[a-zA-Z0-9\._\-~+%]{3,255}` which start with
+// a letter. Names which don't start with a letter are left for pubsub to
+// reject, since an escape doesn't start with a letter either.
+//
+// Runes are escaped with _u<hex>_ in an attempt to look like U+0021. For
+// example `!` escapes to `_u0021_`.
+func SQLNameToPubsubName(s string) string {
+	s = escapeSQLName(s, pubsubDisallowedRE)
+	if len(s) > 255 {
+		// Not going to roundtrip, but not much we can do about that.
+		return s[:255]
+	}
+	return s
+}
+
+// PubsubNameToSQLName is the inverse of SQLNameToPubsubName except when
+// SQLNameToPubsubName had to truncate.
+func PubsubNameToSQLName(s string) string {
+	return unescapeSQLName(s)
+}
+
 // SQLNameToAvroName escapes a sql table name into a valid avro record or field
 // name. This is reversible by AvroNameToSQLName.
 //
@@ -55,6 +55,33 @@ func TestSQLNameToKafkaName(t *testing.T) {
 	require.Equal(t, `/`, KafkaNameToSQLName(`_u2F_`))
 }
 
+func TestSQLNameToPubsubName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tests := []struct {
+		sql, pubsub string
+	}{
+		{`foo`, `foo`},
+		{`abcdefghijklmnopqrstuvwxyz`, `abcdefghijklmnopqrstuvwxyz`},
+		{`ABCDEFGHIJKLMNOPQRSTUVWXYZ`, `ABCDEFGHIJKLMNOPQRSTUVWXYZ`},
+		{`0123456789_-.~+%`, `0123456789_-.~+%`},
+		{`!`, `_u0021_`},
+		{`foo!bar`, `foo_u0021_bar`},
+		{`foo_u0021_bar`, `foo_u005f__u0075__u0030__u0030__u0032__u0031__u005f_bar`},
+		{`/`, `_u002f_`},
+		{`☃`, `_u2603_`},
+	}
+	for i, test := range tests {
+		if p := SQLNameToPubsubName(test.sql); p != test.pubsub {
+			t.Errorf(`%d: %s did not escape to %s got %s`, i, test.sql, test.pubsub, p)
+		}
+		if s := PubsubNameToSQLName(test.pubsub); s != test.sql {
+			t.Errorf(`%d: %s did not unescape to %s got %s`, i, test.pubsub, test.sql, s)
+		}
+	}
+}
+
 func TestSQLNameToAvroName(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
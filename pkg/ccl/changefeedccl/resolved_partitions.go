@@ -0,0 +1,198 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/errors"
+)
+
+// fetchPartitionSpans returns the spans of each partition of the primary
+// indexes of the given tables, grouped by partition name. Partitions with the
+// same name in different tables are treated as a single partition, which makes
+// it possible to track e.g. a region across all the tables of a changefeed.
+//
+// Only the top-level partitioning of the primary index is considered. List
+// entries containing DEFAULT overlap with the other entries of the
+// partitioning and are skipped.
+func fetchPartitionSpans(
+	codec keys.SQLCodec, tableDescs []catalog.TableDescriptor,
+) ([]execinfrapb.ChangeFrontierSpec_PartitionSpans, error) {
+	var a tree.DatumAlloc
+	groups := make(map[string]*roachpb.SpanGroup)
+	addSpan := func(name string, sp roachpb.Span) {
+		g, ok := groups[name]
+		if !ok {
+			g = &roachpb.SpanGroup{}
+			groups[name] = g
+		}
+		g.Add(sp)
+	}
+	for _, desc := range tableDescs {
+		idx := desc.GetPrimaryIndex()
+		part := idx.GetPartitioning()
+		if part.NumColumns() == 0 {
+			continue
+		}
+		if err := part.ForEachList(func(name string, values [][]byte, _ catalog.Partitioning) error {
+			for _, valueEncBuf := range values {
+				t, keyPrefix, err := rowenc.DecodePartitionTuple(
+					&a, codec, desc, idx, part, valueEncBuf, nil /* prefixDatums */)
+				if err != nil {
+					return err
+				}
+				if t.SpecialCount > 0 {
+					continue
+				}
+				addSpan(name, roachpb.Span{Key: keyPrefix, EndKey: roachpb.Key(keyPrefix).PrefixEnd()})
+			}
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(err, "decoding partitions of table %s", desc.GetName())
+		}
+		if err := part.ForEachRange(func(name string, from, to []byte) error {
+			_, fromKey, err := rowenc.DecodePartitionTuple(
+				&a, codec, desc, idx, part, from, nil /* prefixDatums */)
+			if err != nil {
+				return err
+			}
+			_, toKey, err := rowenc.DecodePartitionTuple(
+				&a, codec, desc, idx, part, to, nil /* prefixDatums */)
+			if err != nil {
+				return err
+			}
+			addSpan(name, roachpb.Span{Key: fromKey, EndKey: toKey})
+			return nil
+		}); err != nil {
+			return nil, errors.Wrapf(err, "decoding partitions of table %s", desc.GetName())
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	partitions := make([]execinfrapb.ChangeFrontierSpec_PartitionSpans, len(names))
+	for i, name := range names {
+		partitions[i] = execinfrapb.ChangeFrontierSpec_PartitionSpans{
+			Name:  name,
+			Spans: groups[name].Slice(),
+		}
+	}
+	return partitions, nil
+}
+
+// partitionResolved tracks the resolved timestamp of a single partition of
+// the watched tables. The resolved timestamp of a partition is the minimum
+// resolved timestamp of the spans that it covers, so it may be ahead of the
+// changefeed-level resolved timestamp. It is kept in a frontier over the spans
+// of the partition alone, so that forwarding a span costs the same as it does
+// for the changefeed-level frontier regardless of the number of ranges.
+//
+// The resolved timestamps of partitions are not persisted. After a restart,
+// they start over from the changefeed's high-water mark.
+type partitionResolved struct {
+	name     string
+	frontier *span.Frontier
+	// lastEmitted is the last resolved timestamp emitted for the partition.
+	lastEmitted hlc.Timestamp
+}
+
+func makePartitionResolved(
+	p execinfrapb.ChangeFrontierSpec_PartitionSpans,
+) (partitionResolved, error) {
+	f, err := span.MakeFrontier(p.Spans...)
+	if err != nil {
+		return partitionResolved{}, err
+	}
+	return partitionResolved{name: p.Name, frontier: f}, nil
+}
+
+// forward advances the resolved timestamp of the parts of sp that overlap the
+// partition, and returns true if the resolved timestamp of the partition
+// advanced as a result.
+func (p *partitionResolved) forward(sp roachpb.Span, ts hlc.Timestamp) (bool, error) {
+	return p.frontier.Forward(sp, ts)
+}
+
+// shouldEmit returns the resolved timestamp of the partition and whether it
+// has advanced by at least freq since it was last emitted.
+func (p *partitionResolved) shouldEmit(freq time.Duration) (hlc.Timestamp, bool) {
+	resolved := p.frontier.Frontier()
+	if resolved.IsEmpty() || resolved.LessEq(p.lastEmitted) {
+		return resolved, false
+	}
+	if !p.lastEmitted.IsEmpty() && resolved.GoTime().Sub(p.lastEmitted.GoTime()) < freq {
+		return resolved, false
+	}
+	return resolved, true
+}
+
+// partitionResolvedEncoder wraps the JSON encoder of a changefeed to encode
+// resolved timestamp messages for a single partition.
+type partitionResolvedEncoder struct {
+	*jsonEncoder
+	partition string
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e partitionResolvedEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return e.encodeResolvedTimestamp(resolved, e.partition)
+}
+
+// maybeEmitPartitionResolved forwards the resolved timestamp of the partitions
+// overlapping the given span, and emits those whose resolved timestamp has
+// advanced by at least the resolved timestamp frequency of the changefeed.
+func (cf *changeFrontier) maybeEmitPartitionResolved(sp roachpb.Span, ts hlc.Timestamp) error {
+	if len(cf.partitions) == 0 {
+		return nil
+	}
+	encoder, ok := cf.encoder.(*jsonEncoder)
+	if !ok {
+		return errors.AssertionFailedf("unexpected encoder %T for partition resolved timestamps", cf.encoder)
+	}
+	for i := range cf.partitions {
+		p := &cf.partitions[i]
+		advanced, err := p.forward(sp, ts)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			continue
+		}
+		resolved, ok := p.shouldEmit(cf.freqEmitResolved)
+		if !ok {
+			continue
+		}
+		partitionEncoder := partitionResolvedEncoder{jsonEncoder: encoder, partition: p.name}
+		if err := cf.sink.EmitResolvedTimestamp(cf.Ctx, partitionEncoder, resolved); err != nil {
+			return err
+		}
+		if log.V(2) {
+			log.Infof(cf.Ctx, `resolved %s for partition %s`, resolved, p.name)
+		}
+		p.lastEmitted = resolved
+	}
+	return nil
+}
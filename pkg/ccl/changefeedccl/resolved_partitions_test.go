@@ -0,0 +1,97 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingResolvedSink records the partition resolved timestamps emitted to
+// it.
+type recordingResolvedSink struct {
+	emitted []string
+}
+
+func (s *recordingResolvedSink) Dial() error  { return nil }
+func (s *recordingResolvedSink) Close() error { return nil }
+
+func (s *recordingResolvedSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	e := encoder.(partitionResolvedEncoder)
+	s.emitted = append(s.emitted, e.partition+"@"+resolved.String())
+	return nil
+}
+
+func TestPartitionResolvedEmit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sp := func(start, end string) roachpb.Span {
+		return roachpb.Span{Key: roachpb.Key(start), EndKey: roachpb.Key(end)}
+	}
+	ts := func(sec int64) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: sec * int64(time.Second)}
+	}
+
+	opts, err := getGenericWebhookSinkOptions().GetEncodingOptions()
+	require.NoError(t, err)
+	enc, err := makeJSONEncoder(opts, changefeedbase.Targets{})
+	require.NoError(t, err)
+	sink := &recordingResolvedSink{}
+	cf := &changeFrontier{encoder: enc, sink: sink, freqEmitResolved: 10 * time.Second}
+	cf.Ctx = context.Background()
+	for _, p := range []execinfrapb.ChangeFrontierSpec_PartitionSpans{
+		{Name: "east", Spans: []roachpb.Span{sp("a", "c"), sp("e", "g")}},
+		{Name: "west", Spans: []roachpb.Span{sp("c", "e")}},
+	} {
+		pr, err := makePartitionResolved(p)
+		require.NoError(t, err)
+		cf.partitions = append(cf.partitions, pr)
+	}
+
+	for _, tc := range []struct {
+		span     roachpb.Span
+		ts       hlc.Timestamp
+		expected []string
+	}{
+		// Only part of east is resolved, and west isn't touched.
+		{span: sp("a", "c"), ts: ts(5)},
+		// West is fully resolved by a span extending into east.
+		{span: sp("b", "e"), ts: ts(5), expected: []string{"west@" + ts(5).String()}},
+		// East is resolved at the minimum of its spans.
+		{span: sp("e", "g"), ts: ts(20), expected: []string{"east@" + ts(5).String()}},
+		// West advanced by less than the resolved frequency.
+		{span: sp("c", "e"), ts: ts(10)},
+		// West advanced by the resolved frequency since it was last emitted.
+		{span: sp("c", "e"), ts: ts(15), expected: []string{"west@" + ts(15).String()}},
+		// A span outside of all partitions resolves nothing.
+		{span: sp("x", "z"), ts: ts(100)},
+		// Regressing a span doesn't emit anything.
+		{span: sp("a", "g"), ts: ts(1)},
+		// Both partitions advance at once.
+		{span: sp("a", "g"), ts: ts(30), expected: []string{
+			"east@" + ts(30).String(), "west@" + ts(30).String(),
+		}},
+	} {
+		sink.emitted = nil
+		require.NoError(t, cf.maybeEmitPartitionResolved(tc.span, tc.ts))
+		require.Equal(t, tc.expected, sink.emitted, "forwarding %s to %s", tc.span, tc.ts)
+	}
+}
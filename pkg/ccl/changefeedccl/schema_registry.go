@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path"
 
@@ -164,6 +165,13 @@ func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 			return errors.Wrap(err, "contacting confluent schema registry")
 		}
 		defer gracefulClose(ctx, resp.Body)
+		if resp.StatusCode == http.StatusConflict {
+			// The schema registry rejects schemas that are incompatible
+			// with the previously registered versions of the subject.
+			body, _ := io.ReadAll(resp.Body)
+			return changefeedbase.MarkIncompatibleSchemaError(
+				errors.Errorf("registering schema to %s %s: %s", u, resp.Status, body))
+		}
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			body, _ := io.ReadAll(resp.Body)
 			return errors.Errorf("registering schema to %s %s: %s", u, resp.Status, body)
@@ -199,6 +207,11 @@ func (r *confluentSchemaRegistry) doWithRetry(ctx context.Context, fn func() err
 		if err == nil {
 			return nil
 		}
+		if changefeedbase.IsIncompatibleSchemaError(err) {
+			// Retrying won't help until the compatibility settings of
+			// the subject are changed in the schema registry.
+			return err
+		}
 		log.VInfof(ctx, 2, "retrying schema registry operation: %s", err.Error())
 	}
 	return changefeedbase.MarkRetryableError(err)
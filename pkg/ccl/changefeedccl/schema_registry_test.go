@@ -10,9 +10,13 @@ package changefeedccl
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/stretchr/testify/require"
@@ -56,3 +60,32 @@ func TestConfluentSchemaRegistryPing(t *testing.T) {
 		require.Error(t, reg.Ping(context.Background()))
 	})
 }
+
+func TestConfluentSchemaRegistryIncompatibleSchema(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var registerCalls int
+	regServer := httptest.NewServer(http.HandlerFunc(func(hw http.ResponseWriter, hr *http.Request) {
+		switch {
+		case strings.HasPrefix(hr.URL.Path, "/subjects/"):
+			registerCalls++
+			http.Error(hw, `{"error_code": 409}`, http.StatusConflict)
+		default:
+			hw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer regServer.Close()
+
+	ctx := context.Background()
+	reg, err := newConfluentSchemaRegistry(regServer.URL)
+	require.NoError(t, err)
+
+	// The schema registry rejects incompatible schemas with a conflict, which
+	// is not retried.
+	_, err = reg.RegisterSchemaForSubject(ctx, "foo-value", `"string"`)
+	require.Error(t, err)
+	require.True(t, changefeedbase.IsIncompatibleSchemaError(err))
+	require.False(t, changefeedbase.IsRetryableError(err))
+	require.Equal(t, 1, registerCalls)
+}
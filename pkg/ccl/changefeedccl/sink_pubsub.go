@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"net/url"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -48,7 +49,7 @@ func isPubsubSink(u *url.URL) bool {
 type pubsubClient interface {
 	init() error
 	closeTopics()
-	flushTopics()
+	flushTopics() error
 	sendMessage(content []byte, topic string, key string) error
 	sendMessageToAllTopics(content []byte) error
 	connectivityError() error
@@ -82,14 +83,45 @@ type gcpPubsubClient struct {
 	region     string
 	topicNamer *TopicNamer
 	url        sinkURL
+	batchCfg   batchConfig
 
 	mu struct {
 		syncutil.Mutex
 		autocreateError error
 		publishError    error
+		// pending contains the results of the messages published since the last
+		// flush, when messages are batched.
+		pending []publishResult
 	}
 }
 
+// pubsubSinkConfig is the JSON configuration of the pubsub sink, which is set
+// with the pubsub_sink_config option.
+type pubsubSinkConfig struct {
+	// Flush controls the batching of the messages published to each topic. See
+	// pubsub.PublishSettings.
+	Flush batchConfig `json:",omitempty"`
+}
+
+// getPubsubSinkConfig parses the pubsub_sink_config option.
+func getPubsubSinkConfig(jsonStr changefeedbase.SinkSpecificJSONConfig) (batchConfig, error) {
+	var cfg pubsubSinkConfig
+	if jsonStr != `` {
+		if err := json.Unmarshal([]byte(jsonStr), &cfg); err != nil {
+			return batchConfig{}, errors.Wrapf(err, "error unmarshalling json")
+		}
+	}
+	if cfg.Flush.Messages < 0 || cfg.Flush.Bytes < 0 || cfg.Flush.Frequency < 0 {
+		return batchConfig{}, errors.Errorf(
+			"invalid option value %s, all config values must be non-negative", changefeedbase.OptPubsubSinkConfig)
+	}
+	if (cfg.Flush.Messages > 0 || cfg.Flush.Bytes > 0) && cfg.Flush.Frequency == 0 {
+		return batchConfig{}, errors.Errorf(
+			"invalid option value %s, flush frequency is not set, messages may never be sent", changefeedbase.OptPubsubSinkConfig)
+	}
+	return cfg.Flush, nil
+}
+
 type pubsubSink struct {
 	numWorkers int
 
@@ -178,12 +210,19 @@ func MakePubsubSink(
 	ctx context.Context,
 	u *url.URL,
 	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
 	targets changefeedbase.Targets,
 ) (Sink, error) {
 
 	pubsubURL := sinkURL{URL: u, q: u.Query()}
+	pubsubTopicPrefix := pubsubURL.consumeParam(changefeedbase.SinkParamTopicPrefix)
 	pubsubTopicName := pubsubURL.consumeParam(changefeedbase.SinkParamTopicName)
 
+	batchCfg, err := getPubsubSinkConfig(jsonConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error processing option %s", changefeedbase.OptPubsubSinkConfig)
+	}
+
 	var formatType changefeedbase.FormatType
 	switch encodingOpts.Format {
 	case changefeedbase.OptFormatJSON:
@@ -219,7 +258,8 @@ func MakePubsubSink(
 		if region == "" {
 			return nil, errors.New("region query parameter not found")
 		}
-		tn, err := MakeTopicNamer(targets, WithSingleName(pubsubTopicName))
+		tn, err := MakeTopicNamer(targets,
+			WithPrefix(pubsubTopicPrefix), WithSingleName(pubsubTopicName), WithSanitizeFn(SQLNameToPubsubName))
 		if err != nil {
 			return nil, err
 		}
@@ -229,6 +269,7 @@ func MakePubsubSink(
 			projectID:  projectID,
 			region:     gcpEndpointForRegion(region),
 			url:        pubsubURL,
+			batchCfg:   batchCfg,
 		}
 		p.client = g
 		p.topicNamer = tn
@@ -459,7 +500,9 @@ func (p *pubsubSink) flushWorkers() error {
 	}
 
 	// flush messages within topic
-	p.client.flushTopics()
+	if err := p.client.flushTopics(); err != nil {
+		return err
+	}
 
 	select {
 	// signals sink that flush is complete
@@ -518,9 +561,24 @@ func (p *gcpPubsubClient) openTopic(topicName string) (*pubsub.Topic, error) {
 		}
 	}
 	t.EnableMessageOrdering = true
+	if p.batching() {
+		t.PublishSettings.DelayThreshold = time.Duration(p.batchCfg.Frequency)
+		if p.batchCfg.Messages > 0 {
+			t.PublishSettings.CountThreshold = p.batchCfg.Messages
+		}
+		if p.batchCfg.Bytes > 0 {
+			t.PublishSettings.ByteThreshold = p.batchCfg.Bytes
+		}
+	}
 	return t, nil
 }
 
+// batching returns true if messages are batched according to the
+// pubsub_sink_config option. Otherwise, messages are published one at a time.
+func (p *gcpPubsubClient) batching() bool {
+	return p.batchCfg.Frequency > 0
+}
+
 func (p *gcpPubsubClient) closeTopics() {
 	_ = p.forEachTopic(func(_ string, t *pubsub.Topic) error {
 		t.Stop()
@@ -538,6 +596,11 @@ func (p *gcpPubsubClient) sendMessage(m []byte, topic string, key string) error
 		Data:        m,
 		OrderingKey: key,
 	})
+	if p.batching() {
+		// The message is sent along with the rest of its batch. Its result is
+		// checked when the sink is flushed, or once too many results are pending.
+		return p.addPendingResult(res)
+	}
 
 	// The Get method blocks until a server-generated ID or
 	// an error is returned for the published message.
@@ -563,11 +626,56 @@ func (p *gcpPubsubClient) sendMessageToAllTopics(m []byte) error {
 	})
 }
 
-func (p *gcpPubsubClient) flushTopics() {
+func (p *gcpPubsubClient) flushTopics() error {
 	_ = p.forEachTopic(func(_ string, t *pubsub.Topic) error {
 		t.Flush()
 		return nil
 	})
+
+	return p.waitForPendingResults()
+}
+
+// maxPendingPublishResults is the number of batched messages whose results can
+// be pending before the client waits for them.
+const maxPendingPublishResults = 10000
+
+// publishResult is the result of publishing a message, implemented by
+// *pubsub.PublishResult.
+type publishResult interface {
+	// Get blocks until the message is published, and returns its server ID or
+	// the error encountered.
+	Get(ctx context.Context) (serverID string, err error)
+}
+
+// addPendingResult adds the result of a batched message to the pending
+// results. Once maxPendingPublishResults results are pending, it waits for
+// them, so that the results don't accumulate without bound when the sink is
+// rarely flushed.
+func (p *gcpPubsubClient) addPendingResult(res publishResult) error {
+	p.mu.Lock()
+	p.mu.pending = append(p.mu.pending, res)
+	full := len(p.mu.pending) >= maxPendingPublishResults
+	p.mu.Unlock()
+	if !full {
+		return nil
+	}
+	return p.waitForPendingResults()
+}
+
+// waitForPendingResults waits for the pending results, returning the first
+// error encountered while publishing.
+func (p *gcpPubsubClient) waitForPendingResults() error {
+	p.mu.Lock()
+	pending := p.mu.pending
+	p.mu.pending = nil
+	p.mu.Unlock()
+	for _, res := range pending {
+		if _, err := res.Get(p.ctx); err != nil {
+			p.recordPublishError(err)
+			return err
+		}
+	}
+	return nil
 }
 
 func (p *gcpPubsubClient) forEachTopic(f func(name string, topicClient *pubsub.Topic) error) error {
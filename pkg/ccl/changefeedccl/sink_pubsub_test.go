@@ -0,0 +1,105 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubsubSinkConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tests := []struct {
+		config   changefeedbase.SinkSpecificJSONConfig
+		expected batchConfig
+		err      string
+	}{
+		{config: ``},
+		{
+			config:   `{"Flush": {"Messages": 100, "Frequency": "1s"}}`,
+			expected: batchConfig{Messages: 100, Frequency: jsonDuration(time.Second)},
+		},
+		{
+			config:   `{"Flush": {"Bytes": 1024, "Frequency": "50ms"}}`,
+			expected: batchConfig{Bytes: 1024, Frequency: jsonDuration(50 * time.Millisecond)},
+		},
+		{
+			config: `{"Flush": {"Messages": 100}}`,
+			err:    "flush frequency is not set",
+		},
+		{
+			config: `{"Flush": {"Messages": -1, "Frequency": "1s"}}`,
+			err:    "all config values must be non-negative",
+		},
+		{
+			config: `{"Flush": `,
+			err:    "error unmarshalling json",
+		},
+	}
+	for _, test := range tests {
+		t.Run(string(test.config), func(t *testing.T) {
+			cfg, err := getPubsubSinkConfig(test.config)
+			if test.err != "" {
+				require.Regexp(t, test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cfg)
+		})
+	}
+}
+
+type fakePublishResult struct {
+	err  error
+	gets *int
+}
+
+func (r fakePublishResult) Get(context.Context) (string, error) {
+	*r.gets++
+	return "", r.err
+}
+
+func TestPubsubPendingResultsCapped(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	p := &gcpPubsubClient{ctx: context.Background()}
+	var gets int
+	for i := 0; i < maxPendingPublishResults-1; i++ {
+		require.NoError(t, p.addPendingResult(fakePublishResult{gets: &gets}))
+	}
+	require.Zero(t, gets)
+	require.Len(t, p.mu.pending, maxPendingPublishResults-1)
+
+	// Reaching the cap waits for all the pending results.
+	require.NoError(t, p.addPendingResult(fakePublishResult{gets: &gets}))
+	require.Equal(t, maxPendingPublishResults, gets)
+	require.Empty(t, p.mu.pending)
+
+	// Errors are surfaced by the call which waits for the results, and recorded
+	// as publish errors.
+	gets = 0
+	require.NoError(t, p.addPendingResult(fakePublishResult{err: errors.New("boom"), gets: &gets}))
+	for i := 0; i < maxPendingPublishResults-2; i++ {
+		require.NoError(t, p.addPendingResult(fakePublishResult{gets: &gets}))
+	}
+	require.Regexp(t, "boom", p.addPendingResult(fakePublishResult{gets: &gets}))
+	require.Equal(t, 1, gets)
+	require.Empty(t, p.mu.pending)
+	require.Regexp(t, "boom", p.mu.publishError)
+}
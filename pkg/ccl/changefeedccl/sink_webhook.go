@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
@@ -37,9 +39,10 @@ import (
 )
 
 const (
-	applicationTypeJSON = `application/json`
-	applicationTypeCSV  = `text/csv`
-	authorizationHeader = `Authorization`
+	applicationTypeJSON   = `application/json`
+	applicationTypeCSV    = `text/csv`
+	authorizationHeader   = `Authorization`
+	contentEncodingHeader = `Content-Encoding`
 )
 
 func isWebhookSink(u *url.URL) bool {
@@ -59,11 +62,15 @@ type webhookSink struct {
 	batchCfg    batchConfig
 	ts          timeutil.TimeSource
 	format      changefeedbase.FormatType
+	compression string
 
 	// Webhook destination.
 	url        sinkURL
 	authHeader string
-	client     *httputil.Client
+	// headers are added to every request before the headers set by the sink
+	// itself (content type and encoding, authorization), which take precedence.
+	headers map[string]string
+	client  *httputil.Client
 
 	// messages are written onto batch channel
 	// which batches matches based on batching configuration.
@@ -213,8 +220,10 @@ func (j *jsonMaxRetries) UnmarshalJSON(b []byte) error {
 
 // wrapper structs to unmarshal json, retry.Options will be the actual config
 type retryConfig struct {
-	Max     jsonMaxRetries `json:",omitempty"`
-	Backoff jsonDuration   `json:",omitempty"`
+	Max        jsonMaxRetries `json:",omitempty"`
+	Backoff    jsonDuration   `json:",omitempty"`
+	MaxBackoff jsonDuration   `json:",omitempty"`
+	Multiplier float64        `json:",omitempty"`
 }
 
 // proper JSON schema for webhook sink config:
@@ -225,8 +234,10 @@ type retryConfig struct {
 //	   "Frequency": ...,
 //   },
 //	 "Retry": {
-//	   "Max":     ...,
-//	   "Backoff": ...,
+//	   "Max":        ...,
+//	   "Backoff":    ...,
+//	   "MaxBackoff": ...,
+//	   "Multiplier": ...,
 //   }
 // }
 type webhookSinkConfig struct {
@@ -251,7 +262,7 @@ func (s *webhookSink) getWebhookSinkConfig(
 
 	// don't support negative values
 	if cfg.Flush.Messages < 0 || cfg.Flush.Bytes < 0 || cfg.Flush.Frequency < 0 ||
-		cfg.Retry.Max < 0 || cfg.Retry.Backoff < 0 {
+		cfg.Retry.Max < 0 || cfg.Retry.Backoff < 0 || cfg.Retry.MaxBackoff < 0 || cfg.Retry.Multiplier < 0 {
 		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, all config values must be non-negative", changefeedbase.OptWebhookSinkConfig)
 	}
 
@@ -260,8 +271,24 @@ func (s *webhookSink) getWebhookSinkConfig(
 		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, flush frequency is not set, messages may never be sent", changefeedbase.OptWebhookSinkConfig)
 	}
 
+	// a multiplier below 1 would shrink the backoff between retries
+	if cfg.Retry.Multiplier != 0 && cfg.Retry.Multiplier < 1 {
+		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, retry multiplier must be at least 1", changefeedbase.OptWebhookSinkConfig)
+	}
+
+	// the backoff is capped at MaxBackoff, so it can't be lower than the initial backoff
+	if cfg.Retry.MaxBackoff != 0 && cfg.Retry.MaxBackoff < cfg.Retry.Backoff {
+		return batchCfg, retryCfg, errors.Errorf("invalid option value %s, max retry backoff must not be less than the initial backoff", changefeedbase.OptWebhookSinkConfig)
+	}
+
 	retryCfg.MaxRetries = int(cfg.Retry.Max)
 	retryCfg.InitialBackoff = time.Duration(cfg.Retry.Backoff)
+	if cfg.Retry.MaxBackoff != 0 {
+		retryCfg.MaxBackoff = time.Duration(cfg.Retry.MaxBackoff)
+	}
+	if cfg.Retry.Multiplier != 0 {
+		retryCfg.Multiplier = cfg.Retry.Multiplier
+	}
 	return cfg.Flush, retryCfg, nil
 }
 
@@ -270,6 +297,7 @@ func makeWebhookSink(
 	u sinkURL,
 	encodingOpts changefeedbase.EncodingOptions,
 	opts changefeedbase.WebhookSinkOptions,
+	settings *cluster.Settings,
 	parallelism int,
 	source timeutil.TimeSource,
 	mb metricsRecorderBuilder,
@@ -302,6 +330,15 @@ func makeWebhookSink(
 		return nil, errors.Errorf(`this sink requires the WITH %s option`, changefeedbase.OptTopicInValue)
 	}
 
+	var compression string
+	if codec := encodingOpts.Compression; codec != "" {
+		if strings.EqualFold(codec, "gzip") {
+			compression = sinkCompressionGzip
+		} else {
+			return nil, errors.Errorf(`unsupported compression codec %q`, codec)
+		}
+	}
+
 	var connTimeout time.Duration
 	if opts.ClientTimeout != nil {
 		connTimeout = *opts.ClientTimeout
@@ -317,6 +354,7 @@ func makeWebhookSink(
 		ts:          source,
 		metrics:     mb(requiresResourceAccounting),
 		format:      encodingOpts.Format,
+		compression: compression,
 	}
 
 	var err error
@@ -325,6 +363,11 @@ func makeWebhookSink(
 		return nil, errors.Wrapf(err, "error processing option %s", changefeedbase.OptWebhookSinkConfig)
 	}
 
+	sink.headers, err = getWebhookSinkHeaders(u, settings)
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO(yevgeniy): Establish HTTP connection in Dial().
 	sink.client, err = makeWebhookClient(u, connTimeout)
 	if err != nil {
@@ -341,12 +384,41 @@ func makeWebhookSink(
 	params.Del(changefeedbase.SinkParamCACert)
 	params.Del(changefeedbase.SinkParamClientCert)
 	params.Del(changefeedbase.SinkParamClientKey)
+	params.Del(changefeedbase.SinkParamWebhookHeaders)
 	sinkURLParsed.RawQuery = params.Encode()
 	sink.url = sinkURL{URL: sinkURLParsed}
 
 	return sink, nil
 }
 
+// getWebhookSinkHeaders returns the custom headers to add to the requests of a
+// webhook sink. Headers may be configured for all webhook sinks with the
+// changefeed.webhook.headers cluster setting, and for a single sink with the
+// custom_headers parameter of its URI, which also makes them part of an
+// external connection created for the URI. Headers set in the URI take
+// precedence.
+func getWebhookSinkHeaders(u sinkURL, settings *cluster.Settings) (map[string]string, error) {
+	headers := make(map[string]string)
+	if settings != nil {
+		clusterHeaders, err := changefeedbase.ParseWebhookSinkHeaders(
+			changefeedbase.WebhookSinkHeaders.Get(&settings.SV))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cluster setting %s", changefeedbase.WebhookSinkHeaders.Key())
+		}
+		for k, v := range clusterHeaders {
+			headers[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	uriHeaders, err := changefeedbase.ParseWebhookSinkHeaders(u.Query().Get(changefeedbase.SinkParamWebhookHeaders))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid sink parameter %s", changefeedbase.SinkParamWebhookHeaders)
+	}
+	for k, v := range uriHeaders {
+		headers[http.CanonicalHeaderKey(k)] = v
+	}
+	return headers, nil
+}
+
 func makeWebhookClient(u sinkURL, timeout time.Duration) (*httputil.Client, error) {
 	client := &httputil.Client{
 		Client: &http.Client{
@@ -599,17 +671,40 @@ func (s *webhookSink) workerLoop(workerIndex int) {
 				s.exitWorkersWithError(err)
 				return
 			}
-			if err := s.sendMessageWithRetries(s.workerCtx, encoded.data); err != nil {
+			reqBody, compressedBytes, err := s.compress(encoded.data)
+			if err != nil {
+				s.exitWorkersWithError(err)
+				return
+			}
+			if err := s.sendMessageWithRetries(s.workerCtx, reqBody); err != nil {
 				s.exitWorkersWithError(err)
 				return
 			}
 			encoded.alloc.Release(s.workerCtx)
 			s.metrics.recordEmittedBatch(
-				encoded.emitTime, len(msgs), encoded.mvcc, len(encoded.data), sinkDoesNotCompress)
+				encoded.emitTime, len(msgs), encoded.mvcc, len(encoded.data), compressedBytes)
 		}
 	}
 }
 
+// compress returns the request body for the given payload, compressed if the
+// sink was configured with a compression codec, along with the compressed size
+// to record in metrics (sinkDoesNotCompress if the payload isn't compressed).
+func (s *webhookSink) compress(data []byte) ([]byte, int, error) {
+	if s.compression != sinkCompressionGzip {
+		return data, sinkDoesNotCompress, nil
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), buf.Len(), nil
+}
+
 func (s *webhookSink) sendMessageWithRetries(ctx context.Context, reqBody []byte) error {
 	requestFunc := func() error {
 		return s.sendMessage(ctx, reqBody)
@@ -622,12 +717,18 @@ func (s *webhookSink) sendMessage(ctx context.Context, reqBody []byte) error {
 	if err != nil {
 		return err
 	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
 	switch s.format {
 	case changefeedbase.OptFormatJSON:
 		req.Header.Set("Content-Type", applicationTypeJSON)
 	case changefeedbase.OptFormatCSV:
 		req.Header.Set("Content-Type", applicationTypeCSV)
 	}
+	if s.compression == sinkCompressionGzip {
+		req.Header.Set(contentEncodingHeader, "gzip")
+	}
 
 	if s.authHeader != "" {
 		req.Header.Set(authorizationHeader, s.authHeader)
@@ -734,7 +835,11 @@ func (s *webhookSink) EmitResolvedTimestamp(
 	// do worker logic directly here instead (there's no point using workers for
 	// resolved timestamps since there are no keys and everything must be
 	// in order)
-	if err := s.sendMessageWithRetries(ctx, payload); err != nil {
+	reqBody, _, err := s.compress(payload)
+	if err != nil {
+		return err
+	}
+	if err := s.sendMessageWithRetries(ctx, reqBody); err != nil {
 		s.exitWorkersWithError(err)
 		return err
 	}
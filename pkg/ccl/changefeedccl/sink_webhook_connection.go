@@ -0,0 +1,61 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/connectionpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+func parseAndValidateWebhookSinkURI(
+	ctx context.Context, _ interface{}, _ username.SQLUsername, uri *url.URL,
+) (externalconn.ExternalConnection, error) {
+	// Validate the webhook URI by creating a webhook sink and throwing it away.
+	// The encoding options are the ones the sink requires; the options of the
+	// changefeed using the connection are validated when it is created.
+	encodingOpts := changefeedbase.EncodingOptions{
+		Format:       changefeedbase.OptFormatJSON,
+		Envelope:     changefeedbase.OptEnvelopeWrapped,
+		KeyInValue:   true,
+		TopicInValue: true,
+	}
+	// makeWebhookSink modifies the scheme of the URL it's given.
+	sinkURI := *uri
+	s, err := makeWebhookSink(ctx, sinkURL{URL: &sinkURI}, encodingOpts,
+		changefeedbase.WebhookSinkOptions{}, nil, 1, timeutil.DefaultTimeSource{}, nilMetricsRecorderBuilder)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid webhook URI")
+	}
+	// The sink was never dialed, so only its context needs to be released.
+	s.(*webhookSink).exitWorkers()
+
+	connDetails := connectionpb.ConnectionDetails{
+		Provider: connectionpb.ConnectionProvider_webhook,
+		Details: &connectionpb.ConnectionDetails_SimpleURI{
+			SimpleURI: &connectionpb.SimpleURI{
+				URI: uri.String(),
+			},
+		},
+	}
+	return externalconn.NewExternalConnection(connDetails), nil
+}
+
+func init() {
+	externalconn.RegisterConnectionDetailsFromURIFactory(changefeedbase.SinkSchemeWebhookHTTPS,
+		parseAndValidateWebhookSinkURI)
+	externalconn.RegisterConnectionDetailsFromURIFactory(changefeedbase.SinkSchemeWebhookHTTP,
+		parseAndValidateWebhookSinkURI)
+}
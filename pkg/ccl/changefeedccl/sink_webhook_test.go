@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -83,7 +84,7 @@ func setupWebhookSinkWithDetails(
 	if err != nil {
 		return nil, err
 	}
-	sinkSrc, err := makeWebhookSink(ctx, sinkURL{URL: u}, encodingOpts, sinkOpts, parallelism, source, nilMetricsRecorderBuilder)
+	sinkSrc, err := makeWebhookSink(ctx, sinkURL{URL: u}, encodingOpts, sinkOpts, nil, parallelism, source, nilMetricsRecorderBuilder)
 	if err != nil {
 		return nil, err
 	}
@@ -690,3 +691,103 @@ func TestWebhookSinkShutsDownOnError(t *testing.T) {
 		webhookSinkTestfn(i)
 	}
 }
+
+func TestWebhookSinkRetryConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := &webhookSink{}
+	_, retryCfg, err := s.getWebhookSinkConfig(`{"Retry":{"Max": 10, "Backoff": "1s", "MaxBackoff": "1m", "Multiplier": 3}}`)
+	require.NoError(t, err)
+	require.Equal(t, 10, retryCfg.MaxRetries)
+	require.Equal(t, time.Second, retryCfg.InitialBackoff)
+	require.Equal(t, time.Minute, retryCfg.MaxBackoff)
+	require.Equal(t, 3.0, retryCfg.Multiplier)
+
+	// unset backoff controls keep their defaults
+	_, retryCfg, err = s.getWebhookSinkConfig(`{"Retry":{"Backoff": "1s"}}`)
+	require.NoError(t, err)
+	require.Equal(t, defaultRetryConfig().MaxBackoff, retryCfg.MaxBackoff)
+	require.Equal(t, defaultRetryConfig().Multiplier, retryCfg.Multiplier)
+}
+
+func TestWebhookSinkCompressionAndHeaders(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	webhookSinkTestfn := func(parallelism int) {
+		ctx := context.Background()
+		cert, certEncoded, err := cdctest.NewCACertBase64Encoded()
+		require.NoError(t, err)
+		sinkDest, err := cdctest.StartMockWebhookSink(cert)
+		require.NoError(t, err)
+
+		st := cluster.MakeTestingClusterSettings()
+		changefeedbase.WebhookSinkHeaders.Override(ctx, &st.SV, `{"X-Cluster": "a", "X-Shared": "cluster"}`)
+
+		sinkDestHost, err := url.Parse(sinkDest.URL())
+		require.NoError(t, err)
+
+		// headers set by the sink itself can't be overridden
+		params := sinkDestHost.Query()
+		params.Set(changefeedbase.SinkParamCACert, certEncoded)
+		params.Set(changefeedbase.SinkParamWebhookHeaders, `{"X-Sink": "b", "x-shared": "sink", "Content-Type": "text/plain"}`)
+		sinkDestHost.RawQuery = params.Encode()
+
+		u, err := url.Parse(fmt.Sprintf("webhook-%s", sinkDestHost.String()))
+		require.NoError(t, err)
+
+		opts := getGenericWebhookSinkOptions(struct {
+			key   string
+			value string
+		}{
+			key:   changefeedbase.OptCompression,
+			value: "gzip",
+		})
+		encodingOpts, err := opts.GetEncodingOptions()
+		require.NoError(t, err)
+		sinkOpts, err := opts.GetWebhookSinkOptions()
+		require.NoError(t, err)
+
+		sinkSrc, err := makeWebhookSink(ctx, sinkURL{URL: u}, encodingOpts, sinkOpts, st,
+			parallelism, timeutil.DefaultTimeSource{}, nilMetricsRecorderBuilder)
+		require.NoError(t, err)
+		require.NoError(t, sinkSrc.Dial())
+
+		// the mock sink decompresses gzipped payloads
+		testSendAndReceiveRows(t, sinkSrc, sinkDest)
+
+		headers := sinkDest.LatestHeaders()
+		require.Equal(t, "gzip", headers.Get("Content-Encoding"))
+		require.Equal(t, applicationTypeJSON, headers.Get("Content-Type"))
+		require.Equal(t, "a", headers.Get("X-Cluster"))
+		require.Equal(t, "b", headers.Get("X-Sink"))
+		require.Equal(t, "sink", headers.Get("X-Shared"))
+
+		require.NoError(t, sinkSrc.Close())
+		sinkDest.Close()
+	}
+
+	// run tests with parallelism from 1-4
+	for i := 1; i <= 4; i++ {
+		webhookSinkTestfn(i)
+	}
+}
+
+func TestWebhookSinkReservedHeaders(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, headers := range []string{
+		`{"Host": "example.com"}`,
+		`{"host": "example.com"}`,
+		`{"Connection": "close"}`,
+		`{"transfer-encoding": "chunked"}`,
+		`{"X-Custom": "a", "Upgrade": "websocket"}`,
+	} {
+		_, err := changefeedbase.ParseWebhookSinkHeaders(headers)
+		require.Error(t, err, headers)
+		require.Contains(t, err.Error(), "can't be set")
+	}
+
+	headers, err := changefeedbase.ParseWebhookSinkHeaders(`{"Authorization": "Bearer a", "X-Custom": "b"}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Authorization": "Bearer a", "X-Custom": "b"}, headers)
+}
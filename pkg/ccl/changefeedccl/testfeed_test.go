@@ -1713,7 +1713,8 @@ func (p *fakePubsubClient) sendMessageToAllTopics(m []byte) error {
 	return nil
 }
 
-func (p *fakePubsubClient) flushTopics() {
+func (p *fakePubsubClient) flushTopics() error {
+	return nil
 }
 
 type fakePubsubSink struct {
@@ -1646,6 +1646,13 @@ func TestTenantLogic_show_create(
 	runLogicTest(t, "show_create")
 }
 
+func TestTenantLogic_show_create_all_objects(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "show_create_all_objects")
+}
+
 func TestTenantLogic_show_create_all_schemas(
 	t *testing.T,
 ) {
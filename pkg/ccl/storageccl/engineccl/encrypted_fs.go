@@ -44,6 +44,10 @@ import (
 // - The store-FS is used only for storing the key file for the generated keys. It is used by
 //   the DataKeyManager. These keys are rotated periodically in a simple manner -- a new
 //   active key is generated for future file writes. Existing files are not affected.
+// - The store keys can be reloaded and the data key rotated on demand, while the store is
+//   running, by the encryptionKeyRotator. The sstables encrypted with previous data keys
+//   are then re-encrypted with the active data key by compacting them (see
+//   Pebble.RewriteEncryptedFiles).
 //
 // The data-FS and store-FS both use a common implementation. They consume:
 // - the FS they are wrapping: it is always the base-FS in our case, but it does not matter.
@@ -248,8 +252,12 @@ type encryptionStatsHandler struct {
 
 func (e *encryptionStatsHandler) GetEncryptionStatus() ([]byte, error) {
 	var s enginepbccl.EncryptionStatus
-	if e.storeKM.activeKey != nil {
-		s.ActiveStoreKey = e.storeKM.activeKey.Info
+	storeKey, err := e.storeKM.ActiveKey(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	if storeKey != nil {
+		s.ActiveStoreKey = storeKey.Info
 	}
 	k, err := e.dataKM.ActiveKey(context.TODO())
 	if err != nil {
@@ -278,8 +286,9 @@ func (e *encryptionStatsHandler) GetActiveDataKeyID() (string, error) {
 }
 
 func (e *encryptionStatsHandler) GetActiveStoreKeyType() int32 {
-	if e.storeKM.activeKey != nil {
-		return int32(e.storeKM.activeKey.Info.EncryptionType)
+	// StoreKeyManager.ActiveKey never returns an error.
+	if k, _ := e.storeKM.ActiveKey(context.TODO()); k != nil {
+		return int32(k.Info.EncryptionType)
 	}
 	return int32(enginepbccl.EncryptionType_Plaintext)
 }
@@ -292,6 +301,30 @@ func (e *encryptionStatsHandler) GetKeyIDFromSettings(settings []byte) (string,
 	return s.KeyId, nil
 }
 
+// encryptionKeyRotator implements storage.EncryptionKeyRotator.
+type encryptionKeyRotator struct {
+	storeKM *StoreKeyManager
+	dataKM  *DataKeyManager
+}
+
+// RotateKeys implements storage.EncryptionKeyRotator.
+func (r *encryptionKeyRotator) RotateKeys(ctx context.Context) error {
+	changed, err := r.storeKM.Reload(ctx)
+	if err != nil {
+		return err
+	}
+	if changed {
+		// Setting the new active store key also rotates the active data key,
+		// and rewrites the data keys registry with the new store key.
+		key, err := r.storeKM.ActiveKey(ctx)
+		if err != nil {
+			return err
+		}
+		return r.dataKM.SetActiveStoreKeyInfo(ctx, key.Info)
+	}
+	return r.dataKM.RotateActiveKey(ctx)
+}
+
 // init initializes function hooks used in non-CCL code.
 func init() {
 	storage.NewEncryptedEnvFunc = newEncryptedEnv
@@ -364,6 +397,10 @@ func newEncryptedEnv(
 			storeKM: storeKeyManager,
 			dataKM:  dataKeyManager,
 		},
+		KeyRotator: &encryptionKeyRotator{
+			storeKM: storeKeyManager,
+			dataKM:  dataKeyManager,
+		},
 	}, nil
 }
 
@@ -416,6 +416,117 @@ func TestPebbleEncryption2(t *testing.T) {
 	addKeyAndValidate("d", "d", "plain", "16v2.key")
 }
 
+func TestPebbleEncryptionKeyRotation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	memFS := vfs.NewMem()
+	firstKeyFile128 := "111111111111111111111111111111111234567890123456"
+	secondKeyFile128 := "222222222222222222222222222222221234567890123456"
+	thirdKeyFile128 := "333333333333333333333333333333331234567890123456"
+	writeToFile(t, memFS, "active.key", []byte(firstKeyFile128))
+	writeToFile(t, memFS, "old.key", []byte(thirdKeyFile128))
+	encOptions := baseccl.EncryptionOptions{
+		KeySource: baseccl.EncryptionKeySource_KeyFiles,
+		KeyFiles: &baseccl.EncryptionKeyFiles{
+			CurrentKey: "active.key",
+			OldKey:     "old.key",
+		},
+		DataKeyRotationPeriod: 1000,
+	}
+	encOptionsBytes, err := protoutil.Marshal(&encOptions)
+	require.NoError(t, err)
+
+	open := func() *storage.Pebble {
+		opts := storage.DefaultPebbleOptions()
+		opts.FS = memFS
+		opts.Cache = pebble.NewCache(1 << 20)
+		defer opts.Cache.Unref()
+		db, err := storage.NewPebble(ctx, storage.PebbleConfig{
+			StorageConfig: base.StorageConfig{
+				Attrs:             roachpb.Attributes{},
+				MaxSize:           512 << 20,
+				Settings:          cluster.MakeTestingClusterSettings(),
+				UseFileRegistry:   true,
+				EncryptionOptions: encOptionsBytes,
+			},
+			Opts: opts,
+		})
+		require.NoError(t, err)
+		return db
+	}
+	activeStoreKeyID := func(db *storage.Pebble) string {
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		var s enginepbccl.EncryptionStatus
+		require.NoError(t, protoutil.Unmarshal(stats.EncryptionStatus, &s))
+		return s.ActiveStoreKey.KeyId
+	}
+	checkValue := func(db *storage.Pebble) {
+		val, err := db.MVCCGet(storage.MVCCKey{Key: roachpb.Key("a")})
+		require.NoError(t, err)
+		require.Equal(t, "a", string(val))
+	}
+
+	writeValue := func(db *storage.Pebble) {
+		batch := db.NewUnindexedBatch(true /* writeOnly */)
+		require.NoError(t, batch.PutUnversioned(roachpb.Key("a"), []byte("a")))
+		require.NoError(t, batch.Commit(true))
+		require.NoError(t, db.Flush())
+	}
+
+	db := open()
+	writeValue(db)
+	require.Equal(t, "3131313131313131313131313131313131313131313131313131313131313131", activeStoreKeyID(db))
+
+	// All the sstables are encrypted with the active data key.
+	rewritten, remaining, err := db.RewriteEncryptedFiles(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, rewritten)
+	require.Equal(t, 0, remaining)
+
+	// Rotating only the data key leaves the sstable to be rewritten.
+	require.NoError(t, db.RotateEncryptionKeys(ctx))
+	rewritten, remaining, err = db.RewriteEncryptedFiles(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, rewritten)
+	require.Equal(t, 1, remaining)
+	// The compaction moves the lone sstable to the bottommost level without
+	// rewriting it.
+	rewritten, remaining, err = db.RewriteEncryptedFiles(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, rewritten)
+	require.Equal(t, 1, remaining)
+	// Once it overlaps with newer data, the compaction rewrites it.
+	writeValue(db)
+	rewritten, remaining, err = db.RewriteEncryptedFiles(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, rewritten)
+	require.Equal(t, 0, remaining)
+	checkValue(db)
+
+	// Rotate the store key. The previously active key must become the old key.
+	writeToFile(t, memFS, "active.key", []byte(secondKeyFile128))
+	require.Regexp(t, "previously active store key .* is neither the active nor the old key",
+		db.RotateEncryptionKeys(ctx))
+	writeToFile(t, memFS, "old.key", []byte(firstKeyFile128))
+	require.NoError(t, db.RotateEncryptionKeys(ctx))
+	require.Equal(t, "3232323232323232323232323232323232323232323232323232323232323232", activeStoreKeyID(db))
+	writeValue(db)
+	rewritten, remaining, err = db.RewriteEncryptedFiles(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, rewritten)
+	require.Equal(t, 0, remaining)
+	checkValue(db)
+	db.Close()
+
+	// The store can be reopened with the new key files.
+	db = open()
+	defer db.Close()
+	require.Equal(t, "3232323232323232323232323232323232323232323232323232323232323232", activeStoreKeyID(db))
+	checkValue(db)
+}
+
 func TestCanRegistryElide(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -78,39 +78,86 @@ type StoreKeyManager struct {
 	activeKeyFilename string
 	oldKeyFilename    string
 
-	// Implementation. Both are not nil after a successful call to Load().
-	activeKey *enginepbccl.SecretKey
-	oldKey    *enginepbccl.SecretKey
+	// Implementation.
+	mu struct {
+		syncutil.RWMutex
+		// Both are not nil after a successful call to Load().
+		activeKey *enginepbccl.SecretKey
+		oldKey    *enginepbccl.SecretKey
+	}
 }
 
 // Load must be called before calling other functions.
 func (m *StoreKeyManager) Load(ctx context.Context) error {
-	var err error
-	m.activeKey, err = loadKeyFromFile(m.fs, m.activeKeyFilename)
-	if err != nil {
-		return err
-	}
-	m.oldKey, err = loadKeyFromFile(m.fs, m.oldKeyFilename)
+	activeKey, oldKey, err := m.loadKeys()
 	if err != nil {
 		return err
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.activeKey, m.mu.oldKey = activeKey, oldKey
 	log.Infof(ctx, "loaded active store key: %s, old store key: %s",
-		proto.CompactTextString(m.activeKey.Info), proto.CompactTextString(m.oldKey.Info))
+		proto.CompactTextString(activeKey.Info), proto.CompactTextString(oldKey.Info))
 	return nil
 }
 
+// Reload reloads the keys from the key files, which allows the user to rotate
+// the active store key without restarting the node by writing the new key to
+// the active key file and the previously active key to the old key file. The
+// previously active key must remain one of the keys, as the files encrypted
+// with it are only rewritten later on. Reload returns whether the active key
+// changed.
+func (m *StoreKeyManager) Reload(ctx context.Context) (changed bool, _ error) {
+	activeKey, oldKey, err := m.loadKeys()
+	if err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prevID := m.mu.activeKey.Info.KeyId
+	if activeKey.Info.KeyId == prevID {
+		// Nothing to rotate. The old key isn't replaced either, as files may
+		// still be encrypted with it.
+		return false, nil
+	}
+	if prevID != plainKeyID && oldKey.Info.KeyId != prevID {
+		return false, fmt.Errorf("previously active store key %s is neither the active nor the old key "+
+			"in the key files", prevID)
+	}
+	m.mu.activeKey, m.mu.oldKey = activeKey, oldKey
+	log.Infof(ctx, "reloaded active store key: %s, old store key: %s",
+		proto.CompactTextString(activeKey.Info), proto.CompactTextString(oldKey.Info))
+	return true, nil
+}
+
+func (m *StoreKeyManager) loadKeys() (activeKey, oldKey *enginepbccl.SecretKey, err error) {
+	activeKey, err = loadKeyFromFile(m.fs, m.activeKeyFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+	oldKey, err = loadKeyFromFile(m.fs, m.oldKeyFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return activeKey, oldKey, nil
+}
+
 // ActiveKey implements PebbleKeyManager.ActiveKey.
 func (m *StoreKeyManager) ActiveKey(ctx context.Context) (*enginepbccl.SecretKey, error) {
-	return m.activeKey, nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mu.activeKey, nil
 }
 
 // GetKey implements PebbleKeyManager.GetKey.
 func (m *StoreKeyManager) GetKey(id string) (*enginepbccl.SecretKey, error) {
-	if m.activeKey.Info.KeyId == id {
-		return m.activeKey, nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.mu.activeKey.Info.KeyId == id {
+		return m.mu.activeKey, nil
 	}
-	if m.oldKey.Info.KeyId == id {
-		return m.oldKey, nil
+	if m.mu.oldKey.Info.KeyId == id {
+		return m.mu.oldKey, nil
 	}
 	return nil, fmt.Errorf("store key ID %s was not found", id)
 }
@@ -334,6 +381,24 @@ func (m *DataKeyManager) SetActiveStoreKeyInfo(
 	return nil
 }
 
+// RotateActiveKey generates a new active data key, regardless of the age of
+// the current one. Files written from then on are encrypted with the new key.
+//
+// This function should not be called for a read only store.
+func (m *DataKeyManager) RotateActiveKey(ctx context.Context) error {
+	if m.readOnly {
+		return errors.New("read only")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.mu.rotationEnabled {
+		return errors.New("data key rotation is not enabled")
+	}
+	keyRegistry := makeRegistryProto()
+	proto.Merge(keyRegistry, m.mu.keyRegistry)
+	return m.rotateDataKeyAndWrite(ctx, keyRegistry)
+}
+
 func (m *DataKeyManager) getScrubbedRegistry() *enginepbccl.DataKeysRegistry {
 	m.mu.Lock()
 	defer m.mu.Unlock()
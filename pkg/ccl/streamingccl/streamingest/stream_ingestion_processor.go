@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -97,14 +98,22 @@ type rangeKeyBatcher struct {
 	minTimestamp hlc.Timestamp
 	// Data size of the current batch.
 	dataSize int
+
+	// keyRewrites, if set, are sent along with the SSTs so that the keys of the
+	// range keys are rewritten when the SSTs are ingested. The buffered range
+	// keys are then in the keyspace of the source tenant.
+	keyRewrites []roachpb.KeyPrefixRewrite
 }
 
-func newRangeKeyBatcher(ctx context.Context, cs *cluster.Settings, db *kv.DB) *rangeKeyBatcher {
+func newRangeKeyBatcher(
+	ctx context.Context, cs *cluster.Settings, db *kv.DB, keyRewrites []roachpb.KeyPrefixRewrite,
+) *rangeKeyBatcher {
 	batcher := &rangeKeyBatcher{
 		db:              db,
 		minTimestamp:    hlc.MaxTimestamp,
 		dataSize:        0,
 		rangeKeySSTFile: &storage.MemFile{},
+		keyRewrites:     keyRewrites,
 	}
 	batcher.rangeKeySSTWriterMaker = func() *storage.SSTWriter {
 		w := storage.MakeIngestionSSTWriter(ctx, cs, batcher.rangeKeySSTFile)
@@ -286,7 +295,17 @@ func (sip *streamIngestionProcessor) Start(ctx context.Context) {
 		return
 	}
 
-	sip.rangeBatcher = newRangeKeyBatcher(ctx, evalCtx.Settings, db)
+	// If the range keys are moved to another tenant, have them rewritten when
+	// they are ingested rather than rekeying them one by one.
+	var keyRewrites []roachpb.KeyPrefixRewrite
+	if sip.rewriteToDiffKey &&
+		evalCtx.Settings.Version.IsActive(ctx, clusterversion.AddSSTableKeyRewrites) {
+		keyRewrites = []roachpb.KeyPrefixRewrite{{
+			OldPrefix: keys.MakeTenantPrefix(sip.spec.TenantRekey.OldID),
+			NewPrefix: keys.MakeTenantPrefix(sip.spec.TenantRekey.NewID),
+		}}
+	}
+	sip.rangeBatcher = newRangeKeyBatcher(ctx, evalCtx.Settings, db, keyRewrites)
 
 	// Start a poller that checks if the stream ingestion job has been signaled to
 	// cutover.
@@ -655,14 +674,17 @@ func (sip *streamIngestionProcessor) bufferRangeKeyVal(
 	_, sp := tracing.ChildSpan(sip.Ctx, "stream-ingestion-buffer-range-key")
 	defer sp.Finish()
 
-	var err error
-	rangeKeyVal.RangeKey.StartKey, err = sip.rekey(rangeKeyVal.RangeKey.StartKey)
-	if err != nil {
-		return err
-	}
-	rangeKeyVal.RangeKey.EndKey, err = sip.rekey(rangeKeyVal.RangeKey.EndKey)
-	if err != nil {
-		return err
+	// Range keys that are rewritten during ingestion are buffered as is.
+	if len(sip.rangeBatcher.keyRewrites) == 0 {
+		var err error
+		rangeKeyVal.RangeKey.StartKey, err = sip.rekey(rangeKeyVal.RangeKey.StartKey)
+		if err != nil {
+			return err
+		}
+		rangeKeyVal.RangeKey.EndKey, err = sip.rekey(rangeKeyVal.RangeKey.EndKey)
+		if err != nil {
+			return err
+		}
 	}
 	sip.rangeBatcher.buffer(rangeKeyVal)
 	return nil
@@ -770,6 +792,10 @@ func (r *rangeKeyBatcher) flush(ctx context.Context) error {
 		return err
 	}
 
+	if len(r.keyRewrites) > 0 {
+		return r.flushWithKeyRewrites(ctx, start, end)
+	}
+
 	_, _, err := r.db.AddSSTable(ctx, start, end, r.rangeKeySSTFile.Data(),
 		false /* disallowConflicts */, false, /* disallowShadowing */
 		hlc.Timestamp{}, nil /* stats */, false, /* ingestAsWrites */
@@ -777,6 +803,27 @@ func (r *rangeKeyBatcher) flush(ctx context.Context) error {
 	return err
 }
 
+// flushWithKeyRewrites ingests the SST of the current batch, which spans
+// [start, end) in the keyspace of the source tenant, and has its keys
+// rewritten during ingestion.
+func (r *rangeKeyBatcher) flushWithKeyRewrites(ctx context.Context, start, end roachpb.Key) error {
+	reqStart, err := storage.RewriteKeyPrefix(start, r.keyRewrites, false /* isEndKey */)
+	if err != nil {
+		return err
+	}
+	reqEnd, err := storage.RewriteKeyPrefix(end, r.keyRewrites, true /* isEndKey */)
+	if err != nil {
+		return err
+	}
+	b := &kv.Batch{Header: roachpb.Header{Timestamp: r.db.Clock().Now()}}
+	b.AddRawRequest(&roachpb.AddSSTableRequest{
+		RequestHeader:     roachpb.RequestHeader{Key: reqStart, EndKey: reqEnd},
+		Data:              r.rangeKeySSTFile.Data(),
+		KeyPrefixRewrites: r.keyRewrites,
+	})
+	return r.db.Run(ctx, b)
+}
+
 // Reset all the states inside the batcher and needs to called after flush
 // for further uses.
 func (r *rangeKeyBatcher) reset() {
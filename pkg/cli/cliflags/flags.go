@@ -1189,6 +1189,12 @@ in the history of the cluster.`,
 as target of the decommissioning or recommissioning command.`,
 	}
 
+	NodeDecommissionDryRun = FlagInfo{
+		Name: "dry-run",
+		Description: `Check whether the target nodes can be decommissioned and list
+the ranges that would prevent it, without decommissioning them.`,
+	}
+
 	NodeDrainSelf = FlagInfo{
 		Name: "self",
 		Description: `Use the node ID of the node connected to via --host
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
@@ -161,6 +162,24 @@ Info:
 [cockroach@node5 ~]$ cockroach debug recover apply-plan --store=/mnt/cockroach-data-1 --store=/mnt/cockroach-data-2 recover-plan.json
 
 Now the cluster could be started again.
+
+Half-online recovery:
+
+If the surviving nodes are still running, the steps above could be performed
+without stopping the cluster by omitting --store flags and connecting to any
+of the surviving nodes using --host instead:
+
+1. Run 'cockroach debug recover collect-info --host=<node>' to collect
+replication state from all nodes known to the cluster at once.
+
+2. Run 'cockroach debug recover make-plan --host=<node>' or provide it the
+file generated on step 1.
+
+3. Run 'cockroach debug recover apply-plan --host=<node> <plan-file>' to stage
+the plan on all nodes which have replicas updated by it.
+
+4. Restart the nodes on which the plan was staged. The plan is applied to the
+stores of a node when it starts, before the node joins the cluster.
 `,
 	RunE: UsageAndErr,
 }
@@ -179,6 +198,10 @@ var debugRecoverCollectInfoCmd = &cobra.Command{
 Collect information about replicas by reading data from underlying stores. Store
 locations must be provided using --store flags.
 
+If no --store flags are provided, information is collected from all nodes of
+a running cluster using the node given by --host and the other connection
+flags.
+
 Collected information is written to a destination file if file name is provided,
 or to stdout.
 
@@ -200,17 +223,22 @@ func runDebugDeadReplicaCollect(cmd *cobra.Command, args []string) error {
 	stopper := stop.NewStopper()
 	defer stopper.Stop(cmd.Context())
 
-	var stores []storage.Engine
-	for _, storeSpec := range debugRecoverCollectInfoOpts.Stores.Specs {
-		db, err := OpenEngine(storeSpec.Path, stopper, storage.MustExist, storage.ReadOnly)
-		if err != nil {
-			return errors.Wrapf(err, "failed to open store at path %q, ensure that store path is "+
-				"correct and that it is not used by another process", storeSpec.Path)
+	var replicaInfo loqrecoverypb.NodeReplicaInfo
+	var err error
+	if len(debugRecoverCollectInfoOpts.Stores.Specs) == 0 {
+		replicaInfo, err = collectRemoteReplicaInfo(cmd.Context())
+	} else {
+		var stores []storage.Engine
+		for _, storeSpec := range debugRecoverCollectInfoOpts.Stores.Specs {
+			db, err := OpenEngine(storeSpec.Path, stopper, storage.MustExist, storage.ReadOnly)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open store at path %q, ensure that store path is "+
+					"correct and that it is not used by another process", storeSpec.Path)
+			}
+			stores = append(stores, db)
 		}
-		stores = append(stores, db)
+		replicaInfo, err = loqrecovery.CollectReplicaInfo(cmd.Context(), stores)
 	}
-
-	replicaInfo, err := loqrecovery.CollectReplicaInfo(cmd.Context(), stores)
 	if err != nil {
 		return err
 	}
@@ -241,6 +269,26 @@ func runDebugDeadReplicaCollect(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// collectRemoteReplicaInfo collects replica info from all nodes of a running
+// cluster. Nodes that fail to respond are reported, but don't fail collection
+// as they are likely the nodes that are lost.
+func collectRemoteReplicaInfo(ctx context.Context) (loqrecoverypb.NodeReplicaInfo, error) {
+	c, finish, err := getAdminClient(ctx, serverCfg)
+	if err != nil {
+		return loqrecoverypb.NodeReplicaInfo{}, err
+	}
+	defer finish()
+
+	resp, err := c.RecoveryCollectReplicaInfo(ctx, &serverpb.RecoveryCollectReplicaInfoRequest{})
+	if err != nil {
+		return loqrecoverypb.NodeReplicaInfo{}, errors.Wrap(err, "failed to collect replica info from cluster")
+	}
+	for _, e := range resp.Errors {
+		_, _ = fmt.Fprintf(stderr, "Failed to collect replica info from node n%d: %s\n", e.NodeID, e.Error)
+	}
+	return loqrecoverypb.NodeReplicaInfo{Replicas: resp.Replicas}, nil
+}
+
 var debugRecoverPlanCmd = &cobra.Command{
 	Use:   "make-plan [replica-files]",
 	Short: "generate a plan to recover ranges that lost quorum",
@@ -252,11 +300,14 @@ surviving nodes of a cluster and make a decision which replicas should be surviv
 for the ranges where quorum was lost.
 Decision is then written into a file or stdout.
 
+If no files are provided, information is collected from all nodes of a running
+cluster using the node given by --host and the other connection flags.
+
 This command only creates a plan and doesn't change any data.'
 
 See debug recover command help for more details on how to use this command.
 `,
-	Args: cobra.MinimumNArgs(1),
+	Args: cobra.ArbitraryArgs,
 	RunE: runDebugPlanReplicaRemoval,
 }
 
@@ -268,9 +319,18 @@ var debugRecoverPlanOpts struct {
 }
 
 func runDebugPlanReplicaRemoval(cmd *cobra.Command, args []string) error {
-	replicas, err := readReplicaInfoData(args)
-	if err != nil {
-		return err
+	var replicas []loqrecoverypb.NodeReplicaInfo
+	if len(args) == 0 {
+		replicaInfo, err := collectRemoteReplicaInfo(cmd.Context())
+		if err != nil {
+			return err
+		}
+		replicas = []loqrecoverypb.NodeReplicaInfo{replicaInfo}
+	} else {
+		var err error
+		if replicas, err = readReplicaInfoData(args); err != nil {
+			return err
+		}
 	}
 
 	var deadStoreIDs []roachpb.StoreID
@@ -361,6 +421,7 @@ Discarded live replicas: %d
 		_, _ = fmt.Fprintln(stderr, "Found no ranges in need of recovery, nothing to do.")
 		return nil
 	}
+	plan.PlanID = uuid.MakeV4()
 
 	var writer io.Writer = os.Stdout
 	if len(debugRecoverPlanOpts.outputFileName) > 0 {
@@ -385,7 +446,8 @@ Discarded live replicas: %d
 	}
 
 	_, _ = fmt.Fprint(stderr, "Plan created\nTo complete recovery, distribute the plan to the"+
-		" below nodes and invoke `debug recover apply-plan` on:\n")
+		" below nodes and invoke `debug recover apply-plan` on them, or stage it on a running"+
+		" cluster with `debug recover apply-plan --host`:\n")
 	for node, stores := range report.UpdatedNodes {
 		_, _ = fmt.Fprintf(stderr, "- node n%d, store(s) %s\n", node, joinStoreIDs(stores))
 	}
@@ -420,6 +482,10 @@ Apply changes to replicas in the provided stores using a plan.
 This command will read a plan and update replicas that belong to the
 given stores. Stores must be provided using --store flags. 
 
+If no --store flags are provided, the plan is staged on the nodes of a running
+cluster using the node given by --host and the other connection flags. Staged
+plans are applied when the nodes are restarted.
+
 See debug recover command help for more details on how to use this command.
 `,
 	Args: cobra.ExactArgs(1),
@@ -429,6 +495,7 @@ See debug recover command help for more details on how to use this command.
 var debugRecoverExecuteOpts struct {
 	Stores        base.StoreSpecList
 	confirmAction confirmActionFlag
+	force         bool
 }
 
 // runDebugExecuteRecoverPlan is using the following pattern when performing command
@@ -453,6 +520,10 @@ func runDebugExecuteRecoverPlan(cmd *cobra.Command, args []string) error {
 		return errors.Wrapf(err, "failed to unmarshal plan from file %q", planFile)
 	}
 
+	if len(debugRecoverExecuteOpts.Stores.Specs) == 0 {
+		return stageRecoveryPlan(cmd.Context(), nodeUpdates)
+	}
+
 	var localNodeID roachpb.NodeID
 	batches := make(map[roachpb.StoreID]storage.Batch)
 	for _, storeSpec := range debugRecoverExecuteOpts.Stores.Specs {
@@ -536,6 +607,66 @@ func runDebugExecuteRecoverPlan(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// stageRecoveryPlan stages the plan on all nodes of a running cluster that
+// have replicas updated by the plan, after confirming the action.
+func stageRecoveryPlan(ctx context.Context, plan loqrecoverypb.ReplicaUpdatePlan) error {
+	if plan.PlanID == uuid.Nil {
+		return errors.New("plan has no ID, plans created by older versions can only be applied " +
+			"to stopped nodes using --store flags")
+	}
+	for _, u := range plan.Updates {
+		_, _ = fmt.Fprintf(stderr, "Replica %s for range r%d:%s will be made the designated survivor.\n",
+			u.NewReplica, u.RangeID, u.StartKey.AsRKey())
+	}
+
+	switch debugRecoverExecuteOpts.confirmAction {
+	case prompt:
+		_, _ = fmt.Fprintf(stderr, "\nStage plan %s on the cluster [y/N] ", plan.PlanID)
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return errors.Wrap(err, "failed to read user input")
+		}
+		_, _ = fmt.Fprintf(stderr, "\n")
+		if len(line) < 1 || (line[0] != 'y' && line[0] != 'Y') {
+			_, _ = fmt.Fprint(stderr, "Aborted at user request\n")
+			return nil
+		}
+	case allYes:
+		// All actions enabled by default.
+	default:
+		return errors.New("Aborted by --confirm option")
+	}
+
+	c, finish, err := getAdminClient(ctx, serverCfg)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	resp, err := c.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{
+		Plan:  plan,
+		Force: debugRecoverExecuteOpts.force,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to stage recovery plan")
+	}
+	for _, e := range resp.Errors {
+		_, _ = fmt.Fprintf(stderr, "Failed to stage plan on node n%d: %s\n", e.NodeID, e.Error)
+	}
+	if len(resp.Errors) > 0 {
+		return errors.Newf("plan %s could not be staged on all nodes, fix the errors and retry",
+			plan.PlanID)
+	}
+	var nodeNames []string
+	for _, id := range resp.StagedNodeIDs {
+		nodeNames = append(nodeNames, fmt.Sprintf("n%d", id))
+	}
+	_, _ = fmt.Fprintf(stderr, "Plan %s staged on node(s) %s.\n"+
+		"To complete recovery, restart these nodes.\n", plan.PlanID, strings.Join(nodeNames, ", "))
+	return nil
+}
+
 func joinStoreIDs(storeIDs []roachpb.StoreID) string {
 	storeNames := make([]string, 0, len(storeIDs))
 	for _, id := range storeIDs {
@@ -553,4 +684,5 @@ func setDebugRecoverContextDefaults() {
 	debugRecoverPlanOpts.deadStoreIDs = nil
 	debugRecoverExecuteOpts.Stores.Specs = nil
 	debugRecoverExecuteOpts.confirmAction = prompt
+	debugRecoverExecuteOpts.force = false
 }
@@ -371,6 +371,9 @@ func runDecommissionNode(cmd *cobra.Command, args []string) error {
 	}
 
 	c := serverpb.NewAdminClient(conn)
+	if nodeCtx.nodeDecommissionDryRun {
+		return runDecommissionPreCheck(ctx, c, nodeIDs)
+	}
 	if err := runDecommissionNodeImpl(ctx, c, nodeCtx.nodeDecommissionWait, nodeIDs, localNodeID); err != nil {
 		cause := errors.UnwrapAll(err)
 		if s, ok := status.FromError(cause); ok && s.Code() == codes.NotFound {
@@ -630,6 +633,38 @@ func printDecommissionStatus(resp serverpb.DecommissionStatusResponse) error {
 		clisqlexec.NewRowSliceIter(decommissionResponseValueToRows(resp.Status), decommissionResponseAlignment()))
 }
 
+// decommissionPreCheckRangeReport is the maximum number of blocking ranges
+// listed by `node decommission --dry-run`.
+const decommissionPreCheckRangeReport = 100
+
+// runDecommissionPreCheck checks whether the given nodes can be decommissioned
+// and lists the ranges that would prevent it. It returns an error if any range
+// would.
+func runDecommissionPreCheck(
+	ctx context.Context, c serverpb.AdminClient, nodeIDs []roachpb.NodeID,
+) error {
+	resp, err := c.DecommissionPreCheck(ctx, &serverpb.DecommissionPreCheckRequest{
+		NodeIDs:        nodeIDs,
+		NumRangeReport: decommissionPreCheckRangeReport,
+	})
+	if err != nil {
+		return errors.Wrap(err, "while checking whether the nodes can be decommissioned")
+	}
+	for _, r := range resp.BlockingRanges {
+		fmt.Fprintf(os.Stdout, "r%d: %s\n", r.RangeID, r.Reason)
+	}
+	if omitted := resp.BlockingRangeCount - int64(len(resp.BlockingRanges)); omitted > 0 {
+		fmt.Fprintf(os.Stdout, "... and %d more blocking ranges\n", omitted)
+	}
+	if resp.BlockingRangeCount > 0 {
+		return errors.Newf("%d of %d ranges with replicas on the target nodes would block decommissioning",
+			resp.BlockingRangeCount, resp.CheckedRangeCount)
+	}
+	fmt.Fprintf(os.Stdout, "no blocking ranges found among %d ranges with replicas on the target nodes\n",
+		resp.CheckedRangeCount)
+	return nil
+}
+
 func printDecommissionReplicas(resp serverpb.DecommissionStatusResponse) {
 	fmt.Fprintln(stderr, "\npossible decommission stall detected")
 
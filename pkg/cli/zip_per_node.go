@@ -81,6 +81,8 @@ var debugZipTablesPerNode = []string{
 	"crdb_internal.node_transactions",
 	"crdb_internal.node_txn_stats",
 	"crdb_internal.active_range_feeds",
+	"crdb_internal.closed_timestamp_blocked_ranges",
+	"crdb_internal.node_tripped_circuit_breakers",
 }
 
 // collectCPUProfiles collects CPU profiles in parallel over all nodes
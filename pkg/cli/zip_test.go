@@ -76,12 +76,15 @@ table_name NOT IN (
 	'cross_db_references',
 	'databases',
 	'forward_dependencies',
+	'hot_keys',
 	'index_columns',
 	'lost_descriptors_with_data',
+	'node_plan_pins',
 	'table_columns',
 	'table_row_statistics',
 	'ranges',
 	'ranges_no_leases',
+	'range_details',
 	'predefined_comments',
 	'session_trace',
 	'session_variables',
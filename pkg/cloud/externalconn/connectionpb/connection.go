@@ -20,7 +20,7 @@ func (d *ConnectionDetails) Type() ConnectionType {
 		return TypeStorage
 	case ConnectionProvider_gcp_kms, ConnectionProvider_aws_kms:
 		return TypeKMS
-	case ConnectionProvider_kafka:
+	case ConnectionProvider_kafka, ConnectionProvider_webhook:
 		return TypeStorage
 	default:
 		panic(errors.AssertionFailedf("ConnectionDetails.Type called on a details with an unknown type: %s", d.Provider.String()))
@@ -297,6 +297,38 @@ const (
 	// TTLDistSQL uses DistSQL to distribute TTL SELECT/DELETE statements to
 	// leaseholder nodes.
 	TTLDistSQL
+	// ClosedTimestampPolicyClasses enables ranges to close timestamps at lag
+	// targets configured through span configs, which requires the closed
+	// timestamp side-transport to group ranges by their lag target.
+	ClosedTimestampPolicyClasses
+	// ZstdRPCCompressor indicates that all nodes can decompress RPCs compressed
+	// with zstd.
+	ZstdRPCCompressor
+	// RelocateRangeJobs adds the RELOCATE RANGE job type, which is created by
+	// ALTER RANGE ... RELOCATE ALL.
+	RelocateRangeJobs
+	// PlanPinsTable adds the system.plan_pins table, which stores the plan
+	// hints pinned to statement fingerprints.
+	PlanPinsTable
+	// AddSSTableKeyRewrites adds support for AddSSTableRequest.KeyPrefixRewrites,
+	// which rewrites the key prefixes of an SST during request evaluation.
+	AddSSTableKeyRewrites
+	// IncrementalMaterializedViewRefresh adds the MATERIALIZED VIEW REFRESH job
+	// type, which is created by REFRESH MATERIALIZED VIEW ... INCREMENTAL.
+	IncrementalMaterializedViewRefresh
+	// EncryptionKeyRotationJobs adds the ENCRYPTION KEY ROTATION job type, which is
+	// created by ROTATE ENCRYPTION KEYS, and the RotateEncryptionKeys status RPC.
+	EncryptionKeyRotationJobs
+	// ClosedTimestampLeaseTransferHandoff enables lease transfers to carry the
+	// closed timestamp side-transport state of the outgoing leaseholder.
+	ClosedTimestampLeaseTransferHandoff
+	// MultiColumnHistograms enables the collection of histograms on multi-column
+	// statistics, which are built over the key encodings of the columns.
+	MultiColumnHistograms
+	// LatencyBasedLeaseRebalancing enables the latency_based_lease_rebalancing
+	// zone config field, which all nodes must understand for load-based lease
+	// rebalancing to agree on lease placement.
+	LatencyBasedLeaseRebalancing
 
 	// *************************************************
 	// Step (1): Add new versions here.
@@ -499,6 +531,46 @@ var rawVersionsSingleton = keyedVersions{
 		Key:     TTLDistSQL,
 		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 68},
 	},
+	{
+		Key:     ClosedTimestampPolicyClasses,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 70},
+	},
+	{
+		Key:     ZstdRPCCompressor,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 72},
+	},
+	{
+		Key:     RelocateRangeJobs,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 74},
+	},
+	{
+		Key:     PlanPinsTable,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 76},
+	},
+	{
+		Key:     AddSSTableKeyRewrites,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 78},
+	},
+	{
+		Key:     IncrementalMaterializedViewRefresh,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 80},
+	},
+	{
+		Key:     EncryptionKeyRotationJobs,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 82},
+	},
+	{
+		Key:     ClosedTimestampLeaseTransferHandoff,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 84},
+	},
+	{
+		Key:     MultiColumnHistograms,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 86},
+	},
+	{
+		Key:     LatencyBasedLeaseRebalancing,
+		Version: roachpb.Version{Major: 22, Minor: 1, Internal: 88},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
@@ -334,6 +334,10 @@ func (z *ZoneConfig) Validate() error {
 		}
 	}
 
+	if z.ClosedTimestampTargetDuration != nil && *z.ClosedTimestampTargetDuration <= 0 {
+		return fmt.Errorf("closed_timestamp_target_duration must be positive")
+	}
+
 	if z.NumReplicas != nil {
 		switch {
 		case *z.NumReplicas < 0:
@@ -537,6 +541,17 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.GlobalReads = proto.Bool(*parent.GlobalReads)
 		}
 	}
+	if z.ClosedTimestampTargetDuration == nil {
+		if parent.ClosedTimestampTargetDuration != nil {
+			d := *parent.ClosedTimestampTargetDuration
+			z.ClosedTimestampTargetDuration = &d
+		}
+	}
+	if z.LatencyBasedLeaseRebalancing == nil {
+		if parent.LatencyBasedLeaseRebalancing != nil {
+			z.LatencyBasedLeaseRebalancing = proto.Bool(*parent.LatencyBasedLeaseRebalancing)
+		}
+	}
 	if z.RangeMinBytes == nil {
 		if parent.RangeMinBytes != nil {
 			z.RangeMinBytes = proto.Int64(*parent.RangeMinBytes)
@@ -602,6 +617,17 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			if other.GlobalReads != nil {
 				z.GlobalReads = proto.Bool(*other.GlobalReads)
 			}
+		case "closed_timestamp_target_duration":
+			z.ClosedTimestampTargetDuration = nil
+			if other.ClosedTimestampTargetDuration != nil {
+				d := *other.ClosedTimestampTargetDuration
+				z.ClosedTimestampTargetDuration = &d
+			}
+		case "latency_based_lease_rebalancing":
+			z.LatencyBasedLeaseRebalancing = nil
+			if other.LatencyBasedLeaseRebalancing != nil {
+				z.LatencyBasedLeaseRebalancing = proto.Bool(*other.LatencyBasedLeaseRebalancing)
+			}
 		case "gc.ttlseconds":
 			z.GC = nil
 			if other.GC != nil {
@@ -706,6 +732,26 @@ func (z *ZoneConfig) DiffWithZone(
 					Field: "global_reads",
 				}, nil
 			}
+		case "closed_timestamp_target_duration":
+			if other.ClosedTimestampTargetDuration == nil && z.ClosedTimestampTargetDuration == nil {
+				continue
+			}
+			if z.ClosedTimestampTargetDuration == nil || other.ClosedTimestampTargetDuration == nil ||
+				*z.ClosedTimestampTargetDuration != *other.ClosedTimestampTargetDuration {
+				return false, DiffWithZoneMismatch{
+					Field: "closed_timestamp_target_duration",
+				}, nil
+			}
+		case "latency_based_lease_rebalancing":
+			if other.LatencyBasedLeaseRebalancing == nil && z.LatencyBasedLeaseRebalancing == nil {
+				continue
+			}
+			if z.LatencyBasedLeaseRebalancing == nil || other.LatencyBasedLeaseRebalancing == nil ||
+				*z.LatencyBasedLeaseRebalancing != *other.LatencyBasedLeaseRebalancing {
+				return false, DiffWithZoneMismatch{
+					Field: "latency_based_lease_rebalancing",
+				}, nil
+			}
 		case "gc.ttlseconds":
 			if other.GC == nil && z.GC == nil {
 				continue
@@ -1113,6 +1159,15 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.GlobalReads != nil {
 		sc.GlobalReads = *z.GlobalReads
 	}
+	// ClosedTimestampTargetDuration is unset by default, in which case the
+	// kv.closed_timestamp.target_duration cluster setting applies.
+	if z.ClosedTimestampTargetDuration != nil {
+		sc.ClosedTimestampTargetDuration = *z.ClosedTimestampTargetDuration
+	}
+	// LatencyBasedLeaseRebalancing is false by default.
+	if z.LatencyBasedLeaseRebalancing != nil {
+		sc.LatencyBasedLeaseRebalancing = *z.LatencyBasedLeaseRebalancing
+	}
 	sc.NumReplicas = *z.NumReplicas
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
@@ -16,6 +16,7 @@ import (
 	"math/rand"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -54,6 +55,13 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"at least 3 replicas are required for multi-replica configurations",
 		},
+		{
+			ZoneConfig{
+				NumReplicas:                   proto.Int32(1),
+				ClosedTimestampTargetDuration: func() *time.Duration { d := time.Duration(0); return &d }(),
+			},
+			"closed_timestamp_target_duration must be positive",
+		},
 		{
 			ZoneConfig{
 				NumReplicas:   proto.Int32(1),
@@ -1416,6 +1424,48 @@ func TestZoneConfigToSpanConfigConversion(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Test ClosedTimestampTargetDuration set.
+			zoneConfig: ZoneConfig{
+				RangeMinBytes: proto.Int64(100000),
+				RangeMaxBytes: proto.Int64(200000),
+				GC: &GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas:                   proto.Int32(3),
+				ClosedTimestampTargetDuration: func() *time.Duration { d := 500 * time.Millisecond; return &d }(),
+			},
+			expectSpanConfig: roachpb.SpanConfig{
+				RangeMinBytes: 100000,
+				RangeMaxBytes: 200000,
+				GCPolicy: roachpb.GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas:                   3,
+				ClosedTimestampTargetDuration: 500 * time.Millisecond,
+			},
+		},
+		{
+			// Test LatencyBasedLeaseRebalancing set.
+			zoneConfig: ZoneConfig{
+				RangeMinBytes: proto.Int64(100000),
+				RangeMaxBytes: proto.Int64(200000),
+				GC: &GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas:                  proto.Int32(3),
+				LatencyBasedLeaseRebalancing: proto.Bool(true),
+			},
+			expectSpanConfig: roachpb.SpanConfig{
+				RangeMinBytes: 100000,
+				RangeMaxBytes: 200000,
+				GCPolicy: roachpb.GCPolicy{
+					TTLSeconds: 2400,
+				},
+				NumReplicas:                  3,
+				LatencyBasedLeaseRebalancing: true,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		spanConfig, err := tc.zoneConfig.toSpanConfig()
@@ -15,6 +15,7 @@ import (
 	"runtime/debug"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/gogo/protobuf/proto"
@@ -199,18 +200,20 @@ func (c *ConstraintsList) UnmarshalYAML(unmarshal func(interface{}) error) error
 //
 // TODO(a-robinson,v2.2): Remove the experimental_lease_preferences field.
 type marshalableZoneConfig struct {
-	RangeMinBytes                *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
-	RangeMaxBytes                *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
-	GC                           *GCPolicy         `json:"gc"`
-	GlobalReads                  *bool             `json:"global_reads" yaml:"global_reads"`
-	NumReplicas                  *int32            `json:"num_replicas" yaml:"num_replicas"`
-	NumVoters                    *int32            `json:"num_voters" yaml:"num_voters"`
-	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
-	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
-	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
-	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
-	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
-	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
+	RangeMinBytes                 *int64            `json:"range_min_bytes" yaml:"range_min_bytes"`
+	RangeMaxBytes                 *int64            `json:"range_max_bytes" yaml:"range_max_bytes"`
+	GC                            *GCPolicy         `json:"gc"`
+	GlobalReads                   *bool             `json:"global_reads" yaml:"global_reads"`
+	ClosedTimestampTargetDuration *time.Duration    `json:"closed_timestamp_target_duration,omitempty" yaml:"closed_timestamp_target_duration,omitempty"`
+	LatencyBasedLeaseRebalancing  *bool             `json:"latency_based_lease_rebalancing,omitempty" yaml:"latency_based_lease_rebalancing,omitempty"`
+	NumReplicas                   *int32            `json:"num_replicas" yaml:"num_replicas"`
+	NumVoters                     *int32            `json:"num_voters" yaml:"num_voters"`
+	Constraints                   ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
+	VoterConstraints              ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
+	LeasePreferences              []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
+	ExperimentalLeasePreferences  []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
+	Subzones                      []Subzone         `json:"subzones" yaml:"-"`
+	SubzoneSpans                  []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
 }
 
 func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
@@ -228,6 +231,13 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if c.GlobalReads != nil {
 		m.GlobalReads = proto.Bool(*c.GlobalReads)
 	}
+	if c.ClosedTimestampTargetDuration != nil {
+		d := *c.ClosedTimestampTargetDuration
+		m.ClosedTimestampTargetDuration = &d
+	}
+	if c.LatencyBasedLeaseRebalancing != nil {
+		m.LatencyBasedLeaseRebalancing = proto.Bool(*c.LatencyBasedLeaseRebalancing)
+	}
 	if c.NumReplicas != nil && *c.NumReplicas != 0 {
 		m.NumReplicas = proto.Int32(*c.NumReplicas)
 	}
@@ -267,6 +277,13 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.GlobalReads != nil {
 		c.GlobalReads = proto.Bool(*m.GlobalReads)
 	}
+	if m.ClosedTimestampTargetDuration != nil {
+		d := *m.ClosedTimestampTargetDuration
+		c.ClosedTimestampTargetDuration = &d
+	}
+	if m.LatencyBasedLeaseRebalancing != nil {
+		c.LatencyBasedLeaseRebalancing = proto.Bool(*m.LatencyBasedLeaseRebalancing)
+	}
 	if m.NumReplicas != nil {
 		c.NumReplicas = proto.Int32(*m.NumReplicas)
 	}
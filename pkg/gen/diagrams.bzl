@@ -92,6 +92,7 @@ DIAGRAMS_SRCS = [
   "//docs/generated/sql/bnf:create_index.html",
   "//docs/generated/sql/bnf:create_index_with_storage_param.html",
   "//docs/generated/sql/bnf:create_inverted_index.html",
+  "//docs/generated/sql/bnf:create_plan_pin.html",
   "//docs/generated/sql/bnf:create_role.html",
   "//docs/generated/sql/bnf:create_schedule_for_backup.html",
   "//docs/generated/sql/bnf:create_schema.html",
@@ -116,6 +117,7 @@ DIAGRAMS_SRCS = [
   "//docs/generated/sql/bnf:drop_func.html",
   "//docs/generated/sql/bnf:drop_index.html",
   "//docs/generated/sql/bnf:drop_owned_by.html",
+  "//docs/generated/sql/bnf:drop_plan_pin.html",
   "//docs/generated/sql/bnf:drop_role.html",
   "//docs/generated/sql/bnf:drop_schedule.html",
   "//docs/generated/sql/bnf:drop_schema.html",
@@ -183,6 +185,7 @@ DIAGRAMS_SRCS = [
   "//docs/generated/sql/bnf:resume_job.html",
   "//docs/generated/sql/bnf:resume_schedule.html",
   "//docs/generated/sql/bnf:revoke.html",
+  "//docs/generated/sql/bnf:rotate_encryption_keys.html",
   "//docs/generated/sql/bnf:rollback_transaction.html",
   "//docs/generated/sql/bnf:routine_body.html",
   "//docs/generated/sql/bnf:routine_return.html",
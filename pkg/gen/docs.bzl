@@ -104,6 +104,7 @@ DOCS_SRCS = [
   "//docs/generated/sql/bnf:create_index_stmt.bnf",
   "//docs/generated/sql/bnf:create_index_with_storage_param.bnf",
   "//docs/generated/sql/bnf:create_inverted_index_stmt.bnf",
+  "//docs/generated/sql/bnf:create_plan_pin_stmt.bnf",
   "//docs/generated/sql/bnf:create_role_stmt.bnf",
   "//docs/generated/sql/bnf:create_schedule_for_backup_stmt.bnf",
   "//docs/generated/sql/bnf:create_schema_stmt.bnf",
@@ -128,6 +129,7 @@ DOCS_SRCS = [
   "//docs/generated/sql/bnf:drop_func_stmt.bnf",
   "//docs/generated/sql/bnf:drop_index.bnf",
   "//docs/generated/sql/bnf:drop_owned_by_stmt.bnf",
+  "//docs/generated/sql/bnf:drop_plan_pin_stmt.bnf",
   "//docs/generated/sql/bnf:drop_role_stmt.bnf",
   "//docs/generated/sql/bnf:drop_schedule_stmt.bnf",
   "//docs/generated/sql/bnf:drop_schema.bnf",
@@ -196,6 +198,7 @@ DOCS_SRCS = [
   "//docs/generated/sql/bnf:resume_schedule.bnf",
   "//docs/generated/sql/bnf:resume_stmt.bnf",
   "//docs/generated/sql/bnf:revoke_stmt.bnf",
+  "//docs/generated/sql/bnf:rotate_encryption_keys_stmt.bnf",
   "//docs/generated/sql/bnf:rollback_transaction.bnf",
   "//docs/generated/sql/bnf:routine_body_stmt.bnf",
   "//docs/generated/sql/bnf:routine_return_stmt.bnf",
@@ -48,6 +48,9 @@ var (
 	_ Details = StreamReplicationDetails{}
 	_ Details = RowLevelTTLDetails{}
 	_ Details = SchemaTelemetryDetails{}
+	_ Details = RelocateRangeDetails{}
+	_ Details = MaterializedViewRefreshDetails{}
+	_ Details = EncryptionKeyRotationDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -67,6 +70,9 @@ var (
 	_ ProgressDetails = StreamReplicationProgress{}
 	_ ProgressDetails = RowLevelTTLProgress{}
 	_ ProgressDetails = SchemaTelemetryProgress{}
+	_ ProgressDetails = RelocateRangeProgress{}
+	_ ProgressDetails = MaterializedViewRefreshProgress{}
+	_ ProgressDetails = EncryptionKeyRotationProgress{}
 )
 
 // Type returns the payload's job type.
@@ -137,6 +143,12 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeRowLevelTTL
 	case *Payload_SchemaTelemetry:
 		return TypeAutoSchemaTelemetry
+	case *Payload_RelocateRange:
+		return TypeRelocateRange
+	case *Payload_MaterializedViewRefresh:
+		return TypeMaterializedViewRefresh
+	case *Payload_EncryptionKeyRotation:
+		return TypeEncryptionKeyRotation
 	default:
 		panic(errors.AssertionFailedf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -183,6 +195,12 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_RowLevelTTL{RowLevelTTL: &d}
 	case SchemaTelemetryProgress:
 		return &Progress_SchemaTelemetry{SchemaTelemetry: &d}
+	case RelocateRangeProgress:
+		return &Progress_RelocateRange{RelocateRange: &d}
+	case MaterializedViewRefreshProgress:
+		return &Progress_MaterializedViewRefresh{MaterializedViewRefresh: &d}
+	case EncryptionKeyRotationProgress:
+		return &Progress_EncryptionKeyRotation{EncryptionKeyRotation: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -224,6 +242,12 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.RowLevelTTL
 	case *Payload_SchemaTelemetry:
 		return *d.SchemaTelemetry
+	case *Payload_RelocateRange:
+		return *d.RelocateRange
+	case *Payload_MaterializedViewRefresh:
+		return *d.MaterializedViewRefresh
+	case *Payload_EncryptionKeyRotation:
+		return *d.EncryptionKeyRotation
 	default:
 		return nil
 	}
@@ -265,6 +289,12 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.RowLevelTTL
 	case *Progress_SchemaTelemetry:
 		return *d.SchemaTelemetry
+	case *Progress_RelocateRange:
+		return *d.RelocateRange
+	case *Progress_MaterializedViewRefresh:
+		return *d.MaterializedViewRefresh
+	case *Progress_EncryptionKeyRotation:
+		return *d.EncryptionKeyRotation
 	default:
 		return nil
 	}
@@ -319,6 +349,12 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_RowLevelTTL{RowLevelTTL: &d}
 	case SchemaTelemetryDetails:
 		return &Payload_SchemaTelemetry{SchemaTelemetry: &d}
+	case RelocateRangeDetails:
+		return &Payload_RelocateRange{RelocateRange: &d}
+	case MaterializedViewRefreshDetails:
+		return &Payload_MaterializedViewRefresh{MaterializedViewRefresh: &d}
+	case EncryptionKeyRotationDetails:
+		return &Payload_EncryptionKeyRotation{EncryptionKeyRotation: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -354,7 +390,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 18
+const NumJobTypes = 21
 
 // MarshalJSONPB implements jsonpb.JSONPBMarshaller to  redact sensitive sink URI
 // parameters from ChangefeedDetails.
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -72,6 +73,12 @@ var (
 		Measurement: "Partial Batches",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaDistSenderParallelLimitedScanCount = metric.Metadata{
+		Name:        "distsender.batches.limited_scans.parallel",
+		Help:        "Number of batches with a key limit whose scans were sent to their ranges in parallel",
+		Measurement: "Batches",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaTransportSentCount = metric.Metadata{
 		Name:        "distsender.rpc.sent",
 		Help:        "Number of replica-addressed RPCs sent",
@@ -214,6 +221,22 @@ var senderConcurrencyLimit = settings.RegisterIntSetting(
 	settings.NonNegativeInt,
 )
 
+// traceRequestSpans controls whether the key spans of the requests sent by the
+// DistSender are recorded on its trace spans.
+var traceRequestSpans = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"kv.dist_sender.trace_request_spans.enabled",
+	"if set, the key spans of the requests in each batch are added as a tag to "+
+		"the batch's trace span, which makes them visible in the traces exported to "+
+		"an external collector (see trace.opentelemetry.collector and trace.jaeger.agent); "+
+		"only the table and index prefix of the keys is included",
+	false,
+)
+
+// maxTracedRequestSpans is the maximum number of request spans included in the
+// tag added to a batch's trace span when traceRequestSpans is set.
+const maxTracedRequestSpans = 16
+
 func max(a, b int64) int64 {
 	if a > b {
 		return a
@@ -227,6 +250,7 @@ type DistSenderMetrics struct {
 	PartialBatchCount       *metric.Counter
 	AsyncSentCount          *metric.Counter
 	AsyncThrottledCount     *metric.Counter
+	ParallelLimitedScans    *metric.Counter
 	SentCount               *metric.Counter
 	LocalSentCount          *metric.Counter
 	NextReplicaErrCount     *metric.Counter
@@ -248,6 +272,7 @@ func makeDistSenderMetrics() DistSenderMetrics {
 		PartialBatchCount:       metric.NewCounter(metaDistSenderPartialBatchCount),
 		AsyncSentCount:          metric.NewCounter(metaDistSenderAsyncSentCount),
 		AsyncThrottledCount:     metric.NewCounter(metaDistSenderAsyncThrottledCount),
+		ParallelLimitedScans:    metric.NewCounter(metaDistSenderParallelLimitedScanCount),
 		SentCount:               metric.NewCounter(metaTransportSentCount),
 		LocalSentCount:          metric.NewCounter(metaTransportLocalSentCount),
 		NextReplicaErrCount:     metric.NewCounter(metaTransportSenderNextReplicaErrCount),
@@ -637,6 +662,61 @@ func (ds *DistSender) getRoutingInfo(
 	return returnToken, nil
 }
 
+// requestSpansString formats the methods and key spans of the given requests,
+// up to maxTracedRequestSpans of them. The tag is exported to external trace
+// collectors, so the keys are redacted; see formatRedactedKey.
+func requestSpansString(reqs []roachpb.RequestUnion) string {
+	var buf redact.StringBuilder
+	for i := range reqs {
+		if i == maxTracedRequestSpans {
+			buf.Printf(", ... %d more", redact.SafeInt(len(reqs)-i))
+			break
+		}
+		if i > 0 {
+			buf.SafeString(", ")
+		}
+		req := reqs[i].GetInner()
+		h := req.Header()
+		buf.Printf("%s ", req.Method())
+		formatRedactedKey(&buf, h.Key)
+		if len(h.EndKey) > 0 {
+			buf.SafeRune('-')
+			formatRedactedKey(&buf, h.EndKey)
+		}
+	}
+	return string(buf.RedactableString().Redact())
+}
+
+// formatRedactedKey writes the given key to buf, marking the parts of the key
+// that may contain user data as unsafe. Only the tenant, table and index
+// prefix of SQL keys are safe; other keys are unsafe as a whole.
+func formatRedactedKey(buf *redact.StringBuilder, key roachpb.Key) {
+	_, tenID, err := keys.DecodeTenantPrefix(key)
+	if err != nil {
+		buf.Print(key)
+		return
+	}
+	codec := keys.MakeSQLCodec(tenID)
+	var rest []byte
+	var tableID, indexID uint32
+	if rest, tableID, indexID, err = codec.DecodeIndexPrefix(key); err != nil {
+		if rest, tableID, err = codec.DecodeTablePrefix(key); err != nil {
+			buf.Print(key)
+			return
+		}
+	}
+	if !tenID.IsSystem() {
+		buf.Printf("/Tenant/%d", tenID)
+	}
+	buf.Printf("/Table/%d", redact.SafeUint(tableID))
+	if indexID != 0 {
+		buf.Printf("/%d", redact.SafeUint(indexID))
+	}
+	if len(rest) > 0 {
+		buf.Printf("/%s", roachpb.Key(rest))
+	}
+}
+
 // initAndVerifyBatch initializes timestamp-related information and
 // verifies batch constraints before splitting.
 func (ds *DistSender) initAndVerifyBatch(
@@ -786,6 +866,9 @@ func (ds *DistSender) Send(
 	ctx = ds.AnnotateCtx(ctx)
 	ctx, sp := tracing.EnsureChildSpan(ctx, ds.AmbientContext.Tracer, "dist sender send")
 	defer sp.Finish()
+	if !sp.IsNoop() && traceRequestSpans.Get(&ds.st.SV) {
+		sp.SetTag("request_spans", attribute.StringValue(requestSpansString(ba.Requests)))
+	}
 
 	splitET := false
 	var require1PC bool
@@ -836,6 +919,8 @@ func (ds *DistSender) Send(
 		var pErr *roachpb.Error
 		if withParallelCommit {
 			rpl, pErr = ds.divideAndSendParallelCommit(ctx, ba, rs, isReverse, 0 /* batchIdx */)
+		} else if groups := ds.parallelLimitedScanGroups(ctx, &ba, isReverse); groups != nil {
+			rpl, pErr = ds.divideAndSendParallelLimitedScans(ctx, ba, groups, isReverse)
 		} else {
 			rpl, pErr = ds.divideAndSendBatchToRanges(ctx, ba, rs, isReverse, withCommit, 0 /* batchIdx */)
 		}
@@ -0,0 +1,352 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvcoord
+
+import (
+	"context"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// parallelLimitedScansEnabled controls whether batches of disjoint scans with
+// a key limit are split into groups of ranges that are scanned in parallel.
+var parallelLimitedScansEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"kv.dist_sender.parallel_limited_scans.enabled",
+	"if enabled, batches of disjoint scans with a key limit that span multiple "+
+		"ranges are sent to those ranges in parallel; since every range is scanned "+
+		"before it is known how many keys the preceding ranges return, this may read "+
+		"up to kv.dist_sender.parallel_limited_scans.max_read_amplification times "+
+		"as many keys as the limit",
+	false,
+)
+
+// parallelLimitedScansMaxReadAmplification bounds the number of keys that the
+// groups of a batch of limited scans may read when they are sent in parallel,
+// as a multiple of the batch's key limit.
+var parallelLimitedScansMaxReadAmplification = settings.RegisterIntSetting(
+	settings.TenantWritable,
+	"kv.dist_sender.parallel_limited_scans.max_read_amplification",
+	"the maximum number of keys that a batch of limited scans sent in parallel "+
+		"reads up front, as a multiple of its key limit; the first range is scanned "+
+		"with the full limit and the other ranges share the rest, and a range that "+
+		"needs more keys than its share is scanned again once the keys of the "+
+		"preceding ranges are known; every range may read at least one key",
+	2,
+	settings.PositiveInt,
+)
+
+// maxParallelLimitedScanGroups is the maximum number of groups that a batch
+// of limited scans is split into. The requests of the last group are sent
+// serially, like any other limited batch.
+const maxParallelLimitedScanGroups = 8
+
+// limitedScanGroup is a subset of the requests of a limited batch whose start
+// keys (end keys for reverse scans) fall into the same range.
+type limitedScanGroup struct {
+	// positions are the indexes of the group's requests in the original batch.
+	positions []int
+	// nextKey is the key at which the group starts, in the direction of the
+	// scan. It is used as the resume key if the group is skipped.
+	nextKey roachpb.RKey
+}
+
+// parallelLimitedScanGroups returns the groups that the requests of a batch
+// with a key limit can be split into in order to scan them in parallel. If the
+// batch does not qualify for parallel limited scans, or all of its requests
+// start in the same range, nil is returned.
+//
+// A batch qualifies if it is a non-locking, read-only batch consisting of only
+// Get and Scan requests (only ReverseScan requests for reverse batches) that
+// are sorted in the direction of the scan and don't overlap, which is the
+// shape of the batches that SQL generates for a constrained scan with a limit,
+// e.g. for a filter like `k IN (1, 5, 10) LIMIT 10`. The key limit is the only
+// limit that such a batch may have.
+func (ds *DistSender) parallelLimitedScanGroups(
+	ctx context.Context, ba *roachpb.BatchRequest, isReverse bool,
+) []limitedScanGroup {
+	if ba.MaxSpanRequestKeys <= 0 || ba.TargetBytes != 0 || ba.ReturnOnRangeBoundary ||
+		len(ba.Requests) < 2 || ds.disableParallelBatches ||
+		!parallelLimitedScansEnabled.Get(&ds.st.SV) {
+		return nil
+	}
+	if !ba.IsReadOnly() || ba.IsLocking() {
+		return nil
+	}
+	// Non-transactional consistent reads that span ranges must be retried
+	// within a transaction; leave that to divideAndSendBatchToRanges.
+	if ba.Txn == nil && ba.ReadConsistency == roachpb.CONSISTENT {
+		return nil
+	}
+
+	// prevEnd is the end key (start key for reverse scans) of the previous
+	// request.
+	var prevEnd roachpb.Key
+	for i := range ba.Requests {
+		req := ba.Requests[i].GetInner()
+		h := req.Header()
+		if keys.IsLocal(h.Key) {
+			return nil
+		}
+		switch req.(type) {
+		case *roachpb.GetRequest:
+			if isReverse {
+				return nil
+			}
+		case *roachpb.ScanRequest:
+			if isReverse {
+				return nil
+			}
+		case *roachpb.ReverseScanRequest:
+		default:
+			return nil
+		}
+		start, end := h.Key, h.EndKey
+		if len(end) == 0 {
+			end = start.Next()
+		}
+		if isReverse {
+			if i > 0 && end.Compare(prevEnd) > 0 {
+				return nil
+			}
+			prevEnd = start
+		} else {
+			if i > 0 && start.Compare(prevEnd) < 0 {
+				return nil
+			}
+			prevEnd = end
+		}
+	}
+
+	ri := MakeRangeIterator(ds)
+	var groups []limitedScanGroup
+	for i := range ba.Requests {
+		h := ba.Requests[i].GetInner().Header()
+		var key roachpb.RKey
+		var err error
+		if isReverse {
+			key, err = keys.AddrUpperBound(h.EndKey)
+		} else {
+			key, err = keys.Addr(h.Key)
+		}
+		if err != nil {
+			return nil
+		}
+		if len(groups) > 0 {
+			desc := ri.Desc()
+			inRange := desc.ContainsKey(key)
+			if isReverse {
+				inRange = desc.ContainsKeyInverted(key)
+			}
+			if inRange || len(groups) == maxParallelLimitedScanGroups {
+				last := &groups[len(groups)-1]
+				last.positions = append(last.positions, i)
+				continue
+			}
+		}
+		if isReverse {
+			ri.Seek(ctx, key, Descending)
+		} else {
+			ri.Seek(ctx, key, Ascending)
+		}
+		if !ri.Valid() {
+			// Let the regular code path deal with the error.
+			return nil
+		}
+		groups = append(groups, limitedScanGroup{positions: []int{i}, nextKey: key})
+	}
+	if len(groups) < 2 {
+		return nil
+	}
+	return groups
+}
+
+// parallelLimitedScanGroupLimit returns the key limit with which each group but
+// the first of a batch with the given key limit and number of groups is sent in
+// parallel. The first group is sent with the full limit, and the other groups
+// share what is left of maxReadAmplification times the limit, so that the
+// groups don't read more than that many keys in total. Every group may read at
+// least one key, though.
+func parallelLimitedScanGroupLimit(limit int64, numGroups int, maxReadAmplification int64) int64 {
+	numOther := int64(numGroups - 1)
+	if numOther <= 0 || maxReadAmplification-1 >= numOther || limit > math.MaxInt64/numOther {
+		return limit
+	}
+	groupLimit := limit * (maxReadAmplification - 1) / numOther
+	if groupLimit < 1 {
+		return 1
+	}
+	return groupLimit
+}
+
+// divideAndSendParallelLimitedScans sends the groups of requests returned by
+// parallelLimitedScanGroups to their ranges in parallel, and combines the
+// results in key order until the key limit of the batch is reached. The groups
+// other than the first are sent with a share of the limit (see
+// parallelLimitedScanGroupLimit). A group whose result would exceed the
+// remaining limit, or which stopped at its share of the limit while more keys
+// remain, is sent again with the remaining limit. Once the limit is reached,
+// the groups that are still outstanding are canceled, and the requests of all
+// remaining groups get ResumeSpans.
+func (ds *DistSender) divideAndSendParallelLimitedScans(
+	ctx context.Context, ba roachpb.BatchRequest, groups []limitedScanGroup, isReverse bool,
+) (br *roachpb.BatchResponse, pErr *roachpb.Error) {
+	// Clone the BatchRequest's transaction so that future mutations to the
+	// proto don't affect the proto in this batch.
+	if ba.Txn != nil {
+		ba.Txn = ba.Txn.Clone()
+	}
+	// The groups are evaluated independently, so none of them may perform a
+	// server-side refresh.
+	unsetCanForwardReadTimestampFlag(&ba)
+	log.VEventf(ctx, 2, "sending limited batch as %d parallel groups", len(groups))
+	ds.metrics.ParallelLimitedScans.Inc(1)
+
+	groupBatch := func(g limitedScanGroup, limit int64) roachpb.BatchRequest {
+		gba := ba
+		gba.MaxSpanRequestKeys = limit
+		gba.Requests = make([]roachpb.RequestUnion, len(g.positions))
+		for i, pos := range g.positions {
+			gba.Requests[i] = ba.Requests[pos]
+		}
+		return gba
+	}
+	sendGroup := func(ctx context.Context, g limitedScanGroup, limit int64) response {
+		gba := groupBatch(g, limit)
+		rs, err := keys.Range(gba.Requests)
+		if err != nil {
+			return response{pErr: roachpb.NewError(err)}
+		}
+		reply, pErr := ds.divideAndSendBatchToRanges(
+			ctx, gba, rs, isReverse, false /* withCommit */, 0, /* batchIdx */
+		)
+		if pErr != nil && pErr.Index != nil && pErr.Index.Index != -1 {
+			pErr.Index.Index = int32(g.positions[pErr.Index.Index])
+		}
+		return response{reply: reply, positions: g.positions, pErr: pErr}
+	}
+
+	// The first group is sent synchronously below. The other groups are sent
+	// asynchronously with their share of the limit if one of the limited
+	// goroutines for parallel batch RPCs can be reserved, and synchronously with
+	// the remaining limit otherwise.
+	groupLimit := parallelLimitedScanGroupLimit(
+		ba.MaxSpanRequestKeys, len(groups),
+		parallelLimitedScansMaxReadAmplification.Get(&ds.st.SV),
+	)
+	asyncCtx, cancel := context.WithCancel(ctx)
+	responseChs := make([]chan response, len(groups))
+	for i := 1; i < len(groups); i++ {
+		g := groups[i]
+		responseCh := make(chan response, 1)
+		if err := ds.rpcContext.Stopper.RunAsyncTaskEx(
+			asyncCtx,
+			stop.TaskOpts{
+				TaskName:   "kv.DistSender: sending limited scan group",
+				SpanOpt:    stop.ChildSpan,
+				Sem:        ds.asyncSenderSem,
+				WaitForSem: false,
+			},
+			func(ctx context.Context) {
+				ds.metrics.AsyncSentCount.Inc(1)
+				responseCh <- sendGroup(ctx, g, groupLimit)
+			},
+		); err != nil {
+			ds.metrics.AsyncThrottledCount.Inc(1)
+			continue
+		}
+		responseChs[i] = responseCh
+	}
+	defer func() {
+		// It's important that we wait for all outstanding groups, because the
+		// client.Sender() contract mandates that we don't "hold on" to any part
+		// of a request after DistSender.Send() returns.
+		cancel()
+		for _, responseCh := range responseChs {
+			if responseCh != nil {
+				<-responseCh
+			}
+		}
+	}()
+
+	br = &roachpb.BatchResponse{
+		Responses: make([]roachpb.ResponseUnion, len(ba.Requests)),
+	}
+	remaining := ba.MaxSpanRequestKeys
+	for i, g := range groups {
+		var resp response
+		if responseChs[i] != nil {
+			resp = <-responseChs[i]
+			responseChs[i] = nil
+			if resp.pErr == nil && (replyKeys(resp.reply) > remaining ||
+				(groupLimit < remaining && hitKeyLimit(resp.reply))) {
+				// The group was sent with a different limit than what's left over
+				// after the previous groups: it either returned more keys than
+				// that, or stopped at its share of the limit. Send it again with
+				// the remaining limit.
+				resp = sendGroup(ctx, g, remaining)
+			}
+		} else {
+			resp = sendGroup(ctx, g, remaining)
+		}
+		if resp.pErr != nil {
+			resp.pErr.UpdateTxn(br.Txn)
+			return nil, resp.pErr
+		}
+		if err := br.Combine(resp.reply, resp.positions); err != nil {
+			return nil, roachpb.NewError(err)
+		}
+		remaining -= replyKeys(resp.reply)
+
+		resumeReason := roachpb.RESUME_UNKNOWN
+		for _, r := range resp.reply.Responses {
+			if h := r.GetInner().Header(); h.ResumeSpan != nil {
+				resumeReason = h.ResumeReason
+				break
+			}
+		}
+		if resumeReason == roachpb.RESUME_UNKNOWN && remaining <= 0 {
+			resumeReason = roachpb.RESUME_KEY_LIMIT
+		}
+		if resumeReason != roachpb.RESUME_UNKNOWN {
+			if i+1 < len(groups) {
+				fillSkippedResponses(ba, br, groups[i+1].nextKey, resumeReason, isReverse)
+			}
+			return br, nil
+		}
+	}
+	return br, nil
+}
+
+// hitKeyLimit returns true if one of the requests of a batch stopped because
+// the key limit of the batch was reached.
+func hitKeyLimit(br *roachpb.BatchResponse) bool {
+	for _, r := range br.Responses {
+		if h := r.GetInner().Header(); h.ResumeSpan != nil && h.ResumeReason == roachpb.RESUME_KEY_LIMIT {
+			return true
+		}
+	}
+	return false
+}
+
+// replyKeys returns the number of keys returned by the requests of a batch.
+func replyKeys(br *roachpb.BatchResponse) int64 {
+	var n int64
+	for _, r := range br.Responses {
+		n += r.GetInner().Header().NumKeys
+	}
+	return n
+}
@@ -84,6 +84,7 @@ func maxConcurrentCatchupScans(sv *settings.Values) int {
 
 type rangeFeedConfig struct {
 	useMuxRangeFeed bool
+	familyIDs       []uint32
 }
 
 // RangeFeedOption configures a RangeFeed.
@@ -102,6 +103,15 @@ func WithMuxRangeFeed() RangeFeedOption {
 	})
 }
 
+// WithFamilyIDs configures range feed to only emit the values of the given SQL
+// column families. Values of keys that are not SQL row keys are always emitted.
+// See RangeFeedRequest.FamilyIDs.
+func WithFamilyIDs(familyIDs []uint32) RangeFeedOption {
+	return optionFunc(func(c *rangeFeedConfig) {
+		c.familyIDs = familyIDs
+	})
+}
+
 // A "kill switch" to disable multiplexing rangefeed if severe issues discovered with new implementation.
 var enableMuxRangeFeed = envutil.EnvOrDefaultBool("COCKROACH_ENABLE_MULTIPLEXING_RANGEFEED", true)
 
@@ -194,7 +204,7 @@ func (ds *DistSender) RangeFeedSpans(
 				// Spawn a child goroutine to process this feed.
 				g.GoCtx(func(ctx context.Context) error {
 					return ds.partialRangeFeed(ctx, rr, eventProducer, sri.rs, sri.startAfter,
-						sri.token, withDiff, &catchupSem, rangeCh, eventCh)
+						sri.token, withDiff, cfg.familyIDs, &catchupSem, rangeCh, eventCh)
 				})
 			case <-ctx.Done():
 				return ctx.Err()
@@ -367,6 +377,7 @@ func (ds *DistSender) partialRangeFeed(
 	startAfter hlc.Timestamp,
 	token rangecache.EvictionToken,
 	withDiff bool,
+	familyIDs []uint32,
 	catchupSem *limit.ConcurrentRequestLimiter,
 	rangeCh chan<- singleRangeInfo,
 	eventCh chan<- RangeFeedMessage,
@@ -405,7 +416,7 @@ func (ds *DistSender) partialRangeFeed(
 
 		// Establish a RangeFeed for a single Range.
 		maxTS, err := ds.singleRangeFeed(
-			ctx, span, startAfter, withDiff, token.Desc(),
+			ctx, span, startAfter, withDiff, familyIDs, token.Desc(),
 			catchupSem, eventCh, streamProducerFactory, active.onRangeEvent)
 
 		// Forward the timestamp in case we end up sending it again.
@@ -489,6 +500,7 @@ func (ds *DistSender) singleRangeFeed(
 	span roachpb.Span,
 	startAfter hlc.Timestamp,
 	withDiff bool,
+	familyIDs []uint32,
 	desc *roachpb.RangeDescriptor,
 	catchupSem *limit.ConcurrentRequestLimiter,
 	eventCh chan<- RangeFeedMessage,
@@ -505,7 +517,8 @@ func (ds *DistSender) singleRangeFeed(
 			Timestamp: startAfter,
 			RangeID:   desc.RangeID,
 		},
-		WithDiff: withDiff,
+		WithDiff:  withDiff,
+		FamilyIDs: familyIDs,
 	}
 
 	var latencyFn LatencyFunc
@@ -14,10 +14,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -37,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -44,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -3401,6 +3405,192 @@ func TestCountRanges(t *testing.T) {
 	}
 }
 
+// TestParallelLimitedScans verifies that a batch of disjoint scans with a key
+// limit that spans multiple ranges returns the same results and ResumeSpans
+// whether or not its scans are sent to their ranges in parallel.
+func TestParallelLimitedScans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	clock := hlc.NewClockWithSystemTimeSource(time.Nanosecond /* maxOffset */)
+	rpcContext := rpc.NewInsecureTestingContext(ctx, clock, stopper)
+	g := makeGossip(t, stopper, rpcContext)
+	// Create a range for each of the keys a, b, c and d.
+	const numDescriptors = 5
+	var descriptors [numDescriptors]roachpb.RangeDescriptor
+	for i := range descriptors {
+		startKey := testMetaEndKey
+		if i > 0 {
+			startKey = roachpb.RKey(string(rune('a' + i - 1)))
+		}
+		endKey := roachpb.RKeyMax
+		if i < len(descriptors)-1 {
+			endKey = roachpb.RKey(string(rune('a' + i)))
+		}
+		descriptors[i] = roachpb.RangeDescriptor{
+			RangeID:  roachpb.RangeID(i + 2),
+			StartKey: startKey,
+			EndKey:   endKey,
+			InternalReplicas: []roachpb.ReplicaDescriptor{
+				{
+					NodeID:  1,
+					StoreID: 1,
+				},
+			},
+		}
+	}
+	descDB := mockRangeDescriptorDBForDescs(append(descriptors[:], TestMetaRangeDescriptor)...)
+
+	// sentLimits records the key limits of the batches sent to each range, by
+	// the start key of their first request.
+	var mu struct {
+		syncutil.Mutex
+		sentLimits map[string][]int64
+	}
+
+	// Each scan returns the first two keys of its span, subject to the limit of
+	// the batch.
+	var testFn simpleSendFn = func(
+		_ context.Context, ba roachpb.BatchRequest,
+	) (*roachpb.BatchResponse, error) {
+		mu.Lock()
+		firstKey := string(ba.Requests[0].GetInner().Header().Key)
+		mu.sentLimits[firstKey] = append(mu.sentLimits[firstKey], ba.MaxSpanRequestKeys)
+		mu.Unlock()
+		reply := ba.CreateReply()
+		remaining := ba.MaxSpanRequestKeys
+		for i, ru := range ba.Requests {
+			req := ru.GetScan()
+			resp := reply.Responses[i].GetScan()
+			for _, key := range []roachpb.Key{req.Key, req.Key.Next()} {
+				if remaining == 0 {
+					resp.ResumeSpan = &roachpb.Span{Key: key, EndKey: req.EndKey}
+					resp.ResumeReason = roachpb.RESUME_KEY_LIMIT
+					break
+				}
+				resp.Rows = append(resp.Rows, roachpb.KeyValue{Key: key})
+				resp.NumKeys++
+				remaining--
+			}
+		}
+		return reply, nil
+	}
+
+	for _, tc := range []struct {
+		parallel          bool
+		readAmplification int64
+		// expectedGroupLimit is the limit with which the ranges after the first
+		// are scanned in parallel.
+		expectedGroupLimit int64
+	}{
+		{parallel: false},
+		// The ranges b, c and d share a budget of 5 keys, so each of them is
+		// scanned with a limit of 1 key at first and b is scanned again.
+		{parallel: true, readAmplification: 2, expectedGroupLimit: 1},
+		// The ranges b, c and d share a budget of 15 keys, so each of them is
+		// scanned with the full limit.
+		{parallel: true, readAmplification: 4, expectedGroupLimit: 5},
+	} {
+		t.Run(fmt.Sprintf("parallel=%t/amplification=%d", tc.parallel, tc.readAmplification), func(t *testing.T) {
+			mu.Lock()
+			mu.sentLimits = make(map[string][]int64)
+			mu.Unlock()
+			st := cluster.MakeTestingClusterSettings()
+			parallelLimitedScansEnabled.Override(ctx, &st.SV, tc.parallel)
+			if tc.parallel {
+				parallelLimitedScansMaxReadAmplification.Override(ctx, &st.SV, tc.readAmplification)
+			}
+			cfg := DistSenderConfig{
+				AmbientCtx: log.MakeTestingAmbientCtxWithNewTracer(),
+				Clock:      clock,
+				NodeDescs:  g,
+				RPCContext: rpcContext,
+				TestingKnobs: ClientTestingKnobs{
+					TransportFactory: adaptSimpleTransport(testFn),
+				},
+				RangeDescriptorDB: descDB,
+				Settings:          st,
+			}
+			ds := NewDistSender(cfg)
+
+			var ba roachpb.BatchRequest
+			ba.Txn = &roachpb.Transaction{Name: "test"}
+			ba.MaxSpanRequestKeys = 5
+			for _, k := range []string{"a", "b", "c", "d"} {
+				ba.Add(roachpb.NewScan(roachpb.Key(k), roachpb.Key(k+k), false /* forUpdate */))
+			}
+			br, pErr := ds.Send(ctx, ba)
+			require.Nil(t, pErr)
+
+			expectedKeys := []int64{2, 2, 1, 0}
+			expectedResumeSpans := []*roachpb.Span{
+				nil,
+				nil,
+				{Key: roachpb.Key("c").Next(), EndKey: roachpb.Key("cc")},
+				{Key: roachpb.Key("d"), EndKey: roachpb.Key("dd")},
+			}
+			for i, ru := range br.Responses {
+				resp := ru.GetScan()
+				require.Equal(t, expectedKeys[i], resp.NumKeys, "response %d", i)
+				require.Len(t, resp.Rows, int(expectedKeys[i]), "response %d", i)
+				require.Equal(t, expectedResumeSpans[i], resp.ResumeSpan, "response %d", i)
+			}
+			expectedParallel := int64(0)
+			if tc.parallel {
+				expectedParallel = 1
+			}
+			require.Equal(t, expectedParallel, ds.Metrics().ParallelLimitedScans.Count())
+
+			if tc.parallel {
+				mu.Lock()
+				defer mu.Unlock()
+				require.Equal(t, []int64{5}, mu.sentLimits["a"])
+				// The scan of d may be canceled before it is sent, since the limit is
+				// reached before it.
+				for _, k := range []string{"b", "c", "d"} {
+					if k != "d" {
+						require.NotEmpty(t, mu.sentLimits[k], "range %s", k)
+					}
+					if len(mu.sentLimits[k]) > 0 {
+						require.Equal(t, tc.expectedGroupLimit, mu.sentLimits[k][0], "range %s", k)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParallelLimitedScanGroupLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		limit                int64
+		numGroups            int
+		maxReadAmplification int64
+		expected             int64
+	}{
+		{limit: 10, numGroups: 2, maxReadAmplification: 2, expected: 10},
+		{limit: 10, numGroups: 3, maxReadAmplification: 2, expected: 5},
+		{limit: 10, numGroups: 4, maxReadAmplification: 2, expected: 3},
+		{limit: 10, numGroups: 4, maxReadAmplification: 3, expected: 6},
+		{limit: 10, numGroups: 4, maxReadAmplification: 4, expected: 10},
+		{limit: 10, numGroups: 4, maxReadAmplification: 100, expected: 10},
+		// Every group may read at least one key.
+		{limit: 10, numGroups: 4, maxReadAmplification: 1, expected: 1},
+		{limit: 2, numGroups: 8, maxReadAmplification: 2, expected: 1},
+		{limit: math.MaxInt64, numGroups: 8, maxReadAmplification: 2, expected: math.MaxInt64},
+	} {
+		t.Run("", func(t *testing.T) {
+			require.Equal(t, tc.expected,
+				parallelLimitedScanGroupLimit(tc.limit, tc.numGroups, tc.maxReadAmplification))
+		})
+	}
+}
+
 func TestSenderTransport(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -5285,3 +5475,35 @@ func TestDistSenderNLHEFromUninitializedReplicaDoesNotCauseUnboundedBackoff(t *t
 			require.Equal(t, 1, rangeLookups)
 		})
 }
+
+// TestRequestSpansString verifies the formatting of the request spans added to
+// the DistSender's trace spans.
+func TestRequestSpansString(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// The keys are redacted, except for the table and index prefix of SQL
+	// keys.
+	indexPrefix := keys.SystemSQLCodec.IndexPrefix(106, 1)
+	var ba roachpb.BatchRequest
+	ba.Add(roachpb.NewScan(
+		encoding.EncodeStringAscending(indexPrefix.Clone(), "secret"),
+		keys.SystemSQLCodec.IndexPrefix(106, 2),
+		false, /* forUpdate */
+	))
+	ba.Add(roachpb.NewGet(roachpb.Key("d"), false /* forUpdate */))
+	s := requestSpansString(ba.Requests)
+	require.Equal(t, "Scan /Table/106/1/‹×›-/Table/106/2, Get ‹×›", s)
+
+	tenantPrefix := keys.MakeSQLCodec(roachpb.MakeTenantID(5)).IndexPrefix(106, 1)
+	ba = roachpb.BatchRequest{}
+	ba.Add(roachpb.NewGet(encoding.EncodeStringAscending(tenantPrefix, "secret"), false /* forUpdate */))
+	require.Equal(t, "Get /Tenant/5/Table/106/1/‹×›", requestSpansString(ba.Requests))
+
+	for i := 0; i < maxTracedRequestSpans; i++ {
+		ba.Add(roachpb.NewGet(roachpb.Key(fmt.Sprintf("e%d", i)), false /* forUpdate */))
+	}
+	s = requestSpansString(ba.Requests)
+	require.Equal(t, maxTracedRequestSpans, strings.Count(s, "Get "))
+	require.True(t, strings.HasSuffix(s, ", ... 1 more"), s)
+}
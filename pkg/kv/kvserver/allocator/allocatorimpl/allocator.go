@@ -19,6 +19,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/constraint"
@@ -998,23 +999,9 @@ func (a *Allocator) AllocateTargetFromList(
 	targetType TargetReplicaType,
 ) (roachpb.ReplicationTarget, string) {
 	existingReplicas := append(existingVoters, existingNonVoters...)
-	analyzedOverallConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
-		existingReplicas, conf.NumReplicas, conf.Constraints)
-	analyzedVoterConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
-		existingVoters, conf.GetNumVoters(), conf.VoterConstraints)
-
-	var constraintsChecker constraintsCheckFn
-	switch t := targetType; t {
-	case VoterTarget:
-		constraintsChecker = voterConstraintsCheckerForAllocation(
-			analyzedOverallConstraints,
-			analyzedVoterConstraints,
-		)
-	case NonVoterTarget:
-		constraintsChecker = nonVoterConstraintsCheckerForAllocation(analyzedOverallConstraints)
-	default:
-		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
-	}
+	constraintsChecker := a.allocationConstraintsChecker(
+		ctx, conf, existingVoters, existingReplicas, targetType,
+	)
 
 	// We'll consider the targets that have a non-voter as feasible
 	// relocation/up-replication targets for existing/new voting replicas, since
@@ -1050,6 +1037,242 @@ func (a *Allocator) AllocateTargetFromList(
 	return roachpb.ReplicationTarget{}, ""
 }
 
+// allocationConstraintsChecker returns the function used to check whether a
+// store satisfies the constraints of the span config for a new replica of the
+// given type.
+func (a *Allocator) allocationConstraintsChecker(
+	ctx context.Context,
+	conf roachpb.SpanConfig,
+	existingVoters, existingReplicas []roachpb.ReplicaDescriptor,
+	targetType TargetReplicaType,
+) constraintsCheckFn {
+	analyzedOverallConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
+		existingReplicas, conf.NumReplicas, conf.Constraints)
+	analyzedVoterConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
+		existingVoters, conf.GetNumVoters(), conf.VoterConstraints)
+
+	switch t := targetType; t {
+	case VoterTarget:
+		return voterConstraintsCheckerForAllocation(
+			analyzedOverallConstraints,
+			analyzedVoterConstraints,
+		)
+	case NonVoterTarget:
+		return nonVoterConstraintsCheckerForAllocation(analyzedOverallConstraints)
+	default:
+		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
+	}
+	return nil
+}
+
+// CandidateExplanation describes how the allocator regards a store as the
+// target of a new replica. It is only used to explain allocation decisions.
+type CandidateExplanation struct {
+	StoreID roachpb.StoreID
+	NodeID  roachpb.NodeID
+	// Existing is only set when explaining a rebalance, and is the store of the
+	// existing replica that the candidate would replace.
+	Existing roachpb.StoreID
+	// Reason is set for stores that are not considered as targets at all, and
+	// explains why. The remaining fields are only populated for stores that are
+	// considered.
+	Reason           string
+	Valid            bool
+	FullDisk         bool
+	Necessary        bool
+	HighReadAmp      bool
+	DiversityScore   float64
+	ConvergesScore   int
+	BalanceScore     int
+	RangeCount       int
+	QueriesPerSecond float64
+}
+
+// ExplainAllocateTarget returns an explanation of how every live store is
+// regarded as the target of a new replica of the given type. Stores that are
+// considered are returned first, in the order the allocator prefers them,
+// followed by the stores that are ruled out, along with the reason for it.
+// Throttled stores are never considered, so the reasons for which stores are
+// throttled are returned as well.
+func (a *Allocator) ExplainAllocateTarget(
+	ctx context.Context,
+	conf roachpb.SpanConfig,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	targetType TargetReplicaType,
+) ([]CandidateExplanation, storepool.ThrottledStoreReasons) {
+	allStores, _, _ := a.StorePool.GetStoreList(storepool.StoreFilterNone)
+	candidateStores, _, throttled := a.StorePool.GetStoreList(storepool.StoreFilterThrottled)
+
+	existingReplicas := make([]roachpb.ReplicaDescriptor, 0, len(existingVoters)+len(existingNonVoters))
+	existingReplicas = append(existingReplicas, existingVoters...)
+	existingReplicas = append(existingReplicas, existingNonVoters...)
+	constraintsChecker := a.allocationConstraintsChecker(
+		ctx, conf, existingVoters, existingReplicas, targetType,
+	)
+	existingReplicaSet := getReplicasForDiversityCalc(targetType, existingVoters, existingReplicas)
+	options := a.ScorerOptions(ctx)
+	candidates := rankedCandidateListForAllocation(
+		ctx,
+		candidateStores,
+		constraintsChecker,
+		existingReplicaSet,
+		a.StorePool.GetLocalitiesByStore(existingReplicaSet),
+		a.StorePool.IsStoreReadyForRoutineReplicaTransfer,
+		false, /* allowMultipleReplsPerNode */
+		options,
+	)
+
+	explanations := make([]CandidateExplanation, 0, len(allStores.Stores))
+	considered := make(map[roachpb.StoreID]struct{}, len(candidates))
+	for _, c := range candidates {
+		considered[c.store.StoreID] = struct{}{}
+		explanations = append(explanations, c.explanation(0 /* existing */))
+	}
+
+	candidateStoreMap := candidateStores.ToMap()
+	existingReplTargets := roachpb.MakeReplicaSet(existingReplicas).ReplicationTargets()
+	for _, s := range allStores.Stores {
+		if _, ok := considered[s.StoreID]; ok {
+			continue
+		}
+		constraintsOK, _ := constraintsChecker(s)
+		var reason string
+		switch {
+		case StoreHasReplica(s.StoreID, existingReplTargets):
+			reason = "store already has a replica"
+		case nodeHasReplica(s.Node.NodeID, existingReplTargets):
+			reason = "node already has a replica"
+		case candidateStoreMap[s.StoreID] == nil:
+			reason = "store is throttled or suspect"
+		case !a.StorePool.IsStoreReadyForRoutineReplicaTransfer(ctx, s.StoreID):
+			reason = "store is not ready for replica transfers"
+		case !constraintsOK:
+			reason = "store does not satisfy the constraints"
+		case !allocator.MaxCapacityCheck(s):
+			reason = "store disk is full"
+		default:
+			reason = "store read amplification is too high"
+		}
+		explanations = append(explanations, CandidateExplanation{
+			StoreID:          s.StoreID,
+			NodeID:           s.Node.NodeID,
+			Reason:           reason,
+			RangeCount:       int(s.Capacity.RangeCount),
+			QueriesPerSecond: s.Capacity.QueriesPerSecond,
+		})
+	}
+	return explanations, throttled
+}
+
+// ExplainRemoveTarget returns an explanation of how the existing replicas of
+// the given type are ranked for removal, in the order in which the allocator
+// prefers to remove them.
+func (a *Allocator) ExplainRemoveTarget(
+	ctx context.Context,
+	conf roachpb.SpanConfig,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	targetType TargetReplicaType,
+) []CandidateExplanation {
+	existingReplicas := make([]roachpb.ReplicaDescriptor, 0, len(existingVoters)+len(existingNonVoters))
+	existingReplicas = append(existingReplicas, existingVoters...)
+	existingReplicas = append(existingReplicas, existingNonVoters...)
+	replicasOfType := existingVoters
+	if targetType == NonVoterTarget {
+		replicasOfType = existingNonVoters
+	}
+	candidateStoreIDs := make(roachpb.StoreIDSlice, len(replicasOfType))
+	for i, repl := range replicasOfType {
+		candidateStoreIDs[i] = repl.StoreID
+	}
+	candidateStoreList, _, _ := a.StorePool.GetStoreListFromIDs(candidateStoreIDs, storepool.StoreFilterNone)
+
+	replicaSetForDiversityCalc := getReplicasForDiversityCalc(targetType, existingVoters, existingReplicas)
+	candidates := candidateListForRemoval(
+		ctx,
+		candidateStoreList,
+		a.removalConstraintsChecker(ctx, conf, existingVoters, existingReplicas, targetType),
+		a.StorePool.GetLocalitiesByStore(replicaSetForDiversityCalc),
+		a.ScorerOptions(ctx),
+	)
+
+	// The candidates are sorted from best to worst, and the worst candidates
+	// are the ones removed.
+	explanations := make([]CandidateExplanation, 0, len(candidates))
+	for i := len(candidates) - 1; i >= 0; i-- {
+		explanations = append(explanations, candidates[i].explanation(0 /* existing */))
+	}
+	return explanations
+}
+
+// ExplainRebalanceTarget returns an explanation of how the allocator regards
+// the stores that could replace each existing replica of the given type when
+// rebalancing the range. For every existing replica, whose store is reported
+// in the Existing field, the candidates are listed in the order the allocator
+// prefers them. Throttled stores are never considered, so the reasons for
+// which stores are throttled are returned as well.
+func (a *Allocator) ExplainRebalanceTarget(
+	ctx context.Context,
+	conf roachpb.SpanConfig,
+	existingVoters, existingNonVoters []roachpb.ReplicaDescriptor,
+	targetType TargetReplicaType,
+) ([]CandidateExplanation, storepool.ThrottledStoreReasons) {
+	sl, _, throttled := a.StorePool.GetStoreList(storepool.StoreFilterThrottled)
+
+	existingReplicas := make([]roachpb.ReplicaDescriptor, 0, len(existingVoters)+len(existingNonVoters))
+	existingReplicas = append(existingReplicas, existingVoters...)
+	existingReplicas = append(existingReplicas, existingNonVoters...)
+	removalConstraintsChecker, rebalanceConstraintsChecker := a.rebalanceConstraintsCheckers(
+		ctx, conf, existingVoters, existingReplicas, targetType,
+	)
+	replicaSetToRebalance := existingVoters
+	var replicasWithExcludedStores []roachpb.ReplicaDescriptor
+	if targetType == NonVoterTarget {
+		replicaSetToRebalance = existingNonVoters
+		replicasWithExcludedStores = existingVoters
+	}
+
+	replicaSetForDiversityCalc := getReplicasForDiversityCalc(targetType, existingVoters, existingReplicas)
+	results := rankedCandidateListForRebalancing(
+		ctx,
+		sl,
+		removalConstraintsChecker,
+		rebalanceConstraintsChecker,
+		replicaSetToRebalance,
+		replicasWithExcludedStores,
+		a.StorePool.GetLocalitiesByStore(replicaSetForDiversityCalc),
+		a.StorePool.IsStoreReadyForRoutineReplicaTransfer,
+		a.ScorerOptions(ctx),
+		a.Metrics,
+	)
+
+	var explanations []CandidateExplanation
+	for _, result := range results {
+		for _, c := range result.candidates {
+			explanations = append(explanations, c.explanation(result.existing.store.StoreID))
+		}
+	}
+	return explanations, throttled
+}
+
+// explanation returns the CandidateExplanation of the candidate. existing is
+// the store of the replica the candidate would replace, if any.
+func (c candidate) explanation(existing roachpb.StoreID) CandidateExplanation {
+	return CandidateExplanation{
+		StoreID:          c.store.StoreID,
+		NodeID:           c.store.Node.NodeID,
+		Existing:         existing,
+		Valid:            c.valid,
+		FullDisk:         c.fullDisk,
+		Necessary:        c.necessary,
+		HighReadAmp:      c.highReadAmp,
+		DiversityScore:   c.diversityScore,
+		ConvergesScore:   c.convergesScore,
+		BalanceScore:     int(c.balanceScore),
+		RangeCount:       c.rangeCount,
+		QueriesPerSecond: c.store.Capacity.QueriesPerSecond,
+	}
+}
+
 func (a Allocator) simulateRemoveTarget(
 	ctx context.Context,
 	targetStore roachpb.StoreID,
@@ -1138,26 +1361,9 @@ func (a Allocator) RemoveTarget(
 	}
 
 	existingReplicas := append(existingVoters, existingNonVoters...)
-	analyzedOverallConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
-		existingReplicas, conf.NumReplicas, conf.Constraints)
-	analyzedVoterConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
-		existingVoters, conf.GetNumVoters(), conf.VoterConstraints)
-
-	var constraintsChecker constraintsCheckFn
-	switch t := targetType; t {
-	case VoterTarget:
-		// Voting replicas have to abide by both the overall `constraints` (which
-		// apply to all replicas) and `voter_constraints` which apply only to voting
-		// replicas.
-		constraintsChecker = voterConstraintsCheckerForRemoval(
-			analyzedOverallConstraints,
-			analyzedVoterConstraints,
-		)
-	case NonVoterTarget:
-		constraintsChecker = nonVoterConstraintsCheckerForRemoval(analyzedOverallConstraints)
-	default:
-		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
-	}
+	constraintsChecker := a.removalConstraintsChecker(
+		ctx, conf, existingVoters, existingReplicas, targetType,
+	)
 
 	replicaSetForDiversityCalc := getReplicasForDiversityCalc(targetType, existingVoters, existingReplicas)
 	rankedCandidates := candidateListForRemoval(
@@ -1188,6 +1394,37 @@ func (a Allocator) RemoveTarget(
 	return roachpb.ReplicationTarget{}, "", errors.New("could not select an appropriate replica to be removed")
 }
 
+// removalConstraintsChecker returns the function used to check whether the
+// replica of the given type on a store is valid and necessary with regards to
+// the constraints of the span config, when considering it for removal.
+func (a *Allocator) removalConstraintsChecker(
+	ctx context.Context,
+	conf roachpb.SpanConfig,
+	existingVoters, existingReplicas []roachpb.ReplicaDescriptor,
+	targetType TargetReplicaType,
+) constraintsCheckFn {
+	analyzedOverallConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
+		existingReplicas, conf.NumReplicas, conf.Constraints)
+	analyzedVoterConstraints := constraint.AnalyzeConstraints(ctx, a.StorePool.GetStoreDescriptor,
+		existingVoters, conf.GetNumVoters(), conf.VoterConstraints)
+
+	switch t := targetType; t {
+	case VoterTarget:
+		// Voting replicas have to abide by both the overall `constraints` (which
+		// apply to all replicas) and `voter_constraints` which apply only to voting
+		// replicas.
+		return voterConstraintsCheckerForRemoval(
+			analyzedOverallConstraints,
+			analyzedVoterConstraints,
+		)
+	case NonVoterTarget:
+		return nonVoterConstraintsCheckerForRemoval(analyzedOverallConstraints)
+	default:
+		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
+	}
+	return nil
+}
+
 // RemoveVoter returns a suitable replica to remove from the provided replica
 // set. It first attempts to randomly select a target from the set of stores
 // that have greater than the average number of replicas. Failing that, it falls
@@ -1272,30 +1509,17 @@ func (a Allocator) RebalanceTarget(
 	existingReplicas := append(existingVoters, existingNonVoters...)
 
 	zero := roachpb.ReplicationTarget{}
-	analyzedOverallConstraints := constraint.AnalyzeConstraints(
-		ctx, a.StorePool.GetStoreDescriptor, existingReplicas, conf.NumReplicas, conf.Constraints)
-	analyzedVoterConstraints := constraint.AnalyzeConstraints(
-		ctx, a.StorePool.GetStoreDescriptor, existingVoters, conf.GetNumVoters(), conf.VoterConstraints)
-	var removalConstraintsChecker constraintsCheckFn
-	var rebalanceConstraintsChecker rebalanceConstraintsCheckFn
+	removalConstraintsChecker, rebalanceConstraintsChecker := a.rebalanceConstraintsCheckers(
+		ctx, conf, existingVoters, existingReplicas, targetType,
+	)
 	var replicaSetToRebalance, replicasWithExcludedStores []roachpb.ReplicaDescriptor
 	var otherReplicaSet []roachpb.ReplicaDescriptor
 
 	switch t := targetType; t {
 	case VoterTarget:
-		removalConstraintsChecker = voterConstraintsCheckerForRemoval(
-			analyzedOverallConstraints,
-			analyzedVoterConstraints,
-		)
-		rebalanceConstraintsChecker = voterConstraintsCheckerForRebalance(
-			analyzedOverallConstraints,
-			analyzedVoterConstraints,
-		)
 		replicaSetToRebalance = existingVoters
 		otherReplicaSet = existingNonVoters
 	case NonVoterTarget:
-		removalConstraintsChecker = nonVoterConstraintsCheckerForRemoval(analyzedOverallConstraints)
-		rebalanceConstraintsChecker = nonVoterConstraintsCheckerForRebalance(analyzedOverallConstraints)
 		replicaSetToRebalance = existingNonVoters
 		// When rebalancing non-voting replicas, we don't consider stores that
 		// already have voting replicas as possible candidates. Voting replicas are
@@ -1412,6 +1636,38 @@ func (a Allocator) RebalanceTarget(
 	return addTarget, removeTarget, string(detailsBytes), true
 }
 
+// rebalanceConstraintsCheckers returns the functions used to check whether the
+// existing replicas of the given type, and the stores that could replace them,
+// conform to the constraints of the span config when rebalancing.
+func (a *Allocator) rebalanceConstraintsCheckers(
+	ctx context.Context,
+	conf roachpb.SpanConfig,
+	existingVoters, existingReplicas []roachpb.ReplicaDescriptor,
+	targetType TargetReplicaType,
+) (constraintsCheckFn, rebalanceConstraintsCheckFn) {
+	analyzedOverallConstraints := constraint.AnalyzeConstraints(
+		ctx, a.StorePool.GetStoreDescriptor, existingReplicas, conf.NumReplicas, conf.Constraints)
+	analyzedVoterConstraints := constraint.AnalyzeConstraints(
+		ctx, a.StorePool.GetStoreDescriptor, existingVoters, conf.GetNumVoters(), conf.VoterConstraints)
+
+	switch t := targetType; t {
+	case VoterTarget:
+		return voterConstraintsCheckerForRemoval(
+				analyzedOverallConstraints,
+				analyzedVoterConstraints,
+			), voterConstraintsCheckerForRebalance(
+				analyzedOverallConstraints,
+				analyzedVoterConstraints,
+			)
+	case NonVoterTarget:
+		return nonVoterConstraintsCheckerForRemoval(analyzedOverallConstraints),
+			nonVoterConstraintsCheckerForRebalance(analyzedOverallConstraints)
+	default:
+		log.KvDistribution.Fatalf(ctx, "unsupported targetReplicaType: %v", t)
+	}
+	return nil, nil
+}
+
 // RebalanceVoter returns a suitable store for a rebalance target with required
 // attributes. Rebalance targets are selected via the same mechanism as
 // AllocateVoter(), except the chosen target must follow some additional
@@ -1746,7 +2002,7 @@ func (a *Allocator) TransferLeaseTarget(
 		// falls back to `leaseCountConvergence`. Rationalize this or refactor this
 		// logic to be more clear.
 		transferDec, repl := a.shouldTransferLeaseForAccessLocality(
-			ctx, source, existing, stats, nil, candidateLeasesMean,
+			ctx, conf, source, existing, stats, nil, candidateLeasesMean,
 		)
 		if !excludeLeaseRepl {
 			switch transferDec {
@@ -1982,6 +2238,7 @@ func (a *Allocator) ShouldTransferLease(
 
 	transferDec, _ := a.shouldTransferLeaseForAccessLocality(
 		ctx,
+		conf,
 		source,
 		existing,
 		stats,
@@ -2010,6 +2267,7 @@ func (a *Allocator) ShouldTransferLease(
 // follow-the-workload strategy would still prefer selecting the local store.
 func (a Allocator) FollowTheWorkloadPrefersLocal(
 	ctx context.Context,
+	conf roachpb.SpanConfig,
 	sl storepool.StoreList,
 	source roachpb.StoreDescriptor,
 	candidate roachpb.StoreID,
@@ -2017,7 +2275,7 @@ func (a Allocator) FollowTheWorkloadPrefersLocal(
 	stats *replicastats.ReplicaStats,
 ) bool {
 	adjustments := make(map[roachpb.StoreID]float64)
-	decision, _ := a.shouldTransferLeaseForAccessLocality(ctx, source, existing, stats, adjustments, sl.CandidateLeases.Mean)
+	decision, _ := a.shouldTransferLeaseForAccessLocality(ctx, conf, source, existing, stats, adjustments, sl.CandidateLeases.Mean)
 	if decision == decideWithoutStats {
 		return false
 	}
@@ -2033,6 +2291,7 @@ func (a Allocator) FollowTheWorkloadPrefersLocal(
 
 func (a Allocator) shouldTransferLeaseForAccessLocality(
 	ctx context.Context,
+	conf roachpb.SpanConfig,
 	source roachpb.StoreDescriptor,
 	existing []roachpb.ReplicaDescriptor,
 	stats *replicastats.ReplicaStats,
@@ -2051,6 +2310,15 @@ func (a Allocator) shouldTransferLeaseForAccessLocality(
 		}
 	}
 
+	// Nodes running older versions ignore the zone config field, so keep using
+	// locality-based rebalancing until the cluster is upgraded lest the nodes
+	// disagree on lease placement.
+	if conf.LatencyBasedLeaseRebalancing &&
+		a.StorePool.St.Version.IsActive(ctx, clusterversion.LatencyBasedLeaseRebalancing) {
+		return a.shouldTransferLeaseForGatewayLatency(
+			ctx, source, existing, stats, replicaLocalities, rebalanceAdjustments, candidateLeasesMean)
+	}
+
 	qpsStats, qpsStatsDur := stats.PerLocalityDecayingRate()
 
 	// If we haven't yet accumulated enough data, avoid transferring for now,
@@ -2134,6 +2402,152 @@ func (a Allocator) shouldTransferLeaseForAccessLocality(
 	return shouldNotTransfer, bestRepl
 }
 
+// shouldTransferLeaseForGatewayLatency is the variant of
+// shouldTransferLeaseForAccessLocality used for ranges whose span config
+// enables latency-based lease rebalancing. Instead of weighing each replica by
+// the number of requests coming from localities near it, it estimates the mean
+// latency that requests would incur between their gateway and each replica if
+// that replica held the lease, and weighs each replica by the inverse of that
+// latency. The weights are then scored by loadBasedLeaseRebalanceScore, like
+// those of shouldTransferLeaseForAccessLocality, so that lease counts are still
+// taken into account.
+//
+// Latencies are only measured between the local node and the other nodes, so
+// the latency between a gateway and a replica is estimated as zero if the
+// gateway is the replica's node or has the same locality, as the measured
+// latency if either of them is the local node, and as the sum of their
+// measured latencies to the local node otherwise. The latter is an upper bound
+// of the actual latency, which errs on the side of keeping the lease where the
+// latencies are known.
+func (a Allocator) shouldTransferLeaseForGatewayLatency(
+	ctx context.Context,
+	source roachpb.StoreDescriptor,
+	existing []roachpb.ReplicaDescriptor,
+	stats *replicastats.ReplicaStats,
+	replicaLocalities map[roachpb.NodeID]roachpb.Locality,
+	rebalanceAdjustments map[roachpb.StoreID]float64,
+	candidateLeasesMean float64,
+) (transferDecision, roachpb.ReplicaDescriptor) {
+	gatewayStats, gatewayStatsDur := stats.PerGatewayDecayingRate()
+	// See shouldTransferLeaseForAccessLocality for why we don't fall back to the
+	// algorithm that doesn't use stats here.
+	if gatewayStatsDur < MinLeaseTransferStatsDuration {
+		return shouldNotTransfer, roachpb.ReplicaDescriptor{}
+	}
+
+	localNodeID := source.Node.NodeID
+	localLatencies := make(map[roachpb.NodeID]time.Duration)
+	// localLatency returns the measured latency between the local node and the
+	// given node, if any.
+	localLatency := func(nodeID roachpb.NodeID) (time.Duration, bool) {
+		if nodeID == localNodeID {
+			return 0, true
+		}
+		if latency, ok := localLatencies[nodeID]; ok {
+			return latency, true
+		}
+		addr, err := a.StorePool.Gossip.GetNodeIDAddress(nodeID)
+		if err != nil {
+			log.KvDistribution.Errorf(ctx, "missing address for n%d: %+v", nodeID, err)
+			return 0, false
+		}
+		latency, ok := a.nodeLatencyFn(addr.String())
+		if ok {
+			localLatencies[nodeID] = latency
+		}
+		return latency, ok
+	}
+
+	// Drop the requests from gateways whose latency to the local node is
+	// unknown, since they can't be compared against each replica.
+	var totalQPS float64
+	gatewayLocalities := make(map[roachpb.NodeID]string, len(gatewayStats))
+	for gatewayID, qps := range gatewayStats {
+		if _, ok := localLatency(gatewayID); !ok || qps <= 0 {
+			delete(gatewayStats, gatewayID)
+			continue
+		}
+		totalQPS += qps
+		gatewayLocalities[gatewayID] = a.StorePool.GetNodeLocalityString(gatewayID)
+	}
+	if totalQPS == 0 {
+		return decideWithoutStats, roachpb.ReplicaDescriptor{}
+	}
+
+	// meanLatency estimates the mean latency between the gateways of the
+	// requests and the given replica's node, weighted by their QPS.
+	meanLatency := func(nodeID roachpb.NodeID) (time.Duration, bool) {
+		replicaLatency, ok := localLatency(nodeID)
+		if !ok {
+			return 0, false
+		}
+		replicaLocality := replicaLocalities[nodeID].String()
+		var sum float64
+		for gatewayID, qps := range gatewayStats {
+			var latency time.Duration
+			switch {
+			case gatewayID == nodeID:
+			case replicaLocality == gatewayLocalities[gatewayID]:
+			case nodeID == localNodeID:
+				latency, _ = localLatency(gatewayID)
+			case gatewayID == localNodeID:
+				latency = replicaLatency
+			default:
+				gatewayLatency, _ := localLatency(gatewayID)
+				latency = gatewayLatency + replicaLatency
+			}
+			sum += float64(latency) * qps
+		}
+		return time.Duration(sum / totalQPS), true
+	}
+	// latencyWeight turns a mean latency into a replica weight, which is higher
+	// for lower latencies.
+	latencyWeight := func(latency time.Duration) float64 {
+		latencyMillis := float64(latency) / float64(time.Millisecond)
+		return 1 / math.Max(minReplicaWeight, latencyMillis)
+	}
+
+	sourceLatency, _ := meanLatency(localNodeID)
+	sourceWeight := latencyWeight(sourceLatency)
+	log.KvDistribution.VEventf(ctx, 1,
+		"shouldTransferLease gatewayStats: %+v, sourceLatency: %s", gatewayStats, sourceLatency)
+
+	var bestRepl roachpb.ReplicaDescriptor
+	bestReplScore := int32(math.MinInt32)
+	for _, repl := range existing {
+		if repl.NodeID == localNodeID {
+			continue
+		}
+		storeDesc, ok := a.StorePool.GetStoreDescriptor(repl.StoreID)
+		if !ok {
+			continue
+		}
+		remoteLatency, ok := localLatency(repl.NodeID)
+		if !ok {
+			continue
+		}
+		replLatency, ok := meanLatency(repl.NodeID)
+		if !ok {
+			continue
+		}
+		remoteWeight := latencyWeight(replLatency)
+		replScore, rebalanceAdjustment := loadBasedLeaseRebalanceScore(
+			ctx, a.StorePool.St, remoteWeight, remoteLatency, storeDesc, sourceWeight, source, candidateLeasesMean)
+		if replScore > bestReplScore {
+			bestReplScore = replScore
+			bestRepl = repl
+		}
+		if rebalanceAdjustments != nil {
+			rebalanceAdjustments[repl.StoreID] = rebalanceAdjustment
+		}
+	}
+
+	if bestReplScore > 0 {
+		return shouldTransfer, bestRepl
+	}
+	return shouldNotTransfer, bestRepl
+}
+
 // loadBasedLeaseRebalanceScore attempts to give a score to how desirable it
 // would be to transfer a range lease from the local store to a remote store.
 // It does so using a formula based on the latency between the stores and
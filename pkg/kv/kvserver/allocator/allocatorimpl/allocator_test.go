@@ -590,6 +590,96 @@ func TestAllocatorNoAvailableDisks(t *testing.T) {
 	}
 }
 
+// TestAllocatorExplainAllocateTarget verifies that the stores considered as
+// targets for a new replica are explained along with their scores, and that
+// the reasons for ruling out the remaining stores are reported.
+func TestAllocatorExplainAllocateTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper, g, _, a, _ := CreateTestAllocator(ctx, 10, true /* deterministic */)
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(sameDCStores, t)
+
+	conf := roachpb.SpanConfig{
+		NumReplicas: 3,
+		Constraints: []roachpb.ConstraintsConjunction{
+			{Constraints: []roachpb.Constraint{{Value: "ssd", Type: roachpb.Constraint_REQUIRED}}},
+		},
+	}
+	existingVoters := []roachpb.ReplicaDescriptor{{NodeID: 1, StoreID: 1}}
+	candidates, throttled := a.ExplainAllocateTarget(
+		ctx, conf, existingVoters, nil /* existingNonVoters */, VoterTarget,
+	)
+	require.Empty(t, throttled)
+	require.Len(t, candidates, len(sameDCStores))
+
+	// The only store satisfying the constraints without a replica is listed
+	// first.
+	require.Equal(t, roachpb.StoreID(2), candidates[0].StoreID)
+	require.Empty(t, candidates[0].Reason)
+	require.True(t, candidates[0].Valid)
+
+	reasons := make(map[roachpb.StoreID]string)
+	for _, c := range candidates[1:] {
+		reasons[c.StoreID] = c.Reason
+	}
+	require.Equal(t, map[roachpb.StoreID]string{
+		1: "store already has a replica",
+		3: "store does not satisfy the constraints",
+		4: "store does not satisfy the constraints",
+		5: "store does not satisfy the constraints",
+	}, reasons)
+}
+
+// TestAllocatorExplainRemoveAndRebalanceTarget verifies that removals are
+// explained with the ranking of the existing replicas, and rebalances with the
+// stores that could replace each existing replica.
+func TestAllocatorExplainRemoveAndRebalanceTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper, g, _, a, _ := CreateTestAllocator(ctx, 10, true /* deterministic */)
+	defer stopper.Stop(ctx)
+	gossiputil.NewStoreGossiper(g).GossipStores(sameDCStores, t)
+
+	conf := roachpb.SpanConfig{
+		NumReplicas: 2,
+		Constraints: []roachpb.ConstraintsConjunction{
+			{Constraints: []roachpb.Constraint{{Value: "ssd", Type: roachpb.Constraint_REQUIRED}}},
+		},
+	}
+
+	// The voter on s3 doesn't satisfy the constraints, so it is the first to be
+	// removed.
+	existingVoters := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1}, {NodeID: 2, StoreID: 2}, {NodeID: 3, StoreID: 3},
+	}
+	candidates := a.ExplainRemoveTarget(
+		ctx, conf, existingVoters, nil /* existingNonVoters */, VoterTarget,
+	)
+	require.Len(t, candidates, len(existingVoters))
+	require.Equal(t, roachpb.StoreID(3), candidates[0].StoreID)
+	require.False(t, candidates[0].Valid)
+	for _, c := range candidates[1:] {
+		require.True(t, c.Valid)
+	}
+
+	// The voter on s3 is replaced by s2, which is the only other store that
+	// satisfies the constraints.
+	existingVoters = []roachpb.ReplicaDescriptor{{NodeID: 1, StoreID: 1}, {NodeID: 3, StoreID: 3}}
+	candidates, throttled := a.ExplainRebalanceTarget(
+		ctx, conf, existingVoters, nil /* existingNonVoters */, VoterTarget,
+	)
+	require.Empty(t, throttled)
+	require.NotEmpty(t, candidates)
+	require.Equal(t, roachpb.StoreID(2), candidates[0].StoreID)
+	require.Equal(t, roachpb.StoreID(3), candidates[0].Existing)
+	require.True(t, candidates[0].Valid)
+}
+
 func TestAllocatorReadAmpCheck(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -5424,6 +5514,113 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 	}
 }
 
+func TestAllocatorTransferLeaseTargetGatewayLatency(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper, g, _, storePool, _ := storepool.CreateTestStorePool(ctx,
+		storepool.TestTimeUntilStoreDeadOff, true, /* deterministic */
+		func() int { return 10 }, /* nodeCount */
+		livenesspb.NodeLivenessStatus_LIVE)
+	defer stopper.Stop(ctx)
+
+	// 3 stores in different localities with the same lease count, so that only
+	// the latency of the requests decides where the lease goes.
+	var stores []*roachpb.StoreDescriptor
+	for i := 1; i <= 3; i++ {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node: roachpb.NodeDescriptor{
+				NodeID:  roachpb.NodeID(i),
+				Address: util.MakeUnresolvedAddr("tcp", strconv.Itoa(i)),
+				Locality: roachpb.Locality{
+					Tiers: []roachpb.Tier{
+						{Key: "l", Value: strconv.Itoa(i)},
+					},
+				},
+			},
+			Capacity: roachpb.StoreCapacity{LeaseCount: 10},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+	for _, store := range stores {
+		if err := g.SetNodeDescriptor(&store.Node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manual := timeutil.NewManualTime(timeutil.Unix(0, 123))
+	clock := hlc.NewClock(manual, time.Nanosecond /* maxOffset */)
+	fromGateway := make(map[roachpb.NodeID]*replicastats.ReplicaStats)
+	for i := 1; i <= 3; i++ {
+		stats := replicastats.NewReplicaStats(clock, nil /* getNodeLocality */)
+		for j := 0; j < 100*int(MinLeaseTransferStatsDuration.Seconds()); j++ {
+			stats.RecordCount(1, roachpb.NodeID(i))
+		}
+		fromGateway[roachpb.NodeID(i)] = stats
+	}
+	manual.Advance(MinLeaseTransferStatsDuration)
+
+	noLatency := map[string]time.Duration{}
+	highLatency := map[string]time.Duration{
+		stores[0].Node.Address.String(): 50 * time.Millisecond,
+		stores[1].Node.Address.String(): 50 * time.Millisecond,
+		stores[2].Node.Address.String(): 50 * time.Millisecond,
+	}
+
+	existing := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1, ReplicaID: 1},
+		{NodeID: 2, StoreID: 2, ReplicaID: 2},
+		{NodeID: 3, StoreID: 3, ReplicaID: 3},
+	}
+
+	conf := emptySpanConfig()
+	conf.LatencyBasedLeaseRebalancing = true
+
+	testCases := []struct {
+		leaseholder roachpb.StoreID
+		latency     map[string]time.Duration
+		gateway     roachpb.NodeID
+		expected    roachpb.StoreID
+	}{
+		{leaseholder: 1, latency: noLatency, gateway: 1, expected: 0},
+		{leaseholder: 1, latency: noLatency, gateway: 2, expected: 0},
+		{leaseholder: 1, latency: highLatency, gateway: 1, expected: 0},
+		{leaseholder: 1, latency: highLatency, gateway: 2, expected: 2},
+		{leaseholder: 1, latency: highLatency, gateway: 3, expected: 3},
+		{leaseholder: 2, latency: highLatency, gateway: 1, expected: 1},
+		{leaseholder: 2, latency: highLatency, gateway: 2, expected: 0},
+		{leaseholder: 3, latency: highLatency, gateway: 2, expected: 2},
+	}
+
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			a := MakeAllocator(storePool, func(addr string) (time.Duration, bool) {
+				return c.latency[addr], true
+			}, nil)
+			target := a.TransferLeaseTarget(
+				ctx,
+				conf,
+				existing,
+				&mockRepl{
+					replicationFactor: 3,
+					storeID:           c.leaseholder,
+				},
+				fromGateway[c.gateway],
+				false,
+				allocator.TransferLeaseOptions{
+					CheckCandidateFullness: true,
+				},
+			)
+			if c.expected != target.StoreID {
+				t.Errorf("expected %d, got %d", c.expected, target.StoreID)
+			}
+		})
+	}
+}
+
 func TestLoadBasedLeaseRebalanceScore(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
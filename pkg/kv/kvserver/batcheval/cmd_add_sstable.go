@@ -131,6 +131,7 @@ func EvalAddSSTable(
 	start, end := storage.MVCCKey{Key: args.Key}, storage.MVCCKey{Key: args.EndKey}
 	sst := args.Data
 	sstToReqTS := args.SSTTimestampToRequestTimestamp
+	sstStats := args.MVCCStats
 
 	var span *tracing.Span
 	var err error
@@ -161,10 +162,27 @@ func EvalAddSSTable(
 			"AddSSTable requests must set SSTTimestampToRequestTimestamp")
 	}
 
+	// If requested, rewrite the SST's key prefixes, e.g. to move the keys from
+	// the tenant keyspace of another cluster into the local one. The given
+	// stats no longer apply to the rewritten keys, so we compute them below.
+	if len(args.KeyPrefixRewrites) > 0 {
+		sst, err = storage.RewriteSSTKeyPrefixes(
+			ctx, cArgs.EvalCtx.ClusterSettings(), sst, args.KeyPrefixRewrites)
+		if err != nil {
+			return result.Result{}, errors.Wrap(err, "rewriting SST key prefixes")
+		}
+		// The rewrites may map keys anywhere, so make sure that the rewritten
+		// keys are within the request span, which was used to declare latches.
+		if err := checkSSTSpan(sst, start, end); err != nil {
+			return result.Result{}, errors.Wrap(err, "rewriting SST key prefixes")
+		}
+		sstStats = nil
+	}
+
 	// Under the race detector, check that the SST contents satisfy AddSSTable
 	// requirements. We don't always do this otherwise, due to the cost.
 	if util.RaceEnabled {
-		if err := assertSSTContents(sst, sstToReqTS, args.MVCCStats); err != nil {
+		if err := assertSSTContents(sst, sstToReqTS, sstStats); err != nil {
 			return result.Result{}, err
 		}
 	}
@@ -207,12 +225,12 @@ func EvalAddSSTable(
 		if err == nil {
 			usePrefixSeek = bytes > prefixSeekCollisionCheckRatio*uint64(len(sst))
 		}
-		if args.MVCCStats != nil {
+		if sstStats != nil {
 			// If the incoming SST is small, use a prefix seek. Very little time is
 			// usually spent iterating over keys in these cases anyway, so it makes
 			// sense to use prefix seeks when the max number of seeks we'll do is
 			// bounded with a small number on the SST side.
-			usePrefixSeek = usePrefixSeek || args.MVCCStats.KeyCount < 100
+			usePrefixSeek = usePrefixSeek || sstStats.KeyCount < 100
 		}
 		desc := cArgs.EvalCtx.Desc()
 		leftPeekBound, rightPeekBound := rangeTombstonePeekBounds(
@@ -261,8 +279,8 @@ func EvalAddSSTable(
 
 	// Get the MVCCStats for the SST being ingested.
 	var stats enginepb.MVCCStats
-	if args.MVCCStats != nil {
-		stats = *args.MVCCStats
+	if sstStats != nil {
+		stats = *sstStats
 	} else {
 		log.VEventf(ctx, 2, "computing MVCCStats for SSTable [%s,%s)", start.Key, end.Key)
 		stats, err = storage.ComputeStatsForIter(sstIter, h.Timestamp.WallTime)
@@ -482,6 +500,39 @@ func EvalAddSSTable(
 	}, nil
 }
 
+// checkSSTSpan returns an error if the first or last key of the SST, including
+// range key bounds, is outside of [start, end).
+func checkSSTSpan(sst []byte, start, end storage.MVCCKey) error {
+	iter, err := storage.NewPebbleMemSSTIterator(sst, true /* verify */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsAndRanges,
+		LowerBound: keys.MinKey,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	iter.SeekGE(storage.MVCCKey{Key: keys.MinKey})
+	if ok, err := iter.Valid(); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	} else if unsafeKey := iter.UnsafeKey(); unsafeKey.Less(start) {
+		return errors.Errorf("first key %s not in request range [%s,%s)",
+			unsafeKey.Key, start.Key, end.Key)
+	}
+
+	iter.SeekGE(end)
+	if ok, err := iter.Valid(); err != nil {
+		return err
+	} else if ok {
+		return errors.Errorf("last key %s not in request range [%s,%s)",
+			iter.UnsafeKey(), start.Key, end.Key)
+	}
+	return nil
+}
+
 // assertSSTContents checks that the SST contains expected inputs:
 //
 // * Only SST set operations (not explicitly verified).
@@ -1112,6 +1112,114 @@ func TestEvalAddSSTableRangefeed(t *testing.T) {
 	}
 }
 
+// TestEvalAddSSTableKeyPrefixRewrites tests that EvalAddSSTable rewrites the
+// SST key prefixes when KeyPrefixRewrites is given.
+func TestEvalAddSSTableKeyPrefixRewrites(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	storage.DisableMetamorphicSimpleValueEncoding(t)
+
+	rewrites := []roachpb.KeyPrefixRewrite{{OldPrefix: roachpb.Key("old/"), NewPrefix: roachpb.Key("new/")}}
+
+	testcases := map[string]struct {
+		sst       kvs
+		rewrites  []roachpb.KeyPrefixRewrite // defaults to rewrites
+		toReqTS   int64                      // SSTTimestampToRequestTimestamp
+		expect    kvs
+		expectErr string
+	}{
+		"point keys": {
+			sst:    kvs{pointKV("old/a", 1, "a1"), pointKV("old/b", 2, "b2"), pointKV("old/b", 1, "")},
+			expect: kvs{pointKV("new/a", 1, "a1"), pointKV("new/b", 2, "b2"), pointKV("new/b", 1, "")},
+		},
+		"range keys up to prefix end": {
+			sst:    kvs{pointKV("old/a", 1, "a1"), rangeKV("old/c", "old0", 2, "")},
+			expect: kvs{pointKV("new/a", 1, "a1"), rangeKV("new/c", "new0", 2, "")},
+		},
+		"with SSTTimestampToRequestTimestamp": {
+			sst:     kvs{pointKV("old/a", 1, "a1"), rangeKV("old/c", "old/d", 1, "")},
+			toReqTS: 1,
+			expect:  kvs{pointKV("new/a", 10, "a1"), rangeKV("new/c", "new/d", 10, "")},
+		},
+		"key outside prefixes errors": {
+			sst:       kvs{pointKV("old/a", 1, "a1"), pointKV("other", 1, "o1")},
+			expectErr: "does not match any key prefix rewrite",
+		},
+		"rewritten key outside request span errors": {
+			sst: kvs{pointKV("old/a", 1, "a1"), pointKV("zzz/a", 1, "z1")},
+			rewrites: []roachpb.KeyPrefixRewrite{
+				{OldPrefix: roachpb.Key("old/"), NewPrefix: roachpb.Key("new/")},
+				{OldPrefix: roachpb.Key("zzz/"), NewPrefix: roachpb.Key("out/")},
+			},
+			expectErr: "rewriting SST key prefixes: last key",
+		},
+		"rewritten key before request span errors": {
+			sst: kvs{pointKV("aaa/a", 1, "a1"), pointKV("old/a", 1, "a1")},
+			rewrites: []roachpb.KeyPrefixRewrite{
+				{OldPrefix: roachpb.Key("aaa/"), NewPrefix: roachpb.Key("abc/")},
+				{OldPrefix: roachpb.Key("old/"), NewPrefix: roachpb.Key("new/")},
+			},
+			expectErr: "rewriting SST key prefixes: first key",
+		},
+		"rewritten range key outside request span errors": {
+			sst: kvs{pointKV("old/a", 1, "a1"), rangeKV("old/c", "old1", 2, "")},
+			rewrites: []roachpb.KeyPrefixRewrite{
+				{OldPrefix: roachpb.Key("old/"), NewPrefix: roachpb.Key("new/")},
+				{OldPrefix: roachpb.Key("old1"), NewPrefix: roachpb.Key("new1")},
+			},
+			expectErr: "rewriting SST key prefixes: last key",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			st := cluster.MakeTestingClusterSettings()
+			ctx := context.Background()
+
+			engine := storage.NewDefaultInMemForTesting()
+			defer engine.Close()
+
+			sst, _, _ := storageutils.MakeSST(t, st, tc.sst)
+			keyPrefixRewrites := tc.rewrites
+			if keyPrefixRewrites == nil {
+				keyPrefixRewrites = rewrites
+			}
+			stats := &enginepb.MVCCStats{}
+			result, err := batcheval.EvalAddSSTable(ctx, engine, batcheval.CommandArgs{
+				EvalCtx: (&batcheval.MockEvalCtx{ClusterSettings: st, Desc: &roachpb.RangeDescriptor{}}).EvalContext(),
+				Header: roachpb.Header{
+					Timestamp: hlc.Timestamp{WallTime: 10},
+				},
+				Stats: stats,
+				Args: &roachpb.AddSSTableRequest{
+					RequestHeader:                  roachpb.RequestHeader{Key: roachpb.Key("new/"), EndKey: roachpb.Key("new0")},
+					Data:                           sst,
+					MVCCStats:                      storageutils.SSTStats(t, sst, 0),
+					SSTTimestampToRequestTimestamp: hlc.Timestamp{WallTime: tc.toReqTS},
+					DisallowConflicts:              true,
+					KeyPrefixRewrites:              keyPrefixRewrites,
+				},
+			}, &roachpb.AddSSTableResponse{})
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, result.Replicated.AddSSTable)
+			require.NoError(t, engine.WriteFile("sst", result.Replicated.AddSSTable.Data))
+			require.NoError(t, engine.IngestExternalFiles(ctx, []string{"sst"}))
+
+			require.Equal(t, tc.expect, storageutils.ScanEngine(t, engine))
+
+			// The stats must have been computed for the rewritten keys.
+			require.Zero(t, stats.ContainsEstimates, "found estimated stats")
+			require.Equal(t, storageutils.EngineStats(t, engine, stats.LastUpdateNanos), stats)
+		})
+	}
+}
+
 // TestDBAddSSTable tests application of an SST to a database, both in-memory
 // and on disk.
 func TestDBAddSSTable(t *testing.T) {
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -143,6 +144,17 @@ func evalExport(
 		}
 	}
 
+	// If no versions were written to the range above the start time, there is
+	// nothing to export. Export holds latches over the span at its timestamp, so
+	// any write at or below it has already been accounted for.
+	if args.SkipIfUnchanged && !args.StartTime.IsEmpty() {
+		if lastWrite, ok := cArgs.EvalCtx.GetLastWriteTimestamp(); ok && lastWrite.LessEq(args.StartTime) {
+			log.VEventf(ctx, 2, "[%s, %s) has not been written to above %s (last write: %s), returning empty ExportResponse",
+				args.Key, args.EndKey, args.StartTime, lastWrite)
+			return result.Result{}, nil
+		}
+	}
+
 	var exportAllRevisions bool
 	switch args.MVCCFilter {
 	case roachpb.MVCCFilter_Latest:
@@ -182,6 +194,10 @@ func evalExport(
 		resumeKeyTS = args.ResumeKeyTS
 	}
 
+	// Elastic work, such as the export requests issued by BACKUP, yields once it
+	// has run for longer than allotted to it; see admission.ElasticWorkHandle.
+	elasticHandle := admission.ElasticWorkHandleFromContext(ctx)
+
 	var curSizeOfExportedSSTs int64
 	for start := args.Key; start != nil; {
 		destFile := &storage.MemFile{}
@@ -268,6 +284,21 @@ func evalExport(
 				}
 				break
 			}
+
+			// Only paginated requests can yield, since the caller must be prepared
+			// to handle a resume span.
+			if resume.Key != nil {
+				if overLimit, elapsed := elasticHandle.OverLimit(); overLimit {
+					log.VEventf(ctx, 2, "export of [%s, %s) yielding at %s after %s",
+						args.Key, args.EndKey, resume.Key, elapsed)
+					reply.ResumeSpan = &roachpb.Span{
+						Key:    resume.Key,
+						EndKey: args.EndKey,
+					}
+					reply.ResumeReason = roachpb.RESUME_ELASTIC_LIMIT
+					break
+				}
+			}
 		}
 	}
 
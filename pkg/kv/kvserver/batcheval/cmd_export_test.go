@@ -33,9 +33,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
@@ -668,6 +670,79 @@ func assertEqualKVs(
 	}
 }
 
+// TestExportCmdElasticLimit verifies that a paginated export request returns
+// early with a RESUME_ELASTIC_LIMIT resume span once the elastic work handle in
+// its context is over its allotted CPU time.
+func TestExportCmdElasticLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	// Emit an SST per key, so that the handle is consulted after every key.
+	batcheval.ExportRequestTargetFileSize.Override(ctx, &st.SV, 1)
+
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+	const numKeys = 10
+	for i := 0; i < numKeys; i++ {
+		key := roachpb.Key(fmt.Sprintf("k%02d", i))
+		require.NoError(t, storage.MVCCPut(ctx, eng, nil, key, hlc.Timestamp{WallTime: 1},
+			hlc.ClockTimestamp{}, roachpb.MakeValueFromString("v"), nil))
+	}
+
+	export := func(ctx context.Context, targetBytes int64) *roachpb.ExportResponse {
+		cmd, ok := batcheval.LookupCommand(roachpb.Export)
+		require.True(t, ok)
+		var resp roachpb.ExportResponse
+		_, err := cmd.EvalRO(ctx, eng, batcheval.CommandArgs{
+			EvalCtx: (&batcheval.MockEvalCtx{
+				ClusterSettings: st,
+				Desc:            &roachpb.RangeDescriptor{},
+			}).EvalContext(),
+			Header: roachpb.Header{
+				Timestamp:   hlc.Timestamp{WallTime: 10},
+				TargetBytes: targetBytes,
+			},
+			Args: &roachpb.ExportRequest{
+				RequestHeader: roachpb.RequestHeader{Key: roachpb.Key("k"), EndKey: roachpb.Key("l")},
+				MVCCFilter:    roachpb.MVCCFilter_Latest,
+			},
+		}, &resp)
+		require.NoError(t, err)
+		return &resp
+	}
+
+	overLimitCtx := func() context.Context {
+		mt := timeutil.NewManualTime(timeutil.Unix(0, 0))
+		h := admission.NewElasticWorkHandle(time.Second, mt)
+		mt.Advance(2 * time.Second)
+		return admission.ContextWithElasticWorkHandle(ctx, h)
+	}
+
+	t.Run("no handle", func(t *testing.T) {
+		resp := export(ctx, 1<<20)
+		require.Len(t, resp.Files, numKeys)
+		require.Nil(t, resp.ResumeSpan)
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		resp := export(overLimitCtx(), 1<<20)
+		require.Len(t, resp.Files, 1)
+		require.Equal(t, roachpb.RESUME_ELASTIC_LIMIT, resp.ResumeReason)
+		require.NotNil(t, resp.ResumeSpan)
+		require.Equal(t, roachpb.Key("k01"), resp.ResumeSpan.Key)
+		require.Equal(t, roachpb.Key("l"), resp.ResumeSpan.EndKey)
+	})
+
+	t.Run("over limit without pagination", func(t *testing.T) {
+		// Requests without TargetBytes cannot return a resume span, so they
+		// must not yield.
+		resp := export(overLimitCtx(), 0)
+		require.Len(t, resp.Files, numKeys)
+		require.Nil(t, resp.ResumeSpan)
+	})
+}
+
 func TestRandomKeyAndTimestampExport(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	storage.DisableMetamorphicSimpleValueEncoding(t)
@@ -14,6 +14,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/readsummary/rspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
@@ -131,6 +132,23 @@ func TransferLease(
 	priorReadSum.Merge(rspb.FromTimestamp(newLease.Start.ToTimestamp()))
 
 	log.VEventf(ctx, 2, "lease transfer: prev lease: %+v, new lease: %+v", prevLease, newLease)
-	return evalNewLease(ctx, cArgs.EvalCtx, readWriter, cArgs.Stats,
+	res, err := evalNewLease(ctx, cArgs.EvalCtx, readWriter, cArgs.Stats,
 		newLease, prevLease, &priorReadSum, false /* isExtension */, true /* isTransfer */)
+	if err != nil {
+		return res, err
+	}
+
+	// Hand off the closed timestamp side-transport state of the outgoing
+	// leaseholder to the incoming leaseholder, so that the incoming leaseholder
+	// can start publishing closed timestamps for the range right away. Like the
+	// read summary above, this state is collected after the current lease was
+	// revoked, so it reflects the highest timestamp closed through the
+	// side-transport under this lease. We elide this step in mixed-version
+	// clusters as old nodes don't know about the handoff.
+	if cArgs.EvalCtx.ClusterSettings().Version.IsActive(ctx,
+		clusterversion.ClosedTimestampLeaseTransferHandoff) {
+		handoff := cArgs.EvalCtx.GetClosedTimestampHandoff(ctx)
+		res.Replicated.ClosedTimestampHandoff = &handoff
+	}
+	return res, nil
 }
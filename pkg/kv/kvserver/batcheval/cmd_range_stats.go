@@ -52,6 +52,16 @@ func RangeStats(
 		reply.MaxQueriesPerSecond = -1
 	}
 	reply.MaxQueriesPerSecondSet = true
+	if cpu, ok := cArgs.EvalCtx.GetMaxSplitCPU(); ok {
+		reply.MaxCPUPerSecond = cpu
+	} else {
+		// See comment on MaxCPUPerSecond. -1 means !ok.
+		reply.MaxCPUPerSecond = -1
+	}
+	reply.MaxCPUPerSecondSet = true
+	if ts, ok := cArgs.EvalCtx.GetLastWriteTimestamp(); ok {
+		reply.LastWriteTimestamp = ts
+	}
 	reply.RangeInfo = cArgs.EvalCtx.GetRangeInfo(ctx)
 	return result.Result{}, nil
 }
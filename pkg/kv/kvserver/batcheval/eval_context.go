@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/abortspan"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/readsummary/rspb"
@@ -93,6 +94,13 @@ type EvalContext interface {
 	// is disabled.
 	GetMaxSplitQPS() (float64, bool)
 
+	// GetMaxSplitCPU returns the Replicas maximum CPU nanos/s over a configured
+	// retention period.
+	//
+	// NOTE: This should not be used when the load based splitting cluster setting
+	// is disabled.
+	GetMaxSplitCPU() (float64, bool)
+
 	// GetLastSplitQPS returns the Replica's most recent queries/s request rate.
 	//
 	// NOTE: This should not be used when the load based splitting cluster setting
@@ -101,6 +109,11 @@ type EvalContext interface {
 	// TODO(nvanbenschoten): remove this method in v22.1.
 	GetLastSplitQPS() float64
 
+	// GetLastWriteTimestamp returns an upper bound on the MVCC timestamps of the
+	// values written to the range, and false if no such bound is known. See
+	// roachpb.RangeStatsResponse.LastWriteTimestamp.
+	GetLastWriteTimestamp() (hlc.Timestamp, bool)
+
 	GetGCThreshold() hlc.Timestamp
 	ExcludeDataFromBackup() bool
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
@@ -114,6 +127,13 @@ type EvalContext interface {
 	// requests on the range.
 	GetCurrentReadSummary(ctx context.Context) rspb.ReadSummary
 
+	// GetClosedTimestampHandoff returns the closed timestamp side-transport
+	// state of the range, to be handed off to the incoming leaseholder of a
+	// lease transfer. The method is meant to be called after the current lease
+	// was revoked (see RevokeLease), so that the side-transport can't close
+	// higher timestamps under the lease afterwards.
+	GetClosedTimestampHandoff(ctx context.Context) ctpb.LeaseTransferHandoff
+
 	// RevokeLease stops the replica from using its current lease, if that lease
 	// matches the provided lease sequence. All future calls to leaseStatus on
 	// this node with the current lease will now return a PROSCRIBED status.
@@ -168,6 +188,7 @@ type MockEvalCtx struct {
 	Clock              *hlc.Clock
 	Stats              enginepb.MVCCStats
 	QPS                float64
+	CPU                float64
 	AbortSpan          *abortspan.AbortSpan
 	GCThreshold        hlc.Timestamp
 	Term, FirstIndex   uint64
@@ -175,9 +196,13 @@ type MockEvalCtx struct {
 	Lease              roachpb.Lease
 	CurrentReadSummary rspb.ReadSummary
 	ClosedTimestamp    hlc.Timestamp
+	LastWriteTimestamp hlc.Timestamp
 	RevokedLeaseSeq    roachpb.LeaseSequence
 	MaxBytes           int64
 	ApproxDiskBytes    uint64
+
+	// ClosedTimestampHandoff is returned by GetClosedTimestampHandoff.
+	ClosedTimestampHandoff ctpb.LeaseTransferHandoff
 }
 
 // EvalContext returns the MockEvalCtx as an EvalContext. It will reflect future
@@ -246,6 +271,9 @@ func (m *mockEvalCtxImpl) GetMVCCStats() enginepb.MVCCStats {
 func (m *mockEvalCtxImpl) GetMaxSplitQPS() (float64, bool) {
 	return m.QPS, true
 }
+func (m *mockEvalCtxImpl) GetMaxSplitCPU() (float64, bool) {
+	return m.CPU, true
+}
 func (m *mockEvalCtxImpl) GetLastSplitQPS() float64 {
 	return m.QPS
 }
@@ -254,6 +282,9 @@ func (m *mockEvalCtxImpl) CanCreateTxnRecord(
 ) (bool, hlc.Timestamp, roachpb.TransactionAbortedReason) {
 	return m.CanCreateTxn()
 }
+func (m *mockEvalCtxImpl) GetLastWriteTimestamp() (hlc.Timestamp, bool) {
+	return m.LastWriteTimestamp, !m.LastWriteTimestamp.IsEmpty()
+}
 func (m *mockEvalCtxImpl) GetGCThreshold() hlc.Timestamp {
 	return m.GCThreshold
 }
@@ -272,6 +303,11 @@ func (m *mockEvalCtxImpl) GetRangeInfo(ctx context.Context) roachpb.RangeInfo {
 func (m *mockEvalCtxImpl) GetCurrentReadSummary(ctx context.Context) rspb.ReadSummary {
 	return m.CurrentReadSummary
 }
+func (m *mockEvalCtxImpl) GetClosedTimestampHandoff(
+	ctx context.Context,
+) ctpb.LeaseTransferHandoff {
+	return m.ClosedTimestampHandoff
+}
 func (m *mockEvalCtxImpl) GetClosedTimestampOlderThanStorageSnapshot() hlc.Timestamp {
 	return m.ClosedTimestamp
 }
@@ -338,6 +338,13 @@ func (p *Result) MergeAndDestroy(q Result) error {
 	}
 	q.Replicated.PriorReadSummary = nil
 
+	if p.Replicated.ClosedTimestampHandoff == nil {
+		p.Replicated.ClosedTimestampHandoff = q.Replicated.ClosedTimestampHandoff
+	} else if q.Replicated.ClosedTimestampHandoff != nil {
+		return errors.AssertionFailedf("conflicting closed timestamp handoff")
+	}
+	q.Replicated.ClosedTimestampHandoff = nil
+
 	if !p.Replicated.IsProbe {
 		p.Replicated.IsProbe = q.Replicated.IsProbe
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util/circuit"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -184,6 +185,38 @@ func TestReplicaCircuitBreaker_FollowerTripped(t *testing.T) {
 	})
 }
 
+// In this test, the breaker on the follower is tripped and the probe is
+// disabled, and the tripped breaker shows up in the
+// crdb_internal.node_tripped_circuit_breakers table of the follower's node
+// only.
+func TestReplicaCircuitBreaker_TrippedCircuitBreakersTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	tc := setupCircuitBreakerTest(t)
+	defer tc.Stopper().Stop(context.Background())
+
+	const query = `
+SELECT node_id, store_id, range_id, error LIKE '%injected error%'
+  FROM crdb_internal.node_tripped_circuit_breakers`
+	db1 := sqlutils.MakeSQLRunner(tc.ServerConn(n1))
+	db2 := sqlutils.MakeSQLRunner(tc.ServerConn(n2))
+	db1.CheckQueryResults(t, query, [][]string{})
+	db2.CheckQueryResults(t, query, [][]string{})
+
+	tc.SetProbeEnabled(n2, false)
+	tc.TripBreaker(n2)
+	rangeID := fmt.Sprint(tc.repls[n2].RangeID)
+	db1.CheckQueryResults(t, query, [][]string{})
+	db2.CheckQueryResults(t, query, [][]string{{"2", "2", rangeID, "true"}})
+
+	// Once the breaker untrips, the replica is no longer listed.
+	tc.SetProbeEnabled(n2, true)
+	testutils.SucceedsSoon(t, func() error {
+		return tc.repls[n2].Breaker().Signal().Err()
+	})
+	db2.CheckQueryResults(t, query, [][]string{})
+}
+
 // In this scenario, the breaker is tripped and the probe is disabled and
 // additionally, the liveness records for both nodes have expired. Soon after
 // the probe is re-enabled, the breaker heals. In particular, the probe isn't
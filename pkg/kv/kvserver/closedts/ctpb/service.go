@@ -50,7 +50,11 @@ func (m *Update) String() string {
 		} else {
 			agoMsg = fmt.Sprintf("%s in the future", -ago)
 		}
-		fmt.Fprintf(sb, "%s:%s (%s)", upd.Policy, upd.ClosedTimestamp, agoMsg)
+		if upd.LagTarget != 0 {
+			fmt.Fprintf(sb, "%s(%s):%s (%s)", upd.Policy, upd.LagTarget, upd.ClosedTimestamp, agoMsg)
+		} else {
+			fmt.Fprintf(sb, "%s:%s (%s)", upd.Policy, upd.ClosedTimestamp, agoMsg)
+		}
 	}
 	sb.WriteRune('\n')
 
@@ -11,12 +11,74 @@
 package closedts
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
+// PolicyClass identifies a group of ranges that close timestamps at the same
+// target. Ranges with the LAG_BY_CLUSTER_SETTING policy can override the
+// cluster-wide lag target through their span config, so every distinct lag
+// target forms its own class.
+type PolicyClass struct {
+	Policy roachpb.RangeClosedTimestampPolicy
+	// LagTarget, if non-zero, overrides the kv.closed_timestamp.target_duration
+	// cluster setting. It is always zero for the LEAD_FOR_GLOBAL_READS policy.
+	LagTarget time.Duration
+}
+
+// MakePolicyClass returns the class of a range with the given policy and lag
+// target override.
+func MakePolicyClass(
+	policy roachpb.RangeClosedTimestampPolicy, lagTarget time.Duration,
+) PolicyClass {
+	if policy != roachpb.LAG_BY_CLUSTER_SETTING {
+		lagTarget = 0
+	}
+	return PolicyClass{Policy: policy, LagTarget: lagTarget}
+}
+
+// Less orders classes by policy and then by lag target.
+func (c PolicyClass) Less(o PolicyClass) bool {
+	if c.Policy != o.Policy {
+		return c.Policy < o.Policy
+	}
+	return c.LagTarget < o.LagTarget
+}
+
+func (c PolicyClass) String() string {
+	if c.LagTarget == 0 {
+		return c.Policy.String()
+	}
+	return fmt.Sprintf("%s(%s)", c.Policy, c.LagTarget)
+}
+
+// TargetForPolicyClass returns the target closed timestamp for a range with
+// the given policy class. The class' lag target, if set, takes precedence over
+// lagTargetDuration.
+func TargetForPolicyClass(
+	now hlc.ClockTimestamp,
+	maxClockOffset time.Duration,
+	lagTargetDuration time.Duration,
+	leadTargetOverride time.Duration,
+	sideTransportCloseInterval time.Duration,
+	class PolicyClass,
+) hlc.Timestamp {
+	if class.LagTarget != 0 {
+		lagTargetDuration = class.LagTarget
+	}
+	return TargetForPolicy(
+		now,
+		maxClockOffset,
+		lagTargetDuration,
+		leadTargetOverride,
+		sideTransportCloseInterval,
+		class.Policy,
+	)
+}
+
 // TargetForPolicy returns the target closed timestamp for a range with the
 // given policy.
 func TargetForPolicy(
@@ -87,3 +87,54 @@ func TestTargetForPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestTargetForPolicyClass(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	now := hlc.Timestamp{WallTime: (10 * time.Second).Nanoseconds()}
+	const maxClockOffset = 500 * time.Millisecond
+	const lagTarget = 3 * time.Second
+	const sideTransportCloseInterval = 200 * time.Millisecond
+
+	target := func(class PolicyClass) hlc.Timestamp {
+		return TargetForPolicyClass(
+			now.UnsafeToClockTimestamp(),
+			maxClockOffset,
+			lagTarget,
+			0, /* leadTargetOverride */
+			sideTransportCloseInterval,
+			class,
+		)
+	}
+	targetForPolicy := func(policy roachpb.RangeClosedTimestampPolicy) hlc.Timestamp {
+		return TargetForPolicy(
+			now.UnsafeToClockTimestamp(),
+			maxClockOffset,
+			lagTarget,
+			0, /* leadTargetOverride */
+			sideTransportCloseInterval,
+			policy,
+		)
+	}
+
+	// Classes without a lag target use the cluster setting.
+	require.Equal(t, targetForPolicy(roachpb.LAG_BY_CLUSTER_SETTING),
+		target(PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING}))
+	require.Equal(t, targetForPolicy(roachpb.LEAD_FOR_GLOBAL_READS),
+		target(PolicyClass{Policy: roachpb.LEAD_FOR_GLOBAL_READS}))
+
+	// The lag target overrides the cluster setting.
+	lagClass := MakePolicyClass(roachpb.LAG_BY_CLUSTER_SETTING, time.Second)
+	require.Equal(t, time.Second, lagClass.LagTarget)
+	require.Equal(t, now.Add(-time.Second.Nanoseconds(), 0), target(lagClass))
+
+	// Ranges with global reads ignore the lag target.
+	leadClass := MakePolicyClass(roachpb.LEAD_FOR_GLOBAL_READS, time.Second)
+	require.Equal(t, PolicyClass{Policy: roachpb.LEAD_FOR_GLOBAL_READS}, leadClass)
+	require.Equal(t, targetForPolicy(roachpb.LEAD_FOR_GLOBAL_READS), target(leadClass))
+
+	require.True(t, PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING}.Less(lagClass))
+	require.True(t, lagClass.Less(leadClass))
+	require.Equal(t, "LAG_BY_CLUSTER_SETTING(1s)", lagClass.String())
+}
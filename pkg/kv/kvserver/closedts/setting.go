@@ -13,6 +13,7 @@ package closedts
 import (
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 )
 
@@ -46,3 +47,125 @@ var LeadForGlobalReadsOverride = settings.RegisterDurationSetting(
 	0,
 	settings.NonNegativeDuration,
 )
+
+// SideTransportCompressionMode enumerates the codecs that the side-transport
+// can use to compress its streams.
+type SideTransportCompressionMode int64
+
+// Values for SideTransportCompressionMode.
+const (
+	SideTransportCompressionNone SideTransportCompressionMode = iota
+	SideTransportCompressionSnappy
+	SideTransportCompressionZstd
+)
+
+// SideTransportCompression determines the codec that the side-transport uses
+// to compress the closed timestamp updates it sends to other nodes. The codec
+// is picked when a stream is established, so changes to the setting apply to
+// new streams.
+var SideTransportCompression = settings.RegisterEnumSetting(
+	settings.TenantWritable,
+	"kv.closed_timestamp.side_transport_compression",
+	"the codec used to compress closed timestamp side-transport streams; zstd "+
+		"trades CPU for smaller messages than snappy",
+	"snappy",
+	map[int64]string{
+		int64(SideTransportCompressionNone):   "none",
+		int64(SideTransportCompressionSnappy): "snappy",
+		int64(SideTransportCompressionZstd):   "zstd",
+	},
+)
+
+// SideTransportLaggingStreamThreshold determines how far the closed timestamps
+// received on an incoming side-transport stream can trail their target before
+// the stream is considered to be lagging.
+var SideTransportLaggingStreamThreshold = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"kv.closed_timestamp.side_transport_lagging_stream_threshold",
+	"an incoming closed-timestamp side-transport stream is considered to be lagging if its "+
+		"closed timestamps trail present time by more than kv.closed_timestamp.target_duration "+
+		"plus this duration; set to 0 to disable",
+	10*time.Second,
+	settings.NonNegativeDuration,
+)
+
+// SideTransportLaggingStreamPolicy enumerates the ways in which the receiver of
+// a lagging side-transport stream can treat the stream.
+type SideTransportLaggingStreamPolicy int64
+
+// Values for SideTransportLaggingStreamPolicy.
+const (
+	// SideTransportLaggingStreamFlag only reports lagging streams, through the
+	// logs, the metrics and the stream statuses.
+	SideTransportLaggingStreamFlag SideTransportLaggingStreamPolicy = iota
+	// SideTransportLaggingStreamExclude additionally stops using the closed
+	// timestamps of lagging streams, so that the ranges whose leaseholders are
+	// on the lagging node fall back to the closed timestamps carried by Raft
+	// commands.
+	SideTransportLaggingStreamExclude
+)
+
+// SideTransportLaggingStream determines how the receiver of a lagging
+// side-transport stream treats the stream.
+var SideTransportLaggingStream = settings.RegisterEnumSetting(
+	settings.TenantWritable,
+	"kv.closed_timestamp.side_transport_lagging_stream_policy",
+	"the treatment of lagging incoming closed-timestamp side-transport streams; "+
+		"exclude stops using the closed timestamps received on lagging streams",
+	"flag",
+	map[int64]string{
+		int64(SideTransportLaggingStreamFlag):    "flag",
+		int64(SideTransportLaggingStreamExclude): "exclude",
+	},
+)
+
+// LagAlertThresholdLagByClusterSetting is the closed timestamp lag above which
+// the ranges using the LAG_BY_CLUSTER_SETTING policy are considered to serve
+// stale follower reads, for the purposes of alerting.
+var LagAlertThresholdLagByClusterSetting = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.closed_timestamp.lag_alert_threshold.lag_by_cluster_setting",
+	"if nonzero, an alert is raised when the closed timestamps received from another node for "+
+		"ranges with the default closed timestamp policy trail present time by more than this "+
+		"duration for longer than kv.closed_timestamp.lag_alert_duration",
+	0,
+	settings.NonNegativeDuration,
+)
+
+// LagAlertThresholdLeadForGlobalReads is the closed timestamp lag above which
+// the ranges using the LEAD_FOR_GLOBAL_READS policy are considered to serve
+// stale follower reads, for the purposes of alerting. The closed timestamps of
+// these ranges normally lead present time, so their lag is negative.
+var LagAlertThresholdLeadForGlobalReads = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.closed_timestamp.lag_alert_threshold.lead_for_global_reads",
+	"if nonzero, an alert is raised when the closed timestamps received from another node for "+
+		"global_read ranges trail present time by more than this duration for longer than "+
+		"kv.closed_timestamp.lag_alert_duration",
+	0,
+	settings.NonNegativeDuration,
+)
+
+// LagAlertDuration is how long the closed timestamp lag of a policy needs to
+// exceed the policy's alert threshold before an alert is raised.
+var LagAlertDuration = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.closed_timestamp.lag_alert_duration",
+	"the duration for which the closed timestamp lag of a policy needs to exceed the policy's "+
+		"kv.closed_timestamp.lag_alert_threshold before an alert is raised",
+	30*time.Second,
+	settings.NonNegativeDuration,
+)
+
+// LagAlertThreshold returns the alert threshold configured for the given
+// policy, or 0 if alerting is disabled for the policy.
+func LagAlertThreshold(sv *settings.Values, policy roachpb.RangeClosedTimestampPolicy) time.Duration {
+	switch policy {
+	case roachpb.LAG_BY_CLUSTER_SETTING:
+		return LagAlertThresholdLagByClusterSetting.Get(sv)
+	case roachpb.LEAD_FOR_GLOBAL_READS:
+		return LagAlertThresholdLeadForGlobalReads.Get(sv)
+	default:
+		panic("unexpected RangeClosedTimestampPolicy")
+	}
+}
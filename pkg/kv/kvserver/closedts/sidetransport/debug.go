@@ -138,7 +138,7 @@ func (s *Sender) HTML() string {
 		state := s.connsMu.conns[nid].getState()
 		fmt.Fprintf(sb, "n%d: ", nid)
 		if state.connected {
-			fmt.Fprintf(sb, "connected at: %s (%s ago)\n", state.connectedTime.Truncate(time.Millisecond), now.Sub(state.connectedTime).Truncate(time.Second))
+			fmt.Fprintf(sb, "connected at: %s (%s ago), compression: %s\n", state.connectedTime.Truncate(time.Millisecond), now.Sub(state.connectedTime).Truncate(time.Second), state.compressor)
 		} else {
 			fmt.Fprintf(sb, "disconnected at: %s (%s ago, err: %s)\n", state.lastDisconnectTime.Truncate(time.Millisecond), now.Sub(state.lastDisconnectTime).Truncate(time.Second), state.lastDisconnect)
 		}
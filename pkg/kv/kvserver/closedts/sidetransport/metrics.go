@@ -0,0 +1,180 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sidetransport
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// SenderMetrics encapsulates the metrics exported by the Sender. The metrics
+// are broken down by CantCloseReason, so that operators can tell why ranges
+// are failing to close timestamps.
+type SenderMetrics struct {
+	// FailuresToClose counts, for each CantCloseReason, the number of times that
+	// a range failed to close a timestamp for that reason.
+	FailuresToClose [MaxReason]*metric.Counter
+	// RangesNotClosing tracks, for each CantCloseReason, the number of ranges
+	// that failed to close a timestamp for that reason in the last publishing
+	// cycle.
+	RangesNotClosing [MaxReason]*metric.Gauge
+}
+
+var _ metric.Struct = (*SenderMetrics)(nil)
+
+// MetricStruct makes SenderMetrics a metric.Struct.
+func (m *SenderMetrics) MetricStruct() {}
+
+// cantCloseReasonMetricNames contains the names used for each CantCloseReason
+// in the names of the metrics.
+var cantCloseReasonMetricNames = [MaxReason]string{
+	ReasonUnknown:                 "unknown",
+	ReplicaDestroyed:              "replica_destroyed",
+	InvalidLease:                  "invalid_lease",
+	TargetOverLeaseExpiration:     "target_over_lease_expiration",
+	MergeInProgress:               "merge_in_progress",
+	ProposalsInFlight:             "proposals_in_flight",
+	RequestsEvaluatingBelowTarget: "requests_evaluating_below_target",
+}
+
+func makeSenderMetrics() *SenderMetrics {
+	m := &SenderMetrics{}
+	for r := CantCloseReason(0); r < MaxReason; r++ {
+		name := cantCloseReasonMetricNames[r]
+		m.FailuresToClose[r] = metric.NewCounter(metric.Metadata{
+			Name: fmt.Sprintf("kv.closed_timestamp.side_transport.failures_to_close.%s", name),
+			Help: fmt.Sprintf(
+				"Number of times the side-transport failed to close a timestamp on a range "+
+					"with a local lease because of reason %s", r),
+			Measurement: "Attempts",
+			Unit:        metric.Unit_COUNT,
+		})
+		m.RangesNotClosing[r] = metric.NewGauge(metric.Metadata{
+			Name: fmt.Sprintf("kv.closed_timestamp.side_transport.ranges_not_closing.%s", name),
+			Help: fmt.Sprintf(
+				"Number of ranges with local leases which failed to close a timestamp in the "+
+					"last side-transport publishing cycle because of reason %s", r),
+			Measurement: "Ranges",
+			Unit:        metric.Unit_COUNT,
+		})
+	}
+	return m
+}
+
+// update records the failures to close timestamps from a publishing cycle.
+func (m *SenderMetrics) update(closingFailures *[MaxReason]int) {
+	for r, n := range closingFailures {
+		m.FailuresToClose[r].Inc(int64(n))
+		m.RangesNotClosing[r].Update(int64(n))
+	}
+}
+
+// ReceiverMetrics encapsulates the metrics exported by the Receiver, which
+// describe the health of the incoming side-transport streams.
+type ReceiverMetrics struct {
+	// MaxStreamLag is the largest lag among the incoming streams.
+	MaxStreamLag *metric.Gauge
+	// LaggingStreams is the number of incoming streams that are lagging.
+	LaggingStreams *metric.Gauge
+	// MaxClosedTimestampLag is, for each policy, the largest closed timestamp
+	// lag among the incoming streams.
+	MaxClosedTimestampLag [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]*metric.Gauge
+	// LagAlerting is, for each policy, set to 1 while an alert about the
+	// policy's closed timestamp lag is raised, and to 0 otherwise.
+	LagAlerting [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]*metric.Gauge
+	// LagAlerts counts, for each policy, the alerts raised about the policy's
+	// closed timestamp lag.
+	LagAlerts [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]*metric.Counter
+}
+
+var _ metric.Struct = (*ReceiverMetrics)(nil)
+
+// MetricStruct makes ReceiverMetrics a metric.Struct.
+func (m *ReceiverMetrics) MetricStruct() {}
+
+// policyMetricNames contains the names used for each closed timestamp policy in
+// the names of the metrics.
+var policyMetricNames = [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]string{
+	roachpb.LAG_BY_CLUSTER_SETTING: "lag_by_cluster_setting",
+	roachpb.LEAD_FOR_GLOBAL_READS:  "lead_for_global_reads",
+}
+
+func makeReceiverMetrics(r *Receiver) *ReceiverMetrics {
+	m := &ReceiverMetrics{
+		MaxStreamLag: metric.NewFunctionalGauge(metric.Metadata{
+			Name: "kv.closed_timestamp.side_transport.max_stream_lag",
+			Help: "Largest duration by which the closed timestamps received on an " +
+				"incoming side-transport stream trail present time, across all peers",
+			Measurement: "Nanoseconds",
+			Unit:        metric.Unit_NANOSECONDS,
+		}, func() int64 {
+			var maxLag time.Duration
+			for _, st := range r.StreamStatuses() {
+				if st.Lag > maxLag {
+					maxLag = st.Lag
+				}
+			}
+			return maxLag.Nanoseconds()
+		}),
+		LaggingStreams: metric.NewFunctionalGauge(metric.Metadata{
+			Name: "kv.closed_timestamp.side_transport.lagging_streams",
+			Help: "Number of incoming side-transport streams whose lag exceeds " +
+				"kv.closed_timestamp.side_transport_lagging_stream_threshold",
+			Measurement: "Streams",
+			Unit:        metric.Unit_COUNT,
+		}, func() int64 {
+			var n int64
+			for _, st := range r.StreamStatuses() {
+				if st.Lagging {
+					n++
+				}
+			}
+			return n
+		}),
+	}
+	for pol := range policyMetricNames {
+		pol := pol
+		name := policyMetricNames[pol]
+		m.MaxClosedTimestampLag[pol] = metric.NewFunctionalGauge(metric.Metadata{
+			Name: fmt.Sprintf("kv.closed_timestamp.side_transport.max_closed_timestamp_lag.%s", name),
+			Help: fmt.Sprintf(
+				"Largest lag of the closed timestamps received on incoming side-transport streams "+
+					"for ranges with the %s policy, across all peers", roachpb.RangeClosedTimestampPolicy(pol)),
+			Measurement: "Nanoseconds",
+			Unit:        metric.Unit_NANOSECONDS,
+		}, func() int64 {
+			lag, _, _ := maxClosedTimestampLag(r.StreamStatuses(), pol)
+			return lag.Nanoseconds()
+		})
+		m.LagAlerting[pol] = metric.NewGauge(metric.Metadata{
+			Name: fmt.Sprintf("kv.closed_timestamp.side_transport.lag_alerting.%s", name),
+			Help: fmt.Sprintf(
+				"Set to 1 while the closed timestamp lag of ranges with the %s policy exceeds "+
+					"the policy's kv.closed_timestamp.lag_alert_threshold for longer than "+
+					"kv.closed_timestamp.lag_alert_duration, 0 otherwise", roachpb.RangeClosedTimestampPolicy(pol)),
+			Measurement: "Alerts",
+			Unit:        metric.Unit_COUNT,
+		})
+		m.LagAlerts[pol] = metric.NewCounter(metric.Metadata{
+			Name: fmt.Sprintf("kv.closed_timestamp.side_transport.lag_alerts.%s", name),
+			Help: fmt.Sprintf(
+				"Number of alerts raised because the closed timestamp lag of ranges with the %s "+
+					"policy exceeded the policy's kv.closed_timestamp.lag_alert_threshold",
+				roachpb.RangeClosedTimestampPolicy(pol)),
+			Measurement: "Alerts",
+			Unit:        metric.Unit_COUNT,
+		})
+	}
+	return m
+}
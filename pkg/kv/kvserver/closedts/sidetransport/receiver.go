@@ -13,17 +13,29 @@ package sidetransport
 import (
 	"context"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	grpcstatus "google.golang.org/grpc/status"
 )
 
 // Receiver is the gRPC server for the closed timestamp side-transport,
@@ -31,11 +43,22 @@ import (
 // streaming connections.
 //
 // The state of the Receiver is exposed on each node at /debug/closedts-receiver.
+// The state of the individual incoming streams is also exposed in JSON format
+// at /debug/closedts-receiver/streams.
 type Receiver struct {
 	log.AmbientContext
+	nodeID       *base.NodeIDContainer
 	stop         *stop.Stopper
+	st           *cluster.Settings
 	stores       Stores
 	testingKnobs receiverTestingKnobs
+	metrics      *ReceiverMetrics
+	// laggingLogEvery rate-limits the logging of lagging streams being
+	// excluded.
+	laggingLogEvery log.EveryN
+	// insecureLogEvery rate-limits the logging of streams that can't be
+	// authenticated because the cluster runs in insecure mode.
+	insecureLogEvery log.EveryN
 
 	mu struct {
 		syncutil.RWMutex
@@ -47,6 +70,30 @@ type Receiver struct {
 		syncutil.Mutex
 		lastClosed map[roachpb.NodeID]streamCloseInfo
 	}
+
+	// lagAlerts tracks, for each policy, whether the closed timestamp lag
+	// exceeds the policy's alert threshold. It is only accessed by the lag
+	// monitor task.
+	lagAlerts [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]lagAlert
+	// logEvent records the events raised by the lag monitor. It is set by
+	// Start.
+	logEvent EventLogger
+}
+
+// EventLogger records a structured event, both to the logs and to the
+// system.eventlog table.
+type EventLogger func(context.Context, logpb.EventPayload)
+
+// lagAlert tracks the closed timestamp lag of a policy against the policy's
+// alert threshold.
+type lagAlert struct {
+	// exceededSince is the time when the lag started exceeding the threshold, or
+	// zero if the lag is below the threshold.
+	exceededSince time.Time
+	// raised is set once the lag has exceeded the threshold for long enough for
+	// an alert to be raised. It is reset when the lag recovers, so that a single
+	// alert is raised per incident.
+	raised bool
 }
 
 type streamCloseInfo struct {
@@ -66,29 +113,163 @@ var _ ctpb.SideTransportServer = &Receiver{}
 func NewReceiver(
 	nodeID *base.NodeIDContainer,
 	stop *stop.Stopper,
+	st *cluster.Settings,
 	stores Stores,
 	testingKnobs receiverTestingKnobs,
 ) *Receiver {
 	r := &Receiver{
-		stop:         stop,
-		stores:       stores,
-		testingKnobs: testingKnobs,
+		nodeID:           nodeID,
+		stop:             stop,
+		st:               st,
+		stores:           stores,
+		testingKnobs:     testingKnobs,
+		laggingLogEvery:  log.Every(10 * time.Second),
+		insecureLogEvery: log.Every(time.Minute),
 	}
+	r.metrics = makeReceiverMetrics(r)
 	r.AmbientContext.AddLogTag("n", nodeID)
 	r.mu.conns = make(map[roachpb.NodeID]*incomingStream)
 	r.historyMu.lastClosed = make(map[roachpb.NodeID]streamCloseInfo)
 	return r
 }
 
+// Metrics returns the Receiver's metrics.
+func (s *Receiver) Metrics() *ReceiverMetrics {
+	return s.metrics
+}
+
+// lagMonitorInterval is the interval at which the Receiver checks the closed
+// timestamp lag of every policy against the policy's alert threshold.
+const lagMonitorInterval = time.Second
+
+// Start starts the task which monitors the closed timestamp lag of the incoming
+// streams, raising alerts when the lag of a policy exceeds the policy's
+// kv.closed_timestamp.lag_alert_threshold for longer than
+// kv.closed_timestamp.lag_alert_duration. Alerts are recorded through logEvent.
+func (s *Receiver) Start(ctx context.Context, logEvent EventLogger) {
+	ctx = s.AnnotateCtx(ctx)
+	s.logEvent = logEvent
+	_ /* err */ = s.stop.RunAsyncTask(ctx, "closedts side-transport lag monitor",
+		func(ctx context.Context) {
+			ticker := time.NewTicker(lagMonitorInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					s.checkClosedTimestampLag(ctx, timeutil.Now())
+				case <-s.stop.ShouldQuiesce():
+					return
+				}
+			}
+		})
+}
+
+// checkClosedTimestampLag compares the closed timestamp lag of every policy
+// against the policy's alert threshold, and raises an alert for the policies
+// whose lag has exceeded the threshold for longer than the alert duration.
+func (s *Receiver) checkClosedTimestampLag(ctx context.Context, now time.Time) {
+	statuses := s.StreamStatuses()
+	alertDuration := closedts.LagAlertDuration.Get(&s.st.SV)
+	for pol := range s.lagAlerts {
+		policy := roachpb.RangeClosedTimestampPolicy(pol)
+		threshold := closedts.LagAlertThreshold(&s.st.SV, policy)
+		lag, nodeID, ok := maxClosedTimestampLag(statuses, pol)
+		alert := &s.lagAlerts[pol]
+		if threshold == 0 || !ok || lag <= threshold {
+			*alert = lagAlert{}
+			s.metrics.LagAlerting[pol].Update(0)
+			continue
+		}
+		if alert.exceededSince.IsZero() {
+			alert.exceededSince = now
+		}
+		exceededFor := now.Sub(alert.exceededSince)
+		if alert.raised || exceededFor < alertDuration {
+			continue
+		}
+		alert.raised = true
+		s.metrics.LagAlerting[pol].Update(1)
+		s.metrics.LagAlerts[pol].Inc(1)
+		ev := &eventpb.ClosedTimestampLagExceeded{
+			Policy:            policy.String(),
+			LeaseholderNodeID: int32(nodeID),
+			Lag:               lag.Nanoseconds(),
+			Threshold:         threshold.Nanoseconds(),
+			ExceededFor:       exceededFor.Nanoseconds(),
+		}
+		ev.CommonDetails().Timestamp = now.UnixNano()
+		s.logEvent(ctx, ev)
+	}
+}
+
+// maxClosedTimestampLag returns the largest closed timestamp lag for the given
+// policy among the streams, along with the node publishing it. ok is false if
+// no stream has closed a timestamp for the policy.
+func maxClosedTimestampLag(
+	statuses []StreamStatus, pol int,
+) (lag time.Duration, nodeID roachpb.NodeID, ok bool) {
+	for _, st := range statuses {
+		if st.ClosedTimestamps[pol].IsEmpty() {
+			continue
+		}
+		if !ok || st.ClosedTimestampLags[pol] > lag {
+			lag, nodeID, ok = st.ClosedTimestampLags[pol], st.NodeID, true
+		}
+	}
+	return lag, nodeID, ok
+}
+
 // PushUpdates is the streaming RPC handler.
 func (s *Receiver) PushUpdates(stream ctpb.SideTransport_PushUpdatesServer) error {
 	// Create a steam to service this connection. The stream will call back into
 	// the Receiver through onFirstMsg to register itself once it finds out the
 	// sender's node id.
 	ctx := s.AnnotateCtx(stream.Context())
+	if err := s.authenticatePeer(ctx); err != nil {
+		return err
+	}
 	return newIncomingStream(s, s.stores).Run(ctx, s.stop, stream)
 }
 
+// authenticatePeer checks that the remote end of an incoming stream is another
+// node of the cluster. Closed timestamps received on the stream are trusted to
+// serve follower reads, so they must not be accepted from arbitrary clients,
+// even if these clients are otherwise allowed to perform RPCs (e.g. with a root
+// certificate).
+//
+// In insecure mode, there's no peer identity to check; the stream is accepted
+// and a warning is logged.
+func (s *Receiver) authenticatePeer(ctx context.Context) error {
+	if grpcutil.IsLocalRequestContext(ctx) {
+		// This is an in-process request.
+		return nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return grpcstatus.Error(codes.Unauthenticated, "closed timestamp side-transport: missing peer info")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		if s.insecureLogEvery.ShouldLog() {
+			log.Warningf(ctx, "accepting unauthenticated closed timestamps side-transport "+
+				"connection from %s; closed timestamps can't be authenticated in insecure mode", p.Addr)
+		}
+		return nil
+	}
+	scopes, err := security.GetCertificateUserScope(&tlsInfo.State)
+	if err != nil {
+		return grpcstatus.Error(codes.Unauthenticated, err.Error())
+	}
+	for _, scope := range scopes {
+		if scope.Username == username.NodeUser {
+			return nil
+		}
+	}
+	return grpcstatus.Errorf(codes.PermissionDenied,
+		"closed timestamp side-transport: client certificate %s is not a node certificate",
+		tlsInfo.State.PeerCertificates[0].Subject)
+}
+
 // GetClosedTimestamp returns the latest closed timestamp that the receiver
 // knows for a particular range, together with the LAI needed to have applied in
 // order to use this closed timestamp.
@@ -96,6 +277,9 @@ func (s *Receiver) PushUpdates(stream ctpb.SideTransport_PushUpdatesServer) erro
 // leaseholderNode is the last known leaseholder for the range. For efficiency
 // reasons, only the closed timestamp info received from that node is checked
 // for closed timestamp info about this range.
+//
+// If the stream from leaseholderNode is lagging and the lagging stream policy
+// is to exclude such streams, an empty timestamp is returned.
 func (s *Receiver) GetClosedTimestamp(
 	ctx context.Context, rangeID roachpb.RangeID, leaseholderNode roachpb.NodeID,
 ) (hlc.Timestamp, ctpb.LAI) {
@@ -105,9 +289,85 @@ func (s *Receiver) GetClosedTimestamp(
 	if !ok {
 		return hlc.Timestamp{}, 0
 	}
+	if closedts.SideTransportLaggingStream.Get(&s.st.SV) ==
+		int64(closedts.SideTransportLaggingStreamExclude) {
+		if lag := conn.lag(timeutil.Now()); s.isLagging(lag) {
+			if s.laggingLogEvery.ShouldLog() {
+				log.Warningf(ctx, "ignoring closed timestamps from n%d; side-transport stream lagging by %s",
+					leaseholderNode, lag)
+			}
+			return hlc.Timestamp{}, 0
+		}
+	}
 	return conn.GetClosedTimestamp(ctx, rangeID)
 }
 
+// isLagging returns true if a stream with the given lag is considered to be
+// lagging. The closed timestamps of the LAG_BY_CLUSTER_SETTING policy are
+// expected to trail present time by the target duration, so only the lag beyond
+// that is compared against the threshold.
+func (s *Receiver) isLagging(lag time.Duration) bool {
+	threshold := closedts.SideTransportLaggingStreamThreshold.Get(&s.st.SV)
+	return threshold > 0 && lag > closedts.TargetDuration.Get(&s.st.SV)+threshold
+}
+
+// StreamStatus describes the state of an incoming side-transport stream.
+type StreamStatus struct {
+	// NodeID is the node publishing closed timestamps on the stream.
+	NodeID roachpb.NodeID
+	// ConnectedAt is the time when the stream was established.
+	ConnectedAt time.Time
+	// Age is the time elapsed since the stream was established.
+	Age time.Duration
+	// LastReceived is the time when the last message was received on the
+	// stream. It is zero if no message has been processed yet.
+	LastReceived time.Time
+	// LastSeqNum is the sequence number of the last message received on the
+	// stream.
+	LastSeqNum ctpb.SeqNum
+	// ClosedTimestamps contains, for each policy, the closed timestamp last
+	// communicated on the stream for the ranges using the default lag target.
+	// Ranges with lag targets configured through span configs are not covered.
+	ClosedTimestamps [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
+	// ClosedTimestampLags contains, for each policy, how far the closed
+	// timestamp trails the current time. Lags are negative for policies that
+	// close timestamps in the future, and zero if no timestamp has been closed.
+	ClosedTimestampLags [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]time.Duration
+	// NumTrackedRanges is the number of ranges the stream carries closed
+	// timestamps for.
+	NumTrackedRanges int
+	// Lag is how far the closed timestamp of the LAG_BY_CLUSTER_SETTING policy
+	// trails the current time, or the time elapsed since the stream was
+	// established if no timestamp has been closed yet.
+	Lag time.Duration
+	// Lagging is set if Lag exceeds the
+	// kv.closed_timestamp.side_transport_lagging_stream_threshold setting.
+	Lagging bool
+}
+
+// StreamStatuses returns the state of all the currently-open incoming streams,
+// ordered by node ID. This lets operators tell whether the node is receiving
+// stale closed timestamp updates from any particular peer.
+func (s *Receiver) StreamStatuses() []StreamStatus {
+	s.mu.RLock()
+	conns := make([]*incomingStream, 0, len(s.mu.conns))
+	for _, c := range s.mu.conns {
+		conns = append(conns, c)
+	}
+	s.mu.RUnlock()
+
+	now := timeutil.Now()
+	res := make([]StreamStatus, len(conns))
+	for i, c := range conns {
+		res[i] = c.status(now)
+		res[i].Lagging = s.isLagging(res[i].Lag)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].NodeID < res[j].NodeID
+	})
+	return res
+}
+
 // onFirstMsg is called when the first message on a stream is received. This is
 // the point where the stream finds out what node it's receiving data from.
 func (s *Receiver) onFirstMsg(ctx context.Context, r *incomingStream, nodeID roachpb.NodeID) error {
@@ -115,6 +375,14 @@ func (s *Receiver) onFirstMsg(ctx context.Context, r *incomingStream, nodeID roa
 	defer s.mu.Unlock()
 
 	log.VEventf(ctx, 2, "n%d opened a closed timestamps side-transport connection", nodeID)
+	if nodeID == 0 {
+		return errors.Errorf("closed timestamps side-transport connection without a node ID")
+	}
+	// A node never opens a connection to its own receiver, so a stream claiming
+	// to come from the local node is spoofed.
+	if s.nodeID != nil && nodeID == s.nodeID.Get() {
+		return errors.Errorf("connection claiming to be from the local node n%d", nodeID)
+	}
 	// If we already have a connection from nodeID, we don't accept this one. The
 	// other one has to be zombie going away soon. The client is expected to retry
 	// to establish the new connection.
@@ -176,6 +444,9 @@ type incomingStream struct {
 		syncutil.RWMutex
 		streamState
 		lastReceived time.Time
+		// awaitingSnapshot is set when a gap in the sequence of updates was
+		// detected. Incremental updates are ignored until a snapshot is received.
+		awaitingSnapshot bool
 	}
 }
 
@@ -199,6 +470,7 @@ func newIncomingStream(s *Receiver, stores Stores) *incomingStream {
 		stores:      stores,
 		connectedAt: timeutil.Now(),
 	}
+	r.mu.lastClosed = make(map[closedts.PolicyClass]hlc.Timestamp)
 	return r
 }
 
@@ -215,12 +487,68 @@ func (r *incomingStream) GetClosedTimestamp(
 	if !ok {
 		return hlc.Timestamp{}, 0
 	}
-	return r.mu.lastClosed[info.policy], info.lai
+	return r.mu.lastClosed[info.class], info.lai
+}
+
+// status returns the state of the stream, with durations computed relative to
+// now.
+func (r *incomingStream) status(now time.Time) StreamStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	st := StreamStatus{
+		NodeID:           r.nodeID,
+		ConnectedAt:      r.connectedAt,
+		Age:              now.Sub(r.connectedAt),
+		LastReceived:     r.mu.lastReceived,
+		LastSeqNum:       r.mu.lastSeqNum,
+		NumTrackedRanges: len(r.mu.tracked),
+		Lag:              r.lagLocked(now),
+	}
+	for pol := range st.ClosedTimestamps {
+		ts := r.mu.lastClosed[closedts.PolicyClass{Policy: roachpb.RangeClosedTimestampPolicy(pol)}]
+		st.ClosedTimestamps[pol] = ts
+		if !ts.IsEmpty() {
+			st.ClosedTimestampLags[pol] = now.Sub(ts.GoTime())
+		}
+	}
+	return st
+}
+
+// lag returns how far the closed timestamp communicated on the stream for the
+// LAG_BY_CLUSTER_SETTING policy trails now, or the time elapsed since the stream
+// was established if no timestamp has been closed yet.
+//
+// The lag is measured against the closed timestamp rather than against the
+// time when the last message was received: a stream can keep delivering
+// messages whose closed timestamps don't advance, for example while the
+// receiver waits for a snapshot after detecting a gap, or while the sender
+// fails to close timestamps.
+func (r *incomingStream) lag(now time.Time) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lagLocked(now)
+}
+
+func (r *incomingStream) lagLocked(now time.Time) time.Duration {
+	ts := r.mu.lastClosed[closedts.PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING}]
+	if ts.IsEmpty() {
+		return now.Sub(r.connectedAt)
+	}
+	return now.Sub(ts.GoTime())
 }
 
 // processUpdate processes one update received on the stream, updating the local
 // state.
-func (r *incomingStream) processUpdate(ctx context.Context, msg *ctpb.Update) {
+//
+// If the update is an incremental that doesn't follow the last applied update
+// (i.e. some messages were lost), the update is ignored and requestSnapshot is
+// returned as true the first time the gap is detected; the caller is expected
+// to ask the sender for a snapshot. Until the snapshot arrives, the state as of
+// the last applied update is retained: the closed timestamps it contains remain
+// valid, they simply stop advancing.
+func (r *incomingStream) processUpdate(
+	ctx context.Context, msg *ctpb.Update,
+) (requestSnapshot bool) {
 	log.VEventf(ctx, 4, "received side-transport update: %v", msg)
 
 	if msg.NodeID == 0 {
@@ -231,6 +559,23 @@ func (r *incomingStream) processUpdate(ctx context.Context, msg *ctpb.Update) {
 		log.Fatalf(ctx, "wrong NodeID; expected %d, got %d", r.nodeID, msg.NodeID)
 	}
 
+	if !msg.Snapshot {
+		r.mu.Lock()
+		if r.mu.awaitingSnapshot {
+			r.mu.Unlock()
+			return false
+		}
+		if msg.SeqNum != r.mu.lastSeqNum+1 {
+			r.mu.awaitingSnapshot = true
+			expected := r.mu.lastSeqNum + 1
+			r.mu.Unlock()
+			log.Warningf(ctx, "expected closed timestamp side-transport message with sequence number "+
+				"%d from n%d, got %d; requesting a snapshot", expected, r.nodeID, msg.SeqNum)
+			return true
+		}
+		r.mu.Unlock()
+	}
+
 	// Handle the removed ranges. In order to not lose closed ts info, before we
 	// can remove a range from our tracking, we copy the info about its closed
 	// timestamp to the local replica(s). Note that it's important to do this
@@ -250,7 +595,7 @@ func (r *incomingStream) processUpdate(ctx context.Context, msg *ctpb.Update) {
 				log.Fatalf(ctx, "attempting to unregister a missing range: r%d", rangeID)
 			}
 			r.stores.ForwardSideTransportClosedTimestampForRange(
-				ctx, rangeID, r.mu.lastClosed[info.policy], info.lai)
+				ctx, rangeID, r.mu.lastClosed[info.class], info.lai)
 		}
 		r.mu.RUnlock()
 	}
@@ -261,27 +606,24 @@ func (r *incomingStream) processUpdate(ctx context.Context, msg *ctpb.Update) {
 
 	// Reset all the state on snapshots.
 	if msg.Snapshot {
-		for i := range r.mu.lastClosed {
-			r.mu.lastClosed[i] = hlc.Timestamp{}
-		}
+		r.mu.lastClosed = make(map[closedts.PolicyClass]hlc.Timestamp, len(r.mu.lastClosed))
 		r.mu.tracked = make(map[roachpb.RangeID]trackedRange, len(r.mu.tracked))
-	} else if msg.SeqNum != r.mu.lastSeqNum+1 {
-		log.Fatalf(ctx, "expected closed timestamp side-transport message with sequence number "+
-			"%d, got %d", r.mu.lastSeqNum+1, msg.SeqNum)
+		r.mu.awaitingSnapshot = false
 	}
 	r.mu.lastSeqNum = msg.SeqNum
 
 	for _, rng := range msg.AddedOrUpdated {
 		r.mu.tracked[rng.RangeID] = trackedRange{
-			lai:    rng.LAI,
-			policy: rng.Policy,
+			lai:   rng.LAI,
+			class: closedts.PolicyClass{Policy: rng.Policy, LagTarget: rng.LagTarget},
 		}
 	}
 	for _, rangeID := range msg.Removed {
 		delete(r.mu.tracked, rangeID)
 	}
-	for _, update := range msg.ClosedTimestamps {
-		r.mu.lastClosed[update.Policy] = update.ClosedTimestamp
+	for i := range msg.ClosedTimestamps {
+		update := &msg.ClosedTimestamps[i]
+		r.mu.lastClosed[groupUpdateClass(update)] = update.ClosedTimestamp
 	}
 }
 
@@ -312,20 +654,39 @@ func (r *incomingStream) Run(
 			}
 
 			if r.nodeID == 0 {
-				r.nodeID = msg.NodeID
-
-				if err := r.server.onFirstMsg(ctx, r, r.nodeID); err != nil {
+				if err := r.server.onFirstMsg(ctx, r, msg.NodeID); err != nil {
 					log.Warningf(ctx, "%s", err.Error())
 					return
-				} else if ch := r.testingKnobs.onFirstMsg; ch != nil {
+				}
+				r.nodeID = msg.NodeID
+				if ch := r.testingKnobs.onFirstMsg; ch != nil {
 					ch <- struct{}{}
 				}
 				if !msg.Snapshot {
-					log.Fatal(ctx, "expected the first message to be a snapshot")
+					err := errors.Errorf("expected the first message from n%d to be a snapshot", r.nodeID)
+					log.Warningf(ctx, "%s", err.Error())
+					r.server.onRecvErr(ctx, r.nodeID, err)
+					return
 				}
+			} else if msg.NodeID != r.nodeID {
+				// All the updates on a stream come from the node that opened it. An
+				// update claiming to come from another node can't be trusted, and
+				// neither can the rest of the stream.
+				err := errors.Errorf("wrong NodeID on stream from n%d; got %d", r.nodeID, msg.NodeID)
+				log.Warningf(ctx, "%s", err.Error())
+				r.server.onRecvErr(ctx, r.nodeID, err)
+				return
 			}
 
-			r.processUpdate(ctx, msg)
+			if r.processUpdate(ctx, msg) {
+				// Ask the sender for a snapshot on this same stream. If the request
+				// can't be sent, the stream is broken and the next Recv() will
+				// return an error.
+				if err := stream.Send(&ctpb.Response{RequestSnapshot: true}); err != nil {
+					log.Warningf(ctx, "failed to request closed timestamp snapshot from n%d: %s",
+						r.nodeID, err)
+				}
+			}
 			if ch := r.testingKnobs.onMsg; ch != nil {
 				select {
 				case ch <- msg:
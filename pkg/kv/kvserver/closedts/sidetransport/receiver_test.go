@@ -12,16 +12,28 @@ package sidetransport
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 )
 
 type mockStores struct {
@@ -81,7 +93,7 @@ func TestIncomingStreamProcessUpdateBasic(t *testing.T) {
 	nid := &base.NodeIDContainer{}
 	nid.Set(ctx, 1)
 	stores := &mockStores{}
-	server := NewReceiver(nid, stopper, stores, receiverTestingKnobs{})
+	server := NewReceiver(nid, stopper, cluster.MakeTestingClusterSettings(), stores, receiverTestingKnobs{})
 	r := newIncomingStream(server, stores)
 	r.nodeID = 1
 
@@ -130,6 +142,13 @@ func TestIncomingStreamProcessUpdateBasic(t *testing.T) {
 	ts, lai = r.GetClosedTimestamp(ctx, 1)
 	require.Empty(t, ts)
 	require.Equal(t, laiZero, lai)
+	now := timeutil.Unix(0, 15)
+	st := r.status(now)
+	require.Equal(t, roachpb.NodeID(1), st.NodeID)
+	require.Equal(t, ctpb.SeqNum(2), st.LastSeqNum)
+	require.Equal(t, 2, st.NumTrackedRanges)
+	require.Equal(t, [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp{ts11, ts21}, st.ClosedTimestamps)
+	require.Equal(t, [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]time.Duration{4, -6}, st.ClosedTimestampLags)
 	ts, lai = r.GetClosedTimestamp(ctx, 2)
 	require.Equal(t, ts11, ts)
 	require.Equal(t, lai101, lai)
@@ -166,6 +185,88 @@ func TestIncomingStreamProcessUpdateBasic(t *testing.T) {
 	require.Empty(t, stores.getAndClearRecording())
 }
 
+// Test that an incomingStream that detects a gap in the sequence of updates
+// keeps its state, ignores incrementals and asks for a snapshot.
+func TestIncomingStreamProcessUpdateGap(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	nid := &base.NodeIDContainer{}
+	nid.Set(ctx, 1)
+	stores := &mockStores{}
+	server := NewReceiver(nid, stopper, cluster.MakeTestingClusterSettings(), stores, receiverTestingKnobs{})
+	r := newIncomingStream(server, stores)
+	r.nodeID = 1
+
+	msg := &ctpb.Update{
+		NodeID:   1,
+		SeqNum:   1,
+		Snapshot: true,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts10},
+		},
+		AddedOrUpdated: []ctpb.Update_RangeUpdate{
+			{RangeID: 1, LAI: lai100, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		},
+	}
+	require.False(t, r.processUpdate(ctx, msg))
+
+	// Skip sequence number 2. The stream asks for a snapshot and keeps the state
+	// as of the last applied message.
+	msg = &ctpb.Update{
+		NodeID:   1,
+		SeqNum:   3,
+		Snapshot: false,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts12},
+		},
+		Removed: []roachpb.RangeID{1},
+	}
+	require.True(t, r.processUpdate(ctx, msg))
+	ts, lai := r.GetClosedTimestamp(ctx, 1)
+	require.Equal(t, ts10, ts)
+	require.Equal(t, lai100, lai)
+	require.Empty(t, stores.getAndClearRecording())
+
+	// Further incrementals are ignored, and the snapshot is not requested again.
+	msg.SeqNum = 4
+	require.False(t, r.processUpdate(ctx, msg))
+	ts, _ = r.GetClosedTimestamp(ctx, 1)
+	require.Equal(t, ts10, ts)
+	require.Equal(t, ctpb.SeqNum(1), r.status(timeutil.Now()).LastSeqNum)
+
+	// The snapshot resets the state and incrementals are applied again.
+	msg = &ctpb.Update{
+		NodeID:   1,
+		SeqNum:   4,
+		Snapshot: true,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts11},
+		},
+		AddedOrUpdated: []ctpb.Update_RangeUpdate{
+			{RangeID: 2, LAI: lai101, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		},
+	}
+	require.False(t, r.processUpdate(ctx, msg))
+	ts, _ = r.GetClosedTimestamp(ctx, 1)
+	require.Empty(t, ts)
+	msg = &ctpb.Update{
+		NodeID:   1,
+		SeqNum:   5,
+		Snapshot: false,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts12},
+		},
+	}
+	require.False(t, r.processUpdate(ctx, msg))
+	ts, lai = r.GetClosedTimestamp(ctx, 2)
+	require.Equal(t, ts12, ts)
+	require.Equal(t, lai101, lai)
+}
+
 // Test that when the incomingStream calls into the Stores to update a range, it
 // doesn't hold its internal lock. Or, in other words, test that replicas can
 // call into the stream while the stream is blocked updating the stores. In
@@ -182,7 +283,7 @@ func TestIncomingStreamCallsIntoStoresDontHoldLock(t *testing.T) {
 	nid := &base.NodeIDContainer{}
 	nid.Set(ctx, 1)
 	stores := &mockStores{}
-	server := NewReceiver(nid, stopper, stores, receiverTestingKnobs{})
+	server := NewReceiver(nid, stopper, cluster.MakeTestingClusterSettings(), stores, receiverTestingKnobs{})
 	r := newIncomingStream(server, stores)
 	r.nodeID = 1
 
@@ -216,3 +317,247 @@ func TestIncomingStreamCallsIntoStoresDontHoldLock(t *testing.T) {
 	// Unblock the process.
 	ch <- struct{}{}
 }
+
+// TestIncomingStreamPolicyClasses verifies that ranges with lag targets
+// configured through span configs are tracked separately from the ranges using
+// the default lag target for the same policy.
+func TestIncomingStreamPolicyClasses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	nid := &base.NodeIDContainer{}
+	nid.Set(ctx, 1)
+	stores := &mockStores{}
+	server := NewReceiver(nid, stopper, cluster.MakeTestingClusterSettings(), stores, receiverTestingKnobs{})
+	r := newIncomingStream(server, stores)
+	r.nodeID = 1
+
+	msg := &ctpb.Update{
+		NodeID: 1, SeqNum: 1, Snapshot: true,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts10},
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, LagTarget: time.Second, ClosedTimestamp: ts12},
+			{Policy: roachpb.LEAD_FOR_GLOBAL_READS, ClosedTimestamp: ts20},
+		},
+		AddedOrUpdated: []ctpb.Update_RangeUpdate{
+			{RangeID: 1, LAI: lai100, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+			{RangeID: 2, LAI: lai101, Policy: roachpb.LAG_BY_CLUSTER_SETTING, LagTarget: time.Second},
+		},
+	}
+	r.processUpdate(ctx, msg)
+	ts, lai := r.GetClosedTimestamp(ctx, 1)
+	require.Equal(t, ts10, ts)
+	require.Equal(t, lai100, lai)
+	ts, lai = r.GetClosedTimestamp(ctx, 2)
+	require.Equal(t, ts12, ts)
+	require.Equal(t, lai101, lai)
+	// The stream status only reports the default classes.
+	st := r.status(timeutil.Now())
+	require.Equal(t, [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp{ts10, ts20}, st.ClosedTimestamps)
+
+	// Move range 2 to the default class.
+	msg = &ctpb.Update{
+		NodeID: 1, SeqNum: 2, Snapshot: false,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts11},
+			{Policy: roachpb.LEAD_FOR_GLOBAL_READS, ClosedTimestamp: ts21},
+		},
+		AddedOrUpdated: []ctpb.Update_RangeUpdate{
+			{RangeID: 2, LAI: lai101, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		},
+	}
+	r.processUpdate(ctx, msg)
+	ts, _ = r.GetClosedTimestamp(ctx, 2)
+	require.Equal(t, ts11, ts)
+}
+
+// TestReceiverLaggingStreams verifies that streams whose closed timestamps trail
+// present time by too much are flagged as lagging and, depending on the lagging
+// stream policy, excluded from the closed timestamps served by the Receiver.
+func TestReceiverLaggingStreams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	nid := &base.NodeIDContainer{}
+	nid.Set(ctx, 1)
+	st := cluster.MakeTestingClusterSettings()
+	closedts.SideTransportLaggingStreamThreshold.Override(ctx, &st.SV, 10*time.Second)
+	stores := &mockStores{}
+	server := NewReceiver(nid, stopper, st, stores, receiverTestingKnobs{})
+	r := newIncomingStream(server, stores)
+	r.nodeID = 2
+	require.NoError(t, server.onFirstMsg(ctx, r, r.nodeID))
+
+	// A closed timestamp trailing present time by the target duration is not
+	// lagging.
+	recent := hlc.Timestamp{WallTime: timeutil.Now().Add(-closedts.TargetDuration.Get(&st.SV)).UnixNano()}
+	msg := &ctpb.Update{
+		NodeID: 2, SeqNum: 1, Snapshot: true,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: recent},
+		},
+		AddedOrUpdated: []ctpb.Update_RangeUpdate{
+			{RangeID: 1, LAI: lai100, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		},
+	}
+	r.processUpdate(ctx, msg)
+	require.False(t, server.StreamStatuses()[0].Lagging)
+	require.Equal(t, int64(0), server.Metrics().LaggingStreams.Value())
+
+	// A stream that keeps delivering messages is still lagging if its closed
+	// timestamps don't advance.
+	stale := hlc.Timestamp{WallTime: timeutil.Now().Add(-time.Minute).UnixNano()}
+	msg = &ctpb.Update{
+		NodeID: 2, SeqNum: 2, Snapshot: false,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: stale},
+		},
+	}
+	r.processUpdate(ctx, msg)
+	require.True(t, server.StreamStatuses()[0].Lagging)
+	require.Equal(t, int64(1), server.Metrics().LaggingStreams.Value())
+	require.GreaterOrEqual(t, server.Metrics().MaxStreamLag.Value(), time.Minute.Nanoseconds())
+
+	// Lagging streams are only flagged by default.
+	ts, lai := server.GetClosedTimestamp(ctx, 1, r.nodeID)
+	require.Equal(t, stale, ts)
+	require.Equal(t, lai100, lai)
+
+	closedts.SideTransportLaggingStream.Override(ctx, &st.SV, int64(closedts.SideTransportLaggingStreamExclude))
+	ts, lai = server.GetClosedTimestamp(ctx, 1, r.nodeID)
+	require.Equal(t, hlc.Timestamp{}, ts)
+	require.Equal(t, laiZero, lai)
+
+	// Once the stream catches up, its closed timestamps are used again.
+	recent = hlc.Timestamp{WallTime: timeutil.Now().UnixNano()}
+	msg = &ctpb.Update{
+		NodeID: 2, SeqNum: 3, Snapshot: false,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: recent},
+		},
+	}
+	r.processUpdate(ctx, msg)
+	require.False(t, server.StreamStatuses()[0].Lagging)
+	ts, _ = server.GetClosedTimestamp(ctx, 1, r.nodeID)
+	require.Equal(t, recent, ts)
+}
+
+// TestReceiverLagAlerts verifies that an alert is raised once the closed
+// timestamp lag of a policy exceeds the policy's threshold for long enough.
+func TestReceiverLagAlerts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	nid := &base.NodeIDContainer{}
+	nid.Set(ctx, 1)
+	st := cluster.MakeTestingClusterSettings()
+	closedts.LagAlertThresholdLagByClusterSetting.Override(ctx, &st.SV, time.Minute)
+	closedts.LagAlertDuration.Override(ctx, &st.SV, 10*time.Second)
+	stores := &mockStores{}
+	server := NewReceiver(nid, stopper, st, stores, receiverTestingKnobs{})
+	r := newIncomingStream(server, stores)
+	r.nodeID = 2
+	require.NoError(t, server.onFirstMsg(ctx, r, r.nodeID))
+	var events []*eventpb.ClosedTimestampLagExceeded
+	server.logEvent = func(_ context.Context, ev logpb.EventPayload) {
+		events = append(events, ev.(*eventpb.ClosedTimestampLagExceeded))
+	}
+
+	// ts10 lags present time by decades.
+	msg := &ctpb.Update{
+		NodeID: 2, SeqNum: 1, Snapshot: true,
+		ClosedTimestamps: []ctpb.Update_GroupUpdate{
+			{Policy: roachpb.LAG_BY_CLUSTER_SETTING, ClosedTimestamp: ts10},
+		},
+		AddedOrUpdated: []ctpb.Update_RangeUpdate{
+			{RangeID: 1, LAI: lai100, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		},
+	}
+	r.processUpdate(ctx, msg)
+	m := server.Metrics()
+	lagPol, leadPol := roachpb.LAG_BY_CLUSTER_SETTING, roachpb.LEAD_FOR_GLOBAL_READS
+	require.Greater(t, m.MaxClosedTimestampLag[lagPol].Value(), time.Minute.Nanoseconds())
+	require.Equal(t, int64(0), m.MaxClosedTimestampLag[leadPol].Value())
+
+	// The alert is only raised once the lag has exceeded the threshold for the
+	// alert duration, and only once per incident.
+	now := timeutil.Now()
+	server.checkClosedTimestampLag(ctx, now)
+	require.Equal(t, int64(0), m.LagAlerting[lagPol].Value())
+	require.Empty(t, events)
+	server.checkClosedTimestampLag(ctx, now.Add(10*time.Second))
+	require.Equal(t, int64(1), m.LagAlerting[lagPol].Value())
+	require.Equal(t, int64(1), m.LagAlerts[lagPol].Count())
+	require.Len(t, events, 1)
+	require.Equal(t, lagPol.String(), events[0].Policy)
+	require.Equal(t, int32(2), events[0].LeaseholderNodeID)
+	require.Equal(t, time.Minute.Nanoseconds(), events[0].Threshold)
+	require.Equal(t, (10 * time.Second).Nanoseconds(), events[0].ExceededFor)
+	require.Equal(t, now.Add(10*time.Second).UnixNano(), events[0].Timestamp)
+	server.checkClosedTimestampLag(ctx, now.Add(20*time.Second))
+	require.Equal(t, int64(1), m.LagAlerting[lagPol].Value())
+	require.Equal(t, int64(1), m.LagAlerts[lagPol].Count())
+	require.Equal(t, int64(0), m.LagAlerting[leadPol].Value())
+	require.Equal(t, int64(0), m.LagAlerts[leadPol].Count())
+
+	// Disabling the alert clears it.
+	closedts.LagAlertThresholdLagByClusterSetting.Override(ctx, &st.SV, 0)
+	server.checkClosedTimestampLag(ctx, now.Add(30*time.Second))
+	require.Equal(t, int64(0), m.LagAlerting[lagPol].Value())
+
+	// A new incident raises a new alert.
+	closedts.LagAlertThresholdLagByClusterSetting.Override(ctx, &st.SV, time.Minute)
+	server.checkClosedTimestampLag(ctx, now.Add(40*time.Second))
+	require.Equal(t, int64(0), m.LagAlerting[lagPol].Value())
+	server.checkClosedTimestampLag(ctx, now.Add(50*time.Second))
+	require.Equal(t, int64(1), m.LagAlerting[lagPol].Value())
+	require.Equal(t, int64(2), m.LagAlerts[lagPol].Count())
+	require.Len(t, events, 2)
+}
+
+// Test that streams are only accepted from other nodes of the cluster.
+func TestReceiverRejectsUntrustedStreams(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	nid := &base.NodeIDContainer{}
+	nid.Set(ctx, 1)
+	stores := &mockStores{}
+	server := NewReceiver(nid, stopper, cluster.MakeTestingClusterSettings(), stores, receiverTestingKnobs{})
+
+	// A stream needs to identify its sender, and can't claim to come from the
+	// local node.
+	r := newIncomingStream(server, stores)
+	require.Error(t, server.onFirstMsg(ctx, r, 0))
+	require.Error(t, server.onFirstMsg(ctx, r, 1))
+	require.NoError(t, server.onFirstMsg(ctx, r, 2))
+
+	peerCtx := func(user string) context.Context {
+		p := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 26257}}
+		if user != "" {
+			p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: user}}},
+			}}
+		}
+		return peer.NewContext(ctx, p)
+	}
+	// Only node certificates are allowed in secure mode.
+	require.NoError(t, server.authenticatePeer(peerCtx("node")))
+	require.Error(t, server.authenticatePeer(peerCtx("root")))
+	require.Error(t, server.authenticatePeer(peerCtx("testuser")))
+	// In insecure mode, peers can't be authenticated.
+	require.NoError(t, server.authenticatePeer(peerCtx("")))
+	require.Error(t, server.authenticatePeer(ctx))
+}
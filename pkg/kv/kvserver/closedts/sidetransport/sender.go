@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -33,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
 )
 
 // Sender represents the sending-side of the closed timestamps "side-transport".
@@ -60,6 +62,7 @@ type Sender struct {
 	nodeID  roachpb.NodeID
 	// connFactory is used to establish new connections.
 	connFactory connFactory
+	metrics     *SenderMetrics
 
 	trackedMu struct {
 		syncutil.Mutex
@@ -67,6 +70,11 @@ type Sender struct {
 		// closingFailures buckets the failures to advance the closed timestamps of
 		// ranges for the last publishing cycle.
 		closingFailures [MaxReason]int
+		// blocked contains the ranges with local leases that failed to close a
+		// timestamp in the last publishing cycle. A range enters this map when it
+		// first fails to close a timestamp, and exits it as soon as it succeeds
+		// again or as it stops being tracked as a leaseholder.
+		blocked map[roachpb.RangeID]blockedRange
 	}
 
 	leaseholdersMu struct {
@@ -78,6 +86,10 @@ type Sender struct {
 	// to this buffer signals the connections to send it on their streams.
 	buf *updatesBuf
 
+	// expediteC is signaled when a new message should be published without
+	// waiting for the next publishing cycle. See OnLeaseTransferHandoff.
+	expediteC chan struct{}
+
 	// conns contains connections to all nodes with follower replicas of any of
 	// the registered leaseholder. connections are added as nodes get replicas for
 	// ranges with local leases and removed when the respective node no longer has
@@ -96,9 +108,9 @@ type Sender struct {
 type streamState struct {
 	// lastSeqNum is the sequence number of the last message published.
 	lastSeqNum ctpb.SeqNum
-	// lastClosed is the closed timestamp published for each policy in the
+	// lastClosed is the closed timestamp published for each policy class in the
 	// last message.
-	lastClosed [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
+	lastClosed map[closedts.PolicyClass]hlc.Timestamp
 	// tracked maintains the information that was communicated to connections in
 	// the last sent message (implicitly or explicitly). A range enters this
 	// structure as soon as it's included in a message, and exits it when it's
@@ -114,8 +126,56 @@ type connTestingKnobs struct {
 // trackedRange contains the information that the side-transport last published
 // about a particular range.
 type trackedRange struct {
-	lai    ctpb.LAI
-	policy roachpb.RangeClosedTimestampPolicy
+	lai   ctpb.LAI
+	class closedts.PolicyClass
+}
+
+// groupUpdateClass returns the policy class that a GroupUpdate applies to.
+func groupUpdateClass(u *ctpb.Update_GroupUpdate) closedts.PolicyClass {
+	return closedts.PolicyClass{Policy: u.Policy, LagTarget: u.LagTarget}
+}
+
+// makeGroupUpdates returns the GroupUpdates communicating the given closed
+// timestamps, ordered by policy class.
+func makeGroupUpdates(
+	lastClosed map[closedts.PolicyClass]hlc.Timestamp,
+) []ctpb.Update_GroupUpdate {
+	res := make([]ctpb.Update_GroupUpdate, 0, len(lastClosed))
+	for class, ts := range lastClosed {
+		res = append(res, ctpb.Update_GroupUpdate{
+			Policy:          class.Policy,
+			LagTarget:       class.LagTarget,
+			ClosedTimestamp: ts,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return groupUpdateClass(&res[i]).Less(groupUpdateClass(&res[j]))
+	})
+	return res
+}
+
+// blockedRange contains information about a range that's failing to close
+// timestamps.
+type blockedRange struct {
+	storeID roachpb.StoreID
+	reason  CantCloseReason
+	// since is the time of the first publishing cycle (in a run of consecutive
+	// failures) in which the range failed to close a timestamp.
+	since time.Time
+}
+
+// BlockedRange describes a range with a local lease that failed to close a
+// timestamp in the Sender's last publishing cycle.
+type BlockedRange struct {
+	RangeID roachpb.RangeID
+	// StoreID is the store holding the range's lease.
+	StoreID roachpb.StoreID
+	// Reason is the reason why the range failed to close a timestamp in the last
+	// publishing cycle.
+	Reason CantCloseReason
+	// BlockedSince is the time when the range started failing to close
+	// timestamps. The range may have failed for different reasons since then.
+	BlockedSince time.Time
 }
 
 // leaseholder represents a leaseholder replicas that has been registered with
@@ -145,12 +205,12 @@ type Replica interface {
 	// If the closed timestamp was advanced, the function returns a LAI to be
 	// attached to the newly closed timestamp.
 	//
-	// The desired closed timestamp is passed as a map from range policy to
-	// timestamp; this function looks up the entry for this range.
+	// The desired closed timestamp is obtained by passing the range's policy
+	// class to targetForClass.
 	BumpSideTransportClosed(
 		ctx context.Context,
 		now hlc.ClockTimestamp,
-		targetByPolicy [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp,
+		targetForClass func(closedts.PolicyClass) hlc.Timestamp,
 	) BumpSideTransportClosedResult
 }
 
@@ -168,8 +228,8 @@ type BumpSideTransportClosedResult struct {
 
 	// The range's current LAI, to be associated with the closed timestamp.
 	LAI ctpb.LAI
-	// The range's current policy.
-	Policy roachpb.RangeClosedTimestampPolicy
+	// The range's current policy class.
+	Class closedts.PolicyClass
 }
 
 // CantCloseReason enumerates the reasons why BunpSideTransportClosed might fail
@@ -206,14 +266,23 @@ func newSenderWithConnFactory(
 		st:          st,
 		clock:       clock,
 		connFactory: connFactory,
+		metrics:     makeSenderMetrics(),
 		buf:         newUpdatesBuf(),
+		expediteC:   make(chan struct{}, 1),
 	}
+	s.trackedMu.lastClosed = make(map[closedts.PolicyClass]hlc.Timestamp)
 	s.trackedMu.tracked = make(map[roachpb.RangeID]trackedRange)
+	s.trackedMu.blocked = make(map[roachpb.RangeID]blockedRange)
 	s.leaseholdersMu.leaseholders = make(map[roachpb.RangeID]leaseholder)
 	s.connsMu.conns = make(map[roachpb.NodeID]conn)
 	return s
 }
 
+// Metrics returns the Sender's metrics.
+func (s *Sender) Metrics() *SenderMetrics {
+	return s.metrics
+}
+
 // Run starts a goroutine that periodically closes new timestamps for all the
 // ranges where the leaseholder is on this node.
 //
@@ -252,6 +321,12 @@ func (s *Sender) Run(ctx context.Context, nodeID roachpb.NodeID) {
 				case <-timer.C:
 					timer.Read = true
 					s.publish(ctx)
+				case <-s.expediteC:
+					if interval <= 0 {
+						// The side-transport is disabled.
+						continue
+					}
+					s.publish(ctx)
 				case <-confCh:
 					// Loop around to use the updated timer.
 					continue
@@ -300,6 +375,32 @@ func (s *Sender) UnregisterLeaseholder(
 	}
 }
 
+// OnLeaseTransferHandoff is called when a replica registered through
+// RegisterLeaseholder acquired its lease through a lease transfer that handed
+// off the closed timestamp side-transport state of the outgoing leaseholder.
+// The outgoing leaseholder stopped closing timestamps for the range when it
+// proposed the transfer, so the Sender publishes a new message as soon as
+// possible instead of waiting for the next publishing cycle, in order for the
+// followers to hear about the range from its new leaseholder without a gap.
+// Expedited messages are coalesced, so that many concurrent lease transfers
+// only cause a few extra messages.
+func (s *Sender) OnLeaseTransferHandoff(
+	ctx context.Context, rangeID roachpb.RangeID, leaseSeq roachpb.LeaseSequence,
+) {
+	s.leaseholdersMu.Lock()
+	lh, ok := s.leaseholdersMu.leaseholders[rangeID]
+	s.leaseholdersMu.Unlock()
+	if !ok || lh.leaseSeq != leaseSeq {
+		// The lease has moved on already.
+		return
+	}
+	log.VEventf(ctx, 2, "side-transport expediting publication for r%d after lease transfer", rangeID)
+	select {
+	case s.expediteC <- struct{}{}:
+	default:
+	}
+}
+
 func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 	s.trackedMu.Lock()
 	defer s.trackedMu.Unlock()
@@ -307,8 +408,7 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 	s.trackedMu.closingFailures = [MaxReason]int{}
 
 	msg := &ctpb.Update{
-		NodeID:           s.nodeID,
-		ClosedTimestamps: make([]ctpb.Update_GroupUpdate, len(s.trackedMu.lastClosed)),
+		NodeID: s.nodeID,
 	}
 
 	// Determine the message's sequence number.
@@ -319,28 +419,33 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 	msg.Snapshot = msg.SeqNum == 1
 
 	// Fix the closed timestamps that will be communicated to by this message.
-	// These timestamps (one per range policy) will apply to all the ranges
-	// included in message.
+	// These timestamps (one per range policy class) will apply to all the ranges
+	// included in message. The timestamps for the default class of every policy
+	// are always included; the timestamps for classes with custom lag targets
+	// are computed as the ranges in these classes are encountered.
 	now := s.clock.NowAsClockTimestamp()
 	maxClockOffset := s.clock.MaxOffset()
 	lagTargetDuration := closedts.TargetDuration.Get(&s.st.SV)
 	leadTargetOverride := closedts.LeadForGlobalReadsOverride.Get(&s.st.SV)
 	sideTransportCloseInterval := closedts.SideTransportCloseInterval.Get(&s.st.SV)
-	for i := range s.trackedMu.lastClosed {
-		pol := roachpb.RangeClosedTimestampPolicy(i)
-		target := closedts.TargetForPolicy(
+	lastClosed := make(map[closedts.PolicyClass]hlc.Timestamp, len(s.trackedMu.lastClosed))
+	targetForClass := func(class closedts.PolicyClass) hlc.Timestamp {
+		if target, ok := lastClosed[class]; ok {
+			return target
+		}
+		target := closedts.TargetForPolicyClass(
 			now,
 			maxClockOffset,
 			lagTargetDuration,
 			leadTargetOverride,
 			sideTransportCloseInterval,
-			pol,
+			class,
 		)
-		s.trackedMu.lastClosed[pol] = target
-		msg.ClosedTimestamps[pol] = ctpb.Update_GroupUpdate{
-			Policy:          pol,
-			ClosedTimestamp: target,
-		}
+		lastClosed[class] = target
+		return target
+	}
+	for pol := roachpb.RangeClosedTimestampPolicy(0); pol < roachpb.MAX_CLOSED_TIMESTAMP_POLICY; pol++ {
+		targetForClass(closedts.PolicyClass{Policy: pol})
 	}
 
 	// Make a copy of the leaseholders map, in order to release its mutex
@@ -366,6 +471,11 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 			delete(s.trackedMu.tracked, rid)
 		}
 	}
+	for rid := range s.trackedMu.blocked {
+		if _, ok := leaseholders[rid]; !ok {
+			delete(s.trackedMu.blocked, rid)
+		}
+	}
 
 	// Iterate through each leaseholder and determine whether it can be part of
 	// this update or not.
@@ -374,7 +484,7 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 		lastMsg, tracked := s.trackedMu.tracked[lhRangeID]
 
 		// Check whether the desired timestamp can be closed on this range.
-		closeRes := lh.BumpSideTransportClosed(ctx, now, s.trackedMu.lastClosed)
+		closeRes := lh.BumpSideTransportClosed(ctx, now, targetForClass)
 
 		// Ensure that we're communicating with all of the range's followers. Note
 		// that we're including this range's followers before deciding below if the
@@ -388,6 +498,13 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 
 		if !closeRes.OK {
 			s.trackedMu.closingFailures[closeRes.FailReason]++
+			b, ok := s.trackedMu.blocked[lhRangeID]
+			if !ok {
+				b.since = now.ToTimestamp().GoTime()
+			}
+			b.storeID = lh.StoreID()
+			b.reason = closeRes.FailReason
+			s.trackedMu.blocked[lhRangeID] = b
 			// We can't close the desired timestamp. If this range was tracked, we
 			// need to un-track it.
 			if tracked {
@@ -396,6 +513,7 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 			}
 			continue
 		}
+		delete(s.trackedMu.blocked, lhRangeID)
 
 		// Check whether the range needs to be explicitly updated through the
 		// current message, or if its update can be implicit.
@@ -408,22 +526,31 @@ func (s *Sender) publish(ctx context.Context) hlc.ClockTimestamp {
 			// If the range's LAI has changed, we need to explicitly publish the new
 			// LAI.
 			needExplicit = true
-		} else if lastMsg.policy != closeRes.Policy {
-			// If the policy changed, we need to explicitly publish that; the
+		} else if lastMsg.class != closeRes.Class {
+			// If the policy class changed, we need to explicitly publish that; the
 			// receiver will updates its bookkeeping to indicate that this range is
-			// updated through implicit updates for the new policy.
+			// updated through implicit updates for the new class.
 			needExplicit = true
 		}
 		if needExplicit {
 			msg.AddedOrUpdated = append(msg.AddedOrUpdated, ctpb.Update_RangeUpdate{
-				RangeID: lhRangeID,
-				LAI:     closeRes.LAI,
-				Policy:  closeRes.Policy,
+				RangeID:   lhRangeID,
+				LAI:       closeRes.LAI,
+				Policy:    closeRes.Class.Policy,
+				LagTarget: closeRes.Class.LagTarget,
 			})
-			s.trackedMu.tracked[lhRangeID] = trackedRange{lai: closeRes.LAI, policy: closeRes.Policy}
+			s.trackedMu.tracked[lhRangeID] = trackedRange{lai: closeRes.LAI, class: closeRes.Class}
 		}
 	}
 
+	// Every range included in the message, implicitly or explicitly, requested
+	// the target of its class above, so the message includes the closed
+	// timestamps of all the classes in use.
+	s.trackedMu.lastClosed = lastClosed
+	msg.ClosedTimestamps = makeGroupUpdates(lastClosed)
+
+	s.metrics.update(&s.trackedMu.closingFailures)
+
 	// Close connections to the nodes that no longer need any info from us
 	// (because they don't have replicas for any of the ranges with leases on this
 	// node).
@@ -474,25 +601,57 @@ func (s *Sender) GetSnapshot() *ctpb.Update {
 		// of incremental messages.
 		SeqNum:           s.trackedMu.lastSeqNum,
 		Snapshot:         true,
-		ClosedTimestamps: make([]ctpb.Update_GroupUpdate, len(s.trackedMu.lastClosed)),
+		ClosedTimestamps: makeGroupUpdates(s.trackedMu.lastClosed),
 		AddedOrUpdated:   make([]ctpb.Update_RangeUpdate, 0, len(s.trackedMu.tracked)),
 	}
-	for pol, ts := range s.trackedMu.lastClosed {
-		msg.ClosedTimestamps[pol] = ctpb.Update_GroupUpdate{
-			Policy:          roachpb.RangeClosedTimestampPolicy(pol),
-			ClosedTimestamp: ts,
-		}
-	}
 	for rid, r := range s.trackedMu.tracked {
 		msg.AddedOrUpdated = append(msg.AddedOrUpdated, ctpb.Update_RangeUpdate{
-			RangeID: rid,
-			LAI:     r.lai,
-			Policy:  r.policy,
+			RangeID:   rid,
+			LAI:       r.lai,
+			Policy:    r.class.Policy,
+			LagTarget: r.class.LagTarget,
 		})
 	}
 	return msg
 }
 
+// compressor returns the name of the gRPC compressor to be used by new streams,
+// as dictated by the kv.closed_timestamp.side_transport_compression setting.
+func (s *Sender) compressor(ctx context.Context) string {
+	switch closedts.SideTransportCompressionMode(closedts.SideTransportCompression.Get(&s.st.SV)) {
+	case closedts.SideTransportCompressionNone:
+		return encoding.Identity
+	case closedts.SideTransportCompressionZstd:
+		// Nodes running older versions can't decompress zstd messages; fall back
+		// to snappy until the cluster is upgraded.
+		if s.st.Version.IsActive(ctx, clusterversion.ZstdRPCCompressor) {
+			return rpc.ZstdCompressorName
+		}
+	}
+	return rpc.SnappyCompressorName
+}
+
+// BlockedRanges returns the ranges with local leases that failed to close a
+// timestamp in the last publishing cycle, ordered by range ID.
+func (s *Sender) BlockedRanges() []BlockedRange {
+	s.trackedMu.Lock()
+	defer s.trackedMu.Unlock()
+
+	res := make([]BlockedRange, 0, len(s.trackedMu.blocked))
+	for rid, b := range s.trackedMu.blocked {
+		res = append(res, BlockedRange{
+			RangeID:      rid,
+			StoreID:      b.storeID,
+			Reason:       b.reason,
+			BlockedSince: b.since,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].RangeID < res[j].RangeID
+	})
+	return res
+}
+
 // updatesBuf is a circular buffer of Updates. It's created with a given
 // capacity and, once it fills up, new items overwrite the oldest ones. It lets
 // consumers query for the update with a particular sequence number and it lets
@@ -675,7 +834,9 @@ const sleepOnErr = time.Second
 //
 // The connection will read messages from producer.buf. If the buffer overflows
 // (because this stream is disconnected for long enough), we'll have to send a
-// snapshot before we can resume sending regular messages.
+// snapshot before we can resume sending regular messages. A snapshot is also
+// sent when the receiver asks for one because it detected a gap in the
+// sequence of messages.
 type rpcConn struct {
 	log.AmbientContext
 	dialer       nodeDialer
@@ -689,6 +850,9 @@ type rpcConn struct {
 	// It needs to be called whenever stream is discarded.
 	cancelStreamCtx context.CancelFunc
 	closed          int32 // atomic
+	// snapshotRequested is set when the receiver asks for a snapshot on the
+	// current stream.
+	snapshotRequested int32 // atomic
 
 	mu struct {
 		syncutil.Mutex
@@ -753,13 +917,37 @@ func (r *rpcConn) maybeConnect(ctx context.Context, stopper *stop.Stopper) error
 	if err != nil {
 		return err
 	}
+	// The compressor is picked for every new stream. The receiver decompresses
+	// messages according to the encoding advertised by the stream, so there's
+	// no need to coordinate with it.
+	compressor := r.producer.compressor(ctx)
 	streamCtx, cancel := context.WithCancel(ctx)
-	stream, err := ctpb.NewSideTransportClient(conn).PushUpdates(streamCtx)
+	stream, err := ctpb.NewSideTransportClient(conn).PushUpdates(
+		streamCtx, grpc.UseCompressor(compressor))
 	if err != nil {
 		cancel()
 		return err
 	}
-	r.recordConnect()
+	// Listen for snapshot requests from the receiver. Receivers that don't
+	// request snapshots never send anything, in which case Recv() blocks until
+	// the stream is torn down.
+	atomic.StoreInt32(&r.snapshotRequested, 0)
+	if err := stopper.RunAsyncTask(streamCtx, "closedts side-transport snapshot requests",
+		func(ctx context.Context) {
+			for {
+				resp, err := stream.Recv()
+				if err != nil {
+					return
+				}
+				if resp.RequestSnapshot {
+					atomic.StoreInt32(&r.snapshotRequested, 1)
+				}
+			}
+		}); err != nil {
+		cancel()
+		return err
+	}
+	r.recordConnect(compressor)
 	r.stream = stream
 	// This will need to be called when we're done with the stream.
 	r.cancelStreamCtx = cancel
@@ -808,10 +996,12 @@ func (r *rpcConn) run(ctx context.Context, stopper *stop.Stopper) {
 					return
 				}
 
-				if msg == nil {
-					// The sequence number we've requested is no longer in the buffer. We
-					// need to generate a snapshot in order to re-initialize the stream.
-					// The snapshot will give us the sequence number to use for future
+				snapshotRequested := atomic.CompareAndSwapInt32(&r.snapshotRequested, 1, 0)
+				if msg == nil || snapshotRequested {
+					// The sequence number we've requested is no longer in the buffer, or
+					// the receiver lost some messages and asked for a snapshot. We need
+					// to generate a snapshot in order to re-initialize the stream. The
+					// snapshot will give us the sequence number to use for future
 					// incrementals.
 					msg = r.producer.GetSnapshot()
 				}
@@ -842,6 +1032,7 @@ func (r *rpcConn) run(ctx context.Context, stopper *stop.Stopper) {
 type connState struct {
 	connected          bool
 	connectedTime      time.Time
+	compressor         string
 	lastDisconnect     error
 	lastDisconnectTime time.Time
 }
@@ -852,10 +1043,11 @@ func (r *rpcConn) getState() connState {
 	return r.mu.state
 }
 
-func (r *rpcConn) recordConnect() {
+func (r *rpcConn) recordConnect(compressor string) {
 	r.mu.Lock()
 	r.mu.state.connected = true
 	r.mu.state.connectedTime = timeutil.Now()
+	r.mu.state.compressor = compressor
 	r.mu.Unlock()
 }
 
@@ -867,10 +1059,11 @@ func (s streamState) String() string {
 	// List the closed timestamps.
 	sb.WriteString("closed timestamps: ")
 	now := timeutil.Now()
-	for policy, closedTS := range s.lastClosed {
-		if policy != 0 {
+	for i, upd := range makeGroupUpdates(s.lastClosed) {
+		if i != 0 {
 			sb.WriteString(", ")
 		}
+		closedTS := upd.ClosedTimestamp
 		ago := now.Sub(closedTS.GoTime()).Truncate(time.Millisecond)
 		var agoMsg string
 		if ago >= 0 {
@@ -878,7 +1071,7 @@ func (s streamState) String() string {
 		} else {
 			agoMsg = fmt.Sprintf("%s in the future", -ago)
 		}
-		fmt.Fprintf(sb, "%s:%s (%s)", roachpb.RangeClosedTimestampPolicy(policy), closedTS, agoMsg)
+		fmt.Fprintf(sb, "%s:%s (%s)", groupUpdateClass(&upd), closedTS, agoMsg)
 	}
 
 	// List the tracked ranges.
@@ -887,12 +1080,12 @@ func (s streamState) String() string {
 		id roachpb.RangeID
 		trackedRange
 	}
-	rangesByPolicy := make(map[roachpb.RangeClosedTimestampPolicy][]rangeInfo)
+	rangesByClass := make(map[closedts.PolicyClass][]rangeInfo)
 	for rid, info := range s.tracked {
-		rangesByPolicy[info.policy] = append(rangesByPolicy[info.policy], rangeInfo{id: rid, trackedRange: info})
+		rangesByClass[info.class] = append(rangesByClass[info.class], rangeInfo{id: rid, trackedRange: info})
 	}
-	for policy, ranges := range rangesByPolicy {
-		fmt.Fprintf(sb, "%s: ", policy)
+	for class, ranges := range rangesByClass {
+		fmt.Fprintf(sb, "%s: ", class)
 		sort.Slice(ranges, func(i, j int) bool {
 			return ranges[i].id < ranges[j].id
 		})
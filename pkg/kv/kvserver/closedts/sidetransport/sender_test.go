@@ -14,11 +14,13 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -33,6 +35,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
 )
 
 // mockReplica is a mock implementation of the Replica interface.
@@ -47,7 +50,7 @@ type mockReplica struct {
 	canBump        bool
 	cantBumpReason CantCloseReason
 	lai            ctpb.LAI
-	policy         roachpb.RangeClosedTimestampPolicy
+	class          closedts.PolicyClass
 }
 
 var _ Replica = &mockReplica{}
@@ -55,10 +58,13 @@ var _ Replica = &mockReplica{}
 func (m *mockReplica) StoreID() roachpb.StoreID    { return m.storeID }
 func (m *mockReplica) GetRangeID() roachpb.RangeID { return m.rangeID }
 func (m *mockReplica) BumpSideTransportClosed(
-	_ context.Context, _ hlc.ClockTimestamp, _ [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp,
+	_ context.Context,
+	_ hlc.ClockTimestamp,
+	targetForClass func(closedts.PolicyClass) hlc.Timestamp,
 ) BumpSideTransportClosedResult {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	_ = targetForClass(m.class)
 	reason := ReasonUnknown
 	if !m.canBump {
 		reason = m.cantBumpReason
@@ -68,7 +74,7 @@ func (m *mockReplica) BumpSideTransportClosed(
 		FailReason: reason,
 		Desc:       &m.mu.desc,
 		LAI:        m.lai,
-		Policy:     m.policy,
+		Class:      m.class,
 	}
 }
 
@@ -124,7 +130,7 @@ func newMockReplica(id roachpb.RangeID, nodes ...roachpb.NodeID) *mockReplica {
 		rangeID: id,
 		canBump: true,
 		lai:     5,
-		policy:  roachpb.LAG_BY_CLUSTER_SETTING,
+		class:   closedts.PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING},
 	}
 	r.mu.desc = desc
 	return r
@@ -177,7 +183,7 @@ func TestSenderBasic(t *testing.T) {
 	now = s.publish(ctx)
 	require.Len(t, s.trackedMu.tracked, 1)
 	require.Equal(t, map[roachpb.RangeID]trackedRange{
-		15: {lai: 5, policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		15: {lai: 5, class: closedts.PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING}},
 	}, s.trackedMu.tracked)
 	require.Len(t, s.leaseholdersMu.leaseholders, 1)
 	require.Len(t, s.connsMu.conns, 2)
@@ -209,6 +215,13 @@ func TestSenderBasic(t *testing.T) {
 	require.Len(t, s.leaseholdersMu.leaseholders, 1)
 	require.Len(t, s.connsMu.conns, 2)
 	require.Equal(t, 1, s.trackedMu.closingFailures[r1.cantBumpReason])
+	require.Equal(t, int64(1), s.Metrics().FailuresToClose[r1.cantBumpReason].Count())
+	require.Equal(t, int64(1), s.Metrics().RangesNotClosing[r1.cantBumpReason].Value())
+	require.Equal(t, int64(0), s.Metrics().RangesNotClosing[InvalidLease].Value())
+	blockedSince := now.ToTimestamp().GoTime()
+	require.Equal(t, []BlockedRange{
+		{RangeID: 15, StoreID: 1, Reason: ProposalsInFlight, BlockedSince: blockedSince},
+	}, s.BlockedRanges())
 
 	require.Equal(t, ctpb.SeqNum(3), s.trackedMu.lastSeqNum)
 	up, ok = s.buf.GetBySeq(ctx, 3)
@@ -220,18 +233,27 @@ func TestSenderBasic(t *testing.T) {
 	require.Equal(t, []roachpb.RangeID{15}, up.Removed)
 	require.Nil(t, up.AddedOrUpdated)
 
+	// The leaseholder fails to close the next timestamp for a different reason.
+	// The range has been blocked since the previous failure.
+	r1.cantBumpReason = RequestsEvaluatingBelowTarget
+	s.publish(ctx)
+	require.Equal(t, []BlockedRange{
+		{RangeID: 15, StoreID: 1, Reason: RequestsEvaluatingBelowTarget, BlockedSince: blockedSince},
+	}, s.BlockedRanges())
+
 	// The leaseholder loses its lease.
 	s.UnregisterLeaseholder(ctx, 1, 15)
 	now = s.publish(ctx)
 	require.Len(t, s.trackedMu.tracked, 0)
+	require.Empty(t, s.BlockedRanges())
 	require.Len(t, s.leaseholdersMu.leaseholders, 0)
 	require.Len(t, s.connsMu.conns, 0)
 
-	require.Equal(t, ctpb.SeqNum(4), s.trackedMu.lastSeqNum)
-	up, ok = s.buf.GetBySeq(ctx, 4)
+	require.Equal(t, ctpb.SeqNum(5), s.trackedMu.lastSeqNum)
+	up, ok = s.buf.GetBySeq(ctx, 5)
 	require.True(t, ok)
 	require.Equal(t, roachpb.NodeID(1), up.NodeID)
-	require.Equal(t, ctpb.SeqNum(4), up.SeqNum)
+	require.Equal(t, ctpb.SeqNum(5), up.SeqNum)
 	require.Equal(t, false, up.Snapshot)
 	require.Equal(t, expGroupUpdates(s, now), up.ClosedTimestamps)
 	require.Nil(t, up.Removed)
@@ -241,6 +263,140 @@ func TestSenderBasic(t *testing.T) {
 	require.True(t, c3.(*mockConn).closed)
 }
 
+// TestSenderLeaseTransferHandoff verifies that the Sender publishes a new
+// message without waiting for its next publishing cycle when a registered
+// leaseholder acquired its lease through a lease transfer.
+func TestSenderLeaseTransferHandoff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	connFactory := &mockConnFactory{}
+	s, stopper := newMockSender(connFactory)
+	defer stopper.Stop(ctx)
+	// Make sure that the regular publishing cycle doesn't kick in.
+	closedts.SideTransportCloseInterval.Override(ctx, &s.st.SV, time.Hour)
+	s.Run(ctx, 1 /* nodeID */)
+
+	lastSeqNum := func() ctpb.SeqNum {
+		s.trackedMu.Lock()
+		defer s.trackedMu.Unlock()
+		return s.trackedMu.lastSeqNum
+	}
+
+	r1 := newMockReplica(15, 1, 2, 3)
+	s.RegisterLeaseholder(ctx, r1, 2 /* leaseSeq */)
+
+	// A handoff for a stale lease is ignored.
+	s.OnLeaseTransferHandoff(ctx, 15, 1 /* leaseSeq */)
+	require.Len(t, s.expediteC, 0)
+	require.Equal(t, ctpb.SeqNum(0), lastSeqNum())
+
+	// A handoff for the registered lease causes a message to be published.
+	s.OnLeaseTransferHandoff(ctx, 15, 2 /* leaseSeq */)
+	testutils.SucceedsSoon(t, func() error {
+		if seq := lastSeqNum(); seq != 1 {
+			return errors.Errorf("expected a published message, last seq num: %d", seq)
+		}
+		return nil
+	})
+	up, ok := s.buf.GetBySeq(ctx, 1)
+	require.True(t, ok)
+	require.Equal(t, []ctpb.Update_RangeUpdate{
+		{RangeID: 15, LAI: 5, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+	}, up.AddedOrUpdated)
+}
+
+// TestSenderPolicyClasses verifies that ranges with lag targets configured
+// through span configs are grouped into their own policy classes.
+func TestSenderPolicyClasses(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	connFactory := &mockConnFactory{}
+	s, stopper := newMockSender(connFactory)
+	defer stopper.Stop(ctx)
+
+	lagClass := closedts.PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING, LagTarget: time.Second}
+	r1 := newMockReplica(15, 1, 2)
+	r2 := newMockReplica(16, 1, 2)
+	r2.class = lagClass
+	s.RegisterLeaseholder(ctx, r1, 1)
+	s.RegisterLeaseholder(ctx, r2, 1)
+	now := s.publish(ctx)
+	require.Equal(t, map[roachpb.RangeID]trackedRange{
+		15: {lai: 5, class: closedts.PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING}},
+		16: {lai: 5, class: lagClass},
+	}, s.trackedMu.tracked)
+
+	up, ok := s.buf.GetBySeq(ctx, 1)
+	require.True(t, ok)
+	defaultGroups := expGroupUpdates(s, now)
+	expGroups := []ctpb.Update_GroupUpdate{
+		defaultGroups[0],
+		{
+			Policy:    roachpb.LAG_BY_CLUSTER_SETTING,
+			LagTarget: time.Second,
+			ClosedTimestamp: closedts.TargetForPolicyClass(
+				now,
+				s.clock.MaxOffset(),
+				closedts.TargetDuration.Get(&s.st.SV),
+				closedts.LeadForGlobalReadsOverride.Get(&s.st.SV),
+				closedts.SideTransportCloseInterval.Get(&s.st.SV),
+				lagClass,
+			),
+		},
+		defaultGroups[1],
+	}
+	require.Equal(t, expGroups, up.ClosedTimestamps)
+	sort.Slice(up.AddedOrUpdated, func(i, j int) bool {
+		return up.AddedOrUpdated[i].RangeID < up.AddedOrUpdated[j].RangeID
+	})
+	require.Equal(t, []ctpb.Update_RangeUpdate{
+		{RangeID: 15, LAI: 5, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+		{RangeID: 16, LAI: 5, Policy: roachpb.LAG_BY_CLUSTER_SETTING, LagTarget: time.Second},
+	}, up.AddedOrUpdated)
+	require.Equal(t, expGroups, s.GetSnapshot().ClosedTimestamps)
+
+	// The range switches back to the default lag target. The range needs to be
+	// updated explicitly, and the custom class is no longer published.
+	r2.class = closedts.PolicyClass{Policy: roachpb.LAG_BY_CLUSTER_SETTING}
+	now = s.publish(ctx)
+	up, ok = s.buf.GetBySeq(ctx, 2)
+	require.True(t, ok)
+	require.Equal(t, expGroupUpdates(s, now), up.ClosedTimestamps)
+	require.Equal(t, []ctpb.Update_RangeUpdate{
+		{RangeID: 16, LAI: 5, Policy: roachpb.LAG_BY_CLUSTER_SETTING},
+	}, up.AddedOrUpdated)
+}
+
+func TestSenderCompressor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	s, stopper := newMockSender(&mockConnFactory{})
+	defer stopper.Stop(ctx)
+
+	setCompression := func(st *cluster.Settings, mode closedts.SideTransportCompressionMode) {
+		closedts.SideTransportCompression.Override(ctx, &st.SV, int64(mode))
+	}
+	require.Equal(t, rpc.SnappyCompressorName, s.compressor(ctx))
+	setCompression(s.st, closedts.SideTransportCompressionNone)
+	require.Equal(t, encoding.Identity, s.compressor(ctx))
+	setCompression(s.st, closedts.SideTransportCompressionZstd)
+	require.Equal(t, rpc.ZstdCompressorName, s.compressor(ctx))
+
+	// Before the cluster is upgraded, zstd falls back to snappy.
+	s.st = cluster.MakeTestingClusterSettingsWithVersions(
+		clusterversion.TestingBinaryVersion,
+		clusterversion.TestingBinaryMinSupportedVersion,
+		false, /* initializeVersion */
+	)
+	require.NoError(t, clusterversion.Initialize(
+		ctx, clusterversion.ByKey(clusterversion.ZstdRPCCompressor-1), &s.st.SV))
+	setCompression(s.st, closedts.SideTransportCompressionZstd)
+	require.Equal(t, rpc.SnappyCompressorName, s.compressor(ctx))
+}
+
 func TestSenderConnectionChanges(t *testing.T) {
 	// TODO: Two ranges.
 	// Add follower for range 1: 2, 3.
@@ -534,7 +690,7 @@ func TestSenderReceiverIntegration(t *testing.T) {
 			}
 		}
 		knobs[1] = incomingFromN1Knobs
-		receivers[i] = NewReceiver(nid, receiverStop, stores, knobs)
+		receivers[i] = NewReceiver(nid, receiverStop, cluster.MakeTestingClusterSettings(), stores, knobs)
 		srv, err := newMockSideTransportGRPCServerWithOpts(ctx, receiverStop, receivers[i])
 		dialer.addOrUpdateNode(nid.Get(), srv.addr().String())
 		require.NoError(t, err)
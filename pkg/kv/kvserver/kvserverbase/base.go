@@ -34,6 +34,17 @@ var MergeQueueEnabled = settings.RegisterBoolSetting(
 	true,
 )
 
+// RangefeedEnabled is a cluster setting that enables rangefeed requests.
+// Certain ranges have span configs that specifically enable rangefeeds (system
+// ranges and ranges covering tables in the system database); this setting
+// covers everything else.
+var RangefeedEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"kv.rangefeed.enabled",
+	"if set, rangefeed registration is enabled",
+	false,
+).WithPublic()
+
 // CmdIDKey is a Raft command id. This will be logged unredacted - keep it random.
 type CmdIDKey string
 
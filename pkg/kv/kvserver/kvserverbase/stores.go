@@ -38,6 +38,12 @@ type Store interface {
 
 	// SetQueueActive disables/enables the named queue.
 	SetQueueActive(active bool, queue string) error
+
+	// VisitTrippedCircuitBreakers calls the given function with the range ID
+	// of each replica on the store whose circuit breaker is tripped, along
+	// with the error that the breaker returns to requests. Visiting stops at
+	// the first error returned by the function.
+	VisitTrippedCircuitBreakers(visitor func(rangeID roachpb.RangeID, err error) error) error
 }
 
 // UnsupportedStoresIterator is a StoresIterator that only returns "unsupported"
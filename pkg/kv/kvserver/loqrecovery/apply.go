@@ -17,11 +17,13 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
@@ -342,3 +344,68 @@ func CommitReplicaChanges(batches map[roachpb.StoreID]storage.Batch) (ApplyUpdat
 	}
 	return report, nil
 }
+
+// ApplyStagedPlan applies the recovery plan staged in the plan store, if any,
+// to the replicas in the provided stores. It is called during node startup
+// before the stores are started. The staged plan is removed once it is
+// processed, regardless of the outcome, so that a plan that can't be applied
+// doesn't prevent the node from starting on the next attempt.
+func ApplyStagedPlan(
+	ctx context.Context,
+	planStore PlanStore,
+	engines []storage.Engine,
+	uuidGen uuid.Generator,
+	updateTime time.Time,
+) error {
+	plan, ok, err := planStore.LoadPlan()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	defer func() {
+		if err := planStore.RemovePlan(); err != nil {
+			log.Errorf(ctx, "failed to remove staged loss of quorum recovery plan: %v", err)
+		}
+	}()
+
+	var nodeID roachpb.NodeID
+	batches := make(map[roachpb.StoreID]storage.Batch)
+	for _, eng := range engines {
+		storeIdent, err := kvserver.ReadStoreIdent(ctx, eng)
+		if errors.HasType(err, (*kvserver.NotBootstrappedError)(nil)) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		nodeID = storeIdent.NodeID
+		batch := eng.NewBatch()
+		defer batch.Close()
+		batches[storeIdent.StoreID] = batch
+	}
+	if len(batches) == 0 {
+		log.Warningf(ctx, "ignoring loss of quorum recovery plan %s staged on a node without "+
+			"initialized stores", plan.PlanID)
+		return nil
+	}
+
+	report, err := PrepareUpdateReplicas(ctx, plan, uuidGen, updateTime, nodeID, batches)
+	if err != nil {
+		return err
+	}
+	if len(report.MissingStores) > 0 {
+		log.Warningf(ctx, "loss of quorum recovery plan %s has updates for stores %v which are "+
+			"not present on this node", plan.PlanID, report.MissingStores)
+	}
+	for _, r := range report.UpdatedReplicas {
+		log.Infof(ctx, "updating replica %s of r%d to %s, removing peer replica(s) %s",
+			r.OldReplica, r.RangeID(), r.Replica, r.RemovedReplicas)
+	}
+	if _, err := CommitReplicaChanges(batches); err != nil {
+		return err
+	}
+	log.Infof(ctx, "applied loss of quorum recovery plan %s: updated %d replica(s), skipped %d",
+		plan.PlanID, len(report.UpdatedReplicas), len(report.SkippedReplicas))
+	return nil
+}
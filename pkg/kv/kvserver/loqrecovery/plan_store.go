@@ -0,0 +1,108 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"io"
+	"path/filepath"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+)
+
+const (
+	planDirName  = "loss-of-quorum-recovery"
+	planFileName = "staged-plan.bin"
+	planTempName = planFileName + ".tmp"
+)
+
+// PlanStore persists a recovery plan staged on a running node, so that it can
+// be applied to the node's stores when the node is restarted. Plans are kept
+// in the auxiliary directory of a store, which is available before the node
+// has joined the cluster.
+type PlanStore struct {
+	path string
+	fs   fs.FS
+}
+
+// NewPlanStore creates a plan store which keeps its files in the given
+// directory of the provided filesystem.
+func NewPlanStore(auxDir string, fs fs.FS) PlanStore {
+	return PlanStore{path: filepath.Join(auxDir, planDirName), fs: fs}
+}
+
+// SavePlan writes the plan to the store, replacing any previously staged plan.
+// The plan is first written to a temporary file that is then renamed, so that
+// a partially written plan is never observed.
+func (s PlanStore) SavePlan(plan loqrecoverypb.ReplicaUpdatePlan) error {
+	data, err := protoutil.Marshal(&plan)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal recovery plan")
+	}
+	if err := s.fs.MkdirAll(s.path); err != nil {
+		return errors.Wrapf(err, "failed to create recovery plan directory %q", s.path)
+	}
+	tempName := filepath.Join(s.path, planTempName)
+	f, err := s.fs.Create(tempName)
+	if err != nil {
+		return errors.Wrap(err, "failed to create recovery plan file")
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to write recovery plan")
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to sync recovery plan")
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "failed to close recovery plan file")
+	}
+	if err := s.fs.Rename(tempName, filepath.Join(s.path, planFileName)); err != nil {
+		return errors.Wrap(err, "failed to save recovery plan")
+	}
+	return nil
+}
+
+// LoadPlan reads the staged plan from the store. The returned bool is false if
+// no plan is staged.
+func (s PlanStore) LoadPlan() (loqrecoverypb.ReplicaUpdatePlan, bool, error) {
+	var plan loqrecoverypb.ReplicaUpdatePlan
+	f, err := s.fs.Open(filepath.Join(s.path, planFileName))
+	if oserror.IsNotExist(err) {
+		return plan, false, nil
+	}
+	if err != nil {
+		return plan, false, errors.Wrap(err, "failed to open recovery plan file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return plan, false, errors.Wrap(err, "failed to read recovery plan")
+	}
+	if err := protoutil.Unmarshal(data, &plan); err != nil {
+		return plan, false, errors.Wrap(err, "failed to unmarshal recovery plan")
+	}
+	return plan, true, nil
+}
+
+// RemovePlan removes the staged plan from the store. It is not an error if
+// there is no staged plan.
+func (s PlanStore) RemovePlan() error {
+	err := s.fs.Remove(filepath.Join(s.path, planFileName))
+	if err != nil && !oserror.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove recovery plan")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanStore verifies that a staged plan survives a save and load round
+// trip, is replaced by subsequent saves and is gone once removed.
+func TestPlanStore(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+	ps := NewPlanStore(eng.GetAuxiliaryDir(), eng)
+
+	_, ok, err := ps.LoadPlan()
+	require.NoError(t, err)
+	require.False(t, ok, "no plan expected in empty store")
+
+	makePlan := func(rangeID roachpb.RangeID) loqrecoverypb.ReplicaUpdatePlan {
+		return loqrecoverypb.ReplicaUpdatePlan{
+			PlanID: uuid.MakeV4(),
+			Updates: []loqrecoverypb.ReplicaUpdate{{
+				RangeID:       rangeID,
+				StartKey:      loqrecoverypb.RecoveryKey(roachpb.RKey("a")),
+				OldReplicaID:  1,
+				NewReplica:    roachpb.ReplicaDescriptor{NodeID: 1, StoreID: 1, ReplicaID: 10},
+				NextReplicaID: 11,
+			}},
+		}
+	}
+
+	plan := makePlan(1)
+	require.NoError(t, ps.SavePlan(plan))
+	loaded, ok, err := ps.LoadPlan()
+	require.NoError(t, err)
+	require.True(t, ok, "saved plan not found")
+	require.Equal(t, plan, loaded)
+
+	plan = makePlan(2)
+	require.NoError(t, ps.SavePlan(plan))
+	loaded, ok, err = ps.LoadPlan()
+	require.NoError(t, err)
+	require.True(t, ok, "saved plan not found")
+	require.Equal(t, plan, loaded)
+
+	require.NoError(t, ps.RemovePlan())
+	_, ok, err = ps.LoadPlan()
+	require.NoError(t, err)
+	require.False(t, ok, "plan found after removal")
+	require.NoError(t, ps.RemovePlan(), "removing absent plan")
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package loqrecovery_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func adminClientForServer(
+	ctx context.Context, t *testing.T, tc *testcluster.TestCluster, idx int,
+) serverpb.AdminClient {
+	srv := tc.Server(idx)
+	conn, err := srv.RPCContext().GRPCDialNode(
+		srv.RPCAddr(), srv.NodeID(), rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err, "failed to dial admin server")
+	return serverpb.NewAdminClient(conn)
+}
+
+// TestRecoveryCollectReplicaInfo verifies that replica info is collected from
+// all nodes of a running cluster.
+func TestRecoveryCollectReplicaInfo(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationAuto,
+	})
+	defer tc.Stopper().Stop(ctx)
+	require.NoError(t, tc.WaitForFullReplication())
+
+	adm := adminClientForServer(ctx, t, tc, 0)
+	resp, err := adm.RecoveryCollectReplicaInfo(ctx, &serverpb.RecoveryCollectReplicaInfoRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Errors)
+
+	nodes := make(map[roachpb.NodeID]struct{})
+	for _, r := range resp.Replicas {
+		nodes[r.NodeID] = struct{}{}
+	}
+	require.Len(t, nodes, 3, "replicas of some nodes are missing")
+
+	// Collecting from a single node only returns its replicas.
+	resp, err = adm.RecoveryCollectReplicaInfo(ctx, &serverpb.RecoveryCollectReplicaInfoRequest{
+		NodeID: tc.Server(1).NodeID(),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Replicas)
+	for _, r := range resp.Replicas {
+		require.Equal(t, tc.Server(1).NodeID(), r.NodeID)
+	}
+}
+
+// TestRecoveryStagePlan verifies that a plan is staged on the nodes which
+// have replicas updated by it, and that a different staged plan is only
+// replaced when forced.
+func TestRecoveryStagePlan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	target := tc.Server(1)
+	makePlan := func() loqrecoverypb.ReplicaUpdatePlan {
+		return loqrecoverypb.ReplicaUpdatePlan{
+			PlanID: uuid.MakeV4(),
+			Updates: []loqrecoverypb.ReplicaUpdate{{
+				RangeID:      100,
+				StartKey:     loqrecoverypb.RecoveryKey(roachpb.RKey("a")),
+				OldReplicaID: 1,
+				NewReplica: roachpb.ReplicaDescriptor{
+					NodeID:    target.NodeID(),
+					StoreID:   target.GetFirstStoreID(),
+					ReplicaID: 10,
+				},
+				NextReplicaID: 11,
+			}},
+		}
+	}
+	loadStagedPlan := func() (loqrecoverypb.ReplicaUpdatePlan, bool) {
+		eng := target.Engines()[0]
+		plan, ok, err := loqrecovery.NewPlanStore(eng.GetAuxiliaryDir(), eng).LoadPlan()
+		require.NoError(t, err)
+		return plan, ok
+	}
+
+	adm := adminClientForServer(ctx, t, tc, 0)
+	plan := makePlan()
+	resp, err := adm.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{Plan: plan})
+	require.NoError(t, err)
+	require.Empty(t, resp.Errors)
+	require.Equal(t, []roachpb.NodeID{target.NodeID()}, resp.StagedNodeIDs)
+	staged, ok := loadStagedPlan()
+	require.True(t, ok, "plan was not staged")
+	require.Equal(t, plan.PlanID, staged.PlanID)
+
+	// Staging the same plan again is allowed.
+	resp, err = adm.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{Plan: plan})
+	require.NoError(t, err)
+	require.Empty(t, resp.Errors)
+
+	// A different plan is rejected unless forced.
+	otherPlan := makePlan()
+	resp, err = adm.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{Plan: otherPlan})
+	require.NoError(t, err)
+	require.Len(t, resp.Errors, 1)
+	require.Empty(t, resp.StagedNodeIDs)
+	staged, _ = loadStagedPlan()
+	require.Equal(t, plan.PlanID, staged.PlanID)
+
+	resp, err = adm.RecoveryStagePlan(ctx, &serverpb.RecoveryStagePlanRequest{
+		Plan: otherPlan, Force: true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Errors)
+	staged, _ = loadStagedPlan()
+	require.Equal(t, otherPlan.PlanID, staged.PlanID)
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/split"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/spanconfig"
@@ -180,9 +181,21 @@ func (rangeMergePurgatoryError) PurgatoryErrorMarker() {}
 
 var _ PurgatoryError = rangeMergePurgatoryError{}
 
+// rangeLoad is the load of a range that the mergeQueue considers, as measured
+// by the range's load based splitter.
+type rangeLoad struct {
+	qps   float64
+	qpsOK bool
+	// cpu is the CPU time spent on the range's requests, in nanoseconds per
+	// second. It is only measured when the load based splitting objective is
+	// cpu.
+	cpu   float64
+	cpuOK bool
+}
+
 func (mq *mergeQueue) requestRangeStats(
 	ctx context.Context, key roachpb.Key,
-) (desc *roachpb.RangeDescriptor, stats enginepb.MVCCStats, qps float64, qpsOK bool, err error) {
+) (desc *roachpb.RangeDescriptor, stats enginepb.MVCCStats, load rangeLoad, err error) {
 
 	var ba roachpb.BatchRequest
 	ba.Add(&roachpb.RangeStatsRequest{
@@ -191,20 +204,24 @@ func (mq *mergeQueue) requestRangeStats(
 
 	br, pErr := mq.db.NonTransactionalSender().Send(ctx, ba)
 	if pErr != nil {
-		return nil, enginepb.MVCCStats{}, 0, false, pErr.GoError()
+		return nil, enginepb.MVCCStats{}, rangeLoad{}, pErr.GoError()
 	}
 	res := br.Responses[0].GetInner().(*roachpb.RangeStatsResponse)
 
 	desc = &res.RangeInfo.Desc
 	stats = res.MVCCStats
 	if res.MaxQueriesPerSecondSet {
-		qps = res.MaxQueriesPerSecond
-		qpsOK = qps >= 0
+		load.qps = res.MaxQueriesPerSecond
+		load.qpsOK = load.qps >= 0
 	} else {
-		qps = res.DeprecatedLastQueriesPerSecond
-		qpsOK = true
+		load.qps = res.DeprecatedLastQueriesPerSecond
+		load.qpsOK = true
 	}
-	return desc, stats, qps, qpsOK, nil
+	if res.MaxCPUPerSecondSet {
+		load.cpu = res.MaxCPUPerSecond
+		load.cpuOK = load.cpu >= 0
+	}
+	return desc, stats, load, nil
 }
 
 func (mq *mergeQueue) process(
@@ -217,7 +234,9 @@ func (mq *mergeQueue) process(
 
 	lhsDesc := lhsRepl.Desc()
 	lhsStats := lhsRepl.GetMVCCStats()
-	lhsQPS, lhsQPSOK := lhsRepl.GetMaxSplitQPS()
+	var lhsLoad rangeLoad
+	lhsLoad.qps, lhsLoad.qpsOK = lhsRepl.GetMaxSplitQPS()
+	lhsLoad.cpu, lhsLoad.cpuOK = lhsRepl.GetMaxSplitCPU()
 	minBytes := lhsRepl.GetMinBytes()
 	if lhsStats.Total() >= minBytes {
 		log.VEventf(ctx, 2, "skipping merge: LHS meets minimum size threshold %d with %d bytes",
@@ -225,7 +244,7 @@ func (mq *mergeQueue) process(
 		return false, nil
 	}
 
-	rhsDesc, rhsStats, rhsQPS, rhsQPSOK, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
+	rhsDesc, rhsStats, rhsLoad, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
 	if err != nil {
 		return false, err
 	}
@@ -251,7 +270,8 @@ func (mq *mergeQueue) process(
 	mergedStats := lhsStats
 	mergedStats.Add(rhsStats)
 
-	var mergedQPS float64
+	var mergedQPS, mergedCPU float64
+	objective := splitByLoadObjective(&mq.store.ClusterSettings().SV)
 	if lhsRepl.SplitByLoadEnabled() {
 		// When load is a consideration for splits and, by extension, merges, the
 		// mergeQueue is fairly conservative. In an effort to avoid thrashing and to
@@ -262,16 +282,28 @@ func (mq *mergeQueue) process(
 		// merges, the mergeQueue will only consider a merge when it deems the
 		// maximum qps measurement from both sides to be sufficiently stable and
 		// reliable, meaning that it was a maximum measurement over some extended
-		// period of time.
-		if !lhsQPSOK {
+		// period of time. When the objective is cpu, the same applies to the
+		// maximum cpu measurement.
+		if !lhsLoad.qpsOK {
 			log.VEventf(ctx, 2, "skipping merge: LHS QPS measurement not yet reliable")
 			return false, nil
 		}
-		if !rhsQPSOK {
+		if !rhsLoad.qpsOK {
 			log.VEventf(ctx, 2, "skipping merge: RHS QPS measurement not yet reliable")
 			return false, nil
 		}
-		mergedQPS = lhsQPS + rhsQPS
+		mergedQPS = lhsLoad.qps + rhsLoad.qps
+		if objective == split.SplitCPU {
+			if !lhsLoad.cpuOK {
+				log.VEventf(ctx, 2, "skipping merge: LHS CPU measurement not yet reliable")
+				return false, nil
+			}
+			if !rhsLoad.cpuOK {
+				log.VEventf(ctx, 2, "skipping merge: RHS CPU measurement not yet reliable")
+				return false, nil
+			}
+			mergedCPU = lhsLoad.cpu + rhsLoad.cpu
+		}
 	}
 
 	// Check if the merged range would need to be split, if so, skip merge.
@@ -279,13 +311,19 @@ func (mq *mergeQueue) process(
 	// in a situation where we keep merging ranges that would be split soon after
 	// by a small increase in load.
 	conservativeLoadBasedSplitThreshold := 0.5 * lhsRepl.SplitByLoadQPSThreshold()
+	mergedLoad := mergedQPS
+	if objective == split.SplitCPU {
+		conservativeLoadBasedSplitThreshold =
+			0.5 * float64(SplitByLoadCPUThreshold.Get(&mq.store.ClusterSettings().SV).Nanoseconds())
+		mergedLoad = mergedCPU
+	}
 	shouldSplit, _ := shouldSplitRange(ctx, mergedDesc, mergedStats,
 		lhsRepl.GetMaxBytes(), lhsRepl.shouldBackpressureWrites(), confReader)
-	if shouldSplit || mergedQPS >= conservativeLoadBasedSplitThreshold {
+	if shouldSplit || mergedLoad >= conservativeLoadBasedSplitThreshold {
 		log.VEventf(ctx, 2,
 			"skipping merge to avoid thrashing: merged range %s may split "+
-				"(estimated size, estimated QPS: %d, %v)",
-			mergedDesc, mergedStats.Total(), mergedQPS)
+				"(estimated size, estimated QPS, estimated CPU nanos/s: %d, %v, %v)",
+			mergedDesc, mergedStats.Total(), mergedQPS, mergedCPU)
 		return false, nil
 	}
 
@@ -363,7 +401,7 @@ func (mq *mergeQueue) process(
 		}
 
 		// Refresh RHS descriptor.
-		rhsDesc, _, _, _, err = mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
+		rhsDesc, _, _, err = mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
 		if err != nil {
 			return false, err
 		}
@@ -423,6 +461,9 @@ func (mq *mergeQueue) process(
 	if mergedQPS != 0 {
 		lhsRepl.loadBasedSplitter.RecordMax(mq.store.Clock().PhysicalTime(), mergedQPS)
 	}
+	if mergedCPU != 0 {
+		lhsRepl.loadBasedSplitter.RecordMaxCPU(mq.store.Clock().PhysicalTime(), mergedCPU)
+	}
 	return true, nil
 }
 
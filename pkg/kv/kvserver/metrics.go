@@ -675,6 +675,12 @@ var (
 		Measurement: "Bytes",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRangeSnapshotSendIOOverloadPaced = metric.Metadata{
+		Name:        "range.snapshots.send-io-overload-paced",
+		Help:        "Number of times the rate limit of an outgoing snapshot was reduced due to IO overload on the recipient store",
+		Measurement: "Snapshots",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRangeSnapshotSendQueueLength = metric.Metadata{
 		Name:        "range.snapshots.send-queue",
 		Help:        "Number of snapshots queued to send",
@@ -1001,6 +1007,12 @@ difficult to meaningfully interpret this metric.`,
 		Measurement: "Log Entries",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRaftLogTruncationSnapshots = metric.Metadata{
+		Name:        "raftlog.truncated.snapshots",
+		Help:        "Number of Raft snapshots made necessary by Raft log truncations that cut off lagging followers",
+		Measurement: "Snapshots",
+		Unit:        metric.Unit_COUNT,
+	}
 
 	metaRaftFollowerPaused = metric.Metadata{
 		Name: "admission.raft.paused_replicas",
@@ -1478,6 +1490,44 @@ throttled they do count towards 'delay.total' and 'delay.enginebackpressure'.
 		Unit:        metric.Unit_NANOSECONDS,
 	}
 
+	// Disk bandwidth governor metrics.
+	metaDiskBandwidthForegroundBytes = metric.Metadata{
+		Name:        "storage.disk_bandwidth.foreground.bytes",
+		Help:        "Number of bytes written to the WAL on behalf of foreground traffic",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaDiskBandwidthCompactionBytes = metric.Metadata{
+		Name:        "storage.disk_bandwidth.compaction.bytes",
+		Help:        "Number of bytes written by compactions and charged against the background disk bandwidth limit",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaDiskBandwidthSnapshotBytes = metric.Metadata{
+		Name:        "storage.disk_bandwidth.snapshot.bytes",
+		Help:        "Number of bytes of received snapshots admitted by the disk bandwidth governor",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaDiskBandwidthSnapshotThrottled = metric.Metadata{
+		Name:        "storage.disk_bandwidth.snapshot.throttled",
+		Help:        "Amount by which writes of received snapshots were delayed by the disk bandwidth governor",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaDiskBandwidthIngestionBytes = metric.Metadata{
+		Name:        "storage.disk_bandwidth.ingestion.bytes",
+		Help:        "Number of bytes of AddSSTable requests admitted by the disk bandwidth governor",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaDiskBandwidthIngestionThrottled = metric.Metadata{
+		Name:        "storage.disk_bandwidth.ingestion.throttled",
+		Help:        "Amount by which AddSSTable requests were delayed by the disk bandwidth governor",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+
 	// Export request counter.
 	metaExportEvalTotalDelay = metric.Metadata{
 		Name:        "exportrequest.delay.total",
@@ -1733,6 +1783,7 @@ type StoreMetrics struct {
 	RangeSnapshotRecoverySentBytes               *metric.Counter
 	RangeSnapshotRebalancingRcvdBytes            *metric.Counter
 	RangeSnapshotRebalancingSentBytes            *metric.Counter
+	RangeSnapshotSendIOOverloadPaced             *metric.Counter
 
 	// Range snapshot queue metrics.
 	RangeSnapshotSendQueueLength     *metric.Gauge
@@ -1767,6 +1818,7 @@ type StoreMetrics struct {
 	// Raft log metrics.
 	RaftLogFollowerBehindCount *metric.Gauge
 	RaftLogTruncated           *metric.Counter
+	RaftLogTruncationSnapshots *metric.Counter
 
 	RaftPausedFollowerCount       *metric.Gauge
 	RaftPausedFollowerDroppedMsgs *metric.Counter
@@ -1854,6 +1906,14 @@ type StoreMetrics struct {
 	AddSSTableProposalTotalDelay  *metric.Counter
 	AddSSTableProposalEngineDelay *metric.Counter
 
+	// Disk bandwidth stats, per class of writes.
+	DiskBandwidthForegroundBytes    *metric.Counter
+	DiskBandwidthCompactionBytes    *metric.Counter
+	DiskBandwidthSnapshotBytes      *metric.Counter
+	DiskBandwidthSnapshotThrottled  *metric.Counter
+	DiskBandwidthIngestionBytes     *metric.Counter
+	DiskBandwidthIngestionThrottled *metric.Counter
+
 	// Export request stats.
 	ExportRequestProposalTotalDelay *metric.Counter
 
@@ -2233,6 +2293,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RangeSnapshotRecoverySentBytes:               metric.NewCounter(metaRangeSnapshotRecoverySentBytes),
 		RangeSnapshotRebalancingRcvdBytes:            metric.NewCounter(metaRangeSnapshotRebalancingRcvdBytes),
 		RangeSnapshotRebalancingSentBytes:            metric.NewCounter(metaRangeSnapshotRebalancingSentBytes),
+		RangeSnapshotSendIOOverloadPaced:             metric.NewCounter(metaRangeSnapshotSendIOOverloadPaced),
 		RangeSnapshotSendQueueLength:                 metric.NewGauge(metaRangeSnapshotSendQueueLength),
 		RangeSnapshotRecvQueueLength:                 metric.NewGauge(metaRangeSnapshotRecvQueueLength),
 		RangeSnapshotSendInProgress:                  metric.NewGauge(metaRangeSnapshotSendInProgress),
@@ -2281,6 +2342,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		// Raft log metrics.
 		RaftLogFollowerBehindCount: metric.NewGauge(metaRaftLogFollowerBehindCount),
 		RaftLogTruncated:           metric.NewCounter(metaRaftLogTruncated),
+		RaftLogTruncationSnapshots: metric.NewCounter(metaRaftLogTruncationSnapshots),
 
 		RaftPausedFollowerCount:       metric.NewGauge(metaRaftFollowerPaused),
 		RaftPausedFollowerDroppedMsgs: metric.NewCounter(metaRaftPausedFollowerDroppedMsgs),
@@ -2367,6 +2429,14 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		AddSSTableProposalTotalDelay:  metric.NewCounter(metaAddSSTableEvalTotalDelay),
 		AddSSTableProposalEngineDelay: metric.NewCounter(metaAddSSTableEvalEngineDelay),
 
+		// Disk bandwidth governor.
+		DiskBandwidthForegroundBytes:    metric.NewCounter(metaDiskBandwidthForegroundBytes),
+		DiskBandwidthCompactionBytes:    metric.NewCounter(metaDiskBandwidthCompactionBytes),
+		DiskBandwidthSnapshotBytes:      metric.NewCounter(metaDiskBandwidthSnapshotBytes),
+		DiskBandwidthSnapshotThrottled:  metric.NewCounter(metaDiskBandwidthSnapshotThrottled),
+		DiskBandwidthIngestionBytes:     metric.NewCounter(metaDiskBandwidthIngestionBytes),
+		DiskBandwidthIngestionThrottled: metric.NewCounter(metaDiskBandwidthIngestionThrottled),
+
 		// ExportRequest proposal.
 		ExportRequestProposalTotalDelay: metric.NewCounter(metaExportEvalTotalDelay),
 
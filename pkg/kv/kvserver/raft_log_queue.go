@@ -138,6 +138,11 @@ const (
 	// Allow a limited number of Raft log truncations to be processed
 	// concurrently.
 	raftLogQueueConcurrency = 4
+	// raftLogQueueLaggingFollowerMaxSizeFactor bounds the size of the Raft log
+	// that is retained for followers that are not recently active, but cheaper
+	// to catch up through the log than through a snapshot, as a multiple of the
+	// size above which the log is considered too large.
+	raftLogQueueLaggingFollowerMaxSizeFactor = 2
 )
 
 // raftLogQueue manages a queue of replicas slated to have their raft logs
@@ -199,9 +204,11 @@ func newRaftLogQueue(store *Store, db *kv.DB) *raftLogQueue {
 // truncation strategy bounds the absolute size of the log on all followers.
 //
 // Exceptions are made for replicas for which information is missing ("probing
-// state") as long as they are known to have been online recently, and for
-// in-flight snapshots which are not adequately reflected in the Raft status and
-// would otherwise be cut off with regularity. Probing live followers should
+// state") as long as they are known to have been online recently, for
+// followers that aren't recently active but are cheaper to catch up via the log
+// than via a snapshot of the range, and for in-flight snapshots which are not
+// adequately reflected in the Raft status and would otherwise be cut off with
+// regularity. Probing live followers should
 // only remain in this state for a short moment and so we deny a log truncation
 // outright (as there's no safe index to truncate to); for snapshots, we can
 // still truncate, but not past the snapshot's index.
@@ -260,6 +267,11 @@ func newTruncateDecision(ctx context.Context, r *Replica) (truncateDecision, err
 	const anyRecipientStore roachpb.StoreID = 0
 	pendingSnapshotIndex := r.getSnapshotLogTruncationConstraintsRLocked(anyRecipientStore)
 	lastIndex := r.mu.lastIndex
+	// A snapshot sends all the replicated keys of the range uncompressed, so
+	// the logical size of the user and system keys and values is used as an
+	// estimate of its size. This doesn't account for the encoding overhead of
+	// the snapshot batches, so it underestimates the snapshot slightly.
+	snapshotSize := r.mu.state.Stats.Total() + r.mu.state.Stats.SysBytes
 	// NB: raftLogSize above adjusts for pending truncations that have already
 	// been successfully replicated via raft, but logSizeTrusted does not see if
 	// those pending truncations would cause a transition from trusted =>
@@ -309,6 +321,7 @@ func newTruncateDecision(ctx context.Context, r *Replica) (truncateDecision, err
 		FirstIndex:           firstIndex,
 		LastIndex:            lastIndex,
 		PendingSnapshotIndex: pendingSnapshotIndex,
+		SnapshotSize:         snapshotSize,
 	}
 
 	decision := computeTruncateDecision(input)
@@ -345,6 +358,7 @@ const (
 	truncatableIndexChosenViaFollowers       = "followers"
 	truncatableIndexChosenViaProbingFollower = "probing follower"
 	truncatableIndexChosenViaPendingSnap     = "pending snapshot"
+	truncatableIndexChosenViaSnapshotCost    = "snapshot cost"
 	truncatableIndexChosenViaFirstIndex      = "first index"
 	truncatableIndexChosenViaLastIndex       = "last index"
 )
@@ -366,12 +380,51 @@ type truncateDecisionInput struct {
 	LogSizeTrusted        bool // false when LogSize might be off
 	FirstIndex, LastIndex uint64
 	PendingSnapshotIndex  uint64
+	// SnapshotSize is the estimated number of bytes that a snapshot of the
+	// range would send to a follower that was cut off by a truncation. It is
+	// derived from the range's MVCC stats, not from the size of a snapshot
+	// that was actually sent.
+	SnapshotSize int64
 }
 
 func (input truncateDecisionInput) LogTooLarge() bool {
 	return input.LogSize > input.MaxLogSize
 }
 
+// followerLagBytes estimates the number of bytes of log entries that a follower
+// whose raft progress has the given match index needs to catch up. Followers
+// acknowledge appended entries only after syncing them to disk (see
+// handleRaftReadyRaftMuLocked), so the match index is also the follower's
+// durable index; entries past it are counted as missing even if the follower
+// has received them. The estimate assumes that all the entries in the log have
+// the same size.
+func (input truncateDecisionInput) followerLagBytes(match uint64) int64 {
+	if input.FirstIndex > input.LastIndex || match >= input.LastIndex {
+		return 0
+	}
+	numEntries := input.LastIndex - input.FirstIndex + 1
+	behind := input.LastIndex - match
+	if behind > numEntries {
+		behind = numEntries
+	}
+	return int64(float64(input.LogSize) * float64(behind) / float64(numEntries))
+}
+
+// catchUpCheaperThanSnapshot returns true if a follower whose raft progress has
+// the given match index is cheaper to catch up through the log than through a
+// snapshot, and the log hasn't grown too large to keep it
+// around for that follower. It returns false if the follower already needs a
+// snapshot.
+func (input truncateDecisionInput) catchUpCheaperThanSnapshot(match uint64) bool {
+	if match+1 < input.FirstIndex {
+		return false
+	}
+	if input.LogSize > raftLogQueueLaggingFollowerMaxSizeFactor*input.MaxLogSize {
+		return false
+	}
+	return input.followerLagBytes(match) < input.SnapshotSize
+}
+
 // truncateDecision describes a truncation decision.
 // Beware: when extending this struct, be sure to adjust .String()
 // so that it is guaranteed to not contain any PII or confidential
@@ -544,6 +597,14 @@ func computeTruncateDecision(input truncateDecisionInput) truncateDecision {
 		// truncate it off as long as the raft log is not too large.
 		if !input.LogTooLarge() {
 			decision.ProtectIndex(progress.Match, truncatableIndexChosenViaFollowers)
+		} else if input.catchUpCheaperThanSnapshot(progress.Match) {
+			// Even if the log is too large, truncating it off would cost more than
+			// catching it up: the snapshot that it would need is larger than the
+			// entries it is missing. This is common for large ranges with slow
+			// followers. The log is kept around for the follower only up to a
+			// bound, so that a follower that is gone doesn't let it grow without
+			// limit.
+			decision.ProtectIndex(progress.Match, truncatableIndexChosenViaSnapshotCost)
 		}
 
 		// Otherwise, we let it truncate to the committed index.
@@ -709,7 +770,8 @@ func (rlq *raftLogQueue) process(
 		return false, nil
 	}
 
-	if n := decision.NumNewRaftSnapshots(); log.V(1) || n > 0 && rlq.logSnapshots.ShouldProcess(timeutil.Now()) {
+	numSnapshots := decision.NumNewRaftSnapshots()
+	if log.V(1) || numSnapshots > 0 && rlq.logSnapshots.ShouldProcess(timeutil.Now()) {
 		log.Infof(ctx, "%v", redact.Safe(decision.String()))
 	} else {
 		log.VEventf(ctx, 1, "%v", redact.Safe(decision.String()))
@@ -726,6 +788,7 @@ func (rlq *raftLogQueue) process(
 		return false, err
 	}
 	r.store.metrics.RaftLogTruncated.Inc(int64(decision.NumTruncatableIndexes()))
+	r.store.metrics.RaftLogTruncationSnapshots.Inc(int64(numSnapshots))
 	return true, nil
 }
 
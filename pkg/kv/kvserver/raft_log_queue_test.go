@@ -294,6 +294,75 @@ func TestComputeTruncateDecisionProgressStatusProbe(t *testing.T) {
 	})
 }
 
+// TestComputeTruncateDecisionSnapshotCost verifies that a follower that is not
+// recently active is not cut off by the truncation of a log that is too large
+// if it is cheaper to catch up through the log than through a snapshot, as
+// long as the log isn't much larger than the size limit.
+func TestComputeTruncateDecisionSnapshotCost(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testCases := []struct {
+		logSize      int64
+		snapshotSize int64
+		match        uint64
+		exp          string
+	}{
+		{
+			// The follower is missing ~374 bytes of log entries, which is cheaper
+			// than the snapshot.
+			1500, 10000, 400,
+			"should truncate: true [truncate 300 entries to first index 400 (chosen via: snapshot cost); log too large (1.5 KiB > 1.0 KiB)]",
+		},
+		{
+			// The follower is slow, but has durably appended the log up to index
+			// 150. The ~1.3 KiB of log entries that it is missing are cheaper than
+			// the snapshot, so its log is kept.
+			1500, 10000, 150,
+			"should truncate: false [truncate 50 entries to first index 150 (chosen via: snapshot cost); log too large (1.5 KiB > 1.0 KiB)]",
+		},
+		{
+			// The snapshot is cheaper.
+			1500, 100, 400,
+			"should truncate: true [truncate 400 entries to first index 500 (chosen via: last index); log too large (1.5 KiB > 1.0 KiB); implies 1 Raft snapshot]",
+		},
+		{
+			// The log is too large to be kept around for the follower.
+			4096, 1 << 20, 400,
+			"should truncate: true [truncate 400 entries to first index 500 (chosen via: last index); log too large (4.0 KiB > 1.0 KiB); implies 1 Raft snapshot]",
+		},
+	}
+	for i, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			status := raft.Status{
+				Progress: make(map[uint64]tracker.Progress),
+			}
+			status.Commit = 500
+			for j, match := range []uint64{500, 500, c.match} {
+				status.Progress[uint64(j)] = tracker.Progress{
+					Match:        match,
+					Next:         match + 1,
+					RecentActive: match == 500,
+					State:        tracker.StateReplicate,
+				}
+			}
+			input := truncateDecisionInput{
+				RaftStatus:     status,
+				LogSize:        c.logSize,
+				MaxLogSize:     1024,
+				LogSizeTrusted: true,
+				FirstIndex:     100,
+				LastIndex:      500,
+				SnapshotSize:   c.snapshotSize,
+			}
+			decision := computeTruncateDecision(input)
+			if s := decision.String(); s != c.exp {
+				t.Errorf("%d: expected %q, got %q", i, c.exp, s)
+			}
+		})
+	}
+}
+
 func TestTruncateDecisionZeroValue(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
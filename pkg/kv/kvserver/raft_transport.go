@@ -623,6 +623,7 @@ func (t *RaftTransport) SendSnapshot(
 	newBatch func() storage.Batch,
 	sent func(),
 	recordBytesSent snapshotRecordMetrics,
+	recordIOOverloadPaced func(),
 ) error {
 	nodeID := header.RaftMessageRequest.ToReplica.NodeID
 
@@ -641,7 +642,7 @@ func (t *RaftTransport) SendSnapshot(
 			log.Warningf(ctx, "failed to close snapshot stream: %+v", err)
 		}
 	}()
-	return sendSnapshot(ctx, t.st, t.tracer, stream, storePool, header, snap, newBatch, sent, recordBytesSent)
+	return sendSnapshot(ctx, t.st, t.tracer, stream, storePool, header, snap, newBatch, sent, recordBytesSent, recordIOOverloadPaced)
 }
 
 // DelegateSnapshot creates a rpc stream between the leaseholder and the
@@ -12,6 +12,7 @@ package rangefeed
 
 import (
 	"bytes"
+	"crypto/sha256"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
@@ -65,21 +66,23 @@ type CatchUpIterator struct {
 }
 
 // NewCatchUpIterator returns a CatchUpIterator for the given Reader over the
-// given key/time span. startTime is exclusive.
+// given key/time span. startTime is exclusive. If useTBI is true, the iterator
+// uses a time-bound iterator to skip over data written at or before startTime.
 //
 // NB: startTime is exclusive, i.e. the first possible event will be emitted at
 // Timestamp.Next().
 func NewCatchUpIterator(
-	reader storage.Reader, span roachpb.Span, startTime hlc.Timestamp, closer func(),
+	reader storage.Reader, span roachpb.Span, startTime hlc.Timestamp, closer func(), useTBI bool,
 ) *CatchUpIterator {
 	return &CatchUpIterator{
 		simpleCatchupIter: storage.NewMVCCIncrementalIterator(reader,
 			storage.MVCCIncrementalIterOptions{
-				KeyTypes:  storage.IterKeyTypePointsAndRanges,
-				StartKey:  span.Key,
-				EndKey:    span.EndKey,
-				StartTime: startTime,
-				EndTime:   hlc.MaxTimestamp,
+				KeyTypes:                             storage.IterKeyTypePointsAndRanges,
+				StartKey:                             span.Key,
+				EndKey:                               span.EndKey,
+				StartTime:                            startTime,
+				EndTime:                              hlc.MaxTimestamp,
+				DisableTimeBoundIteratorOptimization: !useTBI,
 				// We want to emit intents rather than error
 				// (the default behavior) so that we can skip
 				// over the provisional values during
@@ -324,3 +327,50 @@ func (i *CatchUpIterator) CatchUpScan(outputFn outputEventFn, withDiff bool) err
 	// Output events for the last key encountered.
 	return outputEvents()
 }
+
+// VerifyCatchUpScan runs a catch-up scan over the given key/time span both with
+// and without the time-bound iterator optimization, and returns an error if
+// the two scans do not emit the same events. The events are fingerprinted
+// rather than buffered, so the memory used by the verification is independent
+// of the size of the scan. The reader must provide a consistent view of the
+// data across both scans, e.g. by being an engine snapshot.
+func VerifyCatchUpScan(
+	reader storage.Reader, span roachpb.Span, startTime hlc.Timestamp, withDiff bool,
+) error {
+	type fingerprint struct {
+		events int
+		hash   [sha256.Size]byte
+	}
+	scan := func(useTBI bool) (fingerprint, error) {
+		iter := NewCatchUpIterator(reader, span, startTime, nil /* closer */, useTBI)
+		defer iter.Close()
+		h := sha256.New()
+		var fp fingerprint
+		err := iter.CatchUpScan(func(e *roachpb.RangeFeedEvent) error {
+			b, err := protoutil.Marshal(e)
+			if err != nil {
+				return err
+			}
+			fp.events++
+			_, err = h.Write(b)
+			return err
+		}, withDiff)
+		h.Sum(fp.hash[:0])
+		return fp, err
+	}
+	withTBI, err := scan(true /* useTBI */)
+	if err != nil {
+		return errors.Wrap(err, "catch-up scan with time-bound iterator")
+	}
+	withoutTBI, err := scan(false /* useTBI */)
+	if err != nil {
+		return errors.Wrap(err, "catch-up scan without time-bound iterator")
+	}
+	if withTBI != withoutTBI {
+		return errors.AssertionFailedf(
+			"catch-up scan of %s above %s with time-bound iterator emitted %d events (fingerprint %x), "+
+				"but %d events (fingerprint %x) without it",
+			span, startTime, withTBI.events, withTBI.hash, withoutTBI.events, withoutTBI.hash)
+	}
+	return nil
+}
@@ -48,7 +48,7 @@ func runCatchUpBenchmark(b *testing.B, emk engineMaker, opts benchOptions) (numE
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		func() {
-			iter := rangefeed.NewCatchUpIterator(eng, span, opts.ts, nil)
+			iter := rangefeed.NewCatchUpIterator(eng, span, opts.ts, nil, true /* useTBI */)
 			defer iter.Close()
 			counter := 0
 			err := iter.CatchUpScan(func(*roachpb.RangeFeedEvent) error {
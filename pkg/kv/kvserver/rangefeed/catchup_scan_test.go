@@ -12,6 +12,7 @@ package rangefeed
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -106,7 +107,7 @@ func TestCatchupScan(t *testing.T) {
 	}
 	testutils.RunTrueAndFalse(t, "withDiff", func(t *testing.T, withDiff bool) {
 		span := roachpb.Span{Key: testKey1, EndKey: roachpb.KeyMax}
-		iter := NewCatchUpIterator(eng, span, ts1, nil)
+		iter := NewCatchUpIterator(eng, span, ts1, nil, true /* useTBI */)
 		defer iter.Close()
 		var events []roachpb.RangeFeedValue
 		// ts1 here is exclusive, so we do not want the versions at ts1.
@@ -149,7 +150,7 @@ func TestCatchupScanInlineError(t *testing.T) {
 
 	// Run a catchup scan across the span and watch it error.
 	span := roachpb.Span{Key: keys.LocalMax, EndKey: keys.MaxKey}
-	iter := NewCatchUpIterator(eng, span, hlc.Timestamp{}, nil)
+	iter := NewCatchUpIterator(eng, span, hlc.Timestamp{}, nil, true /* useTBI */)
 	defer iter.Close()
 
 	err := iter.CatchUpScan(nil, false)
@@ -189,7 +190,7 @@ func TestCatchupScanSeesOldIntent(t *testing.T) {
 
 	// Run a catchup scan across the span and watch it succeed.
 	span := roachpb.Span{Key: keys.LocalMax, EndKey: keys.MaxKey}
-	iter := NewCatchUpIterator(eng, span, tsCutoff, nil)
+	iter := NewCatchUpIterator(eng, span, tsCutoff, nil, true /* useTBI */)
 	defer iter.Close()
 
 	keys := map[string]struct{}{}
@@ -202,3 +203,39 @@ func TestCatchupScanSeesOldIntent(t *testing.T) {
 		"e": {},
 	}, keys)
 }
+
+func TestVerifyCatchUpScan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMemForTesting()
+	defer eng.Close()
+
+	// Write old versions of all keys and flush them into an SST, so that the
+	// time-bound iterator can skip it, and then write newer versions of some
+	// of the keys, including an intent.
+	tsCutoff := hlc.Timestamp{WallTime: 1000}
+	for i := 0; i < 10; i++ {
+		key := roachpb.Key(fmt.Sprintf("key%d", i))
+		require.NoError(t, storage.MVCCPut(ctx, eng, nil, key, tsCutoff.Add(-10, 0),
+			hlc.ClockTimestamp{}, roachpb.MakeValueFromString("old"), nil))
+	}
+	require.NoError(t, eng.Flush())
+	for i := 0; i < 10; i += 3 {
+		key := roachpb.Key(fmt.Sprintf("key%d", i))
+		require.NoError(t, storage.MVCCPut(ctx, eng, nil, key, tsCutoff.Add(10, 0),
+			hlc.ClockTimestamp{}, roachpb.MakeValueFromString("new"), nil))
+	}
+	txn := roachpb.MakeTransaction("foo", roachpb.Key("key5"), roachpb.NormalUserPriority,
+		tsCutoff.Add(20, 0), 100, 0)
+	require.NoError(t, storage.MVCCPut(ctx, eng, nil, roachpb.Key("key5"), txn.ReadTimestamp,
+		hlc.ClockTimestamp{}, roachpb.MakeValueFromString("intent"), &txn))
+
+	snap := eng.NewSnapshot()
+	defer snap.Close()
+	span := roachpb.Span{Key: keys.LocalMax, EndKey: keys.MaxKey}
+	testutils.RunTrueAndFalse(t, "withDiff", func(t *testing.T, withDiff bool) {
+		require.NoError(t, VerifyCatchUpScan(snap, span, tsCutoff, withDiff))
+	})
+}
@@ -31,6 +31,12 @@ var (
 		Measurement: "Events",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRangeFeedCatchUpScanVerificationFailures = metric.Metadata{
+		Name:        "kv.rangefeed.catchup_scan_verification_failures",
+		Help:        "Number of RangeFeed catchup scans whose results differed with and without a time-bound iterator",
+		Measurement: "Events",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRangeFeedBudgetBlocked = metric.Metadata{
 		Name:        "kv.rangefeed.budget_allocation_blocked",
 		Help:        "Number of times RangeFeed waited for budget availability",
@@ -41,9 +47,10 @@ var (
 
 // Metrics are for production monitoring of RangeFeeds.
 type Metrics struct {
-	RangeFeedCatchUpScanNanos *metric.Counter
-	RangeFeedBudgetExhausted  *metric.Counter
-	RangeFeedBudgetBlocked    *metric.Counter
+	RangeFeedCatchUpScanNanos                *metric.Counter
+	RangeFeedCatchUpScanVerificationFailures *metric.Counter
+	RangeFeedBudgetExhausted                 *metric.Counter
+	RangeFeedBudgetBlocked                   *metric.Counter
 
 	RangeFeedSlowClosedTimestampLogN  log.EveryN
 	RangeFeedSlowClosedTimestampNudge singleflight.Group
@@ -60,11 +67,12 @@ func (*Metrics) MetricStruct() {}
 // NewMetrics makes the metrics for RangeFeeds monitoring.
 func NewMetrics() *Metrics {
 	return &Metrics{
-		RangeFeedCatchUpScanNanos:            metric.NewCounter(metaRangeFeedCatchUpScanNanos),
-		RangeFeedBudgetExhausted:             metric.NewCounter(metaRangeFeedExhausted),
-		RangeFeedBudgetBlocked:               metric.NewCounter(metaRangeFeedBudgetBlocked),
-		RangeFeedSlowClosedTimestampLogN:     log.Every(5 * time.Second),
-		RangeFeedSlowClosedTimestampNudgeSem: make(chan struct{}, 1024),
+		RangeFeedCatchUpScanNanos:                metric.NewCounter(metaRangeFeedCatchUpScanNanos),
+		RangeFeedCatchUpScanVerificationFailures: metric.NewCounter(metaRangeFeedCatchUpScanVerificationFailures),
+		RangeFeedBudgetExhausted:                 metric.NewCounter(metaRangeFeedExhausted),
+		RangeFeedBudgetBlocked:                   metric.NewCounter(metaRangeFeedBudgetBlocked),
+		RangeFeedSlowClosedTimestampLogN:         log.Every(5 * time.Second),
+		RangeFeedSlowClosedTimestampNudgeSem:     make(chan struct{}, 1024),
 	}
 }
 
@@ -450,6 +450,7 @@ func (p *Processor) Register(
 	startTS hlc.Timestamp,
 	catchUpIterConstructor CatchUpIteratorConstructor,
 	withDiff bool,
+	familyIDs []uint32,
 	stream Stream,
 	errC chan<- *roachpb.Error,
 ) (bool, *Filter) {
@@ -459,7 +460,7 @@ func (p *Processor) Register(
 	p.syncEventC()
 
 	r := newRegistration(
-		span.AsRawSpanWithNoLocals(), startTS, catchUpIterConstructor, withDiff,
+		span.AsRawSpanWithNoLocals(), startTS, catchUpIterConstructor, withDiff, familyIDs,
 		p.Config.EventChanCap, p.Metrics, stream, errC,
 	)
 	select {
@@ -208,6 +208,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		r1ErrC,
 	)
@@ -329,6 +330,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,  /* catchUpIter */
 		true, /* withDiff */
+		nil,  /* familyIDs */
 		r2Stream,
 		r2ErrC,
 	)
@@ -412,6 +414,7 @@ func TestProcessorBasic(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r3Stream,
 		r3ErrC,
 	)
@@ -437,7 +440,7 @@ func TestNilProcessor(t *testing.T) {
 	stopper := stop.NewStopper()
 	defer stopper.Stop(ctx)
 	require.Panics(t, func() { _ = p.Start(stopper, nil) })
-	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, nil, nil) })
+	require.Panics(t, func() { p.Register(roachpb.RSpan{}, hlc.Timestamp{}, nil, false, nil, nil, nil) })
 }
 
 func TestProcessorSlowConsumer(t *testing.T) {
@@ -454,6 +457,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		r1ErrC,
 	)
@@ -464,6 +468,7 @@ func TestProcessorSlowConsumer(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r2Stream,
 		r2ErrC,
 	)
@@ -566,6 +571,7 @@ func TestProcessorMemoryBudgetExceeded(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		r1ErrC,
 	)
@@ -635,6 +641,7 @@ func TestProcessorMemoryBudgetReleased(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		r1ErrC,
 	)
@@ -708,6 +715,7 @@ func TestProcessorInitializeResolvedTimestamp(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		make(chan *roachpb.Error, 1),
 	)
@@ -951,7 +959,7 @@ func TestProcessorConcurrentStop(t *testing.T) {
 			runtime.Gosched()
 			s := newTestStream()
 			errC := make(chan<- *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, false, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, nil, s, errC)
 		}()
 		go func() {
 			defer wg.Done()
@@ -1020,7 +1028,7 @@ func TestProcessorRegistrationObservesOnlyNewEvents(t *testing.T) {
 			s := newTestStream()
 			regs[s] = firstIdx
 			errC := make(chan *roachpb.Error, 1)
-			p.Register(p.Span, hlc.Timestamp{}, nil, false, s, errC)
+			p.Register(p.Span, hlc.Timestamp{}, nil, false, nil, s, errC)
 			regDone <- struct{}{}
 		}
 	}()
@@ -1110,6 +1118,7 @@ func TestBudgetReleaseOnProcessorStop(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		rStream,
 		rErrC,
 	)
@@ -1198,6 +1207,7 @@ func TestBudgetReleaseOnLastStreamError(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		rStream,
 		rErrC,
 	)
@@ -1275,6 +1285,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		r1ErrC,
 	)
@@ -1286,6 +1297,7 @@ func TestBudgetReleaseOnOneStreamError(t *testing.T) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r2Stream,
 		r2ErrC,
 	)
@@ -1443,6 +1455,7 @@ func BenchmarkProcessorWithBudget(b *testing.B) {
 		hlc.Timestamp{WallTime: 1},
 		nil,   /* catchUpIter */
 		false, /* withDiff */
+		nil,   /* familyIDs */
 		r1Stream,
 		r1ErrC,
 	)
@@ -16,6 +16,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
@@ -77,7 +78,10 @@ type registration struct {
 	span             roachpb.Span
 	catchUpTimestamp hlc.Timestamp // exclusive
 	withDiff         bool
-	metrics          *Metrics
+	// familyIDs, if non-empty, restricts the value events delivered to the
+	// registration to those on keys in one of the listed column families.
+	familyIDs []uint32
+	metrics   *Metrics
 
 	// catchUpIterConstructor is used to construct the catchUpIter if necessary.
 	// The reason this constructor is plumbed down is to make sure that the
@@ -122,6 +126,7 @@ func newRegistration(
 	startTS hlc.Timestamp,
 	catchUpIterConstructor CatchUpIteratorConstructor,
 	withDiff bool,
+	familyIDs []uint32,
 	bufferSz int,
 	metrics *Metrics,
 	stream Stream,
@@ -132,6 +137,7 @@ func newRegistration(
 		catchUpTimestamp:       startTS,
 		catchUpIterConstructor: catchUpIterConstructor,
 		withDiff:               withDiff,
+		familyIDs:              familyIDs,
 		metrics:                metrics,
 		stream:                 stream,
 		errC:                   errC,
@@ -151,6 +157,9 @@ func (r *registration) publish(
 	ctx context.Context, event *roachpb.RangeFeedEvent, allocation *SharedBudgetAllocation,
 ) {
 	r.validateEvent(event)
+	if !r.matchesFamilies(event) {
+		return
+	}
 	e := getPooledSharedEvent(sharedEvent{event: r.maybeStripEvent(event), allocation: allocation})
 
 	r.mu.Lock()
@@ -210,6 +219,38 @@ func (r *registration) validateEvent(event *roachpb.RangeFeedEvent) {
 	}
 }
 
+// matchesFamilies returns whether the event passes the column family filter of
+// the registration. Only RangeFeedValue events on SQL row keys are subject to
+// the filter; everything else, including keys outside of the table data of a
+// tenant and keys whose family cannot be decoded, is let through.
+func (r *registration) matchesFamilies(event *roachpb.RangeFeedEvent) bool {
+	if len(r.familyIDs) == 0 {
+		return true
+	}
+	t, ok := event.GetValue().(*roachpb.RangeFeedValue)
+	if !ok {
+		return true
+	}
+	sqlKey, _, err := keys.DecodeTenantPrefix(t.Key)
+	if err != nil {
+		return true
+	}
+	if roachpb.Key(sqlKey).Compare(keys.TableDataMin) < 0 {
+		// Not a table key, e.g. a system key of the system tenant.
+		return true
+	}
+	familyID, err := keys.DecodeFamilyKey(t.Key)
+	if err != nil {
+		return true
+	}
+	for _, id := range r.familyIDs {
+		if id == familyID {
+			return true
+		}
+	}
+	return false
+}
+
 // maybeStripEvent determines whether the event contains excess information not
 // applicable to the current registration. If so, it makes a copy of the event
 // and strips the incompatible information to match only what the registration
@@ -383,7 +424,16 @@ func (r *registration) maybeRunCatchUpScan() error {
 		r.metrics.RangeFeedCatchUpScanNanos.Inc(timeutil.Since(start).Nanoseconds())
 	}()
 
-	return catchUpIter.CatchUpScan(r.stream.Send, r.withDiff)
+	outputFn := r.stream.Send
+	if len(r.familyIDs) > 0 {
+		outputFn = func(event *roachpb.RangeFeedEvent) error {
+			if !r.matchesFamilies(event) {
+				return nil
+			}
+			return r.stream.Send(event)
+		}
+	}
+	return catchUpIter.CatchUpScan(outputFn, r.withDiff)
 }
 
 // ID implements interval.Interface.
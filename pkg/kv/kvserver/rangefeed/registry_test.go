@@ -15,7 +15,7 @@ import (
 	"fmt"
 	"testing"
 
-	_ "github.com/cockroachdb/cockroach/pkg/keys" // hook up pretty printer
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -119,6 +119,7 @@ func newTestRegistration(
 		ts,
 		makeCatchUpIteratorConstructor(catchup),
 		withDiff,
+		nil, /* familyIDs */
 		5,
 		NewMetrics(),
 		s,
@@ -557,6 +558,46 @@ func TestRegistryPublishBeneathStartTimestamp(t *testing.T) {
 	<-r.errC
 }
 
+func TestRegistrationFamilyFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	rowKey := keys.SystemSQLCodec.IndexPrefix(100, 1)
+	fam0Key := keys.MakeFamilyKey(rowKey.Clone(), 0)
+	fam1Key := keys.MakeFamilyKey(rowKey.Clone(), 1)
+	fam2Key := keys.MakeFamilyKey(rowKey.Clone(), 2)
+	span := roachpb.Span{Key: rowKey, EndKey: rowKey.PrefixEnd()}
+
+	val := roachpb.Value{RawBytes: []byte("val"), Timestamp: hlc.Timestamp{WallTime: 1}}
+	makeValueEvent := func(key roachpb.Key) *roachpb.RangeFeedEvent {
+		ev := new(roachpb.RangeFeedEvent)
+		ev.MustSetValue(&roachpb.RangeFeedValue{Key: key, Value: val})
+		return ev
+	}
+	ev0, ev1, ev2 := makeValueEvent(fam0Key), makeValueEvent(fam1Key), makeValueEvent(fam2Key)
+	// Keys that don't belong to a column family are never filtered out.
+	evNoFamily := makeValueEvent(rowKey)
+	// Neither are keys below the table data, even if they happen to decode as
+	// a key in family 1.
+	evNonTable := makeValueEvent(roachpb.Key("\x87\x89\x8a\x89"))
+	evCheckpoint := new(roachpb.RangeFeedEvent)
+	evCheckpoint.MustSetValue(&roachpb.RangeFeedCheckpoint{
+		Span:       span,
+		ResolvedTS: hlc.Timestamp{WallTime: 1},
+	})
+
+	r := newTestRegistration(span, hlc.Timestamp{}, nil, false /* withDiff */)
+	r.familyIDs = []uint32{0, 2}
+	for _, ev := range []*roachpb.RangeFeedEvent{ev0, ev1, ev2, evNoFamily, evNonTable, evCheckpoint} {
+		r.publish(ctx, ev, nil /* allocation */)
+	}
+	go r.runOutputLoop(context.Background(), 0)
+	require.NoError(t, r.waitForCaughtUp())
+	require.Equal(t, []*roachpb.RangeFeedEvent{ev0, ev2, evNoFamily, evNonTable, evCheckpoint}, r.stream.Events())
+	r.disconnect(nil)
+	<-r.errC
+}
+
 func TestRegistrationString(t *testing.T) {
 	testCases := []struct {
 		r   registration
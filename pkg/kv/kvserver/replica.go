@@ -707,6 +707,10 @@ type Replica struct {
 		// GC threshold for the range.
 		pendingGCThreshold hlc.Timestamp
 	}
+
+	// lastWrite tracks an upper bound on the MVCC timestamps written to the
+	// range while this replica holds the lease. See replica_last_write.go.
+	lastWrite lastWriteTracker
 }
 
 // String returns the string representation of the replica using an
@@ -1152,6 +1156,18 @@ func (r *Replica) GetMaxSplitQPS() (float64, bool) {
 	return r.loadBasedSplitter.MaxQPS(r.Clock().PhysicalTime())
 }
 
+// GetMaxSplitCPU returns the Replica's maximum CPU time per second, in
+// nanoseconds, over a configured measurement period. If the Replica has not
+// been recording CPU time for at least an entire measurement period, which it
+// only does when the load based splitting objective is cpu, the method will
+// return false.
+//
+// NOTE: This should only be used for load based splitting, only
+// works when the load based splitting cluster setting is enabled.
+func (r *Replica) GetMaxSplitCPU() (float64, bool) {
+	return r.loadBasedSplitter.MaxCPU(r.Clock().PhysicalTime())
+}
+
 // GetLastSplitQPS returns the Replica's most recent queries/s request rate.
 //
 // NOTE: This should only be used for load based splitting, only
@@ -13,6 +13,7 @@ package kvserver
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/readsummary/rspb"
@@ -269,7 +270,10 @@ func (r *Replica) handleDescResult(ctx context.Context, desc *roachpb.RangeDescr
 }
 
 func (r *Replica) handleLeaseResult(
-	ctx context.Context, lease *roachpb.Lease, priorReadSum *rspb.ReadSummary,
+	ctx context.Context,
+	lease *roachpb.Lease,
+	priorReadSum *rspb.ReadSummary,
+	closedTSHandoff *ctpb.LeaseTransferHandoff,
 ) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -278,6 +282,9 @@ func (r *Replica) handleLeaseResult(
 		lease,            /* newLease */
 		priorReadSum,
 		assertNoLeaseJump)
+	if closedTSHandoff != nil && lease.Replica.ReplicaID == r.replicaID {
+		r.applyClosedTimestampHandoffLocked(ctx, lease, closedTSHandoff)
+	}
 }
 
 func (r *Replica) handleTruncatedStateResult(
@@ -1323,9 +1323,10 @@ func (sm *replicaStateMachine) handleNonTrivialReplicatedEvalResult(
 	isRaftLogTruncationDeltaTrusted := true
 	if rResult.State != nil {
 		if newLease := rResult.State.Lease; newLease != nil {
-			sm.r.handleLeaseResult(ctx, newLease, rResult.PriorReadSummary)
+			sm.r.handleLeaseResult(ctx, newLease, rResult.PriorReadSummary, rResult.ClosedTimestampHandoff)
 			rResult.State.Lease = nil
 			rResult.PriorReadSummary = nil
+			rResult.ClosedTimestampHandoff = nil
 		}
 
 		// This strongly coupled truncation code will be removed in the release
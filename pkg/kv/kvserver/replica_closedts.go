@@ -13,6 +13,7 @@ package kvserver
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/sidetransport"
@@ -31,13 +32,13 @@ import (
 // If the closed timestamp was advanced, the function returns a LAI to be
 // attached to the newly closed timestamp.
 //
-// This is called by the closed timestamp side-transport. The desired closed timestamp
-// is passed as a map from range policy to timestamp; this function looks up the entry
-// for this range.
+// This is called by the closed timestamp side-transport. The desired closed
+// timestamp is obtained by passing this range's policy class to
+// targetForClass.
 func (r *Replica) BumpSideTransportClosed(
 	ctx context.Context,
 	now hlc.ClockTimestamp,
-	targetByPolicy [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp,
+	targetForClass func(closedts.PolicyClass) hlc.Timestamp,
 ) sidetransport.BumpSideTransportClosedResult {
 	var res sidetransport.BumpSideTransportClosedResult
 	r.mu.Lock()
@@ -55,8 +56,15 @@ func (r *Replica) BumpSideTransportClosed(
 	}
 
 	lai := ctpb.LAI(r.mu.state.LeaseAppliedIndex)
-	policy := r.closedTimestampPolicyRLocked()
-	target := targetByPolicy[policy]
+	class := r.closedTimestampPolicyClassRLocked()
+	if class.LagTarget != 0 &&
+		!r.ClusterSettings().Version.IsActive(ctx, clusterversion.ClosedTimestampPolicyClasses) {
+		// Receivers that don't know about lag targets would apply the closed
+		// timestamp of the default class for the range's policy to this range,
+		// so we stick to that class until the cluster is upgraded.
+		class.LagTarget = 0
+	}
+	target := targetForClass(class)
 	st := r.leaseStatusForRequestRLocked(ctx, now, hlc.Timestamp{} /* reqTS */)
 	// We need to own the lease but note that stasis (LeaseState_UNUSABLE) doesn't
 	// matter.
@@ -109,21 +117,63 @@ func (r *Replica) BumpSideTransportClosed(
 	r.sideTransportClosedTimestamp.forward(ctx, target, lai, knownApplied)
 	res.OK = true
 	res.LAI = lai
-	res.Policy = policy
+	res.Class = class
 	return res
 }
 
+// GetClosedTimestampHandoff returns the highest closed timestamp that the
+// side-transport knows for the range, along with its lease applied index. It is
+// shipped by lease transfers to the incoming leaseholder; see
+// applyClosedTimestampHandoffLocked.
+func (r *Replica) GetClosedTimestampHandoff(ctx context.Context) ctpb.LeaseTransferHandoff {
+	r.sideTransportClosedTimestamp.mu.RLock()
+	defer r.sideTransportClosedTimestamp.mu.RUnlock()
+	cur := r.sideTransportClosedTimestamp.mu.cur
+	return ctpb.LeaseTransferHandoff{ClosedTimestamp: cur.ts, LAI: cur.lai}
+}
+
+// applyClosedTimestampHandoffLocked is called on the incoming leaseholder of a
+// lease transfer with the closed timestamp side-transport state of the
+// outgoing leaseholder. The state seeds the replica's side-transport closed
+// timestamp and the propBuf's closed timestamp, which prevents the incoming
+// leaseholder from ever evaluating writes below a timestamp closed by the
+// outgoing leaseholder. The replica, which has been registered as a
+// leaseholder with the side-transport, also asks the side-transport to publish
+// its closed timestamps right away, so that followers hear about the range from
+// its new leaseholder without waiting for the next publishing cycle.
+func (r *Replica) applyClosedTimestampHandoffLocked(
+	ctx context.Context, lease *roachpb.Lease, handoff *ctpb.LeaseTransferHandoff,
+) {
+	if handoff.LAI == 0 {
+		// The outgoing leaseholder didn't close any timestamp through the
+		// side-transport.
+		return
+	}
+	knownApplied := handoff.LAI <= ctpb.LAI(r.mu.state.LeaseAppliedIndex)
+	r.sideTransportClosedTimestamp.forward(ctx, handoff.ClosedTimestamp, handoff.LAI, knownApplied)
+	r.mu.proposalBuf.forwardClosedTimestampLocked(handoff.ClosedTimestamp)
+	r.store.expediteClosedTimestampPublish(ctx, r.RangeID, lease.Sequence)
+}
+
+// closedTimestampPolicyClassRLocked returns the closed timestamp policy class
+// of the range, which combines the range's policy with the lag target
+// configured through its span config, if any.
+func (r *Replica) closedTimestampPolicyClassRLocked() closedts.PolicyClass {
+	return closedts.MakePolicyClass(
+		r.closedTimestampPolicyRLocked(), r.mu.conf.ClosedTimestampTargetDuration)
+}
+
 // closedTimestampTargetRLocked computes the timestamp we'd like to close for
 // this range. Note that we might not be able to ultimately close this timestamp
 // if there are requests in flight.
 func (r *Replica) closedTimestampTargetRLocked() hlc.Timestamp {
-	return closedts.TargetForPolicy(
+	return closedts.TargetForPolicyClass(
 		r.Clock().NowAsClockTimestamp(),
 		r.Clock().MaxOffset(),
 		closedts.TargetDuration.Get(&r.ClusterSettings().SV),
 		closedts.LeadForGlobalReadsOverride.Get(&r.ClusterSettings().SV),
 		closedts.SideTransportCloseInterval.Get(&r.ClusterSettings().SV),
-		r.closedTimestampPolicyRLocked(),
+		r.closedTimestampPolicyClassRLocked(),
 	)
 }
 
@@ -179,6 +229,7 @@ type sidetransportReceiver interface {
 		ctx context.Context, rangeID roachpb.RangeID, leaseholderNode roachpb.NodeID,
 	) (hlc.Timestamp, ctpb.LAI)
 	HTML() string
+	StreamStatuses() []sidetransport.StreamStatus
 }
 
 // closedTimestamp is a combination of a timestamp and a lease applied index.
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/sidetransport"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
@@ -510,6 +511,11 @@ func (r *mockReceiver) HTML() string {
 	return ""
 }
 
+// StreamStatuses is part of the sidetransportReceiver interface.
+func (r *mockReceiver) StreamStatuses() []sidetransport.StreamStatus {
+	return nil
+}
+
 // Test that r.GetCurrentClosedTimestamp() mixes its sources of information
 // correctly.
 func TestReplicaClosedTimestamp(t *testing.T) {
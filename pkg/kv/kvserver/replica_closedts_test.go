@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -39,6 +40,18 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// lagTargetForClass returns a function to be passed to BumpSideTransportClosed
+// which targets the given timestamp for ranges with the LAG_BY_CLUSTER_SETTING
+// policy.
+func lagTargetForClass(target hlc.Timestamp) func(closedts.PolicyClass) hlc.Timestamp {
+	return func(class closedts.PolicyClass) hlc.Timestamp {
+		if class.Policy != roachpb.LAG_BY_CLUSTER_SETTING {
+			return hlc.Timestamp{}
+		}
+		return target
+	}
+}
+
 // TestBumpSideTransportClosed tests the various states that a replica can find
 // itself in when its BumpSideTransportClosed is called. It verifies that the
 // method only returns successfully if it can bump its closed timestamp to the
@@ -266,8 +279,7 @@ func TestBumpSideTransportClosed(t *testing.T) {
 			setup: func(a setupArgs) (chan struct{}, chan error, error) {
 				// Manually bump the assigned closed timestamp to a time below
 				// where the test will attempt to bump it to.
-				var targets [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
-				targets[roachpb.LAG_BY_CLUSTER_SETTING] = a.target.Add(-1, 0)
+				targets := lagTargetForClass(a.target.Add(-1, 0))
 				return nil, nil, testutils.SucceedsSoonError(func() error {
 					res := a.repl.BumpSideTransportClosed(ctx, a.now, targets)
 					if !res.OK {
@@ -283,8 +295,7 @@ func TestBumpSideTransportClosed(t *testing.T) {
 			setup: func(a setupArgs) (chan struct{}, chan error, error) {
 				// Manually bump the assigned closed timestamp to a time equal
 				// to where the test will attempt to bump it to.
-				var targets [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
-				targets[roachpb.LAG_BY_CLUSTER_SETTING] = a.target
+				targets := lagTargetForClass(a.target)
 				return nil, nil, testutils.SucceedsSoonError(func() error {
 					res := a.repl.BumpSideTransportClosed(ctx, a.now, targets)
 					if !res.OK {
@@ -300,8 +311,7 @@ func TestBumpSideTransportClosed(t *testing.T) {
 			setup: func(a setupArgs) (chan struct{}, chan error, error) {
 				// Manually bump the assigned closed timestamp to a time above
 				// where the test will attempt to bump it to.
-				var targets [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
-				targets[roachpb.LAG_BY_CLUSTER_SETTING] = a.target.Add(1, 0)
+				targets := lagTargetForClass(a.target.Add(1, 0))
 				return nil, nil, testutils.SucceedsSoonError(func() error {
 					res := a.repl.BumpSideTransportClosed(ctx, a.now, targets)
 					if !res.OK {
@@ -377,8 +387,7 @@ func TestBumpSideTransportClosed(t *testing.T) {
 			} else {
 				target, exp = test.computeTarget(repl)
 			}
-			var targets [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
-			targets[roachpb.LAG_BY_CLUSTER_SETTING] = target
+			targets := lagTargetForClass(target)
 
 			// Run the setup function to get the replica in the desired state.
 			var unblockFilterC chan struct{}
@@ -716,13 +725,14 @@ func BenchmarkBumpSideTransportClosed(b *testing.B) {
 
 	manual.Pause()
 	now := s.Clock().NowAsClockTimestamp()
-	var targets [roachpb.MAX_CLOSED_TIMESTAMP_POLICY]hlc.Timestamp
+	var target hlc.Timestamp
+	targets := func(closedts.PolicyClass) hlc.Timestamp { return target }
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		// Advance time and the closed timestamp target.
 		now = now.ToTimestamp().Add(1, 0).UnsafeToClockTimestamp()
-		targets[roachpb.LAG_BY_CLUSTER_SETTING] = now.ToTimestamp()
+		target = now.ToTimestamp()
 
 		// Perform the call.
 		res := r.BumpSideTransportClosed(ctx, now, targets)
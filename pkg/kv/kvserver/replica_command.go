@@ -2853,6 +2853,9 @@ func (r *Replica) followerSendSnapshot(
 			r.store.metrics.RangeSnapshotUnknownSentBytes.Inc(inc)
 		}
 	}
+	recordIOOverloadPaced := func() {
+		r.store.metrics.RangeSnapshotSendIOOverloadPaced.Inc(1)
+	}
 
 	err = contextutil.RunWithTimeout(
 		ctx, "send-snapshot", sendSnapshotTimeout, func(ctx context.Context) error {
@@ -2864,6 +2867,7 @@ func (r *Replica) followerSendSnapshot(
 				newBatchFn,
 				sent,
 				recordBytesSent,
+				recordIOOverloadPaced,
 			)
 		},
 	)
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/abortspan"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/ctpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/readsummary/rspb"
@@ -135,6 +136,12 @@ func (rec SpanSetReplicaEvalContext) GetMaxSplitQPS() (float64, bool) {
 	return rec.i.GetMaxSplitQPS()
 }
 
+// GetMaxSplitCPU returns the Replica's maximum CPU nanos/s for splitting and
+// merging purposes.
+func (rec SpanSetReplicaEvalContext) GetMaxSplitCPU() (float64, bool) {
+	return rec.i.GetMaxSplitCPU()
+}
+
 // GetLastSplitQPS returns the Replica's most recent queries/s rate for
 // splitting and merging purposes.
 func (rec SpanSetReplicaEvalContext) GetLastSplitQPS() float64 {
@@ -163,6 +170,12 @@ func (rec SpanSetReplicaEvalContext) GetGCThreshold() hlc.Timestamp {
 	return rec.i.GetGCThreshold()
 }
 
+// GetLastWriteTimestamp returns an upper bound on the MVCC timestamps written
+// to the range.
+func (rec SpanSetReplicaEvalContext) GetLastWriteTimestamp() (hlc.Timestamp, bool) {
+	return rec.i.GetLastWriteTimestamp()
+}
+
 // ExcludeDataFromBackup returns whether the replica is to be excluded from a
 // backup.
 func (rec SpanSetReplicaEvalContext) ExcludeDataFromBackup() bool {
@@ -220,6 +233,13 @@ func (rec *SpanSetReplicaEvalContext) GetCurrentReadSummary(ctx context.Context)
 	return rec.i.GetCurrentReadSummary(ctx)
 }
 
+// GetClosedTimestampHandoff is part of the EvalContext interface.
+func (rec *SpanSetReplicaEvalContext) GetClosedTimestampHandoff(
+	ctx context.Context,
+) ctpb.LeaseTransferHandoff {
+	return rec.i.GetClosedTimestampHandoff(ctx)
+}
+
 // GetCurrentClosedTimestamp is part of the EvalContext interface.
 func (rec *SpanSetReplicaEvalContext) GetCurrentClosedTimestamp(ctx context.Context) hlc.Timestamp {
 	return rec.i.GetCurrentClosedTimestamp(ctx)
@@ -99,6 +99,11 @@ func newUnloadedReplica(
 	}, func() time.Duration {
 		return kvserverbase.SplitByLoadMergeDelay.Get(&store.cfg.Settings.SV)
 	})
+	split.InitObjective(&r.loadBasedSplitter, func() split.SplitObjective {
+		return splitByLoadObjective(&store.cfg.Settings.SV)
+	}, func() float64 {
+		return float64(SplitByLoadCPUThreshold.Get(&store.cfg.Settings.SV).Nanoseconds())
+	})
 	r.mu.proposals = map[kvserverbase.CmdIDKey]*ProposalData{}
 	r.mu.checksums = map[uuid.UUID]replicaChecksum{}
 	r.mu.proposalBuf.Init((*replicaProposer)(r), tracker.NewLockfreeTracker(), r.Clock(), r.ClusterSettings())
@@ -0,0 +1,153 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// lastWriteTracker tracks an upper bound on the MVCC timestamps of the values
+// written to a range. It allows callers such as incremental backups to learn
+// that a range has not been written to above a given timestamp without reading
+// its data.
+//
+// The bound is maintained by the leaseholder, which evaluates all writes to the
+// range. It is forwarded when a write is evaluated, while the write still holds
+// its latches, so a request that acquires latches after the write observes its
+// timestamp. Writes evaluated under prior leases are accounted for when the
+// lease is acquired: they could not have written above the start of the new
+// lease, or above the closed timestamp target of the range for ranges which
+// close timestamps in the future.
+type lastWriteTracker struct {
+	syncutil.Mutex
+	// ts is the upper bound. It is empty if no bound is known, which is the
+	// case until the replica acquires a lease.
+	ts hlc.Timestamp
+}
+
+// get returns the upper bound on the timestamps written to the range, and
+// false if no bound is known.
+func (t *lastWriteTracker) get() (hlc.Timestamp, bool) {
+	t.Lock()
+	defer t.Unlock()
+	return t.ts, !t.ts.IsEmpty()
+}
+
+// reset replaces the upper bound with the given timestamp. An empty timestamp
+// indicates that no bound is known.
+func (t *lastWriteTracker) reset(ts hlc.Timestamp) {
+	t.Lock()
+	defer t.Unlock()
+	t.ts = ts
+}
+
+// forward forwards the upper bound to the given timestamp, if a bound is known.
+func (t *lastWriteTracker) forward(ts hlc.Timestamp) {
+	t.Lock()
+	defer t.Unlock()
+	if !t.ts.IsEmpty() {
+		t.ts.Forward(ts)
+	}
+}
+
+// merge combines the upper bound of the right-hand side of a merge into the
+// upper bound of the left-hand side. The result is unknown if either bound is.
+func (t *lastWriteTracker) merge(other *lastWriteTracker) {
+	otherTS, ok := other.get()
+	if !ok {
+		t.reset(hlc.Timestamp{})
+		return
+	}
+	t.forward(otherTS)
+}
+
+// priorLeasesLastWriteBound returns an upper bound on the MVCC timestamps
+// written to the range under the leases preceding the given lease, which was
+// just acquired by the replica.
+//
+// Writes evaluated under prior leases did not write above the start of the new
+// lease, unless they belonged to transactions writing at future timestamps.
+// Those are pushed to the future by ranges with global reads, so they don't
+// write above the closed timestamp target of such ranges (plus the maximum
+// clock offset, to account for the clocks of other leaseholders).
+func (r *Replica) priorLeasesLastWriteBound(newLease *roachpb.Lease) hlc.Timestamp {
+	st := r.ClusterSettings()
+	bound := newLease.Start.ToTimestamp()
+	bound.Forward(closedts.TargetForPolicy(
+		r.Clock().NowAsClockTimestamp(),
+		r.Clock().MaxOffset(),
+		closedts.TargetDuration.Get(&st.SV),
+		closedts.LeadForGlobalReadsOverride.Get(&st.SV),
+		closedts.SideTransportCloseInterval.Get(&st.SV),
+		roachpb.LEAD_FOR_GLOBAL_READS,
+	).Add(r.Clock().MaxOffset().Nanoseconds(), 0))
+	return bound
+}
+
+// lastWriteTimestampForBatch returns the largest MVCC timestamp that the given
+// batch may have written, given its response.
+//
+// The timestamp a batch writes at is not necessarily the one it was sent at:
+// evaluation may bump it above the timestamp cache or, after a WriteTooOld
+// condition, above an existing version. The bumped timestamp is the one
+// reflected in the response.
+func lastWriteTimestampForBatch(
+	ba *roachpb.BatchRequest, br *roachpb.BatchResponse,
+) hlc.Timestamp {
+	ts := ba.WriteTimestamp()
+	if br != nil {
+		ts.Forward(br.Timestamp)
+		if br.Txn != nil {
+			ts.Forward(br.Txn.WriteTimestamp)
+		}
+	}
+	for _, union := range ba.Requests {
+		switch t := union.GetInner().(type) {
+		case *roachpb.ResolveIntentRequest:
+			// Committing an intent moves it to the commit timestamp of its
+			// transaction, and resolving the intent of a pushed transaction moves it
+			// to the pushed timestamp. Either may be above the timestamp of the
+			// batch.
+			if t.Status != roachpb.ABORTED {
+				ts.Forward(t.IntentTxn.WriteTimestamp)
+			}
+		case *roachpb.ResolveIntentRangeRequest:
+			if t.Status != roachpb.ABORTED {
+				ts.Forward(t.IntentTxn.WriteTimestamp)
+			}
+		case *roachpb.AddSSTableRequest:
+			// Unless the SST's timestamps are rewritten to the batch's timestamp,
+			// it may contain versions at arbitrary timestamps.
+			if t.SSTTimestampToRequestTimestamp.IsEmpty() {
+				ts = hlc.MaxTimestamp
+			}
+		}
+	}
+	return ts
+}
+
+// GetLastWriteTimestamp returns an upper bound on the MVCC timestamps of the
+// values written to the range, and false if no such bound is known. It must
+// only be called by the leaseholder, while holding latches over the keys that
+// the caller wants to know about.
+func (r *Replica) GetLastWriteTimestamp() (hlc.Timestamp, bool) {
+	return r.lastWrite.get()
+}
+
+// recordWrite forwards the upper bound on the MVCC timestamps written to the
+// range by the given batch, which was evaluated by the leaseholder into the
+// given response.
+func (r *Replica) recordWrite(ba *roachpb.BatchRequest, br *roachpb.BatchResponse) {
+	r.lastWrite.forward(lastWriteTimestampForBatch(ba, br))
+}
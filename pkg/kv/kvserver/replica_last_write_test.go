@@ -0,0 +1,146 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastWriteTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ts := func(wall int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wall} }
+
+	var lhs, rhs lastWriteTracker
+	// No bound is known until the tracker is reset, so writes are ignored.
+	lhs.forward(ts(5))
+	_, ok := lhs.get()
+	require.False(t, ok)
+
+	lhs.reset(ts(10))
+	lhs.forward(ts(5))
+	got, ok := lhs.get()
+	require.True(t, ok)
+	require.Equal(t, ts(10), got)
+	lhs.forward(ts(20))
+	got, _ = lhs.get()
+	require.Equal(t, ts(20), got)
+
+	// Merging with an unknown bound makes the bound unknown.
+	lhs.merge(&rhs)
+	_, ok = lhs.get()
+	require.False(t, ok)
+
+	lhs.reset(ts(20))
+	rhs.reset(ts(30))
+	lhs.merge(&rhs)
+	got, _ = lhs.get()
+	require.Equal(t, ts(30), got)
+}
+
+func TestLastWriteTimestampForBatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	key := roachpb.Key("a")
+	batchTS := hlc.Timestamp{WallTime: 10}
+	commitTS := hlc.Timestamp{WallTime: 20}
+	bumpedTS := hlc.Timestamp{WallTime: 30}
+	makeBatch := func(reqs ...roachpb.Request) *roachpb.BatchRequest {
+		ba := &roachpb.BatchRequest{}
+		ba.Timestamp = batchTS
+		ba.Add(reqs...)
+		return ba
+	}
+	txnResponse := func(writeTS hlc.Timestamp) *roachpb.BatchResponse {
+		br := &roachpb.BatchResponse{}
+		br.Txn = &roachpb.Transaction{TxnMeta: enginepb.TxnMeta{WriteTimestamp: writeTS}}
+		return br
+	}
+	resolve := func(status roachpb.TransactionStatus) roachpb.Request {
+		return &roachpb.ResolveIntentRequest{
+			RequestHeader: roachpb.RequestHeader{Key: key},
+			IntentTxn:     enginepb.TxnMeta{WriteTimestamp: commitTS},
+			Status:        status,
+		}
+	}
+
+	testCases := []struct {
+		name string
+		ba   *roachpb.BatchRequest
+		br   *roachpb.BatchResponse
+		exp  hlc.Timestamp
+	}{
+		{
+			name: "put",
+			ba:   makeBatch(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: key}}),
+			exp:  batchTS,
+		},
+		{
+			// A write bumped by the timestamp cache or by a WriteTooOld condition
+			// of a non-transactional batch.
+			name: "put bumped non-transactional",
+			ba:   makeBatch(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: key}}),
+			br:   &roachpb.BatchResponse{BatchResponse_Header: roachpb.BatchResponse_Header{Timestamp: bumpedTS}},
+			exp:  bumpedTS,
+		},
+		{
+			// The same for the write timestamp of a transaction.
+			name: "put bumped transactional",
+			ba:   makeBatch(&roachpb.PutRequest{RequestHeader: roachpb.RequestHeader{Key: key}}),
+			br:   txnResponse(bumpedTS),
+			exp:  bumpedTS,
+		},
+		{
+			name: "resolve committed intent",
+			ba:   makeBatch(resolve(roachpb.COMMITTED)),
+			exp:  commitTS,
+		},
+		{
+			name: "resolve pushed intent",
+			ba:   makeBatch(resolve(roachpb.PENDING)),
+			exp:  commitTS,
+		},
+		{
+			name: "resolve aborted intent",
+			ba:   makeBatch(resolve(roachpb.ABORTED)),
+			exp:  batchTS,
+		},
+		{
+			name: "addsstable at request timestamp",
+			ba: makeBatch(&roachpb.AddSSTableRequest{
+				RequestHeader:                  roachpb.RequestHeader{Key: key, EndKey: key.Next()},
+				SSTTimestampToRequestTimestamp: batchTS,
+			}),
+			exp: batchTS,
+		},
+		{
+			name: "addsstable at arbitrary timestamps",
+			ba: makeBatch(&roachpb.AddSSTableRequest{
+				RequestHeader: roachpb.RequestHeader{Key: key, EndKey: key.Next()},
+			}),
+			exp: hlc.MaxTimestamp,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, lastWriteTimestampForBatch(tc.ba, tc.br))
+		})
+	}
+}
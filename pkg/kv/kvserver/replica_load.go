@@ -24,6 +24,11 @@ type ReplicaLoad struct {
 	readKeys      *replicastats.ReplicaStats
 	writeBytes    *replicastats.ReplicaStats
 	readBytes     *replicastats.ReplicaStats
+
+	// writeHotKeys and readHotKeys track the keys which account for the most
+	// bytes written and read, respectively.
+	writeHotKeys *replicastats.HotKeys
+	readHotKeys  *replicastats.HotKeys
 }
 
 // NewReplicaLoad returns a new ReplicaLoad, which may be used to track the
@@ -40,6 +45,8 @@ func NewReplicaLoad(clock *hlc.Clock, getNodeLocality replicastats.LocalityOracl
 		readKeys:      replicastats.NewReplicaStats(clock, nil),
 		writeBytes:    replicastats.NewReplicaStats(clock, nil),
 		readBytes:     replicastats.NewReplicaStats(clock, nil),
+		writeHotKeys:  replicastats.NewHotKeys(clock),
+		readHotKeys:   replicastats.NewHotKeys(clock),
 	}
 }
 
@@ -52,6 +59,8 @@ func (rl *ReplicaLoad) split(other *ReplicaLoad) {
 	rl.readKeys.SplitRequestCounts(other.readKeys)
 	rl.writeBytes.SplitRequestCounts(other.writeBytes)
 	rl.readBytes.SplitRequestCounts(other.readBytes)
+	rl.writeHotKeys.Split(other.writeHotKeys)
+	rl.readHotKeys.Split(other.readHotKeys)
 }
 
 // merge will combine the tracked load in other, into the calling struct.
@@ -62,6 +71,8 @@ func (rl *ReplicaLoad) merge(other *ReplicaLoad) {
 	rl.readKeys.MergeRequestCounts(other.readKeys)
 	rl.writeBytes.MergeRequestCounts(other.writeBytes)
 	rl.readBytes.MergeRequestCounts(other.readBytes)
+	rl.writeHotKeys.Merge(other.writeHotKeys)
+	rl.readHotKeys.Merge(other.readHotKeys)
 }
 
 // reset will clear all recorded history.
@@ -72,4 +83,6 @@ func (rl *ReplicaLoad) reset() {
 	rl.readKeys.ResetRequestCounts()
 	rl.writeBytes.ResetRequestCounts()
 	rl.readBytes.ResetRequestCounts()
+	rl.writeHotKeys.Reset()
+	rl.readHotKeys.Reset()
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/replicastats"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"go.etcd.io/etcd/raft/v3"
@@ -325,6 +326,14 @@ func (r *Replica) ReadBytesPerSecond() float64 {
 	return rbps
 }
 
+// HotKeys returns up to k of the keys in the range with the most bytes written
+// per second and up to k of the keys with the most bytes read per second. The
+// keys are only tracked while kv.replica_stats.hot_keys.enabled is set.
+func (r *Replica) HotKeys(k int) (writes, reads []replicastats.HotKey) {
+	span := r.Desc().KeySpan().AsRawSpanWithNoLocals()
+	return r.loadStats.writeHotKeys.TopK(k, span), r.loadStats.readHotKeys.TopK(k, span)
+}
+
 func (r *Replica) needsSplitBySizeRLocked() bool {
 	exceeded, _ := r.exceedsMultipleOfSplitSizeRLocked(1)
 	return exceeded
@@ -268,6 +268,10 @@ func (r *Replica) leasePostApplyLocked(
 		if newLease.Type() == roachpb.LeaseEpoch && leaseChangingHands || log.V(1) {
 			log.VEventf(ctx, 1, "new range lease %s following %s", newLease, prevLease)
 		}
+	} else if leaseChangingHands {
+		// Writes are no longer evaluated by this replica, so it can't keep track
+		// of their timestamps.
+		r.lastWrite.reset(hlc.Timestamp{})
 	}
 
 	if leaseChangingHands && iAmTheLeaseHolder {
@@ -320,6 +324,10 @@ func (r *Replica) leasePostApplyLocked(
 		}
 		applyReadSummaryToTimestampCache(r.store.tsCache, r.descRLocked(), sum)
 
+		// Start tracking the timestamps written to the range, accounting for the
+		// writes evaluated under prior leases.
+		r.lastWrite.reset(r.priorLeasesLastWriteBound(newLease))
+
 		// Reset the request counts used to make lease placement decisions and
 		// load-based splitting/merging decisions whenever starting a new lease.
 		if r.loadStats != nil {
@@ -737,6 +745,12 @@ func (r *Replica) evaluateProposal(
 		}
 		res.Replicated.Delta = ms.ToStatsDelta()
 
+		// Account for the timestamps written by the batch before its latches are
+		// released, so that readers serialized after it observe them.
+		if ba.IsWrite() {
+			r.recordWrite(ba, br)
+		}
+
 		// This is the result of a migration. See the field for more details.
 		if res.Replicated.Delta.ContainsEstimates > 0 {
 			res.Replicated.Delta.ContainsEstimates *= 2
@@ -14,11 +14,13 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/tracker"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftutil"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -29,6 +31,32 @@ import (
 	"go.etcd.io/etcd/raft/v3/raftpb"
 )
 
+// proposalBatchingDelay is the amount of time that the first trivial proposal
+// inserted into an empty proposal buffer waits for other proposals to join it
+// before the buffer is flushed into Raft.
+var proposalBatchingDelay = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.raft.proposal_batching.delay",
+	"if positive, the amount of time that a write proposed to an idle range waits for "+
+		"concurrent writes to the same range so that they are replicated together in a "+
+		"single Raft proposal; this trades latency for throughput on ranges receiving many "+
+		"small writes, such as the last range of a table with sequential keys",
+	0,
+	settings.NonNegativeDurationWithMaximum(100*time.Millisecond),
+)
+
+// proposalBatchingMaxCount is the number of proposals after which a batch
+// that is being delayed by proposalBatchingDelay is flushed without waiting
+// for the rest of the delay.
+var proposalBatchingMaxCount = settings.RegisterIntSetting(
+	settings.SystemOnly,
+	"kv.raft.proposal_batching.max_count",
+	"the number of writes after which writes waiting for kv.raft.proposal_batching.delay "+
+		"are proposed without waiting for the rest of the delay",
+	64,
+	settings.PositiveInt,
+)
+
 // propBuf is a multi-producer, single-consumer buffer for Raft proposals on a
 // range. The buffer supports concurrent insertion of proposals.
 //
@@ -52,6 +80,15 @@ import (
 // moving them into Raft directly because Raft would not begin replicating the
 // proposals until the next handleRaftReady iteration anyway.
 //
+// If kv.raft.proposal_batching.delay is set, the handleRaftReady iteration that
+// is scheduled when a trivial proposal is inserted into the empty buffer is
+// instead deferred by that delay, unless kv.raft.proposal_batching.max_count
+// proposals were inserted in the meantime. This allows the writes of many
+// concurrent transactions to be appended to the Raft log, replicated and
+// synced together (group commit), at the cost of latency for the first of
+// them. Note that the buffer is still flushed earlier if something else
+// triggers a handleRaftReady iteration.
+//
 // propBuf inherits the locking of the proposer that it is bound to during
 // initialization. Methods called "...Locked" and "...RLocked" expect the
 // corresponding locker() and rlocker() to be held.
@@ -145,6 +182,9 @@ type proposer interface {
 	firstIndex() uint64
 	leaseAppliedIndex() uint64
 	enqueueUpdateCheck()
+	// enqueueUpdateCheckAfter schedules a Raft update check after the given
+	// delay.
+	enqueueUpdateCheckAfter(time.Duration)
 	closedTimestampTarget() hlc.Timestamp
 
 	// The following require the proposer to hold an exclusive lock.
@@ -263,7 +303,7 @@ func (b *propBuf) Insert(ctx context.Context, p *ProposalData, tok TrackedReques
 	// Insert the proposal into the buffer's array. The buffer now takes ownership
 	// of the token.
 	p.tok = tok.Move(ctx)
-	b.insertIntoArray(p, idx)
+	b.insertIntoArray(p, idx, true /* batch */)
 	return nil
 }
 
@@ -278,8 +318,9 @@ func (b *propBuf) ReinsertLocked(ctx context.Context, p *ProposalData) error {
 		return err
 	}
 
-	// Insert the proposal into the buffer's array.
-	b.insertIntoArray(p, idx)
+	// Insert the proposal into the buffer's array. Reproposals are not delayed
+	// by proposal batching.
+	b.insertIntoArray(p, idx, false /* batch */)
 	return nil
 }
 
@@ -331,18 +372,41 @@ func (b *propBuf) allocateIndex(ctx context.Context, wLocked bool) (int, error)
 }
 
 // insertIntoArray inserts the proposal into the proposal buffer's array at the
-// specified index. It also schedules a Raft update check if necessary.
-func (b *propBuf) insertIntoArray(p *ProposalData, idx int) {
+// specified index. It also schedules a Raft update check if necessary. If batch
+// is set, the update check may be delayed by proposal batching.
+func (b *propBuf) insertIntoArray(p *ProposalData, idx int, batch bool) {
 	b.arr.asSlice()[idx] = p
+	var delay time.Duration
+	if batch {
+		delay = proposalBatchingDelay.Get(&b.settings.SV)
+	}
 	if idx == 0 {
 		// If this is the first proposal in the buffer, schedule a Raft update
 		// check to inform Raft processing about the new proposal. Everyone else
 		// can rely on the request that added the first proposal to the buffer
 		// having already scheduled a Raft update check.
+		if delay > 0 && batchableProposal(p) {
+			// Give other proposals the chance to join this one before it is
+			// handed to Raft.
+			b.p.enqueueUpdateCheckAfter(delay)
+		} else {
+			b.p.enqueueUpdateCheck()
+		}
+	} else if delay > 0 &&
+		(int64(idx+1) == proposalBatchingMaxCount.Get(&b.settings.SV) || !batchableProposal(p)) {
+		// Enough proposals were batched, or this proposal must not wait for the
+		// rest of the delay.
 		b.p.enqueueUpdateCheck()
 	}
 }
 
+// batchableProposal returns whether the given proposal may wait for other
+// proposals to be batched with it. Non-trivial proposals, like lease requests
+// and splits, are never delayed.
+func batchableProposal(p *ProposalData) bool {
+	return p.command != nil && isTrivial(&p.command.ReplicatedEvalResult)
+}
+
 func (b *propBuf) flushRLocked(ctx context.Context) error {
 	// Upgrade the shared lock to an exclusive lock. After doing so, check again
 	// whether the proposer has been destroyed. If so, wake up other goroutines
@@ -1157,6 +1221,12 @@ func (rp *replicaProposer) enqueueUpdateCheck() {
 	rp.store.enqueueRaftUpdateCheck(rp.RangeID)
 }
 
+func (rp *replicaProposer) enqueueUpdateCheckAfter(delay time.Duration) {
+	// The update check is not canceled if the buffer is flushed before the
+	// delay expires, in which case it's a cheap no-op.
+	time.AfterFunc(delay, rp.enqueueUpdateCheck)
+}
+
 func (rp *replicaProposer) closedTimestampTarget() hlc.Timestamp {
 	return (*Replica)(rp).closedTimestampTargetRLocked()
 }
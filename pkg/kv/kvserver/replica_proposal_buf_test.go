@@ -42,12 +42,14 @@ import (
 // testProposer is a testing implementation of proposer.
 type testProposer struct {
 	syncutil.RWMutex
-	clock      *hlc.Clock
-	ds         destroyStatus
-	fi         uint64
-	lai        uint64
-	enqueued   int
-	registered int
+	clock    *hlc.Clock
+	ds       destroyStatus
+	fi       uint64
+	lai      uint64
+	enqueued int
+	// delayedEnqueues records the delays passed to enqueueUpdateCheckAfter.
+	delayedEnqueues []time.Duration
+	registered      int
 
 	// If not nil, this can be a testProposerRaft used to mock the raft group
 	// passed to FlushLockedWithRaftGroup().
@@ -146,6 +148,10 @@ func (t *testProposer) enqueueUpdateCheck() {
 	t.enqueued++
 }
 
+func (t *testProposer) enqueueUpdateCheckAfter(delay time.Duration) {
+	t.delayedEnqueues = append(t.delayedEnqueues, delay)
+}
+
 func (t *testProposer) closedTimestampTarget() hlc.Timestamp {
 	if t.clock == nil {
 		return hlc.Timestamp{}
@@ -364,6 +370,66 @@ func TestProposalBuffer(t *testing.T) {
 	require.Equal(t, propBufArrayMinSize, b.arr.len())
 }
 
+// TestProposalBufferBatchingDelay tests that, with proposal batching enabled,
+// the Raft update check for the first trivial proposal inserted into the
+// buffer is delayed until enough proposals joined it or a proposal that must
+// not wait is inserted.
+func TestProposalBufferBatchingDelay(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	r := &testProposerRaft{}
+	p := testProposer{
+		raftGroup: r,
+	}
+	var b propBuf
+	var pc proposalCreator
+	clock := hlc.NewClockWithSystemTimeSource(time.Nanosecond /* maxOffset */)
+	st := cluster.MakeTestingClusterSettings()
+	const delay = 5 * time.Millisecond
+	proposalBatchingDelay.Override(ctx, &st.SV, delay)
+	proposalBatchingMaxCount.Override(ctx, &st.SV, 4)
+	b.Init(&p, tracker.NewLockfreeTracker(), clock, st)
+
+	insert := func(pd *ProposalData) {
+		_, tok := b.TrackEvaluatingRequest(ctx, hlc.MinTimestamp)
+		require.NoError(t, b.Insert(ctx, pd, tok))
+	}
+
+	// The first proposal delays the update check, and the following ones don't
+	// schedule one until the batch is full.
+	for i := 0; i < 3; i++ {
+		insert(pc.newPutProposal(hlc.Timestamp{}))
+		require.Equal(t, 0, p.enqueued)
+		require.Equal(t, []time.Duration{delay}, p.delayedEnqueues)
+	}
+	insert(pc.newPutProposal(hlc.Timestamp{}))
+	require.Equal(t, 1, p.enqueued)
+	require.Nil(t, b.flushLocked(ctx))
+	require.Len(t, r.consumeProposals(), 4)
+
+	// A lease request is never delayed, neither when it is inserted into the
+	// empty buffer nor when it joins a delayed batch.
+	insert(pc.newLeaseRequestProposal(roachpb.Lease{}))
+	require.Equal(t, 2, p.enqueued)
+	require.Len(t, p.delayedEnqueues, 1)
+	require.Nil(t, b.flushLocked(ctx))
+	insert(pc.newPutProposal(hlc.Timestamp{}))
+	require.Len(t, p.delayedEnqueues, 2)
+	insert(pc.newLeaseRequestProposal(roachpb.Lease{}))
+	require.Equal(t, 3, p.enqueued)
+	require.Nil(t, b.flushLocked(ctx))
+	require.Len(t, r.consumeProposals(), 3)
+
+	// Without a delay, every proposal into the empty buffer schedules an
+	// update check right away.
+	proposalBatchingDelay.Override(ctx, &st.SV, 0)
+	insert(pc.newPutProposal(hlc.Timestamp{}))
+	require.Equal(t, 4, p.enqueued)
+	require.Len(t, p.delayedEnqueues, 2)
+}
+
 // TestProposalBufferConcurrentWithDestroy tests the concurrency properties of
 // the Raft proposal buffer.
 func TestProposalBufferConcurrentWithDestroy(t *testing.T) {
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/intentresolver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rangefeed"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -37,16 +38,9 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-// RangefeedEnabled is a cluster setting that enables rangefeed requests.
-// Certain ranges have span configs that specifically enable rangefeeds (system
-// ranges and ranges covering tables in the system database); this setting
-// covers everything else.
-var RangefeedEnabled = settings.RegisterBoolSetting(
-	settings.TenantWritable,
-	"kv.rangefeed.enabled",
-	"if set, rangefeed registration is enabled",
-	false,
-).WithPublic()
+// RangefeedEnabled is a cluster setting that enables rangefeed requests. It is
+// defined in kvserverbase so that it can be checked by SQL.
+var RangefeedEnabled = kvserverbase.RangefeedEnabled
 
 // RangeFeedRefreshInterval controls the frequency with which we deliver closed
 // timestamp updates to rangefeeds.
@@ -59,6 +53,28 @@ var RangeFeedRefreshInterval = settings.RegisterDurationSetting(
 	settings.NonNegativeDuration,
 )
 
+// RangefeedCatchUpScanUseTBI controls whether rangefeed catch-up scans use
+// time-bound iterators.
+var RangefeedCatchUpScanUseTBI = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.rangefeed.catchup_scan_iterator_optimization.enabled",
+	"if set, rangefeed catch-up scans use time-bound iterators to skip over data "+
+		"older than the catch-up scan's start time",
+	true,
+)
+
+// RangefeedCatchUpScanVerification controls whether rangefeed catch-up scans
+// that use time-bound iterators are verified in the background.
+var RangefeedCatchUpScanVerification = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.rangefeed.catchup_scan_verification.enabled",
+	"if set, every rangefeed catch-up scan that uses a time-bound iterator is "+
+		"repeated in the background against a snapshot of the replica, both with and "+
+		"without the time-bound iterator, and any mismatch is logged and counted; "+
+		"this more than doubles the cost of catch-up scans",
+	false,
+)
+
 // lockedRangefeedStream is an implementation of rangefeed.Stream which provides
 // support for concurrent calls to Send. Note that the default implementation of
 // grpc.Stream is not safe for concurrent calls to Send.
@@ -218,15 +234,19 @@ func (r *Replica) rangeFeedWithRangeID(
 	// Register the stream with a catch-up iterator.
 	var catchUpIterFunc rangefeed.CatchUpIteratorConstructor
 	if usingCatchUpIter {
+		useTBI := RangefeedCatchUpScanUseTBI.Get(&r.ClusterSettings().SV)
 		catchUpIterFunc = func(span roachpb.Span, startTime hlc.Timestamp) *rangefeed.CatchUpIterator {
 			// Assert that we still hold the raftMu when this is called to ensure
 			// that the catchUpIter reads from the current snapshot.
 			r.raftMu.AssertHeld()
-			return rangefeed.NewCatchUpIterator(r.Engine(), span, startTime, iterSemRelease)
+			if useTBI && RangefeedCatchUpScanVerification.Get(&r.ClusterSettings().SV) {
+				r.verifyCatchUpScanRaftMuLocked(span, startTime, args.WithDiff)
+			}
+			return rangefeed.NewCatchUpIterator(r.Engine(), span, startTime, iterSemRelease, useTBI)
 		}
 	}
 	p := r.registerWithRangefeedRaftMuLocked(
-		ctx, rSpan, args.Timestamp, catchUpIterFunc, args.WithDiff, lockedStream, errC,
+		ctx, rSpan, args.Timestamp, catchUpIterFunc, args.WithDiff, args.FamilyIDs, lockedStream, errC,
 	)
 	r.raftMu.Unlock()
 
@@ -238,6 +258,30 @@ func (r *Replica) rangeFeedWithRangeID(
 	return <-errC
 }
 
+// verifyCatchUpScanRaftMuLocked verifies in the background that a catch-up
+// scan over the given key/time span emits the same events with and without a
+// time-bound iterator. The verification runs against an engine snapshot taken
+// under raftMu, so it observes the same data as a catch-up iterator created in
+// the same critical section. Mismatches are logged and counted, but do not
+// affect the rangefeed, which is why the verification is not tied to the
+// rangefeed's context.
+func (r *Replica) verifyCatchUpScanRaftMuLocked(
+	span roachpb.Span, startTime hlc.Timestamp, withDiff bool,
+) {
+	r.raftMu.AssertHeld()
+	ctx := r.AnnotateCtx(context.Background())
+	snap := r.Engine().NewSnapshot()
+	if err := r.store.stopper.RunAsyncTask(ctx, "rangefeed-catchup-scan-verification", func(ctx context.Context) {
+		defer snap.Close()
+		if err := rangefeed.VerifyCatchUpScan(snap, span, startTime, withDiff); err != nil {
+			r.store.metrics.RangeFeedMetrics.RangeFeedCatchUpScanVerificationFailures.Inc(1)
+			log.Errorf(ctx, "rangefeed catch-up scan verification failed: %v", err)
+		}
+	}); err != nil {
+		snap.Close()
+	}
+}
+
 func (r *Replica) getRangefeedProcessorAndFilter() (*rangefeed.Processor, *rangefeed.Filter) {
 	r.rangefeedMu.RLock()
 	defer r.rangefeedMu.RUnlock()
@@ -326,6 +370,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	startTS hlc.Timestamp, // exclusive
 	catchUpIter rangefeed.CatchUpIteratorConstructor,
 	withDiff bool,
+	familyIDs []uint32,
 	stream rangefeed.Stream,
 	errC chan<- *roachpb.Error,
 ) *rangefeed.Processor {
@@ -337,7 +382,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	r.rangefeedMu.Lock()
 	p := r.rangefeedMu.proc
 	if p != nil {
-		reg, filter := p.Register(span, startTS, catchUpIter, withDiff, stream, errC)
+		reg, filter := p.Register(span, startTS, catchUpIter, withDiff, familyIDs, stream, errC)
 		if reg {
 			// Registered successfully with an existing processor.
 			// Update the rangefeed filter to avoid filtering ops
@@ -406,7 +451,7 @@ func (r *Replica) registerWithRangefeedRaftMuLocked(
 	// any other goroutines are able to stop the processor. In other words,
 	// this ensures that the only time the registration fails is during
 	// server shutdown.
-	reg, filter := p.Register(span, startTS, catchUpIter, withDiff, stream, errC)
+	reg, filter := p.Register(span, startTS, catchUpIter, withDiff, familyIDs, stream, errC)
 	if !reg {
 		select {
 		case <-r.store.Stopper().ShouldQuiesce():
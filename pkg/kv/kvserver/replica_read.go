@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/replicastats"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/uncertainty"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -191,6 +192,10 @@ func (r *Replica) executeReadOnlyBatch(
 		keysRead, bytesRead := getBatchResponseReadStats(br)
 		r.loadStats.readKeys.RecordCount(keysRead, 0)
 		r.loadStats.readBytes.RecordCount(bytesRead, 0)
+		if replicastats.HotKeysEnabled.Get(&r.store.cfg.Settings.SV) &&
+			r.loadStats.readHotKeys.ShouldSample() {
+			recordBatchResponseHotKeys(r.loadStats.readHotKeys, ba, br)
+		}
 		log.Event(ctx, "read completed")
 	}
 	return br, nil, nil, pErr
@@ -465,3 +470,19 @@ func getBatchResponseReadStats(br *roachpb.BatchResponse) (float64, float64) {
 	}
 	return keys, bytes
 }
+
+// recordBatchResponseHotKeys records the bytes read by each request in the
+// sampled batch against the request's start key. Reads of a span are attributed to the
+// span's start key, which identifies the hot scan well enough in practice.
+func recordBatchResponseHotKeys(
+	hk *replicastats.HotKeys, ba *roachpb.BatchRequest, br *roachpb.BatchResponse,
+) {
+	if len(ba.Requests) != len(br.Responses) {
+		return
+	}
+	for i, reply := range br.Responses {
+		if bytesRead := reply.GetInner().Header().NumBytes; bytesRead > 0 {
+			hk.RecordSample(ba.Requests[i].GetInner().Header().Key, float64(bytesRead))
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/circuit"
+	"github.com/cockroachdb/cockroach/pkg/util/grunning"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
@@ -161,6 +162,7 @@ func (r *Replica) SendWithWriteBytes(
 	var br *roachpb.BatchResponse
 	var pErr *roachpb.Error
 	var writeBytes *StoreWriteBytes
+	startCPU := grunning.Time()
 	if isReadOnly {
 		log.Event(ctx, "read-only path")
 		fn := (*Replica).executeReadOnlyBatch
@@ -180,6 +182,9 @@ func (r *Replica) SendWithWriteBytes(
 	} else {
 		log.Fatalf(ctx, "don't know how to handle command %s", ba)
 	}
+	if !ba.IsAdmin() {
+		r.recordCPUForLoadBasedSplitting(ctx, ba, grunning.Subtract(grunning.Time(), startCPU))
+	}
 	if pErr != nil {
 		log.Eventf(ctx, "replica.Send got error: %s", pErr)
 	} else {
@@ -1004,6 +1009,15 @@ func (r *Replica) recordBatchRequestLoad(ctx context.Context, ba *roachpb.BatchR
 	r.loadStats.batchRequests.RecordCount(adjustedQPS, ba.Header.GatewayNodeID)
 	r.loadStats.requests.RecordCount(float64(len(ba.Requests)), ba.Header.GatewayNodeID)
 	r.loadStats.writeBytes.RecordCount(getBatchRequestWriteBytes(ba), ba.Header.GatewayNodeID)
+
+	if ba.IsWrite() && replicastats.HotKeysEnabled.Get(&r.store.cfg.Settings.SV) &&
+		r.loadStats.writeHotKeys.ShouldSample() {
+		for i := range ba.Requests {
+			if swr, isSizedWrite := ba.Requests[i].GetInner().(roachpb.SizedWriteRequest); isSizedWrite {
+				r.loadStats.writeHotKeys.RecordSample(swr.Header().Key, float64(swr.WriteBytes()))
+			}
+		}
+	}
 }
 
 // getBatchRequestQPS calculates the cost estimation of a BatchRequest. The
@@ -12,10 +12,14 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/split"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/grunning"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -35,6 +39,39 @@ var SplitByLoadQPSThreshold = settings.RegisterIntSetting(
 	2500, // 2500 req/s
 ).WithPublic()
 
+// SplitByLoadObjective wraps "kv.range_split.load_objective".
+var SplitByLoadObjective = settings.RegisterEnumSetting(
+	settings.TenantWritable,
+	"kv.range_split.load_objective",
+	"the load that load based splitting balances between the resulting ranges; "+
+		"cpu is only effective in builds that support measuring the CPU time of "+
+		"requests, and falls back to qps otherwise",
+	"qps",
+	map[int64]string{
+		int64(split.SplitQPS): "qps",
+		int64(split.SplitCPU): "cpu",
+	},
+)
+
+// SplitByLoadCPUThreshold wraps "kv.range_split.load_cpu_threshold".
+var SplitByLoadCPUThreshold = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"kv.range_split.load_cpu_threshold",
+	"the CPU time per second spent on a range's requests over which, the range "+
+		"becomes a candidate for load based splitting, when the objective is cpu",
+	500*time.Millisecond,
+	settings.PositiveDuration,
+)
+
+// splitByLoadObjective returns the load based splitting objective in effect
+// for the given settings.
+func splitByLoadObjective(sv *settings.Values) split.SplitObjective {
+	if !grunning.Supported() {
+		return split.SplitQPS
+	}
+	return split.SplitObjective(SplitByLoadObjective.Get(sv))
+}
+
 // SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
 func (r *Replica) SplitByLoadQPSThreshold() float64 {
 	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
@@ -63,3 +100,23 @@ func (r *Replica) recordBatchForLoadBasedSplitting(
 		r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
 	}
 }
+
+// recordCPUForLoadBasedSplitting records the CPU time spent on the batch to be
+// considered for load based splitting, when its objective is CPU.
+func (r *Replica) recordCPUForLoadBasedSplitting(
+	ctx context.Context, ba *roachpb.BatchRequest, cpu time.Duration,
+) {
+	if !r.SplitByLoadEnabled() {
+		return
+	}
+	shouldInitSplit := r.loadBasedSplitter.RecordCPU(timeutil.Now(), cpu.Nanoseconds(), func() roachpb.Span {
+		rs, err := keys.Range(ba.Requests)
+		if err != nil {
+			return roachpb.Span{}
+		}
+		return rs.AsRawSpanWithNoLocals()
+	})
+	if shouldInitSplit {
+		r.store.splitQueue.MaybeAddAsync(ctx, r, r.store.Clock().NowAsClockTimestamp())
+	}
+}
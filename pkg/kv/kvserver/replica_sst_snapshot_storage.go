@@ -31,8 +31,11 @@ import (
 type SSTSnapshotStorage struct {
 	engine  storage.Engine
 	limiter *rate.Limiter
-	dir     string
-	mu      struct {
+	// diskBandwidth, if set, meters and limits the disk bandwidth used by the
+	// SSTs written to the scratches.
+	diskBandwidth *diskBandwidthGovernor
+	dir           string
+	mu            struct {
 		syncutil.Mutex
 		rangeRefCount map[roachpb.RangeID]int
 	}
@@ -234,6 +237,11 @@ func (f *SSTSnapshotStorageFile) Write(contents []byte) (int, error) {
 	if err := limitBulkIOWrite(f.ctx, f.scratch.storage.limiter, len(contents)); err != nil {
 		return 0, err
 	}
+	if g := f.scratch.storage.diskBandwidth; g != nil {
+		if err := g.wait(f.ctx, diskBandwidthSnapshot, int64(len(contents))); err != nil {
+			return 0, err
+		}
+	}
 	return f.file.Write(contents)
 }
 
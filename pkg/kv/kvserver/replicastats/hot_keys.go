@@ -0,0 +1,269 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replicastats
+
+import (
+	"bytes"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// hotKeysCapacity is the number of keys tracked by each window of a HotKeys
+// sketch. The sketch guarantees that every key which accounts for more than
+// 1/hotKeysCapacity of the bytes recorded in a window is tracked.
+const hotKeysCapacity = 32
+
+// hotKeysSampleInterval is the interval at which batches are sampled by
+// ShouldSample.
+const hotKeysSampleInterval = 16
+
+// HotKeysEnabled wraps "kv.replica_stats.hot_keys.enabled". When enabled,
+// replicas maintain an approximate list of the keys that account for the most
+// bytes written and read, so that the keys responsible for a hot range can be
+// identified.
+var HotKeysEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.replica_stats.hot_keys.enabled",
+	"if enabled, replicas track the keys with the most bytes written and read",
+	false,
+)
+
+// HotKey is a key tracked by a HotKeys sketch, along with an estimate of the
+// rate at which bytes were recorded against it.
+type HotKey struct {
+	Key roachpb.Key
+	// BytesPerSecond is an estimate of the bytes recorded against the key per
+	// second. The estimate never underestimates the rate of a key that has been
+	// tracked throughout the sampled windows, and overestimates it by at most
+	// the rate of the least frequent key in each window.
+	BytesPerSecond float64
+}
+
+// HotKeys maintains an approximate list of the keys which account for the most
+// bytes recorded against a replica, using the Space-Saving heavy hitters
+// algorithm: a fixed number of counters is maintained, and a key which is not
+// tracked replaces the key with the smallest count, inheriting its count.
+//
+// Similar to ReplicaStats, old keys are aged out by rotating through windows
+// rather than by decaying counts. Only the current and previous windows are
+// kept.
+type HotKeys struct {
+	clock *hlc.Clock
+
+	// sampleCount is incremented atomically by ShouldSample.
+	sampleCount uint32
+
+	mu struct {
+		syncutil.Mutex
+		cur, prev  hotKeysWindow
+		lastRotate time.Time
+		// hasPrev is set if the previous window covers the full rotation
+		// interval preceding lastRotate.
+		hasPrev bool
+	}
+}
+
+// hotKeysWindow is a Space-Saving sketch over a single window. The map is
+// allocated lazily, so that replicas which don't receive any load don't pay
+// for it. The counts are stored behind pointers so that the count of a key
+// which is already tracked can be updated without copying the key.
+type hotKeysWindow struct {
+	counts map[string]*float64
+}
+
+func (w *hotKeysWindow) record(key []byte, count float64) {
+	// NB: the lookup doesn't allocate, so the key is only copied below, when it
+	// isn't tracked yet. The copy is required since the caller's key may alias
+	// a request that is reused.
+	if c, ok := w.counts[string(key)]; ok {
+		*c += count
+		return
+	}
+	if w.counts == nil {
+		w.counts = make(map[string]*float64, hotKeysCapacity)
+	}
+	if len(w.counts) < hotKeysCapacity {
+		w.counts[string(key)] = &count
+		return
+	}
+	// The sketch is full, so replace the key with the smallest count. The new
+	// key inherits that count, which bounds the error of its estimate.
+	var minKey string
+	var minCount *float64
+	for k, c := range w.counts {
+		if minCount == nil || *c < *minCount {
+			minKey, minCount = k, c
+		}
+	}
+	delete(w.counts, minKey)
+	*minCount += count
+	w.counts[string(key)] = minCount
+}
+
+func (w *hotKeysWindow) reset() {
+	w.counts = nil
+}
+
+// NewHotKeys constructs a new HotKeys sketch.
+func NewHotKeys(clock *hlc.Clock) *HotKeys {
+	hk := &HotKeys{clock: clock}
+	hk.mu.lastRotate = timeutil.Unix(0, clock.PhysicalNow())
+	return hk
+}
+
+// ShouldSample returns whether the keys of the next batch should be recorded.
+// Only one in every hotKeysSampleInterval batches is sampled, so that the
+// other batches don't pay for the mutex and the copies of their keys. The
+// counts of a sampled batch are recorded with RecordSample, which scales them
+// up accordingly.
+func (hk *HotKeys) ShouldSample() bool {
+	return atomic.AddUint32(&hk.sampleCount, 1)%hotKeysSampleInterval == 0
+}
+
+// RecordSample is like Record, for the keys of a batch for which ShouldSample
+// returned true.
+func (hk *HotKeys) RecordSample(key roachpb.Key, count float64) {
+	hk.Record(key, count*hotKeysSampleInterval)
+}
+
+// Record records the given number of bytes against the key.
+func (hk *HotKeys) Record(key roachpb.Key, count float64) {
+	if count <= 0 || len(key) == 0 {
+		return
+	}
+	now := timeutil.Unix(0, hk.clock.PhysicalNow())
+
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+
+	hk.maybeRotateLocked(now)
+	hk.mu.cur.record(key, count)
+}
+
+func (hk *HotKeys) maybeRotateLocked(now time.Time) {
+	sinceRotate := now.Sub(hk.mu.lastRotate)
+	if sinceRotate < replStatsRotateInterval {
+		return
+	}
+	hk.mu.hasPrev = sinceRotate < 2*replStatsRotateInterval
+	if hk.mu.hasPrev {
+		hk.mu.prev = hk.mu.cur
+	} else {
+		// The current window is too old to be worth keeping as well.
+		hk.mu.prev.reset()
+	}
+	hk.mu.cur = hotKeysWindow{}
+	hk.mu.lastRotate = now
+}
+
+// TopK returns up to k of the hottest keys, sorted by decreasing rate, that are
+// contained in the given span.
+func (hk *HotKeys) TopK(k int, span roachpb.Span) []HotKey {
+	now := timeutil.Unix(0, hk.clock.PhysicalNow())
+
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+
+	hk.maybeRotateLocked(now)
+
+	duration := now.Sub(hk.mu.lastRotate)
+	if hk.mu.hasPrev {
+		duration += replStatsRotateInterval
+	}
+	if duration <= 0 {
+		return nil
+	}
+
+	counts := make(map[string]float64, len(hk.mu.cur.counts)+len(hk.mu.prev.counts))
+	for _, w := range []*hotKeysWindow{&hk.mu.cur, &hk.mu.prev} {
+		for key, c := range w.counts {
+			if span.ContainsKey(roachpb.Key(key)) {
+				counts[key] += *c
+			}
+		}
+	}
+
+	res := make([]HotKey, 0, len(counts))
+	for key, c := range counts {
+		res = append(res, HotKey{
+			Key:            roachpb.Key(key),
+			BytesPerSecond: c / duration.Seconds(),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].BytesPerSecond != res[j].BytesPerSecond {
+			return res[i].BytesPerSecond > res[j].BytesPerSecond
+		}
+		return bytes.Compare(res[i].Key, res[j].Key) < 0
+	})
+	if len(res) > k {
+		res = res[:k]
+	}
+	return res
+}
+
+// Merge records the keys tracked by other into the receiver, for the purposes
+// of merging a range. The keys tracked by other are cleared.
+func (hk *HotKeys) Merge(other *HotKeys) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+
+	for key, c := range other.mu.cur.counts {
+		hk.mu.cur.record([]byte(key), *c)
+	}
+	for key, c := range other.mu.prev.counts {
+		hk.mu.prev.record([]byte(key), *c)
+	}
+	other.mu.cur.reset()
+	other.mu.prev.reset()
+}
+
+// Split copies the keys tracked by the receiver into other, for the purposes
+// of splitting a range. Both sides keep all of the keys; keys which fall
+// outside of a replica's span are filtered out by TopK and age out of the
+// sketch as windows rotate.
+func (hk *HotKeys) Split(other *HotKeys) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+
+	other.mu.cur.reset()
+	other.mu.prev.reset()
+	for key, c := range hk.mu.cur.counts {
+		other.mu.cur.record([]byte(key), *c)
+	}
+	for key, c := range hk.mu.prev.counts {
+		other.mu.prev.record([]byte(key), *c)
+	}
+	other.mu.lastRotate = hk.mu.lastRotate
+	other.mu.hasPrev = hk.mu.hasPrev
+}
+
+// Reset clears all of the tracked keys.
+func (hk *HotKeys) Reset() {
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+
+	hk.mu.cur.reset()
+	hk.mu.prev.reset()
+	hk.mu.hasPrev = false
+	hk.mu.lastRotate = timeutil.Unix(0, hk.clock.PhysicalNow())
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package replicastats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotKeys(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	manual := timeutil.NewManualTime(timeutil.Unix(0, 123))
+	clock := hlc.NewClock(manual, time.Nanosecond /* maxOffset */)
+	everything := roachpb.Span{Key: roachpb.KeyMin, EndKey: roachpb.KeyMax}
+
+	hk := NewHotKeys(clock)
+	manual.Advance(10 * time.Second)
+
+	// Record a few heavy keys amongst many more light keys than the sketch has
+	// room for. The heavy keys must survive the evictions.
+	for i := 0; i < 10*hotKeysCapacity; i++ {
+		hk.Record(roachpb.Key("a"), 100)
+		hk.Record(roachpb.Key("b"), 50)
+		hk.Record(roachpb.Key(fmt.Sprintf("light%04d", i)), 1)
+	}
+	top := hk.TopK(2, everything)
+	require.Len(t, top, 2)
+	require.Equal(t, roachpb.Key("a"), top[0].Key)
+	require.Equal(t, roachpb.Key("b"), top[1].Key)
+	require.GreaterOrEqual(t, top[0].BytesPerSecond, float64(100*10*hotKeysCapacity)/10)
+
+	// Keys outside of the span are filtered out.
+	top = hk.TopK(2, roachpb.Span{Key: roachpb.Key("b"), EndKey: roachpb.Key("c")})
+	require.Len(t, top, 1)
+	require.Equal(t, roachpb.Key("b"), top[0].Key)
+
+	// Splitting copies the keys to the other side.
+	other := NewHotKeys(clock)
+	hk.Split(other)
+	require.Equal(t, hk.TopK(2, everything), other.TopK(2, everything))
+
+	// Merging combines the counts, and clears the other side.
+	hk.Merge(other)
+	require.Empty(t, other.TopK(2, everything))
+	require.Equal(t, roachpb.Key("a"), hk.TopK(1, everything)[0].Key)
+
+	// Keys age out of the sketch after two rotations.
+	manual.Advance(replStatsRotateInterval)
+	hk.Record(roachpb.Key("c"), 1)
+	require.Len(t, hk.TopK(10, roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("b")}), 1)
+	manual.Advance(replStatsRotateInterval)
+	hk.Record(roachpb.Key("c"), 1)
+	top = hk.TopK(10, everything)
+	require.Len(t, top, 1)
+	require.Equal(t, roachpb.Key("c"), top[0].Key)
+
+	hk.Reset()
+	require.Empty(t, hk.TopK(10, everything))
+
+	// Empty keys and non-positive counts are ignored.
+	manual.Advance(time.Second)
+	hk.Record(roachpb.KeyMin, 1)
+	hk.Record(roachpb.Key("d"), 0)
+	require.Empty(t, hk.TopK(10, everything))
+
+	// One in every hotKeysSampleInterval batches is sampled, and the counts of
+	// the sampled batches are scaled up accordingly.
+	var sampled int
+	for i := 0; i < 10*hotKeysSampleInterval; i++ {
+		if hk.ShouldSample() {
+			sampled++
+			hk.RecordSample(roachpb.Key("e"), 1)
+		}
+	}
+	require.Equal(t, 10, sampled)
+	top = hk.TopK(1, everything)
+	require.Len(t, top, 1)
+	require.Equal(t, float64(10*hotKeysSampleInterval), top[0].BytesPerSecond)
+}
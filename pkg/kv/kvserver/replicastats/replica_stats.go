@@ -57,6 +57,9 @@ type LocalityOracle func(roachpb.NodeID) string
 // PerLocalityCounts maps from the string representation of a locality to count.
 type PerLocalityCounts map[string]float64
 
+// PerGatewayCounts maps from the node ID of a request gateway to count.
+type PerGatewayCounts map[roachpb.NodeID]float64
+
 // ReplicaStats maintains statistics about the work done by a replica. Its
 // initial use is tracking the number of requests received from each
 // cluster locality in order to inform lease transfer decisions.
@@ -88,12 +91,14 @@ type ReplicaStats struct {
 
 type replicaStatsRecord struct {
 	localityCounts PerLocalityCounts
+	gatewayCounts  PerGatewayCounts
 	sum            float64
 	active         bool
 }
 
 func (rsr *replicaStatsRecord) reset() {
 	rsr.sum = 0
+	rsr.resetGatewayCounts()
 
 	if len(rsr.localityCounts) == 0 {
 		return
@@ -122,6 +127,21 @@ func (rsr *replicaStatsRecord) reset() {
 
 }
 
+// resetGatewayCounts zeroes out the per-gateway counts of the record, reusing
+// the existing map in the same way as the per-locality counts.
+func (rsr *replicaStatsRecord) resetGatewayCounts() {
+	for _, v := range rsr.gatewayCounts {
+		if v == 0 {
+			rsr.gatewayCounts = make(PerGatewayCounts)
+			return
+		}
+	}
+
+	for k := range rsr.gatewayCounts {
+		rsr.gatewayCounts[k] = 0
+	}
+}
+
 // activate sets the active indicator on the record. It assumes that the record
 // is empty and does not clear any fields.
 func (rsr *replicaStatsRecord) activate() {
@@ -151,6 +171,9 @@ func (rsr *replicaStatsRecord) mergeReplicaStatsRecords(other *replicaStatsRecor
 	for locality, count := range other.localityCounts {
 		rsr.localityCounts[locality] += count
 	}
+	for gateway, count := range other.gatewayCounts {
+		rsr.gatewayCounts[gateway] += count
+	}
 }
 
 func (rsr *replicaStatsRecord) split(other *replicaStatsRecord) {
@@ -161,6 +184,10 @@ func (rsr *replicaStatsRecord) split(other *replicaStatsRecord) {
 		rsr.localityCounts[locality] = count / 2.0
 		other.localityCounts[locality] = rsr.localityCounts[locality]
 	}
+	for gateway, count := range rsr.gatewayCounts {
+		rsr.gatewayCounts[gateway] = count / 2.0
+		other.gatewayCounts[gateway] = rsr.gatewayCounts[gateway]
+	}
 }
 
 // NewReplicaStats constructs a new ReplicaStats tracker.
@@ -172,7 +199,10 @@ func NewReplicaStats(clock *hlc.Clock, getNodeLocality LocalityOracle) *ReplicaS
 
 	rs.Mu.lastRotate = timeutil.Unix(0, rs.clock.PhysicalNow())
 	for i := range rs.Mu.records {
-		rs.Mu.records[i] = &replicaStatsRecord{localityCounts: make(PerLocalityCounts)}
+		rs.Mu.records[i] = &replicaStatsRecord{
+			localityCounts: make(PerLocalityCounts),
+			gatewayCounts:  make(PerGatewayCounts),
+		}
 	}
 	// Set the first record to active. All other records will be initially
 	// inactive and empty.
@@ -261,6 +291,11 @@ func (rs *ReplicaStats) RecordCount(count float64, nodeID roachpb.NodeID) {
 	record := rs.Mu.records[rs.Mu.idx]
 	record.sum += count
 	record.localityCounts[locality] += count
+	// Requests which don't originate from a gateway, such as the writes applied
+	// by followers, are recorded against node 0.
+	if nodeID != 0 {
+		record.gatewayCounts[nodeID] += count
+	}
 }
 
 func (rs *ReplicaStats) maybeRotateLocked(now time.Time) {
@@ -282,6 +317,44 @@ func (rs *ReplicaStats) rotateLocked() {
 // stats are exponentially decayed such that newer requests are weighted more
 // heavily than older requests.
 func (rs *ReplicaStats) PerLocalityDecayingRate() (PerLocalityCounts, time.Duration) {
+	counts := make(PerLocalityCounts)
+	dur := rs.decayingRate(func(cur *replicaStatsRecord, decay float64) {
+		for k, v := range cur.localityCounts {
+			counts[k] += v * decay
+		}
+	}, func(secs float64) {
+		for k := range counts {
+			counts[k] = counts[k] / secs
+		}
+	})
+	return counts, dur
+}
+
+// PerGatewayDecayingRate returns the counts-per-second for each gateway node
+// that requests were received from and the amount of time over which the stats
+// were accumulated. The stats are decayed in the same way as
+// PerLocalityDecayingRate.
+func (rs *ReplicaStats) PerGatewayDecayingRate() (PerGatewayCounts, time.Duration) {
+	counts := make(PerGatewayCounts)
+	dur := rs.decayingRate(func(cur *replicaStatsRecord, decay float64) {
+		for k, v := range cur.gatewayCounts {
+			counts[k] += v * decay
+		}
+	}, func(secs float64) {
+		for k := range counts {
+			counts[k] = counts[k] / secs
+		}
+	})
+	return counts, dur
+}
+
+// decayingRate calls accumulate with each active record and its decay factor,
+// then calls divide with the decayed duration in seconds over which the counts
+// were accumulated, if positive. It returns the amount of time since the stats
+// were last reset.
+func (rs *ReplicaStats) decayingRate(
+	accumulate func(cur *replicaStatsRecord, decay float64), divide func(secs float64),
+) time.Duration {
 	now := timeutil.Unix(0, rs.clock.PhysicalNow())
 
 	rs.Mu.Lock()
@@ -294,7 +367,6 @@ func (rs *ReplicaStats) PerLocalityDecayingRate() (PerLocalityCounts, time.Durat
 	timeSinceRotate := now.Sub(rs.Mu.lastRotate)
 	fractionOfRotation := float64(timeSinceRotate) / float64(replStatsRotateInterval)
 
-	counts := make(PerLocalityCounts)
 	var duration time.Duration
 	for i := range rs.Mu.records {
 		// We have to add len(rs.mu.requests) to the numerator to avoid getting a
@@ -307,18 +379,14 @@ func (rs *ReplicaStats) PerLocalityDecayingRate() (PerLocalityCounts, time.Durat
 			} else {
 				duration += time.Duration(float64(replStatsRotateInterval) * decay)
 			}
-			for k, v := range cur.localityCounts {
-				counts[k] += v * decay
-			}
+			accumulate(cur, decay)
 		}
 	}
 
 	if duration.Seconds() > 0 {
-		for k := range counts {
-			counts[k] = counts[k] / duration.Seconds()
-		}
+		divide(duration.Seconds())
 	}
-	return counts, now.Sub(rs.Mu.lastReset)
+	return now.Sub(rs.Mu.lastReset)
 }
 
 // SumLocked returns the sum of all queries currently recorded.
@@ -581,11 +581,45 @@ func TestReplicaStatsRecordAggregate(t *testing.T) {
 		awsLocalities[2]: 2 * expectedSum,
 		awsLocalities[3]: 3 * expectedSum,
 	}
+	expectedGatewayCounts := PerGatewayCounts{
+		1: 1 * expectedSum,
+		2: 2 * expectedSum,
+		3: 3 * expectedSum,
+	}
 	expectedStatsRecord := &replicaStatsRecord{
 		localityCounts: expectedLocalityCounts,
+		gatewayCounts:  expectedGatewayCounts,
 		sum:            expectedSum * 6,
 		active:         true,
 	}
 
 	require.Equal(t, expectedStatsRecord, rs.Mu.records[rs.Mu.idx])
 }
+
+// TestReplicaStatsPerGatewayDecayingRate asserts that requests are tracked per
+// gateway node, and that requests without a gateway are not.
+func TestReplicaStatsPerGatewayDecayingRate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	manual := timeutil.NewManualTime(timeutil.Unix(0, 123))
+	clock := hlc.NewClock(manual, time.Nanosecond /* maxOffset */)
+	rs := NewReplicaStats(clock, nil /* getNodeLocality */)
+
+	rs.RecordCount(1, 0)
+	rs.RecordCount(1, 1)
+	rs.RecordCount(3, 2)
+	manual.Advance(time.Second)
+
+	counts, dur := rs.PerGatewayDecayingRate()
+	require.Equal(t, time.Second, dur)
+	require.Len(t, counts, 2)
+	require.InDelta(t, 3*counts[1], counts[2], 1e-9)
+	localityCounts, _ := rs.PerLocalityDecayingRate()
+	require.InDelta(t, 5*counts[1], localityCounts[""], 1e-9)
+
+	rs.ResetRequestCounts()
+	counts, _ = rs.PerGatewayDecayingRate()
+	require.Zero(t, counts[1])
+	require.Zero(t, counts[2])
+}
@@ -23,6 +23,31 @@ import (
 const minSplitSuggestionInterval = time.Minute
 const minQueriesPerSecondSampleDuration = time.Second
 
+// SplitObjective is the load that a Decider tries to balance across the ranges
+// resulting from a load-based split.
+type SplitObjective int
+
+const (
+	// SplitQPS splits ranges so as to balance their requests per second.
+	SplitQPS SplitObjective = iota
+	// SplitCPU splits ranges so as to balance the CPU time spent evaluating
+	// their requests, which matters for expensive requests (e.g. wide scans)
+	// that can overload a range at a low request rate.
+	SplitCPU
+)
+
+// String implements the fmt.Stringer interface.
+func (o SplitObjective) String() string {
+	switch o {
+	case SplitQPS:
+		return "qps"
+	case SplitCPU:
+		return "cpu"
+	default:
+		return "unknown"
+	}
+}
+
 // A Decider collects measurements about the activity (measured in qps) on a
 // Replica and, assuming that qps thresholds are exceeded, tries to determine a
 // split key that would approximately result in halving the load on each of the
@@ -48,10 +73,18 @@ const minQueriesPerSecondSampleDuration = time.Second
 // prevent load-based splits from being merged away until the resulting ranges
 // have consistently remained below a certain QPS threshold for a sufficiently
 // long period of time.
+//
+// If the objective supplied to InitObjective is SplitCPU, the CPU time spent
+// on the Replica's requests, recorded through RecordCPU, is used instead of
+// their count to decide when to look for a split key and where to split. QPS
+// samples keep being recorded in either case, since they are also used to
+// decide when ranges can be merged.
 type Decider struct {
-	intn         func(n int) int      // supplied to Init
-	qpsThreshold func() float64       // supplied to Init
-	qpsRetention func() time.Duration // supplied to Init
+	intn         func(n int) int       // supplied to Init
+	qpsThreshold func() float64        // supplied to Init
+	qpsRetention func() time.Duration  // supplied to Init
+	objective    func() SplitObjective // supplied to InitObjective
+	cpuThreshold func() float64        // supplied to InitObjective
 
 	mu struct {
 		syncutil.Mutex
@@ -61,9 +94,21 @@ type Decider struct {
 		lastQPS         float64   // last reqs/s rate as of lastQPSRollover
 		count           int64     // number of requests recorded since last rollover
 
+		// Fields tracking the current cpu sample, in nanoseconds, when the
+		// objective is SplitCPU. They are rolled over along with the qps sample.
+		lastCPU  float64 // last cpu nanos/s rate as of lastQPSRollover
+		cpuCount int64   // cpu nanos recorded since last rollover
+
+		// objective is the objective that the splitFinder was created for.
+		objective SplitObjective
+
 		// Fields tracking historical qps samples.
 		maxQPS maxQPSTracker
 
+		// Fields tracking historical cpu samples, when the objective is SplitCPU.
+		// Like maxQPS, they are used to decide when ranges can be merged.
+		maxCPU maxQPSTracker
+
 		// Fields tracking split key suggestions.
 		splitFinder         *Finder   // populated when engaged or decided
 		lastSplitSuggestion time.Time // last stipulation to client to carry out split
@@ -85,6 +130,22 @@ func Init(
 	lbs.qpsRetention = qpsRetention
 }
 
+// InitObjective configures a Decider initialized through Init to choose its
+// objective dynamically. When the objective is SplitCPU, a split key is looked
+// for once the CPU time recorded through RecordCPU, in nanoseconds per second,
+// exceeds cpuThreshold.
+func InitObjective(lbs *Decider, objective func() SplitObjective, cpuThreshold func() float64) {
+	lbs.objective = objective
+	lbs.cpuThreshold = cpuThreshold
+}
+
+func (d *Decider) objectiveLocked() SplitObjective {
+	if d.objective == nil {
+		return SplitQPS
+	}
+	return d.objective()
+}
+
 // Record notifies the Decider that 'n' operations are being carried out which
 // operate on the span returned by the supplied method. The closure will only
 // be called when necessary, that is, when the Decider is considering a split
@@ -97,11 +158,38 @@ func (d *Decider) Record(now time.Time, n int, span func() roachpb.Span) bool {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	return d.recordLocked(now, n, span)
+	return d.recordLocked(now, n, 0 /* cpuNanos */, span)
+}
+
+// RecordCPU notifies the Decider that cpuNanos nanoseconds of CPU time were
+// spent on an operation on the span returned by the supplied method. It is a
+// no-op unless the objective is SplitCPU. The return value has the same
+// meaning as Record's.
+func (d *Decider) RecordCPU(now time.Time, cpuNanos int64, span func() roachpb.Span) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.objectiveLocked() != SplitCPU || cpuNanos <= 0 {
+		return false
+	}
+	return d.recordLocked(now, 0 /* n */, cpuNanos, span)
 }
 
-func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) bool {
+func (d *Decider) recordLocked(
+	now time.Time, n int, cpuNanos int64, span func() roachpb.Span,
+) bool {
 	d.mu.count += int64(n)
+	d.mu.cpuCount += cpuNanos
+	objective := d.objectiveLocked()
+	if objective != d.mu.objective {
+		// Samples collected for another objective don't help finding a split key
+		// for this one. CPU time is only recorded for the SplitCPU objective, so
+		// the historical cpu samples must be measured over a full retention
+		// period again before they can be relied upon.
+		d.mu.objective = objective
+		d.mu.splitFinder = nil
+		d.mu.maxCPU.reset(now, d.qpsRetention())
+	}
 
 	// First compute requests per second since the last check.
 	if d.mu.lastQPSRollover.IsZero() {
@@ -111,11 +199,14 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 	if elapsedSinceLastQPS >= minQueriesPerSecondSampleDuration {
 		// Update the latest QPS and reset the time and request counter.
 		d.mu.lastQPS = (float64(d.mu.count) / float64(elapsedSinceLastQPS)) * 1e9
+		d.mu.lastCPU = (float64(d.mu.cpuCount) / float64(elapsedSinceLastQPS)) * 1e9
 		d.mu.lastQPSRollover = now
 		d.mu.count = 0
+		d.mu.cpuCount = 0
 
 		// Record the latest QPS sample in the historical tracker.
 		d.mu.maxQPS.record(now, d.qpsRetention(), d.mu.lastQPS)
+		d.mu.maxCPU.record(now, d.qpsRetention(), d.mu.lastCPU)
 
 		// If the QPS for the range exceeds the threshold, start actively
 		// tracking potential for splitting this range based on load.
@@ -123,7 +214,11 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 		// begin to Record requests so it can find a split point. If a
 		// splitFinder already exists, we check if a split point is ready
 		// to be used.
-		if d.mu.lastQPS >= d.qpsThreshold() {
+		load, threshold := d.mu.lastQPS, d.qpsThreshold()
+		if objective == SplitCPU {
+			load, threshold = d.mu.lastCPU, d.cpuThreshold()
+		}
+		if load >= threshold {
 			if d.mu.splitFinder == nil {
 				d.mu.splitFinder = NewFinder(now)
 			}
@@ -132,10 +227,20 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 		}
 	}
 
-	if d.mu.splitFinder != nil && n != 0 {
+	// Requests are only sampled for the current objective: their count for
+	// SplitQPS, and their CPU time for SplitCPU.
+	weight := int64(n)
+	if objective == SplitCPU {
+		weight = cpuNanos
+	}
+	if d.mu.splitFinder != nil && weight != 0 {
 		s := span()
 		if s.Key != nil {
-			d.mu.splitFinder.Record(span(), d.intn)
+			if objective == SplitCPU {
+				d.mu.splitFinder.RecordWeighted(s, weight, d.intn)
+			} else {
+				d.mu.splitFinder.Record(s, d.intn)
+			}
 		}
 		if now.Sub(d.mu.lastSplitSuggestion) > minSplitSuggestionInterval && d.mu.splitFinder.Ready(now) && d.mu.splitFinder.Key() != nil {
 			d.mu.lastSplitSuggestion = now
@@ -155,15 +260,35 @@ func (d *Decider) RecordMax(now time.Time, qps float64) {
 	d.mu.maxQPS.record(now, d.qpsRetention(), qps)
 }
 
+// RecordMaxCPU adds a CPU measurement, in nanoseconds per second, directly into
+// the Decider's historical CPU tracker. The sample is considered to have been
+// captured at the provided time.
+func (d *Decider) RecordMaxCPU(now time.Time, cpu float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.mu.maxCPU.record(now, d.qpsRetention(), cpu)
+}
+
 // LastQPS returns the most recent QPS measurement.
 func (d *Decider) LastQPS(now time.Time) float64 {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.recordLocked(now, 0, nil) // force QPS computation
+	d.recordLocked(now, 0, 0, nil) // force QPS computation
 	return d.mu.lastQPS
 }
 
+// LastCPU returns the most recent CPU measurement, in nanoseconds per second.
+// It is only tracked when the objective is SplitCPU.
+func (d *Decider) LastCPU(now time.Time) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.recordLocked(now, 0, 0, nil) // force CPU computation
+	return d.mu.lastCPU
+}
+
 // MaxQPS returns the maximum QPS measurement recorded over the retention
 // period. If the Decider has not been recording for a full retention period,
 // the method returns false.
@@ -171,10 +296,25 @@ func (d *Decider) MaxQPS(now time.Time) (float64, bool) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.recordLocked(now, 0, nil) // force QPS computation
+	d.recordLocked(now, 0, 0, nil) // force QPS computation
 	return d.mu.maxQPS.maxQPS(now, d.qpsRetention())
 }
 
+// MaxCPU returns the maximum CPU measurement, in nanoseconds per second,
+// recorded over the retention period. If the Decider has not been recording CPU
+// for a full retention period, which is only done when the objective is
+// SplitCPU, the method returns false.
+func (d *Decider) MaxCPU(now time.Time) (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.recordLocked(now, 0, 0, nil) // force CPU computation
+	if d.mu.objective != SplitCPU {
+		return 0, false
+	}
+	return d.mu.maxCPU.maxQPS(now, d.qpsRetention())
+}
+
 // MaybeSplitKey returns a key to perform a split at. The return value will be
 // nil if either the Decider hasn't decided that a split should be carried out
 // or if it wasn't able to determine a suitable split key.
@@ -186,7 +326,7 @@ func (d *Decider) MaybeSplitKey(now time.Time) roachpb.Key {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.recordLocked(now, 0, nil)
+	d.recordLocked(now, 0, 0, nil)
 	if d.mu.splitFinder != nil && d.mu.splitFinder.Ready(now) {
 		// We've found a key to split at. This key might be in the middle of a
 		// SQL row. If we fail to rectify that, we'll cause SQL crashes:
@@ -237,7 +377,10 @@ func (d *Decider) Reset(now time.Time) {
 	d.mu.lastQPSRollover = time.Time{}
 	d.mu.lastQPS = 0
 	d.mu.count = 0
+	d.mu.lastCPU = 0
+	d.mu.cpuCount = 0
 	d.mu.maxQPS.reset(now, d.qpsRetention())
+	d.mu.maxCPU.reset(now, d.qpsRetention())
 	d.mu.splitFinder = nil
 	d.mu.lastSplitSuggestion = time.Time{}
 }
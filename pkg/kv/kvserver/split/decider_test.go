@@ -11,6 +11,7 @@
 package split
 
 import (
+	"fmt"
 	"math"
 	"math/rand"
 	"testing"
@@ -232,6 +233,96 @@ func TestDecider_MaxQPS(t *testing.T) {
 	assertMaxQPS(25000, 6, true)
 }
 
+// TestDeciderCPUObjective verifies that, with the SplitCPU objective, a split
+// is looked for based on CPU time rather than on QPS, and that the split key
+// balances CPU time rather than requests.
+func TestDeciderCPUObjective(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	intn := rand.New(rand.NewSource(11)).Intn
+
+	var d Decider
+	Init(&d, intn, func() float64 { return 1e9 }, func() time.Duration { return time.Second })
+	objective := SplitQPS
+	InitObjective(&d, func() SplitObjective { return objective }, func() float64 { return 1e6 })
+
+	// Requests are spread uniformly across 100 keys, but the ones on the last 10
+	// keys are 9 times as expensive as the others. Half of the CPU time is spent
+	// on keys 00-89, and half on keys 90-99.
+	op := func(i int) func() roachpb.Span {
+		return func() roachpb.Span { return roachpb.Span{Key: roachpb.Key(fmt.Sprintf("%02d", i))} }
+	}
+	cost := func(i int) int64 {
+		if i >= 90 {
+			return 9 * int64(time.Millisecond)
+		}
+		return int64(time.Millisecond)
+	}
+	run := func(seconds int) roachpb.Key {
+		var now time.Time
+		for s := 0; s < seconds; s++ {
+			for i := 0; i < 100; i++ {
+				now = now.Add(10 * time.Millisecond)
+				d.Record(now, 1, op(i))
+				d.RecordCPU(now, cost(i), op(i))
+			}
+			if k := d.MaybeSplitKey(now); k != nil {
+				return k
+			}
+		}
+		return nil
+	}
+
+	// The QPS is below the threshold, and CPU time is ignored.
+	require.Nil(t, run(30))
+	require.Zero(t, d.LastCPU(time.Time{}.Add(30*time.Second)))
+
+	objective = SplitCPU
+	d.Reset(time.Time{})
+	k := run(60)
+	require.NotNil(t, k)
+	// With requests weighted equally, the split key would be around key 50.
+	require.True(t, string(k) >= "68" && string(k) <= "92", "unexpected split key %s", k)
+}
+
+func TestDecider_MaxCPU(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	intn := rand.New(rand.NewSource(11)).Intn
+
+	var d Decider
+	Init(&d, intn, func() float64 { return 100.0 }, func() time.Duration { return 10 * time.Second })
+	objective := SplitCPU
+	InitObjective(&d, func() SplitObjective { return objective }, func() float64 { return 1e9 })
+
+	assertMaxCPU := func(i int, expMaxCPU float64, expOK bool) {
+		t.Helper()
+		maxCPU, ok := d.MaxCPU(ms(i))
+		assert.InDelta(t, expMaxCPU, maxCPU, 1)
+		assert.Equal(t, expOK, ok)
+	}
+
+	assertMaxCPU(1000, 0, false)
+
+	d.RecordCPU(ms(1500), 2e6, nil)
+	d.RecordCPU(ms(2000), 2e6, nil)
+	d.RecordCPU(ms(5000), 3e6, nil)
+
+	assertMaxCPU(10000, 4e6, false)
+	assertMaxCPU(11000, 4e6, true)
+
+	// Add in a CPU reading directly.
+	d.RecordMaxCPU(ms(12000), 5e6)
+	assertMaxCPU(13000, 5e6, true)
+
+	// CPU time is not measured with the qps objective.
+	objective = SplitQPS
+	assertMaxCPU(14000, 0, false)
+
+	// After switching back, a full retention period must be measured again.
+	objective = SplitCPU
+	assertMaxCPU(15000, 0, false)
+	assertMaxCPU(25000, 0, true)
+}
+
 func TestDeciderCallsEnsureSafeSplitKey(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	intn := rand.New(rand.NewSource(11)).Intn
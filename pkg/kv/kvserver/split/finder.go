@@ -55,6 +55,10 @@ const (
 type sample struct {
 	key                    roachpb.Key
 	left, right, contained int
+	// count is the number of spans recorded in the counters. It is only
+	// maintained by RecordWeighted, as it otherwise equals the sum of the
+	// counters.
+	count int
 }
 
 // Finder is a structure that is used to determine the split point
@@ -63,6 +67,8 @@ type Finder struct {
 	startTime time.Time
 	samples   [splitKeySampleSize]sample
 	count     int
+	// weighted is set if spans are recorded through RecordWeighted.
+	weighted bool
 }
 
 // NewFinder initiates a Finder with the given time.
@@ -84,7 +90,22 @@ func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
 	if f == nil {
 		return
 	}
+	f.record(span, 1 /* weight */, intNFn)
+}
+
+// RecordWeighted is like Record, but the span's contribution to the counters
+// of the samples is weighted, e.g. by the CPU time spent on its request. Keys
+// are still sampled uniformly among the recorded spans. A Finder should only
+// be used with one of Record and RecordWeighted.
+func (f *Finder) RecordWeighted(span roachpb.Span, weight int64, intNFn func(int) int) {
+	if f == nil {
+		return
+	}
+	f.weighted = true
+	f.record(span, int(weight), intNFn)
+}
 
+func (f *Finder) record(span roachpb.Span, weight int, intNFn func(int) int) {
 	var idx int
 	count := f.count
 	f.count++
@@ -93,8 +114,11 @@ func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
 	} else if idx = intNFn(count); idx >= splitKeySampleSize {
 		// Increment all existing keys' counters.
 		for i := range f.samples {
+			if f.weighted {
+				f.samples[i].count++
+			}
 			if span.ProperlyContainsKey(f.samples[i].key) {
-				f.samples[i].contained++
+				f.samples[i].contained += weight
 			} else {
 				// If the split is chosen to be here and the key is on or to the left
 				// of the start key of the span, we know that the request the span represents
@@ -103,9 +127,9 @@ func (f *Finder) Record(span roachpb.Span, intNFn func(int) int) {
 				// (and given that it is not properly contained by the span) it must mean
 				// that the request the span represents would be on the left.
 				if comp := bytes.Compare(f.samples[i].key, span.Key); comp <= 0 {
-					f.samples[i].right++
+					f.samples[i].right += weight
 				} else if comp > 0 {
-					f.samples[i].left++
+					f.samples[i].left += weight
 				}
 			}
 		}
@@ -128,7 +152,11 @@ func (f *Finder) Key() roachpb.Key {
 	var bestIdx = -1
 	var bestScore float64 = 2
 	for i, s := range f.samples {
-		if s.left+s.right+s.contained < splitKeyMinCounter {
+		recorded := s.left + s.right + s.contained
+		if f.weighted {
+			recorded = s.count
+		}
+		if recorded < splitKeyMinCounter {
 			continue
 		}
 		balanceScore := math.Abs(float64(s.left-s.right)) / float64(s.left+s.right)
@@ -738,6 +738,7 @@ type Store struct {
 	recoveryMgr        txnrecovery.Manager
 	raftEntryCache     *raftentry.Cache
 	limiters           batcheval.Limiters
+	diskBandwidth      *diskBandwidthGovernor
 	txnWaitMetrics     *txnwait.Metrics
 	sstSnapshotStorage SSTSnapshotStorage
 	protectedtsReader  spanconfig.ProtectedTSReader
@@ -1274,6 +1275,9 @@ func NewStore(
 	bulkIOWriteLimit.SetOnChange(&cfg.Settings.SV, func(ctx context.Context) {
 		s.limiters.BulkIOWriteRate.SetLimit(rate.Limit(bulkIOWriteLimit.Get(&cfg.Settings.SV)))
 	})
+	s.diskBandwidth = newDiskBandwidthGovernor(
+		&cfg.Settings.SV, s.metrics, timeutil.DefaultTimeSource{},
+	)
 	s.limiters.ConcurrentExportRequests = limit.MakeConcurrentRequestLimiter(
 		"exportRequestLimiter", int(ExportRequestsLimit.Get(&cfg.Settings.SV)),
 	)
@@ -1288,6 +1292,7 @@ func NewStore(
 	// it can clean it up. If this fails it's not a correctness issue since the
 	// storage is also cleared before receiving a snapshot.
 	s.sstSnapshotStorage = NewSSTSnapshotStorage(s.engine, s.limiters.BulkIOWriteRate)
+	s.sstSnapshotStorage.diskBandwidth = s.diskBandwidth
 	if err := s.sstSnapshotStorage.Clear(); err != nil {
 		log.Warningf(ctx, "failed to clear snapshot storage: %v", err)
 	}
@@ -3321,6 +3326,8 @@ func (s *Store) ComputeMetrics(ctx context.Context, tick int) error {
 	// Get the latest engine metrics.
 	m := s.engine.GetMetrics()
 	s.metrics.updateEngineMetrics(m)
+	_, compactedWritten := m.CompactedBytes()
+	s.diskBandwidth.meterEngineWrites(m.WAL.BytesWritten, compactedWritten)
 
 	// Get engine Env stats.
 	envStats, err := s.engine.GetEnvStats()
@@ -3413,6 +3420,14 @@ func (s *Store) ComputeStatsForKeySpan(startKey, endKey roachpb.RKey) (StoreKeyS
 // carrying out any changes, returning all trace messages collected along the way.
 // Intended to help power a debug endpoint.
 func (s *Store) AllocatorDryRun(ctx context.Context, repl *Replica) (tracingpb.Recording, error) {
+	rec, _ := s.allocatorDryRun(ctx, repl)
+	return rec, nil
+}
+
+// allocatorDryRun runs the given replica through the allocator without
+// actually carrying out any changes, returning all trace messages collected
+// along the way and the error processing the replica resulted in, if any.
+func (s *Store) allocatorDryRun(ctx context.Context, repl *Replica) (tracingpb.Recording, error) {
 	ctx, collectAndFinish := tracing.ContextWithRecordingSpan(ctx, s.cfg.AmbientCtx.Tracer, "allocator dry run")
 	defer collectAndFinish()
 	canTransferLease := func(ctx context.Context, repl *Replica) bool { return true }
@@ -3422,7 +3437,78 @@ func (s *Store) AllocatorDryRun(ctx context.Context, repl *Replica) (tracingpb.R
 	if err != nil {
 		log.Eventf(ctx, "error simulating allocator on replica %s: %s", repl, err)
 	}
-	return collectAndFinish(), nil
+	return collectAndFinish(), err
+}
+
+// AllocatorExplanation is a structured explanation of what the replicate queue
+// would do with a replica, and why.
+type AllocatorExplanation struct {
+	// Action is the action the allocator computes for the range, along with its
+	// priority.
+	Action   allocatorimpl.AllocatorAction
+	Priority float64
+	// Err is the error that processing the replica in the replicate queue
+	// would result in, if any. For example, it describes the constraints that
+	// no store is able to satisfy.
+	Err error
+	// TargetType is the type of replica that the candidates are explained for.
+	TargetType allocatorimpl.TargetReplicaType
+	// Candidates explains how the stores are ranked for the action. For
+	// removals, these are the stores of the existing replicas of the range. For
+	// rebalances, these are the stores that could replace each existing
+	// replica. Otherwise, these are all the live stores, regarded as the target
+	// of a new replica of the range.
+	Candidates []allocatorimpl.CandidateExplanation
+	// Throttled lists the reasons for which stores are not currently
+	// considered as targets.
+	Throttled storepool.ThrottledStoreReasons
+}
+
+// AllocatorExplain runs the given replica through the allocator without
+// actually carrying out any changes, like AllocatorDryRun, and returns the
+// trace messages collected along the way as well as a structured explanation
+// of its decision. Intended to help debug ranges which aren't rebalanced or
+// up-replicated as expected.
+func (s *Store) AllocatorExplain(
+	ctx context.Context, repl *Replica,
+) (tracingpb.Recording, AllocatorExplanation) {
+	desc, conf := repl.DescAndSpanConfig()
+	var e AllocatorExplanation
+	e.Action, e.Priority = s.allocator.ComputeAction(ctx, conf, desc)
+	switch e.Action {
+	case allocatorimpl.AllocatorAddNonVoter,
+		allocatorimpl.AllocatorReplaceDeadNonVoter,
+		allocatorimpl.AllocatorReplaceDecommissioningNonVoter,
+		allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveDeadNonVoter,
+		allocatorimpl.AllocatorRemoveDecommissioningNonVoter:
+		e.TargetType = allocatorimpl.NonVoterTarget
+	default:
+		e.TargetType = allocatorimpl.VoterTarget
+	}
+
+	var rec tracingpb.Recording
+	rec, e.Err = s.allocatorDryRun(ctx, repl)
+
+	voters, nonVoters := desc.Replicas().VoterDescriptors(), desc.Replicas().NonVoterDescriptors()
+	switch e.Action {
+	case allocatorimpl.AllocatorRemoveVoter,
+		allocatorimpl.AllocatorRemoveDeadVoter,
+		allocatorimpl.AllocatorRemoveDecommissioningVoter,
+		allocatorimpl.AllocatorRemoveNonVoter,
+		allocatorimpl.AllocatorRemoveDeadNonVoter,
+		allocatorimpl.AllocatorRemoveDecommissioningNonVoter:
+		e.Candidates = s.allocator.ExplainRemoveTarget(ctx, conf, voters, nonVoters, e.TargetType)
+	case allocatorimpl.AllocatorConsiderRebalance:
+		e.Candidates, e.Throttled = s.allocator.ExplainRebalanceTarget(
+			ctx, conf, voters, nonVoters, e.TargetType,
+		)
+	default:
+		e.Candidates, e.Throttled = s.allocator.ExplainAllocateTarget(
+			ctx, conf, voters, nonVoters, e.TargetType,
+		)
+	}
+	return rec, e
 }
 
 // Enqueue runs the given replica through the requested queue. If `async` is
@@ -3597,6 +3683,17 @@ func (s *Store) registerLeaseholder(
 	}
 }
 
+// expediteClosedTimestampPublish asks the node's closed timestamp side
+// transport to publish closed timestamps as soon as possible, because the
+// provided range just acquired its lease through a lease transfer.
+func (s *Store) expediteClosedTimestampPublish(
+	ctx context.Context, rangeID roachpb.RangeID, leaseSeq roachpb.LeaseSequence,
+) {
+	if s.ctSender != nil {
+		s.ctSender.OnLeaseTransferHandoff(ctx, rangeID, leaseSeq)
+	}
+}
+
 // unregisterLeaseholder unregisters the provided replica from node's closed
 // timestamp side transport if it had been previously registered as a
 // leaseholder.
@@ -3775,6 +3872,7 @@ var _ KVAdmissionController = &KVAdmissionControllerImpl{}
 
 type admissionHandle struct {
 	tenantID                           roachpb.TenantID
+	databaseID                         uint32
 	callAdmittedWorkDoneOnKVAdmissionQ bool
 	storeAdmissionQ                    *admission.StoreWorkQueue
 	storeWorkHandle                    admission.StoreWorkHandle
@@ -3823,6 +3921,12 @@ func (n *KVAdmissionControllerImpl) AdmitKVWork(
 			CreateTime:      createTime,
 			BypassAdmission: bypassAdmission,
 		}
+		if roachpb.IsSystemTenantID(tenantID.ToUint64()) && n.kvAdmissionQ.DatabaseFairnessEnabled() {
+			// Order the work of the system tenant by the database that it reads
+			// from, see admission.SQLDatabaseFairnessEnabled.
+			admissionInfo.DatabaseID = ba.AdmissionHeader.DatabaseID
+			ah.databaseID = admissionInfo.DatabaseID
+		}
 		var err error
 		// Don't subject HeartbeatTxnRequest to the storeAdmissionQ. Even though
 		// it would bypass admission, it would consume a slot. When writes are
@@ -3869,7 +3973,7 @@ func (n *KVAdmissionControllerImpl) AdmittedKVWorkDone(
 ) {
 	ah := handle.(admissionHandle)
 	if ah.callAdmittedWorkDoneOnKVAdmissionQ {
-		n.kvAdmissionQ.AdmittedWorkDone(ah.tenantID)
+		n.kvAdmissionQ.AdmittedWorkDone(ah.tenantID, ah.databaseID)
 	}
 	if ah.storeAdmissionQ != nil {
 		var doneInfo admission.StoreWorkDoneInfo
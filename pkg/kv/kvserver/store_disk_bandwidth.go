@@ -0,0 +1,297 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// diskBandwidthBackgroundRate is the rate limit shared by all background
+// writes to a store's disk.
+var diskBandwidthBackgroundRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.store.disk_bandwidth.background.max_rate",
+	"if positive, the rate limit (bytes/sec) shared by compactions, snapshots and SST "+
+		"ingestion on each store; compactions and snapshots are never delayed by it, but "+
+		"the bandwidth they use delays SST ingestion",
+	0,
+	settings.NonNegativeInt,
+)
+
+// diskBandwidthSnapshotRate is the rate limit for writing received snapshots
+// to a store's disk.
+var diskBandwidthSnapshotRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.store.disk_bandwidth.snapshot.max_rate",
+	"if positive, the rate limit (bytes/sec) for writing received snapshots to each store's disk",
+	0,
+	settings.NonNegativeInt,
+)
+
+// diskBandwidthIngestionRate is the rate limit for AddSSTable ingestion into
+// a store.
+var diskBandwidthIngestionRate = settings.RegisterByteSizeSetting(
+	settings.SystemOnly,
+	"kv.store.disk_bandwidth.ingestion.max_rate",
+	"if positive, the rate limit (bytes/sec) for SST ingestion into each store on behalf "+
+		"of bulk operations such as IMPORT, RESTORE and index backfills",
+	0,
+	settings.NonNegativeInt,
+)
+
+// diskBandwidthClass classifies the writes to a store's disk for the purpose
+// of disk bandwidth accounting.
+type diskBandwidthClass int
+
+const (
+	// diskBandwidthForeground are the writes to the WAL on behalf of regular
+	// traffic. They are metered, but never limited.
+	diskBandwidthForeground diskBandwidthClass = iota
+	// diskBandwidthCompaction are the writes of storage engine compactions. They
+	// are metered and charged against the background limit, but never delayed
+	// since falling behind on compactions increases read amplification and
+	// eventually stalls foreground writes.
+	diskBandwidthCompaction
+	// diskBandwidthSnapshot are the writes of received snapshots to the
+	// snapshot scratch space. They are only delayed by the snapshot limit, since
+	// snapshots are needed to recover from lost replicas, and charged against the
+	// background limit.
+	diskBandwidthSnapshot
+	// diskBandwidthIngestion are the SSTs ingested by AddSSTable requests.
+	diskBandwidthIngestion
+	numDiskBandwidthClasses
+)
+
+func (c diskBandwidthClass) String() string {
+	switch c {
+	case diskBandwidthForeground:
+		return "foreground"
+	case diskBandwidthCompaction:
+		return "compaction"
+	case diskBandwidthSnapshot:
+		return "snapshot"
+	case diskBandwidthIngestion:
+		return "ingestion"
+	default:
+		return "unknown"
+	}
+}
+
+// diskBandwidthGovernor meters the disk bandwidth used by a store per class of
+// writes and limits the bandwidth of background work, so that compactions,
+// snapshots and SST ingestion don't starve foreground writes on disks with
+// little bandwidth to spare, such as HDDs or small EBS volumes.
+//
+// Snapshots are delayed above Raft until the snapshot limit permits them, and
+// ingestion until both the ingestion limit and the shared background limit
+// permit it. Compactions and foreground writes are performed by the storage
+// engine, so their bandwidth is only observed after the fact through the
+// engine metrics. Compactions and snapshots are charged against the background
+// limit, which puts it into debt and delays subsequent ingestion. The debt is
+// capped at one second worth of writes, so that a burst of compactions doesn't
+// stall ingestion for longer than that once the burst is over.
+type diskBandwidthGovernor struct {
+	metrics    *StoreMetrics
+	timeSource timeutil.TimeSource
+	background *diskBandwidthLimiter
+	// classes contains the limiters of the classes that are delayed. It is nil
+	// for the classes that are only metered.
+	classes [numDiskBandwidthClasses]*diskBandwidthLimiter
+
+	mu struct {
+		syncutil.Mutex
+		// initialized is set once the cumulative engine metrics have been
+		// observed by meterEngineWrites, which computes deltas against them.
+		initialized        bool
+		lastWALBytes       uint64
+		lastCompactedBytes uint64
+	}
+}
+
+func newDiskBandwidthGovernor(
+	sv *settings.Values, metrics *StoreMetrics, timeSource timeutil.TimeSource,
+) *diskBandwidthGovernor {
+	g := &diskBandwidthGovernor{
+		metrics:    metrics,
+		timeSource: timeSource,
+		background: newDiskBandwidthLimiter(timeSource),
+	}
+	g.classes[diskBandwidthSnapshot] = newDiskBandwidthLimiter(timeSource)
+	g.classes[diskBandwidthIngestion] = newDiskBandwidthLimiter(timeSource)
+
+	for _, s := range []struct {
+		setting *settings.ByteSizeSetting
+		limiter *diskBandwidthLimiter
+	}{
+		{diskBandwidthBackgroundRate, g.background},
+		{diskBandwidthSnapshotRate, g.classes[diskBandwidthSnapshot]},
+		{diskBandwidthIngestionRate, g.classes[diskBandwidthIngestion]},
+	} {
+		s := s
+		s.limiter.setRate(s.setting.Get(sv))
+		s.setting.SetOnChange(sv, func(ctx context.Context) {
+			s.limiter.setRate(s.setting.Get(sv))
+		})
+	}
+	return g
+}
+
+// wait blocks until n bytes of the given background class may be written,
+// and records them in the metrics of the class. Snapshots only wait on their
+// own limit, and are charged against the background limit without waiting.
+func (g *diskBandwidthGovernor) wait(
+	ctx context.Context, class diskBandwidthClass, n int64,
+) error {
+	if n <= 0 {
+		return nil
+	}
+	begin := g.timeSource.Now()
+	if l := g.classes[class]; l != nil {
+		if err := l.wait(ctx, n); err != nil {
+			return err
+		}
+	}
+	if class == diskBandwidthSnapshot {
+		g.background.charge(n)
+	} else if err := g.background.wait(ctx, n); err != nil {
+		return err
+	}
+	waited := g.timeSource.Since(begin)
+	switch class {
+	case diskBandwidthSnapshot:
+		g.metrics.DiskBandwidthSnapshotBytes.Inc(n)
+		g.metrics.DiskBandwidthSnapshotThrottled.Inc(waited.Nanoseconds())
+	case diskBandwidthIngestion:
+		g.metrics.DiskBandwidthIngestionBytes.Inc(n)
+		g.metrics.DiskBandwidthIngestionThrottled.Inc(waited.Nanoseconds())
+	}
+	return nil
+}
+
+// meterEngineWrites accounts for the writes performed by the storage engine
+// itself, given its cumulative WAL and compaction write bytes. The bytes
+// written by compactions since the last call are charged against the
+// background limit. It is called periodically when the store's metrics are
+// computed.
+func (g *diskBandwidthGovernor) meterEngineWrites(walBytes, compactedBytes uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.mu.initialized {
+		// Only establish the baseline, since the cumulative values include the
+		// writes performed before the store was started.
+		g.mu.initialized = true
+		g.mu.lastWALBytes, g.mu.lastCompactedBytes = walBytes, compactedBytes
+		return
+	}
+	if walBytes > g.mu.lastWALBytes {
+		g.metrics.DiskBandwidthForegroundBytes.Inc(int64(walBytes - g.mu.lastWALBytes))
+	}
+	if compactedBytes > g.mu.lastCompactedBytes {
+		delta := int64(compactedBytes - g.mu.lastCompactedBytes)
+		g.metrics.DiskBandwidthCompactionBytes.Inc(delta)
+		g.background.charge(delta)
+	}
+	g.mu.lastWALBytes, g.mu.lastCompactedBytes = walBytes, compactedBytes
+}
+
+// diskBandwidthLimiter is a token bucket limiting a disk bandwidth in bytes per
+// second. Unlike a quotapool.RateLimiter, it can be charged for writes that
+// already happened without waiting, putting it into debt.
+type diskBandwidthLimiter struct {
+	timeSource timeutil.TimeSource
+	mu         struct {
+		syncutil.Mutex
+		// unlimited is set if the rate limit is not positive, in which case the
+		// token bucket is ignored.
+		unlimited bool
+		// maxDebt is the debt that charge can put the bucket into, which is the
+		// burst.
+		maxDebt quotapool.Tokens
+		tb      quotapool.TokenBucket
+	}
+}
+
+func newDiskBandwidthLimiter(timeSource timeutil.TimeSource) *diskBandwidthLimiter {
+	l := &diskBandwidthLimiter{timeSource: timeSource}
+	l.mu.unlimited = true
+	l.mu.tb.Init(0 /* rate */, 0 /* burst */, timeSource)
+	return l
+}
+
+// setRate sets the limit to the given number of bytes per second. The burst
+// is one second worth of writes. A rate that isn't positive removes the limit.
+func (l *diskBandwidthLimiter) setRate(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.unlimited = bytesPerSec <= 0
+	if l.mu.unlimited {
+		return
+	}
+	l.mu.maxDebt = quotapool.Tokens(bytesPerSec)
+	l.mu.tb.UpdateConfig(quotapool.TokensPerSecond(bytesPerSec), l.mu.maxDebt)
+}
+
+// charge removes n bytes from the bucket without waiting. The bucket is put
+// into debt by at most the burst, so that the writes waiting on it are delayed
+// by at most two seconds worth of writes once the charged writes stop.
+func (l *diskBandwidthLimiter) charge(n int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.unlimited {
+		return
+	}
+	delta := quotapool.Tokens(n)
+	if maxDelta := l.mu.tb.Available() + l.mu.maxDebt; delta > maxDelta {
+		delta = maxDelta
+	}
+	if delta > 0 {
+		l.mu.tb.Adjust(-delta)
+	}
+}
+
+// wait blocks until n bytes can be removed from the bucket and removes them.
+// Writes larger than the burst are admitted once the bucket is full, which
+// puts it into debt.
+func (l *diskBandwidthLimiter) wait(ctx context.Context, n int64) error {
+	var timer timeutil.TimerI
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for {
+		l.mu.Lock()
+		if l.mu.unlimited {
+			l.mu.Unlock()
+			return nil
+		}
+		fulfilled, tryAgainAfter := l.mu.tb.TryToFulfill(quotapool.Tokens(n))
+		l.mu.Unlock()
+		if fulfilled {
+			return nil
+		}
+		if timer == nil {
+			timer = l.timeSource.NewTimer()
+		}
+		timer.Reset(tryAgainAfter)
+		select {
+		case <-timer.Ch():
+			timer.MarkRead()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
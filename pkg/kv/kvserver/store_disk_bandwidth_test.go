@@ -0,0 +1,91 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskBandwidthGovernor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	metrics := newStoreMetrics(time.Minute)
+	mt := timeutil.NewManualTime(timeutil.Unix(0, 123))
+	g := newDiskBandwidthGovernor(&st.SV, metrics, mt)
+
+	// Without limits, writes are only metered.
+	require.NoError(t, g.wait(ctx, diskBandwidthSnapshot, 1<<20))
+	require.NoError(t, g.wait(ctx, diskBandwidthIngestion, 2<<20))
+	require.Equal(t, int64(1<<20), metrics.DiskBandwidthSnapshotBytes.Count())
+	require.Equal(t, int64(2<<20), metrics.DiskBandwidthIngestionBytes.Count())
+	require.Zero(t, metrics.DiskBandwidthSnapshotThrottled.Count())
+	require.Zero(t, metrics.DiskBandwidthIngestionThrottled.Count())
+
+	// The first observation of the engine's writes only establishes a baseline.
+	g.meterEngineWrites(100, 1000)
+	g.meterEngineWrites(150, 1200)
+	require.Equal(t, int64(50), metrics.DiskBandwidthForegroundBytes.Count())
+	require.Equal(t, int64(200), metrics.DiskBandwidthCompactionBytes.Count())
+
+	// Compactions put the background limiter into debt, which delays ingestion
+	// until it has been paid off. The debt is capped at one second worth of
+	// writes, so the ingestion is delayed by two seconds rather than the ten
+	// seconds the compactions would take at the limit.
+	diskBandwidthBackgroundRate.Override(ctx, &st.SV, 1000)
+	g.meterEngineWrites(150, 11200)
+	errC := make(chan error, 1)
+	go func() {
+		errC <- g.wait(ctx, diskBandwidthIngestion, 1000)
+	}()
+	testutils.SucceedsSoon(t, func() error {
+		select {
+		case err := <-errC:
+			require.NoError(t, err)
+			return nil
+		default:
+			mt.Advance(100 * time.Millisecond)
+			return errors.New("ingestion write not admitted yet")
+		}
+	})
+	require.Equal(t, int64(2<<20+1000), metrics.DiskBandwidthIngestionBytes.Count())
+	throttled := metrics.DiskBandwidthIngestionThrottled.Count()
+	require.GreaterOrEqual(t, throttled, time.Second.Nanoseconds())
+	require.Less(t, throttled, 3*time.Second.Nanoseconds())
+
+	// Snapshots aren't delayed by the debt of the background limiter, and only
+	// wait on their own limit.
+	g.meterEngineWrites(150, 21200)
+	require.NoError(t, g.wait(ctx, diskBandwidthSnapshot, 1000))
+	require.Equal(t, int64(1<<20+1000), metrics.DiskBandwidthSnapshotBytes.Count())
+	require.Zero(t, metrics.DiskBandwidthSnapshotThrottled.Count())
+
+	// The limit of a class applies even if the background limit is lifted.
+	diskBandwidthBackgroundRate.Override(ctx, &st.SV, 0)
+	diskBandwidthIngestionRate.Override(ctx, &st.SV, 500)
+	require.NoError(t, g.wait(ctx, diskBandwidthIngestion, 500))
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, g.wait(cancelCtx, diskBandwidthIngestion, 500), context.Canceled)
+	require.Equal(t, int64(2<<20+1500), metrics.DiskBandwidthIngestionBytes.Count())
+}
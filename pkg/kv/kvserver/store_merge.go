@@ -146,6 +146,7 @@ func (s *Store) MergeRange(
 	}
 
 	leftRepl.loadStats.merge(rightRepl.loadStats)
+	leftRepl.lastWrite.merge(&rightRepl.lastWrite)
 
 	// Clear the concurrency manager's lock and txn wait-queues to redirect the
 	// queued transactions to the left-hand replica, if necessary.
@@ -449,6 +449,7 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 		filteredStoreList := allStoresList.ExcludeInvalid(conf.VoterConstraints)
 		if sr.rq.allocator.FollowTheWorkloadPrefersLocal(
 			ctx,
+			conf,
 			filteredStoreList,
 			*localDesc,
 			candidate.StoreID,
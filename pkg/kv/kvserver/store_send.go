@@ -347,6 +347,16 @@ func (s *Store) maybeThrottleBatch(
 			return nil, err
 		}
 
+		if !t.IngestAsWrites {
+			// Wait for the disk bandwidth to ingest the SST. Requests ingested as
+			// regular writes go through the WAL and are accounted for as foreground
+			// writes instead.
+			if err := s.diskBandwidth.wait(ctx, diskBandwidthIngestion, int64(len(t.Data))); err != nil {
+				res.Release()
+				return nil, err
+			}
+		}
+
 		beforeEngineDelay := timeutil.Now()
 		s.engine.PreIngestDelay(ctx)
 		after := timeutil.Now()
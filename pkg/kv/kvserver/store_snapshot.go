@@ -136,6 +136,9 @@ type kvBatchSnapshotStrategy struct {
 	batchSize int64
 	// Limiter for sending KV batches. Only used on the sender side.
 	limiter *rate.Limiter
+	// Adjusts the limiter's rate while the recipient store is IO overloaded.
+	// Only used on the sender side, and may be nil.
+	pacer *snapshotIOPacer
 	// Only used on the sender side.
 	newBatch func() storage.Batch
 
@@ -626,6 +629,9 @@ func (kvSS *kvBatchSnapshotStrategy) sendBatch(
 	batch storage.Batch,
 	timerTag *snapshotTimingTag,
 ) error {
+	if kvSS.pacer != nil {
+		kvSS.pacer.maybeUpdate(ctx, kvSS.limiter, timeutil.Now())
+	}
 	timerTag.start("rateLimit")
 	err := kvSS.limiter.WaitN(ctx, 1)
 	timerTag.stop("rateLimit")
@@ -1075,6 +1081,7 @@ func sendSnapshotErrorWithTrace(
 // SnapshotStorePool narrows StorePool to make sendSnapshot easier to test.
 type SnapshotStorePool interface {
 	Throttle(reason storepool.ThrottleReason, why string, toStoreID roachpb.StoreID)
+	GetStoreDescriptor(storeID roachpb.StoreID) (roachpb.StoreDescriptor, bool)
 }
 
 // minSnapshotRate defines the minimum value that the rate limit for rebalance
@@ -1245,6 +1252,83 @@ var snapshotReservationQueueTimeoutFraction = settings.RegisterFloatSetting(
 	},
 )
 
+// snapshotIOOverloadPacingEnabled controls whether the rate limit of outgoing
+// snapshots is reduced while the recipient store is IO overloaded, as
+// determined by the IOThreshold in its gossiped StoreDescriptor.
+var snapshotIOOverloadPacingEnabled = settings.RegisterBoolSetting(
+	settings.SystemOnly,
+	"kv.snapshot_rate.io_overload_pacing.enabled",
+	"if enabled, the rate limit of snapshots sent to an IO overloaded store is "+
+		"divided by the store's IO overload score, down to a minimum of 1 MiB/s",
+	true,
+)
+
+// snapshotIOPacingInterval is the minimum interval at which the rate limit of
+// an outgoing snapshot is recomputed from the recipient's IO overload score.
+// The score is gossiped, so there is little point in consulting it on every
+// batch.
+const snapshotIOPacingInterval = time.Second
+
+// snapshotIOPacer adjusts the rate limit of an outgoing snapshot based on the
+// IO overload score of the recipient store. While the recipient is overloaded,
+// the rate limit is divided by its score, so that the snapshot does not add
+// much to the L0 overload that admission control is trying to alleviate on the
+// recipient. The rate limit never drops below minSnapshotRate.
+type snapshotIOPacer struct {
+	st        *cluster.Settings
+	storePool SnapshotStorePool
+	storeID   roachpb.StoreID
+	// baseRate is the configured rate limit, in bytes/sec.
+	baseRate  rate.Limit
+	batchSize int64
+	// recordPaced is called whenever the rate limit is reduced from baseRate.
+	recordPaced func()
+
+	lastUpdate time.Time
+	paced      bool
+}
+
+// targetRate returns the rate limit, in bytes/sec, to use given the current IO
+// overload score of the recipient store.
+func (p *snapshotIOPacer) targetRate() (_ rate.Limit, score float64) {
+	if !snapshotIOOverloadPacingEnabled.Get(&p.st.SV) {
+		return p.baseRate, 0
+	}
+	sd, ok := p.storePool.GetStoreDescriptor(p.storeID)
+	if !ok {
+		return p.baseRate, 0
+	}
+	score, overloaded := sd.Capacity.IOThreshold.Score()
+	if !overloaded {
+		return p.baseRate, score
+	}
+	if r := p.baseRate / rate.Limit(score); r > minSnapshotRate {
+		return r, score
+	}
+	return minSnapshotRate, score
+}
+
+// maybeUpdate recomputes the rate of the limiter, which limits batches/sec, if
+// at least snapshotIOPacingInterval has passed since the last update.
+func (p *snapshotIOPacer) maybeUpdate(ctx context.Context, limiter *rate.Limiter, now time.Time) {
+	if !p.lastUpdate.IsZero() && now.Sub(p.lastUpdate) < snapshotIOPacingInterval {
+		return
+	}
+	p.lastUpdate = now
+	target, score := p.targetRate()
+	paced := target < p.baseRate
+	if paced && !p.paced {
+		log.KvDistribution.Infof(ctx,
+			"reducing snapshot rate limit to %s/s due to IO overload score %.2f on s%d",
+			humanizeutil.IBytes(int64(target)), score, p.storeID)
+		if p.recordPaced != nil {
+			p.recordPaced()
+		}
+	}
+	p.paced = paced
+	limiter.SetLimitAt(now, target/rate.Limit(p.batchSize))
+}
+
 // snapshotSSTWriteSyncRate is the size of chunks to write before fsync-ing.
 // The default of 2 MiB was chosen to be in line with the behavior in bulk-io.
 // See sstWriteSyncRate.
@@ -1427,6 +1511,7 @@ func SendEmptySnapshot(
 		eng.NewBatch,
 		func() {},
 		nil, /* recordBytesSent */
+		nil, /* recordIOOverloadPaced */
 	)
 }
 
@@ -1435,6 +1520,10 @@ type noopStorePool struct{}
 
 func (n noopStorePool) Throttle(storepool.ThrottleReason, string, roachpb.StoreID) {}
 
+func (n noopStorePool) GetStoreDescriptor(roachpb.StoreID) (roachpb.StoreDescriptor, bool) {
+	return roachpb.StoreDescriptor{}, false
+}
+
 // sendSnapshot sends an outgoing snapshot via a pre-opened GRPC stream.
 func sendSnapshot(
 	ctx context.Context,
@@ -1447,6 +1536,7 @@ func sendSnapshot(
 	newBatch func() storage.Batch,
 	sent func(),
 	recordBytesSent snapshotRecordMetrics,
+	recordIOOverloadPaced func(),
 ) error {
 	if recordBytesSent == nil {
 		// NB: Some tests and an offline tool (ResetQuorum) call into `sendSnapshot`
@@ -1514,8 +1604,16 @@ func sendSnapshot(
 		ss = &kvBatchSnapshotStrategy{
 			batchSize: batchSize,
 			limiter:   limiter,
-			newBatch:  newBatch,
-			st:        st,
+			pacer: &snapshotIOPacer{
+				st:          st,
+				storePool:   storePool,
+				storeID:     to.StoreID,
+				baseRate:    targetRate,
+				batchSize:   batchSize,
+				recordPaced: recordIOOverloadPaced,
+			},
+			newBatch: newBatch,
+			st:       st,
 		}
 	default:
 		log.Fatalf(ctx, "unknown snapshot strategy: %s", header.Strategy)
@@ -335,6 +335,9 @@ func (s *Store) SplitRange(
 		// assumption that distribution across all tracked load stats is
 		// identical.
 		leftRepl.loadStats.split(rightRepl.loadStats)
+		// The writes to the keys of the RHS were tracked by the LHS.
+		lastWrite, _ := leftRepl.GetLastWriteTimestamp()
+		rightRepl.lastWrite.reset(lastWrite)
 		if err := s.addReplicaInternalLocked(rightRepl); err != nil {
 			return errors.Wrapf(err, "unable to add replica %v", rightRepl)
 		}
@@ -49,6 +49,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -2858,6 +2859,14 @@ func (c fakeSnapshotStream) Send(request *kvserverpb.SnapshotRequest) error {
 
 type fakeStorePool struct {
 	failedThrottles int
+	storeDescs      map[roachpb.StoreID]roachpb.StoreDescriptor
+}
+
+func (sp *fakeStorePool) GetStoreDescriptor(
+	storeID roachpb.StoreID,
+) (roachpb.StoreDescriptor, bool) {
+	sd, ok := sp.storeDescs[storeID]
+	return sd, ok
 }
 
 func (sp *fakeStorePool) Throttle(
@@ -2897,7 +2906,8 @@ func TestSendSnapshotThrottling(t *testing.T) {
 		expectedErr := errors.New("")
 		c := fakeSnapshotStream{nil, expectedErr}
 		err := sendSnapshot(
-			ctx, st, tr, c, sp, header, nil /* snap */, newBatch, nil /* sent */, nil, /* recordBytesSent */
+			ctx, st, tr, c, sp, header, nil /* snap */, newBatch, nil, /* sent */
+			nil /* recordBytesSent */, nil, /* recordIOOverloadPaced */
 		)
 		if sp.failedThrottles != 1 {
 			t.Fatalf("expected 1 failed throttle, but found %d", sp.failedThrottles)
@@ -2915,7 +2925,8 @@ func TestSendSnapshotThrottling(t *testing.T) {
 		}
 		c := fakeSnapshotStream{resp, nil}
 		err := sendSnapshot(
-			ctx, st, tr, c, sp, header, nil /* snap */, newBatch, nil /* sent */, nil, /* recordBytesSent */
+			ctx, st, tr, c, sp, header, nil /* snap */, newBatch, nil, /* sent */
+			nil /* recordBytesSent */, nil, /* recordIOOverloadPaced */
 		)
 		if sp.failedThrottles != 1 {
 			t.Fatalf("expected 1 failed throttle, but found %d", sp.failedThrottles)
@@ -2926,6 +2937,91 @@ func TestSendSnapshotThrottling(t *testing.T) {
 	}
 }
 
+// TestSnapshotIOPacer verifies that the rate limit of an outgoing snapshot is
+// reduced while the recipient store is IO overloaded, and restored once the
+// overload subsides.
+func TestSnapshotIOPacer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	const storeID = roachpb.StoreID(2)
+	const baseRate = 32 << 20
+	const batchSize = 1 << 20
+
+	sp := &fakeStorePool{storeDescs: map[roachpb.StoreID]roachpb.StoreDescriptor{}}
+	setL0Files := func(n int64) {
+		sp.storeDescs[storeID] = roachpb.StoreDescriptor{
+			StoreID: storeID,
+			Capacity: roachpb.StoreCapacity{
+				IOThreshold: admissionpb.IOThreshold{
+					L0NumSubLevels:          0,
+					L0NumSubLevelsThreshold: 20,
+					L0NumFiles:              n,
+					L0NumFilesThreshold:     1000,
+				},
+			},
+		}
+	}
+	var paced int
+	p := &snapshotIOPacer{
+		st:          st,
+		storePool:   sp,
+		storeID:     storeID,
+		baseRate:    baseRate,
+		batchSize:   batchSize,
+		recordPaced: func() { paced++ },
+	}
+	limiter := rate.NewLimiter(baseRate/batchSize, 1 /* burst size */)
+	now := timeutil.Unix(0, 0)
+	advance := func() time.Time {
+		now = now.Add(snapshotIOPacingInterval)
+		return now
+	}
+
+	// The recipient's descriptor is unknown, so the rate is not reduced.
+	p.maybeUpdate(ctx, limiter, advance())
+	require.Equal(t, rate.Limit(baseRate/batchSize), limiter.Limit())
+
+	// The recipient is not overloaded.
+	setL0Files(500)
+	p.maybeUpdate(ctx, limiter, advance())
+	require.Equal(t, rate.Limit(baseRate/batchSize), limiter.Limit())
+	require.Equal(t, 0, paced)
+
+	// The recipient is overloaded with a score of 4.
+	setL0Files(4000)
+	p.maybeUpdate(ctx, limiter, advance())
+	require.Equal(t, rate.Limit(baseRate/4/batchSize), limiter.Limit())
+	require.Equal(t, 1, paced)
+
+	// Updates within the pacing interval are ignored.
+	setL0Files(8000)
+	p.maybeUpdate(ctx, limiter, now.Add(snapshotIOPacingInterval/2))
+	require.Equal(t, rate.Limit(baseRate/4/batchSize), limiter.Limit())
+
+	// A severely overloaded recipient does not reduce the rate below the
+	// minimum snapshot rate. The snapshot was already paced, so the metric is
+	// not incremented again.
+	setL0Files(1000000)
+	p.maybeUpdate(ctx, limiter, advance())
+	require.Equal(t, rate.Limit(minSnapshotRate/batchSize), limiter.Limit())
+	require.Equal(t, 1, paced)
+
+	// The overload subsides.
+	setL0Files(0)
+	p.maybeUpdate(ctx, limiter, advance())
+	require.Equal(t, rate.Limit(baseRate/batchSize), limiter.Limit())
+
+	// Pacing can be disabled.
+	setL0Files(4000)
+	snapshotIOOverloadPacingEnabled.Override(ctx, &st.SV, false)
+	p.maybeUpdate(ctx, limiter, advance())
+	require.Equal(t, rate.Limit(baseRate/batchSize), limiter.Limit())
+	require.Equal(t, 1, paced)
+}
+
 func TestReserveSnapshotThrottling(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -93,3 +93,18 @@ func (s *baseStore) SetQueueActive(active bool, queue string) error {
 	kvQueue.SetDisabled(!active)
 	return nil
 }
+
+// VisitTrippedCircuitBreakers is part of kvserverbase.Store.
+func (s *baseStore) VisitTrippedCircuitBreakers(
+	visitor func(rangeID roachpb.RangeID, err error) error,
+) error {
+	store := (*Store)(s)
+	var visitErr error
+	store.VisitReplicas(func(repl *Replica) (wantMore bool) {
+		if err := repl.breaker.Signal().Err(); err != nil {
+			visitErr = visitor(repl.RangeID, err)
+		}
+		return visitErr == nil
+	})
+	return visitErr
+}
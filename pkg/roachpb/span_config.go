@@ -92,6 +92,12 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.ExcludeDataFromBackup {
 		return errors.AssertionFailedf("ExcludeDataFromBackup set on system span config")
 	}
+	if s.ClosedTimestampTargetDuration != 0 {
+		return errors.AssertionFailedf("ClosedTimestampTargetDuration set on system span config")
+	}
+	if s.LatencyBasedLeaseRebalancing {
+		return errors.AssertionFailedf("LatencyBasedLeaseRebalancing set on system span config")
+	}
 	return nil
 }
 
@@ -45,11 +45,15 @@ func (r *snappyReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
+// SnappyCompressorName is the name of the snappy gRPC compressor, which is used
+// by default by all RPCs when RPC compression is enabled.
+const SnappyCompressorName = "snappy"
+
 type snappyCompressor struct {
 }
 
 func (snappyCompressor) Name() string {
-	return "snappy"
+	return SnappyCompressorName
 }
 
 func (snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
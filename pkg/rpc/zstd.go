@@ -0,0 +1,114 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+)
+
+// ZstdCompressorName is the name of the zstd gRPC compressor. The compressor
+// is not used by default; callers opt into it on a per-stream basis through
+// grpc.UseCompressor. Since nodes running older versions can't decompress zstd
+// messages, callers must gate its use on clusterversion.ZstdRPCCompressor.
+const ZstdCompressorName = "zstd"
+
+// zstdMaxDecodedSize bounds the size of a decompressed message. zstd frames
+// can have very high compression ratios, so without a bound a small message
+// from a misbehaving peer could make the receiver allocate arbitrarily large
+// buffers. The decoder also refuses frames whose window exceeds this size, so
+// it must not be smaller than the window of zstdEncoder, which is 4 MiB.
+var zstdMaxDecodedSize = envutil.EnvOrDefaultBytes(
+	"COCKROACH_RPC_ZSTD_MAX_DECODED_SIZE", 64<<20 /* 64 MiB */)
+
+// zstdEncoder and zstdDecoder are shared by all the streams using the zstd
+// compressor. EncodeAll and DecodeAll are safe for concurrent use. They are
+// created eagerly because they start background goroutines.
+var zstdEncoder, zstdDecoder = func() (*zstd.Encoder, *zstd.Decoder) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	if err != nil {
+		panic(err)
+	}
+	return enc, newZstdDecoder(zstdMaxDecodedSize)
+}()
+
+// newZstdDecoder returns a decoder which refuses to decode messages larger
+// than maxDecodedSize.
+func newZstdDecoder(maxDecodedSize int64) *zstd.Decoder {
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(maxDecodedSize)))
+	if err != nil {
+		panic(err)
+	}
+	return dec
+}
+
+var zstdWriterPool sync.Pool
+
+// zstdWriter buffers a message and compresses it as a single frame when it is
+// closed. gRPC compresses every message separately, so there is nothing to
+// gain from streaming compression.
+type zstdWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+	out []byte
+}
+
+func (w *zstdWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *zstdWriter) Close() error {
+	defer zstdWriterPool.Put(w)
+	w.out = zstdEncoder.EncodeAll(w.buf.Bytes(), w.out[:0])
+	w.buf.Reset()
+	_, err := w.w.Write(w.out)
+	w.w = nil
+	return err
+}
+
+type zstdCompressor struct {
+}
+
+func (zstdCompressor) Name() string {
+	return ZstdCompressorName
+}
+
+func (zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	zw, ok := zstdWriterPool.Get().(*zstdWriter)
+	if !ok {
+		zw = &zstdWriter{}
+	}
+	zw.w = w
+	return zw, nil
+}
+
+func (zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	compressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := zstdDecoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decompressing %d byte zstd message", len(compressed))
+	}
+	return bytes.NewReader(decompressed), nil
+}
+
+func init() {
+	encoding.RegisterCompressor(zstdCompressor{})
+}
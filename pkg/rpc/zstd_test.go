@@ -0,0 +1,82 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestZstdCompressor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	c := encoding.GetCompressor(ZstdCompressorName)
+	require.NotNil(t, c)
+
+	for _, msg := range []string{
+		"",
+		"a",
+		strings.Repeat("closed timestamp ", 1000),
+	} {
+		// Compress each message twice to exercise the reuse of pooled writers.
+		for i := 0; i < 2; i++ {
+			var buf bytes.Buffer
+			w, err := c.Compress(&buf)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(msg))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			if len(msg) > 100 {
+				require.Less(t, buf.Len(), len(msg))
+			}
+
+			r, err := c.Decompress(&buf)
+			require.NoError(t, err)
+			res, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, msg, string(res))
+		}
+	}
+}
+
+// TestZstdDecoderMaxDecodedSize verifies that the decoder refuses to decode
+// messages larger than its limit.
+func TestZstdDecoderMaxDecodedSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const maxSize = 4 << 10
+	dec := newZstdDecoder(maxSize)
+	defer dec.Close()
+	// The decoder also refuses frames whose window is larger than the limit, so
+	// use an encoder with a small window.
+	enc, err := zstd.NewWriter(nil, zstd.WithWindowSize(zstd.MinWindowSize))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, enc.Close()) }()
+
+	small := enc.EncodeAll(make([]byte, maxSize), nil)
+	res, err := dec.DecodeAll(small, nil)
+	require.NoError(t, err)
+	require.Len(t, res, maxSize)
+
+	// The message compresses to a few bytes, but decompresses to more than the
+	// limit.
+	large := enc.EncodeAll(make([]byte, 64*maxSize), nil)
+	require.Less(t, len(large), maxSize)
+	_, err = dec.DecodeAll(large, nil)
+	require.Error(t, err)
+}
@@ -2675,6 +2675,20 @@ func (s *adminServer) Decommission(
 	return s.DecommissionStatus(ctx, &serverpb.DecommissionStatusRequest{NodeIDs: nodeIDs, NumReplicaReport: req.NumReplicaReport})
 }
 
+// DecommissionPreCheck is part of the serverpb.AdminServer interface.
+func (s *adminServer) DecommissionPreCheck(
+	ctx context.Context, req *serverpb.DecommissionPreCheckRequest,
+) (*serverpb.DecommissionPreCheckResponse, error) {
+	if len(req.NodeIDs) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no node ID specified")
+	}
+	r, err := s.server.DecommissionPreCheck(ctx, req.NodeIDs, int(req.NumRangeReport))
+	if err != nil {
+		return nil, serverError(ctx, err)
+	}
+	return r, nil
+}
+
 // DataDistribution returns a count of replicas on each node for each table.
 func (s *adminServer) DataDistribution(
 	ctx context.Context, req *serverpb.DataDistributionRequest,
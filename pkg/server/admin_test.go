@@ -2584,6 +2584,62 @@ func TestDecommissionEnqueueReplicas(t *testing.T) {
 	decommissionAndCheck(5 /* decommissioningSrvIdx */)
 }
 
+// TestDecommissionPreCheck tests that the decommission pre-check reports the
+// ranges that can't move their replicas off of the nodes to decommission.
+func TestDecommissionPreCheck(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartNewTestCluster(t, 4, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	scratchKey := tc.ScratchRange(t)
+	tc.AddVotersOrFatal(t, scratchKey, tc.Target(1), tc.Target(2))
+	scratchRangeID := tc.LookupRangeOrFatal(t, scratchKey).RangeID
+
+	adminSrv := tc.Server(0)
+	conn, err := adminSrv.RPCContext().GRPCDialNode(
+		adminSrv.RPCAddr(), adminSrv.NodeID(), rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err)
+	adminClient := serverpb.NewAdminClient(conn)
+
+	// The scratch range's replica on n3 can be moved to n4.
+	resp, err := adminClient.DecommissionPreCheck(ctx, &serverpb.DecommissionPreCheckRequest{
+		NodeIDs:        []roachpb.NodeID{tc.Server(2).NodeID()},
+		NumRangeReport: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.CheckedRangeCount)
+	require.Zero(t, resp.BlockingRangeCount)
+	require.Empty(t, resp.BlockingRanges)
+
+	// Its replicas on both n2 and n3 can't be, since only n4 is left to take
+	// them over.
+	resp, err = adminClient.DecommissionPreCheck(ctx, &serverpb.DecommissionPreCheckRequest{
+		NodeIDs:        []roachpb.NodeID{tc.Server(1).NodeID(), tc.Server(2).NodeID()},
+		NumRangeReport: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.CheckedRangeCount)
+	require.Equal(t, int64(1), resp.BlockingRangeCount)
+	require.Len(t, resp.BlockingRanges, 1)
+	require.Equal(t, scratchRangeID, resp.BlockingRanges[0].RangeID)
+
+	// Blocking ranges are counted but not listed past the report limit.
+	resp, err = adminClient.DecommissionPreCheck(ctx, &serverpb.DecommissionPreCheckRequest{
+		NodeIDs: []roachpb.NodeID{tc.Server(1).NodeID(), tc.Server(2).NodeID()},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.BlockingRangeCount)
+	require.Empty(t, resp.BlockingRanges)
+
+	_, err = adminClient.DecommissionPreCheck(ctx, &serverpb.DecommissionPreCheckRequest{})
+	require.Error(t, err)
+}
+
 func TestAdminDecommissionedOperations(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -12,6 +12,7 @@ package debug
 
 import (
 	"context"
+	"encoding/json"
 	"expvar"
 	"fmt"
 	"io"
@@ -214,6 +215,7 @@ func (ds *Server) RegisterEngines(specs []base.StoreSpec, engines []storage.Engi
 // sidetransportReceiver abstracts *sidetransport.Receiver.
 type sidetransportReceiver interface {
 	HTML() string
+	StreamStatuses() []sidetransport.StreamStatus
 }
 
 // RegisterClosedTimestampSideTransport registers web endpoints for the closed
@@ -226,6 +228,15 @@ func (ds *Server) RegisterClosedTimestampSideTransport(
 			w.Header().Add("Content-type", "text/html")
 			fmt.Fprint(w, receiver.HTML())
 		})
+	ds.mux.HandleFunc("/debug/closedts-receiver/streams",
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Add("Content-type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(receiver.StreamStatuses()); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		})
 	ds.mux.HandleFunc("/debug/closedts-sender",
 		func(w http.ResponseWriter, req *http.Request) {
 			w.Header().Add("Content-type", "text/html")
@@ -12,14 +12,19 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/allocatorimpl"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/constraint"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
@@ -211,6 +216,208 @@ func (s *Server) Decommission(
 	return nil
 }
 
+// DecommissionPreCheck checks, without changing any membership status, whether
+// decommissioning the specified nodes would leave ranges that are unable to
+// move their replicas off of them. A range blocks decommissioning if:
+//  - a quorum of its voters is not live, so it can't change its replica set, or
+//  - there aren't enough live, active nodes outside of the decommissioning set
+//    and not already holding one of its replicas that satisfy its constraints
+//    to take over its replicas on the decommissioning nodes.
+// All blocking ranges are counted, but at most numRangeReport are listed in the
+// response.
+func (s *Server) DecommissionPreCheck(
+	ctx context.Context, nodeIDs []roachpb.NodeID, numRangeReport int,
+) (*serverpb.DecommissionPreCheckResponse, error) {
+	isDecommissioningNode := make(map[roachpb.NodeID]bool, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		isDecommissioningNode[nodeID] = true
+	}
+	livenessMap := s.nodeLiveness.GetIsLiveMap()
+
+	// Collect the stores that could receive the replicas moved off of the
+	// decommissioning nodes.
+	var candidates []roachpb.StoreDescriptor
+	candidateNodes := make(map[roachpb.NodeID]bool)
+	for _, store := range s.storePool.GetStores() {
+		l, ok := livenessMap[store.Node.NodeID]
+		if ok && l.IsLive && l.Membership.Active() && !isDecommissioningNode[store.Node.NodeID] {
+			candidates = append(candidates, store)
+			candidateNodes[store.Node.NodeID] = true
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].StoreID < candidates[j].StoreID
+	})
+
+	var resp *serverpb.DecommissionPreCheckResponse
+	if err := s.db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		const pageSize = 10000
+		resp = &serverpb.DecommissionPreCheckResponse{}
+		return txn.Iterate(ctx, keys.Meta2Prefix, keys.MetaMax, pageSize,
+			func(rows []kv.KeyValue) error {
+				rangeDesc := roachpb.RangeDescriptor{}
+				for _, row := range rows {
+					if err := row.ValueProto(&rangeDesc); err != nil {
+						return errors.Wrapf(err, "%s: unable to unmarshal range descriptor", row.Key)
+					}
+					reason, checked, err := s.decommissionBlockingReason(
+						ctx, &rangeDesc, isDecommissioningNode, livenessMap, candidates, len(candidateNodes),
+					)
+					if err != nil {
+						return err
+					}
+					if !checked {
+						continue
+					}
+					resp.CheckedRangeCount++
+					if reason == "" {
+						continue
+					}
+					resp.BlockingRangeCount++
+					if len(resp.BlockingRanges) < numRangeReport {
+						resp.BlockingRanges = append(resp.BlockingRanges,
+							serverpb.DecommissionPreCheckResponse_BlockingRange{
+								RangeID: rangeDesc.RangeID,
+								Reason:  reason,
+							})
+					}
+				}
+				return nil
+			})
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// decommissionBlockingReason returns why the given range would block the
+// decommissioning of the given nodes, or an empty string if it wouldn't.
+// checked is false if the range has no replicas on the decommissioning nodes.
+// clusterNodes is the number of nodes that remain once the decommissioning
+// nodes are gone, which bounds the number of replicas the range needs.
+func (s *Server) decommissionBlockingReason(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	isDecommissioningNode map[roachpb.NodeID]bool,
+	livenessMap liveness.IsLiveMap,
+	candidates []roachpb.StoreDescriptor,
+	clusterNodes int,
+) (reason string, checked bool, _ error) {
+	replicas := desc.Replicas()
+	var toMove []roachpb.ReplicaDescriptor
+	for _, r := range replicas.Descriptors() {
+		if isDecommissioningNode[r.NodeID] {
+			toMove = append(toMove, r)
+		}
+	}
+	if len(toMove) == 0 {
+		return "", false, nil
+	}
+
+	voters := replicas.VoterDescriptors()
+	liveVoters := 0
+	for _, r := range voters {
+		if livenessMap[r.NodeID].IsLive {
+			liveVoters++
+		}
+	}
+	if quorum := len(voters)/2 + 1; liveVoters < quorum {
+		return fmt.Sprintf("only %d of %d voters are live, which is below quorum", liveVoters, len(voters)),
+			true, nil
+	}
+
+	var conf roachpb.SpanConfig
+	if s.spanConfigSubscriber != nil {
+		var err error
+		if conf, err = s.spanConfigSubscriber.GetSpanConfigForKey(ctx, desc.StartKey); err != nil {
+			return "", false, err
+		}
+	}
+
+	// Replicas on the decommissioning nodes only need to be replaced as long as
+	// the remaining replicas fall short of what the range needs in the smaller
+	// cluster.
+	neededVoters := len(voters)
+	neededNonVoters := len(replicas.NonVoterDescriptors())
+	if conf.NumReplicas > 0 {
+		neededVoters = allocatorimpl.GetNeededVoters(conf.GetNumVoters(), clusterNodes)
+		neededNonVoters = allocatorimpl.GetNeededNonVoters(
+			neededVoters, int(conf.GetNumNonVoters()), clusterNodes,
+		)
+	}
+	remainingVoters := len(voters)
+	remainingNonVoters := len(replicas.NonVoterDescriptors())
+	for _, r := range toMove {
+		if r.IsVoterNewConfig() {
+			remainingVoters--
+		} else {
+			remainingNonVoters--
+		}
+	}
+
+	// Greedily pick a distinct node to take over each replica to move, among
+	// the nodes that don't already have a replica of the range.
+	usedNodes := make(map[roachpb.NodeID]bool)
+	for _, r := range replicas.Descriptors() {
+		usedNodes[r.NodeID] = true
+	}
+	for _, r := range toMove {
+		if r.IsVoterNewConfig() {
+			if remainingVoters >= neededVoters {
+				continue
+			}
+			remainingVoters++
+		} else {
+			if remainingNonVoters >= neededNonVoters {
+				continue
+			}
+			remainingNonVoters++
+		}
+		var conjunctions []roachpb.ConstraintsConjunction
+		conjunctions = append(conjunctions, conf.Constraints...)
+		if r.IsVoterNewConfig() {
+			conjunctions = append(conjunctions, conf.VoterConstraints...)
+		}
+		existing, _ := s.storePool.GetStoreDescriptor(r.StoreID)
+		found := false
+		for _, candidate := range candidates {
+			if usedNodes[candidate.Node.NodeID] {
+				continue
+			}
+			if canReplaceReplica(candidate, existing, conjunctions) {
+				usedNodes[candidate.Node.NodeID] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf(
+				"no live node satisfying the range's constraints can take over its replica on n%d", r.NodeID,
+			), true, nil
+		}
+	}
+	return "", true, nil
+}
+
+// canReplaceReplica returns whether the candidate store can hold a replica
+// that is moved off of the existing store, given the constraints of the range.
+// Conjunctions that apply to all replicas must be satisfied by the candidate.
+// Conjunctions that only apply to some of the replicas must be satisfied by
+// the candidate if they were by the existing store, so that the candidate can
+// count towards them in its place.
+func canReplaceReplica(
+	candidate, existing roachpb.StoreDescriptor, conjunctions []roachpb.ConstraintsConjunction,
+) bool {
+	for _, conj := range conjunctions {
+		if conj.NumReplicas == 0 || constraint.ConjunctionsCheck(existing, conj.Constraints) {
+			if !constraint.ConjunctionsCheck(candidate, conj.Constraints) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // DecommissioningNodeMap returns the set of node IDs that are decommissioning
 // from the perspective of the server.
 func (s *Server) DecommissioningNodeMap() map[roachpb.NodeID]interface{} {
@@ -12,17 +12,53 @@ package server
 
 import (
 	"context"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/loqrecovery/loqrecoverypb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// newLossOfQuorumRecoveryPlanStore returns the store that keeps the loss of
+// quorum recovery plan staged on this node. The plan is kept in the auxiliary
+// directory of the first store.
+func newLossOfQuorumRecoveryPlanStore(engines []storage.Engine) loqrecovery.PlanStore {
+	return loqrecovery.NewPlanStore(engines[0].GetAuxiliaryDir(), engines[0])
+}
+
+// applyStagedLossOfQuorumRecoveryPlan applies the loss of quorum recovery plan
+// staged on this node, if any. It must be called before the stores are
+// started. Replica changes done by the plan leave recovery records in the
+// stores which are then reported by logPendingLossOfQuorumRecoveryEvents and
+// publishPendingLossOfQuorumRecoveryEvents, the same as for plans applied
+// offline.
+func applyStagedLossOfQuorumRecoveryPlan(ctx context.Context, engines []storage.Engine) {
+	if err := loqrecovery.ApplyStagedPlan(
+		ctx, newLossOfQuorumRecoveryPlanStore(engines), engines, uuid.DefaultGenerator, timeutil.Now(),
+	); err != nil {
+		// We don't want to abort server start as the plan is discarded and the
+		// operator can stage a new one.
+		log.Errorf(ctx, "failed to apply staged loss of quorum recovery plan: %v", err)
+	}
+}
+
 func logPendingLossOfQuorumRecoveryEvents(ctx context.Context, stores *kvserver.Stores) {
 	if err := stores.VisitStores(func(s *kvserver.Store) error {
 		// We are not requesting entry deletion here because we need those entries
@@ -83,3 +119,221 @@ func publishPendingLossOfQuorumRecoveryEvents(
 		}
 	})
 }
+
+// RecoveryCollectReplicaInfo is part of the serverpb.AdminServer interface.
+func (s *adminServer) RecoveryCollectReplicaInfo(
+	ctx context.Context, req *serverpb.RecoveryCollectReplicaInfoRequest,
+) (*serverpb.RecoveryCollectReplicaInfoResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.server.AnnotateCtx(ctx)
+
+	// Note: the root user will bypass SQL auth checks, which is required when
+	// the ranges backing SQL have lost quorum.
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+	if req.NodeID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "node_id must be non-negative; got %d", req.NodeID)
+	}
+
+	if req.NodeID == s.server.NodeID() {
+		return s.recoveryCollectLocalReplicaInfo(ctx)
+	} else if req.NodeID != 0 {
+		admin, err := s.dialNode(ctx, req.NodeID)
+		if err != nil {
+			return nil, serverError(ctx, err)
+		}
+		return admin.RecoveryCollectReplicaInfo(ctx, req)
+	}
+
+	nodeIDs, err := s.gossipNodeIDs()
+	if err != nil {
+		return nil, serverError(ctx, err)
+	}
+	response := &serverpb.RecoveryCollectReplicaInfoResponse{}
+	response.Errors = s.iterateRecoveryNodes(ctx, "collect replica info", nodeIDs,
+		func(ctx context.Context, admin serverpb.AdminClient, nodeID roachpb.NodeID) (interface{}, error) {
+			return admin.RecoveryCollectReplicaInfo(ctx,
+				&serverpb.RecoveryCollectReplicaInfoRequest{NodeID: nodeID})
+		},
+		func(_ roachpb.NodeID, nodeResp interface{}) {
+			response.Replicas = append(response.Replicas,
+				nodeResp.(*serverpb.RecoveryCollectReplicaInfoResponse).Replicas...)
+		})
+	return response, nil
+}
+
+func (s *adminServer) recoveryCollectLocalReplicaInfo(
+	ctx context.Context,
+) (*serverpb.RecoveryCollectReplicaInfoResponse, error) {
+	var engines []storage.Engine
+	if err := s.server.node.stores.VisitStores(func(store *kvserver.Store) error {
+		engines = append(engines, store.Engine())
+		return nil
+	}); err != nil {
+		return nil, serverError(ctx, err)
+	}
+	info, err := loqrecovery.CollectReplicaInfo(ctx, engines)
+	if err != nil {
+		return nil, serverError(ctx, err)
+	}
+	return &serverpb.RecoveryCollectReplicaInfoResponse{Replicas: info.Replicas}, nil
+}
+
+// RecoveryStagePlan is part of the serverpb.AdminServer interface.
+func (s *adminServer) RecoveryStagePlan(
+	ctx context.Context, req *serverpb.RecoveryStagePlanRequest,
+) (*serverpb.RecoveryStagePlanResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.server.AnnotateCtx(ctx)
+
+	if _, err := s.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+	if req.NodeID < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "node_id must be non-negative; got %d", req.NodeID)
+	}
+	if req.Plan.PlanID == uuid.Nil {
+		return nil, status.Errorf(codes.InvalidArgument, "plan must have a plan_id")
+	}
+
+	if req.NodeID == s.server.NodeID() {
+		return s.recoveryStageLocalPlan(ctx, req)
+	} else if req.NodeID != 0 {
+		admin, err := s.dialNode(ctx, req.NodeID)
+		if err != nil {
+			return nil, serverError(ctx, err)
+		}
+		return admin.RecoveryStagePlan(ctx, req)
+	}
+
+	// Only the nodes holding the designated survivors need the plan.
+	var nodeIDs []roachpb.NodeID
+	seen := make(map[roachpb.NodeID]struct{})
+	for _, u := range req.Plan.Updates {
+		if _, ok := seen[u.NodeID()]; !ok {
+			seen[u.NodeID()] = struct{}{}
+			nodeIDs = append(nodeIDs, u.NodeID())
+		}
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+
+	response := &serverpb.RecoveryStagePlanResponse{}
+	response.Errors = s.iterateRecoveryNodes(ctx, "stage recovery plan", nodeIDs,
+		func(ctx context.Context, admin serverpb.AdminClient, nodeID roachpb.NodeID) (interface{}, error) {
+			req := *req
+			req.NodeID = nodeID
+			return admin.RecoveryStagePlan(ctx, &req)
+		},
+		func(_ roachpb.NodeID, nodeResp interface{}) {
+			response.StagedNodeIDs = append(response.StagedNodeIDs,
+				nodeResp.(*serverpb.RecoveryStagePlanResponse).StagedNodeIDs...)
+		})
+	sort.Slice(response.StagedNodeIDs, func(i, j int) bool {
+		return response.StagedNodeIDs[i] < response.StagedNodeIDs[j]
+	})
+	return response, nil
+}
+
+func (s *adminServer) recoveryStageLocalPlan(
+	ctx context.Context, req *serverpb.RecoveryStagePlanRequest,
+) (*serverpb.RecoveryStagePlanResponse, error) {
+	nodeID := s.server.NodeID()
+	for _, u := range req.Plan.Updates {
+		if u.NodeID() == nodeID && !s.server.node.stores.HasStore(u.StoreID()) {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"plan updates replica of r%d on store s%d which is not present on n%d",
+				u.RangeID, u.StoreID(), nodeID)
+		}
+	}
+
+	planStore := newLossOfQuorumRecoveryPlanStore(s.server.engines)
+	staged, ok, err := planStore.LoadPlan()
+	if err != nil {
+		return nil, serverError(ctx, err)
+	}
+	if ok && staged.PlanID != req.Plan.PlanID && !req.Force {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"a different plan %s is already staged on n%d", staged.PlanID, nodeID)
+	}
+	if err := planStore.SavePlan(req.Plan); err != nil {
+		return nil, serverError(ctx, err)
+	}
+	log.Infof(ctx, "staged loss of quorum recovery plan %s, it will be applied when the node is restarted",
+		req.Plan.PlanID)
+	return &serverpb.RecoveryStagePlanResponse{StagedNodeIDs: []roachpb.NodeID{nodeID}}, nil
+}
+
+// gossipNodeIDs returns the IDs of all nodes which have their descriptors in
+// gossip. Unlike the node status records, this doesn't depend on KV being
+// available, which is not the case when loss of quorum recovery is needed.
+func (s *adminServer) gossipNodeIDs() ([]roachpb.NodeID, error) {
+	var nodeIDs []roachpb.NodeID
+	if err := s.server.gossip.IterateInfos(gossip.KeyNodeDescPrefix, func(key string, _ gossip.Info) error {
+		nodeID, err := gossip.DecodeNodeDescKey(key, gossip.KeyNodeDescPrefix)
+		if err != nil {
+			return err
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+	return nodeIDs, nil
+}
+
+// iterateRecoveryNodes calls nodeFn concurrently for each of the given nodes,
+// passing successful responses to responseFn. It returns the errors for nodes
+// which could not be reached or failed to serve the request. responseFn is
+// called while holding a lock, so it doesn't need to synchronize access to the
+// response.
+func (s *adminServer) iterateRecoveryNodes(
+	ctx context.Context,
+	op string,
+	nodeIDs []roachpb.NodeID,
+	nodeFn func(ctx context.Context, admin serverpb.AdminClient, nodeID roachpb.NodeID) (interface{}, error),
+	responseFn func(nodeID roachpb.NodeID, resp interface{}),
+) []serverpb.RecoveryNodeError {
+	var mu syncutil.Mutex
+	var nodeErrors []serverpb.RecoveryNodeError
+	addError := func(nodeID roachpb.NodeID, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		nodeErrors = append(nodeErrors, serverpb.RecoveryNodeError{NodeID: nodeID, Error: err.Error()})
+	}
+
+	var wg sync.WaitGroup
+	for _, nodeID := range nodeIDs {
+		nodeID := nodeID
+		wg.Add(1)
+		if err := s.server.stopper.RunAsyncTask(ctx, "server.adminServer: "+op, func(ctx context.Context) {
+			defer wg.Done()
+			var resp interface{}
+			if err := contextutil.RunWithTimeout(ctx, op, time.Minute, func(ctx context.Context) error {
+				admin, err := s.dialNode(ctx, nodeID)
+				if err != nil {
+					return errors.Wrapf(err, "failed to dial into node %d", nodeID)
+				}
+				resp, err = nodeFn(ctx, admin, nodeID)
+				return err
+			}); err != nil {
+				addError(nodeID, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			responseFn(nodeID, resp)
+		}); err != nil {
+			wg.Done()
+			addError(nodeID, err)
+		}
+	}
+	wg.Wait()
+	sort.Slice(nodeErrors, func(i, j int) bool { return nodeErrors[i].NodeID < nodeErrors[j].NodeID })
+	return nodeErrors
+}
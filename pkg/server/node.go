@@ -1080,6 +1080,10 @@ func (n *Node) batchInternal(
 		n.admissionController.AdmittedKVWorkDone(handle, writeBytes)
 		writeBytes.Release()
 	}()
+	// Elastic work which can be paginated returns a partial result after running
+	// for a while, so that it is subject to admission control again when it is
+	// resumed.
+	ctx = admission.MaybeContextWithElasticWorkHandle(ctx, n.storeCfg.Settings, args)
 	var pErr *roachpb.Error
 	br, writeBytes, pErr = n.stores.SendWithWriteBytes(ctx, *args)
 	if pErr != nil {
@@ -82,6 +82,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/goschedstats"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
@@ -124,6 +125,7 @@ type Server struct {
 	promRuleExporter *metric.PrometheusRuleExporter
 	updates          *diagnostics.UpdateChecker
 	ctSender         *sidetransport.Sender
+	ctReceiver       *sidetransport.Receiver
 
 	http            *httpServer
 	adminAuthzCheck *adminPrivilegeChecker
@@ -477,7 +479,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	registry.AddMetricStruct(raftTransport.Metrics())
 
 	ctSender := sidetransport.NewSender(stopper, st, clock, nodeDialer)
-	ctReceiver := sidetransport.NewReceiver(nodeIDContainer, stopper, stores, nil /* testingKnobs */)
+	registry.AddMetricStruct(ctSender.Metrics())
+	ctReceiver := sidetransport.NewReceiver(nodeIDContainer, stopper, st, stores, nil /* testingKnobs */)
+	registry.AddMetricStruct(ctReceiver.Metrics())
 
 	// The InternalExecutor will be further initialized later, as we create more
 	// of the server's components. There's a circular dependency - many things
@@ -806,6 +810,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			sqlSQLResponseAdmissionQ: gcoords.Regular.GetWorkQueue(admission.SQLSQLResponseWork),
 			spanConfigKVAccessor:     spanConfig.kvAccessorForTenantRecords,
 			kvStoresIterator:         kvserver.MakeStoresIterator(node.stores),
+			closedTimestampSender:    ctSender,
 		},
 		SQLConfig:                &cfg.SQLConfig,
 		BaseConfig:               &cfg.BaseConfig,
@@ -894,6 +899,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		promRuleExporter:       promRuleExporter,
 		updates:                updates,
 		ctSender:               ctSender,
+		ctReceiver:             ctReceiver,
 		runtime:                runtimeSampler,
 		http:                   sHTTP,
 		adminAuthzCheck:        adminAuthzCheck,
@@ -1089,6 +1095,11 @@ func (s *Server) PreStart(ctx context.Context) error {
 			return err
 		}
 
+		// Apply a loss of quorum recovery plan staged on this node before the
+		// engines are inspected, so that stores start with the recovered
+		// replicas.
+		applyStagedLossOfQuorumRecoveryPlan(ctx, s.engines)
+
 		initConfig := newInitServerConfig(ctx, s.cfg, dialOpts)
 		inspectedDiskState, err := inspectEngines(
 			ctx,
@@ -1620,6 +1631,13 @@ func (s *Server) PreStart(ctx context.Context) error {
 	s.debug.RegisterClosedTimestampSideTransport(s.ctSender, s.node.storeCfg.ClosedTimestampReceiver)
 
 	s.ctSender.Run(ctx, state.nodeID)
+	s.ctReceiver.Start(ctx, func(ctx context.Context, event logpb.EventPayload) {
+		dst := sql.LogExternally | sql.LogToDevChannelIfVerbose
+		if s.cfg.EventLogEnabled {
+			dst |= sql.LogToSystemTable
+		}
+		sql.InsertEventRecords(ctx, s.sqlServer.execCfg, dst, event)
+	})
 
 	// Attempt to upgrade cluster version now that the sql server has been
 	// started. At this point we know that all startupmigrations have successfully
@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangestats"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/sidetransport"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
@@ -80,6 +81,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/planpins"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/rangeprober"
 	"github.com/cockroachdb/cockroach/pkg/sql/scheduledlogging"
@@ -157,6 +159,7 @@ type SQLServer struct {
 	// sqlMemMetrics are used to track memory usage of sql sessions.
 	sqlMemMetrics           sql.MemoryMetrics
 	stmtDiagnosticsRegistry *stmtdiagnostics.Registry
+	planPinsRegistry        *planpins.Registry
 	// sqlLivenessSessionID will be populated with a non-zero value for non-system
 	// tenants.
 	sqlLivenessSessionID           sqlliveness.SessionID
@@ -228,6 +231,9 @@ type sqlServerOptionalKVArgs struct {
 	// kvStores is used by crdb_internal builtins to access the stores on this
 	// node.
 	kvStoresIterator kvserverbase.StoresIterator
+	// closedTimestampSender is used by crdb_internal to report the ranges with
+	// local leases that are failing to close timestamps.
+	closedTimestampSender *sidetransport.Sender
 }
 
 // sqlServerOptionalTenantArgs are the arguments supplied to newSQLServer which
@@ -791,6 +797,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		TraceCollector:            traceCollector,
 		TenantUsageServer:         cfg.tenantUsageServer,
 		KVStoresIterator:          cfg.kvStoresIterator,
+		ClosedTimestampSender:     cfg.closedTimestampSender,
 		SyntheticPrivilegeCache: cacheutil.NewCache(
 			serverCacheMemoryMonitor.MakeBoundAccount(), cfg.stopper, 1 /* numSystemTables */),
 
@@ -993,6 +1000,8 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		cfg.Settings,
 	)
 	execCfg.StmtDiagnosticsRecorder = stmtDiagnosticsRegistry
+	planPinsRegistry := planpins.NewRegistry(cfg.circularInternalExecutor, cfg.Settings)
+	execCfg.PlanPins = planPinsRegistry
 
 	{
 		// We only need to attach a version upgrade hook if we're the system
@@ -1138,6 +1147,7 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		internalMemMetrics:                internalMemMetrics,
 		sqlMemMetrics:                     sqlMemMetrics,
 		stmtDiagnosticsRegistry:           stmtDiagnosticsRegistry,
+		planPinsRegistry:                  planPinsRegistry,
 		sqlLivenessProvider:               cfg.sqlLivenessProvider,
 		sqlInstanceProvider:               cfg.sqlInstanceProvider,
 		metricsRegistry:                   cfg.registry,
@@ -1248,6 +1258,7 @@ func (s *SQLServer) preStart(
 		return err
 	}
 	s.stmtDiagnosticsRegistry.Start(ctx, stopper)
+	s.planPinsRegistry.Start(ctx, stopper)
 	if err := s.execCfg.TableStatsCache.Start(ctx, s.execCfg.Codec, s.execCfg.RangeFeedFactory); err != nil {
 		return err
 	}
@@ -43,6 +43,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator/storepool"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/replicastats"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -64,6 +65,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlstats/insights"
+	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
@@ -750,15 +752,12 @@ func (s *statusServer) Allocator(
 					if !rep.OwnsValidLease(ctx, store.Clock().NowAsClockTimestamp()) {
 						return true // continue.
 					}
-					var allocatorSpans tracingpb.Recording
-					allocatorSpans, err = store.AllocatorDryRun(ctx, rep)
+					var dryRun *serverpb.AllocatorDryRun
+					dryRun, err = allocatorDryRun(ctx, store, rep, req.Explain)
 					if err != nil {
 						return false // break and bubble up the error.
 					}
-					output.DryRuns = append(output.DryRuns, &serverpb.AllocatorDryRun{
-						RangeID: rep.RangeID,
-						Events:  recordedSpansToTraceEvents(allocatorSpans),
-					})
+					output.DryRuns = append(output.DryRuns, dryRun)
 					return true // continue.
 				},
 				kvserver.WithReplicasInOrder(),
@@ -776,14 +775,11 @@ func (s *statusServer) Allocator(
 			if !rep.OwnsValidLease(ctx, store.Clock().NowAsClockTimestamp()) {
 				continue
 			}
-			allocatorSpans, err := store.AllocatorDryRun(ctx, rep)
+			dryRun, err := allocatorDryRun(ctx, store, rep, req.Explain)
 			if err != nil {
 				return err
 			}
-			output.DryRuns = append(output.DryRuns, &serverpb.AllocatorDryRun{
-				RangeID: rep.RangeID,
-				Events:  recordedSpansToTraceEvents(allocatorSpans),
-			})
+			output.DryRuns = append(output.DryRuns, dryRun)
 		}
 		return nil
 	})
@@ -793,6 +789,61 @@ func (s *statusServer) Allocator(
 	return output, nil
 }
 
+// allocatorDryRun runs the replica through the allocator of the store without
+// carrying out any changes. If explain is set, the result includes a
+// structured explanation of the allocator decision.
+func allocatorDryRun(
+	ctx context.Context, store *kvserver.Store, rep *kvserver.Replica, explain bool,
+) (*serverpb.AllocatorDryRun, error) {
+	if explain {
+		allocatorSpans, explanation := store.AllocatorExplain(ctx, rep)
+		return &serverpb.AllocatorDryRun{
+			RangeID:     rep.RangeID,
+			Events:      recordedSpansToTraceEvents(allocatorSpans),
+			Explanation: allocatorExplanationToProto(explanation),
+		}, nil
+	}
+	allocatorSpans, err := store.AllocatorDryRun(ctx, rep)
+	if err != nil {
+		return nil, err
+	}
+	return &serverpb.AllocatorDryRun{
+		RangeID: rep.RangeID,
+		Events:  recordedSpansToTraceEvents(allocatorSpans),
+	}, nil
+}
+
+func allocatorExplanationToProto(e kvserver.AllocatorExplanation) *serverpb.AllocatorExplanation {
+	explanation := &serverpb.AllocatorExplanation{
+		Action:     e.Action.String(),
+		Priority:   e.Priority,
+		TargetType: e.TargetType.String(),
+		Candidates: make([]serverpb.AllocatorCandidate, 0, len(e.Candidates)),
+		Throttled:  e.Throttled,
+	}
+	if e.Err != nil {
+		explanation.Error = e.Err.Error()
+	}
+	for _, c := range e.Candidates {
+		explanation.Candidates = append(explanation.Candidates, serverpb.AllocatorCandidate{
+			StoreID:          c.StoreID,
+			NodeID:           c.NodeID,
+			Existing:         c.Existing,
+			Reason:           c.Reason,
+			Valid:            c.Valid,
+			FullDisk:         c.FullDisk,
+			Necessary:        c.Necessary,
+			HighReadAmp:      c.HighReadAmp,
+			DiversityScore:   c.DiversityScore,
+			ConvergesScore:   int32(c.ConvergesScore),
+			BalanceScore:     int32(c.BalanceScore),
+			RangeCount:       int32(c.RangeCount),
+			QueriesPerSecond: c.QueriesPerSecond,
+		})
+	}
+	return explanation
+}
+
 func recordedSpansToTraceEvents(spans []tracingpb.RecordedSpan) []*serverpb.TraceEvent {
 	var output []*serverpb.TraceEvent
 	for _, sp := range spans {
@@ -839,6 +890,7 @@ func (s *statusServer) AllocatorRange(
 					if err == nil {
 						allocatorRequest := &serverpb.AllocatorRequest{
 							RangeIDs: []roachpb.RangeID{roachpb.RangeID(req.RangeId)},
+							Explain:  req.Explain,
 						}
 						allocatorResponse, err = status.Allocator(ctx, allocatorRequest)
 					}
@@ -2543,6 +2595,291 @@ func (s *statusServer) localHotRanges(ctx context.Context) serverpb.HotRangesRes
 	return resp
 }
 
+// hotKeysPerRange is the number of keys reported by the HotKeys endpoint for
+// each hot range, for both bytes written and bytes read.
+const hotKeysPerRange = 10
+
+// HotKeys returns the keys with the most bytes written and read in the hottest
+// ranges on each store of the requested node(s).
+func (s *statusServer) HotKeys(
+	ctx context.Context, req *serverpb.HotKeysRequest,
+) (*serverpb.HotKeysResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+
+		// Only hot keys from the local node.
+		if local {
+			resp, err := s.localHotKeys(ctx)
+			if err != nil {
+				return nil, serverError(ctx, err)
+			}
+			return resp, nil
+		}
+
+		// Only hot keys from one non-local node.
+		status, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, serverError(ctx, err)
+		}
+		return status.HotKeys(ctx, req)
+	}
+
+	// Hot keys from all nodes.
+	response := &serverpb.HotKeysResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	remoteRequest := serverpb.HotKeysRequest{NodeID: "local"}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.HotKeys(ctx, &remoteRequest)
+	}
+	responseFn := func(_ roachpb.NodeID, resp interface{}) {
+		hotKeysResp := resp.(*serverpb.HotKeysResponse)
+		response.Keys = append(response.Keys, hotKeysResp.Keys...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		response.ErrorsByNodeID[nodeID] = err.Error()
+	}
+
+	if err := s.iterateNodes(ctx, "hot keys", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, serverError(ctx, err)
+	}
+
+	// The keys of each range are already sorted, so a stable sort on the range
+	// keeps them in order.
+	sort.SliceStable(response.Keys, func(i, j int) bool {
+		a, b := &response.Keys[i], &response.Keys[j]
+		if a.NodeID != b.NodeID {
+			return a.NodeID < b.NodeID
+		}
+		if a.StoreID != b.StoreID {
+			return a.StoreID < b.StoreID
+		}
+		return a.RangeID < b.RangeID
+	})
+	return response, nil
+}
+
+func (s *statusServer) localHotKeys(ctx context.Context) (*serverpb.HotKeysResponse, error) {
+	nodeID := s.gossip.NodeID.Get()
+	resp := &serverpb.HotKeysResponse{}
+	err := s.stores.VisitStores(func(store *kvserver.Store) error {
+		for _, r := range store.HottestReplicas() {
+			repl, err := store.GetReplica(r.Desc.RangeID)
+			if err != nil {
+				// The replica was removed since the hot ranges were computed.
+				continue
+			}
+			writes, reads := repl.HotKeys(hotKeysPerRange)
+			resp.Keys = append(resp.Keys,
+				makeHotKeys(nodeID, store.StoreID(), r.Desc.RangeID, writes, reads)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// makeHotKeys combines the keys with the most bytes written and read in a
+// range into a single list, sorted by decreasing total bytes per second.
+func makeHotKeys(
+	nodeID roachpb.NodeID,
+	storeID roachpb.StoreID,
+	rangeID roachpb.RangeID,
+	writes, reads []replicastats.HotKey,
+) []serverpb.HotKeysResponse_HotKey {
+	var res []serverpb.HotKeysResponse_HotKey
+	idx := make(map[string]int, len(writes)+len(reads))
+	get := func(key roachpb.Key) *serverpb.HotKeysResponse_HotKey {
+		if i, ok := idx[string(key)]; ok {
+			return &res[i]
+		}
+		idx[string(key)] = len(res)
+		res = append(res, serverpb.HotKeysResponse_HotKey{
+			NodeID:  nodeID,
+			StoreID: storeID,
+			RangeID: rangeID,
+			Key:     key,
+		})
+		return &res[len(res)-1]
+	}
+	for _, w := range writes {
+		get(w.Key).WriteBytesPerSecond = w.BytesPerSecond
+	}
+	for _, r := range reads {
+		get(r.Key).ReadBytesPerSecond = r.BytesPerSecond
+	}
+	sort.Slice(res, func(i, j int) bool {
+		ti := res[i].WriteBytesPerSecond + res[i].ReadBytesPerSecond
+		tj := res[j].WriteBytesPerSecond + res[j].ReadBytesPerSecond
+		if ti != tj {
+			return ti > tj
+		}
+		return res[i].Key.Compare(res[j].Key) < 0
+	})
+	return res
+}
+
+// RangeDetails returns replication and closed timestamp details of the replicas
+// on the requested node(s).
+func (s *statusServer) RangeDetails(
+	ctx context.Context, req *serverpb.RangeDetailsRequest,
+) (*serverpb.RangeDetailsResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if err := s.privilegeChecker.requireViewClusterMetadataPermission(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+
+		// Only replicas from the local node.
+		if local {
+			resp, err := s.localRangeDetails(ctx, req.RangeIDs)
+			if err != nil {
+				return nil, serverError(ctx, err)
+			}
+			return resp, nil
+		}
+
+		// Only replicas from one non-local node.
+		status, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, serverError(ctx, err)
+		}
+		return status.RangeDetails(ctx, req)
+	}
+
+	// Replicas from all nodes.
+	response := &serverpb.RangeDetailsResponse{
+		ErrorsByNodeID: make(map[roachpb.NodeID]string),
+	}
+	dialFn := func(ctx context.Context, nodeID roachpb.NodeID) (interface{}, error) {
+		client, err := s.dialNode(ctx, nodeID)
+		return client, err
+	}
+	remoteRequest := serverpb.RangeDetailsRequest{NodeID: "local", RangeIDs: req.RangeIDs}
+	nodeFn := func(ctx context.Context, client interface{}, _ roachpb.NodeID) (interface{}, error) {
+		status := client.(serverpb.StatusClient)
+		return status.RangeDetails(ctx, &remoteRequest)
+	}
+	responseFn := func(_ roachpb.NodeID, resp interface{}) {
+		detailsResp := resp.(*serverpb.RangeDetailsResponse)
+		response.Replicas = append(response.Replicas, detailsResp.Replicas...)
+	}
+	errorFn := func(nodeID roachpb.NodeID, err error) {
+		response.ErrorsByNodeID[nodeID] = err.Error()
+	}
+
+	if err := s.iterateNodes(ctx, "range details", dialFn, nodeFn, responseFn, errorFn); err != nil {
+		return nil, serverError(ctx, err)
+	}
+
+	sort.Slice(response.Replicas, func(i, j int) bool {
+		a, b := &response.Replicas[i], &response.Replicas[j]
+		if a.RangeID != b.RangeID {
+			return a.RangeID < b.RangeID
+		}
+		if a.NodeID != b.NodeID {
+			return a.NodeID < b.NodeID
+		}
+		return a.StoreID < b.StoreID
+	})
+	return response, nil
+}
+
+// localRangeDetails returns the details of the replicas of the given ranges on
+// the stores of the local node, or of all of them if rangeIDs is empty.
+func (s *statusServer) localRangeDetails(
+	ctx context.Context, rangeIDs []roachpb.RangeID,
+) (*serverpb.RangeDetailsResponse, error) {
+	nodeID := s.gossip.NodeID.Get()
+	isLiveMap := s.nodeLiveness.GetIsLiveMap()
+	clusterNodes := s.storePool.ClusterNodeCount()
+	resp := &serverpb.RangeDetailsResponse{}
+	err := s.stores.VisitStores(func(store *kvserver.Store) error {
+		now := store.Clock().NowAsClockTimestamp()
+		addReplica := func(rep *kvserver.Replica) {
+			metrics := rep.Metrics(ctx, now, isLiveMap, clusterNodes)
+			state := rep.State(ctx)
+			details := serverpb.RangeDetailsResponse_ReplicaDetails{
+				NodeID:           nodeID,
+				StoreID:          store.StoreID(),
+				RangeID:          rep.RangeID,
+				ReplicaID:        rep.ReplicaID(),
+				RaftState:        raftStateDormant,
+				PendingProposals: int64(state.NumPending),
+				IsLeaseholder:    metrics.Leaseholder,
+				LeaseValid:       metrics.LeaseValid,
+				ClosedTimestamp:  state.ActiveClosedTimestamp,
+			}
+			if raftStatus := rep.RaftStatus(); raftStatus != nil {
+				details.RaftState = raftStatus.RaftState.String()
+				details.RaftAppliedIndex = raftStatus.Applied
+				details.RaftCommitIndex = raftStatus.Commit
+			}
+			if state.Lease != nil {
+				details.LeaseEpoch = state.Lease.Epoch
+			}
+			for _, r := range rep.Desc().Replicas().Descriptors() {
+				if !isLiveMap[r.NodeID].IsLive {
+					details.NonLiveNodeIDs = append(details.NonLiveNodeIDs, r.NodeID)
+				}
+			}
+			resp.Replicas = append(resp.Replicas, details)
+		}
+
+		// All ranges requested:
+		if len(rangeIDs) == 0 {
+			store.VisitReplicas(func(rep *kvserver.Replica) bool {
+				addReplica(rep)
+				return true // continue.
+			}, kvserver.WithReplicasInOrder())
+			return nil
+		}
+
+		// Specific ranges requested:
+		for _, rangeID := range rangeIDs {
+			rep, err := store.GetReplica(rangeID)
+			if err != nil {
+				// Not found: continue.
+				continue
+			}
+			addReplica(rep)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // Range returns rangeInfos for all nodes in the cluster about a specific
 // range. It also returns the range history for that range as well.
 func (s *statusServer) Range(
@@ -3253,6 +3590,71 @@ func (s *statusServer) Diagnostics(
 	return s.admin.server.sqlServer.diagnosticsReporter.CreateReport(ctx, telemetry.ReadOnly), nil
 }
 
+// RotateEncryptionKeys rotates the encryption-at-rest keys of the stores of
+// the requested node, and rewrites the sstables encrypted with previous data
+// keys.
+func (s *statusServer) RotateEncryptionKeys(
+	ctx context.Context, req *serverpb.RotateEncryptionKeysRequest,
+) (*serverpb.RotateEncryptionKeysResponse, error) {
+	ctx = propagateGatewayMetadata(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	if _, err := s.privilegeChecker.requireAdminUser(ctx); err != nil {
+		// NB: not using serverError() here since the priv checker
+		// already returns a proper gRPC error status.
+		return nil, err
+	}
+
+	nodeID, local, err := s.parseNodeID(req.NodeID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	if !local {
+		status, err := s.dialNode(ctx, nodeID)
+		if err != nil {
+			return nil, serverError(ctx, err)
+		}
+		return status.RotateEncryptionKeys(ctx, req)
+	}
+
+	resp := &serverpb.RotateEncryptionKeysResponse{NodeID: s.gossip.NodeID.Get()}
+	err = s.stores.VisitStores(func(store *kvserver.Store) error {
+		if req.StoreID != 0 && store.StoreID() != req.StoreID {
+			return nil
+		}
+		if req.RotateKeys {
+			if err := store.Engine().RotateEncryptionKeys(ctx); err != nil {
+				if errors.Is(err, storage.ErrEncryptionNotEnabled) {
+					// There are no keys to rotate on stores which are not encrypted.
+					return nil
+				}
+				return errors.Wrapf(err, "rotating encryption keys of s%d", store.StoreID())
+			}
+		}
+		rewritten, remaining, err := store.Engine().RewriteEncryptedFiles(ctx, int(req.MaxFilesToRewrite))
+		if errors.Is(err, storage.ErrEncryptionNotEnabled) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "rewriting encrypted files of s%d", store.StoreID())
+		}
+		resp.Stores = append(resp.Stores, serverpb.RotateEncryptionKeysResponse_Store{
+			StoreID:        store.StoreID(),
+			FilesRewritten: int64(rewritten),
+			FilesRemaining: int64(remaining),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, serverError(ctx, err)
+	}
+	if req.StoreID != 0 && len(resp.Stores) == 0 {
+		return nil, status.Errorf(codes.NotFound, "encrypted store %d not found on node %d", req.StoreID, resp.NodeID)
+	}
+	return resp, nil
+}
+
 // Stores returns details for each store.
 func (s *statusServer) Stores(
 	ctx context.Context, req *serverpb.StoresRequest,
@@ -968,6 +968,61 @@ func TestHotRanges2Response(t *testing.T) {
 	}
 }
 
+func TestHotKeysResponse(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	ts := s.(*TestServer)
+	db := sqlutils.MakeSQLRunner(sqlDB)
+
+	db.Exec(t, `SET CLUSTER SETTING kv.replica_stats.hot_keys.enabled = true`)
+	db.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	var tableID uint32
+	db.QueryRow(t, `SELECT 't'::REGCLASS::INT`).Scan(&tableID)
+	tablePrefix := keys.SystemSQLCodec.TablePrefix(tableID)
+
+	// Write the same row enough times for some of the batches to be sampled.
+	for i := 0; i < 200; i++ {
+		db.Exec(t, `UPSERT INTO t VALUES (1, repeat('x', 100))`)
+	}
+
+	rootConfig := testutils.NewTestBaseContext(username.RootUserName())
+	rpcContext := newRPCTestContext(ctx, ts, rootConfig)
+	conn, err := rpcContext.GRPCDialNode(ts.ServingRPCAddr(), ts.NodeID(), rpc.DefaultClass).Connect(ctx)
+	require.NoError(t, err)
+	client := serverpb.NewStatusClient(conn)
+
+	testutils.SucceedsSoon(t, func() error {
+		// Refresh the hottest replicas of the stores, so that they include the
+		// range of the table.
+		if err := ts.GetStores().(*kvserver.Stores).VisitStores(func(s *kvserver.Store) error {
+			_, err := s.Capacity(ctx, false /* useCached */)
+			return err
+		}); err != nil {
+			return err
+		}
+		resp, err := client.HotKeys(ctx, &serverpb.HotKeysRequest{})
+		if err != nil {
+			return err
+		}
+		if len(resp.ErrorsByNodeID) > 0 {
+			return errors.Errorf("unexpected errors: %v", resp.ErrorsByNodeID)
+		}
+		for _, k := range resp.Keys {
+			if k.NodeID != ts.NodeID() || k.RangeID == 0 {
+				return errors.Errorf("unexpected hot key: %+v", k)
+			}
+			if bytes.HasPrefix(k.Key, tablePrefix) && k.WriteBytesPerSecond > 0 {
+				return nil
+			}
+		}
+		return errors.Errorf("no hot key written in table t amongst %d hot keys", len(resp.Keys))
+	})
+}
+
 func TestHotRanges2ResponseWithViewActivityOptions(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
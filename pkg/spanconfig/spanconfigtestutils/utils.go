@@ -410,6 +410,12 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.ExcludeDataFromBackup != defaultConf.ExcludeDataFromBackup {
 		diffs = append(diffs, fmt.Sprintf("exclude_data_from_backup=%v", conf.ExcludeDataFromBackup))
 	}
+	if conf.ClosedTimestampTargetDuration != defaultConf.ClosedTimestampTargetDuration {
+		diffs = append(diffs, fmt.Sprintf("closed_timestamp_target_duration=%s", conf.ClosedTimestampTargetDuration))
+	}
+	if conf.LatencyBasedLeaseRebalancing != defaultConf.LatencyBasedLeaseRebalancing {
+		diffs = append(diffs, fmt.Sprintf("latency_based_lease_rebalancing=%t", conf.LatencyBasedLeaseRebalancing))
+	}
 
 	return strings.Join(diffs, " ")
 }
@@ -1067,6 +1067,48 @@ func applyColumnMutation(
 				"column %q is not a stored computed column", col.GetName())
 		}
 		col.ColumnDesc().ComputeExpr = nil
+
+	case *tree.AlterTableSetGenerated:
+		if !col.IsGeneratedAsIdentity() {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"column %q of relation %q is not an identity column", col.GetName(), tableDesc.GetName())
+		}
+		switch t.GeneratedAsIdentityType {
+		case tree.GeneratedAlways:
+			col.ColumnDesc().GeneratedAsIdentityType = catpb.GeneratedAsIdentityType_GENERATED_ALWAYS
+		case tree.GeneratedByDefault:
+			col.ColumnDesc().GeneratedAsIdentityType = catpb.GeneratedAsIdentityType_GENERATED_BY_DEFAULT
+		}
+
+	case *tree.AlterTableDropIdentity:
+		if !col.IsGeneratedAsIdentity() {
+			if t.IfExists {
+				params.p.BufferClientNotice(params.ctx, pgnotice.Newf(
+					"column %q of relation %q is not an identity column, skipping",
+					col.GetName(), tableDesc.GetName()))
+				return nil
+			}
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"column %q of relation %q is not an identity column", col.GetName(), tableDesc.GetName())
+		}
+		// The column's default expression uses the sequence backing the identity,
+		// which is owned by the column. As in Postgres, the sequence is dropped
+		// along with the identity.
+		if col.NumUsesSequences() > 0 {
+			if err := params.p.removeSequenceDependencies(params.ctx, tableDesc, col); err != nil {
+				return err
+			}
+		}
+		if err := params.p.canRemoveAllColumnOwnedSequences(params.ctx, tableDesc, col, tree.DropRestrict); err != nil {
+			return err
+		}
+		if err := params.p.dropSequencesOwnedByCol(params.ctx, col, true /* queueJob */, tree.DropRestrict); err != nil {
+			return err
+		}
+		colDesc := col.ColumnDesc()
+		colDesc.DefaultExpr = nil
+		colDesc.GeneratedAsIdentityType = catpb.GeneratedAsIdentityType_NOT_IDENTITY_COLUMN
+		colDesc.GeneratedAsIdentitySequenceOption = nil
 	}
 	return nil
 }
@@ -64,6 +64,7 @@ var (
 		catconstants.SpanCountTableName,
 		catconstants.SystemPrivilegeTableName,
 		catconstants.SystemExternalConnectionsTableName,
+		catconstants.PlanPinsTableName,
 	}
 
 	readWriteSystemSequences = []catconstants.SystemTableName{
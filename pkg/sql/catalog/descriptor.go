@@ -319,6 +319,10 @@ type TableDescriptor interface {
 	// created at, for materialized views and CREATE TABLE AS. Only valid if
 	// IsAs or MaterializedView returns true.
 	GetCreateAsOfTime() hlc.Timestamp
+	// GetLastRefreshTime returns the timestamp as of which the data of a
+	// materialized view was last computed. Only valid if MaterializedView
+	// returns true.
+	GetLastRefreshTime() hlc.Timestamp
 
 	// GetViewQuery returns this view's CREATE VIEW declaration. Only valid if
 	// IsView is true.
@@ -187,6 +187,83 @@ func (tc *Collection) ReleaseAll(ctx context.Context) {
 	tc.skipValidationOnWrite = false
 }
 
+// UncommittedSnapshot is a snapshot of the uncommitted descriptors layer of a
+// Collection. It is taken when a savepoint is created so that the descriptor
+// changes made after it can be undone by ROLLBACK TO SAVEPOINT, see
+// RollbackUncommittedToSnapshot.
+type UncommittedSnapshot struct {
+	descs map[descpb.ID]catalog.Descriptor
+}
+
+// SnapshotUncommitted returns a snapshot of the uncommitted descriptors layer.
+func (tc *Collection) SnapshotUncommitted() UncommittedSnapshot {
+	var snap UncommittedSnapshot
+	_ = tc.uncommitted.iterateUncommittedByID(func(desc catalog.Descriptor) error {
+		if snap.descs == nil {
+			snap.descs = make(map[descpb.ID]catalog.Descriptor)
+		}
+		snap.descs[desc.GetID()] = desc
+		return nil
+	})
+	return snap
+}
+
+// CheckRollbackUncommittedToSnapshot returns an error if the uncommitted
+// descriptors can't be rolled back to the snapshot by
+// RollbackUncommittedToSnapshot. It does not modify the Collection.
+func (tc *Collection) CheckRollbackUncommittedToSnapshot(snap UncommittedSnapshot) error {
+	_, err := tc.uncommittedCreatedSinceSnapshot(snap)
+	return err
+}
+
+// RollbackUncommittedToSnapshot discards the uncommitted descriptors which
+// were created since the snapshot was taken. This mirrors the rollback of the
+// transaction's KV writes to the corresponding savepoint.
+//
+// Only the creation of new descriptors can be undone. If any descriptor which
+// existed when the snapshot was taken has been modified since, an error is
+// returned and the Collection is left unchanged.
+func (tc *Collection) RollbackUncommittedToSnapshot(snap UncommittedSnapshot) error {
+	created, err := tc.uncommittedCreatedSinceSnapshot(snap)
+	if err != nil {
+		return err
+	}
+	for _, id := range created {
+		tc.uncommitted.remove(id)
+		tc.stored.RemoveFromCache(id)
+	}
+	return nil
+}
+
+// uncommittedCreatedSinceSnapshot returns the IDs of the uncommitted
+// descriptors which were created since the snapshot was taken, or an error if
+// any descriptor which existed when the snapshot was taken has been modified
+// since.
+func (tc *Collection) uncommittedCreatedSinceSnapshot(
+	snap UncommittedSnapshot,
+) ([]descpb.ID, error) {
+	var created []descpb.ID
+	if err := tc.uncommitted.iterateUncommittedByID(func(desc catalog.Descriptor) error {
+		original, mut := tc.uncommitted.getUncommittedMutableByID(desc.GetID())
+		if prev, ok := snap.descs[desc.GetID()]; ok {
+			// The immutable copy is replaced every time the descriptor is written,
+			// so the descriptor is unchanged if it is still the same object, and
+			// if its mutable counterpart has not been modified in-place either.
+			if prev == desc && (mut == nil || mut.DescriptorProto().Equal(desc.DescriptorProto())) {
+				return nil
+			}
+		} else if original == nil {
+			created = append(created, desc.GetID())
+			return nil
+		}
+		return errors.Newf("%s %q (%d) was modified since the savepoint was created",
+			desc.DescriptorType(), desc.GetName(), desc.GetID())
+	}); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
 // HasUncommittedTables returns true if the Collection contains uncommitted
 // tables.
 func (tc *Collection) HasUncommittedTables() (has bool) {
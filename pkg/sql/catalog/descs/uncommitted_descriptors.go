@@ -157,6 +157,14 @@ func (ud *uncommittedDescriptors) ensureMutable(
 	return mut, nil
 }
 
+// remove removes all state pertaining to the descriptor with the given ID
+// from this layer.
+func (ud *uncommittedDescriptors) remove(id descpb.ID) {
+	ud.uncommitted.Remove(id)
+	ud.mutable.Remove(id)
+	ud.original.Remove(id)
+}
+
 // upsert adds an uncommitted descriptor to this layer.
 // This is called exclusively by the Collection's AddUncommittedDescriptor
 // method.
@@ -387,6 +387,19 @@ func (sc *StoredCatalog) RemoveFromNameIndex(desc catalog.Descriptor) {
 	sc.nameIndex.Remove(desc.GetID())
 }
 
+// RemoveFromCache removes a descriptor from the cache and from the name index.
+// This needs to be done if the descriptor no longer exists in storage from the
+// point of view of the transaction, which is the case when the transaction
+// rolls back to a savepoint which preceded the descriptor's creation.
+func (sc *StoredCatalog) RemoveFromCache(id descpb.ID) {
+	sc.cache.Remove(id)
+	sc.nameIndex.Remove(id)
+	delete(sc.validationLevels, id)
+	for _, schemas := range sc.allSchemasForDatabase {
+		delete(schemas, id)
+	}
+}
+
 // NewValidationDereferencer returns this StoredCatalog object wrapped in a
 // validate.ValidationDereferencer implementation. This ensures that any
 // descriptors read for the purpose of validating others are also cached.
@@ -698,6 +698,18 @@ CREATE TABLE system.external_connections (
 	CONSTRAINT "primary" PRIMARY KEY (connection_name),
 	FAMILY "primary" (connection_name, created, updated, connection_type, connection_details, owner)
 );`
+
+	// PlanPinsTableSchema stores the plan hints pinned to statement
+	// fingerprints. The optimizer applies the hints when planning a statement
+	// with a matching fingerprint.
+	PlanPinsTableSchema = `
+CREATE TABLE system.plan_pins (
+	fingerprint STRING NOT NULL,
+	hints STRING NOT NULL,
+	created TIMESTAMP NOT NULL DEFAULT now(),
+	CONSTRAINT "primary" PRIMARY KEY (fingerprint),
+	FAMILY "primary" (fingerprint, hints, created)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2562,6 +2574,36 @@ var (
 			},
 		),
 	)
+
+	// PlanPinsTable is the descriptor for the plan pins table.
+	PlanPinsTable = registerSystemTable(
+		PlanPinsTableSchema,
+		systemTable(
+			catconstants.PlanPinsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "fingerprint", ID: 1, Type: types.String},
+				{Name: "hints", ID: 2, Type: types.String},
+				{Name: "created", ID: 3, Type: types.Timestamp, DefaultExpr: &nowString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"fingerprint", "hints", "created"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"fingerprint"},
+				KeyColumnDirections: singleASC,
+				KeyColumnIDs:        singleID1,
+			},
+		),
+	)
 )
 
 type descRefByName struct {
@@ -1796,6 +1796,11 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				desc.LastRefreshTime = t.MaterializedViewRefresh.AsOf
+			} else {
+				desc.LastRefreshTime = hlc.Timestamp{}
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
@@ -963,6 +963,36 @@ func NewColOperator(
 							newAggArgs.MemAccount = ehaMemAccount
 							newAggArgs.Input = input
 							ehaHashTableAllocator := colmem.NewAllocator(ctx, ehaAccounts[1], factory)
+							// The partitions that the external hash aggregator
+							// cannot split any further are re-aggregated by
+							// another in-memory hash aggregator which gets the
+							// same memory limits as the original one.
+							reaggOpName := ehaOpName + "-reaggregation"
+							reaggMemAccount, reaggMemMonitorName := args.MonitorRegistry.CreateMemAccountForSpillStrategyWithLimit(
+								ctx, flowCtx, hashAggregationMemLimit, reaggOpName, spec.ProcessorID,
+							)
+							reaggHashTableMemAccount := args.MonitorRegistry.CreateExtraMemAccountForSpillStrategy(
+								string(reaggMemMonitorName),
+							)
+							reaggAccounts := args.MonitorRegistry.CreateUnlimitedMemAccounts(
+								ctx, flowCtx, reaggOpName, spec.ProcessorID, 4, /* numAccounts */
+							)
+							reaggregationArgs := &colexecdisk.PartitionReaggregationArgs{
+								Allocator:                colmem.NewLimitedAllocator(ctx, reaggMemAccount, reaggAccounts[0], factory),
+								MemAccount:               reaggMemAccount,
+								HashTableAllocator:       colmem.NewLimitedAllocator(ctx, reaggHashTableMemAccount, reaggAccounts[1], factory),
+								MemMonitorName:           reaggMemMonitorName,
+								OutputUnlimitedAllocator: colmem.NewAllocator(ctx, reaggAccounts[2], factory),
+								NewSpillingQueueArgs: &colexecutils.NewSpillingQueueArgs{
+									UnlimitedAllocator: colmem.NewAllocator(ctx, reaggAccounts[3], factory),
+									Types:              inputTypes,
+									MemoryLimit:        inputTuplesTrackingMemLimit,
+									DiskQueueCfg:       args.DiskQueueCfg,
+									DiskAcc: args.MonitorRegistry.CreateDiskAccount(
+										ctx, flowCtx, reaggMemMonitorName+"-spilling-queue", spec.ProcessorID,
+									),
+								},
+							}
 							eha, toClose := colexecdisk.NewExternalHashAggregator(
 								flowCtx,
 								args,
@@ -972,6 +1002,7 @@ func NewColOperator(
 								ehaHashTableAllocator,
 								colmem.NewAllocator(ctx, ehaAccounts[2], factory),
 								maxOutputBatchMemSize,
+								reaggregationArgs,
 							)
 							result.ToClose = append(result.ToClose, toClose)
 							return eha
@@ -14,23 +14,58 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecargs"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
 	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/redact"
 	"github.com/marusama/semaphore"
 )
 
 const (
 	// This limit comes from the fallback strategy where we are using an
-	// external sort.
-	ehaNumRequiredActivePartitions = colexecop.ExternalSorterMinPartitions
+	// external sort (plus one partition for the spilling queue of the hash
+	// aggregator that re-aggregates the partition first).
+	ehaNumRequiredActivePartitions = colexecop.ExternalSorterMinPartitions + 1
 )
 
+// PartitionReaggregationArgs contains the arguments for the in-memory hash
+// aggregator that is used by the "fallback" strategy of the external hash
+// aggregator to re-aggregate a partition before resorting to the external
+// sort.
+type PartitionReaggregationArgs struct {
+	// Allocator and MemAccount are the limited allocator and memory account
+	// used by the hash aggregator.
+	Allocator  *colmem.Allocator
+	MemAccount *mon.BoundAccount
+	// HashTableAllocator is the limited allocator for the hash table.
+	HashTableAllocator *colmem.Allocator
+	// MemMonitorName is the name of the monitor of the limited memory
+	// accounts. Only the out of memory errors coming from this monitor make
+	// the partition be processed using the external sort.
+	MemMonitorName redact.RedactableString
+	// OutputUnlimitedAllocator is used for the output batch of the hash
+	// aggregator.
+	OutputUnlimitedAllocator *colmem.Allocator
+	// NewSpillingQueueArgs are used to track the input tuples of the
+	// partition so that they can be exported to the external sort. Note that
+	// the FDSemaphore is ignored since the hash-based partitioner acquires
+	// all file descriptors up front.
+	NewSpillingQueueArgs *colexecutils.NewSpillingQueueArgs
+}
+
 // NewExternalHashAggregator returns a new disk-backed hash aggregator. It uses
 // the in-memory hash aggregator as the "main" strategy for the hash-based
 // partitioner and the external sort + ordered aggregator as the "fallback".
+//
+// If reaggregationArgs is non-nil, the "fallback" strategy first attempts to
+// aggregate each partition with a separate in-memory hash aggregator that has
+// a memory limit. A partition usually ends up there because it has too many
+// tuples rather than too many groups, so the number of its groups often fits
+// under the limit. Only if it doesn't, the external sort + ordered aggregator
+// take over.
 func NewExternalHashAggregator(
 	flowCtx *execinfra.FlowCtx,
 	args *colexecargs.NewColOperatorArgs,
@@ -40,6 +75,7 @@ func NewExternalHashAggregator(
 	hashTableAllocator *colmem.Allocator,
 	outputUnlimitedAllocator *colmem.Allocator,
 	maxOutputBatchMemSize int64,
+	reaggregationArgs *PartitionReaggregationArgs,
 ) (colexecop.Operator, colexecop.Closer) {
 	inMemMainOpConstructor := func(partitionedInputs []*partitionerToOperator) colexecop.ResettableOperator {
 		newAggArgs := *newAggArgs
@@ -57,12 +93,34 @@ func NewExternalHashAggregator(
 		maxNumberActivePartitions int,
 		_ semaphore.Semaphore,
 	) colexecop.ResettableOperator {
+		// One partition is reserved for the spilling queue of the hash
+		// aggregator re-aggregating the partition.
+		maxNumberSortPartitions := maxNumberActivePartitions - 1
+		newSortedAggregator := func(input colexecop.Operator) colexecop.Operator {
+			newAggArgs := *newAggArgs
+			newAggArgs.Input = createDiskBackedSorter(
+				input, newAggArgs.InputTypes,
+				makeOrdering(spec.GroupCols), maxNumberSortPartitions,
+			)
+			return colexec.NewOrderedAggregator(&newAggArgs)
+		}
+		if reaggregationArgs == nil {
+			return newSortedAggregator(partitionedInputs[0]).(colexecop.ResettableOperator)
+		}
 		newAggArgs := *newAggArgs
-		newAggArgs.Input = createDiskBackedSorter(
-			partitionedInputs[0], newAggArgs.InputTypes,
-			makeOrdering(spec.GroupCols), maxNumberActivePartitions,
+		newAggArgs.Input = partitionedInputs[0]
+		newAggArgs.Allocator = reaggregationArgs.Allocator
+		newAggArgs.MemAccount = reaggregationArgs.MemAccount
+		newSpillingQueueArgs := *reaggregationArgs.NewSpillingQueueArgs
+		newSpillingQueueArgs.FDSemaphore = nil
+		reaggregator := colexec.NewHashAggregator(
+			&newAggArgs, &newSpillingQueueArgs, reaggregationArgs.HashTableAllocator,
+			reaggregationArgs.OutputUnlimitedAllocator, maxOutputBatchMemSize,
 		)
-		return colexec.NewOrderedAggregator(&newAggArgs)
+		return NewOneInputDiskSpiller(
+			partitionedInputs[0], reaggregator.(colexecop.BufferingInMemoryOperator),
+			reaggregationArgs.MemMonitorName, newSortedAggregator, nil, /* spillingCallbackFn */
+		).(colexecop.ResettableOperator)
 	}
 	eha := newHashBasedPartitioner(
 		newAggArgs.Allocator,
@@ -0,0 +1,191 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexecdisk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecagg"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecargs"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/colcontainerutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExternalHashAggregatorPartitionReaggregation verifies that a partition
+// that cannot be split any further is re-aggregated by the in-memory hash
+// aggregator configured via PartitionReaggregationArgs, and that the external
+// sort + ordered aggregator are only used when that hash aggregator runs out
+// of memory.
+func TestExternalHashAggregatorPartitionReaggregation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	flowCtx := &execinfra.FlowCtx{
+		EvalCtx: &evalCtx,
+		Cfg: &execinfra.ServerConfig{
+			Settings: st,
+		},
+		DiskMonitor: testDiskMonitor,
+	}
+
+	queueCfg, cleanup := colcontainerutils.NewTestingDiskQueueCfg(t, true /* inMem */)
+	defer cleanup()
+	var monitorRegistry colexecargs.MonitorRegistry
+	defer monitorRegistry.Close(ctx)
+
+	// All tuples belong to the same group, so the partition containing them
+	// doesn't get any smaller when it is repartitioned and must be processed
+	// using the "fallback" strategy.
+	typs := []*types.T{types.Int, types.Int}
+	batch := testAllocator.NewMemBatchWithFixedCapacity(typs, coldata.BatchSize())
+	for i := 0; i < coldata.BatchSize(); i++ {
+		batch.ColVec(0).Int64()[i] = 1
+		batch.ColVec(1).Int64()[i] = 1
+	}
+	batch.SetLength(coldata.BatchSize())
+	const numBatches = 4
+	aggSpec := &execinfrapb.AggregatorSpec{
+		GroupCols: []uint32{0},
+		Aggregations: []execinfrapb.AggregatorSpec_Aggregation{
+			{Func: execinfrapb.AnyNotNull, ColIdx: []uint32{0}},
+			{Func: execinfrapb.SumInt, ColIdx: []uint32{1}},
+		},
+	}
+	constructors, constArguments, outputTypes, err := colexecagg.ProcessAggregations(
+		&evalCtx, nil /* semaCtx */, aggSpec.Aggregations, typs,
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		reaggregationMemLimit int64
+		expectedSpilled       bool
+	}{
+		{
+			reaggregationMemLimit: execinfra.DefaultMemoryLimit,
+			expectedSpilled:       false,
+		},
+		{
+			// The limit of 1 byte makes the re-aggregating hash aggregator
+			// run out of memory right away.
+			reaggregationMemLimit: 1,
+			expectedSpilled:       true,
+		},
+	} {
+		t.Run(fmt.Sprintf("limit=%d", tc.reaggregationMemLimit), func(t *testing.T) {
+			const processorID = 1
+			const reaggOpName = "external-hash-aggregator-reaggregation"
+			reaggMemAccount, reaggMemMonitorName := monitorRegistry.CreateMemAccountForSpillStrategyWithLimit(
+				ctx, flowCtx, tc.reaggregationMemLimit, reaggOpName, processorID,
+			)
+			reaggHashTableMemAccount := monitorRegistry.CreateExtraMemAccountForSpillStrategy(
+				string(reaggMemMonitorName),
+			)
+			reaggAccounts := monitorRegistry.CreateUnlimitedMemAccounts(
+				ctx, flowCtx, reaggOpName, processorID, 4, /* numAccounts */
+			)
+			reaggregationArgs := &PartitionReaggregationArgs{
+				Allocator:                colmem.NewLimitedAllocator(ctx, reaggMemAccount, reaggAccounts[0], testColumnFactory),
+				MemAccount:               reaggMemAccount,
+				HashTableAllocator:       colmem.NewLimitedAllocator(ctx, reaggHashTableMemAccount, reaggAccounts[1], testColumnFactory),
+				MemMonitorName:           reaggMemMonitorName,
+				OutputUnlimitedAllocator: colmem.NewAllocator(ctx, reaggAccounts[2], testColumnFactory),
+				NewSpillingQueueArgs: &colexecutils.NewSpillingQueueArgs{
+					UnlimitedAllocator: colmem.NewAllocator(ctx, reaggAccounts[3], testColumnFactory),
+					Types:              typs,
+					MemoryLimit:        tc.reaggregationMemLimit,
+					DiskQueueCfg:       queueCfg,
+					DiskAcc: monitorRegistry.CreateDiskAccount(
+						ctx, flowCtx, reaggMemMonitorName+"-spilling-queue", processorID,
+					),
+				},
+			}
+			var sortUsed bool
+			createDiskBackedSorter := func(
+				input colexecop.Operator, inputTypes []*types.T,
+				orderingCols []execinfrapb.Ordering_Column, _ int,
+			) colexecop.Operator {
+				return colexec.NewSorter(
+					testAllocator, &colexecop.CallbackOperator{
+						InitCb: input.Init,
+						NextCb: func() coldata.Batch {
+							sortUsed = true
+							return input.Next()
+						},
+					}, inputTypes, orderingCols, execinfra.DefaultMemoryLimit,
+				)
+			}
+			args := &colexecargs.NewColOperatorArgs{
+				Spec: &execinfrapb.ProcessorSpec{
+					Core: execinfrapb.ProcessorCoreUnion{Aggregator: aggSpec},
+				},
+				DiskQueueCfg: queueCfg,
+				FDSemaphore:  colexecop.NewTestingSemaphore(ehaNumRequiredActivePartitions),
+			}
+			// Force the repartitioning of the only partition so that it ends
+			// up being processed using the "fallback" strategy.
+			args.TestingKnobs.NumForcedRepartitions = 1
+			eha, closer := NewExternalHashAggregator(
+				flowCtx,
+				args,
+				&colexecagg.NewAggregatorArgs{
+					Allocator:      testAllocator,
+					MemAccount:     testMemAcc,
+					Input:          colexectestutils.NewFiniteBatchSource(testAllocator, batch, typs, numBatches),
+					InputTypes:     typs,
+					Spec:           aggSpec,
+					EvalCtx:        &evalCtx,
+					Constructors:   constructors,
+					ConstArguments: constArguments,
+					OutputTypes:    outputTypes,
+				},
+				createDiskBackedSorter,
+				testDiskAcc,
+				testAllocator,
+				testAllocator,
+				execinfra.DefaultMemoryLimit,
+				reaggregationArgs,
+			)
+
+			eha.Init(ctx)
+			var actual [][2]int64
+			for b := eha.Next(); b.Length() > 0; b = eha.Next() {
+				for i := 0; i < b.Length(); i++ {
+					actual = append(actual, [2]int64{b.ColVec(0).Int64()[i], b.ColVec(1).Int64()[i]})
+				}
+			}
+			require.NoError(t, closer.Close(ctx))
+			require.Equal(t, [][2]int64{{1, int64(numBatches * coldata.BatchSize())}}, actual)
+
+			hbp := eha.(*hashBasedPartitioner)
+			require.Equal(t, tc.expectedSpilled, hbp.diskBackedFallbackOp.(*diskSpillerBase).spilled)
+			require.Equal(t, tc.expectedSpilled, sortUsed)
+			require.Zero(t, args.FDSemaphore.GetCount(), "sem still reports open FDs")
+		})
+	}
+}
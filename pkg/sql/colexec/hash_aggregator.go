@@ -239,12 +239,17 @@ func (op *hashAggregator) Init(ctx context.Context) {
 		return
 	}
 	op.Input.Init(op.Ctx)
+	op.ht = op.newHashTable(op.Ctx)
+}
+
+// newHashTable creates a new hash table used to find the aggregation buckets.
+func (op *hashAggregator) newHashTable(ctx context.Context) *colexechash.HashTable {
 	// These numbers were chosen after running the micro-benchmarks and relevant
 	// TPCH queries using tpchvec/bench.
 	const hashTableLoadFactor = 0.1
 	const hashTableNumBuckets = 256
-	op.ht = colexechash.NewHashTable(
-		op.Ctx,
+	return colexechash.NewHashTable(
+		ctx,
 		op.hashTableAllocator,
 		hashTableLoadFactor,
 		hashTableNumBuckets,
@@ -463,7 +468,15 @@ func (op *hashAggregator) Reset(ctx context.Context) {
 func (op *hashAggregator) resetBucketsAndTrackingState(ctx context.Context) {
 	// Set up buckets for reuse.
 	op.buckets = op.buckets[:0]
-	op.ht.Reset(ctx)
+	if op.ht == nil {
+		// The hash table has been released when the buffered tuples were
+		// exported, so we need to create a new one since the operator is
+		// reused (e.g. for the next partition of the external hash
+		// aggregator).
+		op.ht = op.newHashTable(ctx)
+	} else {
+		op.ht.Reset(ctx)
+	}
 	if op.inputTrackingState.tuples != nil {
 		op.inputTrackingState.tuples.Reset(ctx)
 		op.inputTrackingState.zeroBatchEnqueued = false
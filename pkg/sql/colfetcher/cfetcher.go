@@ -214,15 +214,17 @@ type cFetcher struct {
 
 	// fetcher is the underlying fetcher that provides KVs.
 	fetcher *row.KVFetcher
-	// bytesRead and batchRequestsIssued store the total number of bytes read
-	// and of BatchRequests issued, respectively, by this cFetcher throughout
-	// its lifetime in case when the underlying row.KVFetcher has already been
-	// closed and nil-ed out.
+	// bytesRead, batchRequestsIssued and spansIssued store the total number of
+	// bytes read, of BatchRequests issued and of spans requested, respectively,
+	// by this cFetcher throughout its lifetime in case when the underlying
+	// row.KVFetcher has already been closed and nil-ed out.
 	//
 	// The fields should not be accessed directly by the users of the cFetcher -
-	// getBytesRead() and getBatchRequestsIssued() should be used instead.
+	// getBytesRead(), getBatchRequestsIssued() and getSpansIssued() should be
+	// used instead.
 	bytesRead           int64
 	batchRequestsIssued int64
+	spansIssued         int64
 
 	// machine contains fields that get updated during the run of the fetcher.
 	machine struct {
@@ -1288,6 +1290,15 @@ func (cf *cFetcher) getBatchRequestsIssued() int64 {
 	return cf.batchRequestsIssued
 }
 
+// getSpansIssued returns the number of spans requested by the cFetcher
+// throughout its lifetime so far.
+func (cf *cFetcher) getSpansIssued() int64 {
+	if cf.fetcher != nil {
+		return cf.fetcher.GetSpansIssued()
+	}
+	return cf.spansIssued
+}
+
 var cFetcherPool = sync.Pool{
 	New: func() interface{} {
 		return &cFetcher{}
@@ -1312,6 +1323,7 @@ func (cf *cFetcher) Close(ctx context.Context) {
 	if cf != nil && cf.fetcher != nil {
 		cf.bytesRead = cf.fetcher.GetBytesRead()
 		cf.batchRequestsIssued = cf.fetcher.GetBatchRequestsIssued()
+		cf.spansIssued = cf.fetcher.GetSpansIssued()
 		cf.fetcher.Close(ctx)
 		cf.fetcher = nil
 	}
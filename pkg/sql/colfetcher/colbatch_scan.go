@@ -165,6 +165,13 @@ func (s *ColBatchScan) GetBatchRequestsIssued() int64 {
 	return s.cf.getBatchRequestsIssued()
 }
 
+// GetSpansIssued is part of the colexecop.KVReader interface.
+func (s *ColBatchScan) GetSpansIssued() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cf.getSpansIssued()
+}
+
 // GetCumulativeContentionTime is part of the colexecop.KVReader interface.
 func (s *ColBatchScan) GetCumulativeContentionTime() time.Duration {
 	return execstats.GetCumulativeContentionTime(s.Ctx, nil /* recording */)
@@ -228,6 +235,7 @@ func NewColBatchScan(
 		flowCtx.EvalCtx.SessionData().LockTimeout,
 		kvFetcherMemAcc,
 		flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
+		spec.FetchSpec.DatabaseID,
 	)
 
 	fetcher := cFetcherPool.Get().(*cFetcher)
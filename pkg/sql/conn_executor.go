@@ -839,8 +839,9 @@ func (s *Server) newSessionData(args SessionArgs) *sessiondata.SessionData {
 			RemoteAddr: args.RemoteAddr,
 		},
 		LocalOnlySessionData: sessiondatapb.LocalOnlySessionData{
-			ResultsBufferSize: args.ConnResultsBufferSize,
-			IsSuperuser:       args.IsSuperuser,
+			ResultsBufferSize:           args.ConnResultsBufferSize,
+			ResultsBufferSizeOverridden: args.ConnResultsBufferSizeOverridden,
+			IsSuperuser:                 args.IsSuperuser,
 		},
 	}
 	if len(args.CustomOptionSessionDefaults) > 0 {
@@ -1001,6 +1002,7 @@ func (s *Server) newConnExecutor(
 		portals:   make(map[string]PreparedPortal),
 	}
 	ex.extraTxnState.prepStmtsNamespaceMemAcc = ex.sessionMon.MakeBoundAccount()
+	ex.resultsBufferAcc = ex.sessionMon.MakeBoundAccount()
 	ex.extraTxnState.descCollection = s.cfg.CollectionFactory.NewCollection(ctx, descs.NewTemporarySchemaProvider(sdMutIterator.sds), ex.sessionMon)
 	ex.extraTxnState.jobs = new(jobsCollection)
 	ex.extraTxnState.txnRewindPos = -1
@@ -1140,6 +1142,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 		)
 		ex.extraTxnState.prepStmtsNamespaceMemAcc.Close(ctx)
 		ex.extraTxnState.sqlCursors.closeAll()
+		ex.resultsBufferAcc.Close(ctx)
 	}
 
 	if ex.sessionTracing.Enabled() {
@@ -1209,6 +1212,10 @@ type connExecutor struct {
 	// statistics for result sets (which escape transactions).
 	mon        *mon.BytesMonitor
 	sessionMon *mon.BytesMonitor
+	// resultsBufferAcc accounts for the bytes by which the results buffer of
+	// the connection can grow beyond the session's results buffer size, see
+	// readOnlyResultsBufferSize.
+	resultsBufferAcc mon.BoundAccount
 	// memMetrics contains the metrics that statements executed on this connection
 	// will contribute to.
 	memMetrics MemoryMetrics
@@ -1002,6 +1002,43 @@ func (ex *connExecutor) createJobs(ctx context.Context) error {
 	return nil
 }
 
+// isReadOnlyPlan returns whether the plan for the statement returns rows
+// without performing any writes.
+func isReadOnlyPlan(stmt tree.Statement, flags planFlags) bool {
+	return stmt.StatementReturnType() == tree.Rows &&
+		!flags.IsSet(planFlagContainsMutation) && !flags.IsSet(planFlagIsDDL)
+}
+
+// maybeRaiseResultsBufferLimit raises the number of bytes of results that can
+// be buffered for the statement to readOnlyResultsBufferSize, if the statement
+// is a read-only statement in an implicit transaction and the session's
+// results buffer size was not set explicitly. The bytes by which the buffer
+// can exceed the session's results buffer size are accounted for in the
+// session's memory monitor; if they can't be, the limit isn't raised.
+func (ex *connExecutor) maybeRaiseResultsBufferLimit(
+	ctx context.Context, res RestrictedCommandResult, readOnlyImplicitTxn bool,
+) {
+	sd := ex.sessionData()
+	limit := readOnlyResultsBufferSize.Get(&ex.server.cfg.Settings.SV)
+	var extra int64
+	// A session buffer size of zero disables buffering altogether.
+	if readOnlyImplicitTxn && !sd.ResultsBufferSizeOverridden &&
+		sd.ResultsBufferSize > 0 && limit > sd.ResultsBufferSize {
+		extra = limit - sd.ResultsBufferSize
+	}
+	// The account is resized for every statement, since the results of the
+	// previous statements have been flushed or are covered by the session's
+	// results buffer size by now.
+	if err := ex.resultsBufferAcc.ResizeTo(ctx, extra); err != nil {
+		log.VEventf(ctx, 2, "not raising the results buffer limit: %v", err)
+		ex.resultsBufferAcc.Clear(ctx)
+		return
+	}
+	if extra > 0 {
+		res.SetBufferLimit(limit)
+	}
+}
+
 // rollbackSQLTransaction executes a ROLLBACK statement: the KV transaction is
 // rolled-back and an event is produced.
 func (ex *connExecutor) rollbackSQLTransaction(
@@ -1068,6 +1105,13 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	// directly. Configure this here.
 	if planner.curPlan.avoidBuffering || ex.sessionData().AvoidBuffering {
 		res.DisableBuffering()
+	} else {
+		// Read-only statements in implicit transactions can be retried
+		// transparently as long as none of their results have been sent to the
+		// client, so let them buffer more results.
+		ex.maybeRaiseResultsBufferLimit(
+			ctx, res, planner.autoCommit && isReadOnlyPlan(stmt.AST, planner.curPlan.flags),
+		)
 	}
 
 	var stmtFingerprintID roachpb.StmtFingerprintID
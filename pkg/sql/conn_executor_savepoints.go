@@ -16,13 +16,16 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/errors"
 )
 
 // commitOnReleaseSavepointName is the name of the savepoint with special
@@ -105,6 +108,7 @@ func (ex *connExecutor) execSavepointInOpenState(
 		commitOnRelease: commitOnRelease,
 		kvToken:         token,
 		numDDL:          ex.extraTxnState.numDDL,
+		schemaChanges:   ex.snapshotSchemaChanges(),
 	}
 	savepoints.push(sp)
 	ex.sessionDataStack.PushTopClone()
@@ -204,6 +208,10 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 		return ev, payload
 	}
 
+	if err := ex.rollbackSchemaChangesToSavepoint(entry); err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+
 	if err := ex.popSavepointsToIdx(s, idx); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
@@ -216,7 +224,10 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 }
 
 // checkRollbackValidity verifies that a ROLLBACK TO SAVEPOINT
-// statement should be allowed in the current txn state.
+// statement should be allowed in the current txn state. If DDL statements
+// were executed since a regular savepoint was created, it also verifies that
+// the schema changes they staged in the txn can be rolled back. It has no side
+// effects.
 // It returns ok == false if the operation should be prevented
 // from proceeding, in which case it also populates the event
 // and payload with a suitable user error.
@@ -229,36 +240,104 @@ func (ex *connExecutor) checkRollbackValidity(
 		return ev, payload, true
 	}
 
-	if !entry.kvToken.Initial() {
-		// We don't yet support rolling back a regular savepoint over
-		// DDL. Instead of creating an inconsistent txn or schema state,
-		// prefer to tell the users we don't know how to proceed
-		// yet. Initial savepoints are a special case - we can always
-		// rollback to them because we can reset all the schema change
-		// state.
-		ev, payload = ex.makeErrEvent(unimplemented.NewWithIssueDetail(10735, "rollback-after-ddl",
-			"ROLLBACK TO SAVEPOINT not yet supported after DDL statements"), s)
-		return ev, payload, false
-	}
-
 	if ex.state.mu.txn.UserPriority() == roachpb.MaxUserPriority {
 		// Because we use the same priority (MaxUserPriority) for SET
 		// TRANSACTION PRIORITY HIGH and lease acquisitions, we'd get a
 		// deadlock if we let DDL proceed at high priority.
 		// See https://github.com/cockroachdb/cockroach/issues/46414
 		// for details.
-		//
-		// Note: this check must remain even when regular savepoints are
-		// taught to roll back over DDL (that's the other check in
-		// execSavepointInOpenState), until #46414 gets solved.
 		ev, payload = ex.makeErrEvent(unimplemented.NewWithIssue(46414,
 			"cannot use ROLLBACK TO SAVEPOINT in a HIGH PRIORITY transaction containing DDL"), s)
 		return ev, payload, false
 	}
 
+	if !entry.kvToken.Initial() {
+		// Initial savepoints are a special case - we can always rollback to
+		// them because we can reset all the schema change state. For regular
+		// savepoints, we can only undo the schema changes which created new
+		// descriptors. Instead of creating an inconsistent txn or schema state
+		// in the other cases, prefer to tell the users we don't know how to
+		// proceed yet.
+		if err := ex.checkSchemaChangesRollback(entry.schemaChanges); err != nil {
+			ev, payload = ex.makeErrEvent(errors.WithDetail(
+				unimplemented.NewWithIssueDetail(10735, "rollback-after-ddl",
+					"ROLLBACK TO SAVEPOINT not yet supported after DDL statements "+
+						"which modify existing schema objects"),
+				err.Error(),
+			), s)
+			return ev, payload, false
+		}
+	}
+
 	return ev, payload, true
 }
 
+// rollbackSchemaChangesToSavepoint rolls back the schema change state staged
+// in the txn by the DDL statements executed since the given savepoint was
+// created. It must be called once the KV writes of the txn were rolled back to
+// the savepoint, since the schema change state must not be rolled back if the
+// KV writes are not. Initial savepoints are skipped: all of the schema change
+// state is reset when the txn restarts.
+func (ex *connExecutor) rollbackSchemaChangesToSavepoint(entry *savepoint) error {
+	if entry.kvToken.Initial() || ex.extraTxnState.numDDL <= entry.numDDL {
+		return nil
+	}
+	if err := ex.rollbackSchemaChanges(entry.schemaChanges); err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err,
+			"rolling back schema changes validated by checkRollbackValidity")
+	}
+	return nil
+}
+
+// snapshotSchemaChanges captures the schema change state staged in the
+// current txn, so that it can be restored by rollbackSchemaChanges.
+func (ex *connExecutor) snapshotSchemaChanges() savepointSchemaChanges {
+	snap := savepointSchemaChanges{
+		descs:               ex.extraTxnState.descCollection.SnapshotUncommitted(),
+		numJobs:             len(*ex.extraTxnState.jobs),
+		numDeclarativeStmts: len(ex.extraTxnState.schemaChangerState.stmts),
+	}
+	for id := range ex.extraTxnState.schemaChangeJobRecords {
+		snap.jobRecordIDs.Add(id)
+	}
+	return snap
+}
+
+// checkSchemaChangesRollback returns an error if the schema change state
+// staged in the current txn can't be restored to the given snapshot by
+// rollbackSchemaChanges. This is only possible if the schema changes made
+// since the snapshot only created new descriptors. It does not modify the
+// state.
+func (ex *connExecutor) checkSchemaChangesRollback(snap savepointSchemaChanges) error {
+	if len(ex.extraTxnState.schemaChangerState.stmts) > snap.numDeclarativeStmts {
+		return errors.New("schema changes were planned by the declarative schema changer")
+	}
+	return ex.extraTxnState.descCollection.CheckRollbackUncommittedToSnapshot(snap.descs)
+}
+
+// rollbackSchemaChanges restores the schema change state staged in the
+// current txn to the given snapshot. It must only be called once the KV
+// writes of the txn were rolled back to the corresponding savepoint, and
+// after checkSchemaChangesRollback succeeded; otherwise an error is returned
+// and the state is left untouched.
+func (ex *connExecutor) rollbackSchemaChanges(snap savepointSchemaChanges) error {
+	if err := ex.checkSchemaChangesRollback(snap); err != nil {
+		return err
+	}
+	if err := ex.extraTxnState.descCollection.RollbackUncommittedToSnapshot(snap.descs); err != nil {
+		return err
+	}
+	// The jobs created since the savepoint have been rolled back along with
+	// the rest of the KV writes, so they must not be started upon commit.
+	*ex.extraTxnState.jobs = (*ex.extraTxnState.jobs)[:snap.numJobs]
+	for id := range ex.extraTxnState.schemaChangeJobRecords {
+		if !snap.jobRecordIDs.Contains(id) {
+			delete(ex.extraTxnState.schemaChangeJobRecords, id)
+		}
+	}
+	return nil
+}
+
 func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	ctx context.Context, s *tree.RollbackToSavepoint,
 ) (fsm.Event, fsm.EventPayload) {
@@ -288,6 +367,10 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 		return ex.makeErrEvent(err, s)
 	}
 
+	if err := ex.rollbackSchemaChangesToSavepoint(entry); err != nil {
+		return ex.makeErrEvent(err, s)
+	}
+
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
 	}
@@ -347,10 +430,29 @@ type savepoint struct {
 	kvToken kv.SavepointToken
 
 	// The number of DDL statements that had been executed in the transaction (at
-	// the time the savepoint was created). We refuse to roll back a savepoint if
-	// more DDL statements were executed since the savepoint's creation.
-	// TODO(knz): support partial DDL cancellation in pending txns.
+	// the time the savepoint was created). Rolling back a savepoint after more
+	// DDL statements were executed since the savepoint's creation requires
+	// rolling back the schema changes they staged, see schemaChanges.
 	numDDL int
+
+	// schemaChanges is a snapshot of the schema change state staged in the
+	// transaction at the time the savepoint was created.
+	schemaChanges savepointSchemaChanges
+}
+
+// savepointSchemaChanges captures the schema change state staged in a
+// transaction at the time a savepoint was created.
+type savepointSchemaChanges struct {
+	// descs is a snapshot of the uncommitted descriptors.
+	descs descs.UncommittedSnapshot
+	// numJobs is the number of jobs queued in the transaction.
+	numJobs int
+	// jobRecordIDs are the descriptor IDs for which schema change job records
+	// were staged.
+	jobRecordIDs catalog.DescriptorIDSet
+	// numDeclarativeStmts is the number of statements planned by the
+	// declarative schema changer.
+	numDeclarativeStmts int
 }
 
 type savepointStack []savepoint
@@ -766,6 +766,14 @@ type RestrictedCommandResult interface {
 	// to this CommandResult, will be flushed immediately to the client.
 	// This is currently used for sinkless changefeeds.
 	DisableBuffering()
+
+	// SetBufferLimit raises the number of bytes of results that can be
+	// accumulated before they are flushed to the client, if limit is larger
+	// than the connection's results buffer size. Results that have not been
+	// flushed can be discarded if the statement is retried automatically. The
+	// caller is responsible for accounting for the memory of the larger
+	// buffer.
+	SetBufferLimit(limit int64)
 }
 
 // DescribeResult represents the result of a Describe command (for either
@@ -965,6 +973,10 @@ func (r *streamingCommandResult) DisableBuffering() {
 	panic("cannot disable buffering here")
 }
 
+// SetBufferLimit is part of the RestrictedCommandResult interface. Results are
+// never flushed to a client here, so the limit doesn't apply.
+func (r *streamingCommandResult) SetBufferLimit(int64) {}
+
 // SetError is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) SetError(err error) {
 	r.err = err
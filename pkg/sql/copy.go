@@ -125,6 +125,10 @@ type copyMachine struct {
 
 	scratchRow []tree.Datum
 
+	// bulkIngest is set if the rows are staged and bulk ingested rather than
+	// inserted batch by batch.
+	bulkIngest *copyBulkIngest
+
 	// For testing we want to be able to override this on the instance level.
 	copyBatchRowSize int
 
@@ -267,6 +271,7 @@ func newCopyMachine(
 	}
 	c.initMonitoring(ctx, parentMon)
 	c.processRows = c.insertRows
+	c.maybeInitBulkIngest(ctx, tableDesc, cols)
 	c.rows.Init(c.rowsMemAcc, colinfo.ColTypeInfoFromResCols(c.resultColumns), copyBatchRowSize)
 	c.scratchRow = make(tree.Datums, len(c.resultColumns))
 	return c, nil
@@ -313,6 +318,7 @@ type copyTxnOpt struct {
 }
 
 func (c *copyMachine) Close(ctx context.Context) {
+	c.closeBulkIngest(ctx)
 	c.rows.Close(ctx)
 	c.bufMemAcc.Close(ctx)
 	c.copyMon.Stop(ctx)
@@ -0,0 +1,361 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// copyBulkIngestBufferSize is the initial size of the buffer of the BulkAdder
+// used to ingest the rows of a COPY.
+const copyBulkIngestBufferSize = 64 << 20
+
+// copyBulkIngest holds the state of a copyMachine which stages the copied rows
+// in temp storage, and ingests them with a BulkAdder once all the data has
+// been received. This allows COPY payloads which don't fit in memory, and
+// avoids running an INSERT per batch of rows.
+//
+// The ingestion is not transactional, so it is only used to load empty tables:
+// the rows are written at the time of the ingestion, and if it fails, the
+// table is reverted to its empty state. Uniqueness violations between the
+// copied rows are detected by the BulkAdder. Rangefeeds, and so changefeeds,
+// don't support the ingested SSTs, so the ingestion is not used when they may
+// be enabled on the table.
+type copyBulkIngest struct {
+	tableID      descpb.ID
+	tableVersion descpb.DescriptorVersion
+	// cols are the columns of the table which are copied, in the order of the
+	// copied rows.
+	cols []catalog.Column
+	typs []*types.T
+
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+	// rows accumulates all the copied rows, spilling to temp storage once they
+	// no longer fit in memory.
+	rows *rowcontainer.DiskBackedRowContainer
+}
+
+// checkCopyBulkIngestTable returns an error if the rows copied into the given
+// table cannot be ingested with a BulkAdder, because the table has
+// constraints or ongoing schema changes which the ingestion doesn't handle,
+// or may have rangefeeds over it.
+func checkCopyBulkIngestTable(
+	codec keys.SQLCodec, sv *settings.Values, tableDesc catalog.TableDescriptor,
+) error {
+	if !tableDesc.IsPhysicalTable() || tableDesc.IsSequence() {
+		return errors.Newf("%s is not a table", tableDesc.GetName())
+	}
+	// Rangefeeds are enabled on the ranges of secondary tenants and of the
+	// system database regardless of the cluster setting.
+	if !codec.ForSystemTenant() || tableDesc.GetParentID() == keys.SystemDatabaseID ||
+		kvserverbase.RangefeedEnabled.Get(sv) {
+		return errors.Newf("rangefeeds may be enabled on table %s", tableDesc.GetName())
+	}
+	if len(tableDesc.AllMutations()) > 0 {
+		return errors.Newf("table %s has ongoing schema changes", tableDesc.GetName())
+	}
+	if len(tableDesc.ActiveChecks()) > 0 {
+		return errors.Newf("table %s has check constraints", tableDesc.GetName())
+	}
+	if len(tableDesc.GetUniqueWithoutIndexConstraints()) > 0 {
+		return errors.Newf("table %s has unique constraints without an index", tableDesc.GetName())
+	}
+	var hasFK bool
+	if err := tableDesc.ForeachOutboundFK(func(*descpb.ForeignKeyConstraint) error {
+		hasFK = true
+		return nil
+	}); err != nil {
+		return err
+	}
+	if hasFK {
+		return errors.Newf("table %s has foreign keys", tableDesc.GetName())
+	}
+	return nil
+}
+
+// maybeInitBulkIngest sets up the copyMachine to bulk ingest the copied rows
+// if the copy_from_bulk_ingest_enabled session variable is set. The rows are
+// inserted as usual if the COPY runs in an explicit transaction, or if the
+// table can't be bulk ingested into or is not empty.
+func (c *copyMachine) maybeInitBulkIngest(
+	ctx context.Context, tableDesc catalog.TableDescriptor, cols []catalog.Column,
+) {
+	if !c.p.SessionData().CopyFromBulkIngestEnabled {
+		return
+	}
+	if !c.implicitTxn {
+		log.VEventf(ctx, 2, "not bulk ingesting COPY in an explicit transaction")
+		return
+	}
+	execCfg := c.p.ExecCfg()
+	if err := checkCopyBulkIngestTable(execCfg.Codec, &execCfg.Settings.SV, tableDesc); err != nil {
+		log.VEventf(ctx, 2, "not bulk ingesting COPY: %v", err)
+		return
+	}
+	span := tableDesc.TableSpan(execCfg.Codec)
+	if kvs, err := execCfg.DB.Scan(ctx, span.Key, span.EndKey, 1 /* maxRows */); err != nil {
+		log.VEventf(ctx, 2, "not bulk ingesting COPY: %v", err)
+		return
+	} else if len(kvs) > 0 {
+		log.VEventf(ctx, 2, "not bulk ingesting COPY into non-empty table %s", tableDesc.GetName())
+		return
+	}
+	b := &copyBulkIngest{
+		tableID:      tableDesc.GetID(),
+		tableVersion: tableDesc.GetVersion(),
+		cols:         cols,
+		typs:         make([]*types.T, len(cols)),
+	}
+	for i, col := range cols {
+		b.typs[i] = col.GetType()
+	}
+	distSQLCfg := &c.p.ExecCfg().DistSQLSrv.ServerConfig
+	b.memMonitor = execinfra.NewLimitedMonitorNoFlowCtx(
+		ctx, c.copyMon, distSQLCfg, c.p.SessionData(), "copy-bulk-ingest-limited",
+	)
+	b.diskMonitor = execinfra.NewMonitor(ctx, distSQLCfg.ParentDiskMonitor, "copy-bulk-ingest-disk")
+	b.rows = &rowcontainer.DiskBackedRowContainer{}
+	b.rows.Init(
+		colinfo.NoOrdering, b.typs, c.parsingEvalCtx, distSQLCfg.TempStorage,
+		b.memMonitor, b.diskMonitor,
+	)
+	c.bulkIngest = b
+	c.processRows = c.stageRows
+}
+
+// closeBulkIngest releases the resources used to stage the copied rows.
+func (c *copyMachine) closeBulkIngest(ctx context.Context) {
+	if c.bulkIngest == nil {
+		return
+	}
+	c.bulkIngest.rows.Close(ctx)
+	c.bulkIngest.memMonitor.Stop(ctx)
+	c.bulkIngest.diskMonitor.Stop(ctx)
+	c.bulkIngest = nil
+}
+
+// stageRows moves the buffered rows to the staged rows and, once all the data
+// has been received, ingests the staged rows.
+func (c *copyMachine) stageRows(ctx context.Context, finalBatch bool) error {
+	scratch := make(rowenc.EncDatumRow, len(c.bulkIngest.typs))
+	for i := 0; i < c.rows.Len(); i++ {
+		for j, d := range c.rows.At(i) {
+			scratch[j] = rowenc.DatumToEncDatum(c.bulkIngest.typs[j], d)
+		}
+		if err := c.bulkIngest.rows.AddRow(ctx, scratch); err != nil {
+			return err
+		}
+	}
+	if err := c.rows.UnsafeReset(ctx); err != nil {
+		return err
+	}
+	if !finalBatch {
+		return nil
+	}
+	return c.ingestStagedRows(ctx)
+}
+
+// ingestStagedRows converts the staged rows to KVs and ingests them with a
+// BulkAdder.
+func (c *copyMachine) ingestStagedRows(ctx context.Context) (retErr error) {
+	cleanup := c.p.preparePlannerForCopy(ctx, &c.txnOpt, true /* finalBatch */, c.implicitTxn)
+	defer func() {
+		retErr = cleanup(ctx, retErr)
+	}()
+	numRows := c.bulkIngest.rows.Len()
+	if numRows == 0 {
+		return nil
+	}
+
+	// The table descriptor is leased until the transaction of the planner
+	// finishes, so schema changes of the table wait for the ingestion to
+	// complete before they start backfilling.
+	tableDesc, err := c.p.Descriptors().GetImmutableTableByID(
+		ctx, c.p.Txn(), c.bulkIngest.tableID, tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	if tableDesc.GetVersion() != c.bulkIngest.tableVersion {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"table %s was modified during COPY", tableDesc.GetName())
+	}
+	// The table is reverted to its state as of the read timestamp of the
+	// transaction if the ingestion fails, so it must be empty then.
+	span := tableDesc.TableSpan(c.p.ExecCfg().Codec)
+	if kvs, err := c.p.Txn().Scan(ctx, span.Key, span.EndKey, 1 /* maxRows */); err != nil {
+		return err
+	} else if len(kvs) > 0 {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"table %s was written to during COPY", tableDesc.GetName())
+	}
+	emptyTS := c.p.Txn().ReadTimestamp()
+
+	// All the public columns which are not computed are targeted, so that the
+	// default values of the columns which aren't copied are evaluated like in
+	// an INSERT, rather than with the deterministic variants used by IMPORT.
+	evalCtx := c.p.EvalContext()
+	var targetCols []catalog.Column
+	var targetColNames tree.NameList
+	for _, col := range tableDesc.PublicColumns() {
+		if !col.IsComputed() {
+			targetCols = append(targetCols, col)
+			targetColNames = append(targetColNames, col.ColName())
+		}
+	}
+	defaultExprs, err := schemaexpr.MakeDefaultExprs(
+		ctx, targetCols, &transform.ExprTransformContext{}, evalCtx, &c.p.semaCtx,
+	)
+	if err != nil {
+		return err
+	}
+	// copyOrds[i] is the ordinal of targetCols[i] in the copied rows, or -1 if
+	// the column isn't copied.
+	copyOrds := make([]int, len(targetCols))
+	for i, col := range targetCols {
+		copyOrds[i] = -1
+		for j, copyCol := range c.bulkIngest.cols {
+			if copyCol.GetID() == col.GetID() {
+				copyOrds[i] = j
+			}
+		}
+	}
+
+	kvCh := make(chan row.KVBatch, 10)
+	conv, err := row.NewDatumRowConverter(
+		ctx, &c.p.semaCtx, tableDesc, targetColNames, evalCtx, kvCh, nil, /* seqChunkProvider */
+		c.p.ExecCfg().GetRowMetrics(c.p.SessionData().Internal), c.p.ExecCfg().DB,
+	)
+	if err != nil {
+		return err
+	}
+
+	g := ctxgroup.WithContext(ctx)
+	g.GoCtx(func(ctx context.Context) error {
+		return c.bulkIngestKVs(ctx, tableDesc, kvCh)
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		defer close(kvCh)
+		it := c.bulkIngest.rows.NewIterator(ctx)
+		defer it.Close()
+		datums := make(tree.Datums, len(c.bulkIngest.typs))
+		var da tree.DatumAlloc
+		var rowIndex int64
+		for it.Rewind(); ; it.Next() {
+			if ok, err := it.Valid(); err != nil {
+				return err
+			} else if !ok {
+				break
+			}
+			encRow, err := it.Row()
+			if err != nil {
+				return err
+			}
+			if err := rowenc.EncDatumRowToDatums(c.bulkIngest.typs, datums, encRow, &da); err != nil {
+				return err
+			}
+			for i, ord := range copyOrds {
+				switch {
+				case ord >= 0:
+					conv.Datums[i] = datums[ord]
+				case defaultExprs == nil:
+					conv.Datums[i] = tree.DNull
+				default:
+					d, err := eval.Expr(evalCtx, defaultExprs[i])
+					if err != nil {
+						return err
+					}
+					conv.Datums[i] = d
+				}
+			}
+			if err := conv.Row(ctx, 0 /* sourceID */, rowIndex); err != nil {
+				return err
+			}
+			rowIndex++
+		}
+		return conv.SendBatch(ctx)
+	})
+	if err := g.Wait(); err != nil {
+		if revertErr := revertSpans(
+			ctx, c.p.ExecCfg().DB, []roachpb.Span{span}, emptyTS,
+			false /* ignoreGCThreshold */, RevertTableDefaultBatchSize,
+		); revertErr != nil {
+			return errors.CombineErrors(err, errors.Wrapf(revertErr,
+				"reverting the rows ingested into table %s", tableDesc.GetName()))
+		}
+		return err
+	}
+	c.insertedRows += numRows
+	return c.bulkIngest.rows.UnsafeReset(ctx)
+}
+
+// bulkIngestKVs drains the KVs from the channel until it closes, ingesting
+// them with a BulkAdder.
+func (c *copyMachine) bulkIngestKVs(
+	ctx context.Context, tableDesc catalog.TableDescriptor, kvCh <-chan row.KVBatch,
+) error {
+	writeTS := c.p.ExecCfg().Clock.Now()
+	adder, err := c.p.ExecCfg().DistSQLSrv.ServerConfig.BulkAdder(
+		ctx, c.p.ExecCfg().DB, writeTS, kvserverbase.BulkAdderOptions{
+			Name:          tableDesc.GetName(),
+			MinBufferSize: copyBulkIngestBufferSize,
+			// Shadowing existing keys is disallowed so that the rows written to
+			// the table concurrently are not overwritten.
+			DisallowShadowingBelow: writeTS,
+			WriteAtBatchTimestamp:  true,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer adder.Close(ctx)
+
+	wrapDupError := func(orig error) error {
+		var typed *kvserverbase.DuplicateKeyError
+		if !errors.As(orig, &typed) {
+			return orig
+		}
+		v := &roachpb.Value{RawBytes: typed.Value}
+		return row.NewUniquenessConstraintViolationError(ctx, tableDesc, typed.Key, v)
+	}
+	for kvBatch := range kvCh {
+		for _, kv := range kvBatch.KVs {
+			if err := adder.Add(ctx, kv.Key, kv.Value.RawBytes); err != nil {
+				return wrapDupError(err)
+			}
+		}
+	}
+	return wrapDupError(adder.Flush(ctx))
+}
@@ -353,6 +353,82 @@ func TestCopyBinary(t *testing.T) {
 	sqlDB.CheckQueryResults(t, "SELECT * FROM t ORDER BY id", expect)
 }
 
+// TestCopyBinaryBulkIngest checks that COPY ... BINARY stages the rows in temp
+// storage and bulk ingests them with copy_from_bulk_ingest_enabled set.
+func TestCopyBinaryBulkIngest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupGoDB := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "StartServer" /* prefix */, url.User(username.RootUser))
+	defer cleanupGoDB()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+
+	_, err = conn.Exec(ctx, `
+		CREATE TABLE t (
+			k INT8 PRIMARY KEY,
+			v STRING,
+			d INT8 DEFAULT 7,
+			c INT8 AS (k * 2) STORED,
+			INDEX (v)
+		);
+		CREATE TABLE r (v INT8);
+		CREATE TABLE u (k INT8 PRIMARY KEY, v STRING);
+		SET copy_from_bulk_ingest_enabled = true;
+		-- Make the staged rows spill to temp storage.
+		SET distsql_workmem = '64KiB';
+	`)
+	require.NoError(t, err)
+
+	const numRows = 5000
+	input := make([][]interface{}, numRows)
+	for i := range input {
+		input[i] = []interface{}{int64(i), fmt.Sprintf("value %d", i)}
+	}
+	n, err := conn.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"k", "v"}, pgx.CopyFromRows(input))
+	require.NoError(t, err)
+	require.Equal(t, int64(numRows), n)
+	sqlDB.CheckQueryResults(t, `SELECT count(*), sum(k), sum(d), sum(c) FROM t`, [][]string{{
+		strconv.Itoa(numRows),
+		strconv.Itoa(numRows * (numRows - 1) / 2),
+		strconv.Itoa(numRows * 7),
+		strconv.Itoa(numRows * (numRows - 1)),
+	}})
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t@t_v_idx WHERE v LIKE 'value%'`,
+		[][]string{{strconv.Itoa(numRows)}})
+
+	// Rows are inserted as usual into non-empty tables, so rows which conflict
+	// with existing rows are rejected.
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"k", "v"},
+		pgx.CopyFromRows([][]interface{}{{int64(numRows), "new"}, {int64(1), "duplicate"}}))
+	require.Error(t, err)
+	sqlDB.CheckQueryResults(t, `SELECT v FROM t WHERE k = 1`, [][]string{{"value 1"}})
+
+	// Copied rows which conflict with each other, even with identical values,
+	// are rejected, and none of the copied rows remain.
+	for _, dup := range []string{"duplicate", "value 1"} {
+		_, err = conn.CopyFrom(ctx, pgx.Identifier{"u"}, []string{"k", "v"},
+			pgx.CopyFromRows([][]interface{}{{int64(1), "value 1"}, {int64(2), "value 2"}, {int64(1), dup}}))
+		require.Error(t, err)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM u`, [][]string{{"0"}})
+	}
+
+	// The hidden primary key gets unique values across COPYs.
+	for i := 0; i < 2; i++ {
+		_, err = conn.CopyFrom(ctx, pgx.Identifier{"r"}, []string{"v"},
+			pgx.CopyFromRows([][]interface{}{{int64(1)}, {int64(2)}}))
+		require.NoError(t, err)
+	}
+	sqlDB.CheckQueryResults(t, `SELECT count(*), count(DISTINCT rowid) FROM r`, [][]string{{"4", "4"}})
+}
+
 func TestCopyError(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
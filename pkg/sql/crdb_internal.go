@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
@@ -70,6 +71,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlstats/sslocal"
 	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -115,6 +117,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalClusterTransactionsTableID:         crdbInternalClusterTxnsTable,
 		catconstants.CrdbInternalClusterSessionsTableID:             crdbInternalClusterSessionsTable,
 		catconstants.CrdbInternalClusterSettingsTableID:             crdbInternalClusterSettingsTable,
+		catconstants.CrdbInternalClosedTimestampBlockedRanges:       crdbInternalClosedTimestampBlockedRangesTable,
 		catconstants.CrdbInternalClusterStmtStatsTableID:            crdbInternalClusterStmtStatsTable,
 		catconstants.CrdbInternalCreateFunctionStmtsTableID:         crdbInternalCreateFunctionStmtsTable,
 		catconstants.CrdbInternalCreateSchemaStmtsTableID:           crdbInternalCreateSchemaStmtsTable,
@@ -129,6 +132,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalGossipAlertsTableID:                crdbInternalGossipAlertsTable,
 		catconstants.CrdbInternalGossipLivenessTableID:              crdbInternalGossipLivenessTable,
 		catconstants.CrdbInternalGossipNetworkTableID:               crdbInternalGossipNetworkTable,
+		catconstants.CrdbInternalHotKeysTableID:                     crdbInternalHotKeysTable,
 		catconstants.CrdbInternalTransactionContentionEvents:        crdbInternalTransactionContentionEventsTable,
 		catconstants.CrdbInternalIndexColumnsTableID:                crdbInternalIndexColumnsTable,
 		catconstants.CrdbInternalIndexUsageStatisticsTableID:        crdbInternalIndexUsageStatistics,
@@ -143,11 +147,15 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalLocalTransactionsTableID:           crdbInternalLocalTxnsTable,
 		catconstants.CrdbInternalLocalSessionsTableID:               crdbInternalLocalSessionsTable,
 		catconstants.CrdbInternalLocalMetricsTableID:                crdbInternalLocalMetricsTable,
+		catconstants.CrdbInternalNodeAdmissionDatabaseUsageTableID:  crdbInternalNodeAdmissionDatabaseUsageTable,
 		catconstants.CrdbInternalNodeExecutionInsightsTableID:       crdbInternalNodeExecutionInsightsTable,
+		catconstants.CrdbInternalNodePlanPinsTableID:                crdbInternalNodePlanPinsTable,
 		catconstants.CrdbInternalNodeStmtStatsTableID:               crdbInternalNodeStmtStatsTable,
 		catconstants.CrdbInternalNodeTxnStatsTableID:                crdbInternalNodeTxnStatsTable,
+		catconstants.CrdbInternalNodeTrippedCircuitBreakersTableID:  crdbInternalNodeTrippedCircuitBreakersTable,
 		catconstants.CrdbInternalPartitionsTableID:                  crdbInternalPartitionsTable,
 		catconstants.CrdbInternalPredefinedCommentsTableID:          crdbInternalPredefinedCommentsTable,
+		catconstants.CrdbInternalRangeDetailsTableID:                crdbInternalRangeDetailsTable,
 		catconstants.CrdbInternalRangesNoLeasesTableID:              crdbInternalRangesNoLeasesTable,
 		catconstants.CrdbInternalRangesViewID:                       crdbInternalRangesView,
 		catconstants.CrdbInternalRuntimeInfoTableID:                 crdbInternalRuntimeInfoTable,
@@ -1112,7 +1120,8 @@ CREATE TABLE crdb_internal.node_statement_statistics (
   database_name       STRING NOT NULL,
   exec_node_ids       INT[] NOT NULL,
   txn_fingerprint_id  STRING,
-  index_recommendations STRING[] NOT NULL
+  index_recommendations STRING[] NOT NULL,
+  indexes_used        STRING[] NOT NULL
 )`,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		hasViewActivityOrViewActivityRedacted, err := p.HasViewActivityOrViewActivityRedactedRole(ctx)
@@ -1172,6 +1181,13 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 				}
 			}
 
+			indexesUsed := tree.NewDArray(types.String)
+			for _, idx := range stats.Stats.Indexes {
+				if err := indexesUsed.Append(tree.NewDString(idx)); err != nil {
+					return err
+				}
+			}
+
 			err := addRow(
 				tree.NewDInt(tree.DInt(nodeID)),                           // node_id
 				tree.NewDString(stats.Key.App),                            // application_name
@@ -1216,6 +1232,7 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 				execNodeIDs,                         // exec_node_ids
 				txnFingerprintID,                    // txn_fingerprint_id
 				indexRecommendations,                // index_recommendations
+				indexesUsed,                         // indexes_used
 			)
 			if err != nil {
 				return err
@@ -5708,6 +5725,358 @@ CREATE TABLE crdb_internal.active_range_feeds (
 	},
 }
 
+// crdbInternalClosedTimestampBlockedRangesTable exposes the ranges with leases
+// on the current node that the closed timestamp side-transport is failing to
+// close timestamps for.
+var crdbInternalClosedTimestampBlockedRangesTable = virtualSchemaTable{
+	comment: `ranges with local leases that are failing to close timestamps (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.closed_timestamp_blocked_ranges (
+  node_id          INT NOT NULL,
+  store_id         INT NOT NULL,
+  range_id         INT NOT NULL,
+  reason           STRING NOT NULL,
+  blocked_since    TIMESTAMPTZ NOT NULL,
+  blocked_duration INTERVAL NOT NULL
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.closed_timestamp_blocked_ranges"); err != nil {
+			return err
+		}
+
+		sender := p.ExecCfg().ClosedTimestampSender
+		if sender == nil {
+			// Secondary tenants don't hold leases.
+			return nil
+		}
+		nodeID, _ := p.execCfg.NodeInfo.NodeID.OptionalNodeID() // zero if not available
+		now := timeutil.Now()
+		for _, r := range sender.BlockedRanges() {
+			blockedSince, err := tree.MakeDTimestampTZ(r.BlockedSince, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(nodeID)),
+				tree.NewDInt(tree.DInt(r.StoreID)),
+				tree.NewDInt(tree.DInt(r.RangeID)),
+				tree.NewDString(r.Reason.String()),
+				blockedSince,
+				tree.NewDInterval(
+					duration.MakeDuration(now.Sub(r.BlockedSince).Nanoseconds(), 0, 0),
+					types.DefaultIntervalTypeMetadata,
+				),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalNodeTrippedCircuitBreakersTable exposes the replicas on the current
+// node whose circuit breaker is tripped. Requests to these replicas fail fast
+// until the breaker's probe finds that the range is available again.
+var crdbInternalNodeTrippedCircuitBreakersTable = virtualSchemaTable{
+	comment: `tripped per-replica circuit breakers (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_tripped_circuit_breakers (
+  node_id  INT NOT NULL,
+  store_id INT NOT NULL,
+  range_id INT NOT NULL,
+  error    STRING NOT NULL
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.node_tripped_circuit_breakers"); err != nil {
+			return err
+		}
+
+		nodeID, ok := p.execCfg.NodeInfo.NodeID.OptionalNodeID()
+		if !ok {
+			// Secondary tenants don't have replicas.
+			return nil
+		}
+		return p.ExecCfg().KVStoresIterator.ForEachStore(func(store kvserverbase.Store) error {
+			storeID := tree.NewDInt(tree.DInt(store.StoreID()))
+			return store.VisitTrippedCircuitBreakers(func(rangeID roachpb.RangeID, err error) error {
+				return addRow(
+					tree.NewDInt(tree.DInt(nodeID)),
+					storeID,
+					tree.NewDInt(tree.DInt(rangeID)),
+					tree.NewDString(err.Error()),
+				)
+			})
+		})
+	},
+}
+
+// crdbInternalHotKeysTable exposes the keys with the most bytes written and
+// read in the hottest ranges of each store in the cluster, so that the keys
+// responsible for a hot range can be identified.
+var crdbInternalHotKeysTable = virtualSchemaTable{
+	comment: `keys with the most bytes written and read in the hottest ranges (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.hot_keys (
+  node_id                INT NOT NULL,
+  store_id               INT NOT NULL,
+  range_id               INT NOT NULL,
+  key                    BYTES NOT NULL,
+  pretty_key             STRING NOT NULL,
+  write_bytes_per_second FLOAT NOT NULL,
+  read_bytes_per_second  FLOAT NOT NULL
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.hot_keys"); err != nil {
+			return err
+		}
+		ss, err := p.extendedEvalCtx.NodesStatusServer.OptionalNodesStatusServer(
+			errorutil.FeatureNotAvailableToNonSystemTenantsIssue)
+		if err != nil {
+			return err
+		}
+		response, err := ss.HotKeys(ctx, &serverpb.HotKeysRequest{})
+		if err != nil {
+			return err
+		}
+		for nodeID, msg := range response.ErrorsByNodeID {
+			log.Warningf(ctx, "unable to retrieve hot keys from n%d: %s", nodeID, msg)
+		}
+
+		for _, k := range response.Keys {
+			if err := addRow(
+				tree.NewDInt(tree.DInt(k.NodeID)),
+				tree.NewDInt(tree.DInt(k.StoreID)),
+				tree.NewDInt(tree.DInt(k.RangeID)),
+				tree.NewDBytes(tree.DBytes(k.Key)),
+				tree.NewDString(keys.PrettyPrint(nil /* valDirs */, k.Key)),
+				tree.NewDFloat(tree.DFloat(k.WriteBytesPerSecond)),
+				tree.NewDFloat(tree.DFloat(k.ReadBytesPerSecond)),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalRangeDetailsTable exposes the replication and closed timestamp
+// details of every replica in the cluster. It backs SHOW RANGES WITH DETAILS.
+var crdbInternalRangeDetailsTable = virtualSchemaTable{
+	comment: `replication and closed timestamp details of all replicas (cluster RPC; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.range_details (
+  range_id           INT NOT NULL,
+  node_id            INT NOT NULL,
+  store_id           INT NOT NULL,
+  replica_id         INT NOT NULL,
+  raft_state         STRING NOT NULL,
+  raft_applied_index INT NOT NULL,
+  raft_commit_index  INT NOT NULL,
+  pending_proposals  INT NOT NULL,
+  is_leaseholder     BOOL NOT NULL,
+  lease_valid        BOOL NOT NULL,
+  lease_epoch        INT,
+  closed_timestamp   DECIMAL,
+  non_live_replicas  INT[] NOT NULL,
+  INDEX(range_id)
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return populateRangeDetails(ctx, p, nil /* rangeIDs */, addRow)
+	},
+	indexes: []virtualIndex{
+		{
+			populate: func(
+				ctx context.Context,
+				unwrappedConstraint tree.Datum,
+				p *planner,
+				_ catalog.DatabaseDescriptor,
+				addRow func(...tree.Datum) error,
+			) (matched bool, err error) {
+				rangeID := roachpb.RangeID(tree.MustBeDInt(unwrappedConstraint))
+				if err := populateRangeDetails(ctx, p, []roachpb.RangeID{rangeID}, addRow); err != nil {
+					return false, err
+				}
+				return true, nil
+			},
+		},
+	},
+}
+
+// populateRangeDetails adds a row to crdb_internal.range_details for every
+// replica of the given ranges, or of all ranges if rangeIDs is empty.
+func populateRangeDetails(
+	ctx context.Context, p *planner, rangeIDs []roachpb.RangeID, addRow func(...tree.Datum) error,
+) error {
+	// The details are available to the same users as crdb_internal.ranges, so
+	// that SHOW RANGES WITH DETAILS doesn't require more than SHOW RANGES.
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		all, err := p.Descriptors().GetAllDescriptors(ctx, p.txn)
+		if err != nil {
+			return err
+		}
+		hasPermission := false
+		for _, desc := range all.OrderedDescriptors() {
+			if p.CheckPrivilege(ctx, desc, privilege.ZONECONFIG) == nil {
+				hasPermission = true
+				break
+			}
+		}
+		if !hasPermission {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"only users with the ZONECONFIG privilege or the admin role can read crdb_internal.range_details")
+		}
+	}
+	ss, err := p.extendedEvalCtx.NodesStatusServer.OptionalNodesStatusServer(
+		errorutil.FeatureNotAvailableToNonSystemTenantsIssue)
+	if err != nil {
+		return err
+	}
+	response, err := ss.RangeDetails(ctx, &serverpb.RangeDetailsRequest{RangeIDs: rangeIDs})
+	if err != nil {
+		return err
+	}
+	for nodeID, msg := range response.ErrorsByNodeID {
+		log.Warningf(ctx, "unable to retrieve range details from n%d: %s", nodeID, msg)
+	}
+
+	for _, r := range response.Replicas {
+		leaseEpoch := tree.DNull
+		if r.LeaseEpoch != 0 {
+			leaseEpoch = tree.NewDInt(tree.DInt(r.LeaseEpoch))
+		}
+		closedTimestamp := tree.DNull
+		if !r.ClosedTimestamp.IsEmpty() {
+			closedTimestamp = eval.TimestampToDecimalDatum(r.ClosedTimestamp)
+		}
+		nonLive := tree.NewDArray(types.Int)
+		for _, nodeID := range r.NonLiveNodeIDs {
+			if err := nonLive.Append(tree.NewDInt(tree.DInt(nodeID))); err != nil {
+				return err
+			}
+		}
+		if err := addRow(
+			tree.NewDInt(tree.DInt(r.RangeID)),
+			tree.NewDInt(tree.DInt(r.NodeID)),
+			tree.NewDInt(tree.DInt(r.StoreID)),
+			tree.NewDInt(tree.DInt(r.ReplicaID)),
+			tree.NewDString(r.RaftState),
+			tree.NewDInt(tree.DInt(r.RaftAppliedIndex)),
+			tree.NewDInt(tree.DInt(r.RaftCommitIndex)),
+			tree.NewDInt(tree.DInt(r.PendingProposals)),
+			tree.MakeDBool(tree.DBool(r.IsLeaseholder)),
+			tree.MakeDBool(tree.DBool(r.LeaseValid)),
+			leaseEpoch,
+			closedTimestamp,
+			nonLive,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crdbInternalNodePlanPinsTable exposes the plan pins known to the current node.
+var crdbInternalNodePlanPinsTable = virtualSchemaTable{
+	comment: `plan hints pinned to statement fingerprints (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_plan_pins (
+  fingerprint STRING NOT NULL,
+  hints       STRING NOT NULL,
+  created     TIMESTAMP NOT NULL
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if p.ExecCfg().PlanPins == nil {
+			return nil
+		}
+		for _, pin := range p.ExecCfg().PlanPins.Pins() {
+			created, err := tree.MakeDTimestamp(pin.Created, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.NewDString(pin.Fingerprint),
+				tree.NewDString(pin.Hints),
+				created,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// crdbInternalNodeAdmissionDatabaseUsageTable exposes the usage of the SQL
+// response admission queues of the current node by each database, when the
+// queues order work by database.
+var crdbInternalNodeAdmissionDatabaseUsageTable = virtualSchemaTable{
+	comment: `usage of the SQL response admission queues by database (RAM; local node only)`,
+	schema: `
+CREATE TABLE crdb_internal.node_admission_database_usage (
+  node_id       INT NOT NULL,
+  work_kind     STRING NOT NULL,
+  database_id   INT NOT NULL,
+  database_name STRING,
+  weight        INT NOT NULL,
+  used          INT NOT NULL,
+  waiting       INT NOT NULL
+);`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if err := p.RequireAdminRole(ctx, "read crdb_internal.node_admission_database_usage"); err != nil {
+			return err
+		}
+
+		type workQueue struct {
+			workKind string
+			q        *admission.WorkQueue
+		}
+		queues := []workQueue{{workKind: "sql-kv-response", q: p.ExecCfg().DB.SQLKVResponseAdmissionQ}}
+		if distSQLSrv := p.ExecCfg().DistSQLSrv; distSQLSrv != nil {
+			queues = append(queues, workQueue{
+				workKind: "sql-sql-response", q: distSQLSrv.SQLSQLResponseAdmissionQ,
+			})
+		}
+		dbNames := make(map[uint32]string)
+		if err := forEachDatabaseDesc(ctx, p, nil /* all databases */, false, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				dbNames[uint32(db.GetID())] = db.GetName()
+				return nil
+			}); err != nil {
+			return err
+		}
+		nodeID, _ := p.execCfg.NodeInfo.NodeID.OptionalNodeID() // zero if not available
+		for _, queue := range queues {
+			if queue.q == nil {
+				continue
+			}
+			for _, u := range queue.q.TenantUsages() {
+				if u.DatabaseID == 0 {
+					continue
+				}
+				dbName := tree.DNull
+				if name, ok := dbNames[u.DatabaseID]; ok {
+					dbName = tree.NewDString(name)
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(nodeID)),
+					tree.NewDString(queue.workKind),
+					tree.NewDInt(tree.DInt(u.DatabaseID)),
+					dbName,
+					tree.NewDInt(tree.DInt(u.Weight)),
+					tree.NewDInt(tree.DInt(u.Used)),
+					tree.NewDInt(tree.DInt(u.Waiting)),
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
+
 // crdb_internal.cluster_transaction_statistics contains cluster-wide transaction statistics
 // that have not yet been flushed to disk.
 var crdbInternalClusterTxnStatsTable = virtualSchemaTable{
@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -69,7 +70,9 @@ const nonIndexColHistogramBuckets = 2
 func StubTableStats(
 	desc catalog.TableDescriptor, name string, multiColEnabled bool,
 ) ([]*stats.TableStatisticProto, error) {
-	colStats, err := createStatsDefaultColumns(desc, multiColEnabled)
+	colStats, err := createStatsDefaultColumns(
+		desc, multiColEnabled, false /* multiColHistogramsEnabled */, false, /* virtColEnabled */
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -263,7 +266,12 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 	var deleteOtherStats bool
 	if len(n.ColumnNames) == 0 {
 		multiColEnabled := stats.MultiColumnStatisticsClusterMode.Get(&n.p.ExecCfg().Settings.SV)
-		if colStats, err = createStatsDefaultColumns(tableDesc, multiColEnabled); err != nil {
+		multiColHistogramsEnabled := stats.MultiColumnHistogramsClusterMode.Get(&n.p.ExecCfg().Settings.SV) &&
+			n.p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.MultiColumnHistograms)
+		virtColEnabled := stats.VirtualComputedColumnStatisticsClusterMode.Get(&n.p.ExecCfg().Settings.SV)
+		if colStats, err = createStatsDefaultColumns(
+			tableDesc, multiColEnabled, multiColHistogramsEnabled, virtColEnabled,
+		); err != nil {
 			return nil, err
 		}
 		deleteOtherStats = true
@@ -273,9 +281,10 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 			return nil, err
 		}
 
+		virtColEnabled := stats.VirtualComputedColumnStatisticsClusterMode.Get(&n.p.ExecCfg().Settings.SV)
 		columnIDs := make([]descpb.ColumnID, len(columns))
 		for i := range columns {
-			if columns[i].IsVirtual() {
+			if columns[i].IsVirtual() && !virtColEnabled {
 				return nil, pgerror.Newf(
 					pgcode.InvalidColumnReference,
 					"cannot create statistics on virtual column %q",
@@ -369,11 +378,19 @@ const maxNonIndexCols = 100
 // predicate expressions are also likely to appear in query filters, so stats
 // are collected for those columns as well.
 //
+// If multiColHistogramsEnabled is true, histograms are also collected for the
+// multi-column statistics on index key prefixes. The columns of these
+// statistics are kept in index order, since the histograms are built over the
+// key encodings of the columns.
+//
+// Statistics on virtual computed columns, including the inaccessible columns
+// backing expression indexes, are only collected if virtColEnabled is true.
+//
 // In addition to the index columns, we collect stats on up to maxNonIndexCols
 // other columns from the table. We only collect histograms for index columns,
 // plus any other boolean or enum columns (where the "histogram" is tiny).
 func createStatsDefaultColumns(
-	desc catalog.TableDescriptor, multiColEnabled bool,
+	desc catalog.TableDescriptor, multiColEnabled, multiColHistogramsEnabled, virtColEnabled bool,
 ) ([]jobspb.CreateStatsDetails_ColStat, error) {
 	colStats := make([]jobspb.CreateStatsDetails_ColStat, 0, len(desc.ActiveIndexes()))
 
@@ -392,6 +409,34 @@ func createStatsDefaultColumns(
 		return false
 	}
 
+	// trackMultiColStatsExists is like sortAndTrackStatsExists, but for the
+	// column IDs of a prefix of index key columns. If multi-column histograms
+	// are enabled, the column IDs are not sorted, since the histograms are
+	// built over the key encodings of the columns in index order.
+	trackMultiColStatsExists := func(colIDs []descpb.ColumnID) bool {
+		if multiColHistogramsEnabled {
+			colIDs = append([]descpb.ColumnID(nil), colIDs...)
+		}
+		return sortAndTrackStatsExists(colIDs)
+	}
+
+	// makeMultiColStat returns the column statistic for the given prefix of
+	// index key columns.
+	makeMultiColStat := func(colIDs []descpb.ColumnID) jobspb.CreateStatsDetails_ColStat {
+		if !multiColHistogramsEnabled {
+			// Only generate non-histogram multi-column stats.
+			return jobspb.CreateStatsDetails_ColStat{
+				ColumnIDs:    colIDs,
+				HasHistogram: false,
+			}
+		}
+		return jobspb.CreateStatsDetails_ColStat{
+			ColumnIDs:           colIDs,
+			HasHistogram:        true,
+			HistogramMaxBuckets: stats.DefaultHistogramBuckets,
+		}
+	}
+
 	// addIndexColumnStatsIfNotExists appends column stats for the given column
 	// ID if they have not already been added. Histogram stats are collected for
 	// every indexed column.
@@ -401,11 +446,8 @@ func createStatsDefaultColumns(
 			return err
 		}
 
-		// Do not collect stats for virtual computed columns. DistSQLPlanner
-		// cannot currently collect stats for these columns because it plans
-		// table readers on the table's primary index which does not include
-		// virtual computed columns.
-		if col.IsVirtual() {
+		// Do not collect stats for virtual computed columns unless enabled.
+		if col.IsVirtual() && !virtColEnabled {
 			return nil
 		}
 
@@ -456,13 +498,9 @@ func createStatsDefaultColumns(
 		}
 
 		// Remember the requested stats so we don't request duplicates.
-		_ = sortAndTrackStatsExists(colIDs)
+		_ = trackMultiColStatsExists(colIDs)
 
-		// Only generate non-histogram multi-column stats.
-		colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-			ColumnIDs:    colIDs,
-			HasHistogram: false,
-		})
+		colStats = append(colStats, makeMultiColStat(colIDs))
 	}
 
 	// Add column stats for each secondary index.
@@ -487,7 +525,7 @@ func createStatsDefaultColumns(
 				if err != nil {
 					return nil, err
 				}
-				if col.IsVirtual() {
+				if col.IsVirtual() && !virtColEnabled {
 					continue
 				}
 				colIDs = append(colIDs, col.GetID())
@@ -500,15 +538,11 @@ func createStatsDefaultColumns(
 			}
 
 			// Check for existing stats and remember the requested stats.
-			if ok := sortAndTrackStatsExists(colIDs); ok {
+			if ok := trackMultiColStatsExists(colIDs); ok {
 				continue
 			}
 
-			// Only generate non-histogram multi-column stats.
-			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-				ColumnIDs:    colIDs,
-				HasHistogram: false,
-			})
+			colStats = append(colStats, makeMultiColStat(colIDs))
 		}
 
 		// Add columns referenced in partial index predicate expressions.
@@ -543,8 +577,8 @@ func createStatsDefaultColumns(
 	for i := 0; i < len(desc.PublicColumns()) && nonIdxCols < maxNonIndexCols; i++ {
 		col := desc.PublicColumns()[i]
 
-		// Do not collect stats for virtual computed columns.
-		if col.IsVirtual() {
+		// Do not collect stats for virtual computed columns unless enabled.
+		if col.IsVirtual() && !virtColEnabled {
 			continue
 		}
 
@@ -618,7 +652,7 @@ func (r *createStatsResumer) Resume(ctx context.Context, execCtx interface{}) er
 		// metadata, so we can use a nil rowContainerHelper.
 		resultWriter := NewRowResultWriter(nil /* rowContainer */)
 		if err := dsp.planAndRunCreateStats(
-			ctx, evalCtx, planCtx, txn, r.job, resultWriter,
+			ctx, evalCtx, p.SemaCtx(), planCtx, txn, r.job, resultWriter,
 		); err != nil {
 			// Check if this was a context canceled error and restart if it was.
 			if grpcutil.IsContextCanceled(err) {
@@ -43,16 +43,42 @@ func checkPrivilegesForShowRanges(d *delegator, table cat.Table) error {
 	return nil
 }
 
+// showRangesDetailsCols and showRangesDetailsJoin are spliced into the SHOW
+// RANGES queries when WITH DETAILS is specified. Only the view of the replica on
+// the lease holder store of each range is shown, even if other replicas still
+// believe they hold the lease; the columns are NULL if the lease holder could
+// not be reached. The join looks up the details of each range by its ID,
+// rather than requesting the details of every replica in the cluster.
+const showRangesDetailsCols = `,
+  d.lease_epoch,
+  d.closed_timestamp,
+  d.raft_applied_index,
+  d.raft_commit_index,
+  d.pending_proposals,
+  d.non_live_replicas`
+
+const showRangesDetailsJoin = `
+  LEFT JOIN %s.crdb_internal.range_details AS d
+    ON d.range_id = r.range_id AND d.store_id = r.lease_holder`
+
 // delegateShowRanges implements the SHOW RANGES statement:
 //   SHOW RANGES FROM TABLE t
 //   SHOW RANGES FROM INDEX t@idx
 //   SHOW RANGES FROM DATABASE db
 //
 // These statements show the ranges corresponding to the given table or index,
-// along with the list of replicas and the lease holder.
+// along with the list of replicas and the lease holder. With WITH DETAILS, the
+// lease epoch, closed timestamp, Raft indexes and pending proposals reported by
+// the lease holder are shown as well, along with the replicas that the lease
+// holder considers to be on non-live nodes.
 func (d *delegator) delegateShowRanges(n *tree.ShowRanges) (tree.Statement, error) {
 	sqltelemetry.IncrementShowCounter(sqltelemetry.Ranges)
 	if n.DatabaseName != "" {
+		var detailsCols, detailsJoin string
+		if n.Details {
+			detailsCols = showRangesDetailsCols
+			detailsJoin = fmt.Sprintf(showRangesDetailsJoin, n.DatabaseName.String())
+		}
 		const dbQuery = `
 		SELECT
 			table_name,
@@ -64,18 +90,21 @@ func (d *delegator) delegateShowRanges(n *tree.ShowRanges) (tree.Statement, erro
 				WHEN crdb_internal.pretty_key(r.end_key, 2) = '' THEN NULL
 				ELSE crdb_internal.pretty_key(r.end_key, 2)
 			END AS end_key,
-			range_id,
+			r.range_id,
 			range_size / 1000000 as range_size_mb,
 			lease_holder,
 			replica_localities[array_position(replicas, lease_holder)] as lease_holder_locality,
 			replicas,
-			replica_localities
-		FROM %[1]s.crdb_internal.ranges AS r
+			replica_localities%[3]s
+		FROM %[1]s.crdb_internal.ranges AS r%[4]s
 		WHERE database_name=%[2]s
 		ORDER BY table_name, r.start_key
 		`
 		// Note: n.DatabaseName.String() != string(n.DatabaseName)
-		return parse(fmt.Sprintf(dbQuery, n.DatabaseName.String(), lexbase.EscapeSQLString(string(n.DatabaseName))))
+		return parse(fmt.Sprintf(
+			dbQuery, n.DatabaseName.String(), lexbase.EscapeSQLString(string(n.DatabaseName)),
+			detailsCols, detailsJoin,
+		))
 	}
 
 	idx, resName, err := cat.ResolveTableIndex(
@@ -93,6 +122,12 @@ func (d *delegator) delegateShowRanges(n *tree.ShowRanges) (tree.Statement, erro
 		return nil, errors.New("SHOW RANGES may not be called on a virtual table")
 	}
 
+	var detailsCols, detailsJoin string
+	if n.Details {
+		detailsCols = showRangesDetailsCols
+		detailsJoin = fmt.Sprintf(showRangesDetailsJoin, resName.CatalogName.String())
+	}
+
 	span := idx.Span()
 	startKey := hex.EncodeToString(span.Key)
 	endKey := hex.EncodeToString(span.EndKey)
@@ -100,16 +135,17 @@ func (d *delegator) delegateShowRanges(n *tree.ShowRanges) (tree.Statement, erro
 SELECT 
   CASE WHEN r.start_key <= x'%[1]s' THEN NULL ELSE crdb_internal.pretty_key(r.start_key, 2) END AS start_key,
   CASE WHEN r.end_key >= x'%[2]s' THEN NULL ELSE crdb_internal.pretty_key(r.end_key, 2) END AS end_key,
-  range_id,
+  r.range_id,
   range_size / 1000000 as range_size_mb,
   lease_holder,
   replica_localities[array_position(replicas, lease_holder)] as lease_holder_locality,
   replicas,
-  replica_localities
-FROM %[3]s.crdb_internal.ranges AS r
+  replica_localities%[4]s
+FROM %[3]s.crdb_internal.ranges AS r%[5]s
 WHERE (r.start_key < x'%[2]s')
   AND (r.end_key   > x'%[1]s') ORDER BY r.start_key
 `,
 		startKey, endKey, resName.CatalogName.String(), // note: CatalogName.String() != Catalog()
+		detailsCols, detailsJoin,
 	))
 }
@@ -1546,6 +1546,10 @@ func (dsp *DistSQLPlanner) createTableReaders(
 	if err != nil {
 		return nil, err
 	}
+	spans, err := pruneEmptyPartitionSpans(ctx, planCtx.ExtendedEvalCtx.Txn, n)
+	if err != nil {
+		return nil, err
+	}
 
 	p := planCtx.NewPhysicalPlan()
 	err = dsp.planTableReaders(
@@ -1556,7 +1560,7 @@ func (dsp *DistSQLPlanner) createTableReaders(
 			spec:              spec,
 			post:              post,
 			desc:              n.desc,
-			spans:             n.spans,
+			spans:             spans,
 			reverse:           n.reverse,
 			parallelize:       n.parallelize,
 			estimatedRowCount: n.estimatedRowCount,
@@ -3128,8 +3132,8 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 			if err != nil {
 				return nil, err
 			}
-			plan, err = dsp.createPlanForCreateStats(ctx, planCtx, 0, /* jobID */
-				record.Details.(jobspb.CreateStatsDetails))
+			plan, err = dsp.createPlanForCreateStats(ctx, planCtx, planCtx.planner.SemaCtx(),
+				0 /* jobID */, record.Details.(jobspb.CreateStatsDetails))
 		}
 
 	case *distinctNode:
@@ -19,9 +19,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/span"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
@@ -56,6 +60,7 @@ var maxTimestampAge = settings.RegisterDurationSetting(
 func (dsp *DistSQLPlanner) createStatsPlan(
 	ctx context.Context,
 	planCtx *PlanningCtx,
+	semaCtx *tree.SemaContext,
 	desc catalog.TableDescriptor,
 	reqStats []requestedStat,
 	jobID jobspb.JobID,
@@ -65,15 +70,51 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 		return nil, errors.New("no stats requested")
 	}
 
-	// Calculate the set of columns we need to scan.
-	var colCfg scanColumnsConfig
+	// Calculate the set of columns we need to scan, and the set of virtual
+	// computed columns that need to be rendered on top of the scan.
+	colCfg := scanColumnsConfig{wantedColumns: []tree.ColumnID{}}
 	var tableColSet catalog.TableColSet
+	var requestedCols, virtComputedCols []catalog.Column
 	for _, s := range reqStats {
 		for _, c := range s.columns {
 			if !tableColSet.Contains(c) {
 				tableColSet.Add(c)
-				colCfg.wantedColumns = append(colCfg.wantedColumns, c)
+				col, err := desc.FindColumnWithID(c)
+				if err != nil {
+					return nil, err
+				}
+				requestedCols = append(requestedCols, col)
+				if col.IsVirtual() {
+					virtComputedCols = append(virtComputedCols, col)
+				} else {
+					colCfg.wantedColumns = append(colCfg.wantedColumns, c)
+				}
+			}
+		}
+	}
+
+	// Virtual computed columns are not stored in the primary index, so scan the
+	// columns referenced by their expressions instead.
+	if len(virtComputedCols) > 0 {
+		exprStrings := make([]string, len(virtComputedCols))
+		for i, col := range virtComputedCols {
+			exprStrings[i] = col.GetComputeExpr()
+		}
+		exprs, err := parser.ParseExprs(exprStrings)
+		if err != nil {
+			return nil, err
+		}
+		for _, expr := range exprs {
+			refColIDs, err := schemaexpr.ExtractColumnIDs(desc, expr)
+			if err != nil {
+				return nil, err
 			}
+			refColIDs.ForEach(func(c descpb.ColumnID) {
+				if !tableColSet.Contains(c) {
+					tableColSet.Add(c)
+					colCfg.wantedColumns = append(colCfg.wantedColumns, c)
+				}
+			})
 		}
 	}
 
@@ -83,10 +124,6 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	if err != nil {
 		return nil, err
 	}
-	var colIdxMap catalog.TableColMap
-	for i, c := range scan.cols {
-		colIdxMap.Set(c.GetID(), i)
-	}
 	var sb span.Builder
 	sb.Init(planCtx.EvalContext(), planCtx.ExtendedEvalCtx.Codec, desc, scan.index)
 	scan.spans, err = sb.UnconstrainedSpans()
@@ -109,8 +146,24 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 		}
 	}
 
+	// Determine the columns that are input to the samplers. Unless virtual
+	// computed columns are requested, these are the scanned columns.
+	sampledCols := scan.cols
+	if len(virtComputedCols) > 0 {
+		if err := dsp.addVirtualComputedColumnRenders(
+			ctx, planCtx, semaCtx, p, &scan, requestedCols, virtComputedCols,
+		); err != nil {
+			return nil, err
+		}
+		sampledCols = requestedCols
+	}
+	var colIdxMap catalog.TableColMap
+	for i, c := range sampledCols {
+		colIdxMap.Set(c.GetID(), i)
+	}
+
 	var sketchSpecs, invSketchSpecs []execinfrapb.SketchSpec
-	sampledColumnIDs := make([]descpb.ColumnID, len(scan.cols))
+	sampledColumnIDs := make([]descpb.ColumnID, len(sampledCols))
 	for _, s := range reqStats {
 		spec := execinfrapb.SketchSpec{
 			SketchType:          execinfrapb.SketchType_HLL_PLUS_PLUS_V1,
@@ -252,8 +305,59 @@ func (dsp *DistSQLPlanner) createStatsPlan(
 	return p, nil
 }
 
+// addVirtualComputedColumnRenders adds a rendering stage on top of the given
+// plan of table readers which outputs the requested columns in order. Columns
+// that are not virtual are passed through from the scan, and virtual computed
+// columns are computed from the scanned columns.
+func (dsp *DistSQLPlanner) addVirtualComputedColumnRenders(
+	ctx context.Context,
+	planCtx *PlanningCtx,
+	semaCtx *tree.SemaContext,
+	p *PhysicalPlan,
+	scan *scanNode,
+	requestedCols, virtComputedCols []catalog.Column,
+) error {
+	// Resolve names and types of the virtual computed column expressions in
+	// terms of the scanned columns.
+	tn := tree.NewUnqualifiedTableName(tree.Name(scan.desc.GetName()))
+	virtComputedExprs, _, err := schemaexpr.MakeComputedExprs(
+		ctx, virtComputedCols, scan.cols, scan.desc, tn, planCtx.EvalContext(), semaCtx,
+	)
+	if err != nil {
+		return err
+	}
+
+	var scanColIdxMap catalog.TableColMap
+	for i, c := range scan.cols {
+		scanColIdxMap.Set(c.GetID(), i)
+	}
+
+	var rb renderBuilder
+	rb.init(exec.Node(planNode(scan)), exec.OutputOrdering{})
+	exprs := make(tree.TypedExprs, len(requestedCols))
+	var virtIdx int
+	for i, col := range requestedCols {
+		if col.IsVirtual() {
+			exprs[i] = rb.r.ivarHelper.Rebind(virtComputedExprs[virtIdx])
+			virtIdx++
+			continue
+		}
+		scanIdx, ok := scanColIdxMap.Get(col.GetID())
+		if !ok {
+			return errors.AssertionFailedf("column %d not scanned", col.GetID())
+		}
+		exprs[i] = rb.r.ivarHelper.IndexedVarWithType(scanIdx, col.GetType())
+	}
+	rb.setOutput(exprs, colinfo.ResultColumnsFromColumns(scan.desc.GetID(), requestedCols))
+	return dsp.createPlanForRender(p, rb.r, planCtx)
+}
+
 func (dsp *DistSQLPlanner) createPlanForCreateStats(
-	ctx context.Context, planCtx *PlanningCtx, jobID jobspb.JobID, details jobspb.CreateStatsDetails,
+	ctx context.Context,
+	planCtx *PlanningCtx,
+	semaCtx *tree.SemaContext,
+	jobID jobspb.JobID,
+	details jobspb.CreateStatsDetails,
 ) (*PhysicalPlan, error) {
 	reqStats := make([]requestedStat, len(details.ColumnStats))
 	histogramCollectionEnabled := stats.HistogramClusterMode.Get(&dsp.st.SV)
@@ -273,12 +377,13 @@ func (dsp *DistSQLPlanner) createPlanForCreateStats(
 	}
 
 	tableDesc := tabledesc.NewBuilder(&details.Table).BuildImmutableTable()
-	return dsp.createStatsPlan(ctx, planCtx, tableDesc, reqStats, jobID, details)
+	return dsp.createStatsPlan(ctx, planCtx, semaCtx, tableDesc, reqStats, jobID, details)
 }
 
 func (dsp *DistSQLPlanner) planAndRunCreateStats(
 	ctx context.Context,
 	evalCtx *extendedEvalContext,
+	semaCtx *tree.SemaContext,
 	planCtx *PlanningCtx,
 	txn *kv.Txn,
 	job *jobs.Job,
@@ -287,7 +392,7 @@ func (dsp *DistSQLPlanner) planAndRunCreateStats(
 	ctx = logtags.AddTag(ctx, "create-stats-distsql", nil)
 
 	details := job.Details().(jobspb.CreateStatsDetails)
-	physPlan, err := dsp.createPlanForCreateStats(ctx, planCtx, job.ID(), details)
+	physPlan, err := dsp.createPlanForCreateStats(ctx, planCtx, semaCtx, job.ID(), details)
 	if err != nil {
 		return err
 	}
@@ -225,11 +225,15 @@ func (e *distSQLSpecExecFactory) ConstructScan(
 		spans, err = sb.SpansFromInvertedSpans(params.InvertedConstraint, params.IndexConstraint, nil /* scratch */)
 	} else {
 		splitter := span.MakeSplitter(tabDesc, idx, params.NeededCols)
+		sb.SetMergeAdjacentSpans(mergeAdjacentScanSpans.Get(&e.planner.ExecCfg().Settings.SV))
 		spans, err = sb.SpansFromConstraint(params.IndexConstraint, splitter)
 	}
 	if err != nil {
 		return nil, err
 	}
+	if numMerged := sb.NumMergedSpans(); numMerged > 0 {
+		e.planner.ExecCfg().GetRowMetrics(e.planner.SessionData().Internal).MergedSpansCount.Inc(int64(numMerged))
+	}
 
 	isFullTableOrIndexScan := len(spans) == 1 && spans[0].EqualValue(
 		tabDesc.IndexSpan(e.planner.ExecCfg().Codec, idx.GetID()),
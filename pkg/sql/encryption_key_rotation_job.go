@@ -0,0 +1,184 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// encryptionKeyRotationBatchSize is the maximum number of sstables rewritten
+// on a store by each request of an encryption key rotation job. The progress
+// of the job is recorded after each batch.
+var encryptionKeyRotationBatchSize = 16
+
+// encryptionKeyRotationResumer implements the jobs.Resumer interface for the
+// jobs created by ROTATE ENCRYPTION KEYS. The job first rotates the
+// encryption-at-rest keys of the encrypted stores of every node, and then
+// compacts, in batches, the sstables of each store which are still encrypted
+// with previous data keys. The nodes whose keys were rotated and the number of
+// sstables rewritten on each store are recorded in the job progress, so that a
+// resumed job picks up where it left off.
+//
+// The sstables that compactions leave as is, such as those of the bottommost
+// level without overlapping data above them, remain encrypted with previous
+// data keys, which stay available, until a later compaction rewrites them.
+type encryptionKeyRotationResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*encryptionKeyRotationResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r *encryptionKeyRotationResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	ss, err := p.ExecCfg().NodesStatusServer.OptionalNodesStatusServer(
+		errorutil.FeatureNotAvailableToNonSystemTenantsIssue)
+	if err != nil {
+		return err
+	}
+	progress := r.job.Progress().GetEncryptionKeyRotation()
+	if progress == nil {
+		return errors.AssertionFailedf("invalid progress for encryption key rotation job %d", r.job.ID())
+	}
+
+	// Rotate the keys of the nodes which haven't been rotated yet.
+	nodes, err := ss.ListNodesInternal(ctx, &serverpb.NodesRequest{})
+	if err != nil {
+		return err
+	}
+	rotated := make(map[roachpb.NodeID]struct{}, len(progress.RotatedNodeIDs))
+	for _, nodeID := range progress.RotatedNodeIDs {
+		rotated[nodeID] = struct{}{}
+	}
+	for _, n := range nodes.Nodes {
+		nodeID := n.Desc.NodeID
+		if _, ok := rotated[nodeID]; ok {
+			continue
+		}
+		if nodes.LivenessByNodeID[nodeID] == livenesspb.NodeLivenessStatus_DECOMMISSIONED {
+			continue
+		}
+		resp, err := ss.RotateEncryptionKeys(ctx, &serverpb.RotateEncryptionKeysRequest{
+			NodeID:     strconv.Itoa(int(nodeID)),
+			RotateKeys: true,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "rotating encryption keys of n%d", nodeID)
+		}
+		log.Infof(ctx, "rotated encryption keys of the %d store(s) of n%d", len(resp.Stores), nodeID)
+		progress.RotatedNodeIDs = append(progress.RotatedNodeIDs, nodeID)
+		for _, s := range resp.Stores {
+			progress.Stores = append(progress.Stores, jobspb.EncryptionKeyRotationProgress_Store{
+				NodeID:         nodeID,
+				StoreID:        s.StoreID,
+				FilesRemaining: s.FilesRemaining,
+			})
+		}
+		if err := r.updateProgress(ctx, progress); err != nil {
+			return err
+		}
+	}
+	sort.Slice(progress.Stores, func(i, j int) bool {
+		return progress.Stores[i].StoreID < progress.Stores[j].StoreID
+	})
+
+	// Rewrite the sstables encrypted with previous keys, one batch at a time.
+	for i := range progress.Stores {
+		s := &progress.Stores[i]
+		for s.FilesRemaining > 0 {
+			resp, err := ss.RotateEncryptionKeys(ctx, &serverpb.RotateEncryptionKeysRequest{
+				NodeID:            strconv.Itoa(int(s.NodeID)),
+				StoreID:           s.StoreID,
+				MaxFilesToRewrite: int32(encryptionKeyRotationBatchSize),
+			})
+			if err != nil {
+				return errors.Wrapf(err, "rewriting encrypted files of s%d", s.StoreID)
+			}
+			if len(resp.Stores) != 1 {
+				return errors.AssertionFailedf("expected a single store in response, got %d", len(resp.Stores))
+			}
+			rewritten := resp.Stores[0].FilesRewritten
+			s.FilesRewritten += rewritten
+			s.FilesRemaining = resp.Stores[0].FilesRemaining
+			if err := r.updateProgress(ctx, progress); err != nil {
+				return err
+			}
+			if rewritten == 0 && s.FilesRemaining > 0 {
+				// None of the sstables of the batch were rewritten by the compactions.
+				// Leave the remaining ones to later compactions rather than retry
+				// forever.
+				log.Infof(ctx, "%d sstables of s%d remain encrypted with previous data keys, "+
+					"they will be re-encrypted when compacted", s.FilesRemaining, s.StoreID)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// updateProgress records the given progress in the job.
+func (r *encryptionKeyRotationResumer) updateProgress(
+	ctx context.Context, progress *jobspb.EncryptionKeyRotationProgress,
+) error {
+	return r.job.FractionProgressed(
+		ctx, nil /* txn */, func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+			prog := details.(*jobspb.Progress_EncryptionKeyRotation).EncryptionKeyRotation
+			*prog = *progress
+			return encryptionKeyRotationFractionCompleted(progress)
+		},
+	)
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The keys that were
+// rotated and the files that were rewritten stay so, as the previous keys
+// remain usable.
+func (r *encryptionKeyRotationResumer) OnFailOrCancel(context.Context, interface{}, error) error {
+	return nil
+}
+
+// encryptionKeyRotationFractionCompleted returns the fraction of the sstables
+// encrypted with previous keys which were rewritten, over all the stores whose
+// keys were rotated.
+func encryptionKeyRotationFractionCompleted(
+	progress *jobspb.EncryptionKeyRotationProgress,
+) float32 {
+	var rewritten, remaining int64
+	for i := range progress.Stores {
+		rewritten += progress.Stores[i].FilesRewritten
+		remaining += progress.Stores[i].FilesRemaining
+	}
+	if rewritten+remaining == 0 {
+		return 0
+	}
+	return float32(rewritten) / float32(rewritten+remaining)
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeEncryptionKeyRotation,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &encryptionKeyRotationResumer{job: job}
+		},
+		jobs.DisablesTenantCostControl,
+	)
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvtenant"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangecache"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/closedts/sidetransport"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
@@ -77,6 +78,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/planpins"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
@@ -258,6 +260,28 @@ var traceSessionEventLogEnabled = settings.RegisterBoolSetting(
 	false,
 ).WithPublic()
 
+// readOnlyResultsBufferSize is the size of the buffer that accumulates the
+// results of read-only statements run in implicit transactions. Such
+// statements are retried transparently on retryable errors as long as none of
+// their results have been sent to the client, so they benefit from a larger
+// buffer than the connection's default. The bytes by which the buffer exceeds
+// the connection's results buffer size are accounted for in the session's
+// memory monitor.
+var readOnlyResultsBufferSize = settings.RegisterByteSizeSetting(
+	settings.TenantWritable,
+	"sql.txn.read_only_results_buffer.size",
+	"size of the buffer that accumulates the results of read-only statements in "+
+		"implicit transactions before they are sent to the client, if larger than the "+
+		"connection's results buffer size; such statements are retried automatically on "+
+		"retryable errors as long as none of their results have been sent, so a larger "+
+		"buffer reduces the number of retryable errors returned to clients at the cost "+
+		"of delaying the first result rows and of more memory use; connections whose "+
+		"results buffer size is set explicitly, with the results_buffer_size parameter or "+
+		"the sql.defaults.results_buffer.size setting, are not affected; set to 0 to "+
+		"always use the connection's results buffer size",
+	0,
+)
+
 // ReorderJoinsLimitClusterSettingName is the name of the cluster setting for
 // the maximum number of joins to reorder.
 const ReorderJoinsLimitClusterSettingName = "sql.defaults.reorder_joins_limit"
@@ -1237,6 +1261,9 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// PlanPins maintains the plan hints pinned to statement fingerprints.
+	PlanPins *planpins.Registry
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	GCJobNotifier *gcjobnotifier.Notifier
@@ -1290,6 +1317,11 @@ type ExecutorConfig struct {
 	// access stores on this node.
 	KVStoresIterator kvserverbase.StoresIterator
 
+	// ClosedTimestampSender is the closed timestamp side-transport sender of
+	// this node. It is used by crdb_internal to report the ranges which are
+	// failing to close timestamps. It is nil for secondary tenants.
+	ClosedTimestampSender *sidetransport.Sender
+
 	// CollectionFactory is used to construct a descs.Collection.
 	CollectionFactory *descs.CollectionFactory
 
@@ -1995,6 +2027,12 @@ type SessionArgs struct {
 	// client.
 	RemoteAddr            net.Addr
 	ConnResultsBufferSize int64
+	// ConnResultsBufferSizeOverridden is set if ConnResultsBufferSize was
+	// chosen explicitly, either by the client through the results_buffer_size
+	// connection parameter or by the operator through the
+	// sql.defaults.results_buffer.size setting, in which case it applies to all
+	// the statements.
+	ConnResultsBufferSizeOverridden bool
 	// SessionRevivalToken may contain a token generated from a different session
 	// that can be used to authenticate this session. If it is set, all other
 	// authentication is skipped. Once the token is used to authenticate, this
@@ -3048,6 +3086,18 @@ func (m *sessionDataMutator) SetOptimizerFKCascadesLimit(val int) {
 	m.data.OptimizerFKCascadesLimit = int64(val)
 }
 
+func (m *sessionDataMutator) SetOptimizerMaxConstraintSpans(val int64) {
+	m.data.OptimizerMaxConstraintSpans = val
+}
+
+func (m *sessionDataMutator) SetOptimizerPropagateFKCascadeFilters(val bool) {
+	m.data.OptimizerPropagateFKCascadeFilters = val
+}
+
+func (m *sessionDataMutator) SetOptimizerInlineUDFs(val bool) {
+	m.data.OptimizerInlineUDFs = val
+}
+
 func (m *sessionDataMutator) SetOptimizerUseForecasts(val bool) {
 	m.data.OptimizerUseForecasts = val
 }
@@ -3283,6 +3333,10 @@ func (m *sessionDataMutator) SetParallelizeMultiKeyLookupJoinsEnabled(val bool)
 	m.data.ParallelizeMultiKeyLookupJoinsEnabled = val
 }
 
+func (m *sessionDataMutator) SetStreamerEnabled(val bool) {
+	m.data.StreamerEnabled = val
+}
+
 // TODO(harding): Remove this when costing scans based on average column size
 // is fully supported.
 func (m *sessionDataMutator) SetCostScansWithDefaultColSize(val bool) {
@@ -3341,6 +3395,14 @@ func (m *sessionDataMutator) SetCopyFromAtomicEnabled(val bool) {
 	m.data.CopyFromAtomicEnabled = val
 }
 
+func (m *sessionDataMutator) SetCopyFromBulkIngestEnabled(val bool) {
+	m.data.CopyFromBulkIngestEnabled = val
+}
+
+func (m *sessionDataMutator) SetPartitionSpanPruningEnabled(val bool) {
+	m.data.PartitionSpanPruningEnabled = val
+}
+
 func (m *sessionDataMutator) SetEnforceHomeRegion(val bool) {
 	m.data.EnforceHomeRegion = val
 }
@@ -3471,6 +3533,7 @@ func NewRowMetrics(internal bool) rowinfra.Metrics {
 	return rowinfra.Metrics{
 		MaxRowSizeLogCount: metric.NewCounter(getMetricMeta(rowinfra.MetaMaxRowSizeLog, internal)),
 		MaxRowSizeErrCount: metric.NewCounter(getMetricMeta(rowinfra.MetaMaxRowSizeErr, internal)),
+		MergedSpansCount:   metric.NewCounter(getMetricMeta(rowinfra.MetaMergedScanSpans, internal)),
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
@@ -204,15 +205,20 @@ func (h *LimitHintHelper) ReadSomeRows(rowsRead int64) error {
 }
 
 // CanUseStreamer returns whether the kvstreamer.Streamer API should be used if
-// possible.
-func CanUseStreamer(settings *cluster.Settings) bool {
-	return useStreamerEnabled.Get(&settings.SV)
+// possible. Internal sessions don't initialize the streamer_enabled session
+// setting, so they keep using the cluster setting.
+func CanUseStreamer(settings *cluster.Settings, sd *sessiondata.SessionData) bool {
+	if sd.Internal {
+		return UseStreamerEnabled.Get(&settings.SV)
+	}
+	return sd.StreamerEnabled
 }
 
 // UseStreamer returns whether the kvstreamer.Streamer API should be used as
 // well as the txn that should be used (regardless of the boolean return value).
 func (flowCtx *FlowCtx) UseStreamer() (bool, *kv.Txn, error) {
-	useStreamer := CanUseStreamer(flowCtx.EvalCtx.Settings) && flowCtx.Txn != nil &&
+	useStreamer := CanUseStreamer(flowCtx.EvalCtx.Settings, flowCtx.EvalCtx.SessionData()) &&
+		flowCtx.Txn != nil &&
 		flowCtx.Txn.Type() == kv.LeafTxn && flowCtx.MakeLeafTxn != nil
 	if !useStreamer {
 		return false, flowCtx.Txn, nil
@@ -226,13 +232,15 @@ func (flowCtx *FlowCtx) UseStreamer() (bool, *kv.Txn, error) {
 	return true, leafTxn, nil
 }
 
-// useStreamerEnabled determines whether the Streamer API should be used.
+// UseStreamerEnabled determines the default value for the streamer_enabled
+// session setting, as well as whether internal sessions use the Streamer API.
 // TODO(yuzefovich): remove this in 23.1.
-var useStreamerEnabled = settings.RegisterBoolSetting(
+var UseStreamerEnabled = settings.RegisterBoolSetting(
 	settings.TenantWritable,
 	"sql.distsql.use_streamer.enabled",
-	"determines whether the usage of the Streamer API is allowed. "+
-		"Enabling this will increase the speed of lookup/index joins "+
-		"while adhering to memory limits.",
+	"default value for streamer_enabled session setting; determines "+
+		"whether the usage of the Streamer API is allowed. Enabling this "+
+		"will increase the speed of lookup/index joins while adhering to "+
+		"memory limits.",
 	true,
 )
@@ -255,6 +255,9 @@ func (s *ComponentStats) Union(other *ComponentStats) *ComponentStats {
 	if !result.KV.BatchRequestsIssued.HasValue() {
 		result.KV.BatchRequestsIssued = other.KV.BatchRequestsIssued
 	}
+	if !result.KV.SpansIssued.HasValue() {
+		result.KV.SpansIssued = other.KV.SpansIssued
+	}
 
 	// Exec stats.
 	if !result.Exec.ExecTime.HasValue() {
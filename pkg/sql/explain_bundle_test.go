@@ -176,6 +176,8 @@ CREATE TABLE users(id UUID DEFAULT gen_random_uuid() PRIMARY KEY, promo_id INT R
 			{"null_ordered_last", "on"},
 			{"on_update_rehome_row_enabled", "off"},
 			{"opt_split_scan_limit", "1000"},
+			{"optimizer_max_constraint_spans", "100"},
+			{"optimizer_propagate_fk_cascade_filters", "off"},
 			{"optimizer_use_histograms", "off"},
 			{"optimizer_use_multicol_stats", "off"},
 			{"optimizer_use_not_visible_indexes", "on"},
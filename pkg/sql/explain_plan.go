@@ -14,8 +14,12 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
@@ -197,7 +201,7 @@ func emitExplain(
 		}
 		tabDesc := table.(*optTable).desc
 		idx := index.(*optIndex).idx
-		spans, err := generateScanSpans(evalCtx, codec, tabDesc, idx, scanParams)
+		spans, _, err := generateScanSpans(evalCtx, codec, tabDesc, idx, scanParams)
 		if err != nil {
 			return err.Error()
 		}
@@ -216,7 +220,81 @@ func emitExplain(
 		return catalogkeys.PrettySpans(idx, spans, skip)
 	}
 
-	return explain.Emit(explainPlan, ob, spanFormatFn)
+	keySpansFn := func(table cat.Table, index cat.Index, scanParams exec.ScanParams) []string {
+		tabDesc := table.(*optTable).desc
+		idx := index.(*optIndex).idx
+		spans, _, err := generateScanSpans(evalCtx, codec, tabDesc, idx, scanParams)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return describeKeySpans(codec, tabDesc, idx, spans)
+	}
+
+	return explain.Emit(explainPlan, ob, spanFormatFn, keySpansFn)
+}
+
+// describeKeySpans returns a line for each of the given key spans of an index
+// for EXPLAIN (SPANS). Each line contains the span in pretty-printed and in
+// hex-encoded form, along with the partition of the index (if any) that the
+// start key of the span is routed to, e.g. "/1-/11 [f28989 - f28993)
+// partition: p1".
+func describeKeySpans(
+	codec keys.SQLCodec, tabDesc catalog.TableDescriptor, idx catalog.Index, spans roachpb.Spans,
+) []string {
+	// Collect the coverings of all the partitions of the index, which are sorted
+	// with highest precedence first. These are the same coverings that are used
+	// to route spans to zone configurations.
+	var partitionCoverings []covering.Covering
+	if part := idx.GetPartitioning(); part.NumColumns() > 0 {
+		partitionNames := make(map[string]int32)
+		_ = part.ForEachPartitionName(func(name string) error {
+			partitionNames[name] = int32(len(partitionNames))
+			return nil
+		})
+		var a tree.DatumAlloc
+		var err error
+		partitionCoverings, err = indexCoveringsForPartitioning(
+			&a, codec, tabDesc, idx, part, partitionNames, nil, /* prefixDatums */
+		)
+		if err != nil {
+			return []string{err.Error()}
+		}
+	}
+	findPartition := func(key roachpb.Key) (name string, end roachpb.Key) {
+		for _, c := range partitionCoverings {
+			for _, r := range c {
+				if key.Compare(r.Start) >= 0 && key.Compare(r.End) < 0 {
+					return r.Payload.(zonepb.Subzone).PartitionName, r.End
+				}
+			}
+		}
+		return "", nil
+	}
+
+	// See spanFormatFn in emitExplain for the number of fields to skip.
+	skip := 2
+	if !codec.ForSystemTenant() {
+		skip = 4
+	}
+	valDirs := catalogkeys.IndexKeyValDirs(idx)
+	lines := make([]string, len(spans))
+	for i, sp := range spans {
+		var b strings.Builder
+		b.WriteString(catalogkeys.PrettySpan(valDirs, sp, skip))
+		endKey := sp.EndKey
+		if len(endKey) == 0 {
+			endKey = sp.Key.Next()
+		}
+		fmt.Fprintf(&b, " [%x - %x)", []byte(sp.Key), []byte(endKey))
+		if name, end := findPartition(sp.Key); name != "" {
+			fmt.Fprintf(&b, " partition: %s", name)
+			if endKey.Compare(end) > 0 {
+				b.WriteString(" (and others)")
+			}
+		}
+		lines[i] = b.String()
+	}
+	return lines
 }
 
 func (e *explainPlanNode) Next(params runParams) (bool, error) { return e.run.results.Next(params) }
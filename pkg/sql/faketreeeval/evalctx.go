@@ -235,6 +235,13 @@ func (*DummyEvalPlanner) DecodeGist(gist string, external bool) ([]string, error
 	return nil, errors.WithStack(errEvalPlanner)
 }
 
+// IndexConstraintSpans is part of the Planner interface.
+func (*DummyEvalPlanner) IndexConstraintSpans(
+	ctx context.Context, query string,
+) ([]eval.IndexConstraintSpan, error) {
+	return nil, errors.WithStack(errEvalPlanner)
+}
+
 // SerializeSessionState is part of the Planner interface.
 func (*DummyEvalPlanner) SerializeSessionState() (*tree.DBytes, error) {
 	return nil, errors.WithStack(errEvalPlanner)
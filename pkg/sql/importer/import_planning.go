@@ -104,6 +104,9 @@ const (
 	// RunningStatusImportBundleParseSchema indicates to the user that a bundle format
 	// schema is being parsed
 	runningStatusImportBundleParseSchema jobs.RunningStatus = "parsing schema on Import Bundle"
+	// runningStatusImportPresplitting indicates to the user that the ranges the
+	// IMPORT writes into are being pre-split at keys sampled from the input.
+	runningStatusImportPresplitting jobs.RunningStatus = "pre-splitting ranges from sampled input data"
 )
 
 var importOptionExpectValues = map[string]sql.KVStringOptValidate{
@@ -14,26 +14,33 @@ import (
 	"context"
 	"math"
 	"math/rand"
+	"sort"
 	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
 )
 
 var replanThreshold = settings.RegisterFloatSetting(
@@ -43,6 +50,23 @@ var replanThreshold = settings.RegisterFloatSetting(
 	0.0,
 )
 
+var sampledPresplitCount = settings.RegisterIntSetting(
+	settings.TenantWritable,
+	"bulkio.import.sampled_presplit_count",
+	"number of split points, chosen from a sample of the input files, at which IMPORT pre-splits and scatters the target ranges before ingesting (0=disabled)",
+	0,
+	settings.NonNegativeInt,
+)
+
+var sampledPresplitSampleSize = settings.RegisterIntSetting(
+	settings.TenantWritable,
+	"bulkio.import.sampled_presplit_sample_size",
+	"number of keys sampled uniformly from the input files to choose IMPORT pre-split points; "+
+		"the input files are read in full to take the sample",
+	10000,
+	settings.PositiveInt,
+)
+
 var replanFrequency = settings.RegisterDurationSetting(
 	settings.TenantWritable,
 	"bulkio.import.replan_flow_frequency",
@@ -212,6 +236,11 @@ func distImport(
 		if err := presplitTableBoundaries(ctx, execCtx.ExecCfg(), tables); err != nil {
 			return roachpb.BulkOpSummary{}, err
 		}
+		if err := maybePresplitFromSample(
+			ctx, execCtx, job, tables, typeDescs, from, format, walltime,
+		); err != nil {
+			return roachpb.BulkOpSummary{}, err
+		}
 	}
 
 	recv := sql.MakeDistSQLReceiver(
@@ -353,3 +382,184 @@ func presplitTableBoundaries(
 	}
 	return nil
 }
+
+// maybePresplitFromSample pre-splits and scatters the key space targeted by the
+// IMPORT at split points taken from a sample of the input, so that ingestion is
+// spread across the cluster from the start instead of piling up in the few
+// ranges that cover the empty tables. It is a no-op unless
+// bulkio.import.sampled_presplit_count is set, or if the job is resuming, in
+// which case the splits were already made by the previous attempt.
+//
+// Pre-splitting is best-effort: failing to sample the input or to split at a
+// sampled key is logged, and does not fail the IMPORT.
+func maybePresplitFromSample(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	job *jobs.Job,
+	tables map[string]*execinfrapb.ReadImportDataSpec_ImportTable,
+	typeDescs []*descpb.TypeDescriptor,
+	from []string,
+	format roachpb.IOFileFormat,
+	walltime int64,
+) error {
+	sv := &execCtx.ExecCfg().Settings.SV
+	numSplits := int(sampledPresplitCount.Get(sv))
+	if numSplits == 0 {
+		return nil
+	}
+	for _, pos := range job.Progress().GetImport().ResumePos {
+		if pos > 0 {
+			return nil
+		}
+	}
+
+	var span *tracing.Span
+	ctx, span = tracing.ChildSpan(ctx, "import-pre-splitting-from-sample")
+	defer span.Finish()
+
+	if err := job.RunningStatus(ctx, nil /* txn */, func(_ context.Context, _ jobspb.Details) (jobs.RunningStatus, error) {
+		return runningStatusImportPresplitting, nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to update running status of job %d", errors.Safe(job.ID()))
+	}
+
+	sample, err := sampleImportKeys(
+		ctx, execCtx, job, tables, typeDescs, from, format, walltime, int(sampledPresplitSampleSize.Get(sv)),
+	)
+	if err != nil {
+		log.Warningf(ctx, "failed to sample IMPORT input for pre-splitting: %v", err)
+	} else {
+		presplitAtSampledKeys(ctx, execCtx.ExecCfg().DB, sample, numSplits)
+	}
+
+	return job.RunningStatus(ctx, nil /* txn */, func(_ context.Context, _ jobspb.Details) (jobs.RunningStatus, error) {
+		return "", nil
+	})
+}
+
+// sampleImportKeys converts the rows of the input files into KVs, the same way
+// the import processors will, and returns a uniform sample of up to sampleSize
+// of their keys. Reservoir sampling is used, so that the sample is not skewed
+// towards the start of the input files when the files are not sorted.
+func sampleImportKeys(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	job *jobs.Job,
+	tables map[string]*execinfrapb.ReadImportDataSpec_ImportTable,
+	typeDescs []*descpb.TypeDescriptor,
+	from []string,
+	format roachpb.IOFileFormat,
+	walltime int64,
+	sampleSize int,
+) ([]roachpb.Key, error) {
+	// Rows rejected while sampling will be rejected again by the processors.
+	format.SaveRejected = false
+
+	// The table descriptors are hydrated below, so make sure the ones used by
+	// the processors are left untouched.
+	sampleTables := make(map[string]*execinfrapb.ReadImportDataSpec_ImportTable, len(tables))
+	for name, table := range tables {
+		sampleTable := *table
+		sampleTable.Desc = protoutil.Clone(table.Desc).(*descpb.TableDescriptor)
+		sampleTables[name] = &sampleTable
+	}
+	spec := &execinfrapb.ReadImportDataSpec{
+		Tables:            sampleTables,
+		Types:             typeDescs,
+		Format:            format,
+		WalltimeNanos:     walltime,
+		Uri:               make(map[int32]string, len(from)),
+		ReaderParallelism: 1,
+	}
+	for i, input := range from {
+		spec.Uri[int32(i)] = input
+	}
+
+	importResolver := newImportTypeResolver(spec.Types)
+	for _, table := range spec.Tables {
+		if err := typedesc.HydrateTypesInTableDescriptor(ctx, table.Desc, importResolver); err != nil {
+			return nil, err
+		}
+	}
+	evalCtx := execCtx.ExtendedEvalContext().Context.Copy()
+	evalCtx.Regions = makeImportRegionOperator(job.Details().(jobspb.ImportDetails).DatabasePrimaryRegion)
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.TypeResolver = importResolver
+
+	// No sequence chunk provider is passed in, so that sampling does not
+	// allocate sequence values. Tables with nextval() defaults fail to sample.
+	kvCh := make(chan row.KVBatch, 10)
+	conv, err := makeInputConverter(
+		ctx, &semaCtx, spec, evalCtx, kvCh, nil /* seqChunkProvider */, execCtx.ExecCfg().DB,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := make([]roachpb.Key, 0, sampleSize)
+	rng := rand.New(rand.NewSource(int64(job.ID())))
+	group := ctxgroup.WithContext(ctx)
+	conv.start(group)
+	group.GoCtx(func(ctx context.Context) error {
+		defer close(kvCh)
+		return conv.readFiles(ctx, spec.Uri, nil /* resumePos */, spec.Format,
+			execCtx.ExecCfg().DistSQLSrv.ExternalStorage, execCtx.User())
+	})
+	group.GoCtx(func(ctx context.Context) error {
+		var numKeys int64
+		for batch := range kvCh {
+			for i := range batch.KVs {
+				numKeys++
+				if len(sample) < sampleSize {
+					sample = append(sample, batch.KVs[i].Key)
+				} else if j := rng.Int63n(numKeys); j < int64(sampleSize) {
+					sample[j] = batch.KVs[i].Key
+				}
+			}
+		}
+		return nil
+	})
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return sample, nil
+}
+
+// presplitAtSampledKeys splits the key space at numSplits keys evenly spaced
+// in the sorted sample, and then scatters the resulting ranges. Like the
+// initial splits made by the BulkAdder, all splits are made before any scatter
+// so that the scatters only move the narrower, post-split spans.
+func presplitAtSampledKeys(ctx context.Context, db *kv.DB, sample []roachpb.Key, numSplits int) {
+	if len(sample) == 0 {
+		return
+	}
+	sort.Slice(sample, func(i, j int) bool { return sample[i].Compare(sample[j]) < 0 })
+
+	expirationTime := db.Clock().Now().Add(time.Hour.Nanoseconds(), 0)
+	var toScatter []roachpb.Key
+	var prevSplitKey roachpb.Key
+	for i := 1; i <= numSplits; i++ {
+		splitAt := i * len(sample) / (numSplits + 1)
+		splitKey, err := keys.EnsureSafeSplitKey(sample[splitAt])
+		if err != nil {
+			log.Warningf(ctx, "failed to generate pre-split key for key %s", sample[splitAt])
+			continue
+		}
+		if splitKey.Equal(prevSplitKey) {
+			continue
+		}
+		prevSplitKey = splitKey
+		if err := db.AdminSplit(ctx, splitKey, expirationTime); err != nil {
+			log.Warningf(ctx, "failed to pre-split at %s: %v", splitKey, err)
+			continue
+		}
+		toScatter = append(toScatter, splitKey)
+	}
+	for _, splitKey := range toScatter {
+		if _, err := db.AdminScatter(ctx, splitKey, 0 /* maxSize */); err != nil {
+			log.Warningf(ctx, "failed to scatter pre-split range at %s: %v", splitKey, err)
+		}
+	}
+	log.Infof(ctx, "pre-split and scattered %d ranges from a sample of %d keys",
+		len(toScatter), len(sample))
+}
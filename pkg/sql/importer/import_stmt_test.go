@@ -1640,6 +1640,51 @@ ALTER TABLE ONLY public.b
 `
 )
 
+func TestImportSampledPresplits(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var data strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&data, "%d,%d\n", i, i)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			_, _ = w.Write([]byte(data.String()))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	tc := serverutils.StartNewTestCluster(t, 1, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.import.sampled_presplit_count = 4`)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b INT)`)
+	sqlDB.Exec(t, fmt.Sprintf(`IMPORT INTO t CSV DATA ('%s')`, srv.URL))
+
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t`, [][]string{{"1000"}})
+	var numRanges int
+	sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW RANGES FROM TABLE t]`).Scan(&numRanges)
+	require.GreaterOrEqual(t, numRanges, 5)
+	// The sample covers the whole input rather than its first rows, so the end
+	// of the input is split too.
+	var rangeA, rangeB int
+	sqlDB.QueryRow(t, `SELECT range_id FROM [SHOW RANGE FROM TABLE t FOR ROW (700)]`).Scan(&rangeA)
+	sqlDB.QueryRow(t, `SELECT range_id FROM [SHOW RANGE FROM TABLE t FOR ROW (900)]`).Scan(&rangeB)
+	require.NotEqual(t, rangeA, rangeB)
+
+	// A sample smaller than the input still spreads the splits over the whole
+	// input.
+	sqlDB.Exec(t, `SET CLUSTER SETTING bulkio.import.sampled_presplit_sample_size = 100`)
+	sqlDB.Exec(t, `CREATE TABLE t2 (a INT PRIMARY KEY, b INT)`)
+	sqlDB.Exec(t, fmt.Sprintf(`IMPORT INTO t2 CSV DATA ('%s')`, srv.URL))
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM t2`, [][]string{{"1000"}})
+	sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW RANGES FROM TABLE t2]`).Scan(&numRanges)
+	require.GreaterOrEqual(t, numRanges, 5)
+}
+
 func TestImportRowLimit(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -142,6 +142,10 @@ type instrumentationHelper struct {
 	// costEstimate is the cost of the query as estimated by the optimizer.
 	costEstimate float64
 
+	// indexesUsed is the list of indexes used by the query, in the format
+	// tableID@indexID.
+	indexesUsed []string
+
 	// indexRecs contains index recommendations for the planned statement. It
 	// will only be populated if the statement is an EXPLAIN statement, or if
 	// recommendations are requested for the statement for populating the
@@ -645,6 +649,7 @@ func (m execNodeTraceMetadata) annotateExplain(
 				nodeStats.KVBytesRead.MaybeAdd(stats.KV.BytesRead)
 				nodeStats.KVRowsRead.MaybeAdd(stats.KV.TuplesRead)
 				nodeStats.KVBatchRequestsIssued.MaybeAdd(stats.KV.BatchRequestsIssued)
+				nodeStats.KVSpansIssued.MaybeAdd(stats.KV.SpansIssued)
 				nodeStats.StepCount.MaybeAdd(stats.KV.NumInterfaceSteps)
 				nodeStats.InternalStepCount.MaybeAdd(stats.KV.NumInternalSteps)
 				nodeStats.SeekCount.MaybeAdd(stats.KV.NumInterfaceSeeks)
@@ -1640,6 +1640,13 @@ func TestLogic_show_create(
 	runLogicTest(t, "show_create")
 }
 
+func TestLogic_show_create_all_objects(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "show_create_all_objects")
+}
+
 func TestLogic_show_create_all_schemas(
 	t *testing.T,
 ) {
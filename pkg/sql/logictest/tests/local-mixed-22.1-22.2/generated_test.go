@@ -148,3 +148,10 @@ func TestLogic_synthetic_privileges_mixed(
 	defer leaktest.AfterTest(t)()
 	runLogicTest(t, "synthetic_privileges_mixed")
 }
+
+func TestLogic_zone_config_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "zone_config_mixed")
+}
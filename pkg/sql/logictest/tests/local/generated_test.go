@@ -1381,6 +1381,13 @@ func TestLogic_pgoidtype(
 	runLogicTest(t, "pgoidtype")
 }
 
+func TestLogic_plan_pins(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "plan_pins")
+}
+
 func TestLogic_poison_after_push(
 	t *testing.T,
 ) {
@@ -1752,6 +1759,13 @@ func TestLogic_show_create(
 	runLogicTest(t, "show_create")
 }
 
+func TestLogic_show_create_all_objects(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "show_create_all_objects")
+}
+
 func TestLogic_show_create_all_schemas(
 	t *testing.T,
 ) {
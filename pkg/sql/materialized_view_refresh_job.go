@@ -0,0 +1,820 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// incrementalRefreshBatchSize is the number of rows whose values are looked
+// up by each of the queries run during an incremental refresh.
+const incrementalRefreshBatchSize = 1000
+
+// incrementalRefreshQuery is the query of a materialized view which can be
+// refreshed incrementally. Such a query selects from a single table, and is
+// either a projection and filter of its rows, or an aggregation of them.
+type incrementalRefreshQuery struct {
+	sel *tree.SelectClause
+	// aggregate is set if the query aggregates the rows of the table, in which
+	// case groupBy holds the grouping expressions, if any.
+	aggregate bool
+	groupBy   tree.Exprs
+}
+
+func errIncrementalRefreshNotSupported(reason string) error {
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"materialized view cannot be refreshed incrementally: %s", reason)
+}
+
+// makeIncrementalRefreshQuery parses the query of a materialized view, and
+// returns an error if the view cannot be refreshed incrementally.
+func makeIncrementalRefreshQuery(viewQuery string) (incrementalRefreshQuery, error) {
+	stmt, err := parser.ParseOne(viewQuery)
+	if err != nil {
+		return incrementalRefreshQuery{}, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported("query is not a SELECT")
+	}
+	for {
+		paren, ok := sel.Select.(*tree.ParenSelect)
+		if !ok {
+			break
+		}
+		sel = paren.Select
+	}
+	if sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported(
+			"query has a WITH, ORDER BY, LIMIT or locking clause")
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || clause.TableSelect {
+		return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported("query is not a simple SELECT")
+	}
+	if clause.Distinct || clause.DistinctOn != nil || clause.Window != nil {
+		return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported(
+			"query has a DISTINCT or WINDOW clause")
+	}
+	if len(clause.From.Tables) != 1 || clause.From.AsOf.Expr != nil {
+		return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported(
+			"query does not select from a single table")
+	}
+	switch t := clause.From.Tables[0].(type) {
+	case *tree.TableName:
+	case *tree.AliasedTableExpr:
+		if _, ok := t.Expr.(*tree.TableName); !ok || t.Ordinality || t.Lateral || t.As.Cols != nil {
+			return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported(
+				"query does not select from a single table")
+		}
+	default:
+		return incrementalRefreshQuery{}, errIncrementalRefreshNotSupported(
+			"query does not select from a single table")
+	}
+
+	v := incrementalRefreshVisitor{}
+	for _, e := range clause.Exprs {
+		tree.WalkExprConst(&v, e.Expr)
+	}
+	for _, e := range clause.GroupBy {
+		tree.WalkExprConst(&v, e)
+	}
+	if clause.Where != nil {
+		tree.WalkExprConst(&v, clause.Where.Expr)
+	}
+	if clause.Having != nil {
+		tree.WalkExprConst(&v, clause.Having.Expr)
+	}
+	if v.err != nil {
+		return incrementalRefreshQuery{}, v.err
+	}
+	return incrementalRefreshQuery{
+		sel:       clause,
+		aggregate: v.aggregate || len(clause.GroupBy) > 0 || clause.Having != nil,
+		groupBy:   tree.Exprs(clause.GroupBy),
+	}, nil
+}
+
+// incrementalRefreshVisitor checks that the expressions of a view query can be
+// evaluated on the changed rows of the table alone, i.e. that they only
+// reference columns of the table and call immutable functions. Stable functions
+// are rejected since their results may differ between the time the old and new
+// versions of the rows are evaluated, and the time the view was last refreshed.
+type incrementalRefreshVisitor struct {
+	aggregate bool
+	err       error
+}
+
+var _ tree.Visitor = &incrementalRefreshVisitor{}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *incrementalRefreshVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case tree.UnqualifiedStar, *tree.AllColumnsSelector, *tree.TupleStar:
+		v.err = errIncrementalRefreshNotSupported("query selects all columns with *")
+	case *tree.Subquery:
+		v.err = errIncrementalRefreshNotSupported("query has a subquery")
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.err = errIncrementalRefreshNotSupported("query has a window function")
+			break
+		}
+		name, ok := t.Func.FunctionReference.(*tree.UnresolvedName)
+		if !ok {
+			v.err = errIncrementalRefreshNotSupported("query calls a user-defined function")
+			break
+		}
+		props, overloads := builtinsregistry.GetBuiltinProperties(strings.ToLower(name.String()))
+		if props == nil {
+			v.err = errIncrementalRefreshNotSupported(
+				fmt.Sprintf("query calls unsupported function %s", name))
+			break
+		}
+		switch props.Class {
+		case tree.AggregateClass:
+			v.aggregate = true
+		case tree.WindowClass, tree.GeneratorClass:
+			v.err = errIncrementalRefreshNotSupported(
+				fmt.Sprintf("query calls unsupported function %s", name))
+		}
+		for i := range overloads {
+			if overloads[i].Volatility > volatility.Immutable {
+				v.err = errIncrementalRefreshNotSupported(
+					fmt.Sprintf("query calls non-immutable function %s", name))
+				break
+			}
+		}
+	}
+	return v.err == nil, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *incrementalRefreshVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// checkIncrementalRefreshView returns an error if the rows of the view cannot
+// be maintained incrementally. The view must only have the hidden rowid
+// column as its primary key, and no indexes whose entries can't be written
+// by the row writers directly.
+func checkIncrementalRefreshView(view catalog.TableDescriptor) error {
+	if view.GetPrimaryIndex().NumKeyColumns() != 1 {
+		return errIncrementalRefreshNotSupported("view has a user-defined primary key")
+	}
+	pkCol, err := view.FindColumnWithID(view.GetPrimaryIndex().GetKeyColumnID(0))
+	if err != nil {
+		return err
+	}
+	if !pkCol.IsHidden() {
+		return errIncrementalRefreshNotSupported("view has a user-defined primary key")
+	}
+	if len(view.PartialIndexes()) > 0 {
+		return errIncrementalRefreshNotSupported("view has partial indexes")
+	}
+	for _, c := range view.PublicColumns() {
+		if c.IsVirtual() {
+			return errIncrementalRefreshNotSupported("view has virtual columns")
+		}
+	}
+	return nil
+}
+
+// materializedViewRefreshResumer implements the jobs.Resumer interface for the
+// jobs created by REFRESH MATERIALIZED VIEW ... INCREMENTAL. The job reads the
+// rows of the table which changed since the view was last refreshed with a
+// rangefeed, evaluates the view query on the old and new versions of those
+// rows, and applies the difference to the rows of the view.
+type materializedViewRefreshResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*materializedViewRefreshResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r *materializedViewRefreshResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.MaterializedViewRefreshDetails)
+
+	var view, table catalog.TableDescriptor
+	if err := DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		var err error
+		flags := tree.ObjectLookupFlagsWithRequired()
+		if view, err = col.GetImmutableTableByID(ctx, txn, details.ViewID, flags); err != nil {
+			return err
+		}
+		table, err = col.GetImmutableTableByID(ctx, txn, details.TableID, flags)
+		return err
+	}); err != nil {
+		return err
+	}
+	if view.GetLastRefreshTime() == details.EndTime {
+		// The changes were already applied before the job was resumed.
+		return nil
+	}
+	q, err := makeIncrementalRefreshQuery(view.GetViewQuery())
+	if err != nil {
+		return err
+	}
+
+	var removed, added []tree.Datums
+	changed, err := collectChangedRows(ctx, execCfg, table, details.StartTime, details.EndTime)
+	if errors.Is(err, errChangedRowsUnavailable) {
+		log.Infof(ctx, "refreshing materialized view %q in full: %v", view.GetName(), err)
+		removed, added, err = q.fullDiff(ctx, execCfg, view, details.EndTime)
+		if err != nil {
+			return err
+		}
+		if err := applyMaterializedViewDiff(ctx, execCfg, details, removed, added); err != nil {
+			return err
+		}
+		return r.job.FractionProgressed(
+			ctx, nil /* txn */, func(ctx context.Context, d jobspb.ProgressDetails) float32 {
+				prog := d.(*jobspb.Progress_MaterializedViewRefresh).MaterializedViewRefresh
+				prog.DeletedRows = int64(len(removed))
+				prog.InsertedRows = int64(len(added))
+				return 1
+			},
+		)
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.job.FractionProgressed(
+		ctx, nil /* txn */, func(ctx context.Context, d jobspb.ProgressDetails) float32 {
+			d.(*jobspb.Progress_MaterializedViewRefresh).MaterializedViewRefresh.ChangedRows = int64(len(changed))
+			return 0.5
+		},
+	); err != nil {
+		return err
+	}
+
+	removed, added, err = q.diff(ctx, execCfg, table, changed, details.StartTime, details.EndTime)
+	if err != nil {
+		return err
+	}
+	if err := applyMaterializedViewDiff(ctx, execCfg, details, removed, added); err != nil {
+		return err
+	}
+	return r.job.FractionProgressed(
+		ctx, nil /* txn */, func(ctx context.Context, d jobspb.ProgressDetails) float32 {
+			prog := d.(*jobspb.Progress_MaterializedViewRefresh).MaterializedViewRefresh
+			prog.DeletedRows = int64(len(removed))
+			prog.InsertedRows = int64(len(added))
+			return 1
+		},
+	)
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The rows of the view
+// are only written once all changes are computed, in a single transaction, so
+// there is nothing to revert.
+func (r *materializedViewRefreshResumer) OnFailOrCancel(context.Context, interface{}, error) error {
+	return nil
+}
+
+// errChangedRowsUnavailable is returned by collectChangedRows when the rows
+// which changed since the last refresh can't be determined, e.g. because the
+// history of the table was garbage collected, in which case the view query is
+// evaluated in full instead.
+var errChangedRowsUnavailable = errors.New("rows changed since the last refresh are unavailable")
+
+// markChangedRowsUnavailable marks the errors returned by a rangefeed which
+// imply that the changes to the table can't be read.
+func markChangedRowsUnavailable(err error) error {
+	if errors.HasType(err, (*roachpb.BatchTimestampBeforeGCError)(nil)) ||
+		errors.HasType(err, (*roachpb.MVCCHistoryMutationError)(nil)) {
+		return errors.Mark(err, errChangedRowsUnavailable)
+	}
+	return err
+}
+
+// collectChangedRows returns the primary keys of the rows of the table which
+// changed in (startTime, endTime]. The changes are read with a rangefeed over
+// the primary index of the table, whose catch-up scan starts at startTime. An
+// error marked with errChangedRowsUnavailable is returned if the changes can't
+// be read, e.g. if the primary key of the table changed since startTime.
+func collectChangedRows(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	table catalog.TableDescriptor,
+	startTime, endTime hlc.Timestamp,
+) ([]tree.Datums, error) {
+	// The changes made before the primary key of the table was altered are in
+	// the old primary index.
+	if err := DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		if err := txn.SetFixedTimestamp(ctx, startTime); err != nil {
+			return err
+		}
+		prev, err := col.GetImmutableTableByID(ctx, txn, table.GetID(), tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{Required: true, AvoidLeased: true},
+		})
+		if err != nil {
+			return err
+		}
+		if prev.GetPrimaryIndexID() != table.GetPrimaryIndexID() {
+			return errors.Mark(errors.Newf("primary key of table %s was altered", table.GetName()),
+				errChangedRowsUnavailable)
+		}
+		return nil
+	}); err != nil {
+		return nil, markChangedRowsUnavailable(err)
+	}
+
+	var mu struct {
+		syncutil.Mutex
+		keys map[string]struct{}
+	}
+	mu.keys = make(map[string]struct{})
+	done := make(chan struct{})
+	var doneOnce sync.Once
+	errCh := make(chan error, 1)
+	setErr := func(err error) {
+		select {
+		case errCh <- err:
+		default:
+		}
+	}
+
+	addKey := func(key roachpb.Key, ts hlc.Timestamp) {
+		if endTime.Less(ts) {
+			return
+		}
+		rowKey, err := keys.EnsureSafeSplitKey(key)
+		if err != nil {
+			setErr(err)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		mu.keys[string(rowKey)] = struct{}{}
+	}
+	onValue := func(ctx context.Context, value *roachpb.RangeFeedValue) {
+		addKey(value.Key, value.Value.Timestamp)
+	}
+	span := table.PrimaryIndexSpan(execCfg.Codec)
+	// The rows written by bulk operations are ingested as SSTs, whose keys are
+	// only emitted once.
+	onSSTable := func(ctx context.Context, sst *roachpb.RangeFeedSSTable, registeredSpan roachpb.Span) {
+		if err := forEachSSTKey(sst.Data, span.Intersect(registeredSpan), addKey); err != nil {
+			setErr(err)
+		}
+	}
+	rf, err := execCfg.RangeFeedFactory.RangeFeed(
+		ctx, "materialized-view-refresh",
+		[]roachpb.Span{span}, startTime, onValue,
+		rangefeed.WithOnFrontierAdvance(func(ctx context.Context, ts hlc.Timestamp) {
+			if endTime.LessEq(ts) {
+				doneOnce.Do(func() { close(done) })
+			}
+		}),
+		rangefeed.WithOnSSTable(onSSTable),
+		rangefeed.WithOnInternalError(func(ctx context.Context, err error) {
+			setErr(markChangedRowsUnavailable(err))
+		}),
+		rangefeed.WithOnDeleteRange(func(ctx context.Context, _ *roachpb.RangeFeedDeleteRange) {
+			setErr(errors.Mark(errors.Newf("rows of table %s were deleted in bulk", table.GetName()),
+				errChangedRowsUnavailable))
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-done:
+	case err := <-errCh:
+		rf.Close()
+		return nil, errors.Wrapf(err, "reading changes to table %s", table.GetName())
+	case <-ctx.Done():
+		rf.Close()
+		return nil, ctx.Err()
+	}
+	rf.Close()
+
+	keyCols := table.IndexKeyColumns(table.GetPrimaryIndex())
+	colTypes := make([]*types.T, len(keyCols))
+	for i, c := range keyCols {
+		colTypes[i] = c.GetType()
+	}
+	dirs := table.IndexKeyColumnDirections(table.GetPrimaryIndex())
+	var alloc tree.DatumAlloc
+	mu.Lock()
+	defer mu.Unlock()
+	rows := make([]tree.Datums, 0, len(mu.keys))
+	for k := range mu.keys {
+		vals := make([]rowenc.EncDatum, len(keyCols))
+		if _, _, err := rowenc.DecodeIndexKey(execCfg.Codec, colTypes, vals, dirs, []byte(k)); err != nil {
+			return nil, err
+		}
+		pk := make(tree.Datums, len(vals))
+		for i := range vals {
+			if err := vals[i].EnsureDecoded(colTypes[i], &alloc); err != nil {
+				return nil, err
+			}
+			pk[i] = vals[i].Datum
+		}
+		rows = append(rows, pk)
+	}
+	return rows, nil
+}
+
+// forEachSSTKey calls fn with the point keys of the given SST which are within
+// the given span. An error marked with errChangedRowsUnavailable is returned if
+// the SST has range keys within the span, i.e. deletes rows in bulk.
+func forEachSSTKey(
+	data []byte, span roachpb.Span, fn func(key roachpb.Key, ts hlc.Timestamp),
+) error {
+	iter, err := storage.NewPebbleMemSSTIterator(data, true /* verify */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsAndRanges,
+		LowerBound: span.Key,
+		UpperBound: span.EndKey,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for iter.SeekGE(storage.MVCCKey{Key: span.Key}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		if hasPoint, hasRange := iter.HasPointAndRange(); hasRange {
+			return errors.Mark(errors.Newf("rows were deleted in bulk in span %s", span),
+				errChangedRowsUnavailable)
+		} else if hasPoint {
+			fn(iter.UnsafeKey().Key, iter.UnsafeKey().Timestamp)
+		}
+	}
+}
+
+// fullDiff returns the rows to remove from and add to the view so that it
+// reflects the result of its query as of endTime, by comparing the current
+// rows of the view with the result of the query. It is used when the rows of
+// the table which changed since the last refresh are unavailable.
+func (q incrementalRefreshQuery) fullDiff(
+	ctx context.Context, execCfg *ExecutorConfig, view catalog.TableDescriptor, endTime hlc.Timestamp,
+) (removed, added []tree.Datums, _ error) {
+	var colNames tree.NameList
+	for _, c := range view.PublicColumns() {
+		if c.GetID() != view.GetPrimaryIndex().GetKeyColumnID(0) {
+			colNames = append(colNames, tree.Name(c.GetName()))
+		}
+	}
+	// The view is only written by refreshes, which applyMaterializedViewDiff
+	// checks did not happen concurrently.
+	before, err := execCfg.InternalExecutor.QueryBufferedEx(
+		ctx, "materialized-view-refresh", nil, /* txn */
+		sessiondata.RootUserSessionDataOverride,
+		fmt.Sprintf("SELECT %s FROM [%d AS v]", colNames.String(), view.GetID()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := execCfg.InternalExecutor.QueryBufferedEx(
+		ctx, "materialized-view-refresh", nil, /* txn */
+		sessiondata.RootUserSessionDataOverride,
+		tree.AsStringWithFlags(withAsOfAndFilter(q.sel, endTime, nil /* filter */), tree.FmtParsable),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return subtractDatums(before, after), subtractDatums(after, before), nil
+}
+
+// diff returns the rows to remove from and add to the view so that it
+// reflects the table as of endTime rather than startTime, given the primary
+// keys of the rows of the table which changed in between.
+func (q incrementalRefreshQuery) diff(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	table catalog.TableDescriptor,
+	changed []tree.Datums,
+	startTime, endTime hlc.Timestamp,
+) (removed, added []tree.Datums, _ error) {
+	if len(changed) == 0 {
+		return nil, nil, nil
+	}
+	keyCols := table.IndexKeyColumns(table.GetPrimaryIndex())
+	pkExprs := make(tree.Exprs, len(keyCols))
+	for i, c := range keyCols {
+		pkExprs[i] = &tree.ColumnItem{ColumnName: tree.Name(c.GetName())}
+	}
+	query := func(sel *tree.SelectClause, ts hlc.Timestamp, filter tree.Expr) ([]tree.Datums, error) {
+		return execCfg.InternalExecutor.QueryBufferedEx(
+			ctx, "materialized-view-refresh", nil, /* txn */
+			sessiondata.RootUserSessionDataOverride,
+			tree.AsStringWithFlags(withAsOfAndFilter(sel, ts, filter), tree.FmtParsable),
+		)
+	}
+
+	// Evaluate the view query on the old and new versions of the changed rows,
+	// or on the groups they belonged to for aggregations.
+	evaluate := func(ts hlc.Timestamp) ([]tree.Datums, error) {
+		var res []tree.Datums
+		if q.aggregate && len(q.groupBy) == 0 {
+			// Any change can affect the single row of a scalar aggregation.
+			return query(q.sel, ts, nil /* filter */)
+		}
+		for start := 0; start < len(changed); start += incrementalRefreshBatchSize {
+			end := start + incrementalRefreshBatchSize
+			if end > len(changed) {
+				end = len(changed)
+			}
+			filter := rowsFilter(pkExprs, changed[start:end])
+			if !q.aggregate {
+				rows, err := query(q.sel, ts, filter)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, rows...)
+				continue
+			}
+			// Find the groups the changed rows belonged to at either timestamp,
+			// and evaluate the aggregation on all of their rows.
+			groupSel := &tree.SelectClause{From: q.sel.From, Where: q.sel.Where}
+			for _, e := range q.groupBy {
+				groupSel.Exprs = append(groupSel.Exprs, tree.SelectExpr{Expr: e})
+			}
+			var groups []tree.Datums
+			for _, groupTS := range []hlc.Timestamp{startTime, endTime} {
+				rows, err := query(groupSel, groupTS, filter)
+				if err != nil {
+					return nil, err
+				}
+				groups = append(groups, rows...)
+			}
+			groups = dedupDatums(groups)
+			for len(groups) > 0 {
+				n := len(groups)
+				if n > incrementalRefreshBatchSize {
+					n = incrementalRefreshBatchSize
+				}
+				rows, err := query(q.sel, ts, rowsFilter(q.groupBy, groups[:n]))
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, rows...)
+				groups = groups[n:]
+			}
+		}
+		if q.aggregate {
+			// A group can be affected by rows from several batches.
+			res = dedupDatums(res)
+		}
+		return res, nil
+	}
+	before, err := evaluate(startTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := evaluate(endTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	removed, added = subtractDatums(before, after), subtractDatums(after, before)
+	return removed, added, nil
+}
+
+// withAsOfAndFilter returns a copy of the given query which reads the table as
+// of the given timestamp, and only the rows that pass the filter, if any.
+func withAsOfAndFilter(sel *tree.SelectClause, ts hlc.Timestamp, filter tree.Expr) *tree.Select {
+	c := *sel
+	c.From.AsOf = tree.AsOfClause{Expr: tree.NewStrVal(ts.AsOfSystemTime())}
+	if filter != nil {
+		if c.Where == nil {
+			c.Where = tree.NewWhere(tree.AstWhere, filter)
+		} else {
+			c.Where = tree.NewWhere(tree.AstWhere, &tree.AndExpr{
+				Left: &tree.ParenExpr{Expr: c.Where.Expr}, Right: &tree.ParenExpr{Expr: filter},
+			})
+		}
+	}
+	return &tree.Select{Select: &c}
+}
+
+// rowsFilter returns an expression which selects the rows for which the given
+// expressions evaluate to one of the given tuples of values.
+func rowsFilter(exprs tree.Exprs, rows []tree.Datums) tree.Expr {
+	var filter tree.Expr
+	for _, r := range rows {
+		var match tree.Expr
+		for i, e := range exprs {
+			cmp := &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.IsNotDistinctFrom),
+				Left:     &tree.ParenExpr{Expr: e},
+				Right:    r[i],
+			}
+			if match == nil {
+				match = cmp
+			} else {
+				match = &tree.AndExpr{Left: match, Right: cmp}
+			}
+		}
+		if filter == nil {
+			filter = match
+		} else {
+			filter = &tree.OrExpr{Left: filter, Right: match}
+		}
+	}
+	return filter
+}
+
+// datumsKey returns a string which identifies a row by its values.
+func datumsKey(row tree.Datums) string {
+	return tree.AsStringWithFlags(&row, tree.FmtParsable)
+}
+
+// dedupDatums removes the duplicate rows from the given rows.
+func dedupDatums(rows []tree.Datums) []tree.Datums {
+	seen := make(map[string]struct{}, len(rows))
+	res := rows[:0]
+	for _, r := range rows {
+		k := datumsKey(r)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		res = append(res, r)
+	}
+	return res
+}
+
+// subtractDatums returns the multiset difference of the given rows, i.e. the
+// rows of a which are not matched by a row of b.
+func subtractDatums(a, b []tree.Datums) []tree.Datums {
+	counts := make(map[string]int, len(b))
+	for _, r := range b {
+		counts[datumsKey(r)]++
+	}
+	var res []tree.Datums
+	for _, r := range a {
+		k := datumsKey(r)
+		if counts[k] > 0 {
+			counts[k]--
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// applyMaterializedViewDiff removes and adds the given rows to the view, and
+// advances its last refresh time, in a single transaction.
+func applyMaterializedViewDiff(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	details jobspb.MaterializedViewRefreshDetails,
+	removed, added []tree.Datums,
+) error {
+	return DescsTxn(ctx, execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		view, err := col.GetMutableTableVersionByID(ctx, details.ViewID, txn)
+		if err != nil {
+			return err
+		}
+		if view.LastRefreshTime != details.StartTime || len(view.Mutations) > 0 {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"materialized view %q was refreshed or altered concurrently", view.GetName())
+		}
+		if err := checkIncrementalRefreshView(view); err != nil {
+			return err
+		}
+
+		// The columns of the view are the columns of its query, followed by the
+		// hidden rowid column.
+		cols := view.PublicColumns()
+		rowIDIdx := -1
+		colNames := make(tree.NameList, len(cols))
+		var valueExprs tree.Exprs
+		for i, c := range cols {
+			colNames[i] = tree.Name(c.GetName())
+			if c.GetID() == view.GetPrimaryIndex().GetKeyColumnID(0) {
+				rowIDIdx = i
+				continue
+			}
+			valueExprs = append(valueExprs, &tree.ColumnItem{ColumnName: colNames[i]})
+		}
+		if rowIDIdx == -1 {
+			return errors.AssertionFailedf("missing rowid column in view %q", view.GetName())
+		}
+
+		b := txn.NewBatch()
+		sv := &execCfg.Settings.SV
+		deleter := row.MakeDeleter(execCfg.Codec, view, cols, sv, true /* internal */, nil /* metrics */)
+		remaining := make(map[string]int, len(removed))
+		for _, r := range removed {
+			remaining[datumsKey(r)]++
+		}
+		for start := 0; start < len(removed); start += incrementalRefreshBatchSize {
+			end := start + incrementalRefreshBatchSize
+			if end > len(removed) {
+				end = len(removed)
+			}
+			stmt := fmt.Sprintf("SELECT %s FROM [%d AS v] WHERE %s",
+				colNames.String(), view.GetID(),
+				tree.AsStringWithFlags(rowsFilter(valueExprs, removed[start:end]), tree.FmtParsable))
+			rows, err := execCfg.InternalExecutor.QueryBufferedEx(
+				ctx, "materialized-view-refresh", txn, sessiondata.RootUserSessionDataOverride, stmt,
+			)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				values := append(tree.Datums(nil), r[:rowIDIdx]...)
+				values = append(values, r[rowIDIdx+1:]...)
+				k := datumsKey(values)
+				if remaining[k] == 0 {
+					continue
+				}
+				remaining[k]--
+				if err := deleter.DeleteRow(
+					ctx, b, r, row.PartialIndexUpdateHelper{}, false, /* traceKV */
+				); err != nil {
+					return err
+				}
+			}
+		}
+		for _, n := range remaining {
+			if n > 0 {
+				return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+					"rows of materialized view %q do not match its query; a full refresh is required",
+					view.GetName())
+			}
+		}
+
+		var alloc tree.DatumAlloc
+		inserter, err := row.MakeInserter(
+			ctx, txn, execCfg.Codec, view, cols, &alloc, sv, true /* internal */, nil, /* metrics */
+		)
+		if err != nil {
+			return err
+		}
+		instanceID := execCfg.NodeInfo.NodeID.SQLInstanceID()
+		for _, r := range added {
+			values := make(tree.Datums, 0, len(cols))
+			values = append(values, r[:rowIDIdx]...)
+			values = append(values, tree.NewDInt(builtins.GenerateUniqueInt(instanceID)))
+			values = append(values, r[rowIDIdx:]...)
+			if err := inserter.InsertRow(
+				ctx, b, values, row.PartialIndexUpdateHelper{}, false /* overwrite */, false, /* traceKV */
+			); err != nil {
+				return err
+			}
+		}
+		if err := txn.Run(ctx, b); err != nil {
+			return err
+		}
+
+		view.LastRefreshTime = details.EndTime
+		return col.WriteDesc(ctx, false /* kvTrace */, view, txn)
+	})
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeMaterializedViewRefresh,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &materializedViewRefreshResumer{job: job}
+		},
+		jobs.DisablesTenantCostControl,
+	)
+}
@@ -247,3 +247,82 @@ CREATE MATERIALIZED VIEW t.v AS SELECT x FROM t.t;
 		return nil
 	})
 }
+
+// TestMaterializedViewIncrementalRefresh verifies that an incremental refresh
+// of a materialized view applies the changes made to its table since the view
+// was last refreshed.
+func TestMaterializedViewIncrementalRefresh(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, sqlRaw, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(sqlRaw)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	sqlDB.Exec(t, `
+CREATE DATABASE t;
+CREATE TABLE t.t (k INT PRIMARY KEY, g STRING, x INT);
+INSERT INTO t.t VALUES (1, 'a', 1), (2, 'a', 2), (3, 'b', 3), (4, 'b', 3);
+CREATE MATERIALIZED VIEW t.v AS SELECT x, x * 2 AS y FROM t.t WHERE x > 1;
+CREATE MATERIALIZED VIEW t.agg AS SELECT g, count(*) AS c, sum(x) AS s FROM t.t GROUP BY g;
+CREATE MATERIALIZED VIEW t.total AS SELECT sum(x) AS s FROM t.t;
+`)
+
+	sqlDB.Exec(t, `
+INSERT INTO t.t VALUES (5, 'c', 5), (6, 'a', 3);
+UPDATE t.t SET x = 0 WHERE k = 2;
+UPDATE t.t SET g = 'c' WHERE k = 3;
+DELETE FROM t.t WHERE k = 4;
+`)
+	for _, tc := range []struct {
+		view, query string
+	}{
+		{`t.v`, `SELECT x, x * 2 FROM t.t WHERE x > 1`},
+		{`t.agg`, `SELECT g, count(*), sum(x) FROM t.t GROUP BY g`},
+		{`t.total`, `SELECT sum(x) FROM t.t`},
+	} {
+		sqlDB.Exec(t, `REFRESH MATERIALIZED VIEW `+tc.view+` INCREMENTAL`)
+		sqlDB.CheckQueryResults(t,
+			`SELECT * FROM `+tc.view+` ORDER BY 1, 2`, sqlDB.QueryStr(t, tc.query+` ORDER BY 1, 2`))
+	}
+	sqlDB.CheckQueryResults(t, `
+SELECT status, count(*) FROM [SHOW JOBS]
+WHERE job_type = 'MATERIALIZED VIEW REFRESH' GROUP BY status`,
+		[][]string{{"succeeded", "3"}})
+
+	// A refresh with no changes to the table leaves the view as is.
+	sqlDB.Exec(t, `REFRESH MATERIALIZED VIEW t.v INCREMENTAL`)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM t.v ORDER BY 1`, sqlDB.QueryStr(t, `SELECT x, x * 2 FROM t.t WHERE x > 1 ORDER BY 1`))
+
+	// If the primary key of the table was altered since the last refresh, the
+	// view query is evaluated in full.
+	sqlDB.Exec(t, `
+ALTER TABLE t.t ALTER PRIMARY KEY USING COLUMNS (k, g);
+INSERT INTO t.t VALUES (7, 'd', 7);
+UPDATE t.t SET x = 10 WHERE k = 1;
+`)
+	sqlDB.Exec(t, `REFRESH MATERIALIZED VIEW t.v INCREMENTAL`)
+	sqlDB.CheckQueryResults(t,
+		`SELECT * FROM t.v ORDER BY 1`, sqlDB.QueryStr(t, `SELECT x, x * 2 FROM t.t WHERE x > 1 ORDER BY 1`))
+
+	// Views which can't be maintained incrementally are rejected.
+	sqlDB.Exec(t, `CREATE MATERIALIZED VIEW t.stable AS SELECT x, now() AS n FROM t.t`)
+	sqlDB.ExpectErr(t, `materialized view cannot be refreshed incrementally: query calls non-immutable function now`,
+		`REFRESH MATERIALIZED VIEW t.stable INCREMENTAL`)
+	sqlDB.Exec(t, `CREATE MATERIALIZED VIEW t.lim AS SELECT x FROM t.t LIMIT 1`)
+	sqlDB.ExpectErr(t, `materialized view cannot be refreshed incrementally: query has a WITH, ORDER BY, LIMIT or locking clause`,
+		`REFRESH MATERIALIZED VIEW t.lim INCREMENTAL`)
+	sqlDB.Exec(t, `CREATE MATERIALIZED VIEW t.nodata AS SELECT x FROM t.t WITH NO DATA`)
+	sqlDB.ExpectErr(t, `must be fully refreshed before it can be refreshed incrementally`,
+		`REFRESH MATERIALIZED VIEW t.nodata INCREMENTAL`)
+
+	// Incremental refreshes run a job, so they can't be part of an explicit
+	// transaction.
+	sqlDB.ExpectErr(t, `cannot refresh view in a multi-statement transaction`,
+		`BEGIN; REFRESH MATERIALIZED VIEW t.v INCREMENTAL; COMMIT`)
+}
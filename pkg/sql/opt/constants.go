@@ -17,6 +17,10 @@ const DefaultJoinOrderLimit = 8
 // MaxReorderJoinsLimit is the maximum number of joins which can be reordered.
 const MaxReorderJoinsLimit = 63
 
+// DefaultMaxConstraintSpans is the default limit on the number of spans in the
+// constraint of a constrained scan.
+const DefaultMaxConstraintSpans = 10000
+
 // SaveTablesDatabase is the name of the database where tables created by
 // the saveTableNode are stored.
 const SaveTablesDatabase = "savetables"
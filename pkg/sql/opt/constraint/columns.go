@@ -23,6 +23,11 @@ import (
 // The columns have directions; a descending column inverts the order of the
 // values on that column (in other words, inverts the result of any Datum
 // comparisons on that column).
+//
+// The columns can be virtual computed columns, such as the columns that back
+// an expression index (e.g. an index on lower(s)). In that case, the key values
+// are values of the expression; see idxconstraint for how filters on the
+// underlying expression are converted into spans on such columns.
 type Columns struct {
 	// firstCol holds the first column id and otherCols hold any ids beyond the
 	// first. These are separated in order to optimize for the common case of a
@@ -11,6 +11,7 @@
 package constraint
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -62,6 +63,30 @@ func (c *Constraint) Init(keyCtx *KeyContext, spans *Spans) {
 	c.Spans.makeImmutable()
 }
 
+// Compressed returns a copy of the constraint with its spans stored in
+// prefix-compressed form, or the constraint itself if it has too few spans or
+// their keys share too few values to be worth compressing. Every call to
+// Spans.Get on the copy decompresses a span, so this should only be used for
+// constraints that are retained for a long time, such as the constraints in a
+// detached memo.
+func (c *Constraint) Compressed() *Constraint {
+	if c.Spans.Count() < compressSpansThreshold || c.Spans.compressed != nil {
+		return c
+	}
+	compressed := compressSpans(&c.Spans)
+	if compressed == nil {
+		return c
+	}
+	return &Constraint{
+		Columns: c.Columns,
+		Spans: Spans{
+			numSpans:   c.Spans.numSpans,
+			immutable:  true,
+			compressed: compressed,
+		},
+	}
+}
+
 // InitSingleSpan initializes the constraint to the columns in the key context
 // and with one span.
 func (c *Constraint) InitSingleSpan(keyCtx *KeyContext, span *Span) {
@@ -73,6 +98,46 @@ func (c *Constraint) InitSingleSpan(keyCtx *KeyContext, span *Span) {
 	c.Spans.InitSingleSpan(span)
 }
 
+// InitSingleKeySpans initializes the constraint to the columns in the key
+// context and with a single-key span for each of the given keys, for example:
+//   /1/2, /3/4  =>  [/1/2 - /1/2] [/3/4 - /3/4]
+// The keys can be in any order and can contain duplicates; they are sorted and
+// de-duplicated in place. All keys must have the same length, so that distinct
+// keys result in non-overlapping spans.
+//
+// This is equivalent to appending the spans and calling SortAndMerge and Init,
+// but it is cheaper for large numbers of keys (e.g. from tuple IN lists), since
+// it only needs to compare keys rather than span boundaries.
+func (c *Constraint) InitSingleKeySpans(keyCtx *KeyContext, keys []Key) {
+	ks := keySorter{keyCtx: keyCtx, keys: keys}
+	if !ks.sortedAndDistinct() {
+		sort.Sort(&ks)
+		n := 0
+		for i := range keys {
+			if n > 0 && ks.compare(n-1, i) == 0 {
+				continue
+			}
+			keys[n] = keys[i]
+			n++
+		}
+		keys = keys[:n]
+	}
+
+	*c = Constraint{
+		Columns: keyCtx.Columns,
+	}
+	c.Spans.Alloc(len(keys))
+	var sp Span
+	for i := range keys {
+		if i > 0 && keys[i].Length() != keys[0].Length() {
+			panic(errors.AssertionFailedf("keys must have the same length"))
+		}
+		sp.Init(keys[i], IncludeBoundary, keys[i], IncludeBoundary)
+		c.Spans.Append(&sp)
+	}
+	c.Spans.makeImmutable()
+}
+
 // IsContradiction returns true if there are no spans in the constraint.
 func (c *Constraint) IsContradiction() bool {
 	return c.Spans.Count() == 0
@@ -320,6 +385,20 @@ func (c *Constraint) IntersectsSpan(evalCtx *eval.Context, sp *Span) bool {
 	return ok
 }
 
+// ContainsDatumTuple returns true if the given tuple of values for (a prefix
+// of) the constraint columns falls inside the constrained spans. A tuple for a
+// prefix of the columns is only contained if all the tuples that extend it
+// are. See Spans.ContainsKey.
+func (c *Constraint) ContainsDatumTuple(evalCtx *eval.Context, vals tree.Datums) bool {
+	if len(vals) > c.Columns.Count() {
+		panic(errors.AssertionFailedf(
+			"tuple has %d values but constraint has %d columns", len(vals), c.Columns.Count(),
+		))
+	}
+	keyCtx := MakeKeyContext(&c.Columns, evalCtx)
+	return c.Spans.ContainsKey(&keyCtx, MakeCompositeKey(vals...))
+}
+
 // findIntersectingSpan performs binary search to find a span within
 // the constraint that overlaps sp.
 func (c *Constraint) findIntersectingSpan(keyCtx *KeyContext, sp *Span) (_ *Span, ok bool) {
@@ -344,6 +423,14 @@ func (c *Constraint) findIntersectingSpan(keyCtx *KeyContext, sp *Span) (_ *Span
 //  other:  /b: [/5 - /5]
 //  result: /a/b: [/1/5 - /2/5] [/4/5 - /4/5]
 func (c *Constraint) Combine(evalCtx *eval.Context, other *Constraint) {
+	c.CombineWithSpanBuilder(evalCtx, other, nil /* sb */)
+}
+
+// CombineWithSpanBuilder is like Combine, but it uses the given SpanBuilder to
+// allocate the combined keys. The SpanBuilder can be nil.
+func (c *Constraint) CombineWithSpanBuilder(
+	evalCtx *eval.Context, other *Constraint, sb *SpanBuilder,
+) {
 	if !other.Columns.IsStrictSuffixOf(&c.Columns) {
 		// Note: we don't want to let the c and other pointers escape by passing
 		// them directly to Sprintf.
@@ -404,8 +491,8 @@ func (c *Constraint) Combine(evalCtx *eval.Context, other *Constraint) {
 				extSp := other.Spans.Get(j)
 				var newSp Span
 				newSp.Init(
-					sp.start.Concat(extSp.start), extSp.startBoundary,
-					sp.end.Concat(extSp.end), extSp.endBoundary,
+					sb.Concat(sp.start, extSp.start), extSp.startBoundary,
+					sb.Concat(sp.end, extSp.end), extSp.endBoundary,
 				)
 				result.Append(&newSp)
 			}
@@ -429,7 +516,7 @@ func (c *Constraint) Combine(evalCtx *eval.Context, other *Constraint) {
 			//     [/2/1 - ]
 			extSp := other.Spans.Get(0)
 			if extSp.start.Length() > 0 {
-				sp.start = sp.start.Concat(extSp.start)
+				sp.start = sb.Concat(sp.start, extSp.start)
 				sp.startBoundary = extSp.startBoundary
 				modified = true
 			}
@@ -438,7 +525,7 @@ func (c *Constraint) Combine(evalCtx *eval.Context, other *Constraint) {
 		if endLen == offset && sp.endBoundary == IncludeBoundary {
 			extSp := other.Spans.Get(other.Spans.Count() - 1)
 			if extSp.end.Length() > 0 {
-				sp.end = sp.end.Concat(extSp.end)
+				sp.end = sb.Concat(sp.end, extSp.end)
 				sp.endBoundary = extSp.endBoundary
 				modified = true
 			}
@@ -473,6 +560,10 @@ func (c *Constraint) Combine(evalCtx *eval.Context, other *Constraint) {
 
 // ConsolidateSpans merges spans that have consecutive boundaries. For example:
 //   [/1 - /2] [/3 - /4] becomes [/1 - /4].
+// Boundaries that are consecutive because of the direction of the columns are
+// merged as well. For example, if the first column is ascending and the second
+// one descending (where NULLs sort last):
+//   [/1 - /1/NULL] [/2 - /3] becomes [/1 - /3].
 // An optional PrefixSorter parameter describes the localities of partitions in
 // the index for which the Constraint is being built. Spans belonging to 100%
 // local partitions will not be consolidated with spans that overlap any remote
@@ -518,8 +609,7 @@ func (c *Constraint) ConsolidateSpans(evalCtx *eval.Context, ps partition.Prefix
 		// An example query on a LOCALITY REGIONAL BY ROW table which this
 		// benefits is:
 		// SELECT * FROM regional_by_row_table WHERE pk <> 4 LIMIT 3;
-		if last.endBoundary == IncludeBoundary && sp.startBoundary == IncludeBoundary &&
-			sp.start.IsNextKey(&keyCtx, last.end) && !localRemoteCrossover {
+		if !localRemoteCrossover && spansAreAdjacent(&keyCtx, last, sp) {
 			// We only initialize `result` if we need to change something.
 			if result.Count() == 0 {
 				result.Alloc(c.Spans.Count() - 1)
@@ -543,6 +633,38 @@ func (c *Constraint) ConsolidateSpans(evalCtx *eval.Context, ps partition.Prefix
 	}
 }
 
+// spansAreAdjacent returns true if sp starts right where last ends, with no
+// possible key in between, so that the two spans can be merged. last must
+// precede sp.
+func spansAreAdjacent(keyCtx *KeyContext, last, sp *Span) bool {
+	if last.endBoundary != IncludeBoundary || sp.startBoundary != IncludeBoundary {
+		return false
+	}
+	if sp.start.IsNextKey(keyCtx, last.end) {
+		return true
+	}
+	// An inclusive end key that ends with the last value of its column (in the
+	// direction of the column) includes the same keys as the end key without
+	// that value; for example, /1/NULL includes all keys with prefix /1 if the
+	// second column is descending. Likewise, an inclusive start key that ends
+	// with the first value of its column can be shortened. The shortened keys
+	// may be consecutive even if the original ones aren't.
+	end, start := last.end, sp.start
+	for n := end.Length(); n > 0 && keyCtx.isLastValue(n-1, end.Value(n-1)); n-- {
+		end = end.CutBack(1)
+	}
+	for n := start.Length(); n > 0 && keyCtx.isFirstValue(n-1, start.Value(n-1)); n-- {
+		start = start.CutBack(1)
+	}
+	if end.IsEmpty() || start.IsEmpty() {
+		return false
+	}
+	if end.Length() == last.end.Length() && start.Length() == sp.start.Length() {
+		return false
+	}
+	return start.IsNextKey(keyCtx, end)
+}
+
 // ExactPrefix returns the length of the longest column prefix which are
 // constrained to a single value. For example:
 //   /a/b/c: [/1/2/3 - /1/2/3]                    ->  ExactPrefix = 3
@@ -669,6 +791,62 @@ func (c *Constraint) ExtractConstCols(evalCtx *eval.Context) opt.ColSet {
 	return res
 }
 
+// ColumnConstraint returns the constraint that c implies on the nth column
+// alone. The constraint can only be derived for the columns within the
+// constraint prefix (see Constraint.Prefix() for details) and for the first
+// column that follows it; ok is false for the remaining columns, since the
+// spans allow them to take on any value. For example, in this constraint:
+//   /a/b/c: [/1/1/1 - /1/1/5] [/2/3/1 - /2/3/1]
+// the constraints on the individual columns are:
+//   /a: [/1 - /1] [/2 - /2]
+//   /b: [/1 - /1] [/3 - /3]
+//   /c: [/1 - /5]
+//
+// The returned constraint may be unconstrained, but it is never a
+// contradiction unless c is.
+func (c *Constraint) ColumnConstraint(evalCtx *eval.Context, nth int) (_ Constraint, ok bool) {
+	if c.IsContradiction() {
+		return *c, true
+	}
+	prefix := c.Prefix(evalCtx)
+	if nth > prefix || nth >= c.Columns.Count() {
+		return Constraint{}, false
+	}
+
+	var cols Columns
+	cols.InitSingle(c.Columns.Get(nth))
+	keyCtx := MakeKeyContext(&cols, evalCtx)
+
+	// Within the prefix, every span constrains the column to a single value. For
+	// the first column after the prefix, the spans are cut to that column. If a
+	// span key extends past the column, the cut boundary becomes inclusive,
+	// since keys with the same value for the column can be in the span.
+	cutKey := func(key Key, boundary SpanBoundary) (Key, SpanBoundary) {
+		if key.Length() <= nth {
+			return EmptyKey, IncludeBoundary
+		}
+		if key.Length() > nth+1 {
+			boundary = IncludeBoundary
+		}
+		return MakeKey(key.Value(nth)), boundary
+	}
+	var spans Spans
+	spans.Alloc(c.Spans.Count())
+	for i := 0; i < c.Spans.Count(); i++ {
+		sp := c.Spans.Get(i)
+		var newSp Span
+		start, startBoundary := cutKey(sp.start, sp.startBoundary)
+		end, endBoundary := cutKey(sp.end, sp.endBoundary)
+		newSp.Init(start, startBoundary, end, endBoundary)
+		spans.Append(&newSp)
+	}
+	spans.SortAndMerge(&keyCtx)
+
+	var res Constraint
+	res.Init(&keyCtx, &spans)
+	return res, true
+}
+
 // ExtractNotNullCols returns a set of columns that cannot be NULL when the
 // constraint holds.
 func (c *Constraint) ExtractNotNullCols(evalCtx *eval.Context) opt.ColSet {
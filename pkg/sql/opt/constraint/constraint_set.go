@@ -179,6 +179,71 @@ func (s *Set) Intersect(evalCtx *eval.Context, other *Set) *Set {
 	return mergeSet
 }
 
+// IntersectColumnConstraints returns a set containing one single-column
+// constraint for each column that can be constrained individually by left or
+// right (see Constraint.ColumnConstraint). For columns constrained by both,
+// the constraint is the intersection of the constraints that left and right
+// imply on the column. For example:
+//   left:   /a/b: [/1/1 - /1/5] [/2/1 - /2/5]
+//   right:  /c/b: [/7/4 - /7/9]
+//   result: /a: [/1 - /1] [/2 - /2]; /b: [/4 - /5]; /c: [/7 - /7]
+//
+// This is useful when left and right were derived from the same filters for
+// two different indexes (e.g. when planning zigzag joins): each index
+// constraint may restrict columns that the other cannot, and the intersection
+// reveals column values which neither constraint restricts on its own. If
+// any column intersection is empty, Contradiction is returned.
+func IntersectColumnConstraints(evalCtx *eval.Context, left, right *Constraint) *Set {
+	if left.IsContradiction() || right.IsContradiction() {
+		return Contradiction
+	}
+	res := intersectColumnConstraints(evalCtx, Unconstrained, left)
+	return intersectColumnConstraints(evalCtx, res, right)
+}
+
+// intersectColumnConstraints intersects the given set with the constraints
+// that c implies on each of its individual columns (see
+// Constraint.ColumnConstraint), and returns the resulting set.
+func intersectColumnConstraints(evalCtx *eval.Context, s *Set, c *Constraint) *Set {
+	for i := 0; i < c.Columns.Count() && s != Contradiction; i++ {
+		colConstraint, ok := c.ColumnConstraint(evalCtx, i)
+		if !ok {
+			break
+		}
+		s = s.Intersect(evalCtx, SingleConstraint(&colConstraint))
+	}
+	return s
+}
+
+// ProvesEmpty returns true if the conjunction of the constraints in the set is
+// unsatisfiable. Constraints on the same columns are already intersected when
+// the set is built, but constraints on different (overlapping) sets of columns
+// can also contradict each other. For example:
+//   /a: [/6 - ]
+//   /a/b: [ - /3/5]
+// ProvesEmpty detects these contradictions by intersecting the constraints that
+// each constraint implies on its individual columns.
+func (s *Set) ProvesEmpty(evalCtx *eval.Context) bool {
+	if s == Contradiction {
+		return true
+	}
+	if s.Length() < 2 {
+		return s.Length() == 1 && s.Constraint(0).IsContradiction()
+	}
+	res := Unconstrained
+	for i := 0; i < s.Length(); i++ {
+		c := s.Constraint(i)
+		if c.IsContradiction() {
+			return true
+		}
+		res = intersectColumnConstraints(evalCtx, res, c)
+		if res == Contradiction {
+			return true
+		}
+	}
+	return false
+}
+
 // Union creates a new set with constraints that allow any value that either of
 // the input sets allowed. Compatible constraints (that share same column list)
 // that exist in both sets are merged with one another. Note that the results
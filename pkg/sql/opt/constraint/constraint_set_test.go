@@ -361,6 +361,86 @@ func TestExtractConstColsForSet(t *testing.T) {
 	}
 }
 
+func TestIntersectColumnConstraints(t *testing.T) {
+	testData := []struct {
+		left     string
+		right    string
+		expected string
+	}{
+		{
+			left:     "/1/2: [/1/1 - /1/5] [/2/1 - /2/5]",
+			right:    "/3/2: [/7/4 - /7/9]",
+			expected: "/1: [/1 - /1] [/2 - /2]; /2: [/4 - /5]; /3: [/7 - /7]",
+		},
+		{
+			left:     "/1/2: [/1/1 - /1/5]",
+			right:    "/2/1: [/3/1 - /3/1]",
+			expected: "/1: [/1 - /1]; /2: [/3 - /3]",
+		},
+		{
+			left:     "/1/2: [/1/1 - /1/5]",
+			right:    "/2: [/6 - ]",
+			expected: "contradiction",
+		},
+		{
+			left:     "/1/2: [/1/1 - /2/5]",
+			right:    "/3: [/1 - /1]",
+			expected: "/1: [/1 - /2]; /3: [/1 - /1]",
+		},
+		{
+			left:     "/1/2: [/1 - /1]",
+			right:    "/2: contradiction",
+			expected: "contradiction",
+		},
+		{
+			left:     "/1/2: [ - /5]",
+			right:    "/2/1: [/4 - ]",
+			expected: "/1: [ - /5]; /2: [/4 - ]",
+		},
+	}
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.NewTestingEvalContext(st)
+	for _, tc := range testData {
+		left := ParseConstraint(evalCtx, tc.left)
+		right := ParseConstraint(evalCtx, tc.right)
+		res := IntersectColumnConstraints(evalCtx, &left, &right)
+		if res.String() != tc.expected {
+			t.Errorf("%s, %s: expected %s, got %s", tc.left, tc.right, tc.expected, res.String())
+		}
+	}
+}
+
+func TestSetProvesEmpty(t *testing.T) {
+	testData := []struct {
+		constraints []string
+		expected    bool
+	}{
+		{constraints: []string{}, expected: false},
+		{constraints: []string{"/1: [/1 - /5]"}, expected: false},
+		{constraints: []string{"/1: [/6 - ]", "/1: [ - /3]"}, expected: true},
+		{constraints: []string{"/1: [/6 - ]", "/1/2: [ - /3/5]"}, expected: true},
+		{constraints: []string{"/1: [/3 - ]", "/1/2: [ - /3/5]"}, expected: false},
+		{constraints: []string{"/1/2: [/1/1 - /1/5]", "/2/3: [/6 - /6]"}, expected: true},
+		{constraints: []string{"/1/2: [/1/1 - /1/5]", "/2/3: [/5/1 - /6]"}, expected: false},
+		{constraints: []string{"/1/2: [/1/1 - /1/5]", "/3: [/1 - /1]", "/2/3: [/4/2 - /4/2]"}, expected: true},
+		{constraints: []string{"/1/2: [/1 - /2]", "/2/1: [/3/3 - /3/3]"}, expected: true},
+	}
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.NewTestingEvalContext(st)
+	for _, tc := range testData {
+		s := Unconstrained
+		for _, str := range tc.constraints {
+			c := ParseConstraint(evalCtx, str)
+			s = s.Intersect(evalCtx, SingleConstraint(&c))
+		}
+		if res := s.ProvesEmpty(evalCtx); res != tc.expected {
+			t.Errorf("%v: expected %t, got %t", tc.constraints, tc.expected, res)
+		}
+	}
+}
+
 func TestHasSingleColumnConstValues(t *testing.T) {
 	type testCase struct {
 		constraints []string
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 )
 
 func TestConstraintUnion(t *testing.T) {
@@ -409,6 +410,67 @@ func TestConstraintIntersectsSpan(t *testing.T) {
 	}
 }
 
+func TestConstraintContainsDatumTuple(t *testing.T) {
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+
+	// Each test case has a bunch of tuples that are expected to be contained,
+	// and a bunch of tuples that are expected not to be contained.
+	testData := []struct {
+		constraint   string
+		contained    []string
+		notContained []string
+	}{
+		{
+			constraint:   "/1: [/1 - /3]",
+			contained:    []string{"/1", "/2", "/3"},
+			notContained: []string{"/0", "/4"},
+		},
+		{
+			constraint:   "/1: (/1 - /3) [/5 - /5] (/7 - ]",
+			contained:    []string{"/2", "/5", "/8"},
+			notContained: []string{"/1", "/3", "/4", "/6", "/7"},
+		},
+		{
+			constraint:   "/1/2: [ - /2] [/4 - /4] [/5/3 - /7) [/9 - /9/20]",
+			contained:    []string{"/1/1", "/2/5", "/4", "/4/1", "/5/3", "/6/1", "/9/20"},
+			notContained: []string{"/3/1", "/5/2", "/7", "/7/1", "/9/21", "/10"},
+		},
+		{
+			constraint:   "/1/-2: [/1/5 - /1/2] [/3/5 - /5/2] [/7 - ]",
+			contained:    []string{"/1/5", "/1/3", "/3/5", "/4/10", "/5/2", "/7/1"},
+			notContained: []string{"/1/6", "/1/1", "/3/6", "/5/1", "/6/1"},
+		},
+		{
+			constraint:   "/1: contradiction",
+			notContained: []string{"/1"},
+		},
+	}
+
+	parseTuple := func(str string) tree.Datums {
+		var vals tree.Datums
+		for _, v := range parseIntPath(str) {
+			vals = append(vals, tree.NewDInt(tree.DInt(v)))
+		}
+		return vals
+	}
+	for i, tc := range testData {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			c := ParseConstraint(&evalCtx, tc.constraint)
+			for _, tuple := range tc.contained {
+				if !c.ContainsDatumTuple(&evalCtx, parseTuple(tuple)) {
+					t.Errorf("%s should contain tuple %s", c, tuple)
+				}
+			}
+			for _, tuple := range tc.notContained {
+				if c.ContainsDatumTuple(&evalCtx, parseTuple(tuple)) {
+					t.Errorf("%s should not contain tuple %s", c, tuple)
+				}
+			}
+		})
+	}
+}
+
 func TestConstraintCombine(t *testing.T) {
 	st := cluster.MakeTestingClusterSettings()
 	evalCtx := eval.MakeTestingEvalContext(st)
@@ -460,6 +522,81 @@ func TestConstraintCombine(t *testing.T) {
 	}
 }
 
+func TestConstraintInitSingleKeySpans(t *testing.T) {
+	testData := []struct {
+		cols []opt.OrderingColumn
+		keys [][]int
+		e    string
+	}{
+		{
+			cols: []opt.OrderingColumn{1, 2},
+			keys: nil,
+			e:    "/1/2: contradiction",
+		},
+		{
+			cols: []opt.OrderingColumn{1, 2},
+			keys: [][]int{{1, 2}, {3, 4}},
+			e:    "/1/2: [/1/2 - /1/2] [/3/4 - /3/4]",
+		},
+		{
+			cols: []opt.OrderingColumn{1, 2},
+			keys: [][]int{{3, 4}, {1, 2}, {3, 4}, {1, 3}},
+			e:    "/1/2: [/1/2 - /1/2] [/1/3 - /1/3] [/3/4 - /3/4]",
+		},
+		{
+			cols: []opt.OrderingColumn{1, -2},
+			keys: [][]int{{1, 2}, {1, 3}, {1, 2}, {0, 5}},
+			e:    "/1/-2: [/0/5 - /0/5] [/1/3 - /1/3] [/1/2 - /1/2]",
+		},
+	}
+
+	for i, tc := range testData {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			keyCtx := testKeyContext(tc.cols...)
+			keys := make([]Key, len(tc.keys))
+			for j, vals := range tc.keys {
+				datums := make(tree.Datums, len(vals))
+				for k, v := range vals {
+					datums[k] = tree.NewDInt(tree.DInt(v))
+				}
+				keys[j] = MakeCompositeKey(datums...)
+			}
+			var c Constraint
+			c.InitSingleKeySpans(keyCtx, keys)
+			if s := c.String(); s != tc.e {
+				t.Errorf("expected\n  %s; got\n  %s", tc.e, s)
+			}
+		})
+	}
+
+	// The result must match appending the spans and calling SortAndMerge. We
+	// generate random cases and cross-check.
+	rng, _ := randutil.NewTestRand()
+	keyCtx := testKeyContext(1, -2)
+	for testIdx := 0; testIdx < 100; testIdx++ {
+		n := rng.Intn(20)
+		keys := make([]Key, n)
+		var spans Spans
+		for i := range keys {
+			keys[i] = MakeCompositeKey(
+				tree.NewDInt(tree.DInt(rng.Intn(5))), tree.NewDInt(tree.DInt(rng.Intn(5))),
+			)
+			var sp Span
+			sp.Init(keys[i], IncludeBoundary, keys[i], IncludeBoundary)
+			spans.Append(&sp)
+		}
+		spans.SortAndMerge(keyCtx)
+		var expected Constraint
+		expected.Init(keyCtx, &spans)
+
+		var c Constraint
+		c.InitSingleKeySpans(keyCtx, keys)
+		if actual := c.String(); actual != expected.String() {
+			t.Fatalf("expected  %s  got  %s", expected.String(), actual)
+		}
+	}
+}
+
 func TestConsolidateSpans(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	st := cluster.MakeTestingClusterSettings()
@@ -508,6 +645,36 @@ func TestConsolidateSpans(t *testing.T) {
 			s: "[/1 - /2] [/3 - /5)",
 			e: "[/1 - /5)",
 		},
+		{
+			// NULLs sort last on the descending third column.
+			s: "[/1/1/5 - /1/1/NULL] [/1/2 - /1/3]",
+			e: "[/1/1/5 - /1/3]",
+		},
+		{
+			s: "[/1/1 - /1/2/NULL] [/1/3 - /1/4]",
+			e: "[/1/1 - /1/4]",
+		},
+		{
+			s: "[/1/1/5 - /1/1/NULL] [/1/3 - /1/4]",
+			e: "[/1/1/5 - /1/1/NULL] [/1/3 - /1/4]",
+		},
+		{
+			// NULLs sort first on the ascending second column.
+			s: "[/1 - /2] [/3/NULL - /4]",
+			e: "[/1 - /4]",
+		},
+		{
+			s: "[/1/1 - /1/NULL] [/2 - /3]",
+			e: "[/1/1 - /1/NULL] [/2 - /3]",
+		},
+		{
+			s: "[/1 - /1/9223372036854775807] [/2 - /3]",
+			e: "[/1 - /3]",
+		},
+		{
+			s: "[/1/1 - /1/9223372036854775807/NULL] [/2/NULL/9223372036854775807 - /3]",
+			e: "[/1/1 - /3]",
+		},
 	}
 
 	kc := testKeyContext(1, 2, -3)
@@ -924,6 +1091,90 @@ func TestExtractConstCols(t *testing.T) {
 	}
 }
 
+func TestColumnConstraint(t *testing.T) {
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+
+	testData := []struct {
+		c   string
+		nth int
+		// expected value; empty if the column can't be constrained individually.
+		e string
+	}{
+		{ // 0
+			c:   "/1/2: contradiction",
+			nth: 1,
+			e:   "/1/2: contradiction",
+		},
+		{ // 1
+			c:   "/1/2/3: [/1/1/1 - /1/1/5] [/2/3/1 - /2/3/1]",
+			nth: 0,
+			e:   "/1: [/1 - /1] [/2 - /2]",
+		},
+		{ // 2
+			c:   "/1/2/3: [/1/1/1 - /1/1/5] [/2/3/1 - /2/3/1]",
+			nth: 1,
+			e:   "/2: [/1 - /1] [/3 - /3]",
+		},
+		{ // 3
+			c:   "/1/2/3: [/1/1/1 - /1/1/5] [/2/3/1 - /2/3/1]",
+			nth: 2,
+			e:   "/3: [/1 - /5]",
+		},
+		{ // 4
+			c:   "/1/2: [/1/1 - /2/5]",
+			nth: 0,
+			e:   "/1: [/1 - /2]",
+		},
+		{ // 5
+			c:   "/1/2: [/1/1 - /2/5]",
+			nth: 1,
+			e:   "",
+		},
+		{ // 6
+			c:   "/1/2: (/1/1 - /1/5)",
+			nth: 1,
+			e:   "/2: (/1 - /5)",
+		},
+		{ // 7
+			c:   "/1/2: (/1/1 - /3)",
+			nth: 0,
+			e:   "/1: [/1 - /3)",
+		},
+		{ // 8
+			c:   "/1/-2: [/1/5 - /1/1] [/2/7 - /2/3]",
+			nth: 1,
+			e:   "/-2: [/7 - /1]",
+		},
+		{ // 9
+			c:   "/1/2: [/1 - /1] [/2/3 - /2/3]",
+			nth: 1,
+			e:   "/2: unconstrained",
+		},
+		{ // 10
+			c:   "/1/2/3: [/1/1 - /1/1]",
+			nth: 2,
+			e:   "/3: unconstrained",
+		},
+	}
+
+	for i, tc := range testData {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			c := ParseConstraint(&evalCtx, tc.c)
+			res, ok := c.ColumnConstraint(&evalCtx, tc.nth)
+			if !ok {
+				if tc.e != "" {
+					t.Errorf("expected %s; got no constraint", tc.e)
+				}
+				return
+			}
+			if res.String() != tc.e {
+				t.Errorf("expected %s; got %s", tc.e, res.String())
+			}
+		})
+	}
+}
+
 func TestExtractNotNullCols(t *testing.T) {
 	st := cluster.MakeTestingClusterSettings()
 	evalCtx := eval.MakeTestingEvalContext(st)
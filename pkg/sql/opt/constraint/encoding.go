@@ -0,0 +1,214 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package constraint
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements a stable binary encoding for keys, spans, and
+// constraints, so that computed constraints can be stored alongside cached
+// query plans and in statement bundles. The encoding is as follows:
+//
+//   Key       : <uvarint num values> <value>...
+//   Span      : <boundary flags byte> <start Key> <end Key>
+//   Constraint: <version byte> <uvarint num columns> <varint column>...
+//               <uvarint num spans> <Span>...
+//
+// Key values are value-encoded (see valueside.Encode) without a column ID.
+// Since the value encoding does not contain the full type of a datum, the
+// types of the constrained columns must be supplied when decoding.
+
+// encodingVersion is the version of the constraint encoding. It must be
+// incremented whenever the encoding changes in a way that is not backward
+// compatible.
+const encodingVersion = 1
+
+const (
+	// startExcludedFlag is set in the boundary flags of an encoded span if its
+	// start boundary is exclusive.
+	startExcludedFlag = 1 << 0
+
+	// endExcludedFlag is set in the boundary flags of an encoded span if its end
+	// boundary is exclusive.
+	endExcludedFlag = 1 << 1
+)
+
+// MarshalTo appends the encoding of the key to appendTo and returns the
+// resulting buffer.
+func (k Key) MarshalTo(appendTo []byte) ([]byte, error) {
+	appendTo = encoding.EncodeUvarintAscending(appendTo, uint64(k.Length()))
+	for i := 0; i < k.Length(); i++ {
+		var err error
+		appendTo, err = valueside.Encode(appendTo, valueside.NoColumnID, k.Value(i), nil /* scratch */)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return appendTo, nil
+}
+
+// UnmarshalKey decodes a key encoded by Key.MarshalTo from the front of the
+// given buffer, and returns the key and the remainder of the buffer. The i-th
+// value of the key is decoded as typs[i].
+func UnmarshalKey(a *tree.DatumAlloc, typs []*types.T, b []byte) (Key, []byte, error) {
+	b, n, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return Key{}, nil, err
+	}
+	if n > uint64(len(typs)) {
+		return Key{}, nil, errors.AssertionFailedf(
+			"key has %d values but only %d types were provided", n, len(typs),
+		)
+	}
+	if n == 0 {
+		return EmptyKey, b, nil
+	}
+	vals := make(tree.Datums, n)
+	for i := range vals {
+		vals[i], b, err = valueside.Decode(a, typs[i], b)
+		if err != nil {
+			return Key{}, nil, err
+		}
+	}
+	return MakeCompositeKey(vals...), b, nil
+}
+
+// MarshalTo appends the encoding of the span to appendTo and returns the
+// resulting buffer.
+func (sp *Span) MarshalTo(appendTo []byte) ([]byte, error) {
+	var flags byte
+	if sp.startBoundary == ExcludeBoundary {
+		flags |= startExcludedFlag
+	}
+	if sp.endBoundary == ExcludeBoundary {
+		flags |= endExcludedFlag
+	}
+	appendTo = append(appendTo, flags)
+	appendTo, err := sp.start.MarshalTo(appendTo)
+	if err != nil {
+		return nil, err
+	}
+	return sp.end.MarshalTo(appendTo)
+}
+
+// UnmarshalSpan decodes a span encoded by Span.MarshalTo from the front of the
+// given buffer, and returns the span and the remainder of the buffer. See
+// UnmarshalKey for the meaning of typs.
+func UnmarshalSpan(a *tree.DatumAlloc, typs []*types.T, b []byte) (Span, []byte, error) {
+	if len(b) == 0 {
+		return Span{}, nil, errors.AssertionFailedf("insufficient bytes to decode span")
+	}
+	flags := b[0]
+	if flags&^(startExcludedFlag|endExcludedFlag) != 0 {
+		return Span{}, nil, errors.AssertionFailedf("invalid span boundary flags %x", flags)
+	}
+	start, b, err := UnmarshalKey(a, typs, b[1:])
+	if err != nil {
+		return Span{}, nil, err
+	}
+	end, b, err := UnmarshalKey(a, typs, b)
+	if err != nil {
+		return Span{}, nil, err
+	}
+	sp := Span{
+		start:         start,
+		startBoundary: SpanBoundary(flags&startExcludedFlag != 0),
+		end:           end,
+		endBoundary:   SpanBoundary(flags&endExcludedFlag != 0),
+	}
+	if (start.IsEmpty() && sp.startBoundary == ExcludeBoundary) ||
+		(end.IsEmpty() && sp.endBoundary == ExcludeBoundary) {
+		return Span{}, nil, errors.AssertionFailedf("an empty boundary must be inclusive")
+	}
+	return sp, b, nil
+}
+
+// Marshal returns the encoding of the constraint.
+func (c *Constraint) Marshal() ([]byte, error) {
+	b := []byte{encodingVersion}
+	b = encoding.EncodeUvarintAscending(b, uint64(c.Columns.Count()))
+	for i := 0; i < c.Columns.Count(); i++ {
+		b = encoding.EncodeVarintAscending(b, int64(c.Columns.Get(i)))
+	}
+	b = encoding.EncodeUvarintAscending(b, uint64(c.Spans.Count()))
+	for i := 0; i < c.Spans.Count(); i++ {
+		var err error
+		b, err = c.Spans.Get(i).MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Unmarshal initializes the constraint from an encoding returned by
+// Constraint.Marshal. The i-th value of each span key is decoded as typs[i],
+// so typs must contain the types of the constrained columns, in order.
+func (c *Constraint) Unmarshal(a *tree.DatumAlloc, typs []*types.T, b []byte) error {
+	if len(b) == 0 {
+		return errors.AssertionFailedf("insufficient bytes to decode constraint")
+	}
+	if b[0] != encodingVersion {
+		return errors.AssertionFailedf("unsupported constraint encoding version %d", b[0])
+	}
+	b, numCols, err := encoding.DecodeUvarintAscending(b[1:])
+	if err != nil {
+		return err
+	}
+	if numCols == 0 || numCols > uint64(len(b)) {
+		// Every encoded column takes up at least one byte.
+		return errors.AssertionFailedf("invalid number of columns %d", numCols)
+	}
+	cols := make([]opt.OrderingColumn, numCols)
+	for i := range cols {
+		var col int64
+		b, col, err = encoding.DecodeVarintAscending(b)
+		if err != nil {
+			return err
+		}
+		cols[i] = opt.OrderingColumn(col)
+	}
+	b, numSpans, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return err
+	}
+	if numSpans > uint64(len(b)) {
+		// Every encoded span takes up at least one byte.
+		return errors.AssertionFailedf("invalid number of spans %d", numSpans)
+	}
+	var spans Spans
+	spans.Alloc(int(numSpans))
+	for i := uint64(0); i < numSpans; i++ {
+		var sp Span
+		sp, b, err = UnmarshalSpan(a, typs, b)
+		if err != nil {
+			return err
+		}
+		spans.Append(&sp)
+	}
+	if len(b) != 0 {
+		return errors.AssertionFailedf("%d trailing bytes after constraint", len(b))
+	}
+
+	// This initialization pattern ensures that fields are not unwittingly
+	// reused. Field reuse must be explicit.
+	*c = Constraint{}
+	c.Columns.Init(cols)
+	c.Spans = spans
+	c.Spans.makeImmutable()
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package constraint
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestConstraintMarshal(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+
+	intTyps := []*types.T{types.Int, types.Int, types.Int}
+	testCases := []struct {
+		constraint string
+		typs       []*types.T
+	}{
+		{constraint: "/1: [/1 - /1]", typs: intTyps},
+		{constraint: "/1: [ - /5) (/10 - ]", typs: intTyps},
+		{constraint: "/1: [/NULL - /NULL] [/1 - /2]", typs: intTyps},
+		{constraint: "/1: contradiction", typs: intTyps},
+		{constraint: "/1: unconstrained", typs: intTyps},
+		{constraint: "/1/-2/3: [/1/2 - /1/2/3) (/4 - ]", typs: intTyps},
+		{constraint: "/1/2: [/'apple' - /'banana'/1]", typs: []*types.T{types.String, types.Int}},
+	}
+
+	for _, tc := range testCases {
+		c := ParseConstraint(&evalCtx, tc.constraint)
+		b, err := c.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res Constraint
+		if err := res.Unmarshal(&tree.DatumAlloc{}, tc.typs, b); err != nil {
+			t.Fatalf("%s: %v", tc.constraint, err)
+		}
+		if actual := res.String(); actual != c.String() {
+			t.Errorf("expected %s, got %s", c.String(), actual)
+		}
+
+		// Decoding truncated encodings must fail rather than panic.
+		for i := 0; i < len(b); i++ {
+			if err := res.Unmarshal(&tree.DatumAlloc{}, tc.typs, b[:i]); err == nil {
+				t.Errorf("%s: expected error decoding %d of %d bytes", tc.constraint, i, len(b))
+			}
+		}
+	}
+}
+
+func TestSpanMarshal(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	keyCtx := testKeyContext(1, 2)
+
+	var sp Span
+	sp.Init(
+		MakeCompositeKey(tree.NewDString("apple"), tree.NewDFloat(1.5)), ExcludeBoundary,
+		MakeCompositeKey(tree.NewDString("cherry"), tree.DNull), IncludeBoundary,
+	)
+	b, err := sp.MarshalTo([]byte("prefix"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = append(b, "suffix"...)
+
+	typs := []*types.T{types.String, types.Float}
+	res, rest, err := UnmarshalSpan(&tree.DatumAlloc{}, typs, b[len("prefix"):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Compare(keyCtx, &sp) != 0 {
+		t.Errorf("expected %s, got %s", sp, res)
+	}
+	if string(rest) != "suffix" {
+		t.Errorf("expected remaining bytes %q, got %q", "suffix", rest)
+	}
+
+	// Decoding with too few types must fail.
+	if _, _, err := UnmarshalSpan(&tree.DatumAlloc{}, typs[:1], b[len("prefix"):]); err == nil {
+		t.Errorf("expected error")
+	}
+
+	// Keys with no values round-trip to the empty key.
+	k, rest, err := UnmarshalKey(&tree.DatumAlloc{}, nil /* typs */, []byte{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !k.IsEmpty() || len(rest) != 0 {
+		t.Errorf("expected empty key, got %s", k)
+	}
+}
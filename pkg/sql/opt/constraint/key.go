@@ -290,7 +290,11 @@ func (k Key) String() string {
 	return buf.String()
 }
 
-// KeyContext contains the necessary metadata for comparing Keys.
+// KeyContext contains the necessary metadata for comparing Keys. The Columns
+// determine the direction in which the values of each column sort, so the same
+// pair of keys or spans can compare differently under two KeyContexts. See
+// constraint/testutils for helpers that build KeyContexts and spans from
+// strings in tests.
 type KeyContext struct {
 	Columns Columns
 	EvalCtx *eval.Context
@@ -345,3 +349,22 @@ func (c *KeyContext) Prev(colIdx int, val tree.Datum) (_ tree.Datum, ok bool) {
 	}
 	return val.Next(c.EvalCtx)
 }
+
+// isFirstValue returns true if no value sorts before the given value on a
+// given column, in the direction of the column. NULLs sort first on ascending
+// columns and last on descending columns.
+func (c *KeyContext) isFirstValue(colIdx int, val tree.Datum) bool {
+	if c.Columns.Get(colIdx).Ascending() {
+		return val == tree.DNull
+	}
+	return val != tree.DNull && val.IsMax(c.EvalCtx)
+}
+
+// isLastValue returns true if no value sorts after the given value on a given
+// column, in the direction of the column.
+func (c *KeyContext) isLastValue(colIdx int, val tree.Datum) bool {
+	if c.Columns.Get(colIdx).Ascending() {
+		return val != tree.DNull && val.IsMax(c.EvalCtx)
+	}
+	return val == tree.DNull
+}
@@ -138,6 +138,11 @@ func TestKeyNextPrev(t *testing.T) {
 	kcDesc := testKeyContext(-1)
 	kcAscDesc := testKeyContext(1, -2)
 
+	collatedFoo, err := tree.NewDCollatedString("foo", "en", &tree.CollationEnvironment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	testCases := []struct {
 		key     Key
 		keyCtx  *KeyContext
@@ -198,6 +203,21 @@ func TestKeyNextPrev(t *testing.T) {
 			expNext: "/1/1",
 			expPrev: "/1/3",
 		},
+		// Appending to a collated string doesn't necessarily change its collation
+		// key, so collated strings have no next or previous value, and spans on
+		// them keep exclusive boundaries.
+		{ // 9
+			key:     MakeCompositeKey(tree.NewDInt(1), collatedFoo),
+			keyCtx:  kcAscAsc,
+			expNext: "FAIL",
+			expPrev: "FAIL",
+		},
+		{ // 10
+			key:     MakeCompositeKey(tree.NewDInt(1), collatedFoo),
+			keyCtx:  kcAscDesc,
+			expNext: "FAIL",
+			expPrev: "FAIL",
+		},
 	}
 
 	for i, tc := range testCases {
@@ -213,6 +233,9 @@ func TestKeyNextPrev(t *testing.T) {
 			if res := toStr(key, ok); res != tc.expNext {
 				t.Errorf("Next(%s) = %s, expected %s", tc.key, res, tc.expNext)
 			}
+			if ok && key.Compare(tc.keyCtx, tc.key, ExtendLow, ExtendLow) <= 0 {
+				t.Errorf("Next(%s) = %s does not sort after the key", tc.key, key)
+			}
 			key, ok = tc.key.Prev(tc.keyCtx)
 			if res := toStr(key, ok); res != tc.expPrev {
 				t.Errorf("Prev(%s) = %s, expected %s", tc.key, res, tc.expPrev)
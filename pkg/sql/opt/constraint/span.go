@@ -299,6 +299,49 @@ func (sp *Span) TryUnionWith(keyCtx *KeyContext, other *Span) bool {
 	return true
 }
 
+// TrySubtract removes the range covered by the given span from this span. The
+// difference of two spans can consist of zero, one, or two spans, for example:
+//   [/1 - /10] MINUS [/5 - /15]  =  [/1 - /5)
+//   [/1 - /10] MINUS [/3 - /5]   =  [/1 - /3) (/5 - /10]
+//   [/1 - /10] MINUS [ - ]       =  <empty>
+//
+// If the difference is empty, then this span is not updated and TrySubtract
+// returns ok=false. Otherwise, this span is updated to the first remaining
+// span. If a second span remains (because the given span is strictly inside
+// this span), it is returned as rest, and hasRest is true.
+func (sp *Span) TrySubtract(keyCtx *KeyContext, other *Span) (rest Span, hasRest, ok bool) {
+	if sp.StartsAfter(keyCtx, other) || other.StartsAfter(keyCtx, sp) {
+		// The spans don't overlap, so there is nothing to subtract.
+		return Span{}, false, true
+	}
+
+	// There is a remaining span to the left of the given span if this span
+	// starts before it, and a remaining span to the right if this span ends
+	// after it. Note that the given span's start (end) key can only be empty if
+	// there is no remaining span to its left (right), since an empty start key
+	// sorts before, and an empty end key after, all other keys.
+	hasLeft := sp.CompareStarts(keyCtx, other) < 0
+	hasRight := sp.CompareEnds(keyCtx, other) > 0
+	if !hasLeft && !hasRight {
+		return Span{}, false, false
+	}
+	if hasRight {
+		rest = Span{
+			start:         other.end,
+			startBoundary: !other.endBoundary,
+			end:           sp.end,
+			endBoundary:   sp.endBoundary,
+		}
+	}
+	if !hasLeft {
+		*sp = rest
+		return Span{}, false, true
+	}
+	sp.end = other.start
+	sp.endBoundary = !other.startBoundary
+	return rest, hasRight, true
+}
+
 // PreferInclusive tries to convert exclusive keys to inclusive keys. This is
 // only possible if the relevant type supports Next/Prev.
 //
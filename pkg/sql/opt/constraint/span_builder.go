@@ -0,0 +1,106 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package constraint
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// spanBuilderAllocSize is the number of datums that a SpanBuilder allocates at
+// a time for the values of composite keys.
+const spanBuilderAllocSize = 256
+
+var spanBuilderPool = sync.Pool{
+	New: func() interface{} {
+		return &SpanBuilder{}
+	},
+}
+
+// SpanBuilder constructs composite keys (and thus spans) while amortizing the
+// allocation of the datum slices that back them. Rather than allocating a new
+// slice for every key, it carves the slices out of larger chunks of datums.
+// This matters when constructing constraints for filters with large IN lists,
+// where allocating a slice per key would dominate planning time.
+//
+// Keys are immutable and can outlive the SpanBuilder that created them (for
+// example, as part of a constraint stored in the memo), so a chunk is never
+// reused once any part of it has been handed out. Only the unused remainder of
+// the current chunk is retained when the builder is returned to the pool.
+//
+// A nil *SpanBuilder is valid, and allocates each key separately.
+type SpanBuilder struct {
+	datumAlloc tree.Datums
+}
+
+// NewSpanBuilder returns a SpanBuilder from a pool. Release must be called
+// once the caller is done constructing keys.
+func NewSpanBuilder() *SpanBuilder {
+	return spanBuilderPool.Get().(*SpanBuilder)
+}
+
+// Release returns the SpanBuilder to the pool. Keys constructed by the builder
+// remain valid. The builder must not be used after it is released.
+func (b *SpanBuilder) Release() {
+	spanBuilderPool.Put(b)
+}
+
+// MakeCompositeKey constructs an N-dimensional key from the given values, like
+// the MakeCompositeKey function. Unlike that function, the values are copied,
+// so the caller is free to reuse the vals slice.
+func (b *SpanBuilder) MakeCompositeKey(vals ...tree.Datum) Key {
+	switch len(vals) {
+	case 0:
+		return Key{}
+	case 1:
+		return Key{firstVal: vals[0]}
+	}
+	otherVals := b.allocDatums(len(vals) - 1)
+	copy(otherVals, vals[1:])
+	return Key{firstVal: vals[0], otherVals: otherVals}
+}
+
+// Concat creates a new composite key by extending the values of k with the
+// values of l, like Key.Concat.
+func (b *SpanBuilder) Concat(k, l Key) Key {
+	klen := k.Length()
+	llen := l.Length()
+
+	if klen == 0 {
+		return l
+	}
+	if llen == 0 {
+		return k
+	}
+
+	vals := b.allocDatums(klen + llen - 1)
+	copy(vals, k.otherVals)
+	vals[klen-1] = l.firstVal
+	copy(vals[klen:], l.otherVals)
+	return Key{firstVal: k.firstVal, otherVals: vals}
+}
+
+// allocDatums returns a slice of n datums. The capacity of the slice is
+// limited to n, so that appending to it cannot overwrite datums which are
+// handed out later.
+func (b *SpanBuilder) allocDatums(n int) tree.Datums {
+	if b == nil || n > spanBuilderAllocSize/4 {
+		// Don't waste chunk space on large keys.
+		return make(tree.Datums, n)
+	}
+	if len(b.datumAlloc) < n {
+		b.datumAlloc = make(tree.Datums, spanBuilderAllocSize)
+	}
+	res := b.datumAlloc[:n:n]
+	b.datumAlloc = b.datumAlloc[n:]
+	return res
+}
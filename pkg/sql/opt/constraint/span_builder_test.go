@@ -0,0 +1,70 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package constraint
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestSpanBuilder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	keyCtx := testKeyContext(1, 2, 3, 4)
+
+	test := func(t *testing.T, sb *SpanBuilder) {
+		// Construct many keys using a single scratch slice, and make sure that
+		// none of them are clobbered by later keys.
+		const numKeys = 1000
+		keys := make([]Key, numKeys)
+		vals := make(tree.Datums, 3)
+		for i := range keys {
+			for j := range vals {
+				vals[j] = tree.NewDInt(tree.DInt(i*10 + j))
+			}
+			keys[i] = sb.MakeCompositeKey(vals...)
+		}
+		for i := range keys {
+			expected := MakeCompositeKey(
+				tree.NewDInt(tree.DInt(i*10)),
+				tree.NewDInt(tree.DInt(i*10+1)),
+				tree.NewDInt(tree.DInt(i*10+2)),
+			)
+			if keys[i].Compare(keyCtx, expected, ExtendLow, ExtendLow) != 0 {
+				t.Fatalf("expected %s, got %s", expected, keys[i])
+			}
+		}
+
+		k := sb.Concat(keys[0], MakeKey(tree.NewDInt(100)))
+		if actual := k.String(); actual != "/0/1/2/100" {
+			t.Errorf("expected /0/1/2/100, got %s", actual)
+		}
+		if actual := keys[1].String(); actual != "/10/11/12" {
+			t.Errorf("Concat clobbered an existing key: %s", actual)
+		}
+		if actual := sb.Concat(EmptyKey, keys[2]).String(); actual != "/20/21/22" {
+			t.Errorf("expected /20/21/22, got %s", actual)
+		}
+		if actual := sb.MakeCompositeKey(); !actual.IsEmpty() {
+			t.Errorf("expected empty key, got %s", actual)
+		}
+	}
+
+	t.Run("pooled", func(t *testing.T) {
+		sb := NewSpanBuilder()
+		defer sb.Release()
+		test(t, sb)
+	})
+	t.Run("nil", func(t *testing.T) {
+		test(t, nil /* sb */)
+	})
+}
@@ -611,6 +611,76 @@ func TestSpanUnion(t *testing.T) {
 	testUnion(Span{}, banana, "[ - ]")
 }
 
+func TestSpanSubtract(t *testing.T) {
+	keyCtx := testKeyContext(1, 2)
+	evalCtx := keyCtx.EvalCtx
+
+	testSubtract := func(left, right, expected string) {
+		t.Helper()
+		sp := ParseSpan(evalCtx, left)
+		other := ParseSpan(evalCtx, right)
+		rest, hasRest, ok := sp.TrySubtract(keyCtx, &other)
+
+		var actual string
+		if ok {
+			actual = sp.String()
+			if hasRest {
+				actual += " " + rest.String()
+			}
+		}
+
+		if actual != expected {
+			format := "left: %s, right: %s, expected: %v, actual: %v"
+			t.Errorf(format, left, right, expected, actual)
+		}
+	}
+
+	// Same span.
+	testSubtract("[/1 - /10]", "[/1 - /10]", "")
+
+	// Disjoint spans.
+	testSubtract("[/1 - /10]", "[/20 - /30]", "[/1 - /10]")
+	testSubtract("[/20 - /30]", "[/1 - /10]", "[/20 - /30]")
+	testSubtract("[/1 - /10)", "[/10 - /30]", "[/1 - /10)")
+	testSubtract("(/10 - /30]", "[/1 - /10]", "(/10 - /30]")
+
+	// Partial overlap.
+	testSubtract("[/1 - /10]", "[/5 - /15]", "[/1 - /5)")
+	testSubtract("[/1 - /10]", "(/5 - /15]", "[/1 - /5]")
+	testSubtract("[/5 - /15]", "[/1 - /10]", "(/10 - /15]")
+	testSubtract("[/5 - /15]", "[/1 - /10)", "[/10 - /15]")
+	testSubtract("[/1 - /10]", "[/10 - /15]", "[/1 - /10)")
+
+	// One span is a subset of the other.
+	testSubtract("[/1 - /10]", "[/3 - /5]", "[/1 - /3) (/5 - /10]")
+	testSubtract("[/1 - /10]", "(/3 - /5)", "[/1 - /3] [/5 - /10]")
+	testSubtract("[/1 - /10]", "[/1 - /5]", "(/5 - /10]")
+	testSubtract("[/1 - /10]", "[/5 - /10]", "[/1 - /5)")
+	testSubtract("[/3 - /5]", "[/1 - /10]", "")
+
+	// Unconstrained spans.
+	testSubtract("[ - ]", "[/3 - /5]", "[ - /3) (/5 - ]")
+	testSubtract("[ - ]", "[ - /5]", "(/5 - ]")
+	testSubtract("[ - ]", "[/5 - ]", "[ - /5)")
+	testSubtract("[/1 - /10]", "[ - ]", "")
+
+	// Multi-column keys.
+	testSubtract("[/1 - /2]", "[/1/5 - /1/10]", "[/1 - /1/5) (/1/10 - /2]")
+	testSubtract("[/1/5 - /1/10]", "[/1 - /1]", "")
+	testSubtract("[/1/5 - /2/10]", "[/1 - /1]", "(/1 - /2/10]")
+
+	// Ensure that if TrySubtract results in an empty set, that it does not
+	// update the span.
+	sp := ParseSpan(evalCtx, "[/3 - /5]")
+	other := ParseSpan(evalCtx, "[/1 - /10]")
+	if _, _, ok := sp.TrySubtract(keyCtx, &other); ok {
+		t.Errorf("expected empty difference")
+	}
+	if sp.String() != "[/3 - /5]" {
+		t.Errorf("span was incorrectly updated during TrySubtract: %s", sp)
+	}
+}
+
 func TestSpanPreferInclusive(t *testing.T) {
 	keyCtx := testKeyContext(1, 2)
 
@@ -11,6 +11,7 @@
 package constraint
 
 import (
+	"container/heap"
 	"sort"
 	"strings"
 
@@ -28,6 +29,11 @@ type Spans struct {
 	otherSpans []Span
 	numSpans   int32
 	immutable  bool
+
+	// compressed, if non-nil, holds the spans in prefix-compressed form, in
+	// which case firstSpan and otherSpans are unused. Only immutable spans are
+	// compressed, and only on request. See Constraint.Compressed.
+	compressed *compressedSpans
 }
 
 // Alloc allocates enough space to support the given amount of spans without
@@ -56,8 +62,12 @@ func (s *Spans) Count() int {
 	return int(s.numSpans)
 }
 
-// Get returns the nth span.
+// Get returns the nth span. If the spans are immutable, the returned span
+// must not be modified.
 func (s *Spans) Get(nth int) *Span {
+	if s.compressed != nil {
+		return s.compressed.get(nth)
+	}
 	if nth == 0 && s.numSpans > 0 {
 		return &s.firstSpan
 	}
@@ -151,6 +161,181 @@ func (s *Spans) SortAndMerge(keyCtx *KeyContext) {
 	s.Truncate(n + 1)
 }
 
+// IsSortedAndMerged returns true if the spans are sorted in ascending order
+// and no spans overlap, which is the case for the spans of a Constraint.
+func (s *Spans) IsSortedAndMerged(keyCtx *KeyContext) bool {
+	return s.sortedAndMerged(keyCtx)
+}
+
+// ContainsKey returns true if the given key falls inside one of the spans. The
+// spans must be sorted and merged (see SortAndMerge), which allows the lookup
+// to use binary search. A key with fewer values than the span keys stands for
+// all the keys that extend it, i.e. for the span [key/Low - key/High], and is
+// only contained if all of those keys are. In turn, a span boundary with fewer
+// values than the key is extended according to whether it is inclusive. For
+// example:
+//   [/1 - /5) (/10 - /20] contains /1, /4/7 and /15, but not /5 or /10
+//   [/1/3 - /2] contains /1/5 and /2/7, but not /1
+//   [/1 - /2/3) contains /1 and /2/2, but not /2
+func (s *Spans) ContainsKey(keyCtx *KeyContext, key Key) bool {
+	// Find the first span that doesn't end before the end of the key's
+	// extension. Spans are ordered and non-overlapping, so it is the only span
+	// that can contain the whole extension.
+	i := sort.Search(s.Count(), func(i int) bool {
+		sp := s.Get(i)
+		return key.Compare(keyCtx, sp.end, ExtendHigh, sp.endExt()) <= 0
+	})
+	if i == s.Count() {
+		return false
+	}
+	// The span must also not start after the start of the key's extension.
+	sp := s.Get(i)
+	return key.Compare(keyCtx, sp.start, ExtendLow, sp.startExt()) >= 0
+}
+
+// Subtract returns the spans that cover every key covered by these spans but
+// not by the given spans. Both collections of spans must be sorted and merged
+// (see SortAndMerge); the result will be as well. For example:
+//   [/1 - /10] [/20 - /30] MINUS [/5 - /25]  =  [/1 - /5) (/25 - /30]
+func (s *Spans) Subtract(keyCtx *KeyContext, other *Spans) Spans {
+	// Use variation on merge sort, because both sets of spans are ordered and
+	// non-overlapping.
+	var result Spans
+	result.Alloc(s.Count())
+	otherIndex := 0
+	for i := 0; i < s.Count(); i++ {
+		remaining := *s.Get(i)
+		keep := true
+		for otherIndex < other.Count() {
+			o := other.Get(otherIndex)
+			if remaining.StartsAfter(keyCtx, o) {
+				// The other span ends before the remaining span starts, so it
+				// cannot overlap any of the following spans either.
+				otherIndex++
+				continue
+			}
+			if o.StartsAfter(keyCtx, &remaining) {
+				// The other span starts after the remaining span ends.
+				break
+			}
+			rest, hasRest, ok := remaining.TrySubtract(keyCtx, o)
+			if !ok {
+				// The remaining span is fully covered. The other span may still
+				// overlap the following spans, so don't skip past it.
+				keep = false
+				break
+			}
+			if hasRest {
+				// The other span is strictly inside the remaining span.
+				result.Append(&remaining)
+				remaining = rest
+				otherIndex++
+			}
+			// If a single span remained, it is either to the left of the other
+			// span, in which case the next iteration will break out of the loop,
+			// or to the right of it, in which case the next iteration will skip
+			// the other span.
+		}
+		if keep {
+			result.Append(&remaining)
+		}
+	}
+	return result
+}
+
+// UnionSpans returns the spans that cover every key covered by any of the given
+// collections of spans. Each collection must be sorted and merged (see
+// SortAndMerge); the result will be as well. For example:
+//   [/1 - /5] [/20 - /30]  UNION  [/3 - /10]  UNION  [/10 - /15] [/40 - /50]
+//     =  [/1 - /15] [/20 - /30] [/40 - /50]
+//
+// The collections are combined in a single pass using a k-way merge, so the
+// union of k collections with a total of n spans takes O(n log k) time.
+func UnionSpans(keyCtx *KeyContext, spans ...*Spans) Spans {
+	h := spanCursorHeap{keyCtx: keyCtx}
+	total := 0
+	for _, s := range spans {
+		if s.Count() > 0 {
+			h.cursors = append(h.cursors, spanCursor{spans: s, span: s.Get(0)})
+			total += s.Count()
+		}
+	}
+	heap.Init(&h)
+
+	var result Spans
+	result.Alloc(total)
+	var mergeSpan Span
+	for i := 0; h.Len() > 0; i++ {
+		cur := &h.cursors[0]
+		sp := cur.span
+		// The spans are visited in order of their start boundaries, so if the
+		// next span does not overlap or touch the current merged span, then
+		// neither does any of the following spans.
+		if i == 0 {
+			mergeSpan = *sp
+		} else if !mergeSpan.TryUnionWith(keyCtx, sp) {
+			result.Append(&mergeSpan)
+			mergeSpan = *sp
+		}
+		cur.idx++
+		if cur.idx == cur.spans.Count() {
+			heap.Pop(&h)
+		} else {
+			cur.span = cur.spans.Get(cur.idx)
+			heap.Fix(&h, 0)
+		}
+	}
+	if total > 0 {
+		result.Append(&mergeSpan)
+	}
+	return result
+}
+
+// spanCursor points to the next span of a collection of spans that is merged
+// by UnionSpans.
+type spanCursor struct {
+	spans *Spans
+	idx   int
+	// span caches spans.Get(idx).
+	span *Span
+}
+
+// spanCursorHeap is a min-heap of span cursors, ordered by their next span.
+type spanCursorHeap struct {
+	keyCtx  *KeyContext
+	cursors []spanCursor
+}
+
+var _ heap.Interface = &spanCursorHeap{}
+
+// Len is part of heap.Interface.
+func (h *spanCursorHeap) Len() int {
+	return len(h.cursors)
+}
+
+// Less is part of heap.Interface.
+func (h *spanCursorHeap) Less(i, j int) bool {
+	return h.cursors[i].span.Compare(h.keyCtx, h.cursors[j].span) < 0
+}
+
+// Swap is part of heap.Interface.
+func (h *spanCursorHeap) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+// Push is part of heap.Interface.
+func (h *spanCursorHeap) Push(x interface{}) {
+	h.cursors = append(h.cursors, x.(spanCursor))
+}
+
+// Pop is part of heap.Interface.
+func (h *spanCursorHeap) Pop() interface{} {
+	n := len(h.cursors)
+	res := h.cursors[n-1]
+	h.cursors = h.cursors[:n-1]
+	return res
+}
+
 type spanSorter struct {
 	keyCtx KeyContext
 	spans  *Spans
@@ -174,3 +359,40 @@ func (ss *spanSorter) Swap(i, j int) {
 	sj := ss.spans.Get(j)
 	*si, *sj = *sj, *si
 }
+
+// keySorter sorts keys of the same length.
+type keySorter struct {
+	keyCtx *KeyContext
+	keys   []Key
+}
+
+var _ sort.Interface = &keySorter{}
+
+func (ks *keySorter) compare(i, j int) int {
+	return ks.keys[i].Compare(ks.keyCtx, ks.keys[j], ExtendLow, ExtendLow)
+}
+
+// sortedAndDistinct returns true if the keys are strictly ordered.
+func (ks *keySorter) sortedAndDistinct() bool {
+	for i := 1; i < len(ks.keys); i++ {
+		if ks.compare(i-1, i) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Len is part of sort.Interface.
+func (ks *keySorter) Len() int {
+	return len(ks.keys)
+}
+
+// Less is part of sort.Interface.
+func (ks *keySorter) Less(i, j int) bool {
+	return ks.compare(i, j) < 0
+}
+
+// Swap is part of sort.Interface.
+func (ks *keySorter) Swap(i, j int) {
+	ks.keys[i], ks.keys[j] = ks.keys[j], ks.keys[i]
+}
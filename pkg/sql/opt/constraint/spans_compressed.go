@@ -0,0 +1,168 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package constraint
+
+import (
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// compressSpansThreshold is the minimum number of spans for which
+// Constraint.Compressed stores the spans in prefix-compressed form.
+// Decompressing a span on every call to Get is not free, so small collections
+// of spans are left alone.
+const compressSpansThreshold = 64
+
+// compressedSpans stores a collection of spans in prefix-compressed form.
+//
+// Constraints generated from multi-column IN lists can have thousands of spans
+// whose keys share a common prefix. For example, the filter
+// (a, b, c) IN ((1, 1, 1), (1, 1, 2), ..., (1, 2, 1), ...) results in the spans
+//   [/1/1/1 - /1/1/1] [/1/1/2 - /1/1/2] ... [/1/2/1 - /1/2/1] ...
+// Storing the values of every key separately wastes a lot of memory, since the
+// start and end keys of each span are the same, and consecutive keys share all
+// but their last value.
+//
+// Instead, the key values are stored in a trie: each node holds a single value
+// and points to the node which holds the preceding value of the key. A key is
+// represented by the node which holds its last value, and is reconstructed by
+// following the parent pointers. Since spans are sorted, values that are shared
+// with the previous key can be found by comparing against that key alone.
+// Values are shared only if they are the same Datum, which is the case for keys
+// that are built from the same IN list elements.
+type compressedSpans struct {
+	nodes []prefixNode
+	spans []compressedSpan
+
+	// last caches the most recently decompressed span, as a *decompressedSpan.
+	// Callers commonly call Get with the same index several times in a row, and
+	// this avoids decompressing the span again each time. It is an atomic.Value
+	// because immutable constraints can be shared by memos that are read
+	// concurrently.
+	last atomic.Value
+}
+
+// decompressedSpan is a span that was decompressed from compressedSpans.
+type decompressedSpan struct {
+	nth int
+	sp  Span
+}
+
+// prefixNode is a node in the trie of key values of compressedSpans.
+type prefixNode struct {
+	val tree.Datum
+	// parent is the index of the node holding the preceding value of the key, or
+	// -1 if val is the first value of the key.
+	parent int32
+	// length is the length of the key which ends with this node.
+	length int32
+}
+
+// compressedSpan is a span in compressedSpans.
+type compressedSpan struct {
+	// start and end are the indexes of the nodes holding the last values of the
+	// start and end keys, or -1 if the key is empty.
+	start, end                 int32
+	startBoundary, endBoundary SpanBoundary
+}
+
+// compressSpans returns the prefix-compressed form of the given spans, or nil
+// if the keys of the spans share too few values for the compressed form to save
+// memory.
+func compressSpans(s *Spans) *compressedSpans {
+	c := &compressedSpans{spans: make([]compressedSpan, s.Count())}
+
+	// path contains the indexes of the nodes holding the values of the
+	// previously added key.
+	var path []int32
+	numVals := 0
+	addKey := func(k Key) int32 {
+		numVals += k.Length()
+		shared := 0
+		for shared < len(path) && shared < k.Length() &&
+			c.nodes[path[shared]].val == k.Value(shared) {
+			shared++
+		}
+		path = path[:shared]
+		for i := shared; i < k.Length(); i++ {
+			parent := int32(-1)
+			if i > 0 {
+				parent = path[i-1]
+			}
+			c.nodes = append(c.nodes, prefixNode{val: k.Value(i), parent: parent, length: int32(i + 1)})
+			path = append(path, int32(len(c.nodes)-1))
+		}
+		if len(path) == 0 {
+			return -1
+		}
+		return path[len(path)-1]
+	}
+
+	for i := range c.spans {
+		sp := s.Get(i)
+		c.spans[i] = compressedSpan{
+			start:         addKey(sp.start),
+			end:           addKey(sp.end),
+			startBoundary: sp.startBoundary,
+			endBoundary:   sp.endBoundary,
+		}
+	}
+
+	// Each node takes up more memory than a value in an uncompressed key, so only
+	// use the compressed form if at least half of the values are shared.
+	if len(c.nodes)*2 > numVals {
+		return nil
+	}
+	// Release any excess capacity.
+	c.nodes = append([]prefixNode(nil), c.nodes...)
+	return c
+}
+
+// get returns the nth span. The span is only decompressed if it is not the
+// same as the one returned by the previous call. Spans returned by earlier
+// calls remain valid.
+func (c *compressedSpans) get(nth int) *Span {
+	if last, ok := c.last.Load().(*decompressedSpan); ok && last.nth == nth {
+		return &last.sp
+	}
+	cs := &c.spans[nth]
+	d := &decompressedSpan{nth: nth}
+	d.sp.start = c.key(cs.start)
+	d.sp.startBoundary = cs.startBoundary
+	if cs.end == cs.start {
+		// Point spans have identical start and end keys, so the values only need
+		// to be reconstructed once. Keys are immutable, so they can be shared.
+		d.sp.end = d.sp.start
+	} else {
+		d.sp.end = c.key(cs.end)
+	}
+	d.sp.endBoundary = cs.endBoundary
+	c.last.Store(d)
+	return &d.sp
+}
+
+// key reconstructs the key which ends with the given node.
+func (c *compressedSpans) key(node int32) Key {
+	if node < 0 {
+		return EmptyKey
+	}
+	n := &c.nodes[node]
+	if n.length == 1 {
+		return Key{firstVal: n.val}
+	}
+	otherVals := make(tree.Datums, n.length-1)
+	for i := len(otherVals) - 1; i >= 0; i-- {
+		otherVals[i] = n.val
+		n = &c.nodes[n.parent]
+	}
+	return Key{firstVal: n.val, otherVals: otherVals}
+}
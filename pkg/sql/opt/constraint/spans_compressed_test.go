@@ -0,0 +1,178 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package constraint
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestCompressedSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	keyCtx := testKeyContext(1, 2, 3)
+
+	datums := make(tree.Datums, 100)
+	for i := range datums {
+		datums[i] = tree.NewDInt(tree.DInt(i))
+	}
+
+	check := func(spans *Spans, expectCompressed bool) {
+		t.Helper()
+		var uncompressed Constraint
+		uncompressed.Init(keyCtx, spans)
+		if uncompressed.Spans.compressed != nil {
+			t.Fatal("expected Init not to compress the spans")
+		}
+		c := uncompressed.Compressed()
+		if compressed := c != &uncompressed; compressed != expectCompressed {
+			t.Fatalf("expected compressed=%t, got %t", expectCompressed, compressed)
+		}
+		if compressed := c.Spans.compressed != nil; compressed != expectCompressed {
+			t.Fatalf("expected compressed spans=%t, got %t", expectCompressed, compressed)
+		}
+		if c.Spans.Count() != spans.Count() {
+			t.Fatalf("expected %d spans, got %d", spans.Count(), c.Spans.Count())
+		}
+		for i := 0; i < spans.Count(); i++ {
+			if expected, actual := spans.Get(i), c.Spans.Get(i); expected.Compare(keyCtx, actual) != 0 {
+				t.Errorf("span %d: expected %s, got %s", i, expected, actual)
+			}
+		}
+		if expected, actual := spans.String(), c.Spans.String(); expected != actual {
+			t.Errorf("expected %s, got %s", expected, actual)
+		}
+	}
+
+	// Spans generated from an IN list, like those for the filter
+	// (a, b, c) IN ((1, 0, 0), (1, 0, 1), ...). The keys share most values.
+	var spans Spans
+	for a := 1; a < 3; a++ {
+		for b := 0; b < 5; b++ {
+			for c := 0; c < 10; c++ {
+				var sp Span
+				key := MakeCompositeKey(datums[a], datums[b], datums[c])
+				sp.Init(key, IncludeBoundary, key, IncludeBoundary)
+				spans.Append(&sp)
+			}
+			// Add a span with keys of different lengths and exclusive boundaries.
+			var sp Span
+			sp.Init(
+				MakeCompositeKey(datums[a], datums[b], datums[20]), ExcludeBoundary,
+				MakeCompositeKey(datums[a], datums[b+1]), ExcludeBoundary,
+			)
+			spans.Append(&sp)
+		}
+	}
+	var sp Span
+	sp.Init(MakeKey(datums[5]), IncludeBoundary, EmptyKey, IncludeBoundary)
+	spans.Append(&sp)
+	check(&spans, true /* expectCompressed */)
+
+	// Getting the same span repeatedly only decompresses it once.
+	var uncompressed Constraint
+	uncompressed.Init(keyCtx, &spans)
+	c := uncompressed.Compressed()
+	first := c.Spans.Get(3)
+	if allocs := testing.AllocsPerRun(10, func() {
+		if c.Spans.Get(3) != first {
+			t.Fatal("expected the cached span to be returned")
+		}
+	}); allocs != 0 {
+		t.Errorf("expected no allocations, got %.1f", allocs)
+	}
+	// Spans returned before remain valid after other spans are decompressed.
+	_ = c.Spans.Get(4)
+	if expected := spans.Get(3); expected.Compare(keyCtx, first) != 0 {
+		t.Errorf("expected %s, got %s", expected, first)
+	}
+
+	// The same spans, but with keys that don't share any datums. They cannot be
+	// compressed.
+	var unshared Spans
+	for a := 1; a < 3; a++ {
+		for b := 0; b < 5; b++ {
+			for c := 0; c < 10; c++ {
+				var sp Span
+				sp.Init(
+					MakeCompositeKey(tree.NewDInt(tree.DInt(a)), tree.NewDInt(tree.DInt(b)), datums[c]),
+					IncludeBoundary,
+					MakeCompositeKey(tree.NewDInt(tree.DInt(a)), tree.NewDInt(tree.DInt(b)), datums[c]),
+					IncludeBoundary,
+				)
+				unshared.Append(&sp)
+			}
+		}
+	}
+	check(&unshared, false /* expectCompressed */)
+
+	// Too few spans to be worth compressing.
+	var few Spans
+	for i := 0; i < compressSpansThreshold-1; i++ {
+		var sp Span
+		key := MakeCompositeKey(datums[1], datums[i])
+		sp.Init(key, IncludeBoundary, key, IncludeBoundary)
+		few.Append(&sp)
+	}
+	check(&few, false /* expectCompressed */)
+}
+
+func BenchmarkCompressedSpans(b *testing.B) {
+	keyCtx := testKeyContext(1, 2, 3)
+
+	// Spans generated from an IN list with 1000 elements, like those for the
+	// filter (a, b, c) IN ((0, 0, 0), (0, 0, 1), ..., (9, 9, 9)).
+	datums := make(tree.Datums, 10)
+	for i := range datums {
+		datums[i] = tree.NewDInt(tree.DInt(i))
+	}
+	var spans Spans
+	for x := range datums {
+		for y := range datums {
+			for z := range datums {
+				var sp Span
+				key := MakeCompositeKey(datums[x], datums[y], datums[z])
+				sp.Init(key, IncludeBoundary, key, IncludeBoundary)
+				spans.Append(&sp)
+			}
+		}
+	}
+	var uncompressed Constraint
+	uncompressed.Init(keyCtx, &spans)
+	compressed := uncompressed.Compressed()
+	if compressed == &uncompressed {
+		b.Fatal("expected the spans to be compressed")
+	}
+
+	b.Run("compress", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = uncompressed.Compressed()
+		}
+	})
+	for _, tc := range []struct {
+		name string
+		c    *Constraint
+	}{
+		{name: "iterate/uncompressed", c: &uncompressed},
+		{name: "iterate/compressed", c: compressed},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j, n := 0, tc.c.Spans.Count(); j < n; j++ {
+					_ = tc.c.Spans.Get(j)
+				}
+			}
+		})
+	}
+}
@@ -107,3 +107,216 @@ func TestSpansSortAndMerge(t *testing.T) {
 		}
 	}
 }
+
+func TestSpansSubtract(t *testing.T) {
+	keyCtx := testKeyContext(1)
+	evalCtx := keyCtx.EvalCtx
+
+	testCases := []struct {
+		left, right string
+		expected    string
+	}{
+		{"[/1 - /10]", "", "[/1 - /10]"},
+		{"", "[/1 - /10]", ""},
+		{"[/1 - /10]", "[/1 - /10]", ""},
+		{"[/1 - /10] [/20 - /30]", "[/5 - /25]", "[/1 - /5) (/25 - /30]"},
+		{"[/1 - /10] [/20 - /30]", "[/2 - /3] [/5 - /6] [/25 - /25]", "[/1 - /2) (/3 - /5) (/6 - /10] [/20 - /25) (/25 - /30]"},
+		{"[/1 - /2] [/4 - /5] [/7 - /8]", "[/2 - /7]", "[/1 - /2) (/7 - /8]"},
+		{"[/1 - /2] [/4 - /5] [/7 - /8]", "[/0 - /0] [/3 - /3] [/6 - /6] [/9 - /9]", "[/1 - /2] [/4 - /5] [/7 - /8]"},
+		{"[ - ]", "[/1 - /1] [/3 - /3]", "[ - /1) (/1 - /3) (/3 - ]"},
+		{"[/1 - /10]", "[ - /3] [/8 - ]", "(/3 - /8)"},
+	}
+
+	for _, tc := range testCases {
+		left := parseSpans(evalCtx, tc.left)
+		right := parseSpans(evalCtx, tc.right)
+		result := left.Subtract(keyCtx, &right)
+		if actual := result.String(); actual != tc.expected {
+			t.Errorf("%s MINUS %s: expected %s, got %s", tc.left, tc.right, tc.expected, actual)
+		}
+	}
+
+	// Cross-check against the definition of the difference using random spans
+	// over a small integer domain.
+	for testIdx := 0; testIdx < 100; testIdx++ {
+		rng, _ := randutil.NewTestRand()
+		randSpans := func() Spans {
+			var spans Spans
+			for i, n := 0, rng.Intn(4); i < n; i++ {
+				x, y := rng.Intn(20), rng.Intn(20)
+				if x > y {
+					x, y = y, x
+				}
+				var sp Span
+				sp.Init(
+					MakeKey(tree.NewDInt(tree.DInt(x))), IncludeBoundary,
+					MakeKey(tree.NewDInt(tree.DInt(y))), IncludeBoundary,
+				)
+				spans.Append(&sp)
+			}
+			spans.SortAndMerge(keyCtx)
+			return spans
+		}
+		contains := func(s *Spans, x int) bool {
+			var key Span
+			k := MakeKey(tree.NewDInt(tree.DInt(x)))
+			key.Init(k, IncludeBoundary, k, IncludeBoundary)
+			for i := 0; i < s.Count(); i++ {
+				if !s.Get(i).StartsAfter(keyCtx, &key) && !key.StartsAfter(keyCtx, s.Get(i)) {
+					return true
+				}
+			}
+			return false
+		}
+		left, right := randSpans(), randSpans()
+		result := left.Subtract(keyCtx, &right)
+		if !result.sortedAndMerged(keyCtx) {
+			t.Fatalf("%s MINUS %s: result %s is not sorted and merged", &left, &right, &result)
+		}
+		for x := -1; x <= 20; x++ {
+			expected := contains(&left, x) && !contains(&right, x)
+			if actual := contains(&result, x); actual != expected {
+				t.Fatalf("%s MINUS %s = %s: expected contains(%d)=%t", &left, &right, &result, x, expected)
+			}
+		}
+	}
+}
+
+func TestSpansContainsKey(t *testing.T) {
+	keyCtx := testKeyContext(1, 2)
+	evalCtx := keyCtx.EvalCtx
+
+	testCases := []struct {
+		spans        string
+		contained    []string
+		notContained []string
+	}{
+		{
+			spans:        "[/1 - /5) (/10 - /20]",
+			contained:    []string{"/1", "/1/1", "/4/7", "/15", "/20", "/20/3"},
+			notContained: []string{"/0", "/5", "/5/1", "/10", "/10/1", "/21"},
+		},
+		{
+			// Prefix keys at the boundaries of spans with longer keys.
+			spans:        "[/1/3 - /2] (/4 - /5/2]",
+			contained:    []string{"/1/3", "/1/5", "/2", "/2/7", "/5/2"},
+			notContained: []string{"/1", "/1/2", "/3", "/4", "/4/1", "/5", "/5/3"},
+		},
+		{
+			// Inclusive and exclusive end boundaries that are prefixes of the key.
+			spans:        "[/1 - /1] [/3 - /4)",
+			contained:    []string{"/1", "/1/5", "/3", "/3/1"},
+			notContained: []string{"/2", "/4", "/4/5"},
+		},
+		{
+			spans:        "[/1 - /2/3)",
+			contained:    []string{"/1", "/2/2"},
+			notContained: []string{"/2", "/2/3"},
+		},
+		{
+			spans:        "[ - /2] [/4 - ]",
+			contained:    []string{"/1", "/2/5", "/4", "/9/9"},
+			notContained: []string{"/3", "/3/1"},
+		},
+		{
+			spans:        "",
+			notContained: []string{"/1"},
+		},
+	}
+
+	parseKey := func(str string) Key {
+		var vals []tree.Datum
+		for _, v := range parseIntPath(str) {
+			vals = append(vals, tree.NewDInt(tree.DInt(v)))
+		}
+		return MakeCompositeKey(vals...)
+	}
+	for _, tc := range testCases {
+		spans := parseSpans(evalCtx, tc.spans)
+		for _, key := range tc.contained {
+			if !spans.ContainsKey(keyCtx, parseKey(key)) {
+				t.Errorf("%s should contain %s", tc.spans, key)
+			}
+		}
+		for _, key := range tc.notContained {
+			if spans.ContainsKey(keyCtx, parseKey(key)) {
+				t.Errorf("%s should not contain %s", tc.spans, key)
+			}
+		}
+	}
+}
+
+func TestUnionSpans(t *testing.T) {
+	keyCtx := testKeyContext(1)
+	evalCtx := keyCtx.EvalCtx
+
+	testCases := []struct {
+		spans    []string
+		expected string
+	}{
+		{[]string{}, ""},
+		{[]string{"", ""}, ""},
+		{[]string{"[/1 - /10]", ""}, "[/1 - /10]"},
+		{[]string{"[/1 - /5] [/20 - /30]", "[/3 - /10]", "[/10 - /15] [/40 - /50]"}, "[/1 - /15] [/20 - /30] [/40 - /50]"},
+		{[]string{"[/1 - /2)", "(/2 - /3]", "[/5 - /5]"}, "[/1 - /2) (/2 - /3] [/5 - /5]"},
+		{[]string{"[/1 - /2)", "[/2 - /3]"}, "[/1 - /3]"},
+		{[]string{"[/1 - /1]", "[/2 - /2]", "[/3 - /3]"}, "[/1 - /1] [/2 - /2] [/3 - /3]"},
+		{[]string{"[ - /5]", "[/3 - ]"}, "[ - ]"},
+	}
+
+	for _, tc := range testCases {
+		spans := make([]*Spans, len(tc.spans))
+		for i := range tc.spans {
+			s := parseSpans(evalCtx, tc.spans[i])
+			spans[i] = &s
+		}
+		result := UnionSpans(keyCtx, spans...)
+		if actual := result.String(); actual != tc.expected {
+			t.Errorf("UNION %v: expected %s, got %s", tc.spans, tc.expected, actual)
+		}
+	}
+
+	// Cross-check against Constraint.UnionWith using random spans.
+	for testIdx := 0; testIdx < 100; testIdx++ {
+		rng, _ := randutil.NewTestRand()
+		spans := make([]*Spans, 1+rng.Intn(5))
+		var c Constraint
+		for i := range spans {
+			var s Spans
+			for j, n := 0, rng.Intn(4); j < n; j++ {
+				x, y := 1+rng.Intn(20), 1+rng.Intn(20)
+				if x > y {
+					x, y = y, x
+				}
+				xb, yb := IncludeBoundary, IncludeBoundary
+				if x != y && rng.Intn(2) == 0 {
+					xb = ExcludeBoundary
+				}
+				if x != y && rng.Intn(2) == 0 {
+					yb = ExcludeBoundary
+				}
+				var sp Span
+				sp.Init(
+					MakeKey(tree.NewDInt(tree.DInt(x))), xb,
+					MakeKey(tree.NewDInt(tree.DInt(y))), yb,
+				)
+				s.Append(&sp)
+			}
+			s.SortAndMerge(keyCtx)
+			spans[i] = &s
+
+			var d Constraint
+			d.Init(keyCtx, &s)
+			if i == 0 {
+				c = d
+			} else {
+				c.UnionWith(evalCtx, &d)
+			}
+		}
+
+		result := UnionSpans(keyCtx, spans...)
+		if actual, expected := result.String(), c.Spans.String(); actual != expected {
+			t.Fatalf("UNION %v: expected %s, got %s", spans, expected, actual)
+		}
+	}
+}
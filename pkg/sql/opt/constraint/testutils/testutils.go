@@ -0,0 +1,119 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package testutils contains helpers to build constraints and spans from their
+// string representation, so that tests can declare them concisely, e.g.:
+//
+//   c := testutils.ParseConstraint(evalCtx, "/1/-2: [/1/5 - /1/2] [/3 - /10)")
+//
+// A column ordering is written as in Columns.String, as a list of column IDs
+// in which a negative ID denotes a descending column. Spans are written as in
+// Span.String (a single span can be parsed with constraint.ParseSpan), and are
+// compared with respect to a column ordering through a constraint.KeyContext.
+package testutils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// ParseColumns parses a column ordering in the format of Columns.String, e.g.
+// "/1/-2/3".
+func ParseColumns(str string) constraint.Columns {
+	var cols []opt.OrderingColumn
+	for _, s := range tree.ParsePath(str) {
+		col, err := strconv.Atoi(s)
+		if err != nil || col == 0 {
+			panic(errors.AssertionFailedf("invalid column ordering: %s", str))
+		}
+		cols = append(cols, opt.OrderingColumn(col))
+	}
+	var c constraint.Columns
+	c.Init(cols)
+	return c
+}
+
+// MakeKeyContext returns the KeyContext used to compare the keys and spans
+// over the given column ordering, e.g. "/1/-2".
+func MakeKeyContext(evalCtx *eval.Context, cols string) constraint.KeyContext {
+	c := ParseColumns(cols)
+	return constraint.MakeKeyContext(&c, evalCtx)
+}
+
+// spanRegexp matches a single span in a list of spans. Values within spans
+// cannot contain brackets or parentheses.
+var spanRegexp = regexp.MustCompile(`[\[(][^\[\]()]*[\])]`)
+
+// ParseSpans parses a list of spans in the format of Spans.String, e.g.
+// "[/1 - /2] (/5 - /6]". The spans are returned in the order they are given.
+// The special values "unconstrained" and "contradiction" are also accepted.
+func ParseSpans(evalCtx *eval.Context, str string, typs ...types.Family) constraint.Spans {
+	var spans constraint.Spans
+	switch str = strings.TrimSpace(str); str {
+	case "", "contradiction":
+		return spans
+	case "unconstrained":
+		spans.InitSingleSpan(&constraint.UnconstrainedSpan)
+		return spans
+	}
+	matches := spanRegexp.FindAllStringIndex(str, -1)
+	prevEnd := 0
+	for _, m := range matches {
+		if strings.TrimSpace(str[prevEnd:m[0]]) != "" {
+			panic(errors.AssertionFailedf("invalid span format: %s", str))
+		}
+		sp := constraint.ParseSpan(evalCtx, str[m[0]:m[1]], typs...)
+		spans.Append(&sp)
+		prevEnd = m[1]
+	}
+	if len(matches) == 0 || strings.TrimSpace(str[prevEnd:]) != "" {
+		panic(errors.AssertionFailedf("invalid span format: %s", str))
+	}
+	return spans
+}
+
+// ParseConstraint parses a constraint in the format of Constraint.String, e.g.
+// "/1/-2: [/1/5 - /1/2] [/3 - /10)". The spans must be ordered and must not
+// overlap with respect to the column ordering of the constraint.
+func ParseConstraint(evalCtx *eval.Context, str string, typs ...types.Family) constraint.Constraint {
+	s := strings.SplitN(str, ": ", 2)
+	if len(s) != 2 {
+		panic(errors.AssertionFailedf("invalid constraint format: %s", str))
+	}
+	var c constraint.Constraint
+	c.Columns = ParseColumns(s[0])
+	c.Spans = ParseSpans(evalCtx, s[1], typs...)
+	keyCtx := constraint.MakeKeyContext(&c.Columns, evalCtx)
+	for i := 1; i < c.Spans.Count(); i++ {
+		if !c.Spans.Get(i).StartsAfter(&keyCtx, c.Spans.Get(i-1)) {
+			panic(errors.AssertionFailedf("spans are not ordered or overlap: %s", str))
+		}
+	}
+	return c
+}
+
+// CompareSpans parses the two spans and compares them with respect to the
+// column ordering of the key context. The result is 0 if the spans are equal,
+// -1 if the left span sorts before the right one and 1 otherwise. Spans sort
+// by their start boundaries first, and then by their end boundaries (see
+// Span.Compare).
+func CompareSpans(keyCtx *constraint.KeyContext, left, right string, typs ...types.Family) int {
+	l := constraint.ParseSpan(keyCtx.EvalCtx, left, typs...)
+	r := constraint.ParseSpan(keyCtx.EvalCtx, right, typs...)
+	return l.Compare(keyCtx, &r)
+}
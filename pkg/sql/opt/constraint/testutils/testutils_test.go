@@ -0,0 +1,75 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testutils
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConstraint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+
+	testCases := []string{
+		"/1: [/1 - /10)",
+		"/1/-2: [/1/5 - /1/2] (/3 - /10]",
+		"/2: [ - /3] [/5 - ]",
+		"/1: unconstrained",
+		"/1: contradiction",
+	}
+	for _, tc := range testCases {
+		c := ParseConstraint(&evalCtx, tc)
+		require.Equal(t, tc, c.String())
+	}
+
+	// Spans are ordered according to the direction of the columns.
+	require.Panics(t, func() { ParseConstraint(&evalCtx, "/1: [/5 - /6] [/1 - /2]") })
+	require.NotPanics(t, func() { ParseConstraint(&evalCtx, "/-1: [/5 - /6] [/2 - /1]") })
+	require.Panics(t, func() { ParseConstraint(&evalCtx, "/1: [/1 - /5] [/3 - /6]") })
+	require.Panics(t, func() { ParseConstraint(&evalCtx, "/1 [/1 - /5]") })
+
+	// Values can be given explicit types.
+	c := ParseConstraint(&evalCtx, "/1: [/1 - /2]", types.StringFamily)
+	require.Equal(t, types.String, c.Spans.Get(0).StartKey().Value(0).ResolvedType())
+}
+
+func TestCompareSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := eval.MakeTestingEvalContext(st)
+
+	asc := MakeKeyContext(&evalCtx, "/1/2")
+	desc := MakeKeyContext(&evalCtx, "/-1/2")
+	testCases := []struct {
+		left, right string
+		asc, desc   int
+	}{
+		{"[/1 - /2]", "[/1 - /2]", 0, 0},
+		{"[/1 - /2]", "(/1 - /2]", -1, -1},
+		{"[/1 - /2]", "[/3 - /4]", -1, 1},
+		{"[/1 - /2)", "[/1 - /2]", -1, -1},
+		{"[/1/1 - /2]", "[/1 - /2]", 1, 1},
+		{"[ - /2]", "[/1 - /2]", -1, -1},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.asc, CompareSpans(&asc, tc.left, tc.right), "%s vs %s", tc.left, tc.right)
+		require.Equal(t, tc.desc, CompareSpans(&desc, tc.left, tc.right), "%s vs %s", tc.left, tc.right)
+	}
+}
@@ -11,6 +11,7 @@
 package execbuilder
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -152,6 +153,10 @@ type Builder struct {
 	// was used in the query.
 	JoinAlgorithmCounts map[exec.JoinAlgorithm]int
 
+	// IndexesUsed is the list of indexes read by the query, in the format
+	// tableID@indexID. Indexes of virtual tables are not included.
+	IndexesUsed []string
+
 	// wrapFunctionOverride overrides default implementation to return resolvable
 	// function reference for function with specified function name.
 	// The default can be overridden by calling SetBuiltinFuncWrapper method to provide
@@ -335,6 +340,16 @@ func (b *Builder) findBuiltWithExpr(id opt.WithID) *builtWithExpr {
 	return nil
 }
 
+// recordIndexUsed adds the given index of a non-virtual table to the list of
+// indexes used by the query.
+func (b *Builder) recordIndexUsed(tab cat.Table, idx cat.IndexOrdinal) {
+	if tab.IsVirtualTable() {
+		return
+	}
+	key := fmt.Sprintf("%d@%d", tab.ID(), tab.Index(idx).ID())
+	b.IndexesUsed = util.CombineUniqueString(b.IndexesUsed, []string{key})
+}
+
 // boundedStaleness returns true if this query uses bounded staleness.
 func (b *Builder) boundedStaleness() bool {
 	return b.evalCtx != nil && b.evalCtx.BoundedStaleness()
@@ -676,6 +676,17 @@ func (b *Builder) scanParams(
 		return exec.ScanParams{}, opt.ColMap{}, errors.AssertionFailedf("scan can't provide required ordering")
 	}
 
+	// In test builds, assert that the spans of the constraint are sorted in
+	// ascending order and merged. Reverse scans read the spans in descending
+	// order by reversing them, without re-sorting them.
+	if buildutil.CrdbTestBuild && reverse && scan.Constraint != nil {
+		keyCtx := constraint.MakeKeyContext(&scan.Constraint.Columns, b.evalCtx)
+		if !scan.Constraint.Spans.IsSortedAndMerged(&keyCtx) {
+			return exec.ScanParams{}, opt.ColMap{}, errors.AssertionFailedf(
+				"reverse scan constraint spans are not sorted and merged: %s", scan.Constraint)
+		}
+	}
+
 	return exec.ScanParams{
 		NeededCols:         needed,
 		IndexConstraint:    scan.Constraint,
@@ -713,6 +724,8 @@ func (b *Builder) buildScan(scan *memo.ScanExpr) (execPlan, error) {
 		}
 	}
 
+	b.recordIndexUsed(tab, scan.Index)
+
 	// Save if we planned a full table/index scan on the builder so that the
 	// planner can be made aware later. We only do this for non-virtual tables.
 	stats := scan.Relational().Stats
@@ -1872,6 +1885,7 @@ func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 
 	md := b.mem.Metadata()
 	tab := md.Table(join.Table)
+	b.recordIndexUsed(tab, cat.PrimaryIndex)
 
 	// TODO(radu): the distsql implementation of index join assumes that the input
 	// starts with the PK columns in order (#40749).
@@ -2173,6 +2187,7 @@ func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 
 	tab := md.Table(join.Table)
 	idx := tab.Index(join.Index)
+	b.recordIndexUsed(tab, join.Index)
 
 	locking := join.Locking
 	if b.forceForUpdateLocking {
@@ -2334,6 +2349,7 @@ func (b *Builder) buildInvertedJoin(join *memo.InvertedJoinExpr) (execPlan, erro
 	md := b.mem.Metadata()
 	tab := md.Table(join.Table)
 	idx := tab.Index(join.Index)
+	b.recordIndexUsed(tab, join.Index)
 
 	prefixEqCols := make([]exec.NodeColumnOrdinal, len(join.PrefixKeyCols))
 	for i, c := range join.PrefixKeyCols {
@@ -2435,6 +2451,8 @@ func (b *Builder) buildZigzagJoin(join *memo.ZigzagJoinExpr) (execPlan, error) {
 	rightTable := md.Table(join.RightTable)
 	leftIndex := leftTable.Index(join.LeftIndex)
 	rightIndex := rightTable.Index(join.RightIndex)
+	b.recordIndexUsed(leftTable, join.LeftIndex)
+	b.recordIndexUsed(rightTable, join.RightIndex)
 
 	leftEqCols := make([]exec.TableColumnOrdinal, len(join.LeftEqCols))
 	rightEqCols := make([]exec.TableColumnOrdinal, len(join.RightEqCols))
@@ -193,6 +193,13 @@ func TestExecBuild_explain_shape(
 	runExecBuildLogicTest(t, "explain_shape")
 }
 
+func TestExecBuild_explain_spans(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runExecBuildLogicTest(t, "explain_spans")
+}
+
 func TestExecBuild_expression_index(
 	t *testing.T,
 ) {
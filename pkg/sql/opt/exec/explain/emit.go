@@ -31,9 +31,12 @@ import (
 )
 
 // Emit produces the EXPLAIN output against the given OutputBuilder. The
-// OutputBuilder flags are taken into account.
-func Emit(plan *Plan, ob *OutputBuilder, spanFormatFn SpanFormatFn) error {
-	e := makeEmitter(ob, spanFormatFn)
+// OutputBuilder flags are taken into account. keySpansFn is only used for
+// EXPLAIN (SPANS) and can be nil otherwise.
+func Emit(
+	plan *Plan, ob *OutputBuilder, spanFormatFn SpanFormatFn, keySpansFn KeySpansFn,
+) error {
+	e := makeEmitter(ob, spanFormatFn, keySpansFn)
 	var walk func(n *Node) error
 	walk = func(n *Node) error {
 		// In non-verbose mode, we skip all projections.
@@ -123,6 +126,12 @@ func Emit(plan *Plan, ob *OutputBuilder, spanFormatFn SpanFormatFn) error {
 // constraint.
 type SpanFormatFn func(table cat.Table, index cat.Index, scanParams exec.ScanParams) string
 
+// KeySpansFn is a function used to describe the encoded key spans that a scan
+// reads, for EXPLAIN (SPANS). It returns one line per key span. Only called on
+// non-virtual tables, when there is an index constraint or an inverted
+// constraint.
+type KeySpansFn func(table cat.Table, index cat.Index, scanParams exec.ScanParams) []string
+
 // omitTrivialProjections returns the given node and its result columns and
 // ordering, unless the node is an identity projection (which just renames
 // columns) - in which case we return the child node and the renamed columns.
@@ -166,14 +175,21 @@ func omitTrivialProjections(n *Node) (*Node, colinfo.ResultColumns, colinfo.Colu
 	return input, n.Columns(), ordering
 }
 
+// emptyPartitionCandidatesNode is implemented by scan nodes that remove the
+// spans of partitions which are proven to be empty at execution time.
+type emptyPartitionCandidatesNode interface {
+	EmptyPartitionCandidates() []string
+}
+
 // emitter is a helper for emitting explain information for all the operators.
 type emitter struct {
 	ob           *OutputBuilder
 	spanFormatFn SpanFormatFn
+	keySpansFn   KeySpansFn
 }
 
-func makeEmitter(ob *OutputBuilder, spanFormatFn SpanFormatFn) emitter {
-	return emitter{ob: ob, spanFormatFn: spanFormatFn}
+func makeEmitter(ob *OutputBuilder, spanFormatFn SpanFormatFn, keySpansFn KeySpansFn) emitter {
+	return emitter{ob: ob, spanFormatFn: spanFormatFn, keySpansFn: keySpansFn}
 }
 
 func (e *emitter) nodeName(n *Node) (string, error) {
@@ -417,6 +433,10 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		if s.KVBatchRequestsIssued.HasValue() {
 			e.ob.AddField("KV gRPC calls", string(humanizeutil.Count(s.KVBatchRequestsIssued.Value())))
 		}
+		// Omit KV spans in non-verbose mode.
+		if e.ob.flags.Verbose && s.KVSpansIssued.HasValue() {
+			e.ob.AddField("KV spans", string(humanizeutil.Count(s.KVSpansIssued.Value())))
+		}
 		if s.MaxAllocatedMem.HasValue() {
 			e.ob.AddField("estimated max memory allocated", humanize.IBytes(s.MaxAllocatedMem.Value()))
 		}
@@ -560,6 +580,9 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		if a.Table != nil && !(a.Table.IsVirtualTable() && a.Params.IndexConstraint == nil) {
 			e.emitSpans("spans", a.Table, a.Index, a.Params)
 		}
+		if e.ob.flags.ShowSpans && !e.ob.flags.HideValues && a.Table != nil {
+			e.emitKeySpans(a.Table, a.Index, a.Params)
+		}
 
 		if a.Params.HardLimit > 0 {
 			ob.Attr("limit", a.Params.HardLimit)
@@ -570,6 +593,11 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		if a.Params.Parallelize {
 			ob.VAttr("parallel", "")
 		}
+		if p, ok := n.WrappedNode().(emptyPartitionCandidatesNode); ok {
+			if parts := p.EmptyPartitionCandidates(); len(parts) > 0 {
+				ob.Attr("prunable partitions", strings.Join(parts, ", "))
+			}
+		}
 		e.emitLockingPolicy(a.Params.Locking)
 
 	case valuesOp:
@@ -975,6 +1003,21 @@ func (e *emitter) emitSpans(
 	e.ob.Attr(field, e.spansStr(table, index, scanParams))
 }
 
+// emitKeySpans emits, for EXPLAIN (SPANS), all the logical spans of a scan
+// followed by the encoded key spans that the execution layer reads.
+func (e *emitter) emitKeySpans(table cat.Table, index cat.Index, scanParams exec.ScanParams) {
+	if scanParams.IndexConstraint != nil {
+		e.ob.Attr("logical spans", scanParams.IndexConstraint.Spans.String())
+	}
+	if table.IsVirtualTable() || e.keySpansFn == nil ||
+		(scanParams.IndexConstraint == nil && scanParams.InvertedConstraint == nil) {
+		return
+	}
+	for _, line := range e.keySpansFn(table, index, scanParams) {
+		e.ob.Attr("key span", line)
+	}
+}
+
 func (e *emitter) spansStr(table cat.Table, index cat.Index, scanParams exec.ScanParams) string {
 	if scanParams.InvertedConstraint == nil && scanParams.IndexConstraint == nil {
 		// HardLimit can be -1 to signal unknown limit (for gists).
@@ -29,6 +29,9 @@ type Flags struct {
 	// This is used for EXPLAIN(SHAPE), which is used for the statement-bundle
 	// debug tool.
 	OnlyShape bool
+	// ShowSpans indicates that scans show all their logical spans along with the
+	// encoded key spans they read. This is used for EXPLAIN (SPANS).
+	ShowSpans bool
 
 	// Redaction control (for testing purposes).
 	Redact RedactFlags
@@ -74,6 +77,9 @@ func MakeFlags(options *tree.ExplainOptions) Flags {
 		f.Verbose = true
 		f.ShowTypes = true
 	}
+	if options.Flags[tree.ExplainFlagSpans] {
+		f.ShowSpans = true
+	}
 	if options.Flags[tree.ExplainFlagShape] {
 		f.HideValues = true
 		f.OnlyShape = true
@@ -335,6 +335,7 @@ type ExecutionStats struct {
 	KVBytesRead           optional.Uint
 	KVRowsRead            optional.Uint
 	KVBatchRequestsIssued optional.Uint
+	KVSpansIssued         optional.Uint
 
 	StepCount         optional.Uint
 	InternalStepCount optional.Uint
@@ -12,6 +12,7 @@ package idxconstraint
 
 import (
 	"regexp"
+	"regexp/syntax"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -131,6 +132,29 @@ func (c *indexConstraintCtx) makeStringPrefixSpan(
 	)
 }
 
+// splitLiteralPrefix parses the given regular expression and splits it into a
+// case-sensitive literal prefix and the sub-expressions that follow the
+// prefix. For example, `foo.*` is split into "foo" and `.*`. Returns ok=false
+// if the regular expression cannot be parsed.
+func splitLiteralPrefix(
+	pattern string, flags syntax.Flags,
+) (prefix string, rest []*syntax.Regexp, ok bool) {
+	re, err := syntax.Parse(pattern, flags)
+	if err != nil {
+		return "", nil, false
+	}
+	rest = []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		rest = re.Sub
+	}
+	var b strings.Builder
+	for len(rest) > 0 && rest[0].Op == syntax.OpLiteral && rest[0].Flags&syntax.FoldCase == 0 {
+		b.WriteString(string(rest[0].Rune))
+		rest = rest[1:]
+	}
+	return b.String(), rest, true
+}
+
 // verifyType checks that the type of the index column <offset> matches the
 // given type. We disallow mixed-type comparisons because it would result in
 // incorrect encodings (#4313).
@@ -317,7 +341,12 @@ func (c *indexConstraintCtx) makeSpansForSingleColumnDatum(
 					return true
 				}
 				c.makeStringPrefixSpan(offset, prefix, out)
-				return false
+				// SIMILAR TO patterns must match the entire string, and the . in the
+				// escaped pattern matches newlines. If the pattern is simply the
+				// prefix followed by %, the span is tight.
+				lit, rest, ok := splitLiteralPrefix(pattern, syntax.Perl|syntax.DotNL)
+				return ok && lit == prefix && len(rest) == 1 &&
+					rest[0].Op == syntax.OpStar && rest[0].Sub[0].Op == syntax.OpAnyChar
 			}
 		}
 
@@ -334,10 +363,18 @@ func (c *indexConstraintCtx) makeSpansForSingleColumnDatum(
 				// If complete is true, we have a case like (x ~ `^foo`) which is true
 				// iff the prefix of the string is `foo`; so the span is tight.
 				//
-				// Note that <complete> is not true for `^foo$` (which is good - the
-				// span would not be tight in that case; we would need an eqSpan). We
-				// can't easily detect this case by just checking if the last character
-				// is $ - it could be part of an escape.
+				// Note that <complete> is not true for `^foo$`. We can't easily detect
+				// this case by just checking if the last character is $ - it could be
+				// part of an escape - so parse the pattern to check whether the
+				// prefix is followed only by the end of the text. In that case, we
+				// can use an eqSpan.
+				if !complete {
+					lit, rest, ok := splitLiteralPrefix(string(pattern[1:]), syntax.Perl)
+					if ok && lit == prefix && len(rest) == 1 && rest[0].Op == syntax.OpEndText {
+						c.eqSpan(offset, tree.NewDString(prefix), out)
+						return true
+					}
+				}
 				c.makeStringPrefixSpan(offset, prefix, out)
 				return complete
 			}
@@ -557,18 +594,15 @@ func (c *indexConstraintCtx) makeSpansForTupleIn(
 		return false
 	}
 
-	// Create a span for each (tuple) value inside the right-hand side tuple.
-	keyCtx := &c.keyCtx[offset]
-	var spans constraint.Spans
-	var sp constraint.Span
-	spans.Alloc(len(rhs.Elems))
+	// Create a key for each (tuple) value inside the right-hand side tuple.
+	keys := make([]constraint.Key, 0, len(rhs.Elems))
+	vals := make(tree.Datums, len(tuplePos))
 	for _, child := range rhs.Elems {
 		valTuple, ok := child.(*memo.TupleExpr)
 		if !ok {
 			c.unconstrained(offset, out)
 			return false
 		}
-		vals := make(tree.Datums, len(tuplePos))
 		for i, pos := range tuplePos {
 			val := valTuple.Elems[pos]
 			if !opt.IsConstValueOp(val) {
@@ -588,21 +622,20 @@ func (c *indexConstraintCtx) makeSpansForTupleIn(
 		}
 		// If the tuple contains a NULL, ignore it (it can't match any values).
 		if !containsNull {
-			key := constraint.MakeCompositeKey(vals...)
-			sp.Init(key, includeBoundary, key, includeBoundary)
-			spans.Append(&sp)
+			keys = append(keys, c.spanBuilder.MakeCompositeKey(vals...))
 		}
 	}
 
-	// Sort and de-duplicate the values.
+	// Sort and de-duplicate the values, creating a single-key span for each.
 	// We don't rely on the sorted order of the right-hand side tuple because it's
 	// only useful if all of the following are met:
 	//  - we use all columns in the tuple, i.e. len(tuplePos) = lhs.ChildCount().
 	//  - the columns are in the right order, i.e. tuplePos[i] = i.
 	//  - the columns have the same directions
-	// Note that SortAndMerge exits quickly if the ordering is already correct.
-	spans.SortAndMerge(keyCtx)
-	out.Init(keyCtx, &spans)
+	// Note that InitSingleKeySpans exits quickly if the ordering is already
+	// correct. All the keys have the same length, so the spans never need to be
+	// merged.
+	out.InitSingleKeySpans(&c.keyCtx[offset], keys)
 	// The spans are "tight" unless we used just a prefix.
 	return len(tuplePos) == len(lhs.Elems)
 }
@@ -627,6 +660,15 @@ func (c *indexConstraintCtx) makeSpansForExpr(
 		return datum == tree.DBoolTrue
 	}
 
+	// Support (@1) as (@1 = TRUE) if @1 is boolean. This also applies to
+	// boolean expressions that match the expression of a computed index column,
+	// for example (@2 > 5) for an index on the expression (@2 > 5). The
+	// expression can be of any form (including AND and OR), so this check must
+	// come first.
+	if c.colType(offset).Family() == types.BoolFamily && c.isIndexColumn(e, offset) {
+		return c.makeSpansForSingleColumnDatum(offset, opt.EqOp, tree.DBoolTrue, out)
+	}
+
 	switch t := e.(type) {
 	case *memo.FiltersExpr:
 		switch len(*t) {
@@ -649,12 +691,6 @@ func (c *indexConstraintCtx) makeSpansForExpr(
 	case *memo.OrExpr:
 		return c.makeSpansForOr(offset, t, out)
 
-	case *memo.VariableExpr:
-		// Support (@1) as (@1 = TRUE) if @1 is boolean.
-		if c.colType(offset).Family() == types.BoolFamily && c.isIndexColumn(t, offset) {
-			return c.makeSpansForSingleColumnDatum(offset, opt.EqOp, tree.DBoolTrue, out)
-		}
-
 	case *memo.NotExpr:
 		// Support (NOT @1) as (@1 = FALSE) if @1 is boolean.
 		if c.colType(offset).Family() == types.BoolFamily && c.isIndexColumn(t.Input, offset) {
@@ -685,6 +721,14 @@ func (c *indexConstraintCtx) makeSpansForExpr(
 			return false
 		}
 	}
+	// Check for a comparison of a monotonic function of this column with a
+	// constant, e.g. (extract('epoch', @1) > 1.6e9).
+	if opt.IsConstValueOp(child1) {
+		tight, ok := c.makeSpansForMonotonic(offset, e.Op(), child0, memo.ExtractConstDatum(child1), out)
+		if ok {
+			return tight
+		}
+	}
 	// Check for tuple operations.
 	if child0.Op() == opt.TupleOp && child1.Op() == opt.TupleOp {
 		switch e.Op() {
@@ -803,7 +847,7 @@ func (c *indexConstraintCtx) makeSpansForAnd(
 			}
 			ofsC.IntersectWith(c.evalCtx, &exprConstraint)
 		}
-		out.Combine(c.evalCtx, &ofsC)
+		out.CombineWithSpanBuilder(c.evalCtx, &ofsC, c.spanBuilder)
 	}
 
 	// It's hard in the most general case to determine if the constraints are
@@ -832,40 +876,27 @@ func (c *indexConstraintCtx) makeSpansForOr(
 ) (tight bool) {
 	or := e.(*memo.OrExpr)
 	disjunctions := memo.CollectContiguousOrExprs(or)
-	return c.binaryMergeSpansForOr(offset, disjunctions, out)
-}
-
-// binaryMergeSpansForOr builds and merges the spans for disjunctions with a
-// given offset using divide and conquer.
-func (c *indexConstraintCtx) binaryMergeSpansForOr(
-	offset int, disjunctions []opt.ScalarExpr, out *constraint.Constraint,
-) (tight bool) {
-	size := len(disjunctions)
-	if size == 1 {
-		tight := c.makeSpansForExpr(offset, disjunctions[0], out)
-		return tight
-	}
-	mid := size / 2
-	left := disjunctions[:mid]
-	right := disjunctions[mid:]
-	var rightConstraint constraint.Constraint
 
-	tightLeft := c.binaryMergeSpansForOr(offset, left, out)
-	if out.IsUnconstrained() {
-		// If spans can't be generated for the left child, exit early.
-		c.unconstrained(offset, out)
-		return false
-	}
-	tightRight := c.binaryMergeSpansForOr(offset, right, &rightConstraint)
-	if rightConstraint.IsUnconstrained() {
-		// If spans can't be generated for the right child, exit early.
-		c.unconstrained(offset, out)
-		return false
+	// Build the spans for each disjunction, and merge them all at once.
+	constraints := make([]constraint.Constraint, len(disjunctions))
+	spans := make([]*constraint.Spans, len(disjunctions))
+	tight = true
+	for i := range disjunctions {
+		if !c.makeSpansForExpr(offset, disjunctions[i], &constraints[i]) {
+			tight = false
+		}
+		if constraints[i].IsUnconstrained() {
+			// If spans can't be generated for a disjunction, exit early.
+			c.unconstrained(offset, out)
+			return false
+		}
+		spans[i] = &constraints[i].Spans
 	}
-	out.UnionWith(c.evalCtx, &rightConstraint)
+	union := constraint.UnionSpans(&c.keyCtx[offset], spans...)
+	out.Init(&c.keyCtx[offset], &union)
 
-	// The OR is "tight" if both constraints were tight.
-	return tightLeft && tightRight
+	// The OR is "tight" if all the constraints were tight.
+	return tight
 }
 
 // getMaxSimplifyPrefix finds the longest prefix (maxSimplifyPrefix) such that
@@ -1071,7 +1102,13 @@ func (ic *Instance) Init(
 		ic.allFilters = append(ic.allFilters, optionalFilters...)
 	}
 	ic.indexConstraintCtx.init(columns, notNullCols, computedCols, evalCtx, factory)
+
+	// Only use a pooled SpanBuilder while calculating the spans; any keys
+	// constructed later (e.g. by RemainingFilters) are allocated individually.
+	ic.spanBuilder = constraint.NewSpanBuilder()
 	ic.tight = ic.makeSpansForExpr(0 /* offset */, &ic.allFilters, &ic.constraint)
+	ic.spanBuilder.Release()
+	ic.spanBuilder = nil
 
 	// Note: If consolidate is true, we only consolidate spans at the
 	// end; consolidating partial results can lead to worse spans, for example:
@@ -1158,6 +1195,10 @@ type indexConstraintCtx struct {
 	// We pre-initialize the KeyContext for each suffix of the index columns.
 	keyCtx []constraint.KeyContext
 
+	// spanBuilder is used to allocate keys while the spans are being
+	// calculated. It can be nil.
+	spanBuilder *constraint.SpanBuilder
+
 	factory *norm.Factory
 }
 
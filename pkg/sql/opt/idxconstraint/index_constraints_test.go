@@ -216,6 +216,24 @@ func BenchmarkIndexConstraints(b *testing.B) {
 		}
 		testCases = append(testCases, tc)
 	}
+	// Generate a few testcases with large tuple IN lists, as generated by ORMs.
+	// The tuples are in descending order, so they need to be sorted.
+	for _, n := range []int{10, 100, 1000} {
+		tc := testCase{
+			name:      fmt.Sprintf("tuple-in-%d", n),
+			vars:      "a int, b int",
+			indexInfo: "a, b",
+			expr:      "(a, b) IN (",
+		}
+		for i := n; i > 0; i-- {
+			if i < n {
+				tc.expr += ", "
+			}
+			tc.expr += fmt.Sprintf("(%d, %d)", i/10, i)
+		}
+		tc.expr += ")"
+		testCases = append(testCases, tc)
+	}
 
 	semaCtx := tree.MakeSemaContext()
 	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
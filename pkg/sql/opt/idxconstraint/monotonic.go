@@ -0,0 +1,208 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package idxconstraint
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// monotonicInverse describes how to invert a function f(x) which is
+// monotonically increasing in an index column x, given the constant values of
+// its other arguments. It allows a comparison of f(x) with a constant to be
+// turned into a comparison of x with a constant. For example,
+// (extract('epoch', @1) > 1.6e9) implies (@1 > '2020-09-13 12:26:39').
+//
+// Comparisons of + and - with constants are not handled here, since the
+// NormalizeCmpPlusConst, NormalizeCmpMinusConst and NormalizeCmpConstMinus
+// rules already move the constants to the other side of the comparison.
+type monotonicInverse struct {
+	// invert returns bounds such that lo < x < hi for any x for which
+	// f(x) = val. args contains the values of the arguments of f; the argument
+	// which is the index column is nil. ok is false if the function cannot be
+	// inverted for the given values.
+	invert func(
+		evalCtx *eval.Context, colType *types.T, args tree.Datums, val tree.Datum,
+	) (lo, hi tree.Datum, ok bool)
+
+	// domain, if set, returns the bounds of the values of x for which f is
+	// monotonic. Values of x outside of these bounds are never constrained.
+	domain func(colType *types.T) (lo, hi tree.Datum)
+}
+
+// builtinInverse describes a builtin function which is monotonic in one of its
+// arguments.
+type builtinInverse struct {
+	monotonicInverse
+	// colArg is the index of the argument which is the index column.
+	colArg int
+}
+
+// builtinInverses contains the builtin functions which are monotonic in one of
+// their arguments, keyed by function name.
+var builtinInverses = map[string]builtinInverse{
+	// extract('epoch', x) is the number of seconds since the Unix epoch as a
+	// float, so the inverse is only approximate.
+	"extract": {
+		monotonicInverse: monotonicInverse{
+			invert: invertExtractEpoch,
+			domain: extractEpochDomain,
+		},
+		colArg: 1,
+	},
+}
+
+// invertExtractEpoch inverts extract('epoch', x) for a TIMESTAMP or TIMESTAMPTZ
+// column x. The result of the function is rounded to a float, so the bounds
+// are widened by a second on each side.
+func invertExtractEpoch(
+	_ *eval.Context, colType *types.T, args tree.Datums, val tree.Datum,
+) (lo, hi tree.Datum, ok bool) {
+	element, ok := args[0].(*tree.DString)
+	if !ok || strings.ToLower(string(*element)) != "epoch" {
+		return nil, nil, false
+	}
+	v, ok := val.(*tree.DFloat)
+	if !ok || math.IsNaN(float64(*v)) || math.IsInf(float64(*v), 0) {
+		return nil, nil, false
+	}
+	secs, frac := math.Modf(float64(*v))
+	if secs < math.MinInt64/2 || secs > math.MaxInt64/2 {
+		return nil, nil, false
+	}
+	t := time.Unix(int64(secs), int64(frac*float64(time.Second)))
+	lo, err := makeTimestampDatum(colType, t.Add(-time.Second))
+	if err != nil {
+		return nil, nil, false
+	}
+	hi, err = makeTimestampDatum(colType, t.Add(time.Second))
+	if err != nil {
+		return nil, nil, false
+	}
+	return lo, hi, true
+}
+
+// extractEpochDomain returns the bounds of the timestamps for which
+// extract('epoch', x) is monotonic. The function is computed using the number
+// of nanoseconds since the Unix epoch, which overflows outside of these bounds.
+func extractEpochDomain(colType *types.T) (lo, hi tree.Datum) {
+	lo, err := makeTimestampDatum(colType, time.Unix(0, math.MinInt64).Add(time.Microsecond))
+	if err != nil {
+		return nil, nil
+	}
+	hi, err = makeTimestampDatum(colType, time.Unix(0, math.MaxInt64).Add(-time.Microsecond))
+	if err != nil {
+		return nil, nil
+	}
+	return lo, hi
+}
+
+// makeTimestampDatum returns a TIMESTAMP or TIMESTAMPTZ datum (depending on
+// the given type) for the given time.
+func makeTimestampDatum(typ *types.T, t time.Time) (tree.Datum, error) {
+	switch typ.Family() {
+	case types.TimestampFamily:
+		return tree.MakeDTimestamp(t.UTC(), time.Microsecond)
+	case types.TimestampTZFamily:
+		return tree.MakeDTimestampTZ(t.UTC(), time.Microsecond)
+	}
+	return nil, errors.AssertionFailedf("unexpected type %s", typ)
+}
+
+// findMonotonicInverse checks whether e is a function which is monotonic in
+// index column <offset>, with constant values for its other arguments. If so,
+// it returns the inverse of the function and the values of its arguments.
+func (c *indexConstraintCtx) findMonotonicInverse(
+	offset int, e opt.Expr,
+) (inv monotonicInverse, args tree.Datums, ok bool) {
+	t, ok := e.(*memo.FunctionExpr)
+	if !ok {
+		return monotonicInverse{}, nil, false
+	}
+	fn, ok := builtinInverses[t.Name]
+	if !ok || fn.colArg >= len(t.Args) || !c.isIndexColumn(t.Args[fn.colArg], offset) {
+		return monotonicInverse{}, nil, false
+	}
+	args = make(tree.Datums, len(t.Args))
+	for i, arg := range t.Args {
+		if i == fn.colArg {
+			continue
+		}
+		if !opt.IsConstValueOp(arg) {
+			return monotonicInverse{}, nil, false
+		}
+		args[i] = memo.ExtractConstDatum(arg)
+	}
+	return fn.monotonicInverse, args, true
+}
+
+// makeSpansForMonotonic creates spans for index column <offset> from a
+// comparison of a monotonic function of the column with a constant value, for
+// example (extract('epoch', @1) > 1.6e9). The spans are never tight, since the
+// inverse is approximate. ok is false if the expression is not such a
+// comparison, or if the function cannot be inverted for the given value.
+func (c *indexConstraintCtx) makeSpansForMonotonic(
+	offset int, op opt.Operator, e opt.Expr, val tree.Datum, out *constraint.Constraint,
+) (tight bool, ok bool) {
+	switch op {
+	case opt.EqOp, opt.LtOp, opt.LeOp, opt.GtOp, opt.GeOp:
+	default:
+		return false, false
+	}
+	if val == tree.DNull {
+		return false, false
+	}
+	inv, args, ok := c.findMonotonicInverse(offset, e)
+	if !ok {
+		return false, false
+	}
+	colType := c.colType(offset)
+	lo, hi, ok := inv.invert(c.evalCtx, colType, args, val)
+	if !ok {
+		return false, false
+	}
+	// The inverse is approximate, so the spans are not tight: lo < x for any x
+	// for which f(x) >= val, and x < hi for any x for which f(x) <= val.
+	switch op {
+	case opt.GtOp, opt.GeOp:
+		c.makeSpansForSingleColumnDatum(offset, opt.GtOp, lo, out)
+	case opt.LtOp, opt.LeOp:
+		c.makeSpansForSingleColumnDatum(offset, opt.LtOp, hi, out)
+	case opt.EqOp:
+		var other constraint.Constraint
+		c.makeSpansForSingleColumnDatum(offset, opt.GtOp, lo, out)
+		c.makeSpansForSingleColumnDatum(offset, opt.LtOp, hi, &other)
+		out.IntersectWith(c.evalCtx, &other)
+	}
+	if inv.domain != nil {
+		// Values outside of the domain of the function are not constrained.
+		domainLo, domainHi := inv.domain(colType)
+		if domainLo == nil {
+			c.unconstrained(offset, out)
+			return false, false
+		}
+		var other constraint.Constraint
+		c.makeSpansForSingleColumnDatum(offset, opt.LtOp, domainLo, &other)
+		out.UnionWith(c.evalCtx, &other)
+		c.makeSpansForSingleColumnDatum(offset, opt.GtOp, domainHi, &other)
+		out.UnionWith(c.evalCtx, &other)
+	}
+	return false, true
+}
@@ -529,7 +529,7 @@ func (j *jsonOrArrayFilterPlanner) extractJSONFetchValEqCondition(
 	}
 
 	// Collect a slice of keys from the fetch val expression.
-	var keys []string
+	var keys []tree.Datum
 	keys = j.collectKeys(keys, left)
 	if len(keys) == 0 {
 		return inverted.NonInvertedColExpression{}
@@ -544,9 +544,10 @@ func (j *jsonOrArrayFilterPlanner) extractJSONFetchValEqCondition(
 
 	// When the right side is an array or object, the InvertedExpression
 	// generated is not tight. We must indicate it is non-tight so an additional
-	// filter is added.
+	// filter is added. The same is true when the keys include array indexes,
+	// since the inverted index does not store the positions of array elements.
 	typ := val.JSON.Type()
-	if typ == json.ArrayJSONType || typ == json.ObjectJSONType {
+	if typ == json.ArrayJSONType || typ == json.ObjectJSONType || hasArrayIndex(keys) {
 		invertedExpr.SetNotTight()
 	}
 	return invertedExpr
@@ -580,12 +581,20 @@ func (j *jsonOrArrayFilterPlanner) extractJSONFetchValContainsCondition(
 	}
 
 	// Collect a slice of keys from the fetch val expression.
-	var keys []string
+	var keys []tree.Datum
 	keys = j.collectKeys(keys, left)
 	if len(keys) == 0 {
 		return inverted.NonInvertedColExpression{}
 	}
 
+	// The element at an array index could be contained by the given value
+	// while the rest of the array is not, so the array index keys cannot be
+	// used to constrain the index for ContainedBy expressions.
+	arrayIndex := hasArrayIndex(keys)
+	if containedBy && arrayIndex {
+		return inverted.NonInvertedColExpression{}
+	}
+
 	// Build a new JSON object with the collected keys and val.
 	obj := buildObject(keys, val.JSON)
 
@@ -613,15 +622,30 @@ func (j *jsonOrArrayFilterPlanner) extractJSONFetchValContainsCondition(
 			invertedExpr = inverted.Or(invertedExpr, expr)
 		}
 	}
+	if arrayIndex {
+		// The inverted index does not store the positions of array elements, so
+		// the expression matches rows that have the value at any position.
+		invertedExpr.SetNotTight()
+	}
 	return invertedExpr
 }
 
+// maxFetchValArrayIndexes is the maximum number of integer array indexes in a
+// chain of fetch val expressions for which an inverted expression is built.
+// Each array index wraps the value in another array, and since the inverted
+// index does not store the positions of array elements, the resulting
+// expression matches the value at any position of each array. This budget
+// keeps the false positive rate of the expression, and therefore the cost of
+// the additional filter, in check.
+const maxFetchValArrayIndexes = 3
+
 // collectKeys is called on fetch val expressions to the find corresponding
 // keys used to build a JSON object. It recursively traverses the fetch val
 // expressions and collects keys with which to build the InvertedExpression.
 // If it is not possible to build an inverted expression from the tree of fetch
 // val expressions, collectKeys returns nil for keys. If successful, the JSON
-// fetch value indexes are collected in keys. The keys are ordered by the
+// fetch value indexes are collected in keys. Each key is either a *tree.DString
+// object key or a *tree.DInt array index. The keys are ordered by the
 // outer-most fetch val index first. The outer-most fetch val index is the
 // right-most in the -> chain, for example (j->'a'->'b') is equivalent to
 // ((j->'a')->'b') and 'b' is the outer-most fetch val index.
@@ -633,23 +657,31 @@ func (j *jsonOrArrayFilterPlanner) extractJSONFetchValContainsCondition(
 // inner most JSON object keys.
 //
 // As an example, when left is (j->'a'->'b') and right is ('1'), the keys
-// {"b", "a"} are collected and the JSON object {"a": {"b": 1}} is built.
+// {"b", "a"} are collected and the JSON object {"a": {"b": 1}} is built. When
+// left is (j->'a'->0->'b'), the keys {"b", 0, "a"} are collected and the JSON
+// object {"a": [{"b": 1}]} is built.
 func (j *jsonOrArrayFilterPlanner) collectKeys(
-	currKeys []string, fetch *memo.FetchValExpr,
-) (keys []string) {
+	currKeys []tree.Datum, fetch *memo.FetchValExpr,
+) (keys []tree.Datum) {
 	// The right side of the fetch val expression, the Index field, must be
-	// a constant string. If not, then we cannot build an inverted
+	// a constant string or integer. If not, then we cannot build an inverted
 	// expression.
 	if !memo.CanExtractConstDatum(fetch.Index) {
 		return nil
 	}
-	key, ok := memo.ExtractConstDatum(fetch.Index).(*tree.DString)
-	if !ok {
+	key := memo.ExtractConstDatum(fetch.Index)
+	switch key.(type) {
+	case *tree.DString:
+	case *tree.DInt:
+		if countArrayIndexes(currKeys) >= maxFetchValArrayIndexes {
+			return nil
+		}
+	default:
 		return nil
 	}
 
 	// Append the key to the list of keys.
-	keys = append(currKeys, string(*key))
+	keys = append(currKeys, key)
 
 	// If the left side of the fetch val expression, the Json field, is a
 	// variable or expression corresponding to the index column, then we
@@ -667,6 +699,24 @@ func (j *jsonOrArrayFilterPlanner) collectKeys(
 	return nil
 }
 
+// countArrayIndexes returns the number of integer array indexes in the given
+// keys collected by collectKeys.
+func countArrayIndexes(keys []tree.Datum) int {
+	n := 0
+	for _, k := range keys {
+		if _, ok := k.(*tree.DInt); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// hasArrayIndex returns true if the given keys collected by collectKeys
+// include an integer array index.
+func hasArrayIndex(keys []tree.Datum) bool {
+	return countArrayIndexes(keys) > 0
+}
+
 // buildFetchContainmentObjects constructs new JSON objects with given keys and val.
 // The keys and val are extracted from a fetch val containment expression, and
 // the objects constructed depend on the value type and whether the expression
@@ -674,7 +724,7 @@ func (j *jsonOrArrayFilterPlanner) collectKeys(
 // {"a", "b"} as keys, "c" as val, and construct {"a": "b": ["c"]}.
 // An array of the constructed JSONs is returned.
 func buildFetchContainmentObjects(
-	keys []string, val json.JSON, containedBy bool,
+	keys []tree.Datum, val json.JSON, containedBy bool,
 ) ([]json.JSON, error) {
 	var objs []json.JSON
 	typ := val.Type()
@@ -738,17 +788,22 @@ func buildFetchContainmentObjects(
 //   {<keyN>: ... {<key1>: {key0: <val>}}}
 // Where the keys and val are extracted from a fetch val expression by the
 // caller. Note that key0 is the outer-most fetch val index, so the expression
-// j->'a'->'b' = 1 results in {"a": {"b": 1}}.
-func buildObject(keys []string, val json.JSON) json.JSON {
-	var obj json.JSON
+// j->'a'->'b' = 1 results in {"a": {"b": 1}}. Integer array indexes result in
+// single-element arrays, so the expression j->'a'->0 = 1 results in
+// {"a": [1]}.
+func buildObject(keys []tree.Datum, val json.JSON) json.JSON {
+	obj := val
 	for i := 0; i < len(keys); i++ {
-		b := json.NewObjectBuilder(1)
-		if i == 0 {
-			b.Add(keys[i], val)
-		} else {
-			b.Add(keys[i], obj)
+		switch key := keys[i].(type) {
+		case *tree.DString:
+			b := json.NewObjectBuilder(1)
+			b.Add(string(*key), obj)
+			obj = b.Build()
+		case *tree.DInt:
+			b := json.NewArrayBuilder(1)
+			b.Add(obj)
+			obj = b.Build()
 		}
-		obj = b.Build()
 	}
 	return obj
 }
@@ -460,10 +460,14 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			unique:   true,
 		},
 		{
-			// Integer indexes are not yet supported.
-			filters:  "j->0 = '1'",
-			indexOrd: jsonOrd,
-			ok:       false,
+			// Integer indexes are supported, but the positions of array elements
+			// are not stored in the index, so the expression is not tight.
+			filters:          "j->0 = '1'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: "j->0 = '1'",
 		},
 		{
 			// Arrays on the right side of the equality are supported.
@@ -504,8 +508,16 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			unique:   true,
 		},
 		{
-			// Integer indexes are not yet supported.
-			filters:  "j->0->'b' = '1'",
+			filters:          "j->0->'b' = '1'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: "j->0->'b' = '1'",
+		},
+		{
+			// Too many integer indexes.
+			filters:  "j->0->0->0->0 = '1'",
 			indexOrd: jsonOrd,
 			ok:       false,
 		},
@@ -620,6 +632,52 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			unique:           true,
 			remainingFilters: "j->'a'->'b' @> '{\"c\": [1, 2], \"d\": \"2\"}'",
 		},
+		{
+			// Contains with a fetch val is supported for integer indexes into
+			// arrays of objects.
+			filters:          `j->'a'->0 @> '{"b": 1}'`,
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: `j->'a'->0 @> '{"b": 1}'`,
+		},
+		{
+			filters:          `j->'a'->0 @> '{"b": 1, "c": [{"d": 2}]}'`,
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: `j->'a'->0 @> '{"b": 1, "c": [{"d": 2}]}'`,
+		},
+		{
+			filters:          `j->'a'->0->'b'->1 @> '[{"c": 1}, {"d": 2}]'`,
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           true,
+			remainingFilters: `j->'a'->0->'b'->1 @> '[{"c": 1}, {"d": 2}]'`,
+		},
+		{
+			filters:          `j->'a'->0 @> '1'`,
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: `j->'a'->0 @> '1'`,
+		},
+		{
+			// Too many integer indexes.
+			filters:  `j->0->1->2->3 @> '{"a": 1}'`,
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			// ContainedBy with a fetch val is not supported for integer indexes.
+			filters:  `j->'a'->0 <@ '{"b": 1}'`,
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
 		{
 			// ContainedBy is supported with a fetch val operator on the left.
 			filters:          `j->'a' <@ '1'`,
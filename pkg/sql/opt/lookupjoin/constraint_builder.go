@@ -321,6 +321,43 @@ func (b *ConstraintBuilder) Build(
 			filterOrdsToExclude.Add(endIdx)
 			foundLookupCols = true
 		}
+
+		// Try to find filters that constrain this index column to a range on
+		// expressions of input columns, e.g. y BETWEEN a - 10 AND a + 10. The
+		// expressions are projected on the input so that each input row yields a
+		// span over the index.
+		for !foundStart || !foundEnd {
+			op, bound, filterIdx, ok := b.findJoinDerivedRangeFilter(
+				allFilters, idxCol, idxColIsDesc, !foundStart, !foundEnd,
+			)
+			if !ok {
+				break
+			}
+			isStartBound := op == opt.GtOp || op == opt.GeOp
+			boundName := "lookup_join_end_col_@%d"
+			if isStartBound {
+				boundName = "lookup_join_start_col_@%d"
+			}
+			boundCol := b.md.AddColumn(fmt.Sprintf(boundName, idxCol), b.md.ColumnMeta(idxCol).Type)
+			inputProjections = append(inputProjections, b.f.ConstructProjectionsItem(bound, boundCol))
+			var boundFilter memo.FiltersItem
+			b.f.DisableOptimizationsTemporarily(func() {
+				// Disable normalization rules when constructing the lookup expression
+				// so that it does not get normalized into a non-canonical expression.
+				boundFilter = b.f.ConstructFiltersItem(b.f.DynamicConstruct(
+					op, b.f.ConstructVariable(idxCol), b.f.ConstructVariable(boundCol),
+				).(opt.ScalarExpr))
+			})
+			convertToLookupExpr()
+			lookupExpr = append(lookupExpr, boundFilter)
+			filterOrdsToExclude.Add(filterIdx)
+			foundLookupCols = true
+			if isStartBound {
+				foundStart = true
+			} else {
+				foundEnd = true
+			}
+		}
 		if foundStart && foundEnd {
 			// The column is constrained above and below by an inequality; no further
 			// expressions can be added to the lookup.
@@ -480,17 +517,9 @@ func (b *ConstraintBuilder) findJoinVariableRangeFilters(
 			op = opt.CommuteEqualityOrInequalityOp(op)
 		}
 		if idxColIsDesc && op == opt.LtOp {
-			// We have to ensure that any value from this column can always be
-			// advanced to the first value that orders immediately before it. This is
-			// only possible for a subset of types. We have already ensured that both
-			// sides of the inequality are of identical types, so it doesn't matter
-			// which one we check here.
-			typ := cond.Child(0).(*memo.VariableExpr).Typ
-			switch typ.Family() {
-			case types.BoolFamily, types.FloatFamily, types.INetFamily,
-				types.IntFamily, types.OidFamily, types.TimeFamily, types.TimeTZFamily,
-				types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily:
-			default:
+			// We have already ensured that both sides of the inequality are of
+			// identical types, so it doesn't matter which one we check here.
+			if !canUseExclusiveEndOnDescCol(cond.Child(0).(*memo.VariableExpr).Typ) {
 				continue
 			}
 		}
@@ -506,6 +535,76 @@ func (b *ConstraintBuilder) findJoinVariableRangeFilters(
 	return startIdx, endIdx, foundStart, foundEnd
 }
 
+// findJoinDerivedRangeFilter attempts to find an inequality filter that
+// constrains the given index column with an expression of input columns, e.g.
+// y >= a - 10. The lookup joiner can only build spans from input columns, so
+// the expression must be projected on the input; it is returned as bound, and
+// op is the operator of the filter normalized so that the index column is on
+// its left side. needStart and needEnd indicate whether the index column's
+// start and end bounds are still unconstrained respectively.
+func (b *ConstraintBuilder) findJoinDerivedRangeFilter(
+	filters memo.FiltersExpr, col opt.ColumnID, idxColIsDesc, needStart, needEnd bool,
+) (op opt.Operator, bound opt.ScalarExpr, filterIdx int, ok bool) {
+	colType := b.md.ColumnMeta(col).Type
+	for i := range filters {
+		cond := filters[i].Condition
+		op = cond.Op()
+		switch op {
+		case opt.LtOp, opt.LeOp, opt.GtOp, opt.GeOp:
+		default:
+			continue
+		}
+		left, right := cond.Child(0).(opt.ScalarExpr), cond.Child(1).(opt.ScalarExpr)
+		if v, ok := left.(*memo.VariableExpr); ok && v.Col == col {
+			bound = right
+		} else if v, ok := right.(*memo.VariableExpr); ok && v.Col == col {
+			bound = left
+			op = opt.CommuteEqualityOrInequalityOp(op)
+		} else {
+			continue
+		}
+		// Filters with variable or constant bounds are handled by
+		// findJoinVariableRangeFilters and findJoinConstantRangeFilter.
+		if _, ok := bound.(*memo.VariableExpr); ok || opt.IsConstValueOp(bound) {
+			continue
+		}
+		isStartBound := op == opt.GtOp || op == opt.GeOp
+		if (isStartBound && !needStart) || (!isStartBound && !needEnd) {
+			continue
+		}
+		if !bound.DataType().Identical(colType) {
+			continue
+		}
+		if idxColIsDesc && op == opt.LtOp && !canUseExclusiveEndOnDescCol(colType) {
+			continue
+		}
+		// The bound must only depend on the input row, and must evaluate to the
+		// same value as it would in the ON condition.
+		var sharedProps props.Shared
+		memo.BuildSharedProps(bound, &sharedProps, b.evalCtx)
+		if sharedProps.OuterCols.Empty() || !sharedProps.OuterCols.SubsetOf(b.leftCols) ||
+			sharedProps.HasSubquery || sharedProps.VolatilitySet.HasVolatile() {
+			continue
+		}
+		return op, bound, i, true
+	}
+	return 0, nil, -1, false
+}
+
+// canUseExclusiveEndOnDescCol returns true if a '<' filter on a descending
+// index column of the given type can be used in a lookup. The lookup joiner
+// must be able to advance any value of the column to the value that orders
+// immediately before it, which is only possible for a subset of types.
+func canUseExclusiveEndOnDescCol(typ *types.T) bool {
+	switch typ.Family() {
+	case types.BoolFamily, types.FloatFamily, types.INetFamily,
+		types.IntFamily, types.OidFamily, types.TimeFamily, types.TimeTZFamily,
+		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily:
+		return true
+	}
+	return false
+}
+
 // findJoinConstantRangeFilter tries to find a constant inequality range for this
 // column. If no such range filter can be found, rangeFilter is nil. If
 // remaining is non-nil, it should be appended to the RemainingFilters field of
@@ -271,6 +271,9 @@ func (b *logicalPropsBuilder) buildSelectProps(sel *SelectExpr, rel *props.Relat
 			break
 		}
 	}
+	if !isContradiction {
+		isContradiction = b.filtersProveEmpty(sel.Filters)
+	}
 	if isContradiction {
 		rel.Cardinality = props.ZeroCardinality
 	} else if rel.FuncDeps.HasMax1Row() {
@@ -2006,6 +2009,42 @@ func (b *logicalPropsBuilder) addFiltersToFuncDep(filters FiltersExpr, fdset *pr
 	}
 }
 
+// filtersProveEmpty returns true if the conjunction of the constraints of the
+// given filters is unsatisfiable, even though none of the constraints is a
+// contradiction on its own (e.g. (x, y) > (5, 1) AND x < 3). See
+// constraint.Set.ProvesEmpty.
+func (b *logicalPropsBuilder) filtersProveEmpty(filters FiltersExpr) bool {
+	if len(filters) <= 1 {
+		return false
+	}
+
+	// Intersection is expensive, so first do a quick check to rule out cases
+	// where each constraint refers to a different set of columns.
+	var cols opt.ColSet
+	possibleContradiction := false
+	for i := range filters {
+		if c := filters[i].ScalarProps().Constraints; c != nil {
+			s := c.ExtractCols()
+			if cols.Intersects(s) {
+				possibleContradiction = true
+				break
+			}
+			cols.UnionWith(s)
+		}
+	}
+	if !possibleContradiction {
+		return false
+	}
+
+	intersection := constraint.Unconstrained
+	for i := range filters {
+		if c := filters[i].ScalarProps().Constraints; c != nil {
+			intersection = intersection.Intersect(b.evalCtx, c)
+		}
+	}
+	return intersection.ProvesEmpty(b.evalCtx)
+}
+
 // updateCardinalityFromFilters determines whether a tight cardinality bound
 // can be determined from the filters, and updates the cardinality accordingly.
 // Specifically, it may be possible to determine a tight bound if the key
@@ -141,6 +141,9 @@ type Memo struct {
 	useHistograms                          bool
 	useMultiColStats                       bool
 	useNotVisibleIndex                     bool
+	maxConstraintSpans                     int64
+	propagateFKCascadeFilters              bool
+	inlineUDFs                             bool
 	localityOptimizedSearch                bool
 	safeUpdates                            bool
 	preferLookupJoinsForFKs                bool
@@ -193,6 +196,9 @@ func (m *Memo) Init(evalCtx *eval.Context) {
 		useHistograms:                          evalCtx.SessionData().OptimizerUseHistograms,
 		useMultiColStats:                       evalCtx.SessionData().OptimizerUseMultiColStats,
 		useNotVisibleIndex:                     evalCtx.SessionData().OptimizerUseNotVisibleIndexes,
+		maxConstraintSpans:                     evalCtx.SessionData().OptimizerMaxConstraintSpans,
+		propagateFKCascadeFilters:              evalCtx.SessionData().OptimizerPropagateFKCascadeFilters,
+		inlineUDFs:                             evalCtx.SessionData().OptimizerInlineUDFs,
 		localityOptimizedSearch:                evalCtx.SessionData().LocalityOptimizedSearch,
 		safeUpdates:                            evalCtx.SessionData().SafeUpdates,
 		preferLookupJoinsForFKs:                evalCtx.SessionData().PreferLookupJoinsForFKs,
@@ -329,6 +335,9 @@ func (m *Memo) IsStale(
 		m.useHistograms != evalCtx.SessionData().OptimizerUseHistograms ||
 		m.useMultiColStats != evalCtx.SessionData().OptimizerUseMultiColStats ||
 		m.useNotVisibleIndex != evalCtx.SessionData().OptimizerUseNotVisibleIndexes ||
+		m.maxConstraintSpans != evalCtx.SessionData().OptimizerMaxConstraintSpans ||
+		m.propagateFKCascadeFilters != evalCtx.SessionData().OptimizerPropagateFKCascadeFilters ||
+		m.inlineUDFs != evalCtx.SessionData().OptimizerInlineUDFs ||
 		m.localityOptimizedSearch != evalCtx.SessionData().LocalityOptimizedSearch ||
 		m.safeUpdates != evalCtx.SessionData().SafeUpdates ||
 		m.preferLookupJoinsForFKs != evalCtx.SessionData().PreferLookupJoinsForFKs ||
@@ -477,20 +486,25 @@ func (m *Memo) Detach() {
 
 	// Clear all column statistics from every relational expression in the memo.
 	// This is used to free up the potentially large amount of memory used by
-	// histograms.
-	var clearColStats func(parent opt.Expr)
-	clearColStats = func(parent opt.Expr) {
+	// histograms. For the same reason, scan constraints with many spans are
+	// stored in prefix-compressed form, since the memo may be kept around for a
+	// long time.
+	var detachExpr func(parent opt.Expr)
+	detachExpr = func(parent opt.Expr) {
 		for i, n := 0, parent.ChildCount(); i < n; i++ {
 			child := parent.Child(i)
-			clearColStats(child)
+			detachExpr(child)
 		}
 
 		switch t := parent.(type) {
 		case RelExpr:
 			t.Relational().Stats.ColStats = props.ColStatsMap{}
+			if scan, ok := t.(*ScanExpr); ok && scan.Constraint != nil {
+				scan.Constraint = scan.Constraint.Compressed()
+			}
 		}
 	}
-	clearColStats(m.RootExpr())
+	detachExpr(m.RootExpr())
 }
 
 // DisableCheckExpr disables expression validation performed by CheckExpr,
@@ -220,6 +220,24 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().OptimizerUseNotVisibleIndexes = false
 	notStale()
 
+	// Stale optimizer max constraint spans.
+	evalCtx.SessionData().OptimizerMaxConstraintSpans = 100
+	stale()
+	evalCtx.SessionData().OptimizerMaxConstraintSpans = 0
+	notStale()
+
+	// Stale FK cascade filter propagation enable.
+	evalCtx.SessionData().OptimizerPropagateFKCascadeFilters = true
+	stale()
+	evalCtx.SessionData().OptimizerPropagateFKCascadeFilters = false
+	notStale()
+
+	// Stale UDF inlining enable.
+	evalCtx.SessionData().OptimizerInlineUDFs = true
+	stale()
+	evalCtx.SessionData().OptimizerInlineUDFs = false
+	notStale()
+
 	// Stale locality optimized search enable.
 	evalCtx.SessionData().LocalityOptimizedSearch = true
 	stale()
@@ -966,12 +966,96 @@ func (sb *statisticsBuilder) constrainScan(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
-	corr := sb.correlationFromMultiColDistinctCounts(constrainedCols, scan, s)
-	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, scan, s, corr))
+	// The selectivity of the columns covered by a multi-column histogram is
+	// calculated from the histogram, rather than from the individual columns.
+	independentCols, independentHistCols := constrainedCols, histCols
+	if constraint != nil && pred == nil {
+		if mcCols, sel, ok := sb.selectivityFromMultiColHistogram(constraint, scan, relProps); ok {
+			s.ApplySelectivity(sel)
+			independentCols = constrainedCols.Difference(mcCols)
+			independentHistCols = histCols.Difference(mcCols)
+		}
+	}
+	corr := sb.correlationFromMultiColDistinctCounts(independentCols, scan, s)
+	s.ApplySelectivity(sb.selectivityFromConstrainedCols(independentCols, independentHistCols, scan, s, corr))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(numUnappliedConjuncts))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, notNullCols, constrainedCols))
 }
 
+// selectivityFromMultiColHistogram calculates the selectivity of the given
+// index constraint using a multi-column histogram on a prefix of its columns,
+// if one is available. It returns the columns of the histogram, whose
+// selectivity is accounted for by the returned selectivity, and ok=false if no
+// such histogram exists.
+//
+// Multi-column histograms are collected on the key columns of indexes, with
+// each value being the concatenation of the ascending key encodings of the
+// values of the columns. Unlike the product of the selectivities of
+// single-column histograms, they capture the correlation between the columns.
+func (sb *statisticsBuilder) selectivityFromMultiColHistogram(
+	c *constraint.Constraint, scan *ScanExpr, relProps *props.Relational,
+) (cols opt.ColSet, selectivity props.Selectivity, ok bool) {
+	if !sb.shouldUseHistogram(relProps) ||
+		!sb.evalCtx.SessionData().OptimizerUseHistograms ||
+		!sb.evalCtx.SessionData().OptimizerUseMultiColStats {
+		return opt.ColSet{}, props.OneSelectivity, false
+	}
+	// Only ascending columns can be matched with the encoded keys.
+	numCols := c.ConstrainedColumns(sb.evalCtx)
+	for i := 0; i < numCols; i++ {
+		if c.Columns.Get(i).Descending() {
+			numCols = i
+			break
+		}
+	}
+	if numCols < 2 {
+		return opt.ColSet{}, props.OneSelectivity, false
+	}
+
+	// Find the most recent statistic with a histogram on the longest prefix of
+	// the constrained columns. (Stats are ordered with most recent first.)
+	tab := sb.md.Table(scan.Table)
+	var stat cat.TableStatistic
+	for i, n := 0, tab.StatisticCount(); i < n; i++ {
+		s := tab.Statistic(i)
+		if s.IsForecast() && !sb.evalCtx.SessionData().OptimizerUseForecasts {
+			continue
+		}
+		if s.ColumnCount() < 2 || s.ColumnCount() > numCols || s.Histogram() == nil ||
+			s.HistogramType().Family() != types.BytesFamily {
+			continue
+		}
+		if stat != nil && s.ColumnCount() <= stat.ColumnCount() {
+			continue
+		}
+		matches := true
+		for j := 0; j < s.ColumnCount(); j++ {
+			if scan.Table.ColumnID(s.ColumnOrdinal(j)) != c.Columns.Get(j).ID() {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			stat = s
+		}
+	}
+	if stat == nil {
+		return opt.ColSet{}, props.OneSelectivity, false
+	}
+
+	var hist props.Histogram
+	hist.Init(sb.evalCtx, c.Columns.Get(0).ID(), stat.Histogram())
+	filtered, ok := hist.KeyFilter(c, stat.ColumnCount())
+	if !ok {
+		return opt.ColSet{}, props.OneSelectivity, false
+	}
+	for i := 0; i < stat.ColumnCount(); i++ {
+		cols.Add(c.Columns.Get(i).ID())
+	}
+	rowCount := max(float64(stat.RowCount()), 1)
+	return cols, props.MakeSelectivityFromFraction(filtered.ValuesCount(), rowCount), true
+}
+
 func (sb *statisticsBuilder) colStatScan(colSet opt.ColSet, scan *ScanExpr) *props.ColumnStatistic {
 	relProps := scan.Relational()
 	s := &relProps.Stats
@@ -13,6 +13,7 @@ package norm
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -22,6 +23,74 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// EliminatedJoinInputColMap returns a map from the columns of the eliminated
+// join input that are referenced by the given projections and passthrough
+// columns to equivalent columns of the kept input. A column of the eliminated
+// input is equivalent to a column of the kept input if the join filters
+// constrain them to be equal, and they have identical types without composite
+// key encodings. ok is false if any referenced column has no equivalent, or if
+// no column of the eliminated input is referenced at all (that case is handled
+// by EliminateJoinUnderProjectLeft and EliminateJoinUnderProjectRight).
+//
+// The caller must ensure that the join filters match all rows of the kept
+// input, so that the equalities hold in every row of the join output.
+func (c *CustomFuncs) EliminatedJoinInputColMap(
+	kept, eliminated memo.RelExpr,
+	on memo.FiltersExpr,
+	projections memo.ProjectionsExpr,
+	passthrough opt.ColSet,
+) (colMap opt.ColMap, ok bool) {
+	eliminatedCols := eliminated.Relational().OutputCols
+	refCols := passthrough.Intersection(eliminatedCols)
+	for i := range projections {
+		refCols.UnionWith(projections[i].ScalarProps().OuterCols.Intersection(eliminatedCols))
+	}
+	if refCols.Empty() {
+		return opt.ColMap{}, false
+	}
+
+	md := c.mem.Metadata()
+	keptEq, eliminatedEq, _ := memo.ExtractJoinEqualityColumns(
+		kept.Relational().OutputCols, eliminatedCols, on,
+	)
+	for i := range eliminatedEq {
+		if !refCols.Contains(eliminatedEq[i]) {
+			continue
+		}
+		typ := md.ColumnMeta(eliminatedEq[i]).Type
+		if !typ.Identical(md.ColumnMeta(keptEq[i]).Type) || colinfo.CanHaveCompositeKeyEncoding(typ) {
+			continue
+		}
+		colMap.Set(int(eliminatedEq[i]), int(keptEq[i]))
+		refCols.Remove(eliminatedEq[i])
+	}
+	return colMap, refCols.Empty()
+}
+
+// RemapProjectionsForEliminatedJoin remaps the columns of an eliminated join
+// input in the given projections to their equivalents in the kept input, as
+// given by colMap. Passthrough columns of the eliminated input are turned into
+// projections of their equivalent columns, so that the output columns of the
+// Project are unchanged.
+func (c *CustomFuncs) RemapProjectionsForEliminatedJoin(
+	projections memo.ProjectionsExpr, passthrough opt.ColSet, colMap opt.ColMap,
+) memo.ProjectionsExpr {
+	newProjections := make(memo.ProjectionsExpr, len(projections), len(projections)+colMap.Len())
+	for i := range projections {
+		newProjections[i] = c.f.ConstructProjectionsItem(
+			c.f.RemapCols(projections[i].Element, colMap), projections[i].Col,
+		)
+	}
+	passthrough.ForEach(func(col opt.ColumnID) {
+		if keptCol, ok := colMap.Get(int(col)); ok {
+			newProjections = append(newProjections, c.f.ConstructProjectionsItem(
+				c.f.ConstructVariable(opt.ColumnID(keptCol)), col,
+			))
+		}
+	})
+	return newProjections
+}
+
 // CanMergeProjections returns true if the outer Projections operator never
 // references any of the inner Projections columns. If true, then the outer does
 // not depend on the inner, and the two can be merged into a single set.
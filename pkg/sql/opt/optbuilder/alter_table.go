@@ -11,10 +11,15 @@
 package optbuilder
 
 import (
+	"fmt"
+
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/idxconstraint"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/partition"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -47,8 +52,13 @@ func (b *Builder) buildAlterTableSplit(split *tree.Split, inScope *scope) (outSc
 	// We don't allow the input statement to reference outer columns, so we
 	// pass a "blank" scope rather than inScope.
 	emptyScope := b.allocScope()
-	inputScope := b.buildStmt(split.Rows, colTypes, emptyScope)
-	checkInputColumns("SPLIT AT", inputScope, colNames, colTypes, 1)
+	var inputScope *scope
+	if split.Where != nil {
+		inputScope = b.buildSplitPointsFromPredicate(split.Where.Expr, index, &tn, colTypes)
+	} else {
+		inputScope = b.buildStmt(split.Rows, colTypes, emptyScope)
+		checkInputColumns("SPLIT AT", inputScope, colNames, colTypes, 1)
+	}
 
 	// Build the expiration scalar.
 	var expiration opt.ScalarExpr
@@ -87,6 +97,132 @@ func (b *Builder) buildAlterTableSplit(split *tree.Split, inScope *scope) (outSc
 	return outScope
 }
 
+// buildSplitPointsFromPredicate builds a VALUES expression with the split
+// points for an ALTER TABLE/INDEX .. SPLIT AT WHERE .. statement. The predicate
+// is converted into a constraint on the index columns, and a split point is
+// placed at the start and after the end of each span of the constraint. For
+// example, the predicate a IN (1, 5) OR a > 10 results in the spans
+//   [/1 - /1] [/5 - /5] [/11 - ]
+// and the split points /1, /2, /5, /6 and /11.
+func (b *Builder) buildSplitPointsFromPredicate(
+	pred tree.Expr, index cat.Index, tn *tree.TableName, colTypes []*types.T,
+) (outScope *scope) {
+	if index.IsInverted() {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"SPLIT AT WHERE is not supported for inverted index %q", index.Name(),
+		))
+	}
+
+	tabMeta := b.addTable(index.Table(), tn)
+	tableScope := b.allocScope()
+	tableScope.appendOrdinaryColumnsFromTable(tabMeta, &tabMeta.Alias)
+	filter := b.resolveAndBuildScalar(
+		pred,
+		types.Bool,
+		exprKindWhere,
+		tree.RejectSpecial,
+		tableScope,
+	)
+	filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)}
+
+	columns := make([]opt.OrderingColumn, len(colTypes))
+	var notNullCols opt.ColSet
+	for i := range columns {
+		col := index.Column(i)
+		colID := tabMeta.MetaID.ColumnID(col.Ordinal())
+		columns[i] = opt.MakeOrderingColumn(colID, col.Descending)
+		if !col.IsNullable() {
+			notNullCols.Add(colID)
+		}
+	}
+
+	var ic idxconstraint.Instance
+	ic.Init(
+		filters, nil /* optionalFilters */, columns, notNullCols, nil, /* computedCols */
+		true /* consolidate */, b.evalCtx, b.factory, partition.PrefixSorter{},
+	)
+	c := ic.Constraint()
+	if c.IsUnconstrained() {
+		panic(pgerror.Newf(pgcode.InvalidParameterValue,
+			"SPLIT AT WHERE predicate does not constrain the columns of index %q", index.Name(),
+		))
+	}
+
+	// Collect the split points. An exclusive start boundary is made inclusive
+	// where possible. An inclusive end boundary is moved to the key right after
+	// it; if that's not possible (e.g. for strings), the split point is omitted.
+	keyCtx := constraint.MakeKeyContext(&c.Columns, b.evalCtx)
+	var points []constraint.Key
+	for i, n := 0, c.Spans.Count(); i < n; i++ {
+		sp := c.Spans.Get(i)
+		if start := sp.StartKey(); !start.IsEmpty() {
+			if sp.StartBoundary() == constraint.ExcludeBoundary {
+				if next, ok := start.Next(&keyCtx); ok {
+					start = next
+				}
+			}
+			points = append(points, start)
+		}
+		if end := sp.EndKey(); !end.IsEmpty() {
+			if sp.EndBoundary() == constraint.IncludeBoundary {
+				var ok bool
+				if end, ok = end.Next(&keyCtx); !ok {
+					continue
+				}
+			}
+			points = append(points, end)
+		}
+	}
+
+	// All rows of the VALUES expression must have the same number of columns.
+	// Shorter split points are padded with NULLs, which sort before all other
+	// values on ascending columns. That doesn't hold for descending columns, so
+	// the split points are truncated before the first descending column which
+	// would need to be padded.
+	numCols := 0
+	for _, p := range points {
+		if p.Length() > numCols {
+			numCols = p.Length()
+		}
+	}
+	for _, p := range points {
+		for i := p.Length(); i < numCols; i++ {
+			if columns[i].Descending() {
+				numCols = i
+				break
+			}
+		}
+	}
+	if numCols == 0 {
+		numCols = 1
+	}
+
+	tupleTyp := types.MakeTuple(colTypes[:numCols])
+	rows := make(memo.ScalarListExpr, len(points))
+	for i, p := range points {
+		elems := make(memo.ScalarListExpr, numCols)
+		for j := range elems {
+			val := tree.Datum(tree.DNull)
+			if j < p.Length() {
+				val = p.Value(j)
+			}
+			elems[j] = b.factory.ConstructConstVal(val, colTypes[j])
+		}
+		rows[i] = b.factory.ConstructTuple(elems, tupleTyp)
+	}
+
+	outScope = b.allocScope()
+	for i := 0; i < numCols; i++ {
+		colName := scopeColName(tree.Name(fmt.Sprintf("column%d", i+1)))
+		b.synthesizeColumn(outScope, colName, colTypes[i], nil, nil /* scalar */)
+	}
+	outScope.expr = b.factory.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: colsToColList(outScope.cols),
+		ID:   b.factory.Metadata().NextUniqueID(),
+	})
+	return outScope
+}
+
 // buildAlterTableUnsplit builds an ALTER TABLE/INDEX .. UNSPLIT AT/ALL .. statement.
 func (b *Builder) buildAlterTableUnsplit(unsplit *tree.Unsplit, inScope *scope) (outScope *scope) {
 	flags := cat.Flags{
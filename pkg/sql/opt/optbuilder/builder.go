@@ -70,6 +70,11 @@ type Builder struct {
 	// This is used when re-preparing invalidated queries.
 	KeepPlaceholders bool
 
+	// IndexFlags is a control knob: if set, it maps table IDs to the index
+	// flags used when scanning the table in a FROM clause, unless the statement
+	// specifies its own index flags. This is used to apply plan pins.
+	IndexFlags map[cat.StableID]*tree.IndexFlags
+
 	// -- Results --
 	//
 	// These fields are set during the building process and can be used after
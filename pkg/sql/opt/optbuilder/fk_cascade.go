@@ -53,6 +53,9 @@ import (
 // Note that NULL values in the mutation input don't require any special
 // handling - they will be effectively ignored by the semi-join.
 //
+// The filters of the original mutation on the FK columns, if any, are
+// propagated to the scan of the child table (see cascadeParentFilters).
+//
 // See testdata/fk-on-delete-cascades for more examples.
 //
 type onDeleteCascadeBuilder struct {
@@ -61,17 +64,23 @@ type onDeleteCascadeBuilder struct {
 	// the mutated table (can be passed to mutatedTable.InboundForeignKey).
 	fkInboundOrdinal int
 	childTable       cat.Table
+
+	parentFilters cascadeParentFilters
 }
 
 var _ memo.CascadeBuilder = &onDeleteCascadeBuilder{}
 
 func newOnDeleteCascadeBuilder(
-	mutatedTable cat.Table, fkInboundOrdinal int, childTable cat.Table,
+	mutatedTable cat.Table,
+	fkInboundOrdinal int,
+	childTable cat.Table,
+	parentFilters cascadeParentFilters,
 ) *onDeleteCascadeBuilder {
 	return &onDeleteCascadeBuilder{
 		mutatedTable:     mutatedTable,
 		fkInboundOrdinal: fkInboundOrdinal,
 		childTable:       childTable,
+		parentFilters:    parentFilters,
 	}
 }
 
@@ -102,7 +111,7 @@ func (cb *onDeleteCascadeBuilder) Build(
 		// for each public table column, making it appropriate to set it as
 		// mb.fetchScope.
 		mb.fetchScope = b.buildDeleteCascadeMutationInput(
-			cb.childTable, &mb.alias, fk, binding, bindingProps, oldValues,
+			cb.childTable, &mb.alias, fk, binding, bindingProps, oldValues, cb.parentFilters,
 		)
 		mb.outScope = mb.fetchScope
 
@@ -309,20 +318,9 @@ func (cb *onDeleteFastCascadeBuilder) Build(
 		// Build the filters by copying the original filters and replacing all
 		// variable references.
 		if len(cb.origFilters) > 0 {
-			var replaceFn norm.ReplaceFunc
-			replaceFn = func(e opt.Expr) opt.Expr {
-				if v, ok := e.(*memo.VariableExpr); ok {
-					idx, found := cb.origFKCols.Find(v.Col)
-					if !found {
-						panic(errors.AssertionFailedf("non-FK variable in filter"))
-					}
-					tabOrd := fk.OriginColumnOrdinal(cb.childTable, idx)
-					col := mb.outScope.getColumnForTableOrdinal(tabOrd)
-					return b.factory.ConstructVariable(col.id)
-				}
-				return b.factory.CopyAndReplaceDefault(e, replaceFn)
-			}
-			filters = *replaceFn(&cb.origFilters).(*memo.FiltersExpr)
+			filters = b.remapCascadeParentFilters(
+				cb.origFilters, cb.origFKCols, fk, cb.childTable, mb.outScope,
+			)
 		}
 
 		// We have to filter out rows that have NULL values; add an IS NOT NULL
@@ -390,6 +388,9 @@ func (cb *onDeleteFastCascadeBuilder) Build(
 // Note that NULL values in the mutation input don't require any special
 // handling - they will be effectively ignored by the semi-join.
 //
+// The filters of the original mutation on the FK columns, if any, are
+// propagated to the scan of the child table (see cascadeParentFilters).
+//
 // See testdata/fk-on-delete-set-null and fk-on-delete-set-default for more
 // examples.
 //
@@ -402,18 +403,25 @@ type onDeleteSetBuilder struct {
 
 	// action is either SetNull or SetDefault.
 	action tree.ReferenceAction
+
+	parentFilters cascadeParentFilters
 }
 
 var _ memo.CascadeBuilder = &onDeleteSetBuilder{}
 
 func newOnDeleteSetBuilder(
-	mutatedTable cat.Table, fkInboundOrdinal int, childTable cat.Table, action tree.ReferenceAction,
+	mutatedTable cat.Table,
+	fkInboundOrdinal int,
+	childTable cat.Table,
+	action tree.ReferenceAction,
+	parentFilters cascadeParentFilters,
 ) *onDeleteSetBuilder {
 	return &onDeleteSetBuilder{
 		mutatedTable:     mutatedTable,
 		fkInboundOrdinal: fkInboundOrdinal,
 		childTable:       childTable,
 		action:           action,
+		parentFilters:    parentFilters,
 	}
 }
 
@@ -444,7 +452,7 @@ func (cb *onDeleteSetBuilder) Build(
 		// for each public table column, making it appropriate to set it as
 		// mb.fetchScope.
 		mb.fetchScope = b.buildDeleteCascadeMutationInput(
-			cb.childTable, &mb.alias, fk, binding, bindingProps, oldValues,
+			cb.childTable, &mb.alias, fk, binding, bindingProps, oldValues, cb.parentFilters,
 		)
 		mb.outScope = mb.fetchScope
 
@@ -504,6 +512,8 @@ func (cb *onDeleteSetBuilder) Build(
 // Note that NULL values in the mutation input don't require any special
 // handling - they will be effectively ignored by the semi-join.
 //
+// If there are parentFilters, they are applied to the scan of the child table.
+//
 func (b *Builder) buildDeleteCascadeMutationInput(
 	childTable cat.Table,
 	childTableAlias *tree.TableName,
@@ -511,6 +521,7 @@ func (b *Builder) buildDeleteCascadeMutationInput(
 	binding opt.WithID,
 	bindingProps *props.Relational,
 	oldValues opt.ColList,
+	parentFilters cascadeParentFilters,
 ) (outScope *scope) {
 	outScope = b.buildScan(
 		b.addTable(childTable, childTableAlias),
@@ -524,6 +535,7 @@ func (b *Builder) buildDeleteCascadeMutationInput(
 		b.allocScope(),
 		true, /* disableNotVisibleIndex */
 	)
+	b.addCascadeParentFilters(outScope, parentFilters, fk, childTable)
 
 	numFKCols := fk.ColumnCount()
 	if len(oldValues) != numFKCols {
@@ -607,6 +619,10 @@ func (b *Builder) buildDeleteCascadeMutationInput(
 // Note that NULL "old" values in the mutation input don't require any special
 // handling - they will be effectively ignored by the join.
 //
+// The filters of the original mutation on the old values of the FK columns, if
+// any, are propagated to the scan of the child table (see
+// cascadeParentFilters).
+//
 // See testdata/fk-on-update-* for more examples.
 //
 type onUpdateCascadeBuilder struct {
@@ -617,18 +633,25 @@ type onUpdateCascadeBuilder struct {
 	childTable       cat.Table
 
 	action tree.ReferenceAction
+
+	parentFilters cascadeParentFilters
 }
 
 var _ memo.CascadeBuilder = &onUpdateCascadeBuilder{}
 
 func newOnUpdateCascadeBuilder(
-	mutatedTable cat.Table, fkInboundOrdinal int, childTable cat.Table, action tree.ReferenceAction,
+	mutatedTable cat.Table,
+	fkInboundOrdinal int,
+	childTable cat.Table,
+	action tree.ReferenceAction,
+	parentFilters cascadeParentFilters,
 ) *onUpdateCascadeBuilder {
 	return &onUpdateCascadeBuilder{
 		mutatedTable:     mutatedTable,
 		fkInboundOrdinal: fkInboundOrdinal,
 		childTable:       childTable,
 		action:           action,
+		parentFilters:    parentFilters,
 	}
 }
 
@@ -655,7 +678,7 @@ func (cb *onUpdateCascadeBuilder) Build(
 
 		// Build a join of the table with the mutation input.
 		mb.outScope = b.buildUpdateCascadeMutationInput(
-			cb.childTable, &mb.alias, fk, binding, bindingProps, oldValues, newValues,
+			cb.childTable, &mb.alias, fk, binding, bindingProps, oldValues, newValues, cb.parentFilters,
 		)
 
 		// The scope created by b.buildUpdateCascadeMutationInput has the table
@@ -740,6 +763,8 @@ func (cb *onUpdateCascadeBuilder) Build(
 // inner-join anyway. This reasoning is very similar to that of FK checks for
 // Upserts (see buildFKChecksForUpsert).
 //
+// If there are parentFilters, they are applied to the scan of the child table.
+//
 func (b *Builder) buildUpdateCascadeMutationInput(
 	childTable cat.Table,
 	childTableAlias *tree.TableName,
@@ -748,6 +773,7 @@ func (b *Builder) buildUpdateCascadeMutationInput(
 	bindingProps *props.Relational,
 	oldValues opt.ColList,
 	newValues opt.ColList,
+	parentFilters cascadeParentFilters,
 ) (outScope *scope) {
 	outScope = b.buildScan(
 		b.addTable(childTable, childTableAlias),
@@ -761,6 +787,7 @@ func (b *Builder) buildUpdateCascadeMutationInput(
 		b.allocScope(),
 		true, /* disableNotVisibleIndex */
 	)
+	b.addCascadeParentFilters(outScope, parentFilters, fk, childTable)
 
 	numFKCols := fk.ColumnCount()
 	if len(oldValues) != numFKCols || len(newValues) != numFKCols {
@@ -885,6 +912,126 @@ func (b *Builder) buildUpdateCascadeMutationInput(
 	return outScope
 }
 
+// cascadeParentFilters contains the filters of a mutation on the parent table
+// that only reference the (old values of the) FK columns. The child rows
+// modified by a cascade have FK values equal to those of the mutated parent
+// rows, so they satisfy these filters as well. Applying the filters to the scan
+// of the child table allows the optimizer to constrain the scan instead of
+// reading the entire child table, even though the filters are redundant with
+// the join against the mutation input.
+type cascadeParentFilters struct {
+	filters memo.FiltersExpr
+	// fkCols are the parent table columns (from the metadata of the original
+	// mutation) which are referenced by the filters; they map 1-to-1 to the FK
+	// columns.
+	fkCols opt.ColList
+}
+
+// makeCascadeParentFilters returns the filters that can be propagated from the
+// mutation input to the child table scan of a cascade for the given FK. The
+// oldValues columns map 1-to-1 to the FK columns.
+//
+// The filters are found in the Select operators at the top of the mutation
+// input, possibly under Project and Limit operators; these don't affect the
+// filters' validity since they only remove rows or add columns.
+func (mb *mutationBuilder) makeCascadeParentFilters(
+	fk cat.ForeignKeyConstraint, childTable cat.Table, oldValues opt.ColList,
+) cascadeParentFilters {
+	if !mb.b.evalCtx.SessionData().OptimizerPropagateFKCascadeFilters {
+		return cascadeParentFilters{}
+	}
+	// A filter can only be transferred if equal values in the parent and child
+	// columns are indistinguishable.
+	for i := range oldValues {
+		childCol := childTable.Column(fk.OriginColumnOrdinal(childTable, i))
+		if !mb.md.ColumnMeta(oldValues[i]).Type.Identical(childCol.DatumType()) {
+			return cascadeParentFilters{}
+		}
+	}
+	fkColSet := oldValues.ToSet()
+	var filters memo.FiltersExpr
+	// addConjuncts adds the conjuncts of the given condition that only
+	// reference FK columns to filters.
+	var addConjuncts func(cond opt.ScalarExpr)
+	addConjuncts = func(cond opt.ScalarExpr) {
+		if and, ok := cond.(*memo.AndExpr); ok {
+			addConjuncts(and.Left)
+			addConjuncts(and.Right)
+			return
+		}
+		var p props.Shared
+		memo.BuildSharedProps(cond, &p, mb.b.evalCtx)
+		if p.OuterCols.Empty() || !p.OuterCols.SubsetOf(fkColSet) ||
+			p.VolatilitySet.HasVolatile() || p.HasSubquery ||
+			memo.CanBeCompositeSensitive(mb.md, cond) {
+			return
+		}
+		filters = append(filters, mb.b.factory.ConstructFiltersItem(cond))
+	}
+	for e := mb.outScope.expr; e != nil; {
+		switch t := e.(type) {
+		case *memo.ProjectExpr:
+			e = t.Input
+		case *memo.LimitExpr:
+			e = t.Input
+		case *memo.SelectExpr:
+			for i := range t.Filters {
+				addConjuncts(t.Filters[i].Condition)
+			}
+			e = t.Input
+		default:
+			e = nil
+		}
+	}
+	if len(filters) == 0 {
+		return cascadeParentFilters{}
+	}
+	return cascadeParentFilters{filters: filters, fkCols: oldValues}
+}
+
+// addCascadeParentFilters applies the given parent filters, if any, to the
+// scan of the child table in the given scope.
+func (b *Builder) addCascadeParentFilters(
+	childScope *scope,
+	parentFilters cascadeParentFilters,
+	fk cat.ForeignKeyConstraint,
+	childTable cat.Table,
+) {
+	if len(parentFilters.filters) == 0 {
+		return
+	}
+	filters := b.remapCascadeParentFilters(
+		parentFilters.filters, parentFilters.fkCols, fk, childTable, childScope,
+	)
+	childScope.expr = b.factory.ConstructSelect(childScope.expr, filters)
+}
+
+// remapCascadeParentFilters copies filters that only reference the given
+// parent FK columns, replacing the references with the corresponding FK
+// columns of the child table in the given scope.
+func (b *Builder) remapCascadeParentFilters(
+	filters memo.FiltersExpr,
+	fkCols opt.ColList,
+	fk cat.ForeignKeyConstraint,
+	childTable cat.Table,
+	childScope *scope,
+) memo.FiltersExpr {
+	var replaceFn norm.ReplaceFunc
+	replaceFn = func(e opt.Expr) opt.Expr {
+		if v, ok := e.(*memo.VariableExpr); ok {
+			idx, found := fkCols.Find(v.Col)
+			if !found {
+				panic(errors.AssertionFailedf("non-FK variable in filter"))
+			}
+			tabOrd := fk.OriginColumnOrdinal(childTable, idx)
+			col := childScope.getColumnForTableOrdinal(tabOrd)
+			return b.factory.ConstructVariable(col.id)
+		}
+		return b.factory.CopyAndReplaceDefault(e, replaceFn)
+	}
+	return *replaceFn(&filters).(*memo.FiltersExpr)
+}
+
 // buildCascadeHelper contains boilerplate for CascadeBuilder.Build
 // implementations. It creates a Builder, sets up panic-to-error conversion,
 // and executes the given function.
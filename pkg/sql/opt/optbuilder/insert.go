@@ -324,7 +324,22 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		// Build the final upsert statement, including any returned expressions.
 		mb.buildUpsert(returning)
 
-	// Case 4: INSERT..ON CONFLICT..DO UPDATE statement.
+	// Case 4: INSERT..ON CONFLICT..DO UPDATE statement that is equivalent to an
+	// UPSERT statement which does not need to fetch existing rows.
+	case mb.setBlindUpsertCols(ins.OnConflict):
+		// Ensure that the input is distinct on the primary key columns, so that
+		// duplicate input rows result in an error, just as they would if
+		// existing rows were fetched. Ignore any ordering requested by the input,
+		// as in buildInputForUpsert.
+		mb.outScope.ordering = nil
+		mb.buildDistinctOnForArbiter(
+			nil /* insertColScope */, mb.primaryKeyOrds(), nil /* partialArbiterDistinctCol */, duplicateUpsertErrText,
+		)
+
+		// Build the final upsert statement, including any returned expressions.
+		mb.buildUpsert(returning)
+
+	// Case 5: INSERT..ON CONFLICT..DO UPDATE statement.
 	default:
 		// Left-join each input row to the target table, using the conflict columns
 		// as the join condition.
@@ -356,11 +371,13 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //   4. Each update value is the same as the corresponding insert value.
 //   5. There are no inbound foreign keys containing non-key columns.
 //
-// TODO(andyk): The fast path is currently only enabled when the UPSERT alias
-// is explicitly selected by the user. It's possible to fast path some queries
-// of the form INSERT ... ON CONFLICT, but the utility is low and there are lots
-// of edge cases (that caused real correctness bugs #13437 #13962). As a result,
-// this support was removed and needs to re-enabled. See #14482.
+// The fast path is enabled when the UPSERT alias is explicitly selected by the
+// user, or for INSERT ... ON CONFLICT statements that are equivalent to an
+// UPSERT (see setBlindUpsertCols).
+//
+// TODO(andyk): It's possible to fast path more queries of the form INSERT ...
+// ON CONFLICT, but there are lots of edge cases (that caused real correctness
+// bugs #13437 #13962). See #14482.
 func (mb *mutationBuilder) needExistingRows() bool {
 	if mb.tab.DeletableIndexCount() > 1 {
 		return true
@@ -412,6 +429,94 @@ func (mb *mutationBuilder) needExistingRows() bool {
 	return false
 }
 
+// setBlindUpsertCols sets the list of columns to be updated in case of conflict
+// and returns true if the given INSERT..ON CONFLICT..DO UPDATE clause has the
+// same effect as an UPSERT which can be executed without fetching existing
+// rows (see needExistingRows). This is the case when:
+//
+//   1. The conflict columns are exactly the primary key columns.
+//   2. There is no WHERE clause or arbiter predicate.
+//   3. Each SET expression has the form "col = excluded.col".
+//
+// For example, the following statement can be executed with blind Puts:
+//
+//   CREATE TABLE kv (k INT PRIMARY KEY, v INT)
+//   INSERT INTO kv VALUES (1, 2), (3, 4) ON CONFLICT (k) DO UPDATE SET v = excluded.v
+//
+// If false is returned, the update columns are left unset.
+func (mb *mutationBuilder) setBlindUpsertCols(onConflict *tree.OnConflict) bool {
+	if onConflict.DoNothing || onConflict.Where != nil || onConflict.ArbiterPredicate != nil ||
+		onConflict.Constraint != "" || len(onConflict.Columns) == 0 {
+		return false
+	}
+
+	// References to "excluded" are ambiguous if the target table has the same
+	// name. Leave it to the general case to report the error.
+	if mb.alias.ObjectName == "excluded" {
+		return false
+	}
+
+	// #1: The conflict columns must be the primary key columns.
+	var conflictOrds util.FastIntSet
+	for _, name := range onConflict.Columns {
+		ord := findPublicTableColumnByName(mb.tab, name)
+		if ord == -1 {
+			return false
+		}
+		conflictOrds.Add(ord)
+	}
+	if !conflictOrds.Equals(mb.primaryKeyOrds()) {
+		return false
+	}
+
+	// #3: Each SET expression must set a column to its excluded value.
+	var updateOrds util.FastIntSet
+	for _, expr := range onConflict.Exprs {
+		if expr.Tuple || len(expr.Names) != 1 {
+			return false
+		}
+		name, ok := expr.Expr.(*tree.UnresolvedName)
+		if !ok || name.Star || name.NumParts != 2 ||
+			name.Parts[1] != "excluded" || tree.Name(name.Parts[0]) != expr.Names[0] {
+			return false
+		}
+		// Leave the validation of SET expressions that target key, computed,
+		// mutation or system columns to the general case.
+		ord := findPublicTableColumnByName(mb.tab, expr.Names[0])
+		if ord == -1 || updateOrds.Contains(ord) || conflictOrds.Contains(ord) {
+			return false
+		}
+		if col := mb.tab.Column(ord); col.IsComputed() || col.IsMutation() || col.Kind() != cat.Ordinary {
+			return false
+		}
+		updateOrds.Add(ord)
+	}
+
+	// The statement is now equivalent to an UPSERT of the SET columns.
+	for ord, ok := updateOrds.Next(0); ok; ord, ok = updateOrds.Next(ord + 1) {
+		mb.updateColIDs[ord] = mb.insertColIDs[ord]
+	}
+	if mb.needExistingRows() {
+		for i := range mb.updateColIDs {
+			mb.updateColIDs[i] = 0
+		}
+		return false
+	}
+	return true
+}
+
+// primaryKeyOrds returns the ordinals of the primary key columns of the target
+// table, excluding any implicit partitioning columns.
+func (mb *mutationBuilder) primaryKeyOrds() util.FastIntSet {
+	var ords util.FastIntSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	skipCols := primaryIndex.ImplicitPartitioningColumnCount()
+	for i, n := skipCols, primaryIndex.KeyColumnCount(); i < n; i++ {
+		ords.Add(primaryIndex.Column(i).Ordinal())
+	}
+	return ords
+}
+
 // addTargetNamedColsForInsert adds a list of user-specified column names to the
 // list of table columns that are the target of the Insert operation.
 func (mb *mutationBuilder) addTargetNamedColsForInsert(names tree.NameList) {
@@ -131,6 +131,11 @@ func (mb *mutationBuilder) buildFKChecksAndCascadesForDelete() {
 		//    there are any "orphaned" rows in the child table.
 		if a := h.fk.DeleteReferenceAction(); a != tree.Restrict && a != tree.NoAction {
 			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+			cols := make(opt.ColList, len(h.tabOrdinals))
+			for i, tabOrd := range h.tabOrdinals {
+				cols[i] = mb.fetchColIDs[tabOrd]
+			}
+
 			var builder memo.CascadeBuilder
 			switch a {
 			case tree.Cascade:
@@ -141,19 +146,19 @@ func (mb *mutationBuilder) buildFKChecksAndCascadesForDelete() {
 				)
 				if !ok {
 					mb.ensureWithID()
-					builder = newOnDeleteCascadeBuilder(mb.tab, i, h.otherTab)
+					builder = newOnDeleteCascadeBuilder(
+						mb.tab, i, h.otherTab, mb.makeCascadeParentFilters(h.fk, h.otherTab, cols),
+					)
 				}
 			case tree.SetNull, tree.SetDefault:
 				mb.ensureWithID()
-				builder = newOnDeleteSetBuilder(mb.tab, i, h.otherTab, a)
+				builder = newOnDeleteSetBuilder(
+					mb.tab, i, h.otherTab, a, mb.makeCascadeParentFilters(h.fk, h.otherTab, cols),
+				)
 			default:
 				panic(errors.AssertionFailedf("unhandled action type %s", a))
 			}
 
-			cols := make(opt.ColList, len(h.tabOrdinals))
-			for i, tabOrd := range h.tabOrdinals {
-				cols[i] = mb.fetchColIDs[tabOrd]
-			}
 			mb.cascades = append(mb.cascades, memo.FKCascade{
 				FKName:    h.fk.Name(),
 				Builder:   builder,
@@ -280,7 +285,6 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 		if a := h.fk.UpdateReferenceAction(); a != tree.Restrict && a != tree.NoAction {
 			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
 			mb.ensureWithID()
-			builder := newOnUpdateCascadeBuilder(mb.tab, i, h.otherTab, a)
 
 			oldCols := make(opt.ColList, len(h.tabOrdinals))
 			newCols := make(opt.ColList, len(h.tabOrdinals))
@@ -294,6 +298,9 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 				oldCols[i] = fetchColID
 				newCols[i] = updateColID
 			}
+			builder := newOnUpdateCascadeBuilder(
+				mb.tab, i, h.otherTab, a, mb.makeCascadeParentFilters(h.fk, h.otherTab, oldCols),
+			)
 			mb.cascades = append(mb.cascades, memo.FKCascade{
 				FKName:    h.fk.Name(),
 				Builder:   builder,
@@ -398,7 +405,10 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 		if a := h.fk.UpdateReferenceAction(); a != tree.Restrict && a != tree.NoAction {
 			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
 			mb.ensureWithID()
-			builder := newOnUpdateCascadeBuilder(mb.tab, i, h.otherTab, a)
+			// The filters of an upsert don't apply to the rows being updated.
+			builder := newOnUpdateCascadeBuilder(
+				mb.tab, i, h.otherTab, a, cascadeParentFilters{},
+			)
 
 			oldCols := make(opt.ColList, len(h.tabOrdinals))
 			newCols := make(opt.ColList, len(h.tabOrdinals))
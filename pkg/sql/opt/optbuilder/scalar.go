@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -29,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treebin"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -655,14 +657,24 @@ func (b *Builder) buildUDF(
 
 	// Build an expression for each statement in the function body.
 	rels := make(memo.RelListExpr, len(stmts))
+	var stmtScope *scope
 	for i := range stmts {
-		stmtScope := b.buildStmt(stmts[i].AST, nil /* desiredTypes */, bodyScope)
+		stmtScope = b.buildStmt(stmts[i].AST, nil /* desiredTypes */, bodyScope)
 		rels[i] = memo.RelRequiredPropsExpr{
 			RelExpr:   stmtScope.expr,
 			PhysProps: stmtScope.makePhysicalProps(),
 		}
 	}
 
+	// Inline the body if it is a single statement that computes a scalar
+	// expression, like a view, so that the optimizer can use the expression for
+	// constraint derivation and index selection.
+	if len(stmts) == 1 && b.evalCtx.SessionData().OptimizerInlineUDFs {
+		if inlined := b.maybeInlineUDF(o, input, argCols, stmtScope, f.ResolvedType()); inlined != nil {
+			return b.finishBuildScalar(f, inlined, inScope, outScope, outCol)
+		}
+	}
+
 	out = b.factory.ConstructUDF(
 		input,
 		&memo.UDFPrivate{
@@ -677,6 +689,110 @@ func (b *Builder) buildUDF(
 	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
 }
 
+// maybeInlineUDF returns a scalar expression equivalent to the invocation of a
+// UDF with the given overload and input, or nil if the UDF cannot be inlined.
+// stmtScope is the scope of the only statement in the function body.
+//
+// A UDF can be inlined if it is not volatile and its body is a SELECT without a
+// FROM clause, e.g. SELECT i + 1, which is the case for most functions that
+// compute a value from their arguments. The result expression of the body is
+// returned with references to the arguments replaced by the input expressions.
+// Since the inputs can be referenced any number of times by the body, they
+// must not be volatile. If the function is strict, the expression is wrapped
+// in a CASE that returns NULL if any of the inputs are NULL.
+func (b *Builder) maybeInlineUDF(
+	o *tree.Overload,
+	input memo.ScalarListExpr,
+	argCols opt.ColList,
+	stmtScope *scope,
+	typ *types.T,
+) opt.ScalarExpr {
+	if o.Volatility == volatility.Volatile || len(stmtScope.cols) != 1 {
+		return nil
+	}
+	project, ok := stmtScope.expr.(*memo.ProjectExpr)
+	if !ok {
+		return nil
+	}
+	if values, ok := project.Input.(*memo.ValuesExpr); !ok || len(values.Rows) != 1 || len(values.Cols) != 0 {
+		return nil
+	}
+
+	// Find the expression for the result column of the body, which is either a
+	// projection or a reference to an argument.
+	var body opt.ScalarExpr
+	resultCol := stmtScope.cols[0].id
+	for i := range project.Projections {
+		if project.Projections[i].Col == resultCol {
+			body = project.Projections[i].Element
+			break
+		}
+	}
+	if body == nil {
+		if _, ok := argCols.Find(resultCol); !ok {
+			return nil
+		}
+		body = b.factory.ConstructVariable(resultCol)
+	}
+	if !body.DataType().Identical(typ) {
+		return nil
+	}
+
+	// The body must not be more volatile than the function, so that inlining it
+	// doesn't allow the optimizer to perform transformations that are invalid
+	// for the body.
+	var p props.Shared
+	memo.BuildSharedProps(body, &p, b.evalCtx)
+	if p.VolatilitySet.HasVolatile() || p.HasSubquery ||
+		(o.Volatility <= volatility.Immutable && p.VolatilitySet.HasStable()) {
+		return nil
+	}
+	for i := range input {
+		var inputProps props.Shared
+		memo.BuildSharedProps(input[i], &inputProps, b.evalCtx)
+		if inputProps.VolatilitySet.HasVolatile() || inputProps.HasSubquery {
+			return nil
+		}
+	}
+
+	// Replace the references to the arguments with the input expressions.
+	var replaceFn norm.ReplaceFunc
+	replaceFn = func(e opt.Expr) opt.Expr {
+		if v, ok := e.(*memo.VariableExpr); ok {
+			if ord, ok := argCols.Find(v.Col); ok {
+				return input[ord]
+			}
+		}
+		return b.factory.CopyAndReplaceDefault(e, replaceFn)
+	}
+	inlined := replaceFn(body).(opt.ScalarExpr)
+
+	if !o.CalledOnNullInput && len(input) > 0 {
+		// Build the expression:
+		//
+		//   CASE WHEN input[0] IS NULL OR input[1] IS NULL ... THEN NULL
+		//   ELSE <inlined> END
+		//
+		var anyNull opt.ScalarExpr
+		for i := range input {
+			isNull := b.factory.ConstructIs(input[i], memo.NullSingleton)
+			if anyNull == nil {
+				anyNull = isNull
+			} else {
+				anyNull = b.factory.ConstructOr(anyNull, isNull)
+			}
+		}
+		inlined = b.factory.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{
+				b.factory.ConstructWhen(anyNull, b.factory.ConstructNull(typ)),
+			},
+			inlined,
+		)
+	}
+	return inlined
+}
+
 // buildRangeCond builds a RANGE clause as a simpler expression. Examples:
 // x BETWEEN a AND b                ->  x >= a AND x <= b
 // x NOT BETWEEN a AND b            ->  NOT (x >= a AND x <= b)
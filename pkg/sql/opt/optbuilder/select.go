@@ -118,6 +118,9 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
+			if indexFlags == nil {
+				indexFlags = b.IndexFlags[t.ID()]
+			}
 			return b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
@@ -223,6 +226,9 @@ func (b *Builder) buildDataSource(
 
 		switch t := ds.(type) {
 		case cat.Table:
+			if indexFlags == nil {
+				indexFlags = b.IndexFlags[t.ID()]
+			}
 			outScope = b.buildScanFromTableRef(t, source, indexFlags, locking, inScope)
 		case cat.View:
 			if source.Columns != nil {
@@ -743,16 +749,11 @@ func (b *Builder) addCheckConstraintsForTable(tabMeta *opt.TableMeta) {
 		b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
 			condition = b.buildScalar(texpr, tableScope, nil, nil, nil)
 		})
-		// Check constraints that are guaranteed to not evaluate to NULL
-		// are the only ones converted into filters. This is because a NULL
-		// constraint is interpreted as passing, whereas a NULL filter is not.
-		if memo.ExprIsNeverNull(condition, notNullCols) {
-			// Check if the expression contains non-immutable operators.
-			var sharedProps props.Shared
-			memo.BuildSharedProps(condition, &sharedProps, b.evalCtx)
-			if !sharedProps.VolatilitySet.HasStable() && !sharedProps.VolatilitySet.HasVolatile() {
-				filters = append(filters, b.factory.ConstructFiltersItem(condition))
-			}
+		// Check if the expression contains non-immutable operators.
+		var sharedProps props.Shared
+		memo.BuildSharedProps(condition, &sharedProps, b.evalCtx)
+		if !sharedProps.VolatilitySet.HasStable() && !sharedProps.VolatilitySet.HasVolatile() {
+			filters = b.appendCheckConstraintFilters(filters, condition, notNullCols)
 		}
 	}
 	if len(filters) > 0 {
@@ -760,6 +761,53 @@ func (b *Builder) addCheckConstraintsForTable(tabMeta *opt.TableMeta) {
 	}
 }
 
+// appendCheckConstraintFilters appends the filters derived from the given
+// check constraint condition to filters, and returns the result.
+//
+// Check constraints that are guaranteed to not evaluate to NULL are converted
+// into filters as-is. Other check constraints cannot be converted directly,
+// because a NULL constraint is interpreted as passing, whereas a NULL filter is
+// not. Instead, the condition is split into conjuncts, which are known not to
+// evaluate to false when the constraint passes. A conjunct that can only
+// evaluate to NULL when one of the columns it references is NULL is converted
+// into a filter that also allows these columns to be NULL. For example, the
+// check constraint:
+//
+//   CHECK (region IN ('us', 'eu') AND shard >= 0)
+//
+// on the nullable region and shard columns yields the filters:
+//
+//   region IN ('us', 'eu') OR region IS NULL
+//   shard >= 0 OR shard IS NULL
+//
+// Conjuncts that can evaluate to NULL for other reasons are ignored.
+func (b *Builder) appendCheckConstraintFilters(
+	filters memo.FiltersExpr, condition opt.ScalarExpr, notNullCols opt.ColSet,
+) memo.FiltersExpr {
+	if memo.ExprIsNeverNull(condition, notNullCols) {
+		return append(filters, b.factory.ConstructFiltersItem(condition))
+	}
+	if r, ok := condition.(*memo.RangeExpr); ok {
+		condition = r.And
+	}
+	if and, ok := condition.(*memo.AndExpr); ok {
+		filters = b.appendCheckConstraintFilters(filters, and.Left, notNullCols)
+		return b.appendCheckConstraintFilters(filters, and.Right, notNullCols)
+	}
+	var sharedProps props.Shared
+	memo.BuildSharedProps(condition, &sharedProps, b.evalCtx)
+	nullableCols := sharedProps.OuterCols.Difference(notNullCols)
+	if nullableCols.Empty() || !memo.ExprIsNeverNull(condition, notNullCols.Union(nullableCols)) {
+		return filters
+	}
+	nullableCols.ForEach(func(col opt.ColumnID) {
+		condition = b.factory.ConstructOr(
+			condition, b.factory.ConstructIs(b.factory.ConstructVariable(col), memo.NullSingleton),
+		)
+	})
+	return append(filters, b.factory.ConstructFiltersItem(condition))
+}
+
 // addComputedColsForTable finds all computed columns in the given table and
 // caches them in the table metadata as scalar expressions. These expressions
 // are used as "known truths" about table data. Any columns for which the
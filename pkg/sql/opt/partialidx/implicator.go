@@ -56,6 +56,13 @@ import (
 //   OR-expr A => AND-expr B iff:   A => each of B's children
 //   OR-expr A => OR-expr B iff:    each of A's children => any of B's children
 //
+// If implication cannot be proven with the logic above, constraints are built
+// for the filters and the predicate as a whole, and implication is proven if
+// the spans of the predicate's constraints contain the spans of the filters'
+// constraints. This proves implication of disjunctive predicates that are not
+// implied by any one of their disjuncts, such as "a < 10 OR a > 8" which is
+// implied by "a > 5 AND a < 15".
+//
 // II. Remaining Filters
 //
 // The remaining filters that are returned upon a proof of implication are
@@ -194,6 +201,15 @@ func (im *Implicator) FiltersImplyPredicate(
 		return remainingFilters, true
 	}
 
+	// If implication could not be proven by walking the expressions, attempt
+	// to prove it by comparing the constraints of the filters and the predicate
+	// as a whole. No expressions are removed from the remaining filters in this
+	// case, because the filters are not guaranteed to be equivalent to the
+	// predicate.
+	if im.filtersImplyPredicateByConstraints(filters, pred) {
+		return filters, true
+	}
+
 	return nil, false
 }
 
@@ -590,6 +606,75 @@ func (im *Implicator) atomImpliesAtom(
 	return false
 }
 
+// filtersImplyPredicateByConstraints returns true if the constraints built for
+// the conjunction of the filters are contained by the constraints built for the
+// conjunction of the predicate. For example, it proves that:
+//
+//   a > 5 AND a < 15
+//   =>
+//   a < 10 OR a > 8
+//
+// The constraints of the predicate must be tight, so that every row within them
+// satisfies the predicate. The constraints of the filters do not need to be
+// tight, because every row that satisfies the filters is within them.
+//
+// Constraint sets are conjunctions of constraints, so the predicate's set
+// contains the filters' set if each of the predicate's constraints contains one
+// of the filters' constraints over the same leading columns.
+func (im *Implicator) filtersImplyPredicateByConstraints(
+	filters memo.FiltersExpr, pred memo.FiltersExpr,
+) bool {
+	predSet, predTight := im.filtersConstraints(pred)
+	if !predTight {
+		return false
+	}
+	eSet, _ := im.filtersConstraints(filters)
+
+	// A contradiction implies all predicates, and no filters other than a
+	// contradiction imply a contradiction. See atomImpliesAtom.
+	if eSet == constraint.Contradiction {
+		return true
+	}
+	if predSet == constraint.Contradiction {
+		return false
+	}
+
+	for i, n := 0, predSet.Length(); i < n; i++ {
+		predConstraint := predSet.Constraint(i)
+		contained := false
+		for j, m := 0, eSet.Length(); j < m; j++ {
+			eConstraint := eSet.Constraint(j)
+			if predConstraint.Columns.IsPrefixOf(&eConstraint.Columns) &&
+				predConstraint.Contains(im.evalCtx, eConstraint) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			return false
+		}
+	}
+	return true
+}
+
+// filtersConstraints returns the constraint set for the conjunction of the
+// given filters and whether the set is tight. The constraints built for each
+// FiltersItem are cached.
+func (im *Implicator) filtersConstraints(filters memo.FiltersExpr) (_ *constraint.Set, tight bool) {
+	set, tight := constraint.Unconstrained, true
+	for i := range filters {
+		cond := filters[i].Condition
+		c, condTight, ok := im.fetchConstraint(cond)
+		if !ok {
+			c, condTight = memo.BuildConstraints(cond, im.md, im.evalCtx)
+			im.cacheConstraint(cond, c, condTight)
+		}
+		set = set.Intersect(im.evalCtx, c)
+		tight = tight && condTight
+	}
+	return set, tight || set == constraint.Contradiction
+}
+
 // twoVarComparisonImpliesTwoVarComparison returns true if pred contains e,
 // where both expressions are comparisons (=, <, >, <=, >=, !=) of two
 // variables. If either expressions is not a comparison of two variables, this
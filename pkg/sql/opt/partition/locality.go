@@ -50,6 +50,11 @@ func (pr Prefix) String() string {
 	return buf.String()
 }
 
+// PartitionName returns the name of the partition the Prefix belongs to.
+func (pr Prefix) PartitionName() string {
+	return pr.partitionName
+}
+
 // PrefixSorter sorts prefixes (which are wrapped in Prefix structs) so
 // that longer prefixes are ordered first and within each group of equal-length
 // prefixes so that they are ordered by value.
@@ -385,6 +385,141 @@ func makeSpanFromInvertedSpan(invSpan inverted.Span) *constraint.Span {
 	return &span
 }
 
+// KeyFilter filters a multi-column histogram according to the given
+// constraint, and returns a new histogram with the results. The histogram
+// must have been collected on the first numCols columns of c, with each value
+// being the concatenation of the ascending key encodings of the values of
+// those columns. Spans of c are truncated to their first numCols columns. ok
+// is false if c cannot filter the histogram, which is the case if any of the
+// first numCols columns of c is descending, or if any of the values cannot be
+// encoded.
+func (h *Histogram) KeyFilter(c *constraint.Constraint, numCols int) (_ *Histogram, ok bool) {
+	if c.Columns.Count() < numCols {
+		return nil, false
+	}
+	for i := 0; i < numCols; i++ {
+		if c.Columns.Get(i).Descending() {
+			return nil, false
+		}
+	}
+
+	// Convert the spans of c into spans over the encoded keys. Since the keys
+	// of the spans are encoded in ascending order, the converted spans are
+	// sorted by their start keys, and they only overlap if their keys were
+	// truncated. Overlapping spans are merged here, since filter expects the
+	// spans to be disjoint.
+	spans := make([]constraint.Span, 0, c.Spans.Count())
+	for i, n := 0, c.Spans.Count(); i < n; i++ {
+		span := c.Spans.Get(i)
+		start, startTruncated, err := encodeKeyPrefix(span.StartKey(), numCols)
+		if err != nil {
+			return nil, false
+		}
+		end, endTruncated, err := encodeKeyPrefix(span.EndKey(), numCols)
+		if err != nil {
+			return nil, false
+		}
+		// Spans over the encoded keys always include their start key and
+		// exclude their end key, unless they are unbounded.
+		if span.StartBoundary() == constraint.ExcludeBoundary && !startTruncated && len(start) > 0 {
+			if start = encodedKeyPrefixEnd(start); start == nil {
+				// No key is greater than the start key.
+				continue
+			}
+		}
+		if len(end) > 0 && (span.EndBoundary() == constraint.IncludeBoundary || endTruncated) {
+			end = encodedKeyPrefixEnd(end)
+		}
+		if end != nil && bytes.Compare(start, end) >= 0 {
+			continue
+		}
+		if k := len(spans); k > 0 {
+			prev := spans[k-1].EndKey()
+			if prev.IsEmpty() {
+				continue
+			}
+			if bytes.Compare(start, []byte(*prev.Value(0).(*tree.DBytes))) <= 0 {
+				// The span overlaps or is adjacent to the previous one, so extend
+				// the previous span.
+				prevStart := spans[k-1].StartKey()
+				if end == nil {
+					spans[k-1].Init(
+						prevStart, constraint.IncludeBoundary, constraint.EmptyKey, constraint.IncludeBoundary,
+					)
+				} else if bytes.Compare(end, []byte(*prev.Value(0).(*tree.DBytes))) > 0 {
+					spans[k-1].Init(
+						prevStart, constraint.IncludeBoundary,
+						constraint.MakeKey(tree.NewDBytes(tree.DBytes(end))), constraint.ExcludeBoundary,
+					)
+				}
+				continue
+			}
+		}
+		var newSpan constraint.Span
+		// The statistics use the Bytes type for the encoded key, so we use
+		// DBytes here.
+		startKey := constraint.MakeKey(tree.NewDBytes(tree.DBytes(start)))
+		if end == nil {
+			newSpan.Init(startKey, constraint.IncludeBoundary, constraint.EmptyKey, constraint.IncludeBoundary)
+		} else {
+			newSpan.Init(
+				startKey, constraint.IncludeBoundary,
+				constraint.MakeKey(tree.NewDBytes(tree.DBytes(end))), constraint.ExcludeBoundary,
+			)
+		}
+		spans = append(spans, newSpan)
+	}
+
+	var columns constraint.Columns
+	columns.InitSingle(opt.MakeOrderingColumn(h.col, false /* desc */))
+	return h.filter(
+		len(spans),
+		func(idx int) *constraint.Span {
+			return &spans[idx]
+		},
+		false, /* desc */
+		0,     /* exactPrefix */
+		0,     /* colOffset */
+		nil,   /* prefix */
+		columns,
+	), true
+}
+
+// encodeKeyPrefix returns the concatenation of the ascending key encodings of
+// the first numCols values of the given key, and whether the key was
+// truncated to do so. It returns nil for an empty key, which is unbounded.
+func encodeKeyPrefix(key constraint.Key, numCols int) (_ []byte, truncated bool, _ error) {
+	if key.IsEmpty() {
+		return nil, false, nil
+	}
+	n := key.Length()
+	if n > numCols {
+		n, truncated = numCols, true
+	}
+	enc := []byte{}
+	for i := 0; i < n; i++ {
+		var err error
+		if enc, err = keyside.Encode(enc, key.Value(i), encoding.Ascending); err != nil {
+			return nil, false, err
+		}
+	}
+	return enc, truncated, nil
+}
+
+// encodedKeyPrefixEnd returns the first encoded key that does not have the
+// given key as a prefix, like roachpb.Key.PrefixEnd. It returns nil if there
+// is no such key.
+func encodedKeyPrefixEnd(b []byte) []byte {
+	end := append([]byte(nil), b...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
 func (h *Histogram) getNextLowerBound(currentUpperBound tree.Datum) tree.Datum {
 	nextLowerBound, ok := currentUpperBound.Next(h.evalCtx)
 	if !ok {
@@ -578,6 +713,7 @@ func makeSpanFromBucket(iter *histogramIter, prefix []tree.Datum) (span constrai
 func getFilteredBucket(
 	iter *histogramIter, keyCtx *constraint.KeyContext, filteredSpan *constraint.Span, colOffset int,
 ) *cat.HistogramBucket {
+	filteredSpan = makeInclusiveSpan(keyCtx, filteredSpan, iter.b.UpperBound.ResolvedType())
 	spanLowerBound := filteredSpan.StartKey().Value(colOffset)
 	spanUpperBound := filteredSpan.EndKey().Value(colOffset)
 	bucketLowerBound := iter.lb
@@ -675,6 +811,37 @@ func getFilteredBucket(
 	}
 }
 
+// makeInclusiveSpan returns an equivalent version of the given span with
+// inclusive boundaries, if the span has exclusive boundaries on a column of
+// integer or date type. The range size calculations in getFilteredBucket assume
+// that the boundaries are inclusive, so treating an exclusive boundary as
+// inclusive would overestimate the size of the filtered bucket. For example,
+// for an integer column the span (/2 - /10) is equivalent to [/3 - /9].
+//
+// If the span has no exclusive boundaries, the type is not supported, or the
+// span would become empty (e.g., (/4 - /5)), the original span is returned.
+func makeInclusiveSpan(
+	keyCtx *constraint.KeyContext, sp *constraint.Span, typ *types.T,
+) *constraint.Span {
+	if sp.StartBoundary() == constraint.IncludeBoundary &&
+		sp.EndBoundary() == constraint.IncludeBoundary {
+		return sp
+	}
+	switch typ.Family() {
+	case types.IntFamily, types.DateFamily:
+	default:
+		return sp
+	}
+	inclusive := *sp
+	inclusive.PreferInclusive(keyCtx)
+	if inclusive.StartKey().Compare(
+		keyCtx, inclusive.EndKey(), constraint.ExtendLow, constraint.ExtendHigh,
+	) > 0 {
+		return sp
+	}
+	return &inclusive
+}
+
 // getRangesBeforeAndAfter returns the size of the before and after ranges based
 // on the lower and upper bounds provided. If swap is true, the upper and lower
 // bounds of both ranges are swapped. Returns ok=true if these range sizes are
@@ -332,6 +332,57 @@ func TestHistogram(t *testing.T) {
 	}
 }
 
+func TestHistogramKeyFilter(t *testing.T) {
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+
+	// The histogram is on the encoded keys of columns 1 and 2.
+	key := func(a, b int) tree.Datum {
+		enc, _, err := encodeKeyPrefix(
+			constraint.MakeCompositeKey(tree.NewDInt(tree.DInt(a)), tree.NewDInt(tree.DInt(b))), 2,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tree.NewDBytes(tree.DBytes(enc))
+	}
+	histData := []cat.HistogramBucket{
+		{NumRange: 0, DistinctRange: 0, NumEq: 2, UpperBound: key(1, 1)},
+		{NumRange: 0, DistinctRange: 0, NumEq: 3, UpperBound: key(1, 2)},
+		{NumRange: 0, DistinctRange: 0, NumEq: 4, UpperBound: key(2, 1)},
+		{NumRange: 0, DistinctRange: 0, NumEq: 1, UpperBound: key(3, 5)},
+	}
+	h := &Histogram{}
+	h.Init(&evalCtx, opt.ColumnID(1), histData)
+
+	testData := []struct {
+		constraint string
+		ok         bool
+		count      float64
+	}{
+		{constraint: "/1/2: [/1 - /1]", ok: true, count: 5},
+		{constraint: "/1/2: [/1/2 - /2/1]", ok: true, count: 7},
+		{constraint: "/1/2: (/1 - ]", ok: true, count: 5},
+		{constraint: "/1/2: [ - /1/1]", ok: true, count: 2},
+		{constraint: "/1/2: [/1/2 - /1/2] [/3/5 - /3/5]", ok: true, count: 4},
+		{constraint: "/1/2/3: [/2/1/7 - /2/1/9]", ok: true, count: 4},
+		{constraint: "/1/2/3: [/1/1/7 - /1/1/7] [/1/1/9 - /1/2/3]", ok: true, count: 5},
+		{constraint: "/1/-2: [/1/2 - /1/1]", ok: false},
+	}
+	for _, tc := range testData {
+		c := constraint.ParseConstraint(&evalCtx, tc.constraint)
+		filtered, ok := h.KeyFilter(&c, 2 /* numCols */)
+		if ok != tc.ok {
+			t.Fatalf("for constraint %s, expected ok=%v but found %v", tc.constraint, tc.ok, ok)
+		}
+		if ok && filtered.ValuesCount() != tc.count {
+			t.Fatalf(
+				"for constraint %s, expected count %f but found %f",
+				tc.constraint, tc.count, filtered.ValuesCount(),
+			)
+		}
+	}
+}
+
 func TestFilterBucket(t *testing.T) {
 	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
 	keyCtx := constraint.KeyContext{EvalCtx: &evalCtx}
@@ -432,6 +483,22 @@ func TestFilterBucket(t *testing.T) {
 				span:     "[/10 - /10]",
 				expected: &cat.HistogramBucket{NumEq: 5, NumRange: 0, DistinctRange: 0, UpperBound: tree.NewDInt(10)},
 			},
+			{
+				span:     "(/2 - /10]",
+				expected: &cat.HistogramBucket{NumEq: 5, NumRange: 7, DistinctRange: 7, UpperBound: tree.NewDInt(10)},
+			},
+			{
+				span:     "[/0 - /5)",
+				expected: &cat.HistogramBucket{NumEq: 1, NumRange: 4, DistinctRange: 4, UpperBound: tree.NewDInt(4)},
+			},
+			{
+				span:     "[/2 - /10)",
+				expected: &cat.HistogramBucket{NumEq: 1, NumRange: 7, DistinctRange: 7, UpperBound: tree.NewDInt(9)},
+			},
+			{
+				span:     "(/4 - /6)",
+				expected: &cat.HistogramBucket{NumEq: 1, NumRange: 0, DistinctRange: 0, UpperBound: tree.NewDInt(5)},
+			},
 			{
 				span:    "[/20 - /30]",
 				isError: true,
@@ -541,6 +608,14 @@ func TestFilterBucket(t *testing.T) {
 				span:     "[/2019-07-05 - /2019-08-01]",
 				expected: &cat.HistogramBucket{NumEq: 1, NumRange: 54, DistinctRange: 27, UpperBound: upperBound},
 			},
+			{
+				span:     "(/2019-07-04 - /2019-08-01]",
+				expected: &cat.HistogramBucket{NumEq: 1, NumRange: 54, DistinctRange: 27, UpperBound: upperBound},
+			},
+			{
+				span:     "[/2019-07-01 - /2019-07-03)",
+				expected: &cat.HistogramBucket{NumEq: 2, NumRange: 2, DistinctRange: 1, UpperBound: ub1},
+			},
 		}
 
 		runTest(h, testData, 0 /* colOffset */, types.DateFamily)
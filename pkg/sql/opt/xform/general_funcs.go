@@ -144,13 +144,13 @@ func (c *CustomFuncs) remapJoinColsInScalarExpr(
 }
 
 // checkConstraintFilters generates all filters that we can derive from the
-// check constraints. These are constraints that have been validated and are
-// non-nullable. We only use non-nullable check constraints because they
-// behave differently from filters on NULL. Check constraints are satisfied
-// when their expression evaluates to NULL, while filters are not.
+// check constraints. These are constraints that have been validated. Check
+// constraints behave differently from filters on NULL: check constraints are
+// satisfied when their expression evaluates to NULL, while filters are not.
 //
 // For example, the check constraint a > 1 is satisfied if a is NULL but the
-// equivalent filter a > 1 is not.
+// equivalent filter a > 1 is not. If a is nullable, the derived filter is
+// a > 1 OR a IS NULL instead. See Builder.addCheckConstraintsForTable.
 //
 // These filters do not really filter any rows, they are rather facts or
 // guarantees about the data but treating them as filters may allow some
@@ -196,6 +196,89 @@ func (c *CustomFuncs) checkConstraintFilters(tabID opt.TableID) memo.FiltersExpr
 	return filters[:len(filters):len(filters)]
 }
 
+// maxCheckConstraintValues is the maximum number of distinct values of a column
+// for which checkConstraintValuesFilter builds a filter.
+const maxCheckConstraintValues = 32
+
+// checkConstraintValuesFilter returns a filter that enumerates the values of
+// the given column allowed by the given filters, which are usually the check
+// constraint filters of the table. It returns ok=false if the filters do not
+// restrict the column to a small set of discrete values, or if the filters
+// already enumerate the values of the column.
+//
+// For example, CHECK (shard >= 0 AND shard < 4) yields the filter:
+//
+//   shard IN (0, 1, 2, 3)
+//
+// Unlike a range, a list of values can be combined with filters on the
+// following index columns to build tight index constraints when the query does
+// not filter on the column.
+func (c *CustomFuncs) checkConstraintValuesFilter(
+	filters memo.FiltersExpr, col opt.ColumnID,
+) (_ memo.FiltersItem, ok bool) {
+	cs := constraint.Unconstrained
+	for i := range filters {
+		if !filters[i].ScalarProps().OuterCols.Contains(col) {
+			continue
+		}
+		if cons := filters[i].ScalarProps().Constraints; cons != nil {
+			cs = cs.Intersect(c.e.evalCtx, cons)
+		}
+	}
+	if cs == constraint.Contradiction {
+		return memo.FiltersItem{}, false
+	}
+	var colConstraint *constraint.Constraint
+	for i, n := 0, cs.Length(); i < n; i++ {
+		if cons := cs.Constraint(i); cons.Columns.Count() == 1 && cons.Columns.Get(0).ID() == col {
+			colConstraint = cons
+			break
+		}
+	}
+	if colConstraint == nil {
+		return memo.FiltersItem{}, false
+	}
+
+	// Count the values of the column without enumerating them, so that large
+	// ranges are cheap to reject.
+	keyCtx := constraint.MakeKeyContext(&colConstraint.Columns, c.e.evalCtx)
+	var count int64
+	singleKeys := true
+	for i, n := 0, colConstraint.Spans.Count(); i < n; i++ {
+		span := colConstraint.Spans.Get(i)
+		keyCount, ok := span.KeyCount(&keyCtx, 1 /* prefixLength */)
+		if !ok {
+			return memo.FiltersItem{}, false
+		}
+		count += keyCount
+		if count > maxCheckConstraintValues {
+			return memo.FiltersItem{}, false
+		}
+		singleKeys = singleKeys && span.HasSingleKey(c.e.evalCtx)
+	}
+	if singleKeys {
+		// The filters already enumerate the values of the column.
+		return memo.FiltersItem{}, false
+	}
+
+	values, hasNull, ok := colConstraint.CollectFirstColumnValues(c.e.evalCtx)
+	if !ok {
+		return memo.FiltersItem{}, false
+	}
+	if !hasNull {
+		return c.e.f.ConstructConstFilter(col, values), true
+	}
+	// The constraint column is ascending, so NULL is the first value.
+	values = values[1:]
+	if len(values) == 0 {
+		return memo.FiltersItem{}, false
+	}
+	return c.e.f.ConstructFiltersItem(c.e.f.ConstructOr(
+		c.e.f.ConstructConstFilter(col, values).Condition,
+		c.e.f.ConstructIs(c.e.f.ConstructVariable(col), memo.NullSingleton),
+	)), true
+}
+
 func (c *CustomFuncs) initIdxConstraintForIndex(
 	requiredFilters, optionalFilters memo.FiltersExpr, tabID opt.TableID, indexOrd int,
 ) (ic *idxconstraint.Instance) {
@@ -0,0 +1,80 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package xform
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+)
+
+// IndexConstraint is the constraint derived for an index from the filters
+// applied to a scan of its table.
+type IndexConstraint struct {
+	Table opt.TableID
+	Index cat.IndexOrdinal
+
+	// Constraint is the constraint derived from the filters, or nil if the
+	// filters do not constrain the index.
+	Constraint *constraint.Constraint
+}
+
+// DeriveIndexConstraints derives the index constraints for every Select
+// directly over a Scan in the normalized memo. The constraints are derived the
+// same way as in the GenerateConstrainedScans exploration rule, but no
+// alternative plans are explored. This is intended for debugging why a filter
+// does not constrain an index.
+//
+// Partial indexes whose predicates are not implied by the filters and inverted
+// indexes are not reported.
+func (o *Optimizer) DeriveIndexConstraints() []IndexConstraint {
+	c := &o.explorer.funcs
+	var res []IndexConstraint
+	var walk func(e opt.Expr)
+	walk = func(e opt.Expr) {
+		if sel, ok := e.(*memo.SelectExpr); ok {
+			if scan, ok := sel.Input.(*memo.ScanExpr); ok {
+				res = c.deriveIndexConstraints(res, &scan.ScanPrivate, sel.Filters)
+			}
+		}
+		for i, n := 0, e.ChildCount(); i < n; i++ {
+			walk(e.Child(i))
+		}
+	}
+	walk(o.mem.RootExpr())
+	return res
+}
+
+// deriveIndexConstraints appends to res the constraints derived for each
+// non-inverted index of the scanned table from the given filters.
+func (c *CustomFuncs) deriveIndexConstraints(
+	res []IndexConstraint, scanPrivate *memo.ScanPrivate, explicitFilters memo.FiltersExpr,
+) []IndexConstraint {
+	tabMeta := c.e.mem.Metadata().TableMeta(scanPrivate.Table)
+	optionalFilters, filterColumns :=
+		c.GetOptionalFiltersAndFilterColumns(explicitFilters, scanPrivate)
+
+	var iter scanIndexIter
+	iter.Init(c.e.evalCtx, c.e.f, c.e.mem, &c.im, scanPrivate, explicitFilters, rejectInvertedIndexes)
+	iter.ForEach(func(index cat.Index, filters memo.FiltersExpr, indexCols opt.ColSet, isCovering bool, constProj memo.ProjectionsExpr) {
+		ic := IndexConstraint{Table: scanPrivate.Table, Index: index.Ordinal()}
+		_, _, combinedConstraint, ok := c.MakeCombinedFiltersConstraint(
+			tabMeta, index, scanPrivate, tabMeta.IndexPartitionLocality(index.Ordinal()),
+			filters, optionalFilters, filterColumns,
+		)
+		if ok {
+			ic.Constraint = combinedConstraint
+		}
+		res = append(res, ic)
+	})
+	return res
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
@@ -29,7 +30,7 @@ const maxRowCountForPlaceholderFastPath = 10
 // execbuild time.
 //
 // Currently we support cases where we can convert the entire tree to a single
-// PlaceholderScan.
+// PlaceholderScan, optionally with a constant hard limit.
 //
 // If this function succeeds, the memo will be considered fully optimized.
 func (o *Optimizer) TryPlaceholderFastPath() (_ opt.Expr, ok bool, err error) {
@@ -75,12 +76,31 @@ func (o *Optimizer) TryPlaceholderFastPath() (_ opt.Expr, ok bool, err error) {
 	// Ignore any top-level Project that only passes through columns. It's safe to
 	// remove it because the presentation property still enforces the final result
 	// columns.
-	expr := root
-	if proj, isProject := expr.(*memo.ProjectExpr); isProject {
-		if len(proj.Projections) != 0 {
+	expr, ok := skipPassthroughProject(root)
+	if !ok {
+		return nil, false, nil
+	}
+
+	// A top-level Limit with a constant row count and no internal ordering can
+	// be folded into the scan as a hard limit. The Project, if any, may be
+	// either above or below the Limit.
+	var hardLimit memo.ScanLimit
+	if lim, isLimit := expr.(*memo.LimitExpr); isLimit {
+		if !lim.Ordering.Any() {
+			return nil, false, nil
+		}
+		c, isConst := lim.Limit.(*memo.ConstExpr)
+		if !isConst {
+			return nil, false, nil
+		}
+		n, isInt := c.Value.(*tree.DInt)
+		if !isInt || *n <= 0 {
+			return nil, false, nil
+		}
+		hardLimit = memo.MakeScanLimit(int64(*n), false /* reverse */)
+		if expr, ok = skipPassthroughProject(lim.Input); !ok {
 			return nil, false, nil
 		}
-		expr = proj.Input
 	}
 
 	sel, isSelect := expr.(*memo.SelectExpr)
@@ -88,7 +108,7 @@ func (o *Optimizer) TryPlaceholderFastPath() (_ opt.Expr, ok bool, err error) {
 		return nil, false, nil
 	}
 	scan, isScan := sel.Input.(*memo.ScanExpr)
-	if !isScan {
+	if !isScan || scan.HardLimit.IsSet() {
 		return nil, false, nil
 	}
 
@@ -192,6 +212,7 @@ func (o *Optimizer) TryPlaceholderFastPath() (_ opt.Expr, ok bool, err error) {
 	newPrivate := scan.ScanPrivate
 	newPrivate.Cols = rootRelProps.OutputCols
 	newPrivate.Index = foundIndex.Ordinal()
+	newPrivate.HardLimit = hardLimit
 
 	span := make(memo.ScalarListExpr, numConstrained)
 	for i := range span {
@@ -234,3 +255,17 @@ func (o *Optimizer) TryPlaceholderFastPath() (_ opt.Expr, ok bool, err error) {
 func verifyType(md *opt.Metadata, col opt.ColumnID, typ *types.T) bool {
 	return typ.Family() == types.UnknownFamily || md.ColumnMeta(col).Type.Equivalent(typ)
 }
+
+// skipPassthroughProject returns the input of the given expression if it is a
+// Project that only passes through columns, or the expression itself if it is
+// not a Project. It returns ok=false if the expression is a Project that
+// synthesizes new columns.
+func skipPassthroughProject(expr memo.RelExpr) (_ memo.RelExpr, ok bool) {
+	if proj, isProject := expr.(*memo.ProjectExpr); isProject {
+		if len(proj.Projections) != 0 {
+			return nil, false
+		}
+		return proj.Input, true
+	}
+	return expr, true
+}
@@ -11,6 +11,7 @@
 package xform
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
@@ -160,6 +161,10 @@ func (c *CustomFuncs) GeneratePartialIndexScans(
 //      optionalFilters or explicitFilters
 //   2) No index key columns are referenced in optionalFilters or
 //      explicitFilters.
+// If the index is not partitioned, and explicitFilters reference index key
+// columns but not the first one, an IN list filter enumerating the values that
+// optionalFilters allow for the first index column is used instead, when
+// there are few of them (see checkConstraintValuesFilter).
 // These filters are passed in a single call to tryConstrainIndex.
 // In all known uses, optionalFilters consists of the CHECK constraint filters
 // and computed column filters.
@@ -286,6 +291,22 @@ func (c *CustomFuncs) MakeCombinedFiltersConstraint(
 		partitionFilters, inBetweenFilters = c.partitionValuesFilters(scanPrivate.Table, index)
 	}
 
+	// Similarly, if the explicit filters don't reference the first index
+	// column, but reference other index columns, enumerate the values that the
+	// optional filters allow for the first index column, if there are few of
+	// them. For example, CHECK (shard BETWEEN 0 AND 3) allows the query
+	// SELECT * FROM t WHERE k = 1 to scan the index on (shard, k) with the
+	// spans [/0/1 - /0/1] [/1/1 - /1/1] [/2/1 - /2/1] [/3/1 - /3/1].
+	if len(partitionFilters) == 0 {
+		explicitFilterColumns := c.FilterOuterCols(explicitFilters)
+		if !explicitFilterColumns.Contains(firstIndexCol) &&
+			indexColumns.Intersects(explicitFilterColumns) {
+			if valuesFilter, ok := c.checkConstraintValuesFilter(optionalFilters, firstIndexCol); ok {
+				optionalFilters = append(optionalFilters[:len(optionalFilters):len(optionalFilters)], valuesFilter)
+			}
+		}
+	}
+
 	// Check whether the filter (along with any partitioning filters) can constrain the index.
 	combinedConstraint, remainingFilters, ok = c.tryConstrainIndex(
 		explicitFilters,
@@ -443,6 +464,21 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 			return
 		}
 
+		// Scanning a huge number of spans is expensive for the execution engine
+		// and KV layer, so do not generate a constrained scan if there are more
+		// spans than allowed by the optimizer_max_constraint_spans setting.
+		// Instead, try to generate a lookup join against the constrained values.
+		// The full scan with a filter is already in the memo, so the cheaper of
+		// the two will be chosen.
+		if c.tooManyConstraintSpans(combinedConstraint) {
+			if isCovering && len(constProj) == 0 {
+				c.generateLookupJoinForConstraint(
+					grp, scanPrivate, index, combinedConstraint, remainingFilters,
+				)
+			}
+			return
+		}
+
 		// Construct new constrained ScanPrivate.
 		newScanPrivate := *scanPrivate
 		newScanPrivate.Index = index.Ordinal()
@@ -495,6 +531,98 @@ func (c *CustomFuncs) GenerateConstrainedScans(
 	})
 }
 
+// tooManyConstraintSpans returns true if the given constraint has more spans
+// than allowed by the optimizer_max_constraint_spans session setting.
+func (c *CustomFuncs) tooManyConstraintSpans(cons *constraint.Constraint) bool {
+	maxSpans := c.e.evalCtx.SessionData().OptimizerMaxConstraintSpans
+	return maxSpans > 0 && int64(cons.Spans.Count()) > maxSpans
+}
+
+// generateLookupJoinForConstraint generates a lookup join into the given
+// covering index which is equivalent to a constrained scan of the index with
+// the given constraint, and adds it to grp. It is used in place of constrained
+// scans with a huge number of spans. For example, the filter
+// (a, b) IN ((1, 2), (3, 4), ...) results in the following expression:
+//
+//   (Project
+//     (LookupJoin
+//       (Values [(1, 2), (3, 4), ...])
+//       $remainingFilters
+//       [ KeyCols: (column1, column2) ]
+//     )
+//   )
+//
+// The rows of the Values are sorted in the order of the index, since they are
+// built from the sorted spans of the constraint. No expression is generated if
+// the spans of the constraint do not each contain a single, non-NULL key of the
+// same length.
+func (c *CustomFuncs) generateLookupJoinForConstraint(
+	grp memo.RelExpr,
+	scanPrivate *memo.ScanPrivate,
+	index cat.Index,
+	cons *constraint.Constraint,
+	remainingFilters memo.FiltersExpr,
+) {
+	// Do not override any index hints.
+	if !scanPrivate.Flags.Empty() {
+		return
+	}
+	keyLen := cons.Spans.Get(0).StartKey().Length()
+	for i, n := 0, cons.Spans.Count(); i < n; i++ {
+		sp := cons.Spans.Get(i)
+		if !sp.HasSingleKey(c.e.evalCtx) || sp.StartKey().Length() != keyLen {
+			return
+		}
+		for j := 0; j < keyLen; j++ {
+			if sp.StartKey().Value(j) == tree.DNull {
+				// Lookup joins never match NULL keys.
+				return
+			}
+		}
+	}
+
+	md := c.e.mem.Metadata()
+	keyCols := make(opt.ColList, keyLen)
+	colTypes := make([]*types.T, keyLen)
+	var indexKeyCols opt.ColSet
+	for j := range keyCols {
+		idxCol := cons.Columns.Get(j).ID()
+		indexKeyCols.Add(idxCol)
+		colTypes[j] = md.ColumnMeta(idxCol).Type
+		keyCols[j] = md.AddColumn(fmt.Sprintf("lookup_join_const_col_@%d", idxCol), colTypes[j])
+	}
+	tupleType := types.MakeTuple(colTypes)
+	rows := make(memo.ScalarListExpr, cons.Spans.Count())
+	for i := range rows {
+		key := cons.Spans.Get(i).StartKey()
+		elems := make(memo.ScalarListExpr, keyLen)
+		for j := range elems {
+			elems[j] = c.e.f.ConstructConstVal(key.Value(j), colTypes[j])
+		}
+		rows[i] = c.e.f.ConstructTuple(elems, tupleType)
+	}
+	values := c.e.f.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: keyCols,
+		ID:   md.NextUniqueID(),
+	})
+
+	tableFDs := memo.MakeTableFuncDep(md, scanPrivate.Table)
+	lookupJoin := memo.LookupJoinPrivate{
+		JoinType:              opt.InnerJoinOp,
+		Table:                 scanPrivate.Table,
+		Index:                 index.Ordinal(),
+		KeyCols:               keyCols,
+		Cols:                  scanPrivate.Cols.Union(keyCols.ToSet()),
+		LookupColsAreTableKey: tableFDs.ColsAreLaxKey(indexKeyCols),
+		Locking:               scanPrivate.Locking,
+	}
+
+	var project memo.ProjectExpr
+	project.Input = c.e.f.ConstructLookupJoin(values, remainingFilters, &lookupJoin)
+	project.Passthrough = grp.Relational().OutputCols
+	c.e.mem.AddProjectToGroup(&project, grp)
+}
+
 // tryFoldComputedCol tries to reduce the computed column with the given column
 // ID into a constant value, by evaluating it with respect to a set of other
 // columns that are constant. If the computed column is constant, enter it into
@@ -1023,6 +1151,13 @@ func (c *CustomFuncs) canMaybeConstrainNonInvertedIndex(
 // Scan table which contain one of the constant columns in the FiltersExpr as
 // its prefix.
 //
+// A column is constant if an individual filter constrains it to a single
+// value, or if the intersection of the constraints that the filters (along with
+// check constraints and computed column expressions) imply on the two indexes
+// constrains it to a single value. For example, neither of the filters
+// a IN (1, 2) AND (a, b) IN ((2, 3), (4, 5)) fixes column a on its own, but
+// together they constrain it to [/2 - /2].
+//
 // Similar to the lookup join, if the selected index pair does not contain
 // all the columns in the output of the scan, we wrap the zigzag join
 // in another index join (implemented as a lookup join) on the primary index.
@@ -1037,7 +1172,24 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 	}
 
 	fixedCols := memo.ExtractConstColumns(filters, c.e.evalCtx)
-	if fixedCols.Len() < 2 {
+	var constrainedCols opt.ColSet
+	for i := range filters {
+		if cs := filters[i].ScalarProps().Constraints; cs != nil {
+			constrainedCols.UnionWith(cs.ExtractCols())
+		}
+	}
+	if constrainedCols.Empty() {
+		return
+	}
+
+	// Check constraints and computed columns may constrain additional columns.
+	optionalFilters, _ := c.GetOptionalFiltersAndFilterColumns(filters, scanPrivate)
+	for i := range optionalFilters {
+		if cs := optionalFilters[i].ScalarProps().Constraints; cs != nil {
+			constrainedCols.UnionWith(cs.ExtractCols())
+		}
+	}
+	if constrainedCols.Len() < 2 {
 		// Zigzagging requires at least 2 columns to have fixed values.
 		return
 	}
@@ -1084,13 +1236,29 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 	//
 	// TODO(mgartner): We should consider primary indexes when it has multiple
 	// columns and only the first is being constrained.
+
+	// indexConstraints caches the constraints that the filters imply on each
+	// index. They are used to find columns which are fixed for a given pair of
+	// indexes, but not by any individual filter.
+	indexConstraints := make(map[cat.IndexOrdinal]*constraint.Constraint)
+	indexConstraint := func(index cat.Index) *constraint.Constraint {
+		if res, ok := indexConstraints[index.Ordinal()]; ok {
+			return res
+		}
+		ic := c.initIdxConstraintForIndex(
+			filters, optionalFilters, scanPrivate.Table, index.Ordinal(),
+		)
+		res := ic.Constraint()
+		indexConstraints[index.Ordinal()] = res
+		return res
+	}
+
 	var iter scanIndexIter
 	iter.Init(c.e.evalCtx, c.e.f, c.e.mem, &c.im, scanPrivate, filters, rejectPrimaryIndex|rejectInvertedIndexes)
 	iter.ForEach(func(leftIndex cat.Index, outerFilters memo.FiltersExpr, leftCols opt.ColSet, _ bool, _ memo.ProjectionsExpr) {
-		leftFixed := c.indexConstrainedCols(leftIndex, scanPrivate.Table, fixedCols)
-		// Short-circuit quickly if the first column in the index is not a fixed
-		// column.
-		if leftFixed.Len() == 0 {
+		// Short-circuit quickly if the first column in the index is not
+		// constrained, since it cannot be fixed.
+		if !constrainedCols.Contains(scanPrivate.Table.IndexColumnID(leftIndex, 0)) {
 			return
 		}
 
@@ -1107,7 +1275,23 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 				}
 			}
 
-			rightFixed := c.indexConstrainedCols(rightIndex, scanPrivate.Table, fixedCols)
+			// Find the columns which are fixed for this pair of indexes. The
+			// constraints on the two indexes may fix columns that no individual
+			// filter fixes.
+			colConstraints := constraint.IntersectColumnConstraints(
+				c.e.evalCtx, indexConstraint(leftIndex), indexConstraint(rightIndex),
+			)
+			if colConstraints == constraint.Contradiction {
+				// The filters are a contradiction, so there is no reason to zigzag.
+				return
+			}
+			pairFixedCols := fixedCols.Union(colConstraints.ExtractConstCols(c.e.evalCtx))
+
+			leftFixed := c.indexConstrainedCols(leftIndex, scanPrivate.Table, pairFixedCols)
+			rightFixed := c.indexConstrainedCols(rightIndex, scanPrivate.Table, pairFixedCols)
+			if leftFixed.Len() == 0 {
+				return
+			}
 			// If neither side contributes a fixed column not contributed by the
 			// other, then there's no reason to zigzag on this pair of indexes.
 			if leftFixed.SubsetOf(rightFixed) || rightFixed.SubsetOf(leftFixed) {
@@ -1116,7 +1300,7 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 
 			// Columns that are in both indexes are, by definition, equal.
 			eqCols := leftCols.Intersection(rightCols)
-			eqCols.DifferenceWith(fixedCols)
+			eqCols.DifferenceWith(pairFixedCols)
 			if eqCols.Len() == 0 {
 				// A simple index join is more efficient in such cases.
 				return
@@ -1132,7 +1316,7 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 				scanPrivate.Table,
 				leftIndex,
 				rightIndex,
-				fixedCols,
+				pairFixedCols,
 				leftEq,
 				rightEq,
 			)
@@ -1170,10 +1354,10 @@ func (c *CustomFuncs) GenerateZigzagJoins(
 			}
 
 			leftFixedCols, leftVals, leftTypes := c.fixedColsForZigzag(
-				leftIndex, scanPrivate.Table, innerFilters,
+				leftIndex, scanPrivate.Table, innerFilters, colConstraints,
 			)
 			rightFixedCols, rightVals, rightTypes := c.fixedColsForZigzag(
-				rightIndex, scanPrivate.Table, innerFilters,
+				rightIndex, scanPrivate.Table, innerFilters, colConstraints,
 			)
 
 			// If the fixed cols have been reduced during partial index
@@ -1357,13 +1541,16 @@ func eqColsForZigzag(
 // fixedColsForZigzag is a helper function to generate FixedCols lists for the
 // zigzag join expression. This function iterates through the columns of the
 // specified index in order until it comes across the first column ID that is
-// not constrained to a constant.
+// not constrained to a constant, either by the filters or by colConstraints.
 func (c *CustomFuncs) fixedColsForZigzag(
-	index cat.Index, tabID opt.TableID, filters memo.FiltersExpr,
+	index cat.Index, tabID opt.TableID, filters memo.FiltersExpr, colConstraints *constraint.Set,
 ) (fixedCols opt.ColList, vals memo.ScalarListExpr, typs []*types.T) {
 	for i, cnt := 0, index.ColumnCount(); i < cnt; i++ {
 		colID := tabID.IndexColumnID(index, i)
 		val := memo.ExtractValueForConstColumn(filters, c.e.evalCtx, colID)
+		if val == nil {
+			val = colConstraints.ExtractValueForConstCol(c.e.evalCtx, colID)
+		}
 		if val == nil {
 			break
 		}
@@ -1649,6 +1836,39 @@ func (c *CustomFuncs) SplitDisjunction(
 	return nil, nil, nil, false
 }
 
+// SplitDisjointDisjunction is similar to SplitDisjunction, but it only returns
+// ok=true if the interesting pair of expressions is disjoint, i.e. no row can
+// satisfy both the left and the right expressions. See disjunctsAreDisjoint.
+func (c *CustomFuncs) SplitDisjointDisjunction(
+	sp *memo.ScanPrivate, filters memo.FiltersExpr,
+) (left opt.ScalarExpr, right opt.ScalarExpr, itemToReplace *memo.FiltersItem, ok bool) {
+	left, right, itemToReplace, ok = c.SplitDisjunction(sp, filters)
+	if !ok || !c.disjunctsAreDisjoint(left, right) {
+		return nil, nil, nil, false
+	}
+	return left, right, itemToReplace, true
+}
+
+// disjunctsAreDisjoint returns true if the left and right expressions can
+// never be true for the same row. This is the case when the constraints
+// implied by the two expressions do not intersect; for example, the
+// expressions "a = 1 AND c = 1" and "b = 2 AND c = 2" imply the constraints
+// "/c: [/1 - /1]" and "/c: [/2 - /2]", which have no values in common.
+//
+// The constraints do not need to be tight, because a row that satisfies an
+// expression always satisfies the constraints implied by it. false may be
+// returned for disjoint expressions whose disjointness cannot be proven with
+// constraints.
+func (c *CustomFuncs) disjunctsAreDisjoint(left, right opt.ScalarExpr) bool {
+	md := c.e.mem.Metadata()
+	leftConstraints, _ := memo.BuildConstraints(left, md, c.e.evalCtx)
+	rightConstraints, _ := memo.BuildConstraints(right, md, c.e.evalCtx)
+	if leftConstraints.IsUnconstrained() || rightConstraints.IsUnconstrained() {
+		return false
+	}
+	return leftConstraints.Intersect(c.e.evalCtx, rightConstraints) == constraint.Contradiction
+}
+
 // findInterestingDisjunctionPair groups disjunction sub-expressions into an
 // "interesting" pair of expressions.
 //
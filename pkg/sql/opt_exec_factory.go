@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -137,10 +138,23 @@ func (ef *execFactory) ConstructScan(
 	scan.reverse = params.Reverse
 	scan.parallelize = params.Parallelize
 	var err error
-	scan.spans, err = generateScanSpans(ef.planner.EvalContext(), ef.planner.ExecCfg().Codec, tabDesc, idx, params)
+	if ef.planner.SessionData().PartitionSpanPruningEnabled &&
+		params.InvertedConstraint == nil && params.Locking.Strength == tree.ForNone {
+		scan.emptyPartitionCandidates, err = ef.findEmptyPartitionCandidates(
+			table, index, tabDesc, idx, params.IndexConstraint,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var numMerged int
+	scan.spans, numMerged, err = generateScanSpans(ef.planner.EvalContext(), ef.planner.ExecCfg().Codec, tabDesc, idx, params)
 	if err != nil {
 		return nil, err
 	}
+	if numMerged > 0 && !ef.isExplain {
+		ef.planner.ExecCfg().GetRowMetrics(ef.planner.SessionData().Internal).MergedSpansCount.Inc(int64(numMerged))
+	}
 
 	scan.isFull = len(scan.spans) == 1 && scan.spans[0].EqualValue(
 		scan.desc.IndexSpan(ef.planner.ExecCfg().Codec, scan.index.GetID()),
@@ -164,20 +178,36 @@ func (ef *execFactory) ConstructScan(
 	return scan, nil
 }
 
+// mergeAdjacentScanSpans controls whether adjacent and overlapping spans of
+// scans are merged before they are sent to KV.
+var mergeAdjacentScanSpans = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.scan.merge_adjacent_spans.enabled",
+	"if enabled, adjacent and overlapping spans of scans are merged, which may "+
+		"turn many point lookups into a few scans",
+	false,
+)
+
+// generateScanSpans generates the spans of a scan with the given parameters. It
+// also returns the number of spans that were eliminated by merging adjacent and
+// overlapping spans (see mergeAdjacentScanSpans).
 func generateScanSpans(
 	evalCtx *eval.Context,
 	codec keys.SQLCodec,
 	tabDesc catalog.TableDescriptor,
 	index catalog.Index,
 	params exec.ScanParams,
-) (roachpb.Spans, error) {
+) (_ roachpb.Spans, numMerged int, _ error) {
 	var sb span.Builder
 	sb.Init(evalCtx, codec, tabDesc, index)
 	if params.InvertedConstraint != nil {
-		return sb.SpansFromInvertedSpans(params.InvertedConstraint, params.IndexConstraint, nil /* scratch */)
+		spans, err := sb.SpansFromInvertedSpans(params.InvertedConstraint, params.IndexConstraint, nil /* scratch */)
+		return spans, 0, err
 	}
+	sb.SetMergeAdjacentSpans(mergeAdjacentScanSpans.Get(&evalCtx.Settings.SV))
 	splitter := span.MakeSplitter(tabDesc, index, params.NeededCols)
-	return sb.SpansFromConstraint(params.IndexConstraint, splitter)
+	spans, err := sb.SpansFromConstraint(params.IndexConstraint, splitter)
+	return spans, sb.NumMergedSpans(), err
 }
 
 func (ef *execFactory) constructVirtualScan(
@@ -137,6 +137,8 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE EXTENSION ??`, `CREATE EXTENSION`},
 
 		{`CREATE EXTERNAL CONNECTION ??`, `CREATE EXTERNAL CONNECTION`},
+		{`CREATE PLAN PIN ??`, `CREATE PLAN PIN`},
+		{`CREATE PLAN PIN FOR 'a' ??`, `CREATE PLAN PIN`},
 
 		{`CREATE USER blih ??`, `CREATE ROLE`},
 		{`CREATE USER blih WITH ??`, `CREATE ROLE`},
@@ -185,6 +187,8 @@ func TestContextualHelp(t *testing.T) {
 		{`DROP INDEX blah@blih ??`, `DROP INDEX`},
 
 		{`DROP EXTERNAL CONNECTION blah ??`, `DROP EXTERNAL CONNECTION`},
+		{`DROP PLAN PIN ??`, `DROP PLAN PIN`},
+		{`DROP PLAN PIN IF EXISTS ??`, `DROP PLAN PIN`},
 
 		{`DROP USER ??`, `DROP ROLE`},
 		{`DROP USER IF ??`, `DROP ROLE`},
@@ -290,6 +294,9 @@ func TestContextualHelp(t *testing.T) {
 		{`RESUME SCHEDULES ??`, `RESUME SCHEDULES`},
 		{`RESUME ALL ??`, `RESUME ALL JOBS`},
 
+		{`ROTATE ??`, `ROTATE ENCRYPTION KEYS`},
+		{`ROTATE ENCRYPTION ??`, `ROTATE ENCRYPTION KEYS`},
+
 		{`REVOKE ALL ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM ??`, `REVOKE`},
 		{`REVOKE ALL ON foo FROM bar ??`, `REVOKE`},
@@ -863,10 +863,10 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_PAUSE_ON DEC DECIMAL DEFAULT DEFAULTS DEFINER
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT EXPERIMENTAL_RELOCATE
@@ -914,7 +914,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
 
 %token <str> PARALLEL PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
-%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
+%token <str> PIN PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
 %token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
@@ -924,7 +924,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
 %token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETURNING RETURN RETURNS RETRY REVISION_HISTORY
-%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
+%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROTATE ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
 %token <str> SEARCH SECOND SECONDARY SECURITY SELECT SEQUENCE SEQUENCES
@@ -1104,6 +1104,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %type <tree.Statement> create_extension_stmt
 %type <tree.Statement> create_external_connection_stmt
 %type <tree.Statement> create_index_stmt
+%type <tree.Statement> create_plan_pin_stmt
 %type <tree.Statement> create_role_stmt
 %type <tree.Statement> create_schedule_for_backup_stmt
 %type <tree.Statement> alter_backup_schedule
@@ -1128,6 +1129,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %type <tree.Statement> drop_database_stmt
 %type <tree.Statement> drop_external_connection_stmt
 %type <tree.Statement> drop_index_stmt
+%type <tree.Statement> drop_plan_pin_stmt
 %type <tree.Statement> drop_role_stmt
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
@@ -1160,6 +1162,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %type <tree.StringOrPlaceholderOptList> string_or_placeholder_opt_list
 %type <[]tree.StringOrPlaceholderOptList> list_of_string_or_placeholder_opt_list
 %type <tree.Statement> revoke_stmt
+%type <tree.Statement> rotate_encryption_keys_stmt
 %type <tree.Statement> refresh_stmt
 %type <*tree.Select> select_stmt
 %type <tree.Statement> abort_stmt
@@ -1347,7 +1350,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %type <[]tree.RangePartition> range_partitions
 %type <empty> opt_all_clause
 %type <empty> opt_privileges_clause
-%type <bool> distinct_clause opt_with_data
+%type <bool> distinct_clause opt_with_data opt_with_details
 %type <tree.DistinctOn> distinct_on_clause
 %type <tree.NameList> opt_column_list insert_column_list opt_stats_columns query_stats_cols
 %type <tree.OrderBy> sort_clause single_sort_clause opt_sort_clause
@@ -1541,7 +1544,7 @@ func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
 %type <tree.AuditMode> audit_mode
 
 %type <str> relocate_kw
-%type <tree.RelocateSubject> relocate_subject relocate_subject_nonlease
+%type <tree.RelocateSubject> relocate_subject relocate_subject_nonlease relocate_subject_range
 
 %type <*tree.SetZoneConfig> set_zone_config
 
@@ -1733,6 +1736,8 @@ alter_ddl_stmt:
 //   ALTER TABLE ... ALTER [COLUMN] <colname> {SET ON UPDATE <expr> | DROP ON UPDATE}
 //   ALTER TABLE ... ALTER [COLUMN] <colname> DROP NOT NULL
 //   ALTER TABLE ... ALTER [COLUMN] <colname> DROP STORED
+//   ALTER TABLE ... ALTER [COLUMN] <colname> SET GENERATED {ALWAYS | BY DEFAULT}
+//   ALTER TABLE ... ALTER [COLUMN] <colname> DROP IDENTITY [IF EXISTS]
 //   ALTER TABLE ... ALTER [COLUMN] <colname> [SET DATA] TYPE <type> [COLLATE <collation>]
 //   ALTER TABLE ... ALTER PRIMARY KEY USING INDEX <name>
 //   ALTER TABLE ... RENAME TO <newname>
@@ -1740,6 +1745,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... VALIDATE CONSTRAINT <constraintname>
 //   ALTER TABLE ... SET (storage_param = value, ...)
 //   ALTER TABLE ... SPLIT AT <selectclause> [WITH EXPIRATION <expr>]
+//   ALTER TABLE ... SPLIT AT WHERE <predicate> [WITH EXPIRATION <expr>]
 //   ALTER TABLE ... UNSPLIT AT <selectclause>
 //   ALTER TABLE ... UNSPLIT ALL
 //   ALTER TABLE ... SCATTER [ FROM ( <exprs...> ) TO ( <exprs...> ) ]
@@ -2092,6 +2098,8 @@ alter_database_set_zone_config_extension_stmt:
 //   ALTER RANGE r RELOCATE { VOTERS | NONVOTERS } FROM <store_id> TO <store_id>
 //   ALTER RANGE   RELOCATE LEASE                                  TO <store_id> FOR <selectclause>
 //   ALTER RANGE r RELOCATE LEASE                                  TO <store_id>
+//   ALTER RANGE   RELOCATE ALL            FROM <store_id> TO <store_id> FOR <selectclause>
+//   ALTER RANGE r RELOCATE ALL            FROM <store_id> TO <store_id>
 //
 // Zone configurations:
 //   DISCARD
@@ -2113,6 +2121,7 @@ alter_range_stmt:
 // Commands:
 //   ALTER INDEX ... RENAME TO <newname>
 //   ALTER INDEX ... SPLIT AT <selectclause> [WITH EXPIRATION <expr>]
+//   ALTER INDEX ... SPLIT AT WHERE <predicate> [WITH EXPIRATION <expr>]
 //   ALTER INDEX ... UNSPLIT AT <selectclause>
 //   ALTER INDEX ... UNSPLIT ALL
 //   ALTER INDEX ... SCATTER [ FROM ( <exprs...> ) TO ( <exprs...> ) ]
@@ -2178,6 +2187,24 @@ alter_split_stmt:
       ExpireExpr: $9.expr(),
     }
   }
+| ALTER TABLE table_name SPLIT AT WHERE a_expr
+  {
+    name := $3.unresolvedObjectName().ToTableName()
+    $$.val = &tree.Split{
+      TableOrIndex: tree.TableIndexName{Table: name},
+      Where: tree.NewWhere(tree.AstWhere, $7.expr()),
+      ExpireExpr: tree.Expr(nil),
+    }
+  }
+| ALTER TABLE table_name SPLIT AT WHERE a_expr WITH EXPIRATION a_expr
+  {
+    name := $3.unresolvedObjectName().ToTableName()
+    $$.val = &tree.Split{
+      TableOrIndex: tree.TableIndexName{Table: name},
+      Where: tree.NewWhere(tree.AstWhere, $7.expr()),
+      ExpireExpr: $10.expr(),
+    }
+  }
 
 alter_split_index_stmt:
   ALTER INDEX table_index_name SPLIT AT select_stmt
@@ -2188,6 +2215,14 @@ alter_split_index_stmt:
   {
     $$.val = &tree.Split{TableOrIndex: $3.tableIndexName(), Rows: $6.slct(), ExpireExpr: $9.expr()}
   }
+| ALTER INDEX table_index_name SPLIT AT WHERE a_expr
+  {
+    $$.val = &tree.Split{TableOrIndex: $3.tableIndexName(), Where: tree.NewWhere(tree.AstWhere, $7.expr()), ExpireExpr: tree.Expr(nil)}
+  }
+| ALTER INDEX table_index_name SPLIT AT WHERE a_expr WITH EXPIRATION a_expr
+  {
+    $$.val = &tree.Split{TableOrIndex: $3.tableIndexName(), Where: tree.NewWhere(tree.AstWhere, $7.expr()), ExpireExpr: $10.expr()}
+  }
 
 alter_unsplit_stmt:
   ALTER TABLE table_name UNSPLIT AT select_stmt
@@ -2244,6 +2279,13 @@ relocate_subject_nonlease:
     $$.val = tree.RelocateNonVoters
   }
 
+relocate_subject_range:
+  relocate_subject_nonlease
+| ALL
+  {
+    $$.val = tree.RelocateAll
+  }
+
 alter_relocate_stmt:
   ALTER TABLE table_name relocate_kw relocate_subject select_stmt
   {
@@ -2338,7 +2380,7 @@ alter_range_relocate_stmt:
         SubjectReplicas: tree.RelocateLease,
       }
     }
-| ALTER RANGE relocate_kw relocate_subject_nonlease FROM a_expr TO a_expr FOR select_stmt
+| ALTER RANGE relocate_kw relocate_subject_range FROM a_expr TO a_expr FOR select_stmt
   {
     $$.val = &tree.RelocateRange{
       Rows: $10.slct(),
@@ -2347,7 +2389,7 @@ alter_range_relocate_stmt:
       SubjectReplicas: $4.relocateSubject(),
     }
   }
-| ALTER RANGE a_expr relocate_kw relocate_subject_nonlease FROM a_expr TO a_expr
+| ALTER RANGE a_expr relocate_kw relocate_subject_range FROM a_expr TO a_expr
   {
     $$.val = &tree.RelocateRange{
       Rows: &tree.Select{
@@ -2578,6 +2620,25 @@ alter_table_cmd:
   {
     $$.val = &tree.AlterTableDropStored{Column: tree.Name($3)}
   }
+  // ALTER TABLE <name> ALTER [COLUMN] <colname> SET GENERATED ALWAYS
+| ALTER opt_column column_name SET GENERATED_ALWAYS ALWAYS
+  {
+    $$.val = &tree.AlterTableSetGenerated{Column: tree.Name($3), GeneratedAsIdentityType: tree.GeneratedAlways}
+  }
+  // ALTER TABLE <name> ALTER [COLUMN] <colname> SET GENERATED BY DEFAULT
+| ALTER opt_column column_name SET GENERATED_BY_DEFAULT BY DEFAULT
+  {
+    $$.val = &tree.AlterTableSetGenerated{Column: tree.Name($3), GeneratedAsIdentityType: tree.GeneratedByDefault}
+  }
+  // ALTER TABLE <name> ALTER [COLUMN] <colname> DROP IDENTITY [IF EXISTS]
+| ALTER opt_column column_name DROP IDENTITY
+  {
+    $$.val = &tree.AlterTableDropIdentity{Column: tree.Name($3)}
+  }
+| ALTER opt_column column_name DROP IDENTITY IF EXISTS
+  {
+    $$.val = &tree.AlterTableDropIdentity{Column: tree.Name($3), IfExists: true}
+  }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> SET NOT NULL
 | ALTER opt_column column_name SET NOT NULL
   {
@@ -2975,7 +3036,7 @@ alter_attribute_action:
 // %Help: REFRESH - recalculate a materialized view
 // %Category: Misc
 // %Text:
-// REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name [WITH [NO] DATA]
+// REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name [WITH [NO] DATA | INCREMENTAL]
 refresh_stmt:
   REFRESH MATERIALIZED VIEW opt_concurrently view_name opt_clear_data
   {
@@ -2996,6 +3057,10 @@ opt_clear_data:
   {
     $$.val = tree.RefreshDataClear
   }
+| INCREMENTAL
+  {
+    $$.val = tree.RefreshDataIncremental
+  }
 | /* EMPTY */
   {
     $$.val = tree.RefreshDataDefault
@@ -3439,6 +3504,43 @@ drop_external_connection_stmt:
 	}
 	| DROP EXTERNAL CONNECTION error // SHOW HELP: DROP EXTERNAL CONNECTION
 
+// %Help: CREATE PLAN PIN - pin plan hints to a statement fingerprint
+// %Category: Misc
+// %Text:
+// CREATE PLAN PIN FOR <fingerprint> AS <hints>
+//
+// Fingerprint:
+//   Statement fingerprint, as shown in crdb_internal.statement_statistics.
+//
+// Hints:
+//   Comma-separated list of tables with index hints, as they would
+//   appear in a FROM clause, e.g. 'kv@kv_v_idx, t@{NO_FULL_SCAN}'.
+//
+// %SeeAlso: DROP PLAN PIN
+create_plan_pin_stmt:
+  CREATE PLAN PIN FOR string_or_placeholder AS string_or_placeholder
+  {
+    $$.val = &tree.CreatePlanPin{Fingerprint: $5.expr(), Hints: $7.expr()}
+  }
+| CREATE PLAN PIN error // SHOW HELP: CREATE PLAN PIN
+
+// %Help: DROP PLAN PIN - remove the plan hints pinned to a statement fingerprint
+// %Category: Misc
+// %Text:
+// DROP PLAN PIN [IF EXISTS] FOR <fingerprint>
+//
+// %SeeAlso: CREATE PLAN PIN
+drop_plan_pin_stmt:
+  DROP PLAN PIN FOR string_or_placeholder
+  {
+    $$.val = &tree.DropPlanPin{Fingerprint: $5.expr()}
+  }
+| DROP PLAN PIN IF EXISTS FOR string_or_placeholder
+  {
+    $$.val = &tree.DropPlanPin{Fingerprint: $7.expr(), IfExists: true}
+  }
+| DROP PLAN PIN error // SHOW HELP: DROP PLAN PIN
+
 // %Help: RESTORE - restore data from external storage
 // %Category: CCL
 // %Text:
@@ -4124,6 +4226,7 @@ create_stmt:
 | create_changefeed_stmt
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
+| create_plan_pin_stmt // EXTEND WITH HELP: CREATE PLAN PIN
 | create_unsupported   {}
 | CREATE error         // SHOW HELP: CREATE
 
@@ -4836,6 +4939,7 @@ drop_stmt:
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
 | drop_schedule_stmt // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
+| drop_plan_pin_stmt // EXTEND WITH HELP: DROP PLAN PIN
 | drop_unsupported   {}
 | DROP error         // SHOW HELP: DROP
 
@@ -5098,7 +5202,7 @@ analyze_target:
 //     SHOW, EXPLAIN
 //
 // Plan options:
-//     TYPES, VERBOSE, OPT
+//     TYPES, VERBOSE, SPANS, OPT
 //
 // %SeeAlso: WEBDOCS/explain.html
 explain_stmt:
@@ -5175,6 +5279,7 @@ preparable_stmt:
 | reset_stmt     // help texts in sub-rule
 | restore_stmt   // EXTEND WITH HELP: RESTORE
 | resume_stmt    // help texts in sub-rule
+| rotate_encryption_keys_stmt // EXTEND WITH HELP: ROTATE ENCRYPTION KEYS
 | export_stmt    // EXTEND WITH HELP: EXPORT
 | scrub_stmt     // help texts in sub-rule
 | select_stmt    // help texts in sub-rule
@@ -5759,6 +5864,23 @@ preparable_set_stmt:
 | set_csetting_stmt    // EXTEND WITH HELP: SET CLUSTER SETTING
 | use_stmt             // EXTEND WITH HELP: USE
 
+// %Help: ROTATE ENCRYPTION KEYS - rotate the encryption-at-rest keys of all stores
+// %Category: Cfg
+// %Text:
+// ROTATE ENCRYPTION KEYS
+//
+// The store keys of every store are reloaded from their key files, and a new
+// data key is generated. The files encrypted with previous keys are then
+// rewritten in the background by a job, whose ID is returned.
+//
+// %SeeAlso: SHOW JOBS
+rotate_encryption_keys_stmt:
+  ROTATE ENCRYPTION KEYS
+  {
+    $$.val = &tree.RotateEncryptionKeys{}
+  }
+| ROTATE error // SHOW HELP: ROTATE ENCRYPTION KEYS
+
 // %Help: SCRUB - run checks against databases or tables
 // %Category: Experimental
 // %Text:
@@ -7462,24 +7584,38 @@ show_range_for_row_stmt:
 // %Help: SHOW RANGES - list ranges
 // %Category: Misc
 // %Text:
-// SHOW RANGES FROM TABLE <tablename>
-// SHOW RANGES FROM INDEX [ <tablename> @ ] <indexname>
+// SHOW RANGES FROM TABLE <tablename> [WITH DETAILS]
+// SHOW RANGES FROM INDEX [ <tablename> @ ] <indexname> [WITH DETAILS]
+// SHOW RANGES FROM DATABASE <databasename> [WITH DETAILS]
+//
+// WITH DETAILS adds the lease epoch, closed timestamp, Raft indexes and
+// pending proposals reported by the leaseholder of each range.
 show_ranges_stmt:
-  SHOW RANGES FROM TABLE table_name
+  SHOW RANGES FROM TABLE table_name opt_with_details
   {
     name := $5.unresolvedObjectName().ToTableName()
-    $$.val = &tree.ShowRanges{TableOrIndex: tree.TableIndexName{Table: name}}
+    $$.val = &tree.ShowRanges{TableOrIndex: tree.TableIndexName{Table: name}, Details: $6.bool()}
   }
-| SHOW RANGES FROM INDEX table_index_name
+| SHOW RANGES FROM INDEX table_index_name opt_with_details
   {
-    $$.val = &tree.ShowRanges{TableOrIndex: $5.tableIndexName()}
+    $$.val = &tree.ShowRanges{TableOrIndex: $5.tableIndexName(), Details: $6.bool()}
   }
-| SHOW RANGES FROM DATABASE database_name
+| SHOW RANGES FROM DATABASE database_name opt_with_details
   {
-    $$.val = &tree.ShowRanges{DatabaseName: tree.Name($5)}
+    $$.val = &tree.ShowRanges{DatabaseName: tree.Name($5), Details: $6.bool()}
   }
 | SHOW RANGES error // SHOW HELP: SHOW RANGES
 
+opt_with_details:
+  WITH DETAILS
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
 // %Help: SHOW SURVIVAL GOAL - shows survival goals
 // %Category: DDL
 // %Text:
@@ -15180,12 +15316,14 @@ unreserved_keyword:
 | DEPENDS
 | DESTINATION
 | DETACHED
+| DETAILS
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
 | ENCODING
 | ENCRYPTED
+| ENCRYPTION
 | ENCRYPTION_PASSPHRASE
 | ENUM
 | ENUMS
@@ -15352,6 +15490,7 @@ unreserved_keyword:
 | PAUSE
 | PAUSED
 | PHYSICAL
+| PIN
 | PLACEMENT
 | PLAN
 | PLANS
@@ -15407,6 +15546,7 @@ unreserved_keyword:
 | ROLES
 | ROLLBACK
 | ROLLUP
+| ROTATE
 | ROUTINES
 | ROWS
 | RULE
@@ -91,6 +91,10 @@ type commandResult struct {
 	// statements.
 	bufferingDisabled bool
 
+	// bufferLimit, if larger than the connection's results buffer size, is the
+	// number of bytes of results that can be buffered before they are flushed.
+	bufferLimit int64
+
 	// released is set when the command result has been released so that its
 	// memory can be reused. It is also used to assert against use-after-free
 	// errors.
@@ -241,6 +245,12 @@ func (r *commandResult) DisableBuffering() {
 	r.bufferingDisabled = true
 }
 
+// SetBufferLimit is part of the sql.RestrictedCommandResult interface.
+func (r *commandResult) SetBufferLimit(limit int64) {
+	r.assertNotReleased()
+	r.bufferLimit = limit
+}
+
 // BufferParamStatusUpdate is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) BufferParamStatusUpdate(param string, val string) {
 	r.buffer.paramStatusUpdates = append(
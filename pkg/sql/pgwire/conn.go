@@ -1355,7 +1355,7 @@ func (c *conn) bufferRow(ctx context.Context, row tree.Datums, r *commandResult)
 	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
 		panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
 	}
-	return c.maybeFlush(r.pos, r.bufferingDisabled)
+	return c.maybeFlush(r.pos, r.bufferingDisabled, r.bufferLimit)
 }
 
 // bufferBatch serializes a batch and adds all the rows from it to the buffer.
@@ -1393,7 +1393,7 @@ func (c *conn) bufferBatch(ctx context.Context, batch coldata.Batch, r *commandR
 			if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
 				panic(fmt.Sprintf("unexpected err from buffer: %s", err))
 			}
-			if err := c.maybeFlush(r.pos, r.bufferingDisabled); err != nil {
+			if err := c.maybeFlush(r.pos, r.bufferingDisabled, r.bufferLimit); err != nil {
 				return err
 			}
 		}
@@ -1604,11 +1604,17 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 }
 
 // maybeFlush flushes the buffer to the network connection if it exceeded
-// sessionArgs.ConnResultsBufferSize or if buffering is disabled.
-func (c *conn) maybeFlush(pos sql.CmdPos, bufferingDisabled bool) error {
+// sessionArgs.ConnResultsBufferSize, or bufferLimit if it is larger, or if
+// buffering is disabled. The executor only sets bufferLimit when it applies to
+// the connection, see sql.RestrictedCommandResult.SetBufferLimit.
+func (c *conn) maybeFlush(pos sql.CmdPos, bufferingDisabled bool, bufferLimit int64) error {
 	// Note that ConnResultsBufferSize cannot be changed during a session, so it
 	// is safe to use the value stored on sessionArgs.
-	if !bufferingDisabled && int64(c.writerState.buf.Len()) <= c.sessionArgs.ConnResultsBufferSize {
+	limit := c.sessionArgs.ConnResultsBufferSize
+	if bufferLimit > limit {
+		limit = bufferLimit
+	}
+	if !bufferingDisabled && int64(c.writerState.buf.Len()) <= limit {
 		return nil
 	}
 	return c.Flush(pos)
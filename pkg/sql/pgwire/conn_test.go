@@ -1229,6 +1229,61 @@ func TestConnResultsBufferSize(t *testing.T) {
 	require.False(t, b)
 }
 
+// TestReadOnlyResultsBufferSize checks that read-only statements in implicit
+// transactions are retried transparently on retryable errors even after their
+// results exceed the connection's results buffer.
+func TestReadOnlyResultsBufferSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	// The query produces ~50KiB of results, more than the default results
+	// buffer size, before encountering retryable errors for 100ms.
+	const query = `SELECT i, repeat('a', 1000), if(i < 50, 0, crdb_internal.force_retry('100ms'))
+FROM generate_series(1, 100) AS g(i)`
+	// countRows runs the query on a new connection, since the results buffer
+	// size of a connection is determined when it is opened.
+	db.SetMaxIdleConns(0)
+	countRows := func() (int, error) {
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+		// Use the row-based engine, so that rows are sent to the client as they
+		// are produced rather than in batches.
+		_, err = conn.ExecContext(ctx, `SET vectorize = off`)
+		require.NoError(t, err)
+		rows, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		return n, rows.Err()
+	}
+
+	// The larger buffer is disabled by default, so the error reaches the
+	// client.
+	_, err := countRows()
+	require.Regexp(t, "forced by crdb_internal.force_retry", err)
+
+	_, err = db.Exec(`SET CLUSTER SETTING sql.txn.read_only_results_buffer.size = '512KiB'`)
+	require.NoError(t, err)
+	n, err := countRows()
+	require.NoError(t, err)
+	require.Equal(t, 100, n)
+
+	// A results buffer size chosen by the operator applies to all statements.
+	_, err = db.Exec(`SET CLUSTER SETTING sql.defaults.results_buffer.size = '32KiB'`)
+	require.NoError(t, err)
+	_, err = countRows()
+	require.Regexp(t, "forced by crdb_internal.force_retry", err)
+}
+
 // Test that closing a connection while authentication was ongoing cancels the
 // auhentication process. In other words, this checks that the server is reading
 // from the connection while authentication is ongoing and so it reacts to the
@@ -1008,6 +1008,7 @@ func parseClientProvidedSessionParameters(
 				return sql.SessionArgs{}, pgerror.Newf(pgcode.ProtocolViolation,
 					"results_buffer_size option value '%s' cannot be negative", value)
 			}
+			args.ConnResultsBufferSizeOverridden = true
 			foundBufferSize = true
 
 		case "crdb:remote_addr":
@@ -1059,6 +1060,9 @@ func parseClientProvidedSessionParameters(
 	if !foundBufferSize && sv != nil {
 		// The client did not provide buffer_size; use the cluster setting as default.
 		args.ConnResultsBufferSize = connResultsBufferSize.Get(sv)
+		// If the operator configured the default, it applies to all statements,
+		// see sql.SessionArgs.ConnResultsBufferSizeOverridden.
+		args.ConnResultsBufferSizeOverridden = args.ConnResultsBufferSize != connResultsBufferSize.Default()
 	}
 
 	// TODO(richardjcai): When connecting to the database, we'll want to
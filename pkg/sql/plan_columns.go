@@ -11,6 +11,7 @@
 package sql
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
@@ -112,6 +113,8 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.getColumns(mut, colinfo.AlterRangeRelocateColumns)
 	case *scatterNode:
 		return n.getColumns(mut, colinfo.AlterTableScatterColumns)
+	case *rotateEncryptionKeysNode:
+		return n.getColumns(mut, jobs.DetachedJobExecutionResultHeader)
 	case *showFingerprintsNode:
 		return n.getColumns(mut, colinfo.ShowFingerprintsColumns)
 	case *splitNode:
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
@@ -231,6 +232,17 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 	opc.reset(ctx)
 
 	execMemo, err := opc.buildExecMemo(ctx)
+	if err != nil && opc.indexFlags != nil {
+		// The hints of a plan pin can become unusable after the pin is created,
+		// e.g. if a hinted index is dropped, or if the hinted index cannot
+		// satisfy the statement. Plan the statement without the pin rather than
+		// failing it.
+		log.Warningf(ctx, "ignoring plan pin that failed planning: %v", err)
+		p.curPlan.init(&p.stmt, &p.instrumentation)
+		opc.reset(ctx)
+		opc.indexFlags = nil
+		execMemo, err = opc.buildExecMemo(ctx)
+	}
 	if err != nil {
 		return err
 	}
@@ -326,6 +338,10 @@ type optPlanningCtx struct {
 	// allowMemoReuse is false.
 	useCache bool
 
+	// indexFlags is set if the statement matches a plan pin, in which case it
+	// contains the index flags from the pin's hints.
+	indexFlags map[cat.StableID]*tree.IndexFlags
+
 	flags planFlags
 }
 
@@ -369,6 +385,26 @@ func (opc *optPlanningCtx) reset(ctx context.Context) {
 		opc.allowMemoReuse = false
 		opc.useCache = false
 	}
+
+	opc.indexFlags = nil
+	if pins := p.execCfg.PlanPins; pins != nil {
+		fingerprint := p.stmt.StmtNoConstants
+		// EXPLAIN uses the pin of the explained statement, so that the effect of
+		// a pin can be inspected.
+		switch t := p.stmt.AST.(type) {
+		case *tree.Explain:
+			fingerprint = formatStatementHideConstants(t.Statement)
+		case *tree.ExplainAnalyze:
+			fingerprint = formatStatementHideConstants(t.Statement)
+		}
+		if pin, ok := pins.Lookup(fingerprint); ok {
+			opc.indexFlags = pin.IndexFlags
+			// The memo built for a pinned statement depends on the pin, which can
+			// change without any descriptor changing, so it must not be reused.
+			opc.allowMemoReuse = false
+			opc.useCache = false
+		}
+	}
 }
 
 func (opc *optPlanningCtx) log(ctx context.Context, msg redact.SafeString) {
@@ -559,6 +595,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	f := opc.optimizer.Factory()
 	f.FoldingControl().AllowStableFolds()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, opc.p.stmt.AST)
+	bld.IndexFlags = opc.indexFlags
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
@@ -638,6 +675,7 @@ func (opc *optPlanningCtx) runExecBuilder(
 		planTop.instrumentation.nanosSinceStatsCollected = bld.NanosSinceStatsCollected
 		planTop.instrumentation.joinTypeCounts = bld.JoinTypeCounts
 		planTop.instrumentation.joinAlgorithmCounts = bld.JoinAlgorithmCounts
+		planTop.instrumentation.indexesUsed = bld.IndexesUsed
 	} else {
 		// Create an explain factory and record the explain.Plan.
 		explainFactory := explain.NewFactory(f)
@@ -661,6 +699,7 @@ func (opc *optPlanningCtx) runExecBuilder(
 		planTop.instrumentation.nanosSinceStatsCollected = bld.NanosSinceStatsCollected
 		planTop.instrumentation.joinTypeCounts = bld.JoinTypeCounts
 		planTop.instrumentation.joinAlgorithmCounts = bld.JoinAlgorithmCounts
+		planTop.instrumentation.indexesUsed = bld.IndexesUsed
 
 		planTop.instrumentation.RecordExplainPlan(explainPlan)
 	}
@@ -719,6 +758,66 @@ func (p *planner) DecodeGist(gist string, external bool) ([]string, error) {
 	return explain.DecodePlanGistToRows(gist, cat)
 }
 
+// IndexConstraintSpans is part of the eval.Planner interface.
+func (p *planner) IndexConstraintSpans(
+	ctx context.Context, query string,
+) ([]eval.IndexConstraintSpan, error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := stmt.AST.(*tree.Select); !ok {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"only SELECT statements are supported, found %s", stmt.AST.StatementTag())
+	}
+	if stmt.NumPlaceholders > 0 {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "placeholders are not supported")
+	}
+
+	// Use a separate semantic context and optimizer, since the ones in the
+	// planner are in use by the statement calling this function.
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.SearchPath = p.semaCtx.SearchPath
+	semaCtx.TypeResolver = p
+	semaCtx.FunctionResolver = p
+	semaCtx.TableNameResolver = p
+	semaCtx.DateStyle = p.semaCtx.DateStyle
+	semaCtx.IntervalStyle = p.semaCtx.IntervalStyle
+	semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
+
+	optCatalog := &p.optPlanningCtx.catalog
+	var o xform.Optimizer
+	o.Init(ctx, p.EvalContext(), optCatalog)
+	bld := optbuilder.New(ctx, &semaCtx, p.EvalContext(), optCatalog, o.Factory(), stmt.AST)
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+
+	md := o.Memo().Metadata()
+	var res []eval.IndexConstraintSpan
+	for _, ic := range o.DeriveIndexConstraints() {
+		tab := md.Table(ic.Table)
+		row := eval.IndexConstraintSpan{
+			TableName: string(tab.Name()),
+			IndexName: string(tab.Index(ic.Index).Name()),
+		}
+		if ic.Constraint == nil {
+			res = append(res, row)
+			continue
+		}
+		if ic.Constraint.IsContradiction() {
+			row.Span = "contradiction"
+			res = append(res, row)
+			continue
+		}
+		for i, n := 0, ic.Constraint.Spans.Count(); i < n; i++ {
+			row.Span = ic.Constraint.Spans.Get(i).String()
+			res = append(res, row)
+		}
+	}
+	return res, nil
+}
+
 // makeQueryIndexRecommendation builds a statement and walks through it to find
 // potential index candidates. It then optimizes the statement with those
 // indexes hypothetically added to the table. An index recommendation for the
@@ -0,0 +1,269 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/planpins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	createPlanPinOp = "CREATE PLAN PIN"
+	dropPlanPinOp   = "DROP PLAN PIN"
+)
+
+type createPlanPinNode struct {
+	n *tree.CreatePlanPin
+}
+
+// CreatePlanPin represents a CREATE PLAN PIN statement.
+func (p *planner) CreatePlanPin(ctx context.Context, n *tree.CreatePlanPin) (planNode, error) {
+	if err := p.checkPlanPinsSupported(ctx, createPlanPinOp); err != nil {
+		return nil, err
+	}
+	return &createPlanPinNode{n: n}, nil
+}
+
+func (c *createPlanPinNode) startExec(params runParams) error {
+	p := params.p
+	fingerprintFn, err := p.TypeAsString(params.ctx, c.n.Fingerprint, createPlanPinOp)
+	if err != nil {
+		return err
+	}
+	hintsFn, err := p.TypeAsString(params.ctx, c.n.Hints, createPlanPinOp)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := fingerprintFn()
+	if err != nil {
+		return err
+	}
+	hints, err := hintsFn()
+	if err != nil {
+		return err
+	}
+	hints, indexFlags, err := p.resolvePlanPinHints(params.ctx, hints)
+	if err != nil {
+		return err
+	}
+
+	// Store the time in the pin, so that this node reports the same creation
+	// time as the nodes which learn about the pin from the system table.
+	created := timeutil.Now()
+	rows, err := p.ExecCfg().InternalExecutor.ExecEx(
+		params.ctx,
+		createPlanPinOp,
+		p.Txn(),
+		sessiondata.InternalExecutorOverride{User: username.NodeUserName()},
+		`INSERT INTO system.plan_pins (fingerprint, hints, created) VALUES ($1, $2, $3)
+ON CONFLICT (fingerprint) DO NOTHING`,
+		fingerprint, hints, created,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create plan pin")
+	}
+	if rows == 0 {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"plan pin for %q already exists", fingerprint)
+	}
+
+	if registry := p.ExecCfg().PlanPins; registry != nil {
+		pin := planpins.Pin{
+			Fingerprint: fingerprint,
+			Hints:       hints,
+			Created:     created,
+			IndexFlags:  indexFlags,
+		}
+		p.Txn().AddCommitTrigger(func(context.Context) { registry.Add(pin) })
+	}
+	return nil
+}
+
+// resolvePlanPinHints validates the tables and indexes referenced by the given
+// plan hints, and returns the hints with the tables and indexes replaced by
+// their IDs, along with the resulting index flags keyed by table ID. Storing the
+// IDs makes the pin independent of the search path of the sessions it applies
+// to, and of later renames.
+func (p *planner) resolvePlanPinHints(
+	ctx context.Context, hints string,
+) (string, map[cat.StableID]*tree.IndexFlags, error) {
+	exprs, err := planpins.ParseHints(hints)
+	if err != nil {
+		return "", nil, err
+	}
+	resolved := make([]*tree.AliasedTableExpr, len(exprs))
+	seen := make(map[descpb.ID]struct{}, len(exprs))
+	for i, e := range exprs {
+		var desc catalog.TableDescriptor
+		switch t := e.Expr.(type) {
+		case *tree.TableName:
+			un := t.ToUnresolvedObjectName()
+			desc, err = p.ResolveExistingObjectEx(ctx, un, true /* required */, tree.ResolveRequireTableDesc)
+		case *tree.TableRef:
+			desc, err = p.Descriptors().GetImmutableTableByID(
+				ctx, p.Txn(), descpb.ID(t.TableID), tree.ObjectLookupFlagsWithRequired(),
+			)
+		default:
+			err = errors.AssertionFailedf("unexpected table expression %T", e.Expr)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if _, ok := seen[desc.GetID()]; ok {
+			return "", nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid plan hints %q: table %s is hinted more than once", hints, desc.GetName())
+		}
+		seen[desc.GetID()] = struct{}{}
+
+		flags := *e.IndexFlags
+		if flags.ForceIndex() {
+			if flags.IndexID, err = findPlanPinIndex(desc, flags.Index, flags.IndexID); err != nil {
+				return "", nil, err
+			}
+			flags.Index = ""
+		}
+		flags.ZigzagIndexIDs = make([]tree.IndexID, 0, len(flags.ZigzagIndexes)+len(flags.ZigzagIndexIDs))
+		for _, name := range e.IndexFlags.ZigzagIndexes {
+			id, err := findPlanPinIndex(desc, name, 0 /* id */)
+			if err != nil {
+				return "", nil, err
+			}
+			flags.ZigzagIndexIDs = append(flags.ZigzagIndexIDs, id)
+		}
+		for _, id := range e.IndexFlags.ZigzagIndexIDs {
+			if _, err := findPlanPinIndex(desc, "" /* name */, id); err != nil {
+				return "", nil, err
+			}
+			flags.ZigzagIndexIDs = append(flags.ZigzagIndexIDs, id)
+		}
+		flags.ZigzagIndexes = nil
+		if len(flags.ZigzagIndexIDs) == 0 {
+			flags.ZigzagIndexIDs = nil
+		}
+		resolved[i] = &tree.AliasedTableExpr{
+			Expr: &tree.TableRef{
+				TableID: int64(desc.GetID()),
+				As:      tree.AliasClause{Alias: tree.Name(desc.GetName())},
+			},
+			IndexFlags: &flags,
+		}
+	}
+
+	buf := tree.NewFmtCtx(tree.FmtSimple)
+	for i, e := range resolved {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.FormatNode(e)
+	}
+	indexFlags, err := planpins.IndexFlagsByTableID(resolved)
+	if err != nil {
+		return "", nil, err
+	}
+	return buf.CloseAndGetString(), indexFlags, nil
+}
+
+// findPlanPinIndex returns the ID of the public index of the table with the
+// given name or, if the name is empty, with the given ID.
+func findPlanPinIndex(
+	desc catalog.TableDescriptor, name tree.UnrestrictedName, id tree.IndexID,
+) (tree.IndexID, error) {
+	idx := catalog.FindIndex(desc, catalog.IndexOpts{}, func(idx catalog.Index) bool {
+		if name != "" {
+			return idx.GetName() == string(name)
+		}
+		return idx.GetID() == descpb.IndexID(id)
+	})
+	if idx == nil {
+		if name != "" {
+			return 0, pgerror.Newf(pgcode.UndefinedObject,
+				"index %q not found on table %s", name, desc.GetName())
+		}
+		return 0, pgerror.Newf(pgcode.UndefinedObject,
+			"index [%d] not found on table %s", id, desc.GetName())
+	}
+	return tree.IndexID(idx.GetID()), nil
+}
+
+func (c *createPlanPinNode) Next(_ runParams) (bool, error) { return false, nil }
+func (c *createPlanPinNode) Values() tree.Datums            { return nil }
+func (c *createPlanPinNode) Close(_ context.Context)        {}
+
+type dropPlanPinNode struct {
+	n *tree.DropPlanPin
+}
+
+// DropPlanPin represents a DROP PLAN PIN statement.
+func (p *planner) DropPlanPin(ctx context.Context, n *tree.DropPlanPin) (planNode, error) {
+	if err := p.checkPlanPinsSupported(ctx, dropPlanPinOp); err != nil {
+		return nil, err
+	}
+	return &dropPlanPinNode{n: n}, nil
+}
+
+func (d *dropPlanPinNode) startExec(params runParams) error {
+	p := params.p
+	fingerprintFn, err := p.TypeAsString(params.ctx, d.n.Fingerprint, dropPlanPinOp)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := fingerprintFn()
+	if err != nil {
+		return err
+	}
+
+	rows, err := p.ExecCfg().InternalExecutor.ExecEx(
+		params.ctx,
+		dropPlanPinOp,
+		p.Txn(),
+		sessiondata.InternalExecutorOverride{User: username.NodeUserName()},
+		`DELETE FROM system.plan_pins WHERE fingerprint = $1`, fingerprint,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to drop plan pin")
+	}
+	if rows == 0 && !d.n.IfExists {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"plan pin for %q does not exist", fingerprint)
+	}
+
+	if registry := p.ExecCfg().PlanPins; registry != nil {
+		p.Txn().AddCommitTrigger(func(context.Context) { registry.Remove(fingerprint) })
+	}
+	return nil
+}
+
+func (d *dropPlanPinNode) Next(_ runParams) (bool, error) { return false, nil }
+func (d *dropPlanPinNode) Values() tree.Datums            { return nil }
+func (d *dropPlanPinNode) Close(_ context.Context)        {}
+
+// checkPlanPinsSupported returns an error if plan pins cannot be modified, or
+// if the user is not allowed to modify them.
+func (p *planner) checkPlanPinsSupported(ctx context.Context, op string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.PlanPinsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use %s",
+			clusterversion.ByKey(clusterversion.PlanPinsTable), op)
+	}
+	return p.RequireAdminRole(ctx, op)
+}
@@ -0,0 +1,319 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planpins
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var pollingInterval = settings.RegisterDurationSetting(
+	settings.TenantReadOnly,
+	"sql.plan_pins.poll_interval",
+	"rate at which the planpins.Registry polls for plan pins, set to zero to disable",
+	10*time.Second)
+
+// Pin is a set of plan hints pinned to a statement fingerprint.
+type Pin struct {
+	Fingerprint string
+	// Hints is the textual form of the hints, as stored in system.plan_pins.
+	Hints   string
+	Created time.Time
+	// IndexFlags maps the IDs of the tables referenced by Hints to the index
+	// flags to apply when scanning them.
+	IndexFlags map[cat.StableID]*tree.IndexFlags
+}
+
+// Registry maintains a view on the plan pins stored in system.plan_pins, so
+// that they can be looked up cheaply when planning a statement.
+type Registry struct {
+	mu struct {
+		// NOTE: This lock can't be held while the registry runs any statements
+		// internally; it'd deadlock.
+		syncutil.RWMutex
+		pins map[string]Pin
+
+		// epoch is observed before reading system.plan_pins, and then checked
+		// again before loading the table's contents. If the value changed in
+		// between, then the table contents might be stale.
+		epoch int
+	}
+	st *cluster.Settings
+	ie sqlutil.InternalExecutor
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(ie sqlutil.InternalExecutor, st *cluster.Settings) *Registry {
+	return &Registry{ie: ie, st: st}
+}
+
+// Start will start the polling loop for the Registry.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+
+	// Since polling for plan pins is not under user control, exclude it from
+	// cost accounting and control.
+	ctx = multitenant.WithTenantCostControlExemption(ctx)
+
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "plan-pins-poll", r.poll)
+}
+
+func (r *Registry) poll(ctx context.Context) {
+	var (
+		timer               timeutil.Timer
+		lastPoll            time.Time
+		deadline            time.Time
+		pollIntervalChanged = make(chan struct{}, 1)
+		maybeResetTimer     = func() {
+			if interval := pollingInterval.Get(&r.st.SV); interval <= 0 {
+				// Setting the interval to a non-positive value stops the polling.
+				timer.Stop()
+			} else {
+				newDeadline := lastPoll.Add(interval)
+				if deadline.IsZero() || !deadline.Equal(newDeadline) {
+					deadline = newDeadline
+					timer.Reset(timeutil.Until(deadline))
+				}
+			}
+		}
+		poll = func() {
+			if err := r.pollPins(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warningf(ctx, "error polling for plan pins: %s", err)
+			}
+			lastPoll = timeutil.Now()
+		}
+	)
+	pollingInterval.SetOnChange(&r.st.SV, func(ctx context.Context) {
+		select {
+		case pollIntervalChanged <- struct{}{}:
+		default:
+		}
+	})
+	for {
+		maybeResetTimer()
+		select {
+		case <-pollIntervalChanged:
+			continue // go back around and maybe reset the timer
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		poll()
+	}
+}
+
+func (r *Registry) pollPins(ctx context.Context) error {
+	if !r.st.Version.IsActive(ctx, clusterversion.PlanPinsTable) {
+		return nil
+	}
+
+	var rows []tree.Datums
+	// Loop until we run the query without straddling an epoch increment.
+	for {
+		r.mu.RLock()
+		epoch := r.mu.epoch
+		r.mu.RUnlock()
+
+		it, err := r.ie.QueryIteratorEx(ctx, "plan-pins-poll", nil, /* txn */
+			sessiondata.InternalExecutorOverride{
+				User: username.RootUserName(),
+			},
+			`SELECT fingerprint, hints, created FROM system.plan_pins`,
+		)
+		if err != nil {
+			return err
+		}
+		rows = rows[:0]
+		var ok bool
+		for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+			rows = append(rows, it.Cur())
+		}
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		// If the epoch changed it means that a pin was added or removed manually
+		// while the query was running. In that case, if we were to process the
+		// query results normally, we might undo that change.
+		if r.mu.epoch != epoch {
+			r.mu.Unlock()
+			continue
+		}
+		break
+	}
+	defer r.mu.Unlock()
+
+	pins := make(map[string]Pin, len(rows))
+	for _, row := range rows {
+		pin := Pin{
+			Fingerprint: string(tree.MustBeDString(row[0])),
+			Hints:       string(tree.MustBeDString(row[1])),
+			Created:     row[2].(*tree.DTimestamp).Time,
+		}
+		hints, err := ParseHints(pin.Hints)
+		if err == nil {
+			pin.IndexFlags, err = IndexFlagsByTableID(hints)
+		}
+		if err != nil {
+			// The hints are validated when the pin is created, so this should
+			// only happen if the table was modified directly.
+			log.Warningf(ctx, "ignoring plan pin for %q: %v", pin.Fingerprint, err)
+			continue
+		}
+		pins[pin.Fingerprint] = pin
+	}
+	r.mu.pins = pins
+	return nil
+}
+
+// Lookup returns the pin for the given statement fingerprint, if any.
+func (r *Registry) Lookup(fingerprint string) (Pin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pin, ok := r.mu.pins[fingerprint]
+	return pin, ok
+}
+
+// Pins returns all of the pins known to the registry, sorted by fingerprint.
+func (r *Registry) Pins() []Pin {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pins := make([]Pin, 0, len(r.mu.pins))
+	for _, pin := range r.mu.pins {
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].Fingerprint < pins[j].Fingerprint
+	})
+	return pins
+}
+
+// Add adds a pin to the registry, replacing any pin for the same fingerprint.
+// It is used to make a pin created on this node take effect immediately,
+// rather than after the next poll.
+func (r *Registry) Add(pin Pin) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.epoch++
+	if r.mu.pins == nil {
+		r.mu.pins = make(map[string]Pin)
+	}
+	r.mu.pins[pin.Fingerprint] = pin
+}
+
+// Remove removes the pin for the given fingerprint from the registry, if any.
+func (r *Registry) Remove(fingerprint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.epoch++
+	delete(r.mu.pins, fingerprint)
+}
+
+// ParseHints parses plan hints, which are a comma-separated list of tables
+// with index hints, as they would appear in a FROM clause. For example:
+//
+//	kv@kv_v_idx, t@{NO_FULL_SCAN}
+//
+// The tables are either table names or numeric table references, e.g.
+// [106 AS kv]@[2]. The hints are stored in system.plan_pins with numeric table
+// and index references, which are resolved when the pin is created.
+func ParseHints(hints string) ([]*tree.AliasedTableExpr, error) {
+	stmt, err := parser.ParseOne("SELECT 1 FROM " + hints)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid plan hints %q", hints)
+	}
+	// Reject anything other than a FROM clause by checking that the statement
+	// round-trips through a SELECT built from the FROM clause alone.
+	sel, ok := stmt.AST.(*tree.Select)
+	var clause *tree.SelectClause
+	if ok {
+		clause, ok = sel.Select.(*tree.SelectClause)
+	}
+	if !ok || tree.AsString(&tree.Select{Select: &tree.SelectClause{
+		Exprs: clause.Exprs,
+		From:  tree.From{Tables: clause.From.Tables},
+	}}) != tree.AsString(stmt.AST) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"invalid plan hints %q: expected a comma-separated list of tables with index hints", hints)
+	}
+
+	res := make([]*tree.AliasedTableExpr, 0, len(clause.From.Tables))
+	seen := make(map[string]struct{}, len(clause.From.Tables))
+	for _, t := range clause.From.Tables {
+		ate, ok := t.(*tree.AliasedTableExpr)
+		if ok {
+			switch e := ate.Expr.(type) {
+			case *tree.TableName:
+			case *tree.TableRef:
+				ok = e.Columns == nil
+			default:
+				ok = false
+			}
+		}
+		if !ok || ate.IndexFlags == nil || ate.As.Alias != "" || ate.Ordinality || ate.Lateral {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid plan hints %q: expected a table with index hints, found %s", hints, tree.AsString(t))
+		}
+		key := tree.AsString(ate.Expr)
+		if ref, ok := ate.Expr.(*tree.TableRef); ok {
+			key = fmt.Sprintf("[%d]", ref.TableID)
+		}
+		if _, ok := seen[key]; ok {
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid plan hints %q: table %s is hinted more than once", hints, key)
+		}
+		seen[key] = struct{}{}
+		res = append(res, ate)
+	}
+	return res, nil
+}
+
+// IndexFlagsByTableID returns the index flags of the given hints, keyed by the
+// ID of the hinted table. The tables must be numeric table references, as in
+// the hints stored in system.plan_pins.
+func IndexFlagsByTableID(hints []*tree.AliasedTableExpr) (map[cat.StableID]*tree.IndexFlags, error) {
+	res := make(map[cat.StableID]*tree.IndexFlags, len(hints))
+	for _, h := range hints {
+		ref, ok := h.Expr.(*tree.TableRef)
+		if !ok {
+			return nil, errors.AssertionFailedf("unresolved table %s in plan hints", tree.AsString(h.Expr))
+		}
+		res[cat.StableID(ref.TableID)] = h.IndexFlags
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package planpins
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHints(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	hints, err := ParseHints("kv@kv_v_idx, db.public.t@{NO_FULL_SCAN}")
+	require.NoError(t, err)
+	require.Len(t, hints, 2)
+	require.Equal(t, tree.UnrestrictedName("kv_v_idx"), hints[0].IndexFlags.Index)
+	require.True(t, hints[1].IndexFlags.NoFullScan)
+	_, err = IndexFlagsByTableID(hints)
+	require.Error(t, err)
+
+	hints, err = ParseHints("[106 AS kv]@[2], [107 AS t]@{NO_FULL_SCAN}")
+	require.NoError(t, err)
+	flags, err := IndexFlagsByTableID(hints)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	require.Equal(t, tree.IndexID(2), flags[106].IndexID)
+	require.True(t, flags[107].NoFullScan)
+
+	for _, tc := range []struct {
+		hints string
+		err   string
+	}{
+		{hints: "", err: "syntax error"},
+		{hints: "kv", err: "expected a table with index hints"},
+		{hints: "kv@idx AS k", err: "expected a table with index hints"},
+		{hints: "kv@idx JOIN t@idx ON true", err: "expected a table with index hints"},
+		{hints: "kv@idx WHERE true", err: "expected a comma-separated list"},
+		{hints: "kv@idx LIMIT 1", err: "expected a comma-separated list"},
+		{hints: "kv@idx, kv@other", err: "hinted more than once"},
+		{hints: "[106 AS kv]@idx, [106 AS kv2]@other", err: "hinted more than once"},
+		{hints: "[106(1) AS kv]@idx", err: "expected a table with index hints"},
+	} {
+		t.Run(tc.hints, func(t *testing.T) {
+			_, err := ParseHints(tc.hints)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -13,6 +13,10 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -21,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
 
 type refreshMaterializedViewNode struct {
@@ -82,6 +87,10 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 
 	telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedView)
 
+	if n.n.RefreshDataOption == tree.RefreshDataIncremental {
+		return n.refreshIncremental(params)
+	}
+
 	// Inform the user that CONCURRENTLY is not needed.
 	if n.n.Concurrently {
 		params.p.BufferClientNotice(
@@ -127,6 +136,81 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 	)
 }
 
+// refreshIncremental refreshes the view by applying the changes made to the
+// table it selects from since it was last refreshed. The refresh is performed
+// by a job, which is awaited once the implicit transaction of the statement
+// is committed.
+func (n *refreshMaterializedViewNode) refreshIncremental(params runParams) error {
+	execCfg := params.p.ExecCfg()
+	if !params.extendedEvalCtx.TxnImplicit {
+		return pgerror.Newf(pgcode.InvalidTransactionState,
+			"REFRESH MATERIALIZED VIEW ... INCREMENTAL cannot be used inside an explicit transaction")
+	}
+	if !execCfg.Settings.Version.IsActive(params.ctx, clusterversion.IncrementalMaterializedViewRefresh) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"REFRESH MATERIALIZED VIEW ... INCREMENTAL is not supported until the cluster version is finalized")
+	}
+	if n.desc.IsRefreshViewRequired() || n.desc.LastRefreshTime.IsEmpty() {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"materialized view %q must be fully refreshed before it can be refreshed incrementally",
+			n.desc.Name)
+	}
+	if len(n.desc.DependsOn) != 1 {
+		return errIncrementalRefreshNotSupported("query does not select from a single table")
+	}
+	if _, err := makeIncrementalRefreshQuery(n.desc.GetViewQuery()); err != nil {
+		return err
+	}
+	if err := checkIncrementalRefreshView(n.desc); err != nil {
+		return err
+	}
+	// The changes to the table are read with a rangefeed.
+	if !kvserverbase.RangefeedEnabled.Get(&execCfg.Settings.SV) {
+		return pgerror.Newf(pgcode.ConfigurationLimitExceeded,
+			"incremental refreshes of materialized views require kv.rangefeed.enabled to be set")
+	}
+
+	record := jobs.Record{
+		Description:   params.p.stmt.SQL,
+		Statements:    []string{params.p.stmt.SQL},
+		Username:      params.p.User(),
+		DescriptorIDs: descpb.IDs{n.desc.GetID()},
+		Details: jobspb.MaterializedViewRefreshDetails{
+			ViewID:    n.desc.GetID(),
+			TableID:   n.desc.DependsOn[0],
+			StartTime: n.desc.LastRefreshTime,
+			EndTime:   params.p.Txn().ReadTimestamp(),
+		},
+		Progress: jobspb.MaterializedViewRefreshProgress{},
+	}
+	registry := execCfg.JobRegistry
+	var job *jobs.StartableJob
+	jobID := registry.MakeJobID()
+	plannerTxn := params.p.Txn()
+	if err := func() (err error) {
+		defer func() {
+			if err == nil || job == nil {
+				return
+			}
+			if cleanupErr := job.CleanupOnRollback(params.ctx); cleanupErr != nil {
+				log.Warningf(params.ctx, "failed to cleanup StartableJob: %v", cleanupErr)
+			}
+		}()
+		if err := registry.CreateStartableJobWithTxn(params.ctx, &job, jobID, plannerTxn, record); err != nil {
+			return err
+		}
+		// We commit the transaction here so that the job does not run while it
+		// is open. This is safe because we're in an implicit transaction.
+		return plannerTxn.Commit(params.ctx)
+	}(); err != nil {
+		return err
+	}
+	if err := job.Start(params.ctx); err != nil {
+		return err
+	}
+	return job.AwaitCompletion(params.ctx)
+}
+
 func (n *refreshMaterializedViewNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *refreshMaterializedViewNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *refreshMaterializedViewNode) Close(ctx context.Context)           {}
@@ -13,11 +13,17 @@ package sql
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
 )
 
@@ -37,6 +43,12 @@ type relocateRunState struct {
 	toStoreDesc   *roachpb.StoreDescriptor
 	fromStoreDesc *roachpb.StoreDescriptor
 	results       relocateResults
+
+	// jobRanges contains the progress of the ranges relocated by the RELOCATE
+	// ALL job, once it completed. They are returned one at a time.
+	jobRanges []jobspb.RelocateRangeProgress_Range
+	// jobDone is set once the RELOCATE ALL job completed.
+	jobDone bool
 }
 
 // relocateResults captures the results of the last relocate run
@@ -90,6 +102,9 @@ func (n *relocateRange) startExec(params runParams) error {
 }
 
 func (n *relocateRange) Next(params runParams) (bool, error) {
+	if n.subjectReplicas == tree.RelocateAll {
+		return n.nextFromJob(params)
+	}
 	if ok, err := n.rows.Next(params); err != nil || !ok {
 		return ok, err
 	}
@@ -115,6 +130,107 @@ func (n *relocateRange) Next(params runParams) (bool, error) {
 	return true, nil
 }
 
+// nextFromJob relocates all the ranges in a job the first time it is called,
+// and returns the outcome of the relocation of one range on each call.
+func (n *relocateRange) nextFromJob(params runParams) (bool, error) {
+	if !n.run.jobDone {
+		var rangeIDs []roachpb.RangeID
+		for {
+			ok, err := n.rows.Next(params)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				break
+			}
+			if datum := n.rows.Values()[0]; datum != tree.DNull {
+				rangeIDs = append(rangeIDs, roachpb.RangeID(tree.MustBeDInt(datum)))
+			}
+		}
+		ranges, err := n.runJob(params, rangeIDs)
+		if err != nil {
+			return false, err
+		}
+		n.run.jobRanges = ranges
+		n.run.jobDone = true
+	} else if len(n.run.jobRanges) > 0 {
+		n.run.jobRanges = n.run.jobRanges[1:]
+	}
+	if len(n.run.jobRanges) == 0 {
+		return false, nil
+	}
+	r := &n.run.jobRanges[0]
+	n.run.results = relocateResults{rangeID: r.RangeID}
+	if r.StartKey != nil {
+		n.run.results.rangeDesc = &roachpb.RangeDescriptor{RangeID: r.RangeID, StartKey: r.StartKey}
+	}
+	if r.Step == jobspb.RelocateRangeProgress_FAILED {
+		n.run.results.err = errors.Newf("%s", r.Error)
+	}
+	return true, nil
+}
+
+// runJob runs a job that relocates the given ranges, waits for it to complete
+// and returns the outcome of the relocation of each range.
+func (n *relocateRange) runJob(
+	params runParams, rangeIDs []roachpb.RangeID,
+) ([]jobspb.RelocateRangeProgress_Range, error) {
+	if !params.p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.RelocateRangeJobs) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"ALTER RANGE ... RELOCATE ALL is not supported until the cluster version is finalized")
+	}
+	ranges := make([]jobspb.RelocateRangeProgress_Range, len(rangeIDs))
+	for i, rangeID := range rangeIDs {
+		ranges[i].RangeID = rangeID
+	}
+	record := jobs.Record{
+		Description: params.p.stmt.SQL,
+		Statements:  []string{params.p.stmt.SQL},
+		Username:    params.p.User(),
+		Details: jobspb.RelocateRangeDetails{
+			RangeIDs: rangeIDs,
+			From: roachpb.ReplicationTarget{
+				NodeID: n.run.fromStoreDesc.Node.NodeID, StoreID: n.run.fromStoreDesc.StoreID,
+			},
+			To: roachpb.ReplicationTarget{
+				NodeID: n.run.toStoreDesc.Node.NodeID, StoreID: n.run.toStoreDesc.StoreID,
+			},
+		},
+		Progress: jobspb.RelocateRangeProgress{Ranges: ranges},
+	}
+
+	registry := params.p.ExecCfg().JobRegistry
+	var job *jobs.StartableJob
+	jobID := registry.MakeJobID()
+	if err := params.p.ExecCfg().DB.Txn(params.ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return registry.CreateStartableJobWithTxn(ctx, &job, jobID, txn, record)
+	}); err != nil {
+		if job != nil {
+			if cleanupErr := job.CleanupOnRollback(params.ctx); cleanupErr != nil {
+				log.Warningf(params.ctx, "failed to cleanup StartableJob: %v", cleanupErr)
+			}
+		}
+		return nil, err
+	}
+	if err := job.Start(params.ctx); err != nil {
+		return nil, err
+	}
+	if err := job.AwaitCompletion(params.ctx); err != nil {
+		return nil, err
+	}
+
+	// Reload the job to read the progress recorded by the resumer.
+	completed, err := registry.LoadJob(params.ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	progress := completed.Progress().GetRelocateRange()
+	if progress == nil {
+		return nil, errors.AssertionFailedf("missing progress for relocate range job %d", jobID)
+	}
+	return progress.Ranges, nil
+}
+
 func (n *relocateRange) Values() tree.Datums {
 	result := "ok"
 	if n.run.results.err != nil {
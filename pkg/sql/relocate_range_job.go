@@ -0,0 +1,193 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// relocateRangeResumer implements the jobs.Resumer interface for the jobs
+// created by ALTER RANGE ... RELOCATE ALL. For each range, the job adds a
+// replica to the target store, transfers the lease to it if the source store
+// holds the lease, and removes the replica from the source store. The step
+// reached by each range is recorded in the job progress, so that a resumed job
+// picks up where it left off and users can follow the relocation of each
+// replica.
+type relocateRangeResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = (*relocateRangeResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r *relocateRangeResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(JobExecContext)
+	details := r.job.Details().(jobspb.RelocateRangeDetails)
+	progress := r.job.Progress().GetRelocateRange()
+	if progress == nil || len(progress.Ranges) != len(details.RangeIDs) {
+		return errors.AssertionFailedf("invalid progress for relocate range job %d", r.job.ID())
+	}
+	ranges := progress.Ranges
+	db := p.ExecCfg().DB
+	for i := range ranges {
+		rp := &ranges[i]
+		for rp.Step != jobspb.RelocateRangeProgress_DONE &&
+			rp.Step != jobspb.RelocateRangeProgress_FAILED {
+			if err := relocateRangeStep(ctx, db, details, rp); err != nil {
+				if ctx.Err() != nil {
+					return err
+				}
+				// Failing to relocate a range doesn't fail the job. The error is
+				// reported along with the other ranges once the job completes.
+				log.Warningf(ctx, "failed to relocate r%d: %v", rp.RangeID, err)
+				rp.Step = jobspb.RelocateRangeProgress_FAILED
+				rp.Error = err.Error()
+			}
+			if err := r.job.FractionProgressed(
+				ctx, nil /* txn */, func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+					prog := details.(*jobspb.Progress_RelocateRange).RelocateRange
+					prog.Ranges = ranges
+					return relocateRangeFractionCompleted(ranges)
+				},
+			); err != nil {
+				return err
+			}
+			if err := p.ExecCfg().JobRegistry.CheckPausepoint("relocaterange.after_step"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The replication
+// changes that were already made are not reverted.
+func (r *relocateRangeResumer) OnFailOrCancel(context.Context, interface{}, error) error {
+	return nil
+}
+
+// relocateRangeFractionCompleted returns the fraction of the ranges whose
+// relocation has completed, successfully or not.
+func relocateRangeFractionCompleted(ranges []jobspb.RelocateRangeProgress_Range) float32 {
+	if len(ranges) == 0 {
+		return 1
+	}
+	var completed int
+	for i := range ranges {
+		switch ranges[i].Step {
+		case jobspb.RelocateRangeProgress_DONE, jobspb.RelocateRangeProgress_FAILED:
+			completed++
+		}
+	}
+	return float32(completed) / float32(len(ranges))
+}
+
+// relocateRangeStep performs the next step of the relocation of the given
+// range, and advances its progress. Each step re-reads the range descriptor,
+// which makes it safe to repeat a step after the job is resumed.
+func relocateRangeStep(
+	ctx context.Context,
+	db *kv.DB,
+	details jobspb.RelocateRangeDetails,
+	rp *jobspb.RelocateRangeProgress_Range,
+) error {
+	desc, err := lookupRangeDescriptorByRangeID(ctx, db, rp.RangeID)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up range descriptor")
+	}
+	rp.StartKey = desc.StartKey
+	fromRepl, onFrom := desc.GetReplicaDescriptor(details.From.StoreID)
+	_, onTo := desc.GetReplicaDescriptor(details.To.StoreID)
+
+	switch rp.Step {
+	case jobspb.RelocateRangeProgress_PENDING:
+		if !onFrom {
+			return errors.Errorf("range %d has no replica on store %d", rp.RangeID, details.From.StoreID)
+		}
+		if !onTo {
+			changeType := roachpb.ADD_VOTER
+			if fromRepl.Type == roachpb.NON_VOTER {
+				changeType = roachpb.ADD_NON_VOTER
+			}
+			if _, err := db.AdminChangeReplicas(ctx, desc.StartKey, *desc, []roachpb.ReplicationChange{
+				{ChangeType: changeType, Target: details.To},
+			}); err != nil {
+				return err
+			}
+		}
+		rp.Step = jobspb.RelocateRangeProgress_REPLICA_ADDED
+
+	case jobspb.RelocateRangeProgress_REPLICA_ADDED:
+		if onFrom && fromRepl.IsAnyVoter() {
+			leaseholder, err := leaseholderStoreID(ctx, db, desc)
+			if err != nil {
+				return err
+			}
+			if leaseholder == details.From.StoreID {
+				if err := db.AdminTransferLease(ctx, desc.StartKey, details.To.StoreID); err != nil {
+					return err
+				}
+			}
+		}
+		rp.Step = jobspb.RelocateRangeProgress_LEASE_TRANSFERRED
+
+	case jobspb.RelocateRangeProgress_LEASE_TRANSFERRED:
+		if onFrom {
+			changeType := roachpb.REMOVE_VOTER
+			if fromRepl.Type == roachpb.NON_VOTER {
+				changeType = roachpb.REMOVE_NON_VOTER
+			}
+			if _, err := db.AdminChangeReplicas(ctx, desc.StartKey, *desc, []roachpb.ReplicationChange{
+				{ChangeType: changeType, Target: details.From},
+			}); err != nil {
+				return err
+			}
+		}
+		rp.Step = jobspb.RelocateRangeProgress_DONE
+
+	default:
+		return errors.AssertionFailedf("unexpected relocation step %s", rp.Step)
+	}
+	return nil
+}
+
+// leaseholderStoreID returns the store holding the lease of the given range.
+func leaseholderStoreID(
+	ctx context.Context, db *kv.DB, desc *roachpb.RangeDescriptor,
+) (roachpb.StoreID, error) {
+	b := &kv.Batch{}
+	b.AddRawRequest(&roachpb.LeaseInfoRequest{
+		RequestHeader: roachpb.RequestHeader{Key: desc.StartKey.AsRawKey()},
+	})
+	if err := db.Run(ctx, b); err != nil {
+		return 0, errors.Wrapf(err, "error fetching leaseholder of range %d", desc.RangeID)
+	}
+	resp := b.RawResponse().Responses[0].GetInner().(*roachpb.LeaseInfoResponse)
+	return resp.Lease.Replica.StoreID, nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeRelocateRange,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &relocateRangeResumer{job: job}
+		},
+		jobs.DisablesTenantCostControl,
+	)
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRelocateRangeJobPauseResume checks that a relocation job that is paused
+// in the middle of relocating a range picks up from the recorded step once it
+// is resumed.
+func TestRelocateRangeJobPauseResume(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 2, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			Knobs: base.TestingKnobs{
+				JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+			},
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	tdb.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY)`)
+	tdb.Exec(t, `ALTER TABLE t SPLIT AT VALUES (10), (20)`)
+
+	// Pause the job once the first step of the first range is recorded.
+	tdb.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = 'relocaterange.after_step'`)
+	tdb.ExpectErr(t, `pause point "relocaterange.after_step" hit`,
+		`ALTER RANGE RELOCATE ALL FROM 1 TO 2 FOR SELECT range_id FROM [SHOW RANGES FROM TABLE t]`)
+
+	var jobID jobspb.JobID
+	tdb.QueryRow(t,
+		`SELECT job_id FROM crdb_internal.jobs WHERE job_type = 'RELOCATE RANGE'`,
+	).Scan(&jobID)
+	jobutils.WaitForJobToPause(t, tdb, jobID)
+
+	ranges := jobutils.GetJobProgress(t, tdb, jobID).GetRelocateRange().Ranges
+	require.Len(t, ranges, 3)
+	require.Equal(t, jobspb.RelocateRangeProgress_REPLICA_ADDED, ranges[0].Step)
+	for _, r := range ranges[1:] {
+		require.Equal(t, jobspb.RelocateRangeProgress_PENDING, r.Step)
+	}
+
+	tdb.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = DEFAULT`)
+	tdb.Exec(t, `RESUME JOB $1`, jobID)
+	jobutils.WaitForJobToSucceed(t, tdb, jobID)
+
+	ranges = jobutils.GetJobProgress(t, tdb, jobID).GetRelocateRange().Ranges
+	for _, r := range ranges {
+		require.Equal(t, jobspb.RelocateRangeProgress_DONE, r.Step, "r%d: %s", r.RangeID, r.Error)
+	}
+	tdb.CheckQueryResults(t,
+		`SELECT count(*) FROM [SHOW RANGES FROM TABLE t] WHERE replicas = ARRAY[2] AND lease_holder = 2`,
+		[][]string{{"3"}},
+	)
+}
@@ -78,7 +78,19 @@ func RevertTables(
 		// probably makes sense to log it without a verbosity filter.
 		log.Infof(ctx, "reverting table %s (%d) to time %v", tables[i].GetName(), tables[i].GetID(), targetTime)
 	}
+	return revertSpans(ctx, db, spans, targetTime, ignoreGCThreshold, batchSize)
+}
 
+// revertSpans reverts the passed spans to the target time. It is up to the
+// caller to ensure that there are no concurrent writes to the spans.
+func revertSpans(
+	ctx context.Context,
+	db *kv.DB,
+	spans []roachpb.Span,
+	targetTime hlc.Timestamp,
+	ignoreGCThreshold bool,
+	batchSize int64,
+) error {
 	// TODO(dt): pre-split requests up using a rangedesc cache and run batches in
 	// parallel (since we're passing a key limit, distsender won't do its usual
 	// splitting/parallel sending to separate ranges).
@@ -0,0 +1,82 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+)
+
+type rotateEncryptionKeysNode struct {
+	optColumnsSlot
+
+	jobID jobspb.JobID
+	done  bool
+}
+
+// RotateEncryptionKeys rotates the encryption-at-rest keys of all the stores
+// of the cluster, and rewrites the files encrypted with the previous keys in a
+// job whose ID is returned
+// (`ROTATE ENCRYPTION KEYS` statement).
+// Privileges: admin.
+func (p *planner) RotateEncryptionKeys(
+	ctx context.Context, n *tree.RotateEncryptionKeys,
+) (planNode, error) {
+	if err := p.RequireAdminRole(ctx, "ROTATE ENCRYPTION KEYS"); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Codec.ForSystemTenant() {
+		return nil, errorutil.UnsupportedWithMultiTenancy(
+			errorutil.FeatureNotAvailableToNonSystemTenantsIssue)
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.EncryptionKeyRotationJobs) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"ROTATE ENCRYPTION KEYS is not supported until the cluster version is finalized")
+	}
+	return &rotateEncryptionKeysNode{}, nil
+}
+
+func (n *rotateEncryptionKeysNode) startExec(params runParams) error {
+	record := jobs.Record{
+		Description: params.p.stmt.SQL,
+		Statements:  []string{params.p.stmt.SQL},
+		Username:    params.p.User(),
+		Details:     jobspb.EncryptionKeyRotationDetails{},
+		Progress:    jobspb.EncryptionKeyRotationProgress{},
+	}
+	// The job runs in the background once the transaction commits, like a
+	// detached BACKUP.
+	registry := params.p.ExecCfg().JobRegistry
+	n.jobID = registry.MakeJobID()
+	_, err := registry.CreateAdoptableJobWithTxn(params.ctx, record, n.jobID, params.p.Txn())
+	return err
+}
+
+func (n *rotateEncryptionKeysNode) Next(params runParams) (bool, error) {
+	if n.done {
+		return false, nil
+	}
+	n.done = true
+	return true, nil
+}
+
+func (n *rotateEncryptionKeysNode) Values() tree.Datums {
+	return tree.Datums{tree.NewDInt(tree.DInt(n.jobID))}
+}
+
+func (*rotateEncryptionKeysNode) Close(ctx context.Context) {}
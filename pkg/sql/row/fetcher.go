@@ -381,6 +381,7 @@ func (rf *Fetcher) Init(ctx context.Context, args FetcherInitArgs) error {
 			lockTimeout:                args.LockTimeout,
 			acc:                        rf.kvFetcherMemAcc,
 			forceProductionKVBatchSize: args.ForceProductionKVBatchSize,
+			databaseID:                 args.Spec.DatabaseID,
 		}
 		var batchRequestsIssued int64
 		if args.Txn != nil {
@@ -1263,3 +1264,9 @@ func (rf *Fetcher) GetBytesRead() int64 {
 func (rf *Fetcher) GetBatchRequestsIssued() int64 {
 	return rf.kvFetcher.GetBatchRequestsIssued()
 }
+
+// GetSpansIssued returns total number of spans requested by the underlying
+// KVFetcher.
+func (rf *Fetcher) GetSpansIssued() int64 {
+	return rf.kvFetcher.GetSpansIssued()
+}
@@ -171,6 +171,9 @@ type txnKVFetcher struct {
 	// For request and response admission control.
 	requestAdmissionHeader roachpb.AdmissionHeader
 	responseAdmissionQ     *admission.WorkQueue
+	// databaseID is the ID of the database that the fetcher reads from, if
+	// known. It is used to order response admission across databases.
+	databaseID descpb.ID
 }
 
 var _ KVBatchFetcher = &txnKVFetcher{}
@@ -249,6 +252,7 @@ type kvBatchFetcherArgs struct {
 	forceProductionKVBatchSize bool
 	requestAdmissionHeader     roachpb.AdmissionHeader
 	responseAdmissionQ         *admission.WorkQueue
+	databaseID                 descpb.ID
 }
 
 // newKVBatchFetcher initializes a KVBatchFetcher.
@@ -267,6 +271,7 @@ func newKVBatchFetcher(args kvBatchFetcherArgs) *txnKVFetcher {
 		forceProductionKVBatchSize: args.forceProductionKVBatchSize,
 		requestAdmissionHeader:     args.requestAdmissionHeader,
 		responseAdmissionQ:         args.responseAdmissionQ,
+		databaseID:                 args.databaseID,
 	}
 }
 
@@ -400,6 +405,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	ba.Header.TargetBytes = int64(f.batchBytesLimit)
 	ba.Header.MaxSpanRequestKeys = int64(f.getBatchKeyLimit())
 	ba.AdmissionHeader = f.requestAdmissionHeader
+	ba.AdmissionHeader.DatabaseID = uint32(f.databaseID)
 	ba.Requests = spansToRequests(f.spans.Spans, f.reverse, f.lockStrength, f.reqsScratch)
 
 	if log.ExpensiveLogEnabled(ctx, 2) {
@@ -469,6 +475,9 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 			Priority:   admissionpb.WorkPriority(f.requestAdmissionHeader.Priority),
 			CreateTime: f.requestAdmissionHeader.CreateTime,
 		}
+		if f.responseAdmissionQ.DatabaseFairnessEnabled() {
+			responseAdmission.DatabaseID = uint32(f.databaseID)
+		}
 		if _, err := f.responseAdmissionQ.Admit(ctx, responseAdmission); err != nil {
 			return err
 		}
@@ -44,6 +44,7 @@ type KVFetcher struct {
 	// Note: these need to be read via an atomic op.
 	atomics struct {
 		bytesRead           int64
+		spansIssued         int64
 		batchRequestsIssued *int64
 	}
 }
@@ -72,6 +73,7 @@ func NewKVFetcher(
 	lockTimeout time.Duration,
 	acc *mon.BoundAccount,
 	forceProductionKVBatchSize bool,
+	databaseID descpb.ID,
 ) *KVFetcher {
 	var sendFn sendFunc
 	var batchRequestsIssued int64
@@ -109,6 +111,7 @@ func NewKVFetcher(
 		lockTimeout:                lockTimeout,
 		acc:                        acc,
 		forceProductionKVBatchSize: forceProductionKVBatchSize,
+		databaseID:                 databaseID,
 	}
 	if txn != nil {
 		// In most cases, the txn is non-nil; however, in some code paths (e.g.
@@ -194,6 +197,16 @@ func (f *KVFetcher) GetBatchRequestsIssued() int64 {
 	return atomic.LoadInt64(f.atomics.batchRequestsIssued)
 }
 
+// GetSpansIssued returns the number of spans requested by this fetcher. It is
+// safe for concurrent use and is able to handle a case of uninitialized
+// fetcher.
+func (f *KVFetcher) GetSpansIssued() int64 {
+	if f == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&f.atomics.spansIssued)
+}
+
 // MVCCDecodingStrategy controls if and how the fetcher should decode MVCC
 // timestamps from returned KV's.
 type MVCCDecodingStrategy int
@@ -289,6 +302,7 @@ func (f *KVFetcher) SetupNextFetch(
 	f.kvs = nil
 	f.batchResponse = nil
 	f.spanID = 0
+	atomic.AddInt64(&f.atomics.spansIssued, int64(len(spans)))
 	return f.KVBatchFetcher.SetupNextFetch(
 		ctx, spans, spanIDs, batchBytesLimit, firstBatchKeyLimit,
 	)
@@ -38,6 +38,7 @@ func InitIndexFetchSpec(
 		Version:             descpb.IndexFetchSpecVersionInitial,
 		TableID:             table.GetID(),
 		TableName:           table.GetName(),
+		DatabaseID:          table.GetParentID(),
 		IndexID:             index.GetID(),
 		IndexName:           index.GetName(),
 		IsSecondaryIndex:    !index.Primary(),
@@ -58,10 +58,12 @@ func TestJoinReaderUsesBatchLimit(t *testing.T) {
 	defer s.Stopper().Stop(ctx)
 
 	// Disable the usage of the streamer since this test is designed for the old
-	// non-streamer code path.
+	// non-streamer code path. We use a single connection so that the session
+	// setting applies to all the queries below.
 	// TODO(yuzefovich): remove the test altogether when the corresponding
-	// cluster setting is removed (i.e. only the streamer code path remains).
-	_, err := sqlDB.Exec("SET CLUSTER SETTING sql.distsql.use_streamer.enabled = false;")
+	// session setting is removed (i.e. only the streamer code path remains).
+	sqlDB.SetMaxOpenConns(1)
+	_, err := sqlDB.Exec("SET streamer_enabled = false;")
 	require.NoError(t, err)
 
 	// We're going to create a table with enough rows to exceed a batch's memory
@@ -51,6 +51,7 @@ type rowFetcher interface {
 	Reset()
 	GetBytesRead() int64
 	GetBatchRequestsIssued() int64
+	GetSpansIssued() int64
 	// Close releases any resources held by this fetcher.
 	Close(ctx context.Context)
 }
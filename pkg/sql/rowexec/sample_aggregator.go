@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/axiomhq/hyperloglog"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
@@ -92,6 +93,11 @@ func newSampleAggregator(
 	post *execinfrapb.PostProcessSpec,
 	output execinfra.RowReceiver,
 ) (*sampleAggregator, error) {
+	// Samplers on nodes that don't support multi-column histograms only sample
+	// the first column of each histogram.
+	multiColHistograms := flowCtx.EvalCtx.Settings.Version.IsActive(
+		flowCtx.EvalCtx.Ctx(), clusterversion.MultiColumnHistograms,
+	)
 	for _, s := range spec.Sketches {
 		if len(s.Columns) == 0 {
 			return nil, errors.Errorf("no columns")
@@ -102,7 +108,7 @@ func newSampleAggregator(
 		if s.GenerateHistogram && s.HistogramMaxBuckets == 0 {
 			return nil, errors.Errorf("histogram max buckets not specified")
 		}
-		if s.GenerateHistogram && len(s.Columns) != 1 {
+		if s.GenerateHistogram && len(s.Columns) != 1 && !multiColHistograms {
 			return nil, errors.Errorf("histograms require one column")
 		}
 	}
@@ -143,7 +149,9 @@ func newSampleAggregator(
 			numRows:  0,
 		}
 		if spec.Sketches[i].GenerateHistogram {
-			sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+			for _, col := range spec.Sketches[i].Columns {
+				sampleCols.Add(int(col))
+			}
 		}
 	}
 
@@ -436,7 +444,25 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 	if err := s.FlowCtx.Cfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 		for _, si := range s.sketches {
 			var histogram *stats.HistogramData
-			if si.spec.GenerateHistogram {
+			if si.spec.GenerateHistogram && len(si.spec.Columns) > 1 {
+				colIdxs := make([]int, len(si.spec.Columns))
+				for i, c := range si.spec.Columns {
+					colIdxs[i] = int(c)
+				}
+				h, err := s.generateMultiColumnHistogram(
+					ctx,
+					s.EvalCtx,
+					&s.sr,
+					colIdxs,
+					si.numRows-si.numNulls,
+					s.getDistinctCount(&si, false /* includeNulls */),
+					int(si.spec.HistogramMaxBuckets),
+				)
+				if err != nil {
+					return err
+				}
+				histogram = &h
+			} else if si.spec.GenerateHistogram {
 				colIdx := int(si.spec.Columns[0])
 				typ := s.inTypes[colIdx]
 
@@ -622,6 +648,37 @@ func (s *sampleAggregator) generateHistogram(
 	return h, err
 }
 
+// generateMultiColumnHistogram returns a histogram on the given columns from a
+// set of samples. The histogram is built over the ascending key encodings of
+// the column values, concatenated in the order of the columns, so its buckets
+// are ordered like the keys of an index on the columns. See
+// stats.SampleReservoir.GetEncodedKeys.
+// numRows is the total number of rows from which values were sampled
+// (excluding rows that have NULL values on all of the columns).
+func (s *sampleAggregator) generateMultiColumnHistogram(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	sr *stats.SampleReservoir,
+	colIdxs []int,
+	numRows int64,
+	distinctCount int64,
+	maxBuckets int,
+) (stats.HistogramData, error) {
+	prevCapacity := sr.Cap()
+	values, err := sr.GetEncodedKeys(ctx, &s.tempMemAcc, colIdxs)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+	if sr.Cap() != prevCapacity {
+		log.Infof(
+			ctx, "histogram samples reduced from %d to %d due to excessive memory utilization",
+			prevCapacity, sr.Cap(),
+		)
+	}
+	h, _, err := stats.EquiDepthHistogram(evalCtx, types.Bytes, values, numRows, distinctCount, maxBuckets)
+	return h, err
+}
+
 var _ execinfra.DoesNotUseTxn = &sampleAggregator{}
 
 // DoesNotUseTxn implements the DoesNotUseTxn interface.
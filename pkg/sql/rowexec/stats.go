@@ -170,6 +170,11 @@ func (c *rowFetcherStatCollector) GetBatchRequestsIssued() int64 {
 	return c.fetcher.GetBatchRequestsIssued()
 }
 
+// GetSpansIssued is part of the rowFetcher interface.
+func (c *rowFetcherStatCollector) GetSpansIssued() int64 {
+	return c.fetcher.GetSpansIssued()
+}
+
 // Close is part of the rowFetcher interface.
 func (c *rowFetcherStatCollector) Close(ctx context.Context) {
 	c.fetcher.Close(ctx)
@@ -314,6 +314,7 @@ func (tr *tableReader) execStatsForTrace() *execinfrapb.ComponentStats {
 			KVTime:              is.WaitTime,
 			ContentionTime:      optional.MakeTimeValue(execstats.GetCumulativeContentionTime(tr.Ctx, tr.ExecStatsTrace)),
 			BatchRequestsIssued: optional.MakeUint(uint64(tr.fetcher.GetBatchRequestsIssued())),
+			SpansIssued:         optional.MakeUint(uint64(tr.fetcher.GetSpansIssued())),
 		},
 		Output: tr.OutputHelper.Stats(),
 	}
@@ -847,6 +847,7 @@ func (z *zigzagJoiner) execStatsForTrace() *execinfrapb.ComponentStats {
 		BytesRead:           optional.MakeUint(uint64(z.getBytesRead())),
 		ContentionTime:      optional.MakeTimeValue(execstats.GetCumulativeContentionTime(z.Ctx, z.ExecStatsTrace)),
 		BatchRequestsIssued: optional.MakeUint(uint64(z.getBatchRequestsIssued())),
+		SpansIssued:         optional.MakeUint(uint64(z.getSpansIssued())),
 	}
 	execstats.PopulateKVMVCCStats(&kvStats, &z.scanStats)
 	for i := range z.infos {
@@ -887,6 +888,14 @@ func (z *zigzagJoiner) getBatchRequestsIssued() int64 {
 	return batchRequestsIssued
 }
 
+func (z *zigzagJoiner) getSpansIssued() int64 {
+	var spansIssued int64
+	for i := range z.infos {
+		spansIssued += z.infos[i].fetcher.GetSpansIssued()
+	}
+	return spansIssued
+}
+
 func (z *zigzagJoiner) generateMeta() []execinfrapb.ProducerMetadata {
 	trailingMeta := make([]execinfrapb.ProducerMetadata, 1, 2)
 	meta := &trailingMeta[0]
@@ -29,6 +29,14 @@ var (
 		Measurement: "Rows",
 		Unit:        metric.Unit_COUNT,
 	}
+	// MetaMergedScanSpans is metadata for the
+	// sql.scan.merged_spans.count{.internal} metrics.
+	MetaMergedScanSpans = metric.Metadata{
+		Name:        "sql.scan.merged_spans.count",
+		Help:        "Number of scan spans eliminated by merging adjacent and overlapping spans",
+		Measurement: "Spans",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // Metrics holds metrics measuring calls into the KV layer by various parts of
@@ -36,6 +44,7 @@ var (
 type Metrics struct {
 	MaxRowSizeLogCount *metric.Counter
 	MaxRowSizeErrCount *metric.Counter
+	MergedSpansCount   *metric.Counter
 }
 
 var _ metric.Struct = Metrics{}
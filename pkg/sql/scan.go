@@ -100,6 +100,11 @@ type scanNode struct {
 	// order for this optimization to work, the DistSQL planner must create a
 	// local plan.
 	localityOptimized bool
+
+	// emptyPartitionCandidates contains the partitions whose spans are removed
+	// from the scan when it is executed if they are proven to be empty (see
+	// pruneEmptyPartitionSpans).
+	emptyPartitionCandidates []emptyPartitionCandidate
 }
 
 // scanColumnsConfig controls the "schema" of a scan node.
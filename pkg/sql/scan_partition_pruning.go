@@ -0,0 +1,219 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/partition"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// emptyPartitionCandidate is a partition of the index of a scan which table
+// statistics suggest is empty. See findEmptyPartitionCandidates.
+type emptyPartitionCandidate struct {
+	name string
+	// spans are the spans of the partition prefix.
+	spans roachpb.Spans
+}
+
+// findEmptyPartitionCandidates returns the PARTITION BY LIST partitions of the
+// given index which the spans of the given index constraint fall into, and
+// which the most recent histogram on the first index column indicates contain
+// no rows. It only uses metadata: since statistics can be stale, the
+// partitions are proven to be empty when the scan is executed (see
+// pruneEmptyPartitionSpans), so that cached plans don't miss rows that were
+// inserted into the partitions since they were built.
+func (ef *execFactory) findEmptyPartitionCandidates(
+	table cat.Table,
+	index cat.Index,
+	tabDesc catalog.TableDescriptor,
+	idx catalog.Index,
+	c *constraint.Constraint,
+) ([]emptyPartitionCandidate, error) {
+	if c == nil || c.IsUnconstrained() || index.PartitionCount() < 2 {
+		return nil, nil
+	}
+	hist, histType := partitionColumnHistogram(table, index)
+	if hist == nil {
+		return nil, nil
+	}
+	evalCtx := ef.planner.EvalContext()
+	ps := partition.GetSortedPrefixes(index, util.FastIntSet{}, evalCtx)
+	if ps.Empty() {
+		return nil, nil
+	}
+
+	var sb span.Builder
+	sb.Init(evalCtx, ef.planner.ExecCfg().Codec, tabDesc, idx)
+	keyCtx := constraint.MakeKeyContext(&c.Columns, evalCtx)
+
+	// checked contains the prefixes which were already considered, since many
+	// spans can fall within the same partition.
+	checked := make(map[*partition.Prefix]struct{})
+	var candidates []emptyPartitionCandidate
+	for i, n := 0, c.Spans.Count(); i < n; i++ {
+		// The DEFAULT partition, which has a zero-length prefix, is never pruned
+		// since it doesn't correspond to a single key range.
+		match, ok := constraint.FindMatch(c.Spans.Get(i), ps)
+		if !ok || len(match.Prefix) == 0 {
+			continue
+		}
+		if _, ok := checked[match]; ok {
+			continue
+		}
+		checked[match] = struct{}{}
+		if !histType.Equivalent(match.Prefix[0].ResolvedType()) ||
+			histogramMayContain(evalCtx, hist, match.Prefix[0]) {
+			continue
+		}
+		key := constraint.MakeCompositeKey(match.Prefix...)
+		var sp constraint.Span
+		sp.Init(key, constraint.IncludeBoundary, key, constraint.IncludeBoundary)
+		var prefixConstraint constraint.Constraint
+		prefixConstraint.InitSingleSpan(&keyCtx, &sp)
+		spans, err := sb.SpansFromConstraint(&prefixConstraint, span.NoopSplitter())
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, emptyPartitionCandidate{
+			name: match.PartitionName(), spans: spans,
+		})
+	}
+	return candidates, nil
+}
+
+// pruneEmptyPartitionSpans returns the spans of the given scan, without the
+// spans which fall within one of its empty partition candidates that is
+// proven to be empty with a limit 1 scan in the given transaction, which reads
+// at the same timestamp as the scan itself. It is called when the scan is
+// executed, rather than when it is planned.
+func pruneEmptyPartitionSpans(ctx context.Context, txn *kv.Txn, n *scanNode) (roachpb.Spans, error) {
+	if len(n.emptyPartitionCandidates) == 0 || txn == nil {
+		return n.spans, nil
+	}
+	// empty caches whether each candidate was proven to be empty.
+	empty := make(map[int]bool, len(n.emptyPartitionCandidates))
+	isEmpty := func(i int) (bool, error) {
+		if res, ok := empty[i]; ok {
+			return res, nil
+		}
+		res := true
+		for _, sp := range n.emptyPartitionCandidates[i].spans {
+			kvs, err := txn.Scan(ctx, sp.Key, sp.EndKey, 1 /* maxRows */)
+			if err != nil {
+				return false, err
+			}
+			if len(kvs) > 0 {
+				res = false
+				break
+			}
+		}
+		empty[i] = res
+		return res, nil
+	}
+
+	// candidateContaining returns the candidate whose spans contain the given
+	// span, or -1 if there is none.
+	candidateContaining := func(sp roachpb.Span) int {
+		for i := range n.emptyPartitionCandidates {
+			for _, partitionSpan := range n.emptyPartitionCandidates[i].spans {
+				if partitionSpan.Contains(sp) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	var spans roachpb.Spans
+	for _, sp := range n.spans {
+		if i := candidateContaining(sp); i >= 0 {
+			if pruned, err := isEmpty(i); err != nil {
+				return nil, err
+			} else if pruned {
+				continue
+			}
+		}
+		spans = append(spans, sp)
+	}
+	if len(spans) == 0 {
+		// Keep a single span so that the scan is planned as usual; it is known
+		// to be empty.
+		return n.spans[:1], nil
+	}
+	return spans, nil
+}
+
+// partitionColumnHistogram returns the histogram of the most recent statistic
+// on the first column of the given index, along with the type of its bounds.
+// It returns nil if that statistic doesn't have a histogram.
+func partitionColumnHistogram(
+	table cat.Table, index cat.Index,
+) ([]cat.HistogramBucket, *types.T) {
+	colOrd := index.Column(0).Ordinal()
+	for i, n := 0, table.StatisticCount(); i < n; i++ {
+		stat := table.Statistic(i)
+		if stat.ColumnCount() != 1 || stat.ColumnOrdinal(0) != colOrd {
+			continue
+		}
+		if len(stat.Histogram()) == 0 {
+			return nil, nil
+		}
+		return stat.Histogram(), stat.HistogramType()
+	}
+	return nil, nil
+}
+
+// histogramMayContain returns false if the given histogram indicates that
+// there are no rows with the given value.
+func histogramMayContain(evalCtx *eval.Context, hist []cat.HistogramBucket, d tree.Datum) bool {
+	i := sort.Search(len(hist), func(i int) bool {
+		return hist[i].UpperBound.Compare(evalCtx, d) >= 0
+	})
+	if i == len(hist) {
+		// The value is larger than the upper bound of the histogram.
+		return false
+	}
+	if hist[i].UpperBound.Compare(evalCtx, d) == 0 {
+		return hist[i].NumEq > 0
+	}
+	return hist[i].NumRange > 0
+}
+
+// EmptyPartitionCandidates returns the sorted names of the partitions whose
+// spans are removed from the scan when it is executed if they are still empty.
+// It is used to annotate the scan in EXPLAIN output.
+func (n *scanNode) EmptyPartitionCandidates() []string {
+	var names []string
+	for i := range n.emptyPartitionCandidates {
+		names = append(names, n.emptyPartitionCandidates[i].name)
+	}
+	sort.Strings(names)
+	// A partition has several candidates if it has several prefixes.
+	res := names[:0]
+	for i := range names {
+		if i == 0 || names[i] != names[i-1] {
+			res = append(res, names[i])
+		}
+	}
+	return res
+}
@@ -122,6 +122,9 @@ type SchemaChanger struct {
 	settings             *cluster.Settings
 	execCfg              *ExecutorConfig
 	ieFactory            sqlutil.InternalExecutorFactory
+	// blockingTxns, if set, looks up the transactions which block the
+	// transactions run by txn and txnWithExecutor.
+	blockingTxns *BlockingTxnWatcher
 }
 
 // NewSchemaChangerForTesting only for tests.
@@ -448,6 +451,10 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 			return nil
 		}
 		mut.State = descpb.DescriptorState_PUBLIC
+		if mut.MaterializedView() && !mut.IsRefreshViewRequired() {
+			// The view was backfilled as of its creation time.
+			mut.LastRefreshTime = mut.GetCreateAsOfTime()
+		}
 		return descsCol.WriteDesc(ctx, true /* kvTrace */, mut, txn)
 	})
 }
@@ -2453,7 +2460,13 @@ func (sc *SchemaChanger) txn(
 			return err
 		}
 	}
-	return sc.execCfg.CollectionFactory.Txn(ctx, sc.db, f)
+	defer sc.blockingTxns.SetTxn(nil)
+	return sc.execCfg.CollectionFactory.Txn(ctx, sc.db, func(
+		ctx context.Context, txn *kv.Txn, descsCol *descs.Collection,
+	) error {
+		sc.blockingTxns.SetTxn(txn)
+		return f(ctx, txn, descsCol)
+	})
 }
 
 // txnWithExecutor is to run internal executor within a txn.
@@ -2467,7 +2480,13 @@ func (sc *SchemaChanger) txnWithExecutor(
 			return err
 		}
 	}
-	return sc.execCfg.CollectionFactory.TxnWithExecutor(ctx, sc.db, sd, f)
+	defer sc.blockingTxns.SetTxn(nil)
+	return sc.execCfg.CollectionFactory.TxnWithExecutor(ctx, sc.db, sd, func(
+		ctx context.Context, txn *kv.Txn, descsCol *descs.Collection, ie sqlutil.InternalExecutor,
+	) error {
+		sc.blockingTxns.SetTxn(txn)
+		return f(ctx, txn, descsCol, ie)
+	})
 }
 
 // createSchemaChangeEvalCtx creates an extendedEvalContext() to be used for backfills.
@@ -2598,6 +2617,11 @@ func (r schemaChangeResumer) Resume(ctx context.Context, execCtx interface{}) er
 			Multiplier:     1.5,
 		}
 
+		// The transactions of the schema change are watched for transactions
+		// blocking them on descriptors across all of the attempts below.
+		sc.blockingTxns = StartBlockingTxnWatcher(ctx, sc.execCfg, sc.job)
+		defer sc.blockingTxns.Stop(ctx)
+
 		// The schema change may have to be retried if it is not first in line or
 		// for other retriable reasons so we run it in an exponential backoff retry
 		// loop. The loop terminates only if the context is canceled.
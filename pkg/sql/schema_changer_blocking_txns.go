@@ -0,0 +1,345 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// schemaChangeBlockingTxnPushDelay is the duration after which the
+// transactions of a schema change abort the older transactions which hold
+// locks on the descriptors they are waiting on.
+var schemaChangeBlockingTxnPushDelay = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"sql.schema_change.blocking_txn_push_delay",
+	"if positive, a transaction of a schema change which has waited for this long on locks "+
+		"held on descriptors by transactions that started before it aborts those transactions; "+
+		"if zero, schema changes wait for such transactions to finish",
+	0,
+	settings.NonNegativeDuration,
+)
+
+// schemaChangeBlockingTxnPollInterval is the initial interval at which the
+// locks blocking the transactions of a schema change are looked up. The
+// interval doubles every time no blocking transaction is found, up to
+// schemaChangeBlockingTxnMaxPollInterval, and is reset once one is found.
+var schemaChangeBlockingTxnPollInterval = time.Second
+
+// schemaChangeBlockingTxnMaxPollInterval is the maximum interval at which the
+// locks blocking the transactions of a schema change are looked up.
+var schemaChangeBlockingTxnMaxPollInterval = 30 * time.Second
+
+// blockingTxn is a transaction holding a lock on a descriptor which a
+// transaction of a schema change is waiting on.
+type blockingTxn struct {
+	txn          enginepb.TxnMeta
+	holdDuration time.Duration
+}
+
+// BlockingTxnWatcher looks up the transactions which block the transactions
+// of a schema change job on descriptors. It is used by both the legacy and the
+// declarative schema changer. A single watcher runs for the whole execution of
+// the job, and looks up the transactions blocking the transaction the job is
+// running at the time, if any. The lookups back off while nothing blocks the
+// job.
+//
+// The blocking transactions are reported in the running status of the job of
+// the schema change, which makes the schema changes waiting on other
+// transactions, and the transactions they are waiting on, visible in SHOW
+// JOBS. Once a schema change has waited for longer than
+// sql.schema_change.blocking_txn_push_delay, the blocking transactions which
+// started before the transaction of the schema change are aborted by pushing
+// them with the maximum priority, as the schema change would itself if it ran
+// with a high priority.
+// Newer transactions are never aborted, so that they can't be starved by
+// schema changes in turn. Tenants can't push transactions, so blocking
+// transactions are only aborted for schema changes of the system tenant.
+type BlockingTxnWatcher struct {
+	execCfg *ExecutorConfig
+	job     *jobs.Job
+	cancel  func()
+	done    chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		// txn is the transaction of the schema change currently being run, and
+		// start and startTS the time at which it started.
+		txn     *kv.Txn
+		start   time.Time
+		startTS hlc.Timestamp
+		// status is the running status set on the job, if any, and prevStatus
+		// the running status it replaced.
+		status, prevStatus jobs.RunningStatus
+	}
+}
+
+// StartBlockingTxnWatcher starts a watcher for the transactions run by the
+// given schema change job, which have to be registered with SetTxn. The watcher
+// must be stopped once the job is done running.
+func StartBlockingTxnWatcher(
+	ctx context.Context, execCfg *ExecutorConfig, job *jobs.Job,
+) *BlockingTxnWatcher {
+	w := &BlockingTxnWatcher{
+		execCfg: execCfg,
+		job:     job,
+		done:    make(chan struct{}),
+	}
+	if job == nil || execCfg.DistSQLSrv == nil {
+		close(w.done)
+		return w
+	}
+	stopper := execCfg.DistSQLSrv.Stopper
+	watchCtx, cancel := stopper.WithCancelOnQuiesce(ctx)
+	w.cancel = cancel
+	if err := stopper.RunAsyncTask(watchCtx, "schema-change-blocking-txns", func(ctx context.Context) {
+		defer close(w.done)
+		w.run(ctx)
+	}); err != nil {
+		cancel()
+		close(w.done)
+	}
+	return w
+}
+
+// SetTxn sets the transaction of the schema change currently being run, or
+// clears it if txn is nil. The watcher may be nil, in which case SetTxn does
+// nothing.
+func (w *BlockingTxnWatcher) SetTxn(txn *kv.Txn) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if txn != nil && w.mu.txn == txn {
+		// The transaction is being retried.
+		return
+	}
+	w.mu.txn = txn
+	w.mu.start = timeutil.Now()
+	w.mu.startTS = w.execCfg.Clock.Now()
+}
+
+// Stop stops the watcher, and restores the running status of the job if the
+// watcher changed it. The watcher may be nil, in which case Stop does nothing.
+func (w *BlockingTxnWatcher) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if w.cancel != nil {
+		w.cancel()
+	}
+	<-w.done
+	if err := w.setRunningStatus(ctx, ""); err != nil {
+		log.Warningf(ctx, "failed to restore running status of job %d: %v", w.job.ID(), err)
+	}
+}
+
+func (w *BlockingTxnWatcher) run(ctx context.Context) {
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+	interval := schemaChangeBlockingTxnPollInterval
+	for {
+		timer.Reset(w.nextPollDelay(interval))
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Read = true
+		}
+		blocked, err := w.check(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Warningf(ctx, "failed to check for transactions blocking job %d: %v", w.job.ID(), err)
+		}
+		if blocked {
+			interval = schemaChangeBlockingTxnPollInterval
+		} else {
+			interval *= 2
+			if interval > schemaChangeBlockingTxnMaxPollInterval {
+				interval = schemaChangeBlockingTxnMaxPollInterval
+			}
+		}
+	}
+}
+
+// nextPollDelay returns how long to wait before looking up the blocking
+// transactions again, given the current backoff interval. The wait is cut
+// short so that the lookup happens when the push delay of the current
+// transaction of the schema change elapses.
+func (w *BlockingTxnWatcher) nextPollDelay(interval time.Duration) time.Duration {
+	delay := schemaChangeBlockingTxnPushDelay.Get(&w.execCfg.Settings.SV)
+	if delay == 0 {
+		return interval
+	}
+	w.mu.Lock()
+	txn, start := w.mu.txn, w.mu.start
+	w.mu.Unlock()
+	if txn == nil {
+		return interval
+	}
+	if untilPush := delay - timeutil.Since(start); untilPush > 0 && untilPush < interval {
+		return untilPush
+	}
+	return interval
+}
+
+// check looks up the transactions blocking the current transaction of the
+// schema change, reports them and aborts them if the schema change has waited
+// on them for too long. It returns whether any transaction was blocking the
+// schema change.
+func (w *BlockingTxnWatcher) check(ctx context.Context) (blocked bool, _ error) {
+	w.mu.Lock()
+	txn, start, startTS := w.mu.txn, w.mu.start, w.mu.startTS
+	w.mu.Unlock()
+	var blockers []blockingTxn
+	if txn != nil {
+		var err error
+		if blockers, err = w.findBlockingTxns(ctx, txn.ID()); err != nil {
+			return false, err
+		}
+	}
+	var status jobs.RunningStatus
+	if len(blockers) > 0 {
+		var b strings.Builder
+		b.WriteString("waiting for transactions holding locks on descriptors:")
+		for i, blocker := range blockers {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, " %s (held for %s)",
+				blocker.txn.ID.Short(), blocker.holdDuration.Round(time.Second))
+		}
+		status = jobs.RunningStatus(b.String())
+	}
+	blocked = len(blockers) > 0
+	if err := w.setRunningStatus(ctx, status); err != nil {
+		return blocked, err
+	}
+
+	delay := schemaChangeBlockingTxnPushDelay.Get(&w.execCfg.Settings.SV)
+	if delay == 0 || timeutil.Since(start) < delay || !w.execCfg.Codec.ForSystemTenant() {
+		return blocked, nil
+	}
+	for _, blocker := range blockers {
+		if !blocker.txn.MinTimestamp.Less(startTS) {
+			continue
+		}
+		if err := w.abortBlockingTxn(ctx, blocker.txn); err != nil {
+			return blocked, errors.Wrapf(err, "failed to abort transaction %s", blocker.txn.ID.Short())
+		}
+		w.execCfg.SchemaChangerMetrics.BlockingTxnsAborted.Inc(1)
+		log.Infof(ctx, "job %d aborted transaction %s which held a lock on a descriptor for %s",
+			w.job.ID(), blocker.txn.ID.Short(), blocker.holdDuration)
+	}
+	return blocked, nil
+}
+
+// findBlockingTxns returns the transactions holding locks on descriptors which
+// the given transaction is waiting on.
+func (w *BlockingTxnWatcher) findBlockingTxns(
+	ctx context.Context, txnID uuid.UUID,
+) ([]blockingTxn, error) {
+	span := roachpb.Span{Key: w.execCfg.Codec.TablePrefix(keys.DescriptorTableID)}
+	span.EndKey = span.Key.PrefixEnd()
+	var b kv.Batch
+	b.AddRawRequest(&roachpb.QueryLocksRequest{
+		RequestHeader: roachpb.RequestHeaderFromSpan(span),
+	})
+	if err := w.execCfg.DB.Run(ctx, &b); err != nil {
+		return nil, err
+	}
+	var blockers []blockingTxn
+	seen := make(map[uuid.UUID]struct{})
+	for _, l := range b.RawResponse().Responses[0].GetQueryLocks().Locks {
+		if l.LockHolder == nil || l.LockHolder.ID == txnID {
+			continue
+		}
+		if _, ok := seen[l.LockHolder.ID]; ok {
+			continue
+		}
+		for _, waiter := range l.Waiters {
+			if waiter.WaitingTxn != nil && waiter.WaitingTxn.ID == txnID {
+				seen[l.LockHolder.ID] = struct{}{}
+				blockers = append(blockers, blockingTxn{txn: *l.LockHolder, holdDuration: l.HoldDuration})
+				break
+			}
+		}
+	}
+	return blockers, nil
+}
+
+// abortBlockingTxn aborts the given transaction by pushing it with the
+// maximum priority.
+func (w *BlockingTxnWatcher) abortBlockingTxn(ctx context.Context, txn enginepb.TxnMeta) error {
+	var b kv.Batch
+	b.Header.Timestamp = w.execCfg.Clock.Now()
+	b.AddRawRequest(&roachpb.PushTxnRequest{
+		RequestHeader: roachpb.RequestHeader{Key: txn.Key},
+		PusherTxn: roachpb.Transaction{
+			TxnMeta: enginepb.TxnMeta{Priority: enginepb.MaxTxnPriority},
+		},
+		PusheeTxn: txn,
+		PushType:  roachpb.PUSH_ABORT,
+	})
+	return w.execCfg.DB.Run(ctx, &b)
+}
+
+// setRunningStatus sets the running status of the job to the given status. An
+// empty status restores the running status of the job from before the watcher
+// changed it, unless it was changed since.
+func (w *BlockingTxnWatcher) setRunningStatus(ctx context.Context, status jobs.RunningStatus) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if status == w.mu.status {
+		return nil
+	}
+	if err := w.job.Update(ctx, nil /* txn */, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		current := jobs.RunningStatus(md.Progress.RunningStatus)
+		switch {
+		case w.mu.status == "":
+			w.mu.prevStatus = current
+		case current != w.mu.status:
+			// The schema change updated the running status in the meantime.
+			w.mu.prevStatus = current
+			if status == "" {
+				return nil
+			}
+		}
+		if status == "" {
+			md.Progress.RunningStatus = string(w.mu.prevStatus)
+		} else {
+			md.Progress.RunningStatus = string(status)
+		}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+	w.mu.status = status
+	return nil
+}
@@ -43,6 +43,12 @@ var (
 		Measurement: "Errors",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaBlockingTxnsAborted = metric.Metadata{
+		Name:        "sql.schema_changer.blocking_txns_aborted",
+		Help:        "Counter of the number of transactions aborted by the schema changer for holding locks on descriptors for too long",
+		Measurement: "Transactions",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // SchemaChangerMetrics are metrics corresponding to the schema changer.
@@ -51,6 +57,7 @@ type SchemaChangerMetrics struct {
 	Successes            *metric.Counter
 	RetryErrors          *metric.Counter
 	PermanentErrors      *metric.Counter
+	BlockingTxnsAborted  *metric.Counter
 	ConstraintErrors     telemetry.Counter
 	UncategorizedErrors  telemetry.Counter
 }
@@ -67,6 +74,7 @@ func NewSchemaChangerMetrics() *SchemaChangerMetrics {
 		Successes:            metric.NewCounter(metaSuccesses),
 		RetryErrors:          metric.NewCounter(metaRetryErrors),
 		PermanentErrors:      metric.NewCounter(metaPermanentErrors),
+		BlockingTxnsAborted:  metric.NewCounter(metaBlockingTxnsAborted),
 		ConstraintErrors:     sqltelemetry.SchemaChangeErrorCounter("constraint_violation"),
 		UncategorizedErrors:  sqltelemetry.SchemaChangeErrorCounter("uncategorized"),
 	}
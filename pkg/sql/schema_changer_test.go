@@ -47,6 +47,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/gcjob"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltestutils"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
@@ -8354,3 +8357,81 @@ SELECT fraction_completed > 0
 		}
 	}
 }
+
+// TestSchemaChangeAbortsBlockingTxns checks that a schema change aborts an
+// older transaction holding a lock on the descriptor it is waiting on once
+// sql.schema_change.blocking_txn_push_delay has elapsed, with both the legacy
+// and the declarative schema changer.
+func TestSchemaChangeAbortsBlockingTxns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testutils.RunTrueAndFalse(t, "declarative", func(t *testing.T, declarative bool) {
+		ctx := context.Background()
+		blocked := make(chan struct{})
+		proceed := make(chan struct{})
+		var once sync.Once
+		block := func() {
+			once.Do(func() {
+				close(blocked)
+				<-proceed
+			})
+		}
+		params, _ := tests.CreateTestServerParams()
+		params.Knobs = base.TestingKnobs{
+			SQLSchemaChanger: &sql.SchemaChangerTestingKnobs{
+				RunBeforeBackfill: func() error {
+					block()
+					return nil
+				},
+			},
+			SQLDeclarativeSchemaChanger: &scexec.TestingKnobs{
+				BeforeStage: func(p scplan.Plan, stageIdx int) error {
+					if p.Params.ExecutionPhase == scop.PostCommitPhase {
+						block()
+					}
+					return nil
+				},
+			},
+		}
+		s, sqlDB, kvDB := serverutils.StartServer(t, params)
+		defer s.Stopper().Stop(ctx)
+
+		runner := sqlutils.MakeSQLRunner(sqlDB)
+		runner.Exec(t, `SET CLUSTER SETTING sql.schema_change.blocking_txn_push_delay = '10ms'`)
+		runner.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY)`)
+		desc := desctestutils.TestingGetPublicTableDescriptor(kvDB, keys.SystemSQLCodec, "defaultdb", "t")
+		descKey := catalogkeys.MakeDescMetadataKey(keys.SystemSQLCodec, desc.GetID())
+
+		// The blocking transaction starts before the schema change so that it is
+		// older than all of its transactions. The declarative schema changer
+		// blocks from within one of its transactions.
+		txn := kvDB.NewTxn(ctx, "blocking-txn")
+
+		useDeclarative := "off"
+		if declarative {
+			useDeclarative = "on"
+		}
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := sqlDB.Exec(fmt.Sprintf(`
+SET use_declarative_schema_changer = %s;
+ALTER TABLE t ADD COLUMN b INT DEFAULT 1;
+`, useDeclarative))
+			errCh <- err
+		}()
+		<-blocked
+
+		// Hold a lock on the descriptor of the table in the blocking
+		// transaction, which stays open while the schema change resumes.
+		descKV, err := kvDB.Get(ctx, descKey)
+		require.NoError(t, err)
+		require.NoError(t, txn.Put(ctx, descKey, descKV.ValueBytes()))
+		close(proceed)
+
+		require.NoError(t, <-errCh)
+		require.Error(t, txn.Commit(ctx))
+		execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+		require.Positive(t, execCfg.SchemaChangerMetrics.BlockingTxnsAborted.Count())
+	})
+}
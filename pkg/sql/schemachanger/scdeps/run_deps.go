@@ -29,8 +29,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
+// TxnObserver is notified of the transaction opened by WithTxnInJob while the
+// transaction runs, and with a nil transaction once it is done.
+type TxnObserver = func(*kv.Txn)
+
 // NewJobRunDependencies returns an scrun.JobRunDependencies implementation built from the
-// given arguments.
+// given arguments. The txnObserver may be nil.
 func NewJobRunDependencies(
 	collectionFactory *descs.CollectionFactory,
 	db *kv.DB,
@@ -49,6 +53,7 @@ func NewJobRunDependencies(
 	statements []string,
 	sessionData *sessiondata.SessionData,
 	kvTrace bool,
+	txnObserver TxnObserver,
 ) scrun.JobRunDependencies {
 	return &jobExecutionDeps{
 		collectionFactory:     collectionFactory,
@@ -68,6 +73,7 @@ func NewJobRunDependencies(
 		sessionData:           sessionData,
 		kvTrace:               kvTrace,
 		statsRefresher:        statsRefresher,
+		txnObserver:           txnObserver,
 	}
 }
 
@@ -83,6 +89,7 @@ type jobExecutionDeps struct {
 	jobRegistry           *jobs.Registry
 	job                   *jobs.Job
 	kvTrace               bool
+	txnObserver           TxnObserver
 
 	indexValidator scexec.IndexValidator
 
@@ -104,9 +111,15 @@ func (d *jobExecutionDeps) ClusterSettings() *cluster.Settings {
 func (d *jobExecutionDeps) WithTxnInJob(ctx context.Context, fn scrun.JobTxnFunc) error {
 	var createdJobs []jobspb.JobID
 	var tableStatsToRefresh []descpb.ID
+	if d.txnObserver != nil {
+		defer d.txnObserver(nil)
+	}
 	err := d.collectionFactory.Txn(ctx, d.db, func(
 		ctx context.Context, txn *kv.Txn, descriptors *descs.Collection,
 	) error {
+		if d.txnObserver != nil {
+			d.txnObserver(txn)
+		}
 		pl := d.job.Payload()
 		ed := &execDeps{
 			txnDeps: txnDeps{
@@ -67,6 +67,10 @@ func (n *newSchemaChangeResumer) run(ctx context.Context, execCtxI interface{})
 		return err
 	}
 	payload := n.job.Payload()
+	// The transactions of the schema change are watched for transactions
+	// blocking them on descriptors.
+	blockingTxns := sql.StartBlockingTxnWatcher(ctx, execCfg, n.job)
+	defer blockingTxns.Stop(ctx)
 	deps := scdeps.NewJobRunDependencies(
 		execCfg.CollectionFactory,
 		execCfg.DB,
@@ -95,6 +99,7 @@ func (n *newSchemaChangeResumer) run(ctx context.Context, execCtxI interface{})
 		payload.Statement,
 		execCtx.SessionData(),
 		execCtx.ExtendedEvalContext().Tracing.KVTracingEnabled(),
+		blockingTxns.SetTxn,
 	)
 
 	err := scrun.RunSchemaChangesInJob(
@@ -511,6 +511,29 @@ The output can be used to recreate a database.'
 			makeShowCreateAllTypesGenerator,
 			`Returns rows of CREATE type statements.
 The output can be used to recreate a database.'
+`,
+			volatility.Volatile,
+		),
+	),
+	"crdb_internal.show_create_all_objects": makeBuiltin(
+		tree.FunctionProperties{
+			Class: tree.GeneratorClass,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{"database_name", types.String},
+			},
+			showCreateAllObjectsGeneratorType,
+			makeShowCreateAllObjectsGenerator,
+			`Returns rows of CREATE schema, type, table, sequence and view
+statements followed by ALTER table statements that add foreign keys and
+GRANT statements. The rows are ordered by dependencies, so that the output can
+be executed in another database or cluster to recreate the database. The public
+schema and the privileges of the admin and root roles are omitted. Roles are
+not created and owners are not preserved, so the roles named in the GRANT
+statements must exist wherever the output is executed.
+It is not recommended to perform this operation on a database with many
+tables.
 `,
 			volatility.Volatile,
 		),
@@ -545,6 +568,21 @@ The output can be used to recreate a database.'
 			volatility.Volatile,
 		),
 	),
+	"crdb_internal.index_constraint_spans": makeBuiltin(
+		tree.FunctionProperties{
+			Class:    tree.GeneratorClass,
+			Category: builtinconstants.CategorySystemInfo,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{"query", types.String},
+			},
+			indexConstraintSpansGeneratorType,
+			makeIndexConstraintSpansGenerator,
+			`Returns the spans that the optimizer derives from the filters of the given SELECT query for each index of the scanned tables, one row per span. The span is NULL if the filters do not constrain the index. The query is not planned nor executed.`,
+			volatility.Volatile,
+		),
+	),
 }
 
 var decodePlanGistGeneratorType = types.String
@@ -597,6 +635,64 @@ func makeDecodeExternalPlanGistGenerator(
 	return &gistPlanGenerator{gist: gist, evalCtx: ctx, external: true}, nil
 }
 
+var indexConstraintSpansGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.String, types.String, types.String},
+	[]string{"table_name", "index_name", "span"},
+)
+
+// indexConstraintSpansGenerator implements
+// crdb_internal.index_constraint_spans.
+type indexConstraintSpansGenerator struct {
+	query   string
+	evalCtx *eval.Context
+	spans   []eval.IndexConstraintSpan
+	index   int
+}
+
+var _ eval.ValueGenerator = &indexConstraintSpansGenerator{}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *indexConstraintSpansGenerator) ResolvedType() *types.T {
+	return indexConstraintSpansGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *indexConstraintSpansGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	spans, err := g.evalCtx.Planner.IndexConstraintSpans(ctx, g.query)
+	if err != nil {
+		return err
+	}
+	g.spans = spans
+	g.index = -1
+	return nil
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *indexConstraintSpansGenerator) Next(context.Context) (bool, error) {
+	g.index++
+	return g.index < len(g.spans), nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *indexConstraintSpansGenerator) Close(context.Context) {}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *indexConstraintSpansGenerator) Values() (tree.Datums, error) {
+	s := &g.spans[g.index]
+	span := tree.DNull
+	if s.Span != "" {
+		span = tree.NewDString(s.Span)
+	}
+	return tree.Datums{tree.NewDString(s.TableName), tree.NewDString(s.IndexName), span}, nil
+}
+
+func makeIndexConstraintSpansGenerator(
+	ctx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	query := string(tree.MustBeDString(args[0]))
+	return &indexConstraintSpansGenerator{query: query, evalCtx: ctx}, nil
+}
+
 func makeGeneratorOverload(
 	in tree.TypeList, ret *types.T, g eval.GeneratorOverload, info string, volatility volatility.V,
 ) tree.Overload {
@@ -2299,6 +2395,7 @@ func (p *payloadsForTraceGenerator) Close(_ context.Context) {
 var showCreateAllSchemasGeneratorType = types.String
 var showCreateAllTypesGeneratorType = types.String
 var showCreateAllTablesGeneratorType = types.String
+var showCreateAllObjectsGeneratorType = types.String
 
 // Phase is used to determine if CREATE statements or ALTER statements
 // are being generated for showCreateAllTables.
@@ -2626,3 +2723,130 @@ func makeShowCreateAllTypesGenerator(
 		acc:         ctx.Mon.MakeBoundAccount(),
 	}, nil
 }
+
+// showCreateAllObjectsGenerator supports the execution of
+// crdb_internal.show_create_all_objects(dbName). It chains the
+// crdb_internal.show_create_all_{schemas,types,tables} generators, which are
+// ordered such that every object is created after the objects it depends on,
+// and then generates the GRANT statements of the database's objects.
+type showCreateAllObjectsGenerator struct {
+	evalPlanner eval.Planner
+	txn         *kv.Txn
+	dbName      string
+	acc         mon.BoundAccount
+
+	// gens are the generators of the CREATE and ALTER statements, in the order
+	// in which their statements must be executed.
+	gens []eval.ValueGenerator
+
+	// The following variables are updated during
+	// calls to Next() and change throughout the lifecycle of
+	// showCreateAllObjectsGenerator.
+	curr       tree.Datum
+	genIdx     int
+	genStarted bool
+	grants     []string
+	grantIdx   int
+}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (s *showCreateAllObjectsGenerator) ResolvedType() *types.T {
+	return showCreateAllObjectsGeneratorType
+}
+
+// Start implements the tree.ValueGenerator interface.
+func (s *showCreateAllObjectsGenerator) Start(ctx context.Context, txn *kv.Txn) error {
+	// The chained generators are started lazily, so that only one of them
+	// holds the ids of its objects at a time.
+	s.txn = txn
+	s.genIdx = 0
+	s.genStarted = false
+	s.grantIdx = -1
+	return nil
+}
+
+func (s *showCreateAllObjectsGenerator) Next(ctx context.Context) (bool, error) {
+	for s.genIdx < len(s.gens) {
+		gen := s.gens[s.genIdx]
+		if !s.genStarted {
+			if err := gen.Start(ctx, s.txn); err != nil {
+				return false, err
+			}
+			s.genStarted = true
+		}
+		ok, err := gen.Next(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			gen.Close(ctx)
+			s.genIdx++
+			s.genStarted = false
+			if s.genIdx == len(s.gens) {
+				// All the objects were created, generate their grants.
+				s.grants, err = getGrantStatements(ctx, s.evalPlanner, s.txn, s.dbName, &s.acc)
+				if err != nil {
+					return false, err
+				}
+			}
+			continue
+		}
+		vals, err := gen.Values()
+		if err != nil {
+			return false, err
+		}
+		if vals[0] != tree.DNull && string(tree.MustBeDString(vals[0])) == createPublicSchemaStatement {
+			continue
+		}
+		s.curr = vals[0]
+		return true, nil
+	}
+
+	s.grantIdx++
+	if s.grantIdx >= len(s.grants) {
+		return false, nil
+	}
+	s.curr = tree.NewDString(s.grants[s.grantIdx])
+	return true, nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (s *showCreateAllObjectsGenerator) Values() (tree.Datums, error) {
+	return tree.Datums{s.curr}, nil
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (s *showCreateAllObjectsGenerator) Close(ctx context.Context) {
+	// The generators before genIdx were closed once they were exhausted.
+	for _, gen := range s.gens[s.genIdx:] {
+		gen.Close(ctx)
+	}
+	s.genIdx = len(s.gens)
+	s.acc.Close(ctx)
+}
+
+// makeShowCreateAllObjectsGenerator creates a generator to support the
+// crdb_internal.show_create_all_objects(dbName) builtin.
+func makeShowCreateAllObjectsGenerator(
+	ctx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	dbName := string(tree.MustBeDString(args[0]))
+	var gens []eval.ValueGenerator
+	for _, makeGen := range []eval.GeneratorOverload{
+		makeShowCreateAllSchemasGenerator,
+		makeShowCreateAllTypesGenerator,
+		makeShowCreateAllTablesGenerator,
+	} {
+		gen, err := makeGen(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		gens = append(gens, gen)
+	}
+	return &showCreateAllObjectsGenerator{
+		evalPlanner: ctx.Planner,
+		dbName:      dbName,
+		acc:         ctx.Mon.MakeBoundAccount(),
+		gens:        gens,
+	}, nil
+}
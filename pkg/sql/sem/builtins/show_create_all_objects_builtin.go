@@ -0,0 +1,116 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// createPublicSchemaStatement is the statement generated by
+// crdb_internal.show_create_all_schemas for the public schema, which exists
+// in every database and is therefore omitted from
+// crdb_internal.show_create_all_objects.
+const createPublicSchemaStatement = "CREATE SCHEMA public;"
+
+// getGrantStatements returns the GRANT statements that recreate the
+// privileges on the schemas, types, tables, sequences and views of a
+// specified database. The privileges of the admin and root roles are omitted
+// since they cannot be revoked, and so are the privileges on the implicit array
+// types of user-defined types, which follow the privileges of the types they
+// belong to. Schema grants are ordered before type grants, which are ordered
+// before table grants. The grantees are not created by the dump, so they must
+// exist wherever the statements are executed.
+func getGrantStatements(
+	ctx context.Context, evalPlanner eval.Planner, txn *kv.Txn, dbName string, acc *mon.BoundAccount,
+) (grantStmts []string, retErr error) {
+	query := fmt.Sprintf(`
+		SELECT object_type, schema_name, object_name, grantee, is_grantable,
+		       array_agg(privilege_type ORDER BY privilege_type)
+		FROM (
+			SELECT 1 AS ord, 'SCHEMA' AS object_type, table_schema AS schema_name,
+			       '' AS object_name, grantee, privilege_type, is_grantable
+			FROM %[1]s.information_schema.schema_privileges
+			WHERE table_catalog = $1
+			UNION ALL
+			SELECT 2, 'TYPE', type_schema, type_name, grantee, privilege_type, is_grantable
+			FROM %[1]s.information_schema.type_privileges
+			WHERE type_catalog = $1
+				AND (type_schema, type_name) IN (
+					SELECT schema_name, descriptor_name
+					FROM %[1]s.crdb_internal.create_type_statements
+					WHERE database_name = $1
+				)
+			UNION ALL
+			SELECT 3, 'TABLE', table_schema, table_name, grantee, privilege_type, is_grantable
+			FROM %[1]s.information_schema.table_privileges
+			WHERE table_catalog = $1
+		)
+		WHERE schema_name NOT IN ('crdb_internal', 'information_schema', 'pg_catalog', 'pg_extension')
+			AND schema_name NOT LIKE 'pg_temp%%'
+			AND grantee NOT IN ('admin', 'root')
+		GROUP BY ord, object_type, schema_name, object_name, grantee, is_grantable
+		ORDER BY ord, schema_name, object_name, grantee, is_grantable
+		`, dbName)
+	it, err := evalPlanner.QueryIteratorEx(
+		ctx,
+		"crdb_internal.show_create_all_objects",
+		sessiondata.NoSessionDataOverride,
+		query,
+		dbName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = errors.CombineErrors(retErr, it.Close())
+	}()
+
+	var ok bool
+	for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+		row := it.Cur()
+		objectType := string(tree.MustBeDString(row[0]))
+		objectName := tree.NameString(string(tree.MustBeDString(row[1])))
+		if objectType != "SCHEMA" {
+			objectName += "." + tree.NameString(string(tree.MustBeDString(row[2])))
+		}
+		grantee := tree.NameString(string(tree.MustBeDString(row[3])))
+		var privs []string
+		for _, priv := range tree.MustBeDArray(row[5]).Array {
+			privs = append(privs, string(tree.MustBeDString(priv)))
+		}
+
+		stmt := fmt.Sprintf(
+			"GRANT %s ON %s %s TO %s", strings.Join(privs, ", "), objectType, objectName, grantee,
+		)
+		if row[4] != tree.DNull && string(tree.MustBeDString(row[4])) == "YES" {
+			stmt += " WITH GRANT OPTION"
+		}
+		stmt += ";"
+		grantStmts = append(grantStmts, stmt)
+		if err = acc.Grow(ctx, int64(len(stmt))); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return grantStmts, err
+	}
+
+	return grantStmts, nil
+}
@@ -84,6 +84,7 @@ const (
 	SystemPrivilegeTableName               SystemTableName = "privileges"
 	SystemExternalConnectionsTableName     SystemTableName = "external_connections"
 	RoleIDSequenceName                     SystemTableName = "role_id_seq"
+	PlanPinsTableName                      SystemTableName = "plan_pins"
 )
 
 // Oid for virtual database and table.
@@ -161,6 +162,12 @@ const (
 	CrdbInternalTenantUsageDetailsViewID
 	CrdbInternalPgCatalogTableIsImplementedTableID
 	CrdbInternalSuperRegions
+	CrdbInternalClosedTimestampBlockedRanges
+	CrdbInternalNodeAdmissionDatabaseUsageTableID
+	CrdbInternalNodeTrippedCircuitBreakersTableID
+	CrdbInternalHotKeysTableID
+	CrdbInternalNodePlanPinsTableID
+	CrdbInternalRangeDetailsTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
 	InformationSchemaApplicableRolesID
@@ -269,6 +269,11 @@ type Planner interface {
 	// DecodeGist exposes gist functionality to the builtin functions.
 	DecodeGist(gist string, external bool) ([]string, error)
 
+	// IndexConstraintSpans builds the given query and returns the spans which
+	// the optimizer derives from its filters for each index of the scanned
+	// tables, without planning the query.
+	IndexConstraintSpans(ctx context.Context, query string) ([]IndexConstraintSpan, error)
+
 	// SerializeSessionState serializes the variables in the current session
 	// and returns a state, in bytes form.
 	SerializeSessionState() (*tree.DBytes, error)
@@ -356,6 +361,16 @@ type Planner interface {
 	GetMultiregionConfig(databaseID descpb.ID) (interface{}, bool)
 }
 
+// IndexConstraintSpan is a span derived by the optimizer for an index from the
+// filters of a query. It is returned by Planner.IndexConstraintSpans.
+type IndexConstraintSpan struct {
+	TableName string
+	IndexName string
+	// Span is the formatted span, "contradiction" if the filters cannot be
+	// satisfied, or empty if the filters do not constrain the index.
+	Span string
+}
+
 // InternalRows is an iterator interface that's exposed by the internal
 // executor. It provides access to the rows from a query.
 // InternalRows is a copy of the one in sql/internal.go excluding the
@@ -31,6 +31,10 @@ const (
 	RelocateVoters
 	// RelocateNonVoters indicates that non-voter replicas should be relocated.
 	RelocateNonVoters
+	// RelocateAll indicates that the replica on the source store, whether it is
+	// a voter or a non-voter, should be relocated along with the lease if the
+	// source store holds it.
+	RelocateAll
 )
 
 // Format implementsthe NodeFormatter interface.
@@ -46,6 +50,8 @@ func (n RelocateSubject) String() string {
 		return "VOTERS"
 	case RelocateNonVoters:
 		return "NONVOTERS"
+	case RelocateAll:
+		return "ALL"
 	default:
 		panic(errors.AssertionFailedf("programming error: unhandled case %d", int(n)))
 	}
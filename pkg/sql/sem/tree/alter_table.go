@@ -64,11 +64,13 @@ func (*AlterTableDropColumn) alterTableCmd()         {}
 func (*AlterTableDropConstraint) alterTableCmd()     {}
 func (*AlterTableDropNotNull) alterTableCmd()        {}
 func (*AlterTableDropStored) alterTableCmd()         {}
+func (*AlterTableDropIdentity) alterTableCmd()       {}
 func (*AlterTableSetNotNull) alterTableCmd()         {}
 func (*AlterTableRenameColumn) alterTableCmd()       {}
 func (*AlterTableRenameConstraint) alterTableCmd()   {}
 func (*AlterTableSetAudit) alterTableCmd()           {}
 func (*AlterTableSetDefault) alterTableCmd()         {}
+func (*AlterTableSetGenerated) alterTableCmd()       {}
 func (*AlterTableSetOnUpdate) alterTableCmd()        {}
 func (*AlterTableSetVisible) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
@@ -84,11 +86,13 @@ var _ AlterTableCmd = &AlterTableDropColumn{}
 var _ AlterTableCmd = &AlterTableDropConstraint{}
 var _ AlterTableCmd = &AlterTableDropNotNull{}
 var _ AlterTableCmd = &AlterTableDropStored{}
+var _ AlterTableCmd = &AlterTableDropIdentity{}
 var _ AlterTableCmd = &AlterTableSetNotNull{}
 var _ AlterTableCmd = &AlterTableRenameColumn{}
 var _ AlterTableCmd = &AlterTableRenameConstraint{}
 var _ AlterTableCmd = &AlterTableSetAudit{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
+var _ AlterTableCmd = &AlterTableSetGenerated{}
 var _ AlterTableCmd = &AlterTableSetOnUpdate{}
 var _ AlterTableCmd = &AlterTableSetVisible{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
@@ -537,6 +541,62 @@ func (node *AlterTableDropStored) Format(ctx *FmtCtx) {
 	ctx.WriteString(" DROP STORED")
 }
 
+// AlterTableSetGenerated represents an ALTER COLUMN SET GENERATED {ALWAYS |
+// BY DEFAULT} command to change the type of an identity column.
+type AlterTableSetGenerated struct {
+	Column                  Name
+	GeneratedAsIdentityType GeneratedIdentityType
+}
+
+// GetColumn implements the ColumnMutationCmd interface.
+func (node *AlterTableSetGenerated) GetColumn() Name {
+	return node.Column
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableSetGenerated) TelemetryName() string {
+	return "set_generated"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetGenerated) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ALTER COLUMN ")
+	ctx.FormatNode(&node.Column)
+	switch node.GeneratedAsIdentityType {
+	case GeneratedAlways:
+		ctx.WriteString(" SET GENERATED ALWAYS")
+	case GeneratedByDefault:
+		ctx.WriteString(" SET GENERATED BY DEFAULT")
+	}
+}
+
+// AlterTableDropIdentity represents an ALTER COLUMN DROP IDENTITY [IF EXISTS]
+// command to turn an identity column into a regular column.
+type AlterTableDropIdentity struct {
+	Column   Name
+	IfExists bool
+}
+
+// GetColumn implements the ColumnMutationCmd interface.
+func (node *AlterTableDropIdentity) GetColumn() Name {
+	return node.Column
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableDropIdentity) TelemetryName() string {
+	return "drop_identity"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableDropIdentity) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ALTER COLUMN ")
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" DROP IDENTITY")
+	if node.IfExists {
+		ctx.WriteString(" IF EXISTS")
+	}
+}
+
 // AlterTablePartitionByTable represents an ALTER TABLE PARTITION [ALL]
 // BY command.
 type AlterTablePartitionByTable struct {
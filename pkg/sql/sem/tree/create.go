@@ -2055,6 +2055,9 @@ const (
 	// RefreshDataClear refers to the WITH NO DATA option provided to the REFRESH
 	// MATERIALIZED VIEW statement.
 	RefreshDataClear
+	// RefreshDataIncremental refers to the INCREMENTAL option provided to the
+	// REFRESH MATERIALIZED VIEW statement.
+	RefreshDataIncremental
 )
 
 // Format implements the NodeFormatter interface.
@@ -2069,6 +2072,8 @@ func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH DATA")
 	case RefreshDataClear:
 		ctx.WriteString(" WITH NO DATA")
+	case RefreshDataIncremental:
+		ctx.WriteString(" INCREMENTAL")
 	}
 }
 
@@ -2193,3 +2198,19 @@ func (node *CreateExternalConnection) Format(ctx *FmtCtx) {
 	ctx.WriteString(" AS ")
 	ctx.FormatNode(node.As)
 }
+
+// CreatePlanPin represents a CREATE PLAN PIN statement.
+type CreatePlanPin struct {
+	Fingerprint Expr
+	Hints       Expr
+}
+
+var _ Statement = &CreatePlanPin{}
+
+// Format implements the Statement interface.
+func (node *CreatePlanPin) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE PLAN PIN FOR ")
+	ctx.FormatNode(node.Fingerprint)
+	ctx.WriteString(" AS ")
+	ctx.FormatNode(node.Hints)
+}
@@ -234,3 +234,21 @@ func (node *DropExternalConnection) Format(ctx *FmtCtx) {
 		ctx.FormatNode(node.ConnectionLabel)
 	}
 }
+
+// DropPlanPin represents a DROP PLAN PIN statement.
+type DropPlanPin struct {
+	Fingerprint Expr
+	IfExists    bool
+}
+
+var _ Statement = &DropPlanPin{}
+
+// Format implements the Statement interface.
+func (node *DropPlanPin) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP PLAN PIN ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.WriteString("FOR ")
+	ctx.FormatNode(node.Fingerprint)
+}
@@ -116,6 +116,7 @@ const (
 	ExplainFlagMemo
 	ExplainFlagShape
 	ExplainFlagViz
+	ExplainFlagSpans
 	numExplainFlags = iota
 )
 
@@ -128,6 +129,7 @@ var explainFlagStrings = [...]string{
 	ExplainFlagMemo:    "MEMO",
 	ExplainFlagShape:   "SHAPE",
 	ExplainFlagViz:     "VIZ",
+	ExplainFlagSpans:   "SPANS",
 }
 
 var explainFlagStringMap = func() map[string]ExplainFlag {
@@ -261,6 +263,15 @@ func MakeExplain(options []string, stmt Statement) (Statement, error) {
 		}
 	}
 
+	if opts.Flags[ExplainFlagSpans] {
+		if opts.Mode != ExplainPlan {
+			return nil, pgerror.Newf(pgcode.Syntax, "the SPANS flag can only be used with PLAN")
+		}
+		if analyze {
+			return nil, pgerror.Newf(pgcode.Syntax, "the SPANS flag cannot be used with ANALYZE")
+		}
+	}
+
 	if analyze {
 		if opts.Mode != ExplainDistSQL && opts.Mode != ExplainDebug && opts.Mode != ExplainPlan {
 			return nil, pgerror.Newf(pgcode.Syntax, "EXPLAIN ANALYZE cannot be used with %s", opts.Mode)
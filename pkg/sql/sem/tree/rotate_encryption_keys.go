@@ -0,0 +1,19 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// RotateEncryptionKeys represents a ROTATE ENCRYPTION KEYS statement.
+type RotateEncryptionKeys struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *RotateEncryptionKeys) Format(ctx *FmtCtx) {
+	ctx.WriteString("ROTATE ENCRYPTION KEYS")
+}
@@ -684,6 +684,10 @@ func (node *ShowRoles) Format(ctx *FmtCtx) {
 type ShowRanges struct {
 	TableOrIndex TableIndexName
 	DatabaseName Name
+	// Details is set when WITH DETAILS was specified, in which case the
+	// replication state reported by the leaseholder of each range is shown
+	// as well.
+	Details bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -699,6 +703,9 @@ func (node *ShowRanges) Format(ctx *FmtCtx) {
 		ctx.WriteString("TABLE ")
 		ctx.FormatNode(&node.TableOrIndex)
 	}
+	if node.Details {
+		ctx.WriteString(" WITH DETAILS")
+	}
 }
 
 // ShowRangeForRow represents a SHOW RANGE FOR ROW statement.
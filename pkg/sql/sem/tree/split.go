@@ -16,6 +16,10 @@ type Split struct {
 	// Each row contains values for the columns in the PK or index (or a prefix
 	// of the columns).
 	Rows *Select
+	// Where, if set, is a predicate on the columns of the PK or index which is
+	// used in place of Rows. The splits are placed at the boundaries of the
+	// spans of the index which satisfy the predicate.
+	Where *Where
 	// Splits can last a specified amount of time before becoming eligible for
 	// automatic merging.
 	ExpireExpr Expr
@@ -31,7 +35,11 @@ func (node *Split) Format(ctx *FmtCtx) {
 	}
 	ctx.FormatNode(&node.TableOrIndex)
 	ctx.WriteString(" SPLIT AT ")
-	ctx.FormatNode(node.Rows)
+	if node.Where != nil {
+		ctx.FormatNode(node.Where)
+	} else {
+		ctx.FormatNode(node.Rows)
+	}
 	if node.ExpireExpr != nil {
 		ctx.WriteString(" WITH EXPIRATION ")
 		ctx.FormatNode(node.ExpireExpr)
@@ -742,6 +742,24 @@ func (*DropExternalConnection) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropExternalConnection) StatementTag() string { return "DROP EXTERNAL CONNECTION" }
 
+// StatementReturnType implements the Statement interface.
+func (*CreatePlanPin) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*CreatePlanPin) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreatePlanPin) StatementTag() string { return "CREATE PLAN PIN" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropPlanPin) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*DropPlanPin) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropPlanPin) StatementTag() string { return "DROP PLAN PIN" }
+
 // StatementReturnType implements the Statement interface.
 func (*CreateIndex) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1237,6 +1255,15 @@ func (*RollbackTransaction) StatementType() StatementType { return TypeTCL }
 // StatementTag returns a short string identifying the type of statement.
 func (*RollbackTransaction) StatementTag() string { return "ROLLBACK" }
 
+// StatementReturnType implements the Statement interface.
+func (*RotateEncryptionKeys) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*RotateEncryptionKeys) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RotateEncryptionKeys) StatementTag() string { return "ROTATE ENCRYPTION KEYS" }
+
 // StatementReturnType implements the Statement interface.
 func (*Savepoint) StatementReturnType() StatementReturnType { return Ack }
 
@@ -2006,8 +2033,10 @@ func (n *AlterTableDropColumn) String() string                { return AsString(
 func (n *AlterTableDropConstraint) String() string            { return AsString(n) }
 func (n *AlterTableDropNotNull) String() string               { return AsString(n) }
 func (n *AlterTableDropStored) String() string                { return AsString(n) }
+func (n *AlterTableDropIdentity) String() string              { return AsString(n) }
 func (n *AlterTableLocality) String() string                  { return AsString(n) }
 func (n *AlterTableSetDefault) String() string                { return AsString(n) }
+func (n *AlterTableSetGenerated) String() string              { return AsString(n) }
 func (n *AlterTableSetVisible) String() string                { return AsString(n) }
 func (n *AlterTableSetNotNull) String() string                { return AsString(n) }
 func (n *AlterTableOwner) String() string                     { return AsString(n) }
@@ -2066,6 +2095,8 @@ func (n *ExplainAnalyze) String() string                      { return AsString(
 func (n *Export) String() string                              { return AsString(n) }
 func (n *CreateExternalConnection) String() string            { return AsString(n) }
 func (n *DropExternalConnection) String() string              { return AsString(n) }
+func (n *CreatePlanPin) String() string                       { return AsString(n) }
+func (n *DropPlanPin) String() string                         { return AsString(n) }
 func (n *FetchCursor) String() string                         { return AsString(n) }
 func (n *Grant) String() string                               { return AsString(n) }
 func (n *GrantRole) String() string                           { return AsString(n) }
@@ -2091,6 +2122,7 @@ func (n *Revoke) String() string                              { return AsString(
 func (n *RevokeRole) String() string                          { return AsString(n) }
 func (n *RollbackToSavepoint) String() string                 { return AsString(n) }
 func (n *RollbackTransaction) String() string                 { return AsString(n) }
+func (n *RotateEncryptionKeys) String() string                { return AsString(n) }
 func (n *Savepoint) String() string                           { return AsString(n) }
 func (n *Scatter) String() string                             { return AsString(n) }
 func (n *ScheduledBackup) String() string                     { return AsString(n) }
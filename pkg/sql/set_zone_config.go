@@ -15,8 +15,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -93,6 +95,35 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 			)
 		},
 	},
+	"closed_timestamp_target_duration": {
+		requiredType: types.Interval,
+		setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+			target := time.Duration(tree.MustBeDInterval(d).Duration.Nanos())
+			c.ClosedTimestampTargetDuration = &target
+		},
+		checkAllowed: func(ctx context.Context, execCfg *ExecutorConfig, _ tree.Datum) error {
+			if !execCfg.Settings.Version.IsActive(ctx, clusterversion.ClosedTimestampPolicyClasses) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"closed_timestamp_target_duration requires all nodes to be upgraded to %s",
+					clusterversion.ByKey(clusterversion.ClosedTimestampPolicyClasses))
+			}
+			return nil
+		},
+	},
+	"latency_based_lease_rebalancing": {
+		requiredType: types.Bool,
+		setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+			c.LatencyBasedLeaseRebalancing = proto.Bool(bool(tree.MustBeDBool(d)))
+		},
+		checkAllowed: func(ctx context.Context, execCfg *ExecutorConfig, _ tree.Datum) error {
+			if !execCfg.Settings.Version.IsActive(ctx, clusterversion.LatencyBasedLeaseRebalancing) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"latency_based_lease_rebalancing requires all nodes to be upgraded to %s",
+					clusterversion.ByKey(clusterversion.LatencyBasedLeaseRebalancing))
+			}
+			return nil
+		},
+	},
 	"num_replicas": {
 		requiredType: types.Int,
 		setter:       func(c *zonepb.ZoneConfig, d tree.Datum) { c.NumReplicas = proto.Int32(int32(tree.MustBeDInt(d))) },
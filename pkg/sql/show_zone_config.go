@@ -223,6 +223,15 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 		maybeWriteComma(f)
 		f.Printf("\tglobal_reads = %t", *zone.GlobalReads)
 	}
+	if zone.ClosedTimestampTargetDuration != nil {
+		maybeWriteComma(f)
+		f.Printf("\tclosed_timestamp_target_duration = %s",
+			lexbase.EscapeSQLString(zone.ClosedTimestampTargetDuration.String()))
+	}
+	if zone.LatencyBasedLeaseRebalancing != nil {
+		maybeWriteComma(f)
+		f.Printf("\tlatency_based_lease_rebalancing = %t", *zone.LatencyBasedLeaseRebalancing)
+	}
 	if zone.NumReplicas != nil {
 		maybeWriteComma(f)
 		f.Printf("\tnum_replicas = %d", *zone.NumReplicas)
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/constraint"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/partition"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
@@ -41,6 +42,12 @@ type Builder struct {
 	// KeyPrefix is the prefix of keys generated by the builder.
 	KeyPrefix []byte
 	alloc     tree.DatumAlloc
+
+	// mergeAdjacent, if set, causes SpansFromConstraint to merge adjacent and
+	// overlapping spans. See SetMergeAdjacentSpans.
+	mergeAdjacent bool
+	// numMerged is the number of spans that have been eliminated by merging.
+	numMerged int
 }
 
 // Init initializes a Builder with a table and index.
@@ -63,6 +70,21 @@ func (s *Builder) InitWithFetchSpec(
 	s.KeyPrefix = rowenc.MakeIndexKeyPrefix(codec, spec.TableID, spec.IndexID)
 }
 
+// SetMergeAdjacentSpans configures whether SpansFromConstraint merges adjacent
+// and overlapping spans. Merging reduces the number of requests sent to KV, for
+// example by turning the point lookups of an IN list on consecutive integers
+// into a single scan, at the cost of no longer fetching only the needed column
+// families of single rows.
+func (s *Builder) SetMergeAdjacentSpans(merge bool) {
+	s.mergeAdjacent = merge
+}
+
+// NumMergedSpans returns the number of spans that have been eliminated by
+// merging adjacent and overlapping spans. See SetMergeAdjacentSpans.
+func (s *Builder) NumMergedSpans() int {
+	return s.numMerged
+}
+
 // SpanFromEncDatums encodes a span with len(values) constraint columns from the
 // index prefixed with the index key prefix that includes the table and index
 // ID. SpanFromEncDatums assumes that the EncDatums in values are in the order
@@ -229,6 +251,16 @@ func (s *Builder) SpansFromConstraint(
 		return spans, nil
 	}
 
+	if s.mergeAdjacent && c.Spans.Count() > 1 {
+		// Merge spans with consecutive boundaries, such as [/1 - /1] [/2 - /2],
+		// which are not adjacent once encoded. The constraint is copied, since it
+		// belongs to the optimizer.
+		consolidated := *c
+		consolidated.ConsolidateSpans(s.evalCtx, partition.PrefixSorter{})
+		s.numMerged += c.Spans.Count() - consolidated.Spans.Count()
+		c = &consolidated
+	}
+
 	spans = make(roachpb.Spans, 0, c.Spans.Count())
 	for i := 0; i < c.Spans.Count(); i++ {
 		spans, err = s.appendSpansFromConstraintSpan(spans, c.Spans.Get(i), splitter)
@@ -236,9 +268,47 @@ func (s *Builder) SpansFromConstraint(
 			return nil, err
 		}
 	}
+	if s.mergeAdjacent {
+		var numMerged int
+		spans, numMerged = MergeAdjacentSpans(spans)
+		s.numMerged += numMerged
+	}
 	return spans, nil
 }
 
+// MergeAdjacentSpans merges each span into the preceding span if the two spans
+// are adjacent or overlap, and returns the merged spans along with the number
+// of spans that were eliminated. The spans must be ordered by their start keys,
+// which is the case for the spans generated from a constraint. Spans without an
+// EndKey, which represent a single key, are turned into ranges when they are
+// merged. The input slice is modified in place.
+func MergeAdjacentSpans(spans roachpb.Spans) (_ roachpb.Spans, numMerged int) {
+	if len(spans) < 2 {
+		return spans, 0
+	}
+	endKey := func(sp roachpb.Span) roachpb.Key {
+		if len(sp.EndKey) == 0 {
+			return sp.Key.Next()
+		}
+		return sp.EndKey
+	}
+	result := spans[:1]
+	for _, sp := range spans[1:] {
+		last := &result[len(result)-1]
+		lastEnd := endKey(*last)
+		if sp.Key.Compare(lastEnd) > 0 {
+			result = append(result, sp)
+			continue
+		}
+		if spEnd := endKey(sp); lastEnd.Compare(spEnd) < 0 {
+			lastEnd = spEnd
+		}
+		last.EndKey = lastEnd
+		numMerged++
+	}
+	return result, numMerged
+}
+
 // UnconstrainedSpans returns the full span corresponding to the Builder's
 // table and index.
 func (s *Builder) UnconstrainedSpans() (roachpb.Spans, error) {
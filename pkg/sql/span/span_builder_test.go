@@ -0,0 +1,83 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package span_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/span"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAdjacentSpans(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sp := func(key, endKey string) roachpb.Span {
+		s := roachpb.Span{Key: roachpb.Key(key)}
+		if endKey != "" {
+			s.EndKey = roachpb.Key(endKey)
+		}
+		return s
+	}
+
+	testCases := []struct {
+		name      string
+		spans     roachpb.Spans
+		expected  roachpb.Spans
+		numMerged int
+	}{
+		{
+			name:     "single span",
+			spans:    roachpb.Spans{sp("a", "b")},
+			expected: roachpb.Spans{sp("a", "b")},
+		},
+		{
+			name:     "disjoint spans",
+			spans:    roachpb.Spans{sp("a", "b"), sp("c", "d")},
+			expected: roachpb.Spans{sp("a", "b"), sp("c", "d")},
+		},
+		{
+			name:      "adjacent spans",
+			spans:     roachpb.Spans{sp("a", "b"), sp("b", "c"), sp("c", "d")},
+			expected:  roachpb.Spans{sp("a", "d")},
+			numMerged: 2,
+		},
+		{
+			name:      "overlapping spans",
+			spans:     roachpb.Spans{sp("a", "c"), sp("b", "d"), sp("e", "f")},
+			expected:  roachpb.Spans{sp("a", "d"), sp("e", "f")},
+			numMerged: 1,
+		},
+		{
+			name:      "contained span",
+			spans:     roachpb.Spans{sp("a", "d"), sp("b", "c")},
+			expected:  roachpb.Spans{sp("a", "d")},
+			numMerged: 1,
+		},
+		{
+			name:      "single keys",
+			spans:     roachpb.Spans{sp("a", ""), sp("a\x00", ""), sp("b", "")},
+			expected:  roachpb.Spans{sp("a", "a\x00\x00"), sp("b", "")},
+			numMerged: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, numMerged := span.MergeAdjacentSpans(tc.spans)
+			require.Equal(t, tc.expected, merged)
+			require.Equal(t, tc.numMerged, numMerged)
+		})
+	}
+}
@@ -209,6 +209,7 @@ type RecordedStmtStats struct {
 	PlanGist             string
 	StatementError       error
 	IndexRecommendations []string
+	Indexes              []string
 	Query                string
 	StartTime            time.Time
 	EndTime              time.Time
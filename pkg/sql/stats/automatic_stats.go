@@ -77,6 +77,28 @@ var MultiColumnStatisticsClusterMode = settings.RegisterBoolSetting(
 	true,
 ).WithPublic()
 
+// VirtualComputedColumnStatisticsClusterMode controls the cluster setting for
+// enabling the collection of statistics on virtual computed columns, including
+// the inaccessible virtual columns backing expression indexes.
+var VirtualComputedColumnStatisticsClusterMode = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.stats.virtual_computed_columns.enabled",
+	"set to true to collect table statistics on virtual computed columns",
+	true,
+)
+
+// MultiColumnHistogramsClusterMode controls the cluster setting for enabling
+// the collection of histograms on multi-column statistics of index key
+// prefixes. These histograms are built over the key encodings of the columns,
+// and can only be collected once all nodes are running a version which
+// supports them.
+var MultiColumnHistogramsClusterMode = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.stats.multi_column_histogram_collection.enabled",
+	"multi-column histogram collection mode",
+	false,
+)
+
 // AutomaticStatisticsMaxIdleTime controls the maximum fraction of time that
 // the sampler processors will be idle when scanning large tables for automatic
 // statistics (in high load scenarios). This value can be tuned to trade off
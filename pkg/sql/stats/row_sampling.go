@@ -16,11 +16,13 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/memsize"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/errors"
 )
@@ -240,6 +242,55 @@ func (sr *SampleReservoir) GetNonNullDatums(
 	return
 }
 
+// GetEncodedKeys returns, for each sampled row, the ascending key encodings of
+// the values of the specified columns, concatenated in order. Rows where all
+// of the columns are NULL are skipped. The encoded keys sort in the same order
+// as the keys of an index on the columns. Like GetNonNullDatums, the capacity
+// of the reservoir may shrink if we hit a memory limit while building the
+// return slice.
+func (sr *SampleReservoir) GetEncodedKeys(
+	ctx context.Context, memAcc *mon.BoundAccount, colIdxs []int,
+) (values tree.Datums, err error) {
+	err = sr.retryMaybeResize(ctx, func() error {
+		// Account for the memory we'll use copying the samples into values.
+		if memAcc != nil {
+			if err := memAcc.Grow(ctx, memsize.DatumOverhead*int64(len(sr.samples))); err != nil {
+				return err
+			}
+		}
+		values = make(tree.Datums, 0, len(sr.samples))
+		for _, sample := range sr.samples {
+			var key []byte
+			allNull := true
+			for _, colIdx := range colIdxs {
+				ed := &sample.Row[colIdx]
+				if ed.Datum == nil {
+					values = nil
+					return errors.AssertionFailedf("value in column %d not decoded", colIdx)
+				}
+				allNull = allNull && ed.IsNull()
+				var err error
+				if key, err = keyside.Encode(key, ed.Datum, encoding.Ascending); err != nil {
+					values = nil
+					return err
+				}
+			}
+			if allNull {
+				continue
+			}
+			if memAcc != nil {
+				if err := memAcc.Grow(ctx, int64(len(key))); err != nil {
+					values = nil
+					return err
+				}
+			}
+			values = append(values, tree.NewDBytes(tree.DBytes(key)))
+		}
+		return nil
+	})
+	return
+}
+
 func (sr *SampleReservoir) copyRow(
 	ctx context.Context, evalCtx *eval.Context, dst, src rowenc.EncDatumRow,
 ) error {
@@ -20,10 +20,12 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 )
@@ -135,6 +137,61 @@ func TestSampleReservoir(t *testing.T) {
 	}
 }
 
+func TestSampleReservoirGetEncodedKeys(t *testing.T) {
+	ctx := context.Background()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+
+	typs := []*types.T{types.Int, types.Int}
+	rows := [][2]tree.Datum{
+		{tree.NewDInt(2), tree.NewDInt(1)},
+		{tree.DNull, tree.DNull},
+		{tree.NewDInt(1), tree.NewDInt(5)},
+		{tree.NewDInt(1), tree.DNull},
+		{tree.NewDInt(-3), tree.NewDInt(7)},
+	}
+	var sr SampleReservoir
+	sr.Init(len(rows), 1, typs, nil /* memAcc */, util.MakeFastIntSet(0, 1))
+	for i, r := range rows {
+		row := rowenc.EncDatumRow{
+			rowenc.DatumToEncDatum(typs[0], r[0]), rowenc.DatumToEncDatum(typs[1], r[1]),
+		}
+		if err := sr.SampleRow(ctx, &evalCtx, row, uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	values, err := sr.GetEncodedKeys(ctx, nil /* memAcc */, []int{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The row where both columns are NULL is skipped.
+	if len(values) != len(rows)-1 {
+		t.Fatalf("expected %d keys, found %d", len(rows)-1, len(values))
+	}
+	// The encoded keys sort like the rows of an index on the columns, with
+	// NULLs first.
+	sort.Slice(values, func(i, j int) bool {
+		return *values[i].(*tree.DBytes) < *values[j].(*tree.DBytes)
+	})
+	var decoded []string
+	for _, v := range values {
+		key := []byte(*v.(*tree.DBytes))
+		var s string
+		for range typs {
+			var d tree.Datum
+			if d, key, err = keyside.Decode(&tree.DatumAlloc{}, types.Int, key, encoding.Ascending); err != nil {
+				t.Fatal(err)
+			}
+			s += "/" + d.String()
+		}
+		decoded = append(decoded, s)
+	}
+	expected := []string{"/-3/7", "/1/NULL", "/1/5", "/2/1"}
+	if !reflect.DeepEqual(expected, decoded) {
+		t.Errorf("expected keys %v, found %v", expected, decoded)
+	}
+}
+
 func TestTruncateDatum(t *testing.T) {
 	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
 	runTest := func(d, expected tree.Datum) {
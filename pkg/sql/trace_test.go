@@ -29,9 +29,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing/tracingpb"
 	"github.com/cockroachdb/logtags"
 	"github.com/stretchr/testify/require"
+	otelsdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestTrace(t *testing.T) {
@@ -668,3 +671,48 @@ func TestStatementThreshold(t *testing.T) {
 	r.Exec(t, "select 1")
 	// TODO(andrei): check the logs for traces somehow.
 }
+
+// TestStatementSpansExportedToOpenTelemetry verifies that the spans of a
+// statement, including the DistSender spans tagged with the redacted key spans
+// of their requests, are exported to the OpenTelemetry tracer, which sends them
+// to the collector configured through trace.opentelemetry.collector.
+func TestStatementSpansExportedToOpenTelemetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.dist_sender.trace_request_spans.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE t (k STRING PRIMARY KEY)`)
+	var tableID int
+	sqlDB.QueryRow(t, `SELECT 't'::regclass::oid`).Scan(&tableID)
+
+	sr := tracetest.NewSpanRecorder()
+	tr := s.TracerI().(*tracing.Tracer)
+	tr.SetOpenTelemetryTracer(otelsdk.NewTracerProvider(otelsdk.WithSpanProcessor(sr)).Tracer("test"))
+	defer tr.SetOpenTelemetryTracer(nil)
+
+	const stmt = `SELECT * FROM t WHERE k = 'secret'`
+	sqlDB.Exec(t, stmt)
+
+	var foundStmt, foundRequestSpans bool
+	for _, sp := range sr.Ended() {
+		for _, attr := range sp.Attributes() {
+			switch {
+			case sp.Name() == "sql query" && attr.Key == "statement":
+				foundStmt = foundStmt || attr.Value.AsString() == stmt
+			case sp.Name() == "dist sender send" && attr.Key == "request_spans":
+				v := attr.Value.AsString()
+				if !strings.Contains(v, fmt.Sprintf("/Table/%d/1/", tableID)) {
+					continue
+				}
+				require.NotContains(t, v, "secret")
+				foundRequestSpans = true
+			}
+		}
+	}
+	require.True(t, foundStmt, "statement span not exported")
+	require.True(t, foundRequestSpans, "request spans not exported")
+}
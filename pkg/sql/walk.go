@@ -383,6 +383,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&createExternalConectionNode{}):             "create external connection",
 	reflect.TypeOf(&createFunctionNode{}):                      "create function",
 	reflect.TypeOf(&createIndexNode{}):                         "create index",
+	reflect.TypeOf(&createPlanPinNode{}):                       "create plan pin",
 	reflect.TypeOf(&createSequenceNode{}):                      "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):                        "create schema",
 	reflect.TypeOf(&createStatsNode{}):                         "create statistics",
@@ -399,6 +400,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropExternalConnectionNode{}):              "drop external connection",
 	reflect.TypeOf(&dropFunctionNode{}):                        "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                           "drop index",
+	reflect.TypeOf(&dropPlanPinNode{}):                         "drop plan pin",
 	reflect.TypeOf(&dropSequenceNode{}):                        "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                          "drop schema",
 	reflect.TypeOf(&dropTableNode{}):                           "drop table",
@@ -440,6 +442,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&renderNode{}):                              "render",
 	reflect.TypeOf(&resetAllNode{}):                            "reset all",
 	reflect.TypeOf(&RevokeRoleNode{}):                          "revoke role",
+	reflect.TypeOf(&rotateEncryptionKeysNode{}):                "rotate encryption keys",
 	reflect.TypeOf(&rowCountNode{}):                            "count",
 	reflect.TypeOf(&rowSourceToPlanNode{}):                     "row source to plan node",
 	reflect.TypeOf(&saveTableNode{}):                           "save table",
@@ -856,6 +856,17 @@ type Engine interface {
 	// GetEnvStats retrieves stats about the engine's environment
 	// For RocksDB, this includes details of at-rest encryption.
 	GetEnvStats() (*EnvStats, error)
+	// RotateEncryptionKeys rotates the encryption-at-rest keys of the engine:
+	// the store keys are reloaded from the key files, and a new data key is made
+	// active. Returns ErrEncryptionNotEnabled if encryption-at-rest is not
+	// enabled.
+	RotateEncryptionKeys(ctx context.Context) error
+	// RewriteEncryptedFiles compacts up to maxFiles sstables which are not
+	// encrypted with the active data key, so that their contents get encrypted
+	// with it. It returns the number of those sstables which were rewritten, and
+	// the number of sstables which remain encrypted with previous data keys.
+	// Returns ErrEncryptionNotEnabled if encryption-at-rest is not enabled.
+	RewriteEncryptedFiles(ctx context.Context, maxFiles int) (rewritten, remaining int, _ error)
 	// GetAuxiliaryDir returns a path under which files can be stored
 	// persistently, and from which data can be ingested by the engine.
 	//
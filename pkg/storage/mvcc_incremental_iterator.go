@@ -157,6 +157,11 @@ type MVCCIncrementalIterOptions struct {
 	StartTime hlc.Timestamp
 	EndTime   hlc.Timestamp
 
+	// DisableTimeBoundIteratorOptimization disables the time-bound iterator
+	// optimization, even if StartTime is set. This is useful to verify the
+	// results of an iteration that uses a time-bound iterator.
+	DisableTimeBoundIteratorOptimization bool
+
 	// RangeKeyMaskingBelow will mask points keys covered by MVCC range tombstones
 	// below the given timestamp. For more details, see IterOptions.
 	//
@@ -185,6 +190,9 @@ func NewMVCCIncrementalIterator(
 	if util.IsMetamorphicBuild() { // NB: always randomize when metamorphic
 		useTBI = mvccIncrementalIteratorMetamorphicTBI
 	}
+	if opts.DisableTimeBoundIteratorOptimization {
+		useTBI = false
+	}
 
 	var iter MVCCIterator
 	var timeBoundIter MVCCIterator
@@ -643,6 +643,15 @@ type EncryptionStatsHandler interface {
 	GetKeyIDFromSettings(settings []byte) (string, error)
 }
 
+// EncryptionKeyRotator rotates the encryption-at-rest keys of a store while
+// it is running.
+type EncryptionKeyRotator interface {
+	// RotateKeys reloads the store keys from the key files and makes a new data
+	// key active. Files written from then on are encrypted with the new data
+	// key, existing files are not affected.
+	RotateKeys(ctx context.Context) error
+}
+
 // Pebble is a wrapper around a Pebble database instance.
 type Pebble struct {
 	atomic struct {
@@ -719,6 +728,8 @@ type EncryptionEnv struct {
 	FS vfs.FS
 	// StatsHandler exposes encryption-at-rest state for observability.
 	StatsHandler EncryptionStatsHandler
+	// KeyRotator rotates the encryption-at-rest keys on demand.
+	KeyRotator EncryptionKeyRotator
 }
 
 var _ Engine = &Pebble{}
@@ -1634,6 +1645,104 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 	return stats, nil
 }
 
+// ErrEncryptionNotEnabled is returned when rotating the encryption-at-rest keys
+// of an engine that is not encrypted.
+var ErrEncryptionNotEnabled = errors.New("encryption-at-rest is not enabled on this store")
+
+// RotateEncryptionKeys implements the Engine interface.
+func (p *Pebble) RotateEncryptionKeys(ctx context.Context) error {
+	if p.encryption == nil || p.encryption.KeyRotator == nil {
+		return ErrEncryptionNotEnabled
+	}
+	if err := p.encryption.KeyRotator.RotateKeys(ctx); err != nil {
+		return err
+	}
+	// Flush the memtable, which switches to a new WAL encrypted with the new
+	// data key.
+	return p.Flush()
+}
+
+// RewriteEncryptedFiles implements the Engine interface.
+//
+// The sstables are rewritten by manual compactions of their key spans, so that
+// Pebble remains in charge of the lifecycle of its files. A manual compaction
+// does not rewrite an sstable that it can move to the next level as is, nor an
+// sstable of the bottommost level without overlapping data in the levels
+// above. Such sstables stay encrypted with their data key until a later
+// compaction rewrites them, and are counted as remaining.
+func (p *Pebble) RewriteEncryptedFiles(
+	ctx context.Context, maxFiles int,
+) (rewritten, remaining int, _ error) {
+	if p.encryption == nil {
+		return 0, 0, ErrEncryptionNotEnabled
+	}
+	ssts, err := p.sstablesWithInactiveKeys()
+	if err != nil {
+		return 0, 0, err
+	}
+	compacted := make(map[pebble.FileNum]struct{})
+	for i := 0; i < len(ssts) && i < maxFiles; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+		start := ssts[i].Smallest.UserKey
+		largest, ok := DecodeEngineKey(ssts[i].Largest.UserKey)
+		if !ok {
+			return 0, 0, errors.AssertionFailedf(
+				"invalid largest key %x of sstable %s", ssts[i].Largest.UserKey, ssts[i].FileNum)
+		}
+		// The end of the span must be greater than its start, which isn't the case
+		// of sstables containing the versions of a single key.
+		end := EngineKey{Key: largest.Key.Next()}.Encode()
+		if err := p.db.Compact(start, end, true /* parallelize */); err != nil {
+			return 0, 0, errors.Wrapf(err, "compacting sstable %s", ssts[i].FileNum)
+		}
+		compacted[ssts[i].FileNum] = struct{}{}
+	}
+	after, err := p.sstablesWithInactiveKeys()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, sst := range after {
+		delete(compacted, sst.FileNum)
+	}
+	return len(compacted), len(after), nil
+}
+
+// sstablesWithInactiveKeys returns the sstables which are not encrypted with
+// the active data key, including the sstables written in plaintext before
+// encryption-at-rest was enabled, which have no entry in the file registry.
+func (p *Pebble) sstablesWithInactiveKeys() ([]pebble.SSTableInfo, error) {
+	activeKeyID, err := p.encryption.StatsHandler.GetActiveDataKeyID()
+	if err != nil {
+		return nil, err
+	}
+	sstInfos, err := p.db.SSTables()
+	if err != nil {
+		return nil, err
+	}
+	var res []pebble.SSTableInfo
+	for _, ssts := range sstInfos {
+		for _, sst := range ssts {
+			keyID := "plain"
+			filename := p.fs.PathJoin(p.path, fmt.Sprintf("%s.sst", sst.FileNum))
+			if entry := p.fileRegistry.GetFileEntry(filename); entry != nil {
+				keyID, err = p.encryption.StatsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
+				if err != nil {
+					return nil, err
+				}
+				if len(keyID) == 0 {
+					keyID = "plain"
+				}
+			}
+			if keyID != activeKeyID {
+				res = append(res, sst)
+			}
+		}
+	}
+	return res, nil
+}
+
 // GetAuxiliaryDir implements the Engine interface.
 func (p *Pebble) GetAuxiliaryDir() string {
 	return p.auxDir
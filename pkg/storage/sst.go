@@ -955,3 +955,107 @@ func UpdateSSTTimestamps(
 
 	return sstOut.Bytes(), nil
 }
+
+// RewriteSSTKeyPrefixes rewrites the keys in the provided SST, replacing the
+// OldPrefix of the first matching rewrite with its NewPrefix. Timestamps and
+// values are preserved. All point keys and range key bounds must match one of
+// the rewrites, and the rewrites must preserve the order of the keys.
+func RewriteSSTKeyPrefixes(
+	ctx context.Context, st *cluster.Settings, sst []byte, rewrites []roachpb.KeyPrefixRewrite,
+) ([]byte, error) {
+	if len(rewrites) == 0 {
+		return nil, errors.Errorf("no key prefix rewrites given")
+	}
+
+	sstOut := &MemFile{}
+	sstOut.Buffer.Grow(len(sst))
+	writer := MakeIngestionSSTWriter(ctx, st, sstOut)
+	defer writer.Close()
+
+	// Rewrite point keys.
+	iter, err := NewPebbleMemSSTIterator(sst, false /* verify */, IterOptions{
+		KeyTypes:   IterKeyTypePointsOnly,
+		LowerBound: keys.MinKey,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	for iter.SeekGE(MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		newKey, err := RewriteKeyPrefix(key.Key, rewrites, false /* isEndKey */)
+		if err != nil {
+			return nil, err
+		}
+		err = writer.PutRawMVCC(MVCCKey{Key: newKey, Timestamp: key.Timestamp}, iter.UnsafeValue())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Rewrite range keys.
+	iter, err = NewPebbleMemSSTIterator(sst, false /* verify */, IterOptions{
+		KeyTypes:   IterKeyTypeRangesOnly,
+		LowerBound: keys.MinKey,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	for iter.SeekGE(MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		rangeKeys := iter.RangeKeys()
+		startKey, err := RewriteKeyPrefix(rangeKeys.Bounds.Key, rewrites, false /* isEndKey */)
+		if err != nil {
+			return nil, err
+		}
+		endKey, err := RewriteKeyPrefix(rangeKeys.Bounds.EndKey, rewrites, true /* isEndKey */)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range rangeKeys.Versions {
+			rangeKey := MVCCRangeKey{StartKey: startKey, EndKey: endKey, Timestamp: v.Timestamp}
+			if err = writer.PutRawMVCCRangeKey(rangeKey, v.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = writer.Finish(); err != nil {
+		return nil, err
+	}
+
+	return sstOut.Bytes(), nil
+}
+
+// RewriteKeyPrefix returns a copy of the key with the prefix of the first
+// matching rewrite replaced. If isEndKey is true, the key is the exclusive end
+// key of a span, which may also be the PrefixEnd of a rewrite's OldPrefix.
+func RewriteKeyPrefix(
+	key roachpb.Key, rewrites []roachpb.KeyPrefixRewrite, isEndKey bool,
+) (roachpb.Key, error) {
+	for _, rw := range rewrites {
+		if bytes.HasPrefix(key, rw.OldPrefix) {
+			newKey := make(roachpb.Key, 0, len(rw.NewPrefix)+len(key)-len(rw.OldPrefix))
+			newKey = append(newKey, rw.NewPrefix...)
+			return append(newKey, key[len(rw.OldPrefix):]...), nil
+		}
+		if isEndKey && key.Equal(rw.OldPrefix.PrefixEnd()) {
+			return rw.NewPrefix.PrefixEnd(), nil
+		}
+	}
+	return nil, errors.Errorf("key %s does not match any key prefix rewrite", key)
+}
@@ -259,6 +259,7 @@ var charts = []sectionDescription{
 					"distsender.batches.partial",
 					"distsender.batches.async.sent",
 					"distsender.batches.async.throttled",
+					"distsender.batches.limited_scans.parallel",
 				},
 				AxisLabel: "Batches",
 			},
@@ -599,6 +600,12 @@ var charts = []sectionDescription{
 					"kv.rangefeed.catchup_scan_nanos",
 				},
 			},
+			{
+				Title: "Rangefeed Catchup Scan Verification Failures",
+				Metrics: []string{
+					"kv.rangefeed.catchup_scan_verification_failures",
+				},
+			},
 			{
 				Title: "Rangefeed Memory Allocations",
 				Metrics: []string{
@@ -646,6 +653,10 @@ var charts = []sectionDescription{
 					"range.snapshots.unknown.sent-bytes",
 				},
 			},
+			{
+				Title:   "Snapshots Paced Due To IO Overload",
+				Metrics: []string{"range.snapshots.send-io-overload-paced"},
+			},
 		},
 	},
 	{
@@ -829,6 +840,59 @@ var charts = []sectionDescription{
 				Title:   "Closed Timestamp",
 				Metrics: []string{"kv.closed_timestamp.max_behind_nanos"},
 			},
+			{
+				Title: "Side-Transport Failures to Close",
+				Metrics: []string{
+					"kv.closed_timestamp.side_transport.failures_to_close.unknown",
+					"kv.closed_timestamp.side_transport.failures_to_close.replica_destroyed",
+					"kv.closed_timestamp.side_transport.failures_to_close.invalid_lease",
+					"kv.closed_timestamp.side_transport.failures_to_close.target_over_lease_expiration",
+					"kv.closed_timestamp.side_transport.failures_to_close.merge_in_progress",
+					"kv.closed_timestamp.side_transport.failures_to_close.proposals_in_flight",
+					"kv.closed_timestamp.side_transport.failures_to_close.requests_evaluating_below_target",
+				},
+			},
+			{
+				Title: "Side-Transport Ranges Not Closing",
+				Metrics: []string{
+					"kv.closed_timestamp.side_transport.ranges_not_closing.unknown",
+					"kv.closed_timestamp.side_transport.ranges_not_closing.replica_destroyed",
+					"kv.closed_timestamp.side_transport.ranges_not_closing.invalid_lease",
+					"kv.closed_timestamp.side_transport.ranges_not_closing.target_over_lease_expiration",
+					"kv.closed_timestamp.side_transport.ranges_not_closing.merge_in_progress",
+					"kv.closed_timestamp.side_transport.ranges_not_closing.proposals_in_flight",
+					"kv.closed_timestamp.side_transport.ranges_not_closing.requests_evaluating_below_target",
+				},
+			},
+			{
+				Title:   "Side-Transport Max Stream Lag",
+				Metrics: []string{"kv.closed_timestamp.side_transport.max_stream_lag"},
+			},
+			{
+				Title:   "Side-Transport Lagging Streams",
+				Metrics: []string{"kv.closed_timestamp.side_transport.lagging_streams"},
+			},
+			{
+				Title: "Side-Transport Max Closed Timestamp Lag",
+				Metrics: []string{
+					"kv.closed_timestamp.side_transport.max_closed_timestamp_lag.lag_by_cluster_setting",
+					"kv.closed_timestamp.side_transport.max_closed_timestamp_lag.lead_for_global_reads",
+				},
+			},
+			{
+				Title: "Side-Transport Lag Alerting",
+				Metrics: []string{
+					"kv.closed_timestamp.side_transport.lag_alerting.lag_by_cluster_setting",
+					"kv.closed_timestamp.side_transport.lag_alerting.lead_for_global_reads",
+				},
+			},
+			{
+				Title: "Side-Transport Lag Alerts",
+				Metrics: []string{
+					"kv.closed_timestamp.side_transport.lag_alerts.lag_by_cluster_setting",
+					"kv.closed_timestamp.side_transport.lag_alerts.lead_for_global_reads",
+				},
+			},
 			{
 				Title:   "Count",
 				Metrics: []string{"follower_reads.success_count"},
@@ -1742,6 +1806,10 @@ var charts = []sectionDescription{
 				Title:   "Entries Truncated",
 				Metrics: []string{"raftlog.truncated"},
 			},
+			{
+				Title:   "Snapshots Caused By Truncation",
+				Metrics: []string{"raftlog.truncated.snapshots"},
+			},
 			{
 				Title:   "Followers Behind By...",
 				Metrics: []string{"raftlog.behind"},
@@ -2108,6 +2176,10 @@ var charts = []sectionDescription{
 				},
 				AxisLabel: "Schema Change Executions",
 			},
+			{
+				Title:   "Blocking Transactions Aborted",
+				Metrics: []string{"sql.schema_changer.blocking_txns_aborted"},
+			},
 		},
 	},
 	{
@@ -2630,6 +2702,14 @@ var charts = []sectionDescription{
 				},
 				AxisLabel: "Transactions",
 			},
+			{
+				Title: "Merged Scan Spans",
+				Metrics: []string{
+					"sql.scan.merged_spans.count",
+					"sql.scan.merged_spans.count.internal",
+				},
+				AxisLabel: "Spans",
+			},
 		},
 	},
 	{
@@ -2989,6 +3069,24 @@ var charts = []sectionDescription{
 				},
 				AxisLabel: "Bytes",
 			},
+			{
+				Title: "Disk Bandwidth",
+				Metrics: []string{
+					"storage.disk_bandwidth.foreground.bytes",
+					"storage.disk_bandwidth.compaction.bytes",
+					"storage.disk_bandwidth.snapshot.bytes",
+					"storage.disk_bandwidth.ingestion.bytes",
+				},
+				AxisLabel: "Bytes",
+			},
+			{
+				Title: "Disk Bandwidth Throttling",
+				Metrics: []string{
+					"storage.disk_bandwidth.snapshot.throttled",
+					"storage.disk_bandwidth.ingestion.throttled",
+				},
+				AxisLabel: "Duration (nanos)",
+			},
 			{
 				Title:   "Stalls",
 				Metrics: []string{"storage.write-stalls"},
@@ -3215,6 +3313,9 @@ var charts = []sectionDescription{
 					"jobs.auto_span_config_reconciliation.currently_running",
 					"jobs.auto_sql_stats_compaction.currently_running",
 					"jobs.stream_replication.currently_running",
+					"jobs.relocate_range.currently_running",
+					"jobs.materialized_view_refresh.currently_running",
+					"jobs.encryption_key_rotation.currently_running",
 				},
 			},
 			{
@@ -3226,9 +3327,12 @@ var charts = []sectionDescription{
 					"jobs.backup.currently_idle",
 					"jobs.changefeed.currently_idle",
 					"jobs.create_stats.currently_idle",
+					"jobs.encryption_key_rotation.currently_idle",
 					"jobs.import.currently_idle",
+					"jobs.materialized_view_refresh.currently_idle",
 					"jobs.migration.currently_idle",
 					"jobs.new_schema_change.currently_idle",
+					"jobs.relocate_range.currently_idle",
 					"jobs.restore.currently_idle",
 					"jobs.schema_change.currently_idle",
 					"jobs.schema_change_gc.currently_idle",
@@ -3411,6 +3515,39 @@ var charts = []sectionDescription{
 					"jobs.auto_sql_stats_compaction.resume_retry_error",
 				},
 			},
+			{
+				Title: "Relocate Range",
+				Metrics: []string{
+					"jobs.relocate_range.fail_or_cancel_completed",
+					"jobs.relocate_range.fail_or_cancel_failed",
+					"jobs.relocate_range.fail_or_cancel_retry_error",
+					"jobs.relocate_range.resume_completed",
+					"jobs.relocate_range.resume_failed",
+					"jobs.relocate_range.resume_retry_error",
+				},
+			},
+			{
+				Title: "Materialized View Refresh",
+				Metrics: []string{
+					"jobs.materialized_view_refresh.fail_or_cancel_completed",
+					"jobs.materialized_view_refresh.fail_or_cancel_failed",
+					"jobs.materialized_view_refresh.fail_or_cancel_retry_error",
+					"jobs.materialized_view_refresh.resume_completed",
+					"jobs.materialized_view_refresh.resume_failed",
+					"jobs.materialized_view_refresh.resume_retry_error",
+				},
+			},
+			{
+				Title: "Encryption Key Rotation",
+				Metrics: []string{
+					"jobs.encryption_key_rotation.fail_or_cancel_completed",
+					"jobs.encryption_key_rotation.fail_or_cancel_failed",
+					"jobs.encryption_key_rotation.fail_or_cancel_retry_error",
+					"jobs.encryption_key_rotation.resume_completed",
+					"jobs.encryption_key_rotation.resume_failed",
+					"jobs.encryption_key_rotation.resume_retry_error",
+				},
+			},
 		},
 	},
 	{
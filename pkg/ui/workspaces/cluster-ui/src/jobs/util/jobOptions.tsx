@@ -156,6 +156,15 @@ export const typeOptions = [
     value: JobType.ROW_LEVEL_TTL.toString(),
     name: "Time-to-live Deletions",
   },
+  { value: JobType.RELOCATE_RANGE.toString(), name: "Range Relocations" },
+  {
+    value: JobType.MATERIALIZED_VIEW_REFRESH.toString(),
+    name: "Materialized View Refreshes",
+  },
+  {
+    value: JobType.ENCRYPTION_KEY_ROTATION.toString(),
+    name: "Encryption Key Rotations",
+  },
 ];
 
 export const showOptions = [
@@ -366,6 +366,10 @@ export default function Debug() {
             name="Receiver on this node"
             url="debug/closedts-receiver"
           />
+          <DebugTableLink
+            name="Incoming streams on this node (JSON)"
+            url="debug/closedts-receiver/streams"
+          />
         </DebugTableRow>
       </DebugTable>
       <DebugTable
@@ -159,6 +159,12 @@ var upgrades = []upgrade.Upgrade{
 		NoPrecondition,
 		updateInvalidColumnIDsInSequenceBackReferences,
 	),
+	upgrade.NewTenantUpgrade(
+		"add the system.plan_pins table",
+		toCV(clusterversion.PlanPinsTable),
+		NoPrecondition,
+		systemPlanPinsTableMigration,
+	),
 }
 
 func init() {
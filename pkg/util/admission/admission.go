@@ -120,7 +120,7 @@
 //   return err
 // }
 // doWork()
-// if enabled { kvQueue.AdmittedWorkDone(tid) }
+// if enabled { kvQueue.AdmittedWorkDone(tid, databaseID) }
 
 // Additionally, each store has a single StoreWorkQueue and GrantCoordinator
 // for writes. See kvStoreTokenGranter and how its tokens are dynamically
@@ -0,0 +1,96 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// ElasticWorkMaxDuration bounds how long a paginated request admitted at an
+// elastic priority may run before it returns the results collected so far.
+var ElasticWorkMaxDuration = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"admission.elastic_work.max_duration",
+	"if positive, paginated KV requests with a priority below normal (such as the export "+
+		"requests issued by BACKUP) return a partial result with a resume span after running "+
+		"for this long, so that they are subject to admission control again when resumed",
+	100*time.Millisecond,
+	settings.NonNegativeDuration,
+)
+
+// ElasticWorkHandle is used to bound the duration of a unit of elastic work,
+// i.e. throughput-oriented work with a priority below admissionpb.NormalPri.
+// Such work is admitted once, but can hold on to resources for a long time,
+// which hurts the latency of foreground work. Work that can be broken up into
+// chunks and resumed (such as ExportRequest evaluation) checks OverLimit after
+// each chunk, and yields once the handle is over its limit. The resumed work is
+// then subject to admission control again.
+//
+// A nil handle is never over its limit.
+type ElasticWorkHandle struct {
+	allotted time.Duration
+	start    time.Time
+	ts       timeutil.TimeSource
+}
+
+// NewElasticWorkHandle returns a handle which is over its limit once the
+// allotted duration has passed.
+func NewElasticWorkHandle(allotted time.Duration, ts timeutil.TimeSource) *ElasticWorkHandle {
+	return &ElasticWorkHandle{allotted: allotted, start: ts.Now(), ts: ts}
+}
+
+// OverLimit returns true if the work has run for longer than allotted, along
+// with the duration it has run for.
+func (h *ElasticWorkHandle) OverLimit() (overLimit bool, elapsed time.Duration) {
+	if h == nil {
+		return false, 0
+	}
+	elapsed = h.ts.Since(h.start)
+	return elapsed > h.allotted, elapsed
+}
+
+type elasticWorkHandleKey struct{}
+
+// ContextWithElasticWorkHandle returns a context carrying the given handle.
+func ContextWithElasticWorkHandle(ctx context.Context, h *ElasticWorkHandle) context.Context {
+	return context.WithValue(ctx, elasticWorkHandleKey{}, h)
+}
+
+// ElasticWorkHandleFromContext returns the handle carried by the context, or
+// nil if there is none.
+func ElasticWorkHandleFromContext(ctx context.Context) *ElasticWorkHandle {
+	h, _ := ctx.Value(elasticWorkHandleKey{}).(*ElasticWorkHandle)
+	return h
+}
+
+// MaybeContextWithElasticWorkHandle returns a context carrying an
+// ElasticWorkHandle if the batch is elastic work that can be paginated, i.e.
+// it has a priority below admissionpb.NormalPri and sets TargetBytes.
+// Otherwise, the context is returned unchanged.
+func MaybeContextWithElasticWorkHandle(
+	ctx context.Context, st *cluster.Settings, ba *roachpb.BatchRequest,
+) context.Context {
+	if ba.TargetBytes <= 0 || admissionpb.WorkPriority(ba.AdmissionHeader.Priority) >= admissionpb.NormalPri {
+		return ctx
+	}
+	allotted := ElasticWorkMaxDuration.Get(&st.SV)
+	if allotted <= 0 {
+		return ctx
+	}
+	return ContextWithElasticWorkHandle(ctx, NewElasticWorkHandle(allotted, timeutil.DefaultTimeSource{}))
+}
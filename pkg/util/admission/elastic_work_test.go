@@ -0,0 +1,84 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission/admissionpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticWorkHandle(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// A nil handle is never over its limit.
+	var nilHandle *ElasticWorkHandle
+	overLimit, _ := nilHandle.OverLimit()
+	require.False(t, overLimit)
+
+	manual := timeutil.NewManualTime(timeutil.Unix(0, 123))
+	h := NewElasticWorkHandle(100*time.Millisecond, manual)
+	manual.Advance(100 * time.Millisecond)
+	overLimit, elapsed := h.OverLimit()
+	require.False(t, overLimit)
+	require.Equal(t, 100*time.Millisecond, elapsed)
+	manual.Advance(time.Millisecond)
+	overLimit, elapsed = h.OverLimit()
+	require.True(t, overLimit)
+	require.Equal(t, 101*time.Millisecond, elapsed)
+
+	ctx := context.Background()
+	require.Nil(t, ElasticWorkHandleFromContext(ctx))
+	require.Equal(t, h, ElasticWorkHandleFromContext(ContextWithElasticWorkHandle(ctx, h)))
+}
+
+func TestMaybeContextWithElasticWorkHandle(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	makeBatch := func(pri admissionpb.WorkPriority, targetBytes int64) *roachpb.BatchRequest {
+		ba := &roachpb.BatchRequest{}
+		ba.AdmissionHeader.Priority = int32(pri)
+		ba.TargetBytes = targetBytes
+		return ba
+	}
+
+	// Only paginated requests with a priority below normal get a handle.
+	for _, tc := range []struct {
+		pri         admissionpb.WorkPriority
+		targetBytes int64
+		expHandle   bool
+	}{
+		{pri: admissionpb.BulkNormalPri, targetBytes: 1, expHandle: true},
+		{pri: admissionpb.BulkNormalPri, targetBytes: 0, expHandle: false},
+		{pri: admissionpb.NormalPri, targetBytes: 1, expHandle: false},
+		{pri: admissionpb.HighPri, targetBytes: 1, expHandle: false},
+	} {
+		ctx := MaybeContextWithElasticWorkHandle(ctx, st, makeBatch(tc.pri, tc.targetBytes))
+		require.Equal(t, tc.expHandle, ElasticWorkHandleFromContext(ctx) != nil, "%+v", tc)
+	}
+
+	// The handle can be disabled.
+	ElasticWorkMaxDuration.Override(ctx, &st.SV, 0)
+	ctx = MaybeContextWithElasticWorkHandle(ctx, st, makeBatch(admissionpb.BulkNormalPri, 1))
+	require.Nil(t, ElasticWorkHandleFromContext(ctx))
+}
@@ -15,6 +15,8 @@ import (
 	"context"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -92,6 +94,59 @@ var KVStoresTenantWeightsEnabled = settings.RegisterBoolSetting(
 	"when true, tenant weights are enabled for KV-stores admission control",
 	false).WithPublic()
 
+// SQLDatabaseFairnessEnabled controls whether the work of the system tenant
+// in the KV and SQL response WorkQueues is ordered by the database that it
+// reads from. All the SQL work of the system tenant belongs to the same
+// tenant, so this prevents heavy scans of one database from starving the work
+// reading from other databases. This setting has no effect on work whose
+// database is not known.
+var SQLDatabaseFairnessEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"admission.sql.database_fairness.enabled",
+	"when true, KV and SQL response work is ordered across the databases that it reads from, "+
+		"adjusted by admission.sql.database_weights",
+	false).WithPublic()
+
+// SQLDatabaseWeights assigns weights to databases, for ordering work when
+// SQLDatabaseFairnessEnabled is true. Databases without a weight are assigned
+// the default weight of 1.
+var SQLDatabaseWeights = settings.RegisterValidatedStringSetting(
+	settings.TenantWritable,
+	"admission.sql.database_weights",
+	"comma-separated list of <database ID>=<weight> pairs, assigning weights to databases "+
+		"for ordering KV and SQL response work when admission.sql.database_fairness.enabled is true",
+	"",
+	func(_ *settings.Values, s string) error {
+		_, err := parseDatabaseWeights(s)
+		return err
+	}).WithPublic()
+
+// parseDatabaseWeights parses the value of SQLDatabaseWeights into a database
+// ID => weight map.
+func parseDatabaseWeights(s string) (map[uint32]uint32, error) {
+	weights := make(map[uint32]uint32)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.Split(pair, "=")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid database weight %q, expected <database ID>=<weight>", pair)
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 32)
+		if err != nil || id == 0 {
+			return nil, errors.Errorf("invalid database ID in database weight %q", pair)
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 32)
+		if err != nil || weight == 0 {
+			return nil, errors.Errorf("invalid weight in database weight %q", pair)
+		}
+		weights[uint32(id)] = uint32(weight)
+	}
+	return weights, nil
+}
+
 // EpochLIFOEnabled controls whether the adaptive epoch-LIFO scheme is enabled
 // for admission control. Is only relevant when the above admission control
 // settings are also set to true. Unlike those settings, which are granular
@@ -162,6 +217,14 @@ type WorkInfo struct {
 	// when KV work generates other KV work (to avoid deadlock). Ignored
 	// otherwise.
 	BypassAdmission bool
+	// DatabaseID is the ID of the database that the work reads from. If
+	// non-zero, and the WorkQueue orders work by database (see
+	// DatabaseFairnessEnabled), the work is ordered among the work of the other
+	// databases instead of among the work of the other tenants. It must only
+	// be set for work of the system tenant, and only when
+	// DatabaseFairnessEnabled returns true. For WorkQueues that use slots, the
+	// same DatabaseID must be passed to AdmittedWorkDone.
+	DatabaseID uint32
 
 	// Optional information specified only for WorkQueues where the work is tied
 	// to a range. This allows queued work to return early as soon as the range
@@ -183,8 +246,10 @@ type WorkInfo struct {
 // WorkQueue maintains a queue of work waiting to be admitted. Ordering of
 // work is achieved via 2 heaps: a tenant heap orders the tenants with waiting
 // work in increasing order of used slots or tokens, optionally adjusted by
-// tenant weights. Within each tenant, the waiting work is ordered based on
-// priority and create time. Tenants with non-zero values of used slots or
+// tenant weights. Work of the system tenant that specifies a database is
+// instead tracked by an entry for its database, which is ordered in the same
+// tenant heap, adjusted by database weights. Within each tenant (or
+// database), the waiting work is ordered based on priority and create time. Tenants with non-zero values of used slots or
 // tokens are tracked even if they have no more waiting work. Token usage is
 // reset to zero every second. The choice of 1 second of memory for token
 // distribution fairness is somewhat arbitrary. The same 1 second interval is
@@ -203,7 +268,7 @@ type WorkInfo struct {
 //  }
 //  <do the work>
 //  if enabled {
-//    kvQueue.AdmittedWorkDone(tid)
+//    kvQueue.AdmittedWorkDone(tid, 0 /* databaseID */)
 //  }
 type WorkQueue struct {
 	ambientCtx  context.Context
@@ -212,6 +277,9 @@ type WorkQueue struct {
 	usesTokens  bool
 	tiedToRange bool
 	settings    *cluster.Settings
+	// ordersByDatabase is true for the WorkQueues that can order work by
+	// database, see WorkInfo.DatabaseID.
+	ordersByDatabase bool
 
 	// Prevents more than one caller to be in Admit and calling tryGet or adding
 	// to the queue. It allows WorkQueue to release mu before calling tryGet and
@@ -223,8 +291,15 @@ type WorkQueue struct {
 		// Tenants with waiting work.
 		tenantHeap tenantHeap
 		// All tenants, including those without waiting work. Periodically cleaned.
-		tenants       map[uint64]*tenantInfo
-		tenantWeights struct {
+		tenants map[uint64]*tenantInfo
+		// All databases of the system tenant whose work is ordered by database,
+		// including those without waiting work. Periodically cleaned. These are
+		// kept separate from tenants since database IDs and tenant IDs overlap.
+		databases map[uint32]*tenantInfo
+		// databaseWeights are the weights of the databases, from the
+		// SQLDatabaseWeights setting.
+		databaseWeights map[uint32]uint32
+		tenantWeights   struct {
 			mu syncutil.Mutex
 			// active refers to the currently active weights. mu is held for updates
 			// to the inactive weights, to prevent concurrent updates. After
@@ -320,6 +395,10 @@ func initWorkQueue(
 	q.usesTokens = opts.usesTokens
 	q.tiedToRange = opts.tiedToRange
 	q.settings = settings
+	// The StoreWorkQueue also uses KVWork, but with tokens. Only the slot-based
+	// KVWork queue, which admits the KV CPU work, orders work by database.
+	q.ordersByDatabase = workKind == SQLKVResponseWork || workKind == SQLSQLResponseWork ||
+		(workKind == KVWork && !opts.usesTokens)
 	q.logThreshold = log.Every(5 * time.Minute)
 	q.metrics = metrics
 	q.stopCh = stopCh
@@ -329,8 +408,15 @@ func initWorkQueue(
 		q.mu.Lock()
 		defer q.mu.Unlock()
 		q.mu.tenants = make(map[uint64]*tenantInfo)
+		q.mu.databases = make(map[uint32]*tenantInfo)
 		q.sampleEpochLIFOSettingsLocked()
 	}()
+	if q.ordersByDatabase {
+		SQLDatabaseWeights.SetOnChange(&settings.SV, func(context.Context) {
+			q.setDatabaseWeights()
+		})
+		q.setDatabaseWeights()
+	}
 	go func() {
 		ticker := time.NewTicker(time.Second)
 		for {
@@ -349,6 +435,82 @@ func initWorkQueue(
 	}
 }
 
+// setDatabaseWeights sets the weights of the databases from the
+// SQLDatabaseWeights setting. The weights are capped like tenant weights, see
+// tenantWeightCap.
+func (q *WorkQueue) setDatabaseWeights() {
+	weights, err := parseDatabaseWeights(SQLDatabaseWeights.Get(&q.settings.SV))
+	if err != nil {
+		// The setting is validated, so this should not happen.
+		log.Warningf(q.ambientCtx, "ignoring invalid database weights: %v", err)
+		return
+	}
+	maxWeight := uint32(1)
+	for _, v := range weights {
+		if v > maxWeight {
+			maxWeight = v
+		}
+	}
+	if maxWeight > tenantWeightCap {
+		scaling := tenantWeightCap / float64(maxWeight)
+		for k, v := range weights {
+			weights[k] = uint32(math.Ceil(float64(v) * scaling))
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.mu.databaseWeights = weights
+	// There are few databases, so unlike SetTenantWeights, the existing ones
+	// are updated in one go.
+	for databaseID, database := range q.mu.databases {
+		weight := q.getDatabaseWeightLocked(databaseID)
+		if database.weight != weight {
+			database.weight = weight
+			if isInTenantHeap(database) {
+				q.mu.tenantHeap.fix(database)
+			}
+		}
+	}
+}
+
+func (q *WorkQueue) getDatabaseWeightLocked(databaseID uint32) uint32 {
+	weight, ok := q.mu.databaseWeights[databaseID]
+	if !ok {
+		weight = defaultTenantWeight
+	}
+	return weight
+}
+
+// DatabaseFairnessEnabled returns true if the WorkQueue orders the work that
+// specifies a WorkInfo.DatabaseID by database, see
+// SQLDatabaseFairnessEnabled.
+func (q *WorkQueue) DatabaseFairnessEnabled() bool {
+	return q.ordersByDatabase && SQLDatabaseFairnessEnabled.Get(&q.settings.SV)
+}
+
+// getTenantInfoLocked returns the tenantInfo tracking the work of the given
+// tenant, or of the given database if databaseID is non-zero. If create is
+// true, the tenantInfo is created if it does not exist, else nil is returned.
+func (q *WorkQueue) getTenantInfoLocked(
+	tenantID uint64, databaseID uint32, create bool,
+) *tenantInfo {
+	if databaseID != 0 {
+		database, ok := q.mu.databases[databaseID]
+		if !ok && create {
+			database = newTenantInfo(tenantID, q.getDatabaseWeightLocked(databaseID))
+			database.databaseID = databaseID
+			q.mu.databases[databaseID] = database
+		}
+		return database
+	}
+	tenant, ok := q.mu.tenants[tenantID]
+	if !ok && create {
+		tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
+		q.mu.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
 func isInTenantHeap(tenant *tenantInfo) bool {
 	// If there is some waiting work, this tenant is in tenantHeap.
 	return len(tenant.waitingWorkHeap) > 0 || len(tenant.openEpochsHeap) > 0
@@ -439,40 +601,55 @@ func (q *WorkQueue) tryCloseEpoch(timeNow time.Time) {
 	q.mu.closedEpochThreshold = epoch
 	doLog := q.logThreshold.ShouldLog()
 	for _, tenant := range q.mu.tenants {
-		prevThreshold := tenant.fifoPriorityThreshold
-		tenant.fifoPriorityThreshold =
-			tenant.priorityStates.getFIFOPriorityThresholdAndReset(
-				tenant.fifoPriorityThreshold, q.mu.epochLengthNanos, q.mu.maxQueueDelayToSwitchToLifo)
-		if !epochLIFOEnabled {
-			tenant.fifoPriorityThreshold = int(admissionpb.LowPri)
+		q.closeEpochForTenantLocked(tenant, epoch, epochLIFOEnabled, doLog)
+	}
+	for _, database := range q.mu.databases {
+		q.closeEpochForTenantLocked(database, epoch, epochLIFOEnabled, doLog)
+	}
+}
+
+func (q *WorkQueue) closeEpochForTenantLocked(
+	tenant *tenantInfo, epoch int64, epochLIFOEnabled bool, doLog bool,
+) {
+	prevThreshold := tenant.fifoPriorityThreshold
+	tenant.fifoPriorityThreshold =
+		tenant.priorityStates.getFIFOPriorityThresholdAndReset(
+			tenant.fifoPriorityThreshold, q.mu.epochLengthNanos, q.mu.maxQueueDelayToSwitchToLifo)
+	if !epochLIFOEnabled {
+		tenant.fifoPriorityThreshold = int(admissionpb.LowPri)
+	}
+	if tenant.fifoPriorityThreshold != prevThreshold || doLog {
+		logVerb := "is"
+		if tenant.fifoPriorityThreshold != prevThreshold {
+			logVerb = "changed to"
 		}
-		if tenant.fifoPriorityThreshold != prevThreshold || doLog {
-			logVerb := "is"
-			if tenant.fifoPriorityThreshold != prevThreshold {
-				logVerb = "changed to"
-			}
-			// TODO(sumeer): export this as a per-tenant metric somehow. We could
-			// start with this being a per-WorkQueue metric for only the system
-			// tenant. However, currently we share metrics across WorkQueues --
-			// specifically all the store WorkQueues share the same metric. We
-			// should eliminate that sharing and make those per store metrics.
+		// TODO(sumeer): export this as a per-tenant metric somehow. We could
+		// start with this being a per-WorkQueue metric for only the system
+		// tenant. However, currently we share metrics across WorkQueues --
+		// specifically all the store WorkQueues share the same metric. We
+		// should eliminate that sharing and make those per store metrics.
+		if tenant.databaseID != 0 {
+			log.Infof(q.ambientCtx, "%s: FIFO threshold for tenant %d database %d %s %d",
+				string(workKindString(q.workKind)), tenant.id, tenant.databaseID, logVerb,
+				tenant.fifoPriorityThreshold)
+		} else {
 			log.Infof(q.ambientCtx, "%s: FIFO threshold for tenant %d %s %d",
 				string(workKindString(q.workKind)), tenant.id, logVerb, tenant.fifoPriorityThreshold)
 		}
-		// Note that we are ignoring the new priority threshold and only
-		// dequeueing the ones that are in the closed epoch. It is possible to
-		// have work items that are not in the closed epoch and whose priority
-		// makes them no longer subject to LIFO, but they will need to wait here
-		// until their epochs close. This is considered acceptable since the
-		// priority threshold should not fluctuate rapidly.
-		for len(tenant.openEpochsHeap) > 0 {
-			work := tenant.openEpochsHeap[0]
-			if work.epoch > epoch {
-				break
-			}
-			heap.Pop(&tenant.openEpochsHeap)
-			heap.Push(&tenant.waitingWorkHeap, work)
+	}
+	// Note that we are ignoring the new priority threshold and only
+	// dequeueing the ones that are in the closed epoch. It is possible to
+	// have work items that are not in the closed epoch and whose priority
+	// makes them no longer subject to LIFO, but they will need to wait here
+	// until their epochs close. This is considered acceptable since the
+	// priority threshold should not fluctuate rapidly.
+	for len(tenant.openEpochsHeap) > 0 {
+		work := tenant.openEpochsHeap[0]
+		if work.epoch > epoch {
+			break
 		}
+		heap.Pop(&tenant.openEpochsHeap)
+		heap.Push(&tenant.waitingWorkHeap, work)
 	}
 }
 
@@ -496,6 +673,10 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	}
 	q.metrics.Requested.Inc(1)
 	tenantID := info.TenantID.ToUint64()
+	var databaseID uint32
+	if q.ordersByDatabase {
+		databaseID = info.DatabaseID
+	}
 
 	// The code in this method does not use defer to unlock the mutexes because
 	// it needs the flexibility of selectively unlocking one of these on a
@@ -503,11 +684,7 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 	// mutexes are properly unlocked on all code paths.
 	q.admitMu.Lock()
 	q.mu.Lock()
-	tenant, ok := q.mu.tenants[tenantID]
-	if !ok {
-		tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
-		q.mu.tenants[tenantID] = tenant
-	}
+	tenant := q.getTenantInfoLocked(tenantID, databaseID, true /* create */)
 	if info.BypassAdmission && roachpb.IsSystemTenantID(tenantID) && q.workKind == KVWork {
 		tenant.used += uint64(info.requestedCount)
 		if isInTenantHeap(tenant) {
@@ -556,9 +733,9 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 		prevTenant := tenant
 		// The tenant could have been removed when using tokens. See the comment
 		// where the tenantInfo struct is declared.
-		tenant, ok = q.mu.tenants[tenantID]
+		tenant = q.getTenantInfoLocked(tenantID, databaseID, false /* create */)
 		if !q.usesTokens {
-			if !ok || prevTenant != tenant {
+			if tenant == nil || prevTenant != tenant {
 				panic("prev tenantInfo no longer in map")
 			}
 			if tenant.used < uint64(info.requestedCount) {
@@ -567,9 +744,8 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 			}
 			tenant.used -= uint64(info.requestedCount)
 		} else {
-			if !ok {
-				tenant = newTenantInfo(tenantID, q.getTenantWeightLocked(tenantID))
-				q.mu.tenants[tenantID] = tenant
+			if tenant == nil {
+				tenant = q.getTenantInfoLocked(tenantID, databaseID, true /* create */)
 			}
 			// Don't want to overflow tenant.used if it is already 0 because of
 			// being reset to 0 by the GC goroutine.
@@ -686,15 +862,19 @@ func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err
 // AdmittedWorkDone is used to inform the WorkQueue that some admitted work is
 // finished. It must be called iff the WorkKind of this WorkQueue uses slots
 // (not tokens), i.e., KVWork, SQLStatementLeafStartWork,
-// SQLStatementRootStartWork.
-func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID) {
+// SQLStatementRootStartWork. The databaseID must be the WorkInfo.DatabaseID
+// that the work was admitted with.
+func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID, databaseID uint32) {
 	if q.usesTokens {
 		panic(errors.AssertionFailedf("tokens should not be returned"))
 	}
+	if !q.ordersByDatabase {
+		databaseID = 0
+	}
 	// Single slot is allocated for the work.
 	q.mu.Lock()
-	tenant, ok := q.mu.tenants[tenantID.ToUint64()]
-	if !ok {
+	tenant := q.getTenantInfoLocked(tenantID.ToUint64(), databaseID, false /* create */)
+	if tenant == nil {
 		panic(errors.AssertionFailedf("tenant not found"))
 	}
 	tenant.used--
@@ -751,17 +931,81 @@ func (q *WorkQueue) gcTenantsAndResetTokens() {
 	// longer than desired. We could break this iteration into smaller parts if
 	// needed.
 	for id, info := range q.mu.tenants {
-		if info.used == 0 && !isInTenantHeap(info) {
+		if q.gcTenantOrResetTokensLocked(info) {
 			delete(q.mu.tenants, id)
 			releaseTenantInfo(info)
-		} else if q.usesTokens {
-			info.used = 0
-			// All the heap members will reset used=0, so no need to change heap
-			// ordering.
+		}
+	}
+	for id, info := range q.mu.databases {
+		if q.gcTenantOrResetTokensLocked(info) {
+			delete(q.mu.databases, id)
+			releaseTenantInfo(info)
 		}
 	}
 }
 
+// gcTenantOrResetTokensLocked returns true if the tenantInfo can be garbage
+// collected, else resets its used tokens if the WorkQueue uses tokens.
+func (q *WorkQueue) gcTenantOrResetTokensLocked(info *tenantInfo) (gc bool) {
+	if info.used == 0 && !isInTenantHeap(info) {
+		return true
+	}
+	if q.usesTokens {
+		info.used = 0
+		// All the heap members will reset used=0, so no need to change heap
+		// ordering.
+	}
+	return false
+}
+
+// TenantUsage describes the usage of a WorkQueue by a tenant, or by a database
+// of the system tenant if the WorkQueue orders its work by database (see
+// SQLDatabaseFairnessEnabled).
+type TenantUsage struct {
+	// TenantID is the ID of the tenant.
+	TenantID uint64
+	// DatabaseID is the ID of the database, or zero if this is the usage of
+	// the work of the tenant that is not ordered by database.
+	DatabaseID uint32
+	// Weight is the weight assigned to the tenant or database.
+	Weight uint32
+	// Used is the number of slots currently used, or the number of tokens
+	// granted within the last interval if the WorkQueue uses tokens.
+	Used uint64
+	// Waiting is the number of requests waiting for admission.
+	Waiting int
+}
+
+// TenantUsages returns the usage of the WorkQueue by the tenants and the
+// databases that it tracks, ordered by tenant ID and then database ID.
+func (q *WorkQueue) TenantUsages() []TenantUsage {
+	q.mu.Lock()
+	usages := make([]TenantUsage, 0, len(q.mu.tenants)+len(q.mu.databases))
+	appendUsage := func(tenant *tenantInfo) {
+		usages = append(usages, TenantUsage{
+			TenantID:   tenant.id,
+			DatabaseID: tenant.databaseID,
+			Weight:     tenant.weight,
+			Used:       tenant.used,
+			Waiting:    len(tenant.waitingWorkHeap) + len(tenant.openEpochsHeap),
+		})
+	}
+	for _, tenant := range q.mu.tenants {
+		appendUsage(tenant)
+	}
+	for _, database := range q.mu.databases {
+		appendUsage(database)
+	}
+	q.mu.Unlock()
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].TenantID != usages[j].TenantID {
+			return usages[i].TenantID < usages[j].TenantID
+		}
+		return usages[i].DatabaseID < usages[j].DatabaseID
+	})
+	return usages
+}
+
 // adjustTenantTokens is used internally by StoreWorkQueue. The
 // additionalTokens count can be negative, in which case it is returning
 // tokens. This is only for WorkQueue's own accounting -- it should not call
@@ -797,15 +1041,33 @@ func (q *WorkQueue) SafeFormat(s redact.SafePrinter, verb rune) {
 	s.Printf("tenantHeap len: %d", len(q.mu.tenantHeap))
 	if len(q.mu.tenantHeap) > 0 {
 		s.Printf(" top tenant: %d", q.mu.tenantHeap[0].id)
+		if q.mu.tenantHeap[0].databaseID != 0 {
+			s.Printf(" database: %d", q.mu.tenantHeap[0].databaseID)
+		}
 	}
 	var ids []uint64
 	for id := range q.mu.tenants {
 		ids = append(ids, id)
 	}
 	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	tenants := make([]*tenantInfo, 0, len(ids)+len(q.mu.databases))
 	for _, id := range ids {
-		tenant := q.mu.tenants[id]
-		s.Printf("\n tenant-id: %d used: %d, w: %d, fifo: %d", tenant.id, tenant.used,
+		tenants = append(tenants, q.mu.tenants[id])
+	}
+	var databaseIDs []uint32
+	for id := range q.mu.databases {
+		databaseIDs = append(databaseIDs, id)
+	}
+	sort.Slice(databaseIDs, func(i, j int) bool { return databaseIDs[i] < databaseIDs[j] })
+	for _, id := range databaseIDs {
+		tenants = append(tenants, q.mu.databases[id])
+	}
+	for _, tenant := range tenants {
+		s.Printf("\n tenant-id: %d", tenant.id)
+		if tenant.databaseID != 0 {
+			s.Printf(" database-id: %d", tenant.databaseID)
+		}
+		s.Printf(" used: %d, w: %d, fifo: %d", tenant.used,
 			tenant.weight, tenant.fifoPriorityThreshold)
 		if len(tenant.waitingWorkHeap) > 0 {
 			s.Printf(" waiting work heap:")
@@ -1081,9 +1343,14 @@ func (ps *priorityStates) getFIFOPriorityThresholdAndReset(
 	return priority
 }
 
-// tenantInfo is the per-tenant information in the tenantHeap.
+// tenantInfo is the per-tenant information in the tenantHeap. It is also
+// used for the per-database information of the work that is ordered by
+// database, see WorkInfo.DatabaseID.
 type tenantInfo struct {
 	id uint64
+	// databaseID is non-zero iff this tracks the work of a database of the
+	// tenant.
+	databaseID uint32
 	// The weight assigned to the tenant. Must be > 0.
 	weight uint32
 	// used can be the currently used slots, or the tokens granted within the last
@@ -101,9 +101,10 @@ func (tg *testGranter) storeWriteDone(
 }
 
 type testWork struct {
-	tenantID roachpb.TenantID
-	cancel   context.CancelFunc
-	admitted bool
+	tenantID   roachpb.TenantID
+	databaseID uint32
+	cancel     context.CancelFunc
+	admitted   bool
 	// For StoreWorkQueue testing.
 	handle StoreWorkHandle
 }
@@ -207,13 +208,18 @@ func TestWorkQueueBasic(t *testing.T) {
 				d.ScanArgs(t, "create-time-millis", &createTime)
 				var bypass bool
 				d.ScanArgs(t, "bypass", &bypass)
+				var databaseID int
+				if d.HasArg("database") {
+					d.ScanArgs(t, "database", &databaseID)
+				}
 				ctx, cancel := context.WithCancel(context.Background())
-				wrkMap.set(id, &testWork{tenantID: tenant, cancel: cancel})
+				wrkMap.set(id, &testWork{tenantID: tenant, databaseID: uint32(databaseID), cancel: cancel})
 				workInfo := WorkInfo{
 					TenantID:        tenant,
 					Priority:        admissionpb.WorkPriority(priority),
 					CreateTime:      int64(createTime) * int64(time.Millisecond),
 					BypassAdmission: bypass,
+					DatabaseID:      uint32(databaseID),
 				}
 				go func(ctx context.Context, info WorkInfo, id int) {
 					enabled, err := q.Admit(ctx, info)
@@ -269,7 +275,7 @@ func TestWorkQueueBasic(t *testing.T) {
 				if !work.admitted {
 					return fmt.Sprintf("id not admitted: %d\n", id)
 				}
-				q.AdmittedWorkDone(work.tenantID)
+				q.AdmittedWorkDone(work.tenantID, work.databaseID)
 				wrkMap.delete(id)
 				return buf.stringAndReset()
 
@@ -293,6 +299,13 @@ func TestWorkQueueBasic(t *testing.T) {
 				q.SetTenantWeights(weightMap)
 				return q.String()
 
+			case "set-database-weights":
+				var weights string
+				d.ScanArgs(t, "weights", &weights)
+				SQLDatabaseWeights.Override(context.Background(), &st.SV,
+					strings.ReplaceAll(weights, ":", "="))
+				return q.String()
+
 			case "print":
 				return q.String()
 
@@ -396,6 +409,24 @@ func TestWorkQueueTokenResetRace(t *testing.T) {
 	mu.Unlock()
 }
 
+func TestParseDatabaseWeights(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	weights, err := parseDatabaseWeights("")
+	require.NoError(t, err)
+	require.Empty(t, weights)
+
+	weights, err = parseDatabaseWeights("104=5, 106 = 2,")
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]uint32{104: 5, 106: 2}, weights)
+
+	for _, s := range []string{"104", "104=", "=5", "0=5", "104=0", "a=5", "104=5=6"} {
+		_, err := parseDatabaseWeights(s)
+		require.Error(t, err, s)
+	}
+}
+
 func TestPriorityStates(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -13,6 +13,7 @@ package metric
 import (
 	"io"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/gogo/protobuf/proto"
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,6 +21,15 @@ import (
 	"github.com/prometheus/common/expfmt"
 )
 
+// ChildMetricsEnabled controls whether child metrics, such as the metrics of
+// individual changefeeds with a metrics label, are exported. It is defined
+// here rather than next to the exporting code so that the packages creating
+// child metrics can check it without depending on the server.
+var ChildMetricsEnabled = settings.RegisterBoolSetting(
+	settings.TenantWritable, "server.child_metrics.enabled",
+	"enables the exporting of child metrics, additional prometheus time series with extra labels",
+	false).WithPublic()
+
 // PrometheusExporter contains a map of metric families (a metric with multiple labels).
 // It initializes each metric family once and reuses it for each prometheus scrape.
 // Using ScrapeAndPrintAsText is a thread safe way to scrape and export the
@@ -89,6 +89,13 @@ func (tb *TokenBucket) Adjust(delta Tokens) {
 	}
 }
 
+// Available returns the current amount of tokens, which is negative if the
+// bucket is in debt.
+func (tb *TokenBucket) Available() Tokens {
+	tb.update()
+	return tb.current
+}
+
 // TryToFulfill either removes the given amount if is available, or returns a
 // time after which the request should be retried.
 func (tb *TokenBucket) TryToFulfill(amount Tokens) (fulfilled bool, tryAgainAfter time.Duration) {
@@ -31,6 +31,9 @@ func TestTokenBucket(t *testing.T) {
 		if delta := tb.current - expected; delta > eps || delta < -eps {
 			t.Fatalf("expected current amount %v, got %v", expected, tb.current)
 		}
+		if available := tb.Available(); available != tb.current {
+			t.Fatalf("expected available amount %v, got %v", tb.current, available)
+		}
 	}
 
 	checkFulfill := func(amount Tokens, expected time.Duration) {